@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/Bigsy/mcpmu/internal/config"
@@ -20,6 +23,7 @@ var (
 	addOAuthCallbackPort int
 	addStartupTimeout    int
 	addToolTimeout       int
+	addStdin             bool
 )
 
 var addCmd = &cobra.Command{
@@ -43,7 +47,10 @@ Examples:
   mcpmu add atlassian https://mcp.atlassian.com/mcp --scopes read,write
 
   # HTTP server with pre-registered OAuth client
-  mcpmu add slack https://mcp.slack.com/mcp --oauth-client-id 1601185624273.8899143856786 --oauth-callback-port 3118`,
+  mcpmu add slack https://mcp.slack.com/mcp --oauth-client-id 1601185624273.8899143856786 --oauth-callback-port 3118
+
+  # Any server, from a JSON server config on stdin
+  echo '{"url":"https://example.com/mcp","http_headers":{"X-Team":"infra"}}' | mcpmu add api --stdin`,
 	RunE: runAdd,
 }
 
@@ -59,11 +66,16 @@ func init() {
 	addCmd.Flags().IntVar(&addOAuthCallbackPort, "oauth-callback-port", 0, "OAuth callback port (1-65535)")
 	addCmd.Flags().IntVar(&addStartupTimeout, "startup-timeout", 0, "Startup timeout in seconds (default: 10)")
 	addCmd.Flags().IntVar(&addToolTimeout, "tool-timeout", 0, "Tool call timeout in seconds (default: 60)")
+	addCmd.Flags().BoolVar(&addStdin, "stdin", false, "Read the server config as a JSON object from stdin")
 
 	rootCmd.AddCommand(addCmd)
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
+	if addStdin {
+		return runAddStdin(cmd, args)
+	}
+
 	// Check if this is an HTTP server:
 	// 1. --url flag provided, or
 	// 2. Second positional arg looks like a URL
@@ -227,6 +239,70 @@ func runAddHTTP(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runAddStdin adds a server whose whole config is a JSON object on stdin, in
+// the same form as an entry under "servers" in the config file. It is
+// validated like any other new server.
+func runAddStdin(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("--stdin takes only a server name\n\nUsage: mcpmu add <name> --stdin < server.json")
+	}
+	name := args[0]
+
+	var other []string
+	for _, flag := range []string{"env", "cwd", "autostart", "url", "bearer-env", "scopes", "oauth-client-id", "oauth-callback-port", "startup-timeout", "tool-timeout"} {
+		if cmd.Flags().Changed(flag) {
+			other = append(other, "--"+flag)
+		}
+	}
+	if len(other) > 0 {
+		return fmt.Errorf("--stdin cannot be combined with %s; put those settings in the JSON", strings.Join(other, ", "))
+	}
+
+	data, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+	srv, err := parseServerJSON(data)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(addConfigPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddServer(name, srv); err != nil {
+		return err
+	}
+	if err := saveConfig(cfg, addConfigPath); err != nil {
+		return err
+	}
+
+	if srv.IsHTTP() {
+		fmt.Printf("Added HTTP server %q (%s)\n", name, srv.URL)
+	} else {
+		fmt.Printf("Added server %q\n", name)
+	}
+	return nil
+}
+
+// parseServerJSON decodes a single server config object, rejecting empty
+// input and anything after the object.
+func parseServerJSON(data []byte) (config.ServerConfig, error) {
+	var srv config.ServerConfig
+	if len(bytes.TrimSpace(data)) == 0 {
+		return srv, fmt.Errorf("no server config on stdin; expected a JSON object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&srv); err != nil {
+		return srv, fmt.Errorf("invalid server config JSON: %w", err)
+	}
+	if dec.More() {
+		return srv, fmt.Errorf("invalid server config JSON: expected a single object")
+	}
+	return srv, nil
+}
+
 // parseEnvFlags parses KEY=VALUE pairs from --env flags.
 func parseEnvFlags(flags []string) (map[string]string, error) {
 	if len(flags) == 0 {
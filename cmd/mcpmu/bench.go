@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchConfigPath string
+	benchTool       string
+	benchArgs       string
+	benchIterations int
+	benchJSON       bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench <server>",
+	Short: "Measure a server's startup, tool discovery and call latency",
+	Long: `Start a configured server and measure how long it takes to initialize
+and to list its tools, then time N round trips and print min/avg/p95.
+
+With --tool, each round trip calls that tool with --args (default {}), so
+pick one that does no real work. Without it, each round trip is a
+tools/list. A tool call that returns isError still counts; a failed round
+trip aborts the benchmark.
+
+The server is stopped afterwards. It runs as a separate copy, so a serve or
+TUI instance using the same config is not affected.
+
+Examples:
+  mcpmu bench filesystem
+  mcpmu bench filesystem --tool list_allowed_directories -n 50
+  mcpmu bench github --tool get_me --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().StringVarP(&benchConfigPath, "config", "c", "", "Path to config file")
+	benchCmd.Flags().StringVar(&benchTool, "tool", "", "Tool to call on each iteration (default: time tools/list instead)")
+	benchCmd.Flags().StringVar(&benchArgs, "args", "{}", "Tool arguments as a JSON object")
+	benchCmd.Flags().IntVarP(&benchIterations, "iterations", "n", 10, "Number of round trips to time")
+	benchCmd.Flags().BoolVar(&benchJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchResult is the output of mcpmu bench. Durations are milliseconds.
+type benchResult struct {
+	Server      string       `json:"server"`
+	InitMs      float64      `json:"initMs"`
+	DiscoveryMs float64      `json:"discoveryMs"`
+	ToolCount   int          `json:"toolCount"`
+	Method      string       `json:"method"`
+	Tool        string       `json:"tool,omitempty"`
+	Iterations  int          `json:"iterations"`
+	Latency     latencyStats `json:"latency"`
+}
+
+// latencyStats summarises a set of round-trip times in milliseconds.
+type latencyStats struct {
+	MinMs float64 `json:"minMs"`
+	AvgMs float64 `json:"avgMs"`
+	P95Ms float64 `json:"p95Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if benchIterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+	var toolArgs json.RawMessage
+	if benchTool != "" {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(benchArgs), &obj); err != nil {
+			return fmt.Errorf("--args must be a JSON object: %w", err)
+		}
+		toolArgs = json.RawMessage(benchArgs)
+	}
+
+	cfg, err := loadConfig(benchConfigPath)
+	if err != nil {
+		return err
+	}
+	srv, ok := cfg.GetServer(name)
+	if !ok {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	supervisor, cleanup := newProbeSupervisor(cfg, benchConfigPath, "bench")
+	defer cleanup()
+
+	result, err := benchServer(supervisor, name, srv, benchTool, toolArgs, benchIterations)
+	if err != nil {
+		return err
+	}
+
+	if benchJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Server:     %s\n", result.Server)
+	fmt.Printf("Init:       %s\n", formatMs(result.InitMs))
+	fmt.Printf("Discovery:  %s (%d tools)\n", formatMs(result.DiscoveryMs), result.ToolCount)
+	label := result.Method
+	if result.Tool != "" {
+		label += " " + result.Tool
+	}
+	fmt.Printf("%s x%d:  min %s  avg %s  p95 %s  max %s\n", label, result.Iterations,
+		formatMs(result.Latency.MinMs), formatMs(result.Latency.AvgMs),
+		formatMs(result.Latency.P95Ms), formatMs(result.Latency.MaxMs))
+	return nil
+}
+
+// benchServer starts a server, times its initialization and first tool
+// discovery, then times iterations round trips: calls to tool, or
+// tools/list when tool is empty.
+func benchServer(supervisor *process.Supervisor, name string, srv config.ServerConfig, tool string, toolArgs json.RawMessage, iterations int) (*benchResult, error) {
+	startupTimeout := time.Duration(srv.StartupTimeout()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+	defer cancel()
+
+	// Start returns before a stdio server has initialized, so the split
+	// between init and discovery comes from the handle
+	start := time.Now()
+	handle, err := supervisor.Start(ctx, name, srv)
+	if err != nil {
+		return nil, fmt.Errorf("start %s: %s", name, redact.String(err.Error()))
+	}
+	if handle.AuthStatus() == mcp.AuthStatusOAuthNeeds {
+		return nil, fmt.Errorf("server %q requires OAuth login; run: mcpmu mcp login %s", name, name)
+	}
+	if err := handle.WaitForTools(ctx); err != nil {
+		return nil, fmt.Errorf("start %s: %s", name, redact.String(err.Error()))
+	}
+	if err := handle.ToolsError(); err != nil {
+		return nil, fmt.Errorf("list tools on %s: %s", name, redact.String(err.Error()))
+	}
+	ready := time.Now()
+	initializedAt := handle.InitializedAt()
+
+	result := &benchResult{
+		Server:      name,
+		InitMs:      toMs(initializedAt.Sub(start)),
+		DiscoveryMs: toMs(ready.Sub(initializedAt)),
+		ToolCount:   len(handle.Tools()),
+		Method:      "tools/list",
+		Tool:        tool,
+		Iterations:  iterations,
+	}
+	if tool != "" {
+		result.Method = "tools/call"
+		if !slices.ContainsFunc(handle.Tools(), func(t mcp.Tool) bool { return t.Name == tool }) {
+			return nil, fmt.Errorf("server %q has no tool %q", name, tool)
+		}
+	}
+
+	client := handle.Client()
+	toolTimeout := time.Duration(srv.ToolTimeout()) * time.Second
+	samples := make([]time.Duration, 0, iterations)
+	for i := range iterations {
+		callCtx, callCancel := context.WithTimeout(context.Background(), toolTimeout)
+		start := time.Now()
+		if tool != "" {
+			_, err = client.CallTool(callCtx, tool, toolArgs)
+		} else {
+			_, err = client.ListTools(callCtx)
+		}
+		elapsed := time.Since(start)
+		callCancel()
+		if err != nil {
+			return nil, fmt.Errorf("round trip %d of %d failed: %s", i+1, iterations, redact.String(err.Error()))
+		}
+		samples = append(samples, elapsed)
+	}
+	result.Latency = summariseLatency(samples)
+	return result, nil
+}
+
+// summariseLatency computes min, average, 95th percentile (nearest rank)
+// and max of samples, which must not be empty.
+func summariseLatency(samples []time.Duration) latencyStats {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	// Nearest-rank: the smallest sample with at least 95% at or below it
+	rank := (95*len(sorted) + 99) / 100
+	return latencyStats{
+		MinMs: toMs(sorted[0]),
+		AvgMs: toMs(total / time.Duration(len(sorted))),
+		P95Ms: toMs(sorted[rank-1]),
+		MaxMs: toMs(sorted[len(sorted)-1]),
+	}
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func formatMs(ms float64) string {
+	return fmt.Sprintf("%.1fms", ms)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcptest"
+)
+
+func TestSummariseLatency(t *testing.T) {
+	var samples []time.Duration
+	for i := 20; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	got := summariseLatency(samples)
+	want := latencyStats{MinMs: 1, AvgMs: 10.5, P95Ms: 19, MaxMs: 20}
+	if got != want {
+		t.Errorf("summariseLatency() = %+v, want %+v", got, want)
+	}
+
+	if got := summariseLatency([]time.Duration{3 * time.Millisecond}); got.P95Ms != 3 || got.MinMs != 3 {
+		t.Errorf("single sample = %+v, want all 3ms", got)
+	}
+}
+
+func TestCLI_Bench(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["slow"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "noop"}, {Name: "other"}},
+		Delays: map[string]time.Duration{
+			"initialize": 100 * time.Millisecond,
+			"tools/list": 50 * time.Millisecond,
+			"tools/call": 80 * time.Millisecond,
+		},
+		EchoToolCalls: true,
+	})
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "bench", "slow", "--tool", "noop", "-n", "3", "--json")
+	if err != nil {
+		t.Fatalf("bench failed: %v\nstderr: %s", err, stderr)
+	}
+	var result benchResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+
+	if result.Method != "tools/call" || result.Tool != "noop" || result.Iterations != 3 || result.ToolCount != 2 {
+		t.Errorf("unexpected result header: %+v", result)
+	}
+	if result.InitMs < 100 {
+		t.Errorf("initMs = %v, want at least the 100ms initialize delay", result.InitMs)
+	}
+	if result.DiscoveryMs < 50 {
+		t.Errorf("discoveryMs = %v, want at least the 50ms tools/list delay", result.DiscoveryMs)
+	}
+	lat := result.Latency
+	if lat.MinMs < 80 || lat.MaxMs > 2000 {
+		t.Errorf("latency = %+v, want round trips of just over the 80ms tools/call delay", lat)
+	}
+	if lat.MinMs > lat.AvgMs || lat.AvgMs > lat.MaxMs || lat.P95Ms > lat.MaxMs {
+		t.Errorf("latency stats out of order: %+v", lat)
+	}
+
+	// Text output, timing tools/list
+	stdout, stderr, err = runCLI(testBinary, configPath, "bench", "slow", "-n", "2")
+	if err != nil {
+		t.Fatalf("bench failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "Discovery:") || !strings.Contains(stdout, "tools/list x2:") {
+		t.Errorf("unexpected text output:\n%s", stdout)
+	}
+
+	if _, stderr, err := runCLI(testBinary, configPath, "bench", "slow", "--tool", "missing"); err == nil || !strings.Contains(stderr, `has no tool "missing"`) {
+		t.Errorf("expected an unknown tool to fail, err=%v stderr=%s", err, stderr)
+	}
+}
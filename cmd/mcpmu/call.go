@@ -0,0 +1,526 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	callConfigPath string
+	callKeepAlive  time.Duration
+	callJSON       bool
+	callDaemon     bool
+	callVerbose    bool
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call <server> <tool> [json-arguments]",
+	Short: "Call one tool on a server and print the result",
+	Long: `Start a configured server, call one of its tools with the given JSON
+arguments (default {}) and print the result's text content. The command
+fails if the tool reports an error. The server's deniedTools are enforced.
+
+By default the server is stopped after the call. With --keep-alive, it is
+left running in a background process for that long after the last call, and
+calls to the same server made meanwhile reuse it instead of starting it
+again. The background process stops early if the server exits or its
+config changes.
+
+With --verbose, the JSON-RPC frames exchanged with the server are echoed to
+stderr with secrets masked. With --keep-alive, these are the frames
+exchanged while the call was in flight, so a call to a server that is
+already running shows no initialize handshake.
+
+Examples:
+  mcpmu call filesystem list_allowed_directories
+  mcpmu call github get_me --json
+  mcpmu call github get_me --verbose
+  mcpmu call filesystem read_file '{"path": "/tmp/a.txt"}' --keep-alive 5m`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if callDaemon {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(2, 3)(cmd, args)
+	},
+	RunE: runCall,
+}
+
+func init() {
+	callCmd.Flags().StringVarP(&callConfigPath, "config", "c", "", "Path to config file")
+	callCmd.Flags().DurationVar(&callKeepAlive, "keep-alive", 0, "Keep the server running for this long after the call and reuse it for later calls (0 = stop it after the call)")
+	callCmd.Flags().BoolVar(&callJSON, "json", false, "Print the whole tool result as JSON")
+	callCmd.Flags().BoolVarP(&callVerbose, "verbose", "v", false, "Echo JSON-RPC frames exchanged with the server to stderr (secrets masked)")
+	callCmd.Flags().BoolVar(&callDaemon, "daemon", false, "Run the background process behind --keep-alive")
+	_ = callCmd.Flags().MarkHidden("daemon")
+
+	rootCmd.AddCommand(callCmd)
+}
+
+// keepAliveRequest is a tool call sent to a keep-alive process.
+type keepAliveRequest struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+	Config    string          `json:"config"`    // serverFingerprint of the caller's server config
+	KeepAlive time.Duration   `json:"keepAlive"` // idle time before the process stops
+	Verbose   bool            `json:"verbose,omitempty"`
+}
+
+// keepAliveResponse answers a keepAliveRequest. Stale means the process is
+// running an outdated server config or its server has exited; it has
+// stopped, and the caller should start a new one. Trace holds the frames
+// exchanged during a verbose call.
+type keepAliveResponse struct {
+	Result *mcp.ToolResult  `json:"result,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	Stale  bool             `json:"stale,omitempty"`
+	Trace  []mcp.TraceEntry `json:"trace,omitempty"`
+}
+
+func runCall(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	resolvedConfigPath, err := resolveConfigPath(callConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadFrom(resolvedConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	srv, ok := cfg.GetServer(name)
+	if !ok {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	if callDaemon {
+		return runKeepAliveDaemon(cfg, resolvedConfigPath, name, srv)
+	}
+	if callKeepAlive < 0 {
+		return fmt.Errorf("--keep-alive must not be negative")
+	}
+
+	tool := args[1]
+	arguments := json.RawMessage("{}")
+	if len(args) == 3 {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(args[2]), &obj); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+		arguments = json.RawMessage(args[2])
+	}
+	if srv.IsToolDenied(tool) {
+		return fmt.Errorf("tool %q is denied for server %q", tool, name)
+	}
+
+	var result *mcp.ToolResult
+	if callKeepAlive > 0 {
+		result, err = callKeptAlive(resolvedConfigPath, name, srv, keepAliveRequest{
+			Tool:      tool,
+			Arguments: arguments,
+			Config:    serverFingerprint(srv),
+			KeepAlive: callKeepAlive,
+			Verbose:   callVerbose,
+		})
+	} else {
+		supervisor, cleanup := newProbeSupervisor(cfg, resolvedConfigPath, "call")
+		defer cleanup()
+		if callVerbose {
+			trace := mcp.NewTrace(0)
+			trace.SetTap(func(e mcp.TraceEntry) { writeCallTrace(name, e) })
+			supervisor.SetTrace(name, trace)
+		}
+		var handle *process.Handle
+		handle, err = startForCall(supervisor, name, srv)
+		if err == nil {
+			result, err = callTool(handle, name, srv, tool, arguments)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return printToolResult(result, tool)
+}
+
+// writeCallTrace echoes a traced frame to stderr for --verbose. Each frame
+// is written in one go, so frames traced concurrently don't interleave.
+func writeCallTrace(name string, e mcp.TraceEntry) {
+	_, _ = io.WriteString(os.Stderr, mcp.FormatTraceEntry(name, e))
+}
+
+// startForCall starts a server and waits until its tools are known.
+func startForCall(supervisor *process.Supervisor, name string, srv config.ServerConfig) (*process.Handle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(srv.StartupTimeout())*time.Second)
+	defer cancel()
+
+	handle, err := supervisor.Start(ctx, name, srv)
+	if err != nil {
+		return nil, fmt.Errorf("start %s: %s", name, redact.String(err.Error()))
+	}
+	if handle.AuthStatus() == mcp.AuthStatusOAuthNeeds {
+		return nil, fmt.Errorf("server %q requires OAuth login; run: mcpmu mcp login %s", name, name)
+	}
+	if err := handle.WaitForTools(ctx); err != nil {
+		return nil, fmt.Errorf("start %s: %s", name, redact.String(err.Error()))
+	}
+	if err := handle.ToolsError(); err != nil {
+		return nil, fmt.Errorf("list tools on %s: %s", name, redact.String(err.Error()))
+	}
+	return handle, nil
+}
+
+// callTool calls a tool on a started server, bounded by its tool timeout.
+func callTool(handle *process.Handle, name string, srv config.ServerConfig, tool string, arguments json.RawMessage) (*mcp.ToolResult, error) {
+	if !slices.ContainsFunc(handle.Tools(), func(t mcp.Tool) bool { return t.Name == tool }) {
+		return nil, fmt.Errorf("server %q has no tool %q", name, tool)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(srv.ToolTimeout())*time.Second)
+	defer cancel()
+	result, err := handle.Client().CallTool(ctx, tool, arguments)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %s", tool, redact.String(err.Error()))
+	}
+	return result, nil
+}
+
+// printToolResult prints a tool result, as JSON with --json or else its
+// text content with other content blocks as JSON lines, and fails if the
+// tool reported an error.
+func printToolResult(result *mcp.ToolResult, tool string) error {
+	if callJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, block := range result.Content {
+			var text struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			}
+			if json.Unmarshal(block, &text) == nil && text.Type == "text" {
+				fmt.Println(text.Text)
+			} else {
+				fmt.Println(string(block))
+			}
+		}
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s returned an error", tool)
+	}
+	return nil
+}
+
+// serverFingerprint identifies a server config, so a keep-alive process can
+// tell that the server it runs has been reconfigured.
+func serverFingerprint(srv config.ServerConfig) string {
+	data, _ := json.Marshal(srv)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// keepAliveSocketPath is where the keep-alive process for a server listens,
+// next to the config.
+func keepAliveSocketPath(configPath, name string) (string, error) {
+	dir, err := config.StateDir(configPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "call-"+name+".sock"), nil
+}
+
+// callKeptAlive sends a call to the server's keep-alive process, starting
+// one if none is running or the running one is stale.
+func callKeptAlive(configPath, name string, srv config.ServerConfig, req keepAliveRequest) (*mcp.ToolResult, error) {
+	socket, err := keepAliveSocketPath(configPath, name)
+	if err != nil {
+		return nil, err
+	}
+	startupTimeout := time.Duration(srv.StartupTimeout()) * time.Second
+	callTimeout := startupTimeout + time.Duration(srv.ToolTimeout())*time.Second
+
+	for range 2 {
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			if err := spawnKeepAliveDaemon(configPath, name, req.KeepAlive); err != nil {
+				return nil, err
+			}
+			if conn, err = dialKeepAliveDaemon(socket, startupTimeout); err != nil {
+				return nil, fmt.Errorf("keep-alive process for %s did not start: %w", name, err)
+			}
+		}
+		resp, err := exchangeKeepAlive(conn, req, callTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("keep-alive process for %s: %w", name, err)
+		}
+		if resp.Stale {
+			continue
+		}
+		for _, e := range resp.Trace {
+			writeCallTrace(name, e)
+		}
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+		return resp.Result, nil
+	}
+	return nil, fmt.Errorf("keep-alive process for %s kept going stale", name)
+}
+
+// spawnKeepAliveDaemon starts a detached `mcpmu call --daemon` for a server.
+// Its output is discarded, so it doesn't hold this command's stdio open.
+func spawnKeepAliveDaemon(configPath, name string, keepAlive time.Duration) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find mcpmu executable: %w", err)
+	}
+	args := []string{"call", "--daemon", "--keep-alive", keepAlive.String(), "--config", configPath, name}
+	if configDir != "" {
+		args = append(args, "--config-dir", configDir)
+	}
+	cmd := exec.Command(exe, args...)
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start keep-alive process: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// dialKeepAliveDaemon waits up to timeout for a keep-alive process to listen.
+func dialKeepAliveDaemon(socket string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// exchangeKeepAlive sends one request over conn and reads the response.
+func exchangeKeepAlive(conn net.Conn, req keepAliveRequest, timeout time.Duration) (*keepAliveResponse, error) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp keepAliveResponse
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// keepAliveDaemon serves calls for one server over a unix socket until it
+// has been idle for its keep-alive duration.
+type keepAliveDaemon struct {
+	name        string
+	srv         config.ServerConfig
+	fingerprint string
+	listener    net.Listener
+
+	ready    chan struct{} // closed once the server has started (or failed to)
+	handle   *process.Handle
+	startErr error
+
+	traceMu sync.Mutex
+	tracing []*[]mcp.TraceEntry // frames collected for each verbose call in flight
+
+	mu        sync.Mutex
+	active    int           // calls in flight
+	keepAlive time.Duration // from the latest call
+	idle      *time.Timer
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+// runKeepAliveDaemon is `mcpmu call --daemon`: it listens on the server's
+// socket straight away, so the caller that spawned it can connect while the
+// server starts, and exits once idle for --keep-alive.
+func runKeepAliveDaemon(cfg *config.Config, configPath, name string, srv config.ServerConfig) error {
+	socket, err := keepAliveSocketPath(configPath, name)
+	if err != nil {
+		return err
+	}
+	// Another caller's process may have won the race to start
+	if conn, err := net.Dial("unix", socket); err == nil {
+		_ = conn.Close()
+		return nil
+	}
+	_ = os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socket, err)
+	}
+
+	supervisor, cleanup := newProbeSupervisor(cfg, configPath, "call-"+name)
+	defer cleanup()
+
+	d := &keepAliveDaemon{
+		name:        name,
+		srv:         srv,
+		fingerprint: serverFingerprint(srv),
+		listener:    listener,
+		ready:       make(chan struct{}),
+		keepAlive:   callKeepAlive,
+		done:        make(chan struct{}),
+	}
+	// Covers startup too, in case the caller gave up before connecting
+	d.idle = time.AfterFunc(time.Duration(srv.StartupTimeout())*time.Second+callKeepAlive, d.stop)
+
+	trace := mcp.NewTrace(0)
+	trace.SetTap(d.traceFrame)
+	supervisor.SetTrace(name, trace)
+
+	go func() {
+		d.handle, d.startErr = startForCall(supervisor, name, srv)
+		close(d.ready)
+	}()
+	go d.accept()
+
+	<-d.done
+	return nil
+}
+
+func (d *keepAliveDaemon) accept() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			d.stop()
+			return
+		}
+		d.begin()
+		go d.serve(conn)
+	}
+}
+
+// serve answers one request.
+func (d *keepAliveDaemon) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	var req keepAliveRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		d.end(0)
+		return
+	}
+	var resp keepAliveResponse
+	if req.Verbose {
+		resp = d.traceCall(req)
+	} else {
+		resp = d.call(req)
+	}
+	if resp.Stale {
+		// Stop listening before answering, so the caller's replacement
+		// process can take over the socket
+		_ = d.listener.Close()
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+	d.end(req.KeepAlive)
+
+	stop := resp.Stale
+	select {
+	case <-d.ready:
+		stop = stop || d.startErr != nil
+	default:
+	}
+	if stop {
+		d.stop()
+	}
+}
+
+func (d *keepAliveDaemon) call(req keepAliveRequest) keepAliveResponse {
+	if req.Config != d.fingerprint {
+		return keepAliveResponse{Stale: true}
+	}
+	<-d.ready
+	if d.startErr != nil {
+		return keepAliveResponse{Error: d.startErr.Error()}
+	}
+	if !d.handle.IsRunning() {
+		return keepAliveResponse{Stale: true}
+	}
+	result, err := callTool(d.handle, d.name, d.srv, req.Tool, req.Arguments)
+	if err != nil {
+		return keepAliveResponse{Error: err.Error()}
+	}
+	return keepAliveResponse{Result: result}
+}
+
+// traceCall makes a call, returning the frames traced meanwhile with it.
+// Frames of other calls in flight at the same time are included too.
+func (d *keepAliveDaemon) traceCall(req keepAliveRequest) keepAliveResponse {
+	frames := new([]mcp.TraceEntry)
+	d.traceMu.Lock()
+	d.tracing = append(d.tracing, frames)
+	d.traceMu.Unlock()
+
+	resp := d.call(req)
+
+	d.traceMu.Lock()
+	defer d.traceMu.Unlock()
+	d.tracing = slices.DeleteFunc(d.tracing, func(f *[]mcp.TraceEntry) bool { return f == frames })
+	resp.Trace = *frames
+	return resp
+}
+
+// traceFrame is the server trace's tap, handing each frame to the verbose
+// calls in flight.
+func (d *keepAliveDaemon) traceFrame(e mcp.TraceEntry) {
+	d.traceMu.Lock()
+	defer d.traceMu.Unlock()
+	for _, frames := range d.tracing {
+		*frames = append(*frames, e)
+	}
+}
+
+// begin and end bracket a call; the idle timer runs only when none is in
+// flight, restarting from the latest caller's keep-alive.
+func (d *keepAliveDaemon) begin() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active++
+	d.idle.Stop()
+}
+
+func (d *keepAliveDaemon) end(keepAlive time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.active--
+	if keepAlive > 0 {
+		d.keepAlive = keepAlive
+	}
+	if d.active == 0 {
+		d.idle.Reset(d.keepAlive)
+	}
+}
+
+func (d *keepAliveDaemon) stop() {
+	d.stopOnce.Do(func() {
+		_ = d.listener.Close()
+		close(d.done)
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "syscall"
+
+// detachedProcAttr needs nothing extra where there is no controlling
+// terminal to leave.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return nil
+}
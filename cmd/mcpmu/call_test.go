@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcptest"
+)
+
+func TestCLI_Call(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	srv := fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools:         []mcptest.Tool{{Name: "echo"}, {Name: "secret"}},
+		EchoToolCalls: true,
+	})
+	srv.DeniedTools = []string{"secret"}
+	cfg.Servers["echo"] = srv
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "call", "echo", "echo", `{"n":1}`)
+	if err != nil {
+		t.Fatalf("call failed: %v\nstderr: %s", err, stderr)
+	}
+	if want := "Called tool: echo\nArguments: {\"n\":1}\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+
+	for _, args := range [][]string{
+		{"call", "echo", "missing"},
+		{"call", "echo", "secret"},
+		{"call", "echo", "echo", "[1]"},
+		{"call", "nope", "echo"},
+	} {
+		if _, _, err := runCLI(testBinary, configPath, args...); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
+
+func TestCLI_Call_Verbose(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["echo"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "search"}},
+	})
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	_, stderr, err := runCLI(testBinary, configPath, "call", "echo", "search", `{"query":"weather","api_key":"hunter2"}`, "--verbose")
+	if err != nil {
+		t.Fatalf("call failed: %v\nstderr: %s", err, stderr)
+	}
+	for _, want := range []string{`"method": "tools/call"`, `"name": "search"`, `"query": "weather"`, `"api_key": "REDACTED"`, "→ echo", "← echo"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, stderr)
+		}
+	}
+	if strings.Contains(stderr, "hunter2") {
+		t.Errorf("verbose output leaks the api_key:\n%s", stderr)
+	}
+}
+
+func TestCLI_Call_KeepAlive(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+	requestLog := filepath.Join(t.TempDir(), "requests.log")
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["echo"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools:          []mcptest.Tool{{Name: "echo"}},
+		EchoToolCalls:  true,
+		RequestLogPath: requestLog,
+	})
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	socket := filepath.Join(filepath.Dir(configPath), "call-echo.sock")
+	t.Cleanup(func() {
+		// The keep-alive process removes its socket when it exits
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if _, err := os.Stat(socket); os.IsNotExist(err) {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Errorf("keep-alive process did not exit")
+	})
+
+	for i := range 2 {
+		args := []string{"call", "echo", "echo", "--keep-alive", "2s"}
+		if i == 1 {
+			args = append(args, "--verbose")
+		}
+		stdout, stderr, err := runCLI(testBinary, configPath, args...)
+		if err != nil {
+			t.Fatalf("call %d failed: %v\nstderr: %s", i, err, stderr)
+		}
+		if i == 1 && !strings.Contains(stderr, `"method": "tools/call"`) {
+			t.Errorf("verbose call through the keep-alive process traced no tools/call:\n%s", stderr)
+		}
+		if want := "Called tool: echo\nArguments: {}\n"; stdout != want {
+			t.Errorf("call %d stdout = %q, want %q", i, stdout, want)
+		}
+	}
+
+	data, err := os.ReadFile(requestLog)
+	if err != nil {
+		t.Fatalf("read request log: %v", err)
+	}
+	methods := strings.Fields(string(data))
+	if n := strings.Count(string(data), "initialize\n"); n != 1 {
+		t.Errorf("server initialized %d times, want 1 (requests: %v)", n, methods)
+	}
+	if n := strings.Count(string(data), "tools/call\n"); n != 2 {
+		t.Errorf("got %d tool calls, want 2 (requests: %v)", n, methods)
+	}
+}
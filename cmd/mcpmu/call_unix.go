@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import "syscall"
+
+// detachedProcAttr starts the keep-alive process in its own session, so it
+// outlives the terminal that ran mcpmu call.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
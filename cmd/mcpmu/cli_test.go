@@ -6,10 +6,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcptest"
+	"github.com/Bigsy/mcpmu/internal/server"
 )
 
 // testBinary is the path to the pre-built binary, set by TestMain.
@@ -17,6 +22,11 @@ var testBinary string
 
 // TestMain builds the binary once before all tests run.
 func TestMain(m *testing.M) {
+	// Re-executed as a fake MCP server; see TestHelperProcess
+	if os.Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		os.Exit(m.Run())
+	}
+
 	// Find module root
 	moduleRoot, err := findModuleRoot()
 	if err != nil {
@@ -49,6 +59,29 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// TestHelperProcess implements the fake MCP server used by fakeServerConfig.
+func TestHelperProcess(t *testing.T) {
+	mcptest.RunHelperProcess(t)
+}
+
+// fakeServerConfig returns a stdio server entry that runs this test binary
+// as a fake MCP server.
+func fakeServerConfig(t *testing.T, cfg mcptest.FakeServerConfig) config.ServerConfig {
+	t.Helper()
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal fake server config: %v", err)
+	}
+	return config.ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess", "--"},
+		Env: map[string]string{
+			"GO_WANT_HELPER_PROCESS": "1",
+			"FAKE_MCP_CFG":           string(cfgJSON),
+		},
+	}
+}
+
 // findModuleRoot returns the root of the Go module.
 func findModuleRoot() (string, error) {
 	dir, err := os.Getwd()
@@ -94,6 +127,20 @@ func runCLI(binary, configPath string, args ...string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
+// loadTestServer loads the config and returns the named server.
+func loadTestServer(t *testing.T, configPath, name string) config.ServerConfig {
+	t.Helper()
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	srv, ok := cfg.GetServer(name)
+	if !ok {
+		t.Fatalf("server %q not found in config", name)
+	}
+	return srv
+}
+
 // getServerName verifies the server exists and returns its name (which is also the ID now)
 func getServerName(t *testing.T, configPath, name string) string {
 	t.Helper()
@@ -450,6 +497,77 @@ func TestCLI_Add_HTTP_BearerAndOAuth_MutuallyExclusive(t *testing.T) {
 	}
 }
 
+func TestCLI_Add_Stdin(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	// Legacy flat "scopes" is normalized into the oauth block
+	input := `{
+		"url": "https://example.com/mcp",
+		"http_headers": {"X-Team": "infra"},
+		"env": {"REGION": "eu"},
+		"scopes": ["read", "write"],
+		"tool_timeout_sec": 30,
+		"deniedTools": ["delete_repo"]
+	}`
+	cmd := exec.Command(testBinary, "--config", configPath, "add", "api", "--stdin")
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("add --stdin failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), `Added HTTP server "api" (https://example.com/mcp)`) {
+		t.Errorf("expected success message, got: %s", out)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	var stored struct {
+		Servers map[string]json.RawMessage `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	var got, want any
+	_ = json.Unmarshal(stored.Servers["api"], &got)
+	_ = json.Unmarshal([]byte(`{
+		"url": "https://example.com/mcp",
+		"http_headers": {"X-Team": "infra"},
+		"env": {"REGION": "eu"},
+		"oauth": {"scopes": ["read", "write"]},
+		"tool_timeout_sec": 30,
+		"deniedTools": ["delete_repo"]
+	}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored server = %s", stored.Servers["api"])
+	}
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		args  []string
+		want  string
+	}{
+		{"both", `{"command": "echo", "url": "https://example.com/mcp"}`, nil, "mutually exclusive"},
+		{"empty", "", nil, "no server config"},
+		{"not-json", `command: echo`, nil, "invalid server config JSON"},
+		{"trailing", `{"command": "echo"} {"command": "cat"}`, nil, "single object"},
+		{"flags", `{"command": "echo"}`, []string{"--autostart"}, "--autostart"},
+	} {
+		args := append([]string{"--config", configPath, "add", tc.name, "--stdin"}, tc.args...)
+		cmd := exec.Command(testBinary, args...)
+		cmd.Stdin = strings.NewReader(tc.input)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Errorf("%s: expected add --stdin to fail", tc.name)
+		} else if !strings.Contains(string(out), tc.want) {
+			t.Errorf("%s: expected error containing %q, got: %s", tc.name, tc.want, out)
+		}
+	}
+}
+
 func TestCLI_Add_Stdio_RejectsOAuthFlags(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
@@ -607,6 +725,74 @@ func TestCLI_List_Empty(t *testing.T) {
 	}
 }
 
+func TestCLI_List_StatusNoStart(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "alpha", "--", "echo", "a")
+	_, _, _ = runCLI(testBinary, configPath, "add", "beta", "--", "echo", "b")
+
+	cache := `{"version": 1, "servers": {
+		"alpha": {"tools": [{"name": "read", "tokenCount": 1}, {"name": "write", "tokenCount": 1}]}
+	}}`
+	if err := os.WriteFile(filepath.Join(filepath.Dir(configPath), "toolcache.json"), []byte(cache), 0644); err != nil {
+		t.Fatalf("failed to write tool cache: %v", err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "list", "--json", "--status", "--no-start")
+	if err != nil {
+		t.Fatalf("list --status --no-start failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	var servers []struct {
+		Name   string `json:"name"`
+		Status *struct {
+			State     string `json:"state"`
+			ToolCount *int   `json:"toolCount"`
+			ToolsFrom string `json:"toolsFrom"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &servers); err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d: %s", len(servers), stdout)
+	}
+
+	alpha, beta := servers[0], servers[1]
+	if alpha.Status == nil || alpha.Status.State != "cached" || alpha.Status.ToolsFrom != "cache" {
+		t.Errorf("alpha status = %+v, want cached from cache", alpha.Status)
+	} else if alpha.Status.ToolCount == nil || *alpha.Status.ToolCount != 2 {
+		t.Errorf("alpha toolCount = %v, want 2", alpha.Status.ToolCount)
+	}
+	if beta.Status == nil || beta.Status.State != "uncached" || beta.Status.ToolCount != nil {
+		t.Errorf("beta status = %+v, want uncached without toolCount", beta.Status)
+	}
+}
+
+func TestCLI_List_StatusFlagErrors(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", "--status"}, "--status requires --json"},
+		{[]string{"list", "--json", "--no-start"}, "--no-start requires --status"},
+	}
+	for _, tt := range tests {
+		_, stderr, err := runCLI(testBinary, configPath, tt.args...)
+		if err == nil {
+			t.Errorf("%v: expected error", tt.args)
+			continue
+		}
+		if !strings.Contains(stderr, tt.want) {
+			t.Errorf("%v: expected %q in stderr, got: %s", tt.args, tt.want, stderr)
+		}
+	}
+}
+
 func TestCLI_Remove(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
@@ -646,6 +832,118 @@ func TestCLI_Remove_NotFound(t *testing.T) {
 	}
 }
 
+func TestCLI_DestructiveCommands_RequireConfirmation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      []string
+		unchanged func(cfg *config.Config) bool
+	}{
+		{
+			name: "remove",
+			args: []string{"remove", "api-server"},
+			unchanged: func(cfg *config.Config) bool {
+				_, ok := cfg.GetServer("api-server")
+				return ok
+			},
+		},
+		{
+			name: "namespace remove",
+			args: []string{"namespace", "remove", "prod"},
+			unchanged: func(cfg *config.Config) bool {
+				_, ok := cfg.GetNamespace("prod")
+				return ok
+			},
+		},
+		{
+			name: "permission unset",
+			args: []string{"permission", "unset", "prod", "api-server", "create_user"},
+			unchanged: func(cfg *config.Config) bool {
+				_, ok := cfg.GetToolPermission("prod", "api-server", "create_user")
+				return ok
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			configPath := setupTestConfig(t)
+			_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+			_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+			_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+
+			loaded := func() *config.Config {
+				t.Helper()
+				cfg, err := config.LoadFrom(configPath)
+				if err != nil {
+					t.Fatalf("load config: %v", err)
+				}
+				return cfg
+			}
+
+			// stdin is not a terminal here, so there is nobody to ask
+			_, stderr, err := runCLI(testBinary, configPath, tt.args...)
+			if err == nil {
+				t.Fatal("expected the command to refuse without --yes")
+			}
+			if !strings.Contains(stderr, "without confirmation") || !strings.Contains(stderr, "pass --yes") {
+				t.Errorf("expected a message pointing at --yes, got: %s", stderr)
+			}
+			if !tt.unchanged(loaded()) {
+				t.Fatal("config changed without confirmation")
+			}
+
+			stdout, stderr, err := runCLI(testBinary, configPath, append(tt.args, "--yes")...)
+			if err != nil {
+				t.Fatalf("%v --yes failed: %v\nstdout: %s\nstderr: %s", tt.args, err, stdout, stderr)
+			}
+			if tt.unchanged(loaded()) {
+				t.Error("expected --yes to apply the change")
+			}
+		})
+	}
+}
+
+func TestCLI_DisableEnable_Reason(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "disable", "my-server", "--reason", "flaky upstream")
+	if err != nil {
+		t.Fatalf("disable failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	srv, _ := cfg.GetServer("my-server")
+	if srv.IsEnabled() || srv.DisabledReason != "flaky upstream" {
+		t.Fatalf("after disable: enabled=%v reason=%q", srv.IsEnabled(), srv.DisabledReason)
+	}
+
+	listOut, _, _ := runCLI(testBinary, configPath, "list")
+	if !strings.Contains(listOut, "no (flaky upstream)") {
+		t.Errorf("expected reason in list output, got: %s", listOut)
+	}
+
+	if stdout, stderr, err := runCLI(testBinary, configPath, "enable", "my-server"); err != nil {
+		t.Fatalf("enable failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	cfg, err = config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	srv, _ = cfg.GetServer("my-server")
+	if !srv.IsEnabled() || srv.DisabledReason != "" {
+		t.Errorf("after enable: enabled=%v reason=%q, want enabled with no reason", srv.IsEnabled(), srv.DisabledReason)
+	}
+}
+
 // ============================================================================
 // Namespace CLI Tests
 // ============================================================================
@@ -878,551 +1176,1952 @@ func TestCLI_Namespace_Default(t *testing.T) {
 	}
 }
 
-func TestCLI_Namespace_SetDenyDefault(t *testing.T) {
+func TestCLI_Namespace_DefaultClear(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "dev")
 	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-deny-default", "prod", "true")
+	// initialize resolves the namespace serve exposes
+	initialize := func() string {
+		t.Helper()
+		cmd := exec.Command(testBinary, "--config", configPath, "serve", "--stdio")
+		cmd.Stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n")
+		var stdout, stderr strings.Builder
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		_ = cmd.Run()
+		return stdout.String() + stderr.String()
+	}
+
+	if _, stderr, err := runCLI(testBinary, configPath, "namespace", "default", "dev"); err != nil {
+		t.Fatalf("namespace default failed: %v\nstderr: %s", err, stderr)
+	}
+	if out := initialize(); !strings.Contains(out, `Using default namespace "dev"`) {
+		t.Errorf("expected serve to use the default namespace, got:\n%s", out)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "default", "--clear")
 	if err != nil {
-		t.Fatalf("namespace set-deny-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("namespace default --clear failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Cleared default namespace (was "dev")`) {
+		t.Errorf("expected clear message, got: %s", stdout)
 	}
 
-	if !strings.Contains(stdout, `Deny-by-default enabled for namespace "prod"`) {
-		t.Errorf("expected success message, got: %s", stdout)
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DefaultNamespace != "" {
+		t.Errorf("DefaultNamespace = %q, want empty", cfg.DefaultNamespace)
 	}
 
-	// Verify in list
 	listOut, _, _ := runCLI(testBinary, configPath, "namespace", "list")
-	if !strings.Contains(listOut, "yes") { // deny-default column shows "yes"
-		t.Errorf("expected deny-default 'yes', got: %s", listOut)
+	if strings.Contains(listOut, "*") || !strings.Contains(listOut, "No default namespace set") {
+		t.Errorf("expected list to show no default, got: %s", listOut)
 	}
-}
 
-// ============================================================================
-// Permission CLI Tests
-// ============================================================================
+	// With two namespaces and no default, serve can't pick one
+	if out := initialize(); !strings.Contains(out, "Multiple namespaces configured") {
+		t.Errorf("expected serve to require --namespace, got:\n%s", out)
+	}
 
-func TestCLI_Permission_Set(t *testing.T) {
+	// An empty name clears too, and clearing twice is harmless
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "default", "prod")
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "default", ""); err != nil {
+		t.Fatalf(`namespace default "" failed: %v`, err)
+	}
+	stdout, _, err = runCLI(testBinary, configPath, "namespace", "default", "--clear")
+	if err != nil || !strings.Contains(stdout, "No default namespace set") {
+		t.Errorf("expected clearing an unset default to succeed, got err=%v stdout=%s", err, stdout)
+	}
+
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "default", "--clear", "dev"); err == nil {
+		t.Error("expected --clear with a name to fail")
+	}
+}
+
+func TestCLI_Namespace_SetDenyDefault(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
 	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-deny-default", "prod", "true")
 	if err != nil {
-		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("namespace set-deny-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
 
-	if !strings.Contains(stdout, "denied") {
-		t.Errorf("expected 'denied' in output, got: %s", stdout)
+	if !strings.Contains(stdout, `Deny-by-default enabled for namespace "prod"`) {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	// Verify in list
+	listOut, _, _ := runCLI(testBinary, configPath, "namespace", "list")
+	if !strings.Contains(listOut, "yes") { // deny-default column shows "yes"
+		t.Errorf("expected deny-default 'yes', got: %s", listOut)
 	}
 }
 
-func TestCLI_Permission_Set_NotFound(t *testing.T) {
-	t.Parallel()
+// setupNamespaceToolsConfig seeds two overlapping namespaces and a tool cache:
+// staging has fs, git and db (never started, so uncached); prod has fs and
+// web and denies fs.write_file.
+func setupNamespaceToolsConfig(t *testing.T) string {
+	t.Helper()
 	configPath := setupTestConfig(t)
 
+	for _, name := range []string{"fs", "git", "web", "db"} {
+		_, _, _ = runCLI(testBinary, configPath, "add", name, "--", "echo", "hello")
+	}
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "staging")
 	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "nonexistent", "tool", "allow")
-	if err == nil {
-		t.Fatal("expected error for non-existent server")
+	for _, name := range []string{"fs", "git", "db"} {
+		_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "staging", name)
 	}
+	for _, name := range []string{"fs", "web"} {
+		_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "prod", name)
+	}
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "fs", "write_file", "deny")
 
-	output := stdout + stderr
-	if !strings.Contains(output, "not found") {
-		t.Errorf("expected 'not found' error, got: %s", output)
+	cache := `{"version": 1, "servers": {
+		"fs": {"tools": [{"name": "read_file", "tokenCount": 1}, {"name": "write_file", "tokenCount": 1}]},
+		"git": {"tools": [{"name": "commit", "tokenCount": 1}]},
+		"web": {"tools": [{"name": "fetch", "tokenCount": 1}]}
+	}}`
+	if err := os.WriteFile(filepath.Join(filepath.Dir(configPath), "toolcache.json"), []byte(cache), 0644); err != nil {
+		t.Fatalf("failed to write tool cache: %v", err)
 	}
+	return configPath
 }
 
-func TestCLI_Permission_List(t *testing.T) {
+func TestCLI_Namespace_Tools(t *testing.T) {
 	t.Parallel()
-	configPath := setupTestConfig(t)
-
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "read_user", "allow")
+	configPath := setupNamespaceToolsConfig(t)
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod")
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "tools", "prod")
 	if err != nil {
-		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("namespace tools failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
-
-	if !strings.Contains(stdout, "create_user") || !strings.Contains(stdout, "read_user") {
-		t.Errorf("expected both permissions in output, got: %s", stdout)
+	for _, want := range []string{"fs.read_file", "fs.write_file", "web.fetch", "explicit-deny"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got: %s", want, stdout)
+		}
 	}
-
-	if !strings.Contains(stdout, "deny") || !strings.Contains(stdout, "allow") {
-		t.Errorf("expected permission values, got: %s", stdout)
+	if strings.Contains(stdout, "git.commit") {
+		t.Errorf("expected git tools to be absent from prod, got: %s", stdout)
 	}
 }
 
-func TestCLI_Permission_List_JSON(t *testing.T) {
+func TestCLI_Namespace_Tools_Diff(t *testing.T) {
 	t.Parallel()
-	configPath := setupTestConfig(t)
-
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	configPath := setupNamespaceToolsConfig(t)
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod", "--json")
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "tools", "--diff", "staging", "prod", "--json")
 	if err != nil {
-		t.Fatalf("permission list --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("namespace tools --diff failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	var result struct {
+		OnlyInA               []string `json:"onlyInA"`
+		OnlyInB               []string `json:"onlyInB"`
+		PermissionDifferences []struct {
+			Tool string `json:"tool"`
+			A    struct {
+				Allowed bool `json:"allowed"`
+			} `json:"a"`
+			B struct {
+				Allowed bool   `json:"allowed"`
+				Rule    string `json:"rule"`
+			} `json:"b"`
+		} `json:"permissionDifferences"`
+		UncachedServers []string `json:"uncachedServers"`
 	}
-
-	var result map[string]any
 	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
 		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
 	}
 
-	if result["namespace"] != "prod" {
-		t.Errorf("expected namespace 'prod', got %v", result["namespace"])
+	if !slices.Equal(result.OnlyInA, []string{"git.commit"}) {
+		t.Errorf("onlyInA = %v, want [git.commit]", result.OnlyInA)
+	}
+	if !slices.Equal(result.OnlyInB, []string{"web.fetch"}) {
+		t.Errorf("onlyInB = %v, want [web.fetch]", result.OnlyInB)
+	}
+	if len(result.PermissionDifferences) != 1 {
+		t.Fatalf("expected 1 permission difference, got %+v", result.PermissionDifferences)
+	}
+	diff := result.PermissionDifferences[0]
+	if diff.Tool != "fs.write_file" || !diff.A.Allowed || diff.B.Allowed || diff.B.Rule != "explicit-deny" {
+		t.Errorf("unexpected permission difference: %+v", diff)
+	}
+	if !slices.Equal(result.UncachedServers, []string{"db"}) {
+		t.Errorf("uncachedServers = %v, want [db]", result.UncachedServers)
 	}
 
-	permissions := result["permissions"].([]any)
-	if len(permissions) != 1 {
-		t.Errorf("expected 1 permission, got %d", len(permissions))
+	// Human-readable output covers the same buckets
+	text, stderr, err := runCLI(testBinary, configPath, "namespace", "tools", "--diff", "staging", "prod")
+	if err != nil {
+		t.Fatalf("namespace tools --diff failed: %v", err)
+	}
+	for _, want := range []string{"Only in staging", "git.commit", "Only in prod", "web.fetch", "Permission differences", "fs.write_file"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got: %s", want, text)
+		}
+	}
+	if !strings.Contains(stderr, "db") {
+		t.Errorf("expected warning about uncached server db, got stderr: %s", stderr)
 	}
 }
 
-func TestCLI_Permission_List_Empty(t *testing.T) {
+func TestCLI_Namespace_Tools_DiffArgs(t *testing.T) {
 	t.Parallel()
-	configPath := setupTestConfig(t)
-
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	configPath := setupNamespaceToolsConfig(t)
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod")
-	if err != nil {
-		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "tools", "--diff", "staging"); err == nil {
+		t.Error("expected error when --diff is given one namespace")
 	}
-
-	if !strings.Contains(stdout, "No explicit permissions") {
-		t.Errorf("expected 'No explicit permissions', got: %s", stdout)
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "tools", "staging", "prod"); err == nil {
+		t.Error("expected error for two namespaces without --diff")
+	}
+	_, stderr, err := runCLI(testBinary, configPath, "namespace", "tools", "--diff", "staging", "nope")
+	if err == nil || !strings.Contains(stderr, "not found") {
+		t.Errorf("expected not found error, got err=%v stderr=%s", err, stderr)
 	}
 }
 
-func TestCLI_Permission_Unset(t *testing.T) {
+func TestCLI_Namespace_SetStripPrefix(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "add", "github", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "gh")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "gh", "github")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset", "prod", "api-server", "create_user")
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-strip-prefix", "gh", "true")
 	if err != nil {
-		t.Fatalf("permission unset failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("namespace set-strip-prefix failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
-
-	if !strings.Contains(stdout, "Removed permission") {
+	if !strings.Contains(stdout, `Strip-prefix-when-single enabled for namespace "gh"`) {
 		t.Errorf("expected success message, got: %s", stdout)
 	}
 
-	// Verify permission is gone
-	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
-	if strings.Contains(listOut, "create_user") {
-		t.Error("permission should have been removed")
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.Namespaces["gh"].StripPrefixWhenSingle {
+		t.Error("expected stripPrefixWhenSingle to be set")
 	}
 }
 
-func TestCLI_Permission_Set_NormalizesQualifiedName(t *testing.T) {
+func TestCLI_Namespace_SetShowDenied(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-
-	serverID := getServerName(t, configPath, "api-server")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-show-denied", "work", "true")
+	if err != nil {
+		t.Fatalf("namespace set-show-denied failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Namespace "work" now lists denied tools`) {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.Namespaces["work"].ShowDeniedTools {
+		t.Error("expected showDeniedTools to be set")
+	}
+
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "set-show-denied", "work", "maybe"); err == nil {
+		t.Error("expected error for invalid value")
+	}
+}
+
+func TestCLI_Namespace_SetDescription(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work", "--description", "old")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-description", "work", "Day-job servers")
+	if err != nil {
+		t.Fatalf("namespace set-description failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Set description for namespace "work"`) {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Namespaces["work"].Description; got != "Day-job servers" {
+		t.Errorf("description = %q, want %q", got, "Day-job servers")
+	}
+}
+
+func TestCLI_Namespace_SetServers(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	for _, name := range []string{"fs", "git", "db"} {
+		_, _, _ = runCLI(testBinary, configPath, "add", name, "--", "echo", "hello")
+	}
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "work", "fs")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "work", "git")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "set-servers", "work", "git,db")
+	if err != nil {
+		t.Fatalf("namespace set-servers failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Set 2 server(s) for namespace "work"`) {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Namespaces["work"].ServerIDs; !slices.Equal(got, []string{"git", "db"}) {
+		t.Errorf("serverIds = %v, want [git db]", got)
+	}
+
+	// An unknown server rejects the whole set
+	stdout, stderr, err = runCLI(testBinary, configPath, "namespace", "set-servers", "work", "fs,nope")
+	if err == nil {
+		t.Fatal("expected error for non-existent server")
+	}
+	if !strings.Contains(stdout+stderr, `not found: nope`) {
+		t.Errorf("expected 'not found' error, got: %s", stdout+stderr)
+	}
+	cfg, err = config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Namespaces["work"].ServerIDs; !slices.Equal(got, []string{"git", "db"}) {
+		t.Errorf("serverIds changed after failed set-servers: %v", got)
+	}
+}
+
+func TestCLI_Namespace_Servers(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	for _, name := range []string{"fs", "git"} {
+		_, _, _ = runCLI(testBinary, configPath, "add", name, "--", "echo", "hello")
+	}
+	_, _, _ = runCLI(testBinary, configPath, "disable", "git")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "work", "fs")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "work", "git")
+
+	// Leave a dangling reference behind, as a hand-edited config would
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ns := cfg.Namespaces["work"]
+	ns.ServerIDs = append(ns.ServerIDs, "ghost")
+	cfg.Namespaces["work"] = ns
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "servers", "work")
+	if err != nil {
+		t.Fatalf("namespace servers failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	for _, want := range []string{"fs", "stopped", "git", "disabled", "ghost", "missing"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got: %s", want, stdout)
+		}
+	}
+	if !strings.Contains(stderr, "missing server(s): ghost") {
+		t.Errorf("expected dangling reference warning, got: %s", stderr)
+	}
+
+	stdout, stderr, err = runCLI(testBinary, configPath, "namespace", "servers", "work", "--json")
+	if err != nil {
+		t.Fatalf("namespace servers --json failed: %v\nstderr: %s", err, stderr)
+	}
+	var views []struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+		Running bool   `json:"running"`
+		Missing bool   `json:"missing"`
+		State   string `json:"state"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &views); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(views) != 3 {
+		t.Fatalf("expected 3 servers, got %d: %s", len(views), stdout)
+	}
+	if v := views[0]; v.Name != "fs" || !v.Enabled || v.Running || v.Missing || v.State != "stopped" {
+		t.Errorf("fs = %+v, want enabled and stopped", v)
+	}
+	if v := views[1]; v.Name != "git" || v.Enabled || v.Missing || v.State != "disabled" {
+		t.Errorf("git = %+v, want disabled", v)
+	}
+	if v := views[2]; v.Name != "ghost" || v.Enabled || !v.Missing || v.State != "missing" {
+		t.Errorf("ghost = %+v, want missing", v)
+	}
+
+	_, _, err = runCLI(testBinary, configPath, "namespace", "servers", "nope")
+	if err == nil {
+		t.Error("expected error for unknown namespace")
+	}
+}
+
+func TestCLI_Namespace_Check_Static(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	disabled := false
+	cfg.Servers["fs"] = config.ServerConfig{Command: "echo"}
+	cfg.Servers["git"] = config.ServerConfig{Command: "echo", Enabled: &disabled}
+	cfg.Servers["web"] = config.ServerConfig{Command: "echo"}
+	cfg.Namespaces["ready"] = config.NamespaceConfig{ServerIDs: []string{"fs"}}
+	cfg.Namespaces["broken"] = config.NamespaceConfig{ServerIDs: []string{"fs", "git", "ghost"}}
+	cfg.Namespaces["off"] = config.NamespaceConfig{ServerIDs: []string{"git"}}
+	cfg.Namespaces["empty"] = config.NamespaceConfig{}
+	cfg.ToolPermissions = []config.ToolPermission{
+		{Namespace: "broken", Server: "web", ToolName: "fetch", Enabled: true},
+	}
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "check", "ready")
+	if err != nil {
+		t.Fatalf("namespace check failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, `Namespace "ready" is ready to serve`) {
+		t.Errorf("expected ready message, got: %s", stdout)
+	}
+
+	stdout, stderr, err = runCLI(testBinary, configPath, "namespace", "check", "broken")
+	if err == nil {
+		t.Fatalf("expected namespace check to fail, got: %s", stdout)
+	}
+	for _, want := range []string{
+		`error: server "ghost" is assigned but not configured`,
+		"fix: mcpmu namespace unassign broken ghost",
+		`warning: server "git" is disabled`,
+		"fix: mcpmu enable git",
+		`warning: tool permissions for server "web" have no effect`,
+		"fix: mcpmu namespace assign broken web",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got: %s", want, stdout)
+		}
+	}
+	if !strings.Contains(stderr, `namespace "broken" is not ready: 1 error(s)`) {
+		t.Errorf("expected error summary in stderr, got: %s", stderr)
+	}
+
+	type problem struct {
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+	}
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"off", "none of the assigned servers is enabled"},
+		{"empty", "no servers are assigned"},
+	}
+	for _, tt := range tests {
+		stdout, _, err := runCLI(testBinary, configPath, "namespace", "check", tt.namespace, "--json")
+		if err == nil {
+			t.Errorf("%s: expected namespace check to fail", tt.namespace)
+		}
+		var report struct {
+			OK       bool      `json:"ok"`
+			Problems []problem `json:"problems"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+			t.Fatalf("%s: failed to parse JSON: %v\nstdout: %s", tt.namespace, err, stdout)
+		}
+		if report.OK || !slices.Contains(report.Problems, problem{Severity: "error", Message: tt.want}) {
+			t.Errorf("%s: expected error %q, got %s", tt.namespace, tt.want, stdout)
+		}
+	}
+
+	if _, _, err := runCLI(testBinary, configPath, "namespace", "check", "nope"); err == nil {
+		t.Error("expected error for unknown namespace")
+	}
+}
+
+func TestCLI_Namespace_Check_Start(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["files"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "read_file"}, {Name: "write_file"}},
+	})
+	cfg.Servers["locked"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "secret"}},
+	})
+	cfg.Servers["broken"] = fakeServerConfig(t, mcptest.ErrorOnInitConfig(-32601, "Method not found"))
+	cfg.Namespaces["work"] = config.NamespaceConfig{
+		ServerIDs:      []string{"files", "locked"},
+		ServerDefaults: map[string]bool{"locked": true},
+	}
+	cfg.Namespaces["flaky"] = config.NamespaceConfig{ServerIDs: []string{"files", "broken"}}
+	cfg.ToolPermissions = []config.ToolPermission{
+		{Namespace: "work", Server: "files", ToolName: "delete_file", Enabled: true},
+	}
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	// Statically fine; only starting the servers finds the stale allow rule
+	if stdout, stderr, err := runCLI(testBinary, configPath, "namespace", "check", "work"); err != nil {
+		t.Fatalf("static namespace check failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	stdout, _, err := runCLI(testBinary, configPath, "namespace", "check", "work", "--start", "--json")
+	if err == nil {
+		t.Fatalf("expected namespace check --start to fail, got: %s", stdout)
+	}
+	var work struct {
+		OK      bool `json:"ok"`
+		Servers []struct {
+			Name           string `json:"name"`
+			Started        bool   `json:"started"`
+			ToolCount      int    `json:"toolCount"`
+			PermittedTools int    `json:"permittedTools"`
+			Error          string `json:"error"`
+		} `json:"servers"`
+		Problems []struct {
+			Severity string `json:"severity"`
+			Server   string `json:"server"`
+			Message  string `json:"message"`
+			Fix      string `json:"fix"`
+		} `json:"problems"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &work); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(work.Servers) != 2 {
+		t.Fatalf("expected 2 servers, got %s", stdout)
+	}
+	if s := work.Servers[0]; s.Name != "files" || !s.Started || s.ToolCount != 2 || s.PermittedTools != 2 {
+		t.Errorf("files = %+v, want started with 2 of 2 tools permitted", s)
+	}
+	if s := work.Servers[1]; s.Name != "locked" || !s.Started || s.ToolCount != 1 || s.PermittedTools != 0 {
+		t.Errorf("locked = %+v, want started with 0 of 1 tools permitted", s)
+	}
+	if len(work.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %s", stdout)
+	}
+	if p := work.Problems[0]; p.Severity != "error" || p.Server != "files" || p.Fix != "mcpmu permission unset work files delete_file" {
+		t.Errorf("problem[0] = %+v, want an error for the missing allowed tool", p)
+	}
+	if p := work.Problems[1]; p.Severity != "warning" || p.Server != "locked" || !strings.Contains(p.Message, "denied") {
+		t.Errorf("problem[1] = %+v, want a warning that locked's tools are all denied", p)
+	}
+
+	stdout, _, err = runCLI(testBinary, configPath, "namespace", "check", "flaky", "--start")
+	if err == nil {
+		t.Fatalf("expected namespace check --start to fail, got: %s", stdout)
+	}
+	for _, want := range []string{"broken", `error: server "broken" failed to start`, "Method not found"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output, got: %s", want, stdout)
+		}
+	}
+}
+
+// ============================================================================
+// Permission CLI Tests
+// ============================================================================
+
+func TestCLI_Permission_Set(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	if err != nil {
+		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "denied") {
+		t.Errorf("expected 'denied' in output, got: %s", stdout)
+	}
+}
+
+func TestCLI_Permission_Set_NotFound(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "nonexistent", "tool", "allow")
+	if err == nil {
+		t.Fatal("expected error for non-existent server")
+	}
+
+	output := stdout + stderr
+	if !strings.Contains(output, "not found") {
+		t.Errorf("expected 'not found' error, got: %s", output)
+	}
+}
+
+func TestCLI_Permission_List(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "read_user", "allow")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if err != nil {
+		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "create_user") || !strings.Contains(stdout, "read_user") {
+		t.Errorf("expected both permissions in output, got: %s", stdout)
+	}
+
+	if !strings.Contains(stdout, "deny") || !strings.Contains(stdout, "allow") {
+		t.Errorf("expected permission values, got: %s", stdout)
+	}
+}
+
+func TestCLI_Permission_List_JSON(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod", "--json")
+	if err != nil {
+		t.Fatalf("permission list --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+
+	if result["namespace"] != "prod" {
+		t.Errorf("expected namespace 'prod', got %v", result["namespace"])
+	}
+
+	permissions := result["permissions"].([]any)
+	if len(permissions) != 1 {
+		t.Errorf("expected 1 permission, got %d", len(permissions))
+	}
+}
+
+func TestCLI_Permission_List_Empty(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if err != nil {
+		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "No explicit permissions") {
+		t.Errorf("expected 'No explicit permissions', got: %s", stdout)
+	}
+}
+
+func TestCLI_Permission_Unset(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset", "prod", "api-server", "create_user", "--yes")
+	if err != nil {
+		t.Fatalf("permission unset failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "Removed permission") {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	// Verify permission is gone
+	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if strings.Contains(listOut, "create_user") {
+		t.Error("permission should have been removed")
+	}
+}
+
+func TestCLI_Permission_Set_NormalizesQualifiedName(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+
+	serverID := getServerName(t, configPath, "api-server")
 
 	// Use a qualified name like "<serverID>.create_user" - should be normalized to "create_user"
 	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", serverID+".create_user", "deny")
 	if err != nil {
-		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	// List should show "create_user" not "srv.create_user"
+	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if strings.Contains(listOut, "srv.create_user") {
+		t.Error("qualified name should have been normalized")
+	}
+	if !strings.Contains(listOut, "create_user") {
+		t.Error("expected normalized tool name 'create_user'")
+	}
+}
+
+func TestCLI_Permission_Unset_NormalizesQualifiedName(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+
+	serverID := getServerName(t, configPath, "api-server")
+
+	// Unset with a qualified name - should still work
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset", "prod", "api-server", serverID+".create_user", "--yes")
+	if err != nil {
+		t.Fatalf("permission unset failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "Removed permission") {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	// Verify permission is gone
+	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if strings.Contains(listOut, "create_user") {
+		t.Error("permission should have been removed")
+	}
+}
+
+func TestCLI_Permission_DoesNotStripDotsInToolNames(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "fs.read_file", "deny")
+	if err != nil {
+		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
+	if !strings.Contains(listOut, "fs.read_file") {
+		t.Error("expected tool name with dots to be preserved")
+	}
+	if strings.Contains(stdout, "Removed permission") || strings.Contains(stderr, "invalid") {
+		t.Logf("unexpected output while setting permission: stdout=%q stderr=%q", stdout, stderr)
+	}
+}
+
+// ============================================================================
+// Server Default CLI Tests
+// ============================================================================
+
+func TestCLI_Permission_Check(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "fs", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "set-deny-default", "work", "true")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "work", "fs", "read_file", "allow")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "work", "fs", "write_file", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "allow")
+	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "fs", "delete_file")
+
+	tests := []struct {
+		name    string
+		args    []string
+		allowed bool
+		rule    string
+	}{
+		{"explicit allow", []string{"work", "fs.read_file"}, true, "explicit-allow"},
+		{"explicit deny", []string{"work", "fs.write_file"}, false, "explicit-deny"},
+		{"namespace deny by default", []string{"work", "fs.list_dir"}, false, "namespace-deny-default"},
+		{"server allow by default", []string{"work", "grafana.query"}, true, "server-allow-default"},
+		{"global deny", []string{"work", "fs.delete_file"}, false, "global-deny"},
+		{"no namespace allows all", []string{"fs.list_dir"}, true, "no-namespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"permission", "check"}, tt.args...)
+			stdout, stderr, err := runCLI(testBinary, configPath, append(args, "--json")...)
+			if err != nil {
+				t.Fatalf("permission check failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+			}
+
+			var result struct {
+				Allowed bool   `json:"allowed"`
+				Rule    string `json:"rule"`
+			}
+			if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+				t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+			}
+			if result.Allowed != tt.allowed || result.Rule != tt.rule {
+				t.Errorf("got allowed=%v rule=%s, want allowed=%v rule=%s", result.Allowed, result.Rule, tt.allowed, tt.rule)
+			}
+
+			text, _, err := runCLI(testBinary, configPath, args...)
+			if err != nil {
+				t.Fatalf("permission check failed: %v", err)
+			}
+			verdict := "allowed"
+			if !tt.allowed {
+				verdict = "denied"
+			}
+			if !strings.Contains(text, verdict) || !strings.Contains(text, tt.rule) {
+				t.Errorf("expected %q and rule %q in output, got: %s", verdict, tt.rule, text)
+			}
+		})
+	}
+}
+
+func TestCLI_Permission_Check_Errors(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "fs", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown namespace", []string{"nope", "fs.read_file"}, "not found"},
+		{"unknown server", []string{"work", "nope.read_file"}, "not found"},
+		{"unqualified tool", []string{"work", "read_file"}, "server.tool"},
+		{"manager tool", []string{"work", "mcpmu.servers_list"}, "manager tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, err := runCLI(testBinary, configPath, append([]string{"permission", "check"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("expected error, got stdout: %s", stdout)
+			}
+			if !strings.Contains(stdout+stderr, tt.want) {
+				t.Errorf("expected %q in output, got: %s", tt.want, stdout+stderr)
+			}
+		})
+	}
+}
+
+func TestCLI_Permission_SetServerDefault(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+	if err != nil {
+		t.Fatalf("set-server-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "deny") {
+		t.Errorf("expected 'deny' in output, got: %s", stdout)
+	}
+}
+
+func TestCLI_Permission_UnsetServerDefault(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset-server-default", "work", "grafana")
+	if err != nil {
+		t.Fatalf("unset-server-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "Removed server default") {
+		t.Errorf("expected success message, got: %s", stdout)
+	}
+
+	// Verify it's gone from list output
+	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "work")
+	if strings.Contains(listOut, "grafana") && strings.Contains(listOut, "deny by default") {
+		t.Error("server default should have been removed from list output")
+	}
+}
+
+func TestCLI_Permission_SetServerDefault_NotFound(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+
+	// Non-existent server
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set-server-default", "work", "nonexistent", "deny")
+	if err == nil {
+		t.Fatal("expected error for non-existent server")
+	}
+	output := stdout + stderr
+	if !strings.Contains(output, "not found") {
+		t.Errorf("expected 'not found' error, got: %s", output)
+	}
+
+	// Non-existent namespace
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	stdout, stderr, err = runCLI(testBinary, configPath, "permission", "set-server-default", "nonexistent", "grafana", "deny")
+	if err == nil {
+		t.Fatal("expected error for non-existent namespace")
+	}
+	output = stdout + stderr
+	if !strings.Contains(output, "not found") {
+		t.Errorf("expected 'not found' error, got: %s", output)
+	}
+}
+
+func TestCLI_Permission_List_WithServerDefaults(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+
+	// Table output - with server defaults but no explicit tool permissions
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "work")
+	if err != nil {
+		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "Server defaults:") {
+		t.Errorf("expected 'Server defaults:' section, got: %s", stdout)
+	}
+	if !strings.Contains(stdout, "grafana") || !strings.Contains(stdout, "deny") {
+		t.Errorf("expected both grafana and deny in output, got: %s", stdout)
+	}
+
+	// JSON output
+	stdout, stderr, err = runCLI(testBinary, configPath, "permission", "list", "work", "--json")
+	if err != nil {
+		t.Fatalf("permission list --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+
+	sd, ok := result["serverDefaults"]
+	if !ok {
+		t.Fatal("expected 'serverDefaults' field in JSON output")
+	}
+	sdMap, ok := sd.(map[string]any)
+	if !ok {
+		t.Fatalf("expected serverDefaults to be a map, got %T", sd)
+	}
+	if sdMap["grafana"] != "deny" {
+		t.Errorf("expected grafana=deny, got %v", sdMap["grafana"])
+	}
+}
+
+func TestCLI_Permission_List_WithServerDefaults_AndToolPermissions(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "work", "grafana", "query_loki_logs", "allow")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "work")
+	if err != nil {
+		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	// Should show both server defaults section and tool permissions
+	if !strings.Contains(stdout, "Server defaults:") {
+		t.Errorf("expected 'Server defaults:' section, got: %s", stdout)
+	}
+	if !strings.Contains(stdout, "query_loki_logs") {
+		t.Errorf("expected tool permission in output, got: %s", stdout)
+	}
+}
+
+// ============================================================================
+// Server Deny Tool CLI Tests
+// ============================================================================
+
+func TestCLI_Server_DenyTool(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	// Add a server first
+	_, _, err := runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
 	}
 
-	// List should show "create_user" not "srv.create_user"
-	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
-	if strings.Contains(listOut, "srv.create_user") {
-		t.Error("qualified name should have been normalized")
+	// Deny a tool
+	stdout, stderr, err := runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	if err != nil {
+		t.Fatalf("server deny-tool failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
-	if !strings.Contains(listOut, "create_user") {
-		t.Error("expected normalized tool name 'create_user'")
+
+	// Verify config
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	srv, _ := cfg.GetServer("my-server")
+	if !srv.IsToolDenied("delete_file") {
+		t.Error("expected delete_file to be denied")
+	}
+
+	// Deny same tool again (idempotent)
+	_, _, err = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	if err != nil {
+		t.Fatalf("idempotent deny-tool failed: %v", err)
+	}
+	cfg, _ = config.LoadFrom(configPath)
+	srv, _ = cfg.GetServer("my-server")
+	if len(srv.DeniedTools) != 1 {
+		t.Errorf("expected 1 denied tool (idempotent), got %d", len(srv.DeniedTools))
 	}
 }
 
-func TestCLI_Permission_Unset_NormalizesQualifiedName(t *testing.T) {
+func TestCLI_Server_DenyTool_Multiple(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "create_user", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
 
-	serverID := getServerName(t, configPath, "api-server")
+	// Deny multiple tools in one call
+	stdout, stderr, err := runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file", "move_file")
+	if err != nil {
+		t.Fatalf("server deny-tool multiple failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
 
-	// Unset with a qualified name - should still work
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset", "prod", "api-server", serverID+".create_user")
+	cfg, _ := config.LoadFrom(configPath)
+	srv, _ := cfg.GetServer("my-server")
+	if len(srv.DeniedTools) != 2 {
+		t.Errorf("expected 2 denied tools, got %d: %v", len(srv.DeniedTools), srv.DeniedTools)
+	}
+}
+
+func TestCLI_Server_AllowTool(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+
+	// Allow the tool
+	stdout, stderr, err := runCLI(testBinary, configPath, "server", "allow-tool", "my-server", "delete_file")
 	if err != nil {
-		t.Fatalf("permission unset failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("server allow-tool failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
 
-	if !strings.Contains(stdout, "Removed permission") {
-		t.Errorf("expected success message, got: %s", stdout)
+	cfg, _ := config.LoadFrom(configPath)
+	srv, _ := cfg.GetServer("my-server")
+	if srv.IsToolDenied("delete_file") {
+		t.Error("expected delete_file to no longer be denied")
 	}
+}
 
-	// Verify permission is gone
-	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
-	if strings.Contains(listOut, "create_user") {
-		t.Error("permission should have been removed")
+func TestCLI_Server_AllowTool_NotDenied(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+
+	// Allow a tool that isn't denied (no-op, no error)
+	_, _, err := runCLI(testBinary, configPath, "server", "allow-tool", "my-server", "read_file")
+	if err != nil {
+		t.Fatalf("allow-tool on non-denied tool should not error: %v", err)
 	}
 }
 
-func TestCLI_Permission_DoesNotStripDotsInToolNames(t *testing.T) {
+func TestCLI_Server_DeniedTools(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "api-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "prod")
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file", "move_file")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set", "prod", "api-server", "fs.read_file", "deny")
+	// List denied tools
+	stdout, stderr, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server")
 	if err != nil {
-		t.Fatalf("permission set failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("server denied-tools failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
 
-	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "prod")
-	if !strings.Contains(listOut, "fs.read_file") {
-		t.Error("expected tool name with dots to be preserved")
+	if !strings.Contains(stdout, "delete_file") {
+		t.Errorf("expected delete_file in output, got: %s", stdout)
 	}
-	if strings.Contains(stdout, "Removed permission") || strings.Contains(stderr, "invalid") {
-		t.Logf("unexpected output while setting permission: stdout=%q stderr=%q", stdout, stderr)
+	if !strings.Contains(stdout, "move_file") {
+		t.Errorf("expected move_file in output, got: %s", stdout)
 	}
 }
 
-// ============================================================================
-// Server Default CLI Tests
-// ============================================================================
+func TestCLI_Server_DeniedTools_JSON(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
 
-func TestCLI_Permission_SetServerDefault(t *testing.T) {
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server", "--json")
+	if err != nil {
+		t.Fatalf("server denied-tools --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	var tools []string
+	if err := json.Unmarshal([]byte(stdout), &tools); err != nil {
+		t.Fatalf("invalid JSON output: %v\nraw: %s", err, stdout)
+	}
+	if len(tools) != 1 || tools[0] != "delete_file" {
+		t.Errorf("expected [\"delete_file\"], got %v", tools)
+	}
+}
+
+func TestCLI_Server_DeniedTools_Empty(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+	stdout, _, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server")
 	if err != nil {
-		t.Fatalf("set-server-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("server denied-tools (empty) failed: %v", err)
 	}
 
-	if !strings.Contains(stdout, "deny") {
-		t.Errorf("expected 'deny' in output, got: %s", stdout)
+	if !strings.Contains(stdout, "No denied tools") {
+		t.Errorf("expected 'No denied tools' message, got: %s", stdout)
 	}
 }
 
-func TestCLI_Permission_UnsetServerDefault(t *testing.T) {
+func TestCLI_Server_Trust(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
 	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "set-deny-default", "work", "true")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "work", "my-server")
+	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "work", "my-server", "delete_file", "deny")
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "unset-server-default", "work", "grafana")
+	if _, stderr, err := runCLI(testBinary, configPath, "server", "trust", "my-server"); err != nil {
+		t.Fatalf("server trust failed: %v\nstderr: %s", err, stderr)
+	}
+	if srv := loadTestServer(t, configPath, "my-server"); !srv.Trusted {
+		t.Fatal("expected my-server to be trusted")
+	}
+
+	stdout, _, _ := runCLI(testBinary, configPath, "permission", "check", "work", "my-server.read_file")
+	if !strings.Contains(stdout, "trusted") {
+		t.Errorf("expected read_file to be allowed as trusted, got: %s", stdout)
+	}
+	stdout, _, _ = runCLI(testBinary, configPath, "permission", "check", "work", "my-server.delete_file")
+	if !strings.Contains(stdout, "explicitly denied") {
+		t.Errorf("expected delete_file to stay explicitly denied, got: %s", stdout)
+	}
+
+	if _, stderr, err := runCLI(testBinary, configPath, "server", "untrust", "my-server"); err != nil {
+		t.Fatalf("server untrust failed: %v\nstderr: %s", err, stderr)
+	}
+	if srv := loadTestServer(t, configPath, "my-server"); srv.Trusted {
+		t.Error("expected my-server to no longer be trusted")
+	}
+
+	if _, _, err := runCLI(testBinary, configPath, "server", "trust", "nope"); err == nil {
+		t.Error("expected trusting an unknown server to fail")
+	}
+}
+
+func TestCLI_Permission_ExportImport(t *testing.T) {
+	t.Parallel()
+	srcPath := setupTestConfig(t)
+	dstPath := setupTestConfig(t)
+
+	for _, args := range [][]string{
+		{"add", "gh", "--", "echo"},
+		{"add", "fs", "--", "echo"},
+		{"namespace", "add", "prod"},
+		{"namespace", "set-deny-default", "prod", "true"},
+		{"permission", "set-server-default", "prod", "fs", "allow"},
+		{"permission", "set", "prod", "gh", "create_issue", "allow"},
+		{"permission", "set", "prod", "gh", "delete_repo", "deny"},
+		{"permission", "set", "prod", "fs", "write_file", "deny"},
+	} {
+		if _, stderr, err := runCLI(testBinary, srcPath, args...); err != nil {
+			t.Fatalf("%v failed: %v\nstderr: %s", args, err, stderr)
+		}
+	}
+	// The destination names the GitHub server differently and already has a
+	// permission that --replace should remove
+	for _, args := range [][]string{
+		{"add", "github", "--", "echo"},
+		{"add", "fs", "--", "echo"},
+		{"namespace", "add", "staging"},
+		{"permission", "set", "staging", "fs", "read_file", "deny"},
+	} {
+		if _, stderr, err := runCLI(testBinary, dstPath, args...); err != nil {
+			t.Fatalf("%v failed: %v\nstderr: %s", args, err, stderr)
+		}
+	}
+
+	exported, stderr, err := runCLI(testBinary, srcPath, "permission", "export", "prod")
 	if err != nil {
-		t.Fatalf("unset-server-default failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("permission export failed: %v\nstderr: %s", err, stderr)
+	}
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policyPath, []byte(exported), 0644); err != nil {
+		t.Fatal(err)
 	}
 
-	if !strings.Contains(stdout, "Removed server default") {
-		t.Errorf("expected success message, got: %s", stdout)
+	// Without the rename, gh doesn't exist and nothing is imported
+	if _, stderr, err := runCLI(testBinary, dstPath, "permission", "import", "staging", policyPath); err == nil || !strings.Contains(stderr, `unknown server(s): gh`) {
+		t.Fatalf("expected import to fail on the unknown server, err=%v stderr=%s", err, stderr)
 	}
 
-	// Verify it's gone from list output
-	listOut, _, _ := runCLI(testBinary, configPath, "permission", "list", "work")
-	if strings.Contains(listOut, "grafana") && strings.Contains(listOut, "deny by default") {
-		t.Error("server default should have been removed from list output")
+	if _, stderr, err := runCLI(testBinary, dstPath, "permission", "import", "staging", policyPath, "--map", "gh=github", "--replace"); err != nil {
+		t.Fatalf("permission import failed: %v\nstderr: %s", err, stderr)
+	}
+
+	reexported, _, err := runCLI(testBinary, dstPath, "permission", "export", "staging")
+	if err != nil {
+		t.Fatalf("permission export of the imported namespace failed: %v", err)
+	}
+	var got, want config.NamespacePolicy
+	if err := json.Unmarshal([]byte(reexported), &got); err != nil {
+		t.Fatalf("export output is not a policy: %v\n%s", err, reexported)
+	}
+	want = config.NamespacePolicy{
+		DenyByDefault:  true,
+		ServerDefaults: map[string]bool{"fs": false},
+		Permissions: []config.PolicyPermission{
+			{Server: "fs", ToolName: "write_file", Enabled: false},
+			{Server: "github", ToolName: "create_issue", Enabled: true},
+			{Server: "github", ToolName: "delete_repo", Enabled: false},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imported policy = %+v, want %+v", got, want)
+	}
+
+	if _, _, err := runCLI(testBinary, dstPath, "permission", "import", "staging", policyPath, "--map", "nope=github"); err == nil {
+		t.Error("expected a --map for a server the policy doesn't reference to fail")
 	}
 }
 
-func TestCLI_Permission_SetServerDefault_NotFound(t *testing.T) {
+func TestCLI_Server_DenyTool_NormalizesQualifiedName(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "add", "filesystem", "--", "echo", "hello")
 
-	// Non-existent server
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "set-server-default", "work", "nonexistent", "deny")
+	// Deny using qualified name (as copied from tools/list)
+	_, _, err := runCLI(testBinary, configPath, "server", "deny-tool", "filesystem", "filesystem.delete_file")
+	if err != nil {
+		t.Fatalf("deny-tool with qualified name failed: %v", err)
+	}
+
+	cfg, _ := config.LoadFrom(configPath)
+	srv, _ := cfg.GetServer("filesystem")
+	// Should be stored as unqualified "delete_file", not "filesystem.delete_file"
+	if srv.IsToolDenied("filesystem.delete_file") {
+		t.Error("should not store qualified name verbatim")
+	}
+	if !srv.IsToolDenied("delete_file") {
+		t.Error("expected unqualified 'delete_file' to be denied after normalization")
+	}
+}
+
+func TestCLI_Server_DenyTool_ServerNotFound(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	_, _, err := runCLI(testBinary, configPath, "server", "deny-tool", "nonexistent", "tool")
 	if err == nil {
 		t.Fatal("expected error for non-existent server")
 	}
-	output := stdout + stderr
-	if !strings.Contains(output, "not found") {
-		t.Errorf("expected 'not found' error, got: %s", output)
+}
+
+func TestCLI_Config_PathPrecedence(t *testing.T) {
+	t.Parallel()
+	home := t.TempDir()
+	envPath := filepath.Join(t.TempDir(), "env.json")
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want string
+	}{
+		{"default", "", nil, filepath.Join(home, ".config", "mcpmu", "config.json")},
+		{"env", envPath, nil, envPath},
+		{"flag beats env", envPath, []string{"--config", flagPath}, flagPath},
+		{"flag expands home", "", []string{"--config", "~/custom.json"}, filepath.Join(home, "custom.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(testBinary, append([]string{"config", "path"}, tt.args...)...)
+			cmd.Env = append(os.Environ(), "HOME="+home, "MCPMU_CONFIG="+tt.env)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("config path failed: %v\noutput: %s", err, out)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("config path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCLI_ConfigDir(t *testing.T) {
+	t.Parallel()
+	home := t.TempDir()
+	flagDir := t.TempDir()
+	envDir := t.TempDir()
+
+	run := func(env []string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command(testBinary, args...)
+		cmd.Env = append(os.Environ(), append([]string{"HOME=" + home, "MCPMU_CONFIG="}, env...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v failed: %v\noutput: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	// --config-dir relocates the default config
+	run(nil, "--config-dir", flagDir, "add", "echo", "--", "echo", "hi")
+	cfg, err := config.LoadFrom(filepath.Join(flagDir, "config.json"))
+	if err != nil {
+		t.Fatalf("load relocated config: %v", err)
+	}
+	if _, ok := cfg.GetServer("echo"); !ok {
+		t.Error("expected server in the --config-dir config")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "mcpmu", "config.json")); err == nil {
+		t.Error("default config should not be written with --config-dir")
+	}
+
+	// $MCPMU_HOME does the same, and the flag beats it
+	if got := run([]string{"MCPMU_HOME=" + envDir}, "config", "path"); got != filepath.Join(envDir, "config.json") {
+		t.Errorf("config path with MCPMU_HOME = %q", got)
+	}
+	if got := run([]string{"MCPMU_HOME=" + envDir}, "--config-dir", flagDir, "config", "path"); got != filepath.Join(flagDir, "config.json") {
+		t.Errorf("config path with --config-dir and MCPMU_HOME = %q", got)
+	}
+}
+
+func TestCLI_MCPCredentialStore(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "mcp", "credential-store")
+	if err != nil {
+		t.Fatalf("credential-store failed: %v\nstderr: %s", err, stderr)
+	}
+	if strings.TrimSpace(stdout) != "auto" {
+		t.Errorf("default store = %q, want auto", stdout)
+	}
+
+	if _, stderr, err := runCLI(testBinary, configPath, "mcp", "credential-store", "pass"); err != nil {
+		t.Fatalf("set credential-store failed: %v\nstderr: %s", err, stderr)
+	}
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.MCPOAuthCredentialStore != "pass" {
+		t.Errorf("mcp_oauth_credentials_store = %q, want pass", cfg.MCPOAuthCredentialStore)
+	}
+
+	stdout, stderr, err = runCLI(testBinary, configPath, "mcp", "credential-store", "file", "--encryption", "passphrase")
+	if err != nil {
+		t.Fatalf("set encryption failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "File store encryption: passphrase") {
+		t.Errorf("stdout = %q, want encryption confirmation", stdout)
+	}
+	cfg, err = config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.MCPOAuthCredentialStore != "file" || cfg.MCPOAuthCredentialEncryption != "passphrase" {
+		t.Errorf("store = %q, encryption = %q, want file/passphrase", cfg.MCPOAuthCredentialStore, cfg.MCPOAuthCredentialEncryption)
 	}
 
-	// Non-existent namespace
-	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
-	stdout, stderr, err = runCLI(testBinary, configPath, "permission", "set-server-default", "nonexistent", "grafana", "deny")
+	_, stderr, err = runCLI(testBinary, configPath, "mcp", "credential-store", "vault")
 	if err == nil {
-		t.Fatal("expected error for non-existent namespace")
+		t.Fatal("expected error for unknown backend")
 	}
-	output = stdout + stderr
-	if !strings.Contains(output, "not found") {
-		t.Errorf("expected 'not found' error, got: %s", output)
+	if !strings.Contains(stderr, "invalid credential store") {
+		t.Errorf("stderr = %q, want invalid credential store", stderr)
 	}
 }
 
-func TestCLI_Permission_List_WithServerDefaults(t *testing.T) {
+func TestCLI_Config_ShowMasksSecrets(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
+	_, _, _ = runCLI(testBinary, configPath, "add", "gh", "--env", "GITHUB_TOKEN=ghp_secret", "--env", "LOG_LEVEL=debug", "--", "gh-mcp", "--api-key=sk-123")
 
-	// Table output - with server defaults but no explicit tool permissions
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "work")
-	if err != nil {
-		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
-	}
-	if !strings.Contains(stdout, "Server defaults:") {
-		t.Errorf("expected 'Server defaults:' section, got: %s", stdout)
-	}
-	if !strings.Contains(stdout, "grafana") || !strings.Contains(stdout, "deny") {
-		t.Errorf("expected both grafana and deny in output, got: %s", stdout)
+	for _, args := range [][]string{{"config", "show"}, {"config", "show", "--json"}} {
+		stdout, stderr, err := runCLI(testBinary, configPath, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		for _, secret := range []string{"ghp_secret", "sk-123"} {
+			if strings.Contains(stdout, secret) {
+				t.Errorf("%v: secret %q not masked:\n%s", args, secret, stdout)
+			}
+		}
+		for _, want := range []string{"GITHUB_TOKEN", "REDACTED", "debug"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%v: expected %q in output:\n%s", args, want, stdout)
+			}
+		}
 	}
 
-	// JSON output
-	stdout, stderr, err = runCLI(testBinary, configPath, "permission", "list", "work", "--json")
+	stdout, stderr, err := runCLI(testBinary, configPath, "config", "show", "--json", "--reveal")
 	if err != nil {
-		t.Fatalf("permission list --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("config show --reveal failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
-
-	var result map[string]any
-	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+	var cfg config.Config
+	if err := json.Unmarshal([]byte(stdout), &cfg); err != nil {
 		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
 	}
-
-	sd, ok := result["serverDefaults"]
-	if !ok {
-		t.Fatal("expected 'serverDefaults' field in JSON output")
-	}
-	sdMap, ok := sd.(map[string]any)
-	if !ok {
-		t.Fatalf("expected serverDefaults to be a map, got %T", sd)
-	}
-	if sdMap["grafana"] != "deny" {
-		t.Errorf("expected grafana=deny, got %v", sdMap["grafana"])
+	if got := cfg.Servers["gh"].Env["GITHUB_TOKEN"]; got != "ghp_secret" {
+		t.Errorf("--reveal GITHUB_TOKEN = %q, want ghp_secret", got)
 	}
 }
 
-func TestCLI_Permission_List_WithServerDefaults_AndToolPermissions(t *testing.T) {
+func TestCLI_Schema(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "grafana", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set-server-default", "work", "grafana", "deny")
-	_, _, _ = runCLI(testBinary, configPath, "permission", "set", "work", "grafana", "query_loki_logs", "allow")
+	stdout, stderr, err := runCLI(testBinary, configPath, "schema")
+	if err != nil {
+		t.Fatalf("schema failed: %v\nstderr: %s", err, stderr)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(stdout), &schema); err != nil {
+		t.Fatalf("schema output is not JSON: %v", err)
+	}
+	if schema["$id"] != config.SchemaURL {
+		t.Errorf("$id = %v, want %s", schema["$id"], config.SchemaURL)
+	}
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "permission", "list", "work")
+	// --link survives later saves by other commands
+	if _, stderr, err := runCLI(testBinary, configPath, "schema", "--link"); err != nil {
+		t.Fatalf("schema --link failed: %v\nstderr: %s", err, stderr)
+	}
+	_, _, _ = runCLI(testBinary, configPath, "add", "fs", "--", "echo", "hello")
+	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
-		t.Fatalf("permission list failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Schema != config.SchemaURL {
+		t.Errorf("$schema = %q after add, want %q", cfg.Schema, config.SchemaURL)
 	}
 
-	// Should show both server defaults section and tool permissions
-	if !strings.Contains(stdout, "Server defaults:") {
-		t.Errorf("expected 'Server defaults:' section, got: %s", stdout)
+	if _, stderr, err := runCLI(testBinary, configPath, "schema", "--unlink"); err != nil {
+		t.Fatalf("schema --unlink failed: %v\nstderr: %s", err, stderr)
 	}
-	if !strings.Contains(stdout, "query_loki_logs") {
-		t.Errorf("expected tool permission in output, got: %s", stdout)
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), `"$schema"`) {
+		t.Errorf("expected $schema to be removed, got %s", data)
 	}
 }
 
 // ============================================================================
-// Server Deny Tool CLI Tests
+// Import CLI Tests
 // ============================================================================
 
-func TestCLI_Server_DenyTool(t *testing.T) {
+func TestCLI_Import_FromMcpmu(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	// Add a server first
-	_, _, err := runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-	if err != nil {
-		t.Fatalf("add failed: %v", err)
+	_, _, _ = runCLI(testBinary, configPath, "add", "local", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "personal")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "assign", "personal", "local")
+
+	templatePath := filepath.Join(t.TempDir(), "template.json")
+	template := `{"schemaVersion": 1,
+		"servers": {"jira": {"command": "jira-mcp"}},
+		"namespaces": {"work": {"description": "Team", "serverIds": ["jira"], "denyByDefault": true}},
+		"toolPermissions": [{"namespace": "work", "server": "jira", "toolName": "get_issue", "enabled": true}]}`
+	if err := os.WriteFile(templatePath, []byte(template), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
 	}
 
-	// Deny a tool
-	stdout, stderr, err := runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	stdout, stderr, err := runCLI(testBinary, configPath, "import", "--from", "mcpmu", templatePath)
 	if err != nil {
-		t.Fatalf("server deny-tool failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("import failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	for _, want := range []string{`+ server "jira"`, `+ namespace "work"`, "+ permission work/jira.get_issue"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
 	}
 
-	// Verify config
 	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
 		t.Fatalf("load config: %v", err)
 	}
-	srv, _ := cfg.GetServer("my-server")
-	if !srv.IsToolDenied("delete_file") {
-		t.Error("expected delete_file to be denied")
+	if !cfg.Namespaces["work"].DenyByDefault {
+		t.Error("expected imported namespace to keep denyByDefault")
+	}
+	if enabled, ok := cfg.GetToolPermission("work", "jira", "get_issue"); !ok || !enabled {
+		t.Error("expected imported permission")
+	}
+	if got := cfg.Namespaces["personal"].ServerIDs; !slices.Equal(got, []string{"local"}) {
+		t.Errorf("personal namespace changed: %v", got)
 	}
 
-	// Deny same tool again (idempotent)
-	_, _, err = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	// Re-importing a changed template conflicts unless told what to do
+	changed := strings.Replace(template, `"Team"`, `"Team v2"`, 1)
+	if err := os.WriteFile(templatePath, []byte(changed), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	stdout, stderr, err = runCLI(testBinary, configPath, "import", "--from", "mcpmu", templatePath)
+	if err == nil || !strings.Contains(stdout+stderr, "conflicting entries") {
+		t.Fatalf("expected conflict error, got err=%v output: %s", err, stdout+stderr)
+	}
+	stdout, stderr, err = runCLI(testBinary, configPath, "import", "--from", "mcpmu", "--on-conflict", "overwrite", templatePath)
 	if err != nil {
-		t.Fatalf("idempotent deny-tool failed: %v", err)
+		t.Fatalf("import --on-conflict overwrite failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
-	cfg, _ = config.LoadFrom(configPath)
-	srv, _ = cfg.GetServer("my-server")
-	if len(srv.DeniedTools) != 1 {
-		t.Errorf("expected 1 denied tool (idempotent), got %d", len(srv.DeniedTools))
+	cfg, err = config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Namespaces["work"].Description; got != "Team v2" {
+		t.Errorf("description = %q, want Team v2", got)
 	}
 }
 
-func TestCLI_Server_DenyTool_Multiple(t *testing.T) {
+// ============================================================================
+// Diff CLI Tests
+// ============================================================================
+
+func TestCLI_Diff(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
+	dir := t.TempDir()
+
+	pathA := filepath.Join(dir, "a.json")
+	pathB := filepath.Join(dir, "b.json")
+	configA := `{"schemaVersion": 1,
+		"servers": {"github": {"command": "gh-mcp"}, "jira": {"command": "jira-mcp"}},
+		"namespaces": {"work": {"serverIds": ["github", "jira"]}},
+		"toolPermissions": [{"namespace": "work", "server": "github", "toolName": "create_issue", "enabled": true}]}`
+	configB := `{"toolPermissions": [{"namespace": "work", "server": "github", "toolName": "create_issue", "enabled": false}],
+		"namespaces": {"work": {"serverIds": ["github"]}, "ops": {"serverIds": ["slack"]}},
+		"servers": {"slack": {"url": "https://slack.example.com/mcp"}, "github": {"command": "gh-mcp", "autostart": true}},
+		"defaultNamespace": "work", "schemaVersion": 1}`
+	if err := os.WriteFile(pathA, []byte(configA), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pathB, []byte(configB), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, err := runCLI(testBinary, configPath, "diff", pathA, pathB)
+	if err != nil {
+		t.Fatalf("diff failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	for _, want := range []string{
+		"Servers:", "+ slack", "- jira", "~ github (autostart)",
+		"Namespaces:", "+ ops", "~ work (serverIds)",
+		"Permissions:", "~ work/github.create_issue: allow -> deny",
+		"Settings:", "~ defaultNamespace",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-
-	// Deny multiple tools in one call
-	stdout, stderr, err := runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file", "move_file")
+	stdout, stderr, err = runCLI(testBinary, configPath, "diff", pathA, pathB, "--json")
 	if err != nil {
-		t.Fatalf("server deny-tool multiple failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("diff --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	var result config.ConfigDiff
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if !slices.Equal(result.Servers.Added, []string{"slack"}) || !slices.Equal(result.Servers.Removed, []string{"jira"}) {
+		t.Errorf("servers = %+v", result.Servers)
+	}
+	if !slices.Equal(result.Namespaces.Added, []string{"ops"}) || len(result.Namespaces.Changed) != 1 {
+		t.Errorf("namespaces = %+v", result.Namespaces)
+	}
+	if len(result.Permissions.Changed) != 1 || result.Permissions.Changed[0].To {
+		t.Errorf("permissions = %+v", result.Permissions)
 	}
 
-	cfg, _ := config.LoadFrom(configPath)
-	srv, _ := cfg.GetServer("my-server")
-	if len(srv.DeniedTools) != 2 {
-		t.Errorf("expected 2 denied tools, got %d: %v", len(srv.DeniedTools), srv.DeniedTools)
+	// Identical configs
+	stdout, _, err = runCLI(testBinary, configPath, "diff", pathA, pathA)
+	if err != nil || !strings.Contains(stdout, "No differences") {
+		t.Errorf("expected no differences, got err=%v output: %s", err, stdout)
+	}
+
+	if _, _, err := runCLI(testBinary, configPath, "diff", pathA, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing config file")
 	}
 }
 
-func TestCLI_Server_AllowTool(t *testing.T) {
+func TestCLI_Export_Env(t *testing.T) {
 	t.Parallel()
-	configPath := setupTestConfig(t)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfgJSON := `{"schemaVersion": 1, "servers": {
+		"github": {"url": "https://api.github.example/mcp", "bearer_token_env_var": "MCPMU_EXPORT_GITHUB_TOKEN"},
+		"figma": {"url": "https://figma.example/mcp", "env_http_headers": {"X-Api-Key": "MCPMU_EXPORT_FIGMA_KEY", "X-Team": "MCPMU_EXPORT_FIGMA_TEAM"}},
+		"ci": {"url": "https://ci.example/mcp", "bearer_token_env_var": "MCPMU_EXPORT_CI_TOKEN,MCPMU_EXPORT_API_TOKEN"},
+		"local": {"command": "echo", "env": {"SECRET": "inline"}}}}`
+	if err := os.WriteFile(configPath, []byte(cfgJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(testBinary, "--config", configPath, "export", "--format", "env")
+	cmd.Env = append(os.Environ(),
+		"MCPMU_EXPORT_FIGMA_KEY=secret-value",
+		"MCPMU_EXPORT_FIGMA_TEAM=  ",
+		"MCPMU_EXPORT_API_TOKEN=other-secret",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("export failed: %v\n%s", err, out)
+	}
+	stdout := string(out)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	want := `# ci: bearer token (one of MCPMU_EXPORT_CI_TOKEN, MCPMU_EXPORT_API_TOKEN) - set via MCPMU_EXPORT_API_TOKEN
+MCPMU_EXPORT_CI_TOKEN=
+MCPMU_EXPORT_API_TOKEN=
+# figma: header X-Api-Key - set
+MCPMU_EXPORT_FIGMA_KEY=
+# figma: header X-Team - missing
+MCPMU_EXPORT_FIGMA_TEAM=
+# github: bearer token - missing
+MCPMU_EXPORT_GITHUB_TOKEN=
 
-	// Allow the tool
-	stdout, stderr, err := runCLI(testBinary, configPath, "server", "allow-tool", "my-server", "delete_file")
-	if err != nil {
-		t.Fatalf("server allow-tool failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+# 2 of 4 required variable(s) missing
+`
+	if stdout != want {
+		t.Errorf("unexpected export output:\n%s\nwant:\n%s", stdout, want)
+	}
+	if strings.Contains(stdout, "secret") || strings.Contains(stdout, "SECRET") {
+		t.Error("export must not print values or stdio env")
 	}
 
-	cfg, _ := config.LoadFrom(configPath)
-	srv, _ := cfg.GetServer("my-server")
-	if srv.IsToolDenied("delete_file") {
-		t.Error("expected delete_file to no longer be denied")
+	if _, _, err := runCLI(testBinary, configPath, "export", "--format", "yaml"); err == nil {
+		t.Error("expected error for unsupported format")
 	}
 }
 
-func TestCLI_Server_AllowTool_NotDenied(t *testing.T) {
-	t.Parallel()
-	configPath := setupTestConfig(t)
+func TestServeOptions_ReloadDebounce(t *testing.T) {
+	flag := serveCmd.Flags().Lookup("reload-debounce")
+	t.Cleanup(func() {
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	})
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
+	opts := serveOptions(&config.Config{}, "", nil, server.DuplicateIDsQueue, server.MaxToolsError)
+	if opts.DebounceDelay != server.DefaultDebounceDelay {
+		t.Errorf("default DebounceDelay = %v, want %v", opts.DebounceDelay, server.DefaultDebounceDelay)
+	}
 
-	// Allow a tool that isn't denied (no-op, no error)
-	_, _, err := runCLI(testBinary, configPath, "server", "allow-tool", "my-server", "read_file")
-	if err != nil {
-		t.Fatalf("allow-tool on non-denied tool should not error: %v", err)
+	if err := serveCmd.ParseFlags([]string{"--reload-debounce", "750ms"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	opts = serveOptions(&config.Config{}, "", nil, server.DuplicateIDsQueue, server.MaxToolsError)
+	if opts.DebounceDelay != 750*time.Millisecond {
+		t.Errorf("DebounceDelay = %v, want 750ms", opts.DebounceDelay)
+	}
+
+	if err := serveCmd.ParseFlags([]string{"--reload-debounce", "soon"}); err == nil {
+		t.Error("expected an error for an invalid duration")
 	}
 }
 
-func TestCLI_Server_DeniedTools(t *testing.T) {
+func TestCLI_Serve_ReloadDebounceMustBePositive(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file", "move_file")
+	for _, value := range []string{"0s", "-1s"} {
+		_, stderr, err := runCLI(testBinary, configPath, "serve", "--reload-debounce", value)
+		if err == nil || !strings.Contains(stderr, "--reload-debounce must be positive") {
+			t.Errorf("--reload-debounce %s: err=%v stderr=%s", value, err, stderr)
+		}
+	}
+}
 
-	// List denied tools
-	stdout, stderr, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server")
+func TestCLI_Serve_StartupSummary(t *testing.T) {
+	t.Parallel()
+	configPath := setupTestConfig(t)
+
+	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
-		t.Fatalf("server denied-tools failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["files"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "read_file"}, {Name: "write_file"}},
+	})
+	cfg.Servers["clock"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "now"}},
+	})
+	cfg.Namespaces["work"] = config.NamespaceConfig{ServerIDs: []string{"files", "clock"}}
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	cmd := exec.Command(testBinary, "--config", configPath, "serve", "--stdio", "--namespace", "work")
+	cmd.Stdin = strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+	)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("serve failed: %v\nstderr: %s", err, stderr.String())
 	}
 
-	if !strings.Contains(stdout, "delete_file") {
-		t.Errorf("expected delete_file in output, got: %s", stdout)
+	var summary string
+	for line := range strings.SplitSeq(stderr.String(), "\n") {
+		if _, after, ok := strings.Cut(line, "Startup summary: "); ok {
+			if summary != "" {
+				t.Errorf("expected a single startup summary, got another: %s", line)
+			}
+			summary = after
+		}
 	}
-	if !strings.Contains(stdout, "move_file") {
-		t.Errorf("expected move_file in output, got: %s", stdout)
+	if summary == "" {
+		t.Fatalf("no startup summary in stderr:\n%s", stderr.String())
+	}
+
+	fields := strings.Split(summary, ", ")
+	want := []string{
+		"config " + configPath,
+		`namespace "work" (selection: flag)`,
+		"2 server(s)",
+		"lazy start",
+		"3 tools exposed",
+	}
+	if !slices.Equal(fields, want) {
+		t.Errorf("summary fields = %q, want %q", fields, want)
 	}
 }
 
-func TestCLI_Server_DeniedTools_JSON(t *testing.T) {
+func TestCLI_Serve_DryRun(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-	_, _, _ = runCLI(testBinary, configPath, "server", "deny-tool", "my-server", "delete_file")
+	// Commands that can't run: the dry run must not try to start them
+	missing := filepath.Join(t.TempDir(), "no-such-server")
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Servers["files"] = config.ServerConfig{Command: missing}
+	cfg.Servers["clock"] = config.ServerConfig{Command: missing}
+	cfg.Servers["remote"] = config.ServerConfig{URL: "https://example.invalid/mcp"}
+	cfg.Namespaces["work"] = config.NamespaceConfig{ServerIDs: []string{"files", "clock", "remote"}}
+	cfg.ToolPermissions = []config.ToolPermission{
+		{Namespace: "work", Server: "files", ToolName: "write_file", Enabled: false},
+	}
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
 
-	stdout, stderr, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server", "--json")
+	cache, err := config.NewToolCache(configPath)
 	if err != nil {
-		t.Fatalf("server denied-tools --json failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		t.Fatalf("NewToolCache: %v", err)
+	}
+	if err := cache.Update("files", []config.CachedToolInput{{Name: "read_file"}, {Name: "write_file"}}); err != nil {
+		t.Fatalf("cache files: %v", err)
+	}
+	if err := cache.Update("clock", []config.CachedToolInput{{Name: "now"}}); err != nil {
+		t.Fatalf("cache clock: %v", err)
 	}
 
-	var tools []string
-	if err := json.Unmarshal([]byte(stdout), &tools); err != nil {
-		t.Fatalf("invalid JSON output: %v\nraw: %s", err, stdout)
+	stdout, stderr, err := runCLI(testBinary, configPath, "serve", "--dry-run", "--namespace", "work", "--eager")
+	if err != nil {
+		t.Fatalf("serve --dry-run failed: %v\nstderr: %s", err, stderr)
+	}
+
+	for _, want := range []string{
+		"Namespace: work (selection: flag)",
+		"files   stdio      eager     1 (1 denied)",
+		"clock   stdio      eager     1",
+		"remote  http       eager     not cached",
+		"2 tool(s) exposed:\n  files.read_file\n  clock.now\n",
+		"1 server(s) have no cached tools",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, stdout)
+		}
 	}
-	if len(tools) != 1 || tools[0] != "delete_file" {
-		t.Errorf("expected [\"delete_file\"], got %v", tools)
+	if strings.Contains(stdout, "write_file") {
+		t.Errorf("denied tool listed in dry run:\n%s", stdout)
+	}
+	if strings.Contains(stderr, "Failed to start") || strings.Contains(stderr, "no-such-server") {
+		t.Errorf("dry run tried to start a server:\n%s", stderr)
+	}
+
+	// An unknown namespace fails the dry run
+	if _, _, err := runCLI(testBinary, configPath, "serve", "--dry-run", "--namespace", "nope"); err == nil {
+		t.Error("expected serve --dry-run with an unknown namespace to fail")
 	}
 }
 
-func TestCLI_Server_DeniedTools_Empty(t *testing.T) {
+// ============================================================================
+// Last-used Namespace CLI Tests
+// ============================================================================
+
+func TestCLI_Serve_Inspect(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "my-server", "--", "echo", "hello")
-
-	stdout, _, err := runCLI(testBinary, configPath, "server", "denied-tools", "my-server")
+	cfg, err := config.LoadFrom(configPath)
 	if err != nil {
-		t.Fatalf("server denied-tools (empty) failed: %v", err)
+		t.Fatalf("load config: %v", err)
 	}
-
-	if !strings.Contains(stdout, "No denied tools") {
-		t.Errorf("expected 'No denied tools' message, got: %s", stdout)
+	cfg.Servers["files"] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools:         []mcptest.Tool{{Name: "read_file"}},
+		EchoToolCalls: true,
+	})
+	if err := config.SaveTo(cfg, configPath); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	script := strings.Join([]string{
+		"tools/list",
+		`tools/call files.read_file {"path": "/tmp/a.txt"}`,
+		"tools/call files.read_file {not json",
+		"bogus/method",
+		"quit",
+		"tools/list",
+	}, "\n")
+	cmd := exec.Command(testBinary, "--config", configPath, "serve", "--inspect")
+	cmd.Stdin = strings.NewReader(script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("serve --inspect failed: %v\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		"Connected to mcpmu",
+		`"name": "files.read_file"`,
+		`Called tool: read_file\nArguments: {\"path\":\"/tmp/a.txt\"}`,
+		"Invalid JSON arguments",
+		"Error -32601: Method not found: bogus/method",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %q in the session, got:\n%s", want, out)
+		}
+	}
+	// Nothing after quit runs
+	if n := strings.Count(string(out), `"name": "files.read_file"`); n != 1 {
+		t.Errorf("expected one tools/list before quit, got %d:\n%s", n, out)
 	}
 }
 
-func TestCLI_Server_DenyTool_NormalizesQualifiedName(t *testing.T) {
+func TestCLI_Serve_SelectRecordsLastUsed(t *testing.T) {
 	t.Parallel()
 	configPath := setupTestConfig(t)
 
-	_, _, _ = runCLI(testBinary, configPath, "add", "filesystem", "--", "echo", "hello")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "home")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "add", "work")
+	_, _, _ = runCLI(testBinary, configPath, "namespace", "default", "home")
 
-	// Deny using qualified name (as copied from tools/list)
-	_, _, err := runCLI(testBinary, configPath, "server", "deny-tool", "filesystem", "filesystem.delete_file")
+	// serve exits as soon as stdin (empty) is closed
+	stdout, stderr, err := runCLI(testBinary, configPath, "serve", "--select", "work")
 	if err != nil {
-		t.Fatalf("deny-tool with qualified name failed: %v", err)
+		t.Fatalf("serve --select failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
 
-	cfg, _ := config.LoadFrom(configPath)
-	srv, _ := cfg.GetServer("filesystem")
-	// Should be stored as unqualified "delete_file", not "filesystem.delete_file"
-	if srv.IsToolDenied("filesystem.delete_file") {
-		t.Error("should not store qualified name verbatim")
+	st, err := config.LoadState(configPath)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
 	}
-	if !srv.IsToolDenied("delete_file") {
-		t.Error("expected unqualified 'delete_file' to be denied after normalization")
+	if st.LastUsedNamespace != "work" {
+		t.Errorf("LastUsedNamespace = %q, want work", st.LastUsedNamespace)
+	}
+	cfg, err := config.LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DefaultNamespace != "home" {
+		t.Errorf("DefaultNamespace = %q, want it left as home", cfg.DefaultNamespace)
 	}
-}
 
-func TestCLI_Server_DenyTool_ServerNotFound(t *testing.T) {
-	t.Parallel()
-	configPath := setupTestConfig(t)
+	stdout, _, _ = runCLI(testBinary, configPath, "namespace", "list", "--json")
+	var namespaces []struct {
+		Name       string `json:"name"`
+		IsDefault  bool   `json:"isDefault"`
+		IsLastUsed bool   `json:"isLastUsed"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &namespaces); err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+	for _, ns := range namespaces {
+		if ns.IsDefault != (ns.Name == "home") || ns.IsLastUsed != (ns.Name == "work") {
+			t.Errorf("namespace %s: isDefault=%v isLastUsed=%v", ns.Name, ns.IsDefault, ns.IsLastUsed)
+		}
+	}
 
-	_, _, err := runCLI(testBinary, configPath, "server", "deny-tool", "nonexistent", "tool")
-	if err == nil {
-		t.Fatal("expected error for non-existent server")
+	_, stderr, err = runCLI(testBinary, configPath, "serve", "--last-used")
+	if err != nil {
+		t.Fatalf("serve --last-used failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(stderr, `Using last-used namespace "work"`) {
+		t.Errorf("expected serve --last-used to pick work, stderr:\n%s", stderr)
+	}
+
+	// Unknown namespaces are rejected and not recorded
+	if _, _, err := runCLI(testBinary, configPath, "serve", "--select", "nope"); err == nil {
+		t.Error("expected serve --select with an unknown namespace to fail")
+	}
+	if st, _ := config.LoadState(configPath); st.LastUsedNamespace != "work" {
+		t.Errorf("LastUsedNamespace = %q after failed select, want work", st.LastUsedNamespace)
 	}
 }
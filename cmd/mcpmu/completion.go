@@ -15,18 +15,26 @@ func init() {
 	// MCP commands (only HTTP servers are valid)
 	mcpLoginCmd.ValidArgsFunction = completeHTTPServerNames
 	mcpLogoutCmd.ValidArgsFunction = completeHTTPServerNames
+	mcpCredentialStoreCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return []string{"auto", "keyring", "file", "pass", "env"}, cobra.ShellCompDirectiveNoFileComp
+	}
 
 	// Namespace commands (single arg: namespace name)
 	namespaceRemoveCmd.ValidArgsFunction = completeNamespaceNames
 	namespaceDefaultCmd.ValidArgsFunction = completeNamespaceNames
 	namespaceRenameCmd.ValidArgsFunction = completeNamespaceNames
+	namespaceToolsCmd.ValidArgsFunction = completeNamespaceNames
 
 	// Namespace commands (namespace + server)
 	namespaceAssignCmd.ValidArgsFunction = completeNamespaceThenServer
 	namespaceUnassignCmd.ValidArgsFunction = completeNamespaceThenServer
 
-	// Namespace set-deny-default (namespace + true/false)
+	// Namespace set-deny-default/set-show-denied (namespace + true/false)
 	namespaceSetDenyDefaultCmd.ValidArgsFunction = completeNamespaceThenBool
+	namespaceSetShowDeniedCmd.ValidArgsFunction = completeNamespaceThenBool
 
 	// Permission commands
 	permissionListCmd.ValidArgsFunction = completeNamespaceNames
@@ -34,6 +42,9 @@ func init() {
 	permissionUnsetCmd.ValidArgsFunction = completePermissionUnsetArgs
 	permissionSetServerDefaultCmd.ValidArgsFunction = completePermissionServerDefaultArgs
 	permissionUnsetServerDefaultCmd.ValidArgsFunction = completeNamespaceThenServer
+	permissionCheckCmd.ValidArgsFunction = completeNamespaceNames
+	permissionExportCmd.ValidArgsFunction = completeNamespaceNames
+	permissionImportCmd.ValidArgsFunction = completeNamespaceThenFile
 
 	// Flag completions
 	_ = serveCmd.RegisterFlagCompletionFunc("namespace", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -42,6 +53,26 @@ func init() {
 	_ = serveCmd.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	for _, c := range []*cobra.Command{rootCmd, tuiCmd} {
+		_ = c.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+	_ = serveCmd.RegisterFlagCompletionFunc("duplicate-ids", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"queue", "reject"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = mcpCredentialStoreCmd.RegisterFlagCompletionFunc("encryption", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"none", "keyring", "passphrase"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = topCmd.RegisterFlagCompletionFunc("namespace", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return namespaceNames(cmd), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = importCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"mcpmu"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = importCmd.RegisterFlagCompletionFunc("on-conflict", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"fail", "skip", "overwrite"}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 // loadConfigForCompletion loads config silently for shell completion.
@@ -128,6 +159,17 @@ func completeNamespaceNames(cmd *cobra.Command, args []string, toComplete string
 	return namespaceNames(cmd), cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeNamespaceThenFile completes namespace (arg 0) then a file path.
+func completeNamespaceThenFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return namespaceNames(cmd), cobra.ShellCompDirectiveNoFileComp
+	}
+	if len(args) == 1 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
 // completeNamespaceThenServer completes namespace (arg 0) then server (arg 1).
 func completeNamespaceThenServer(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	switch len(args) {
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the config file in effect",
+	Long:  `Inspect which config file mcpmu uses and what it contains.`,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configShowCmd)
+}
+
+// ============================================================================
+// config path
+// ============================================================================
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the resolved config file path",
+	Long: `Print the path of the config file mcpmu reads and writes.
+
+The path is taken from --config if given, otherwise from the MCPMU_CONFIG
+environment variable, otherwise config.json in the --config-dir or
+MCPMU_HOME directory, otherwise ~/.config/mcpmu/config.json.
+
+Examples:
+  mcpmu config path
+  mcpmu config path --config ./project.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigPrintPath,
+}
+
+func runConfigPrintPath(cmd *cobra.Command, args []string) error {
+	path, err := resolveConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+// ============================================================================
+// config show
+// ============================================================================
+
+var (
+	configShowJSON   bool
+	configShowReveal bool
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the loaded config",
+	Long: `Print the loaded config in a readable form, or as JSON with --json.
+
+Secret-looking values (env vars such as API_KEY or *_TOKEN, secret flags in
+args, credentials in URLs, sensitive headers and OAuth client secrets) are
+masked unless --reveal is given.
+
+Examples:
+  mcpmu config show
+  mcpmu config show --json
+  mcpmu config show --json --reveal`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "Output the config as JSON")
+	configShowCmd.Flags().BoolVar(&configShowReveal, "reveal", false, "Show secret values instead of masking them")
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	path, err := resolveConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if !configShowReveal {
+		maskConfigSecrets(cfg)
+	}
+
+	if configShowJSON {
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printConfig(path, cfg)
+	return nil
+}
+
+// maskConfigSecrets replaces secret-looking values in cfg in place.
+func maskConfigSecrets(cfg *config.Config) {
+	for name, srv := range cfg.Servers {
+		srv.Env = redact.Env(srv.Env)
+		srv.Args = redact.Args(srv.Args)
+		srv.URL = redact.URL(srv.URL)
+		srv.Proxy = redact.URL(srv.Proxy)
+		srv.HTTPHeaders = redact.Env(srv.HTTPHeaders)
+		if srv.OAuth != nil && srv.OAuth.ClientSecret != "" {
+			oauth := *srv.OAuth
+			oauth.ClientSecret = redact.Mask
+			srv.OAuth = &oauth
+		}
+		cfg.Servers[name] = srv
+	}
+}
+
+func printConfig(path string, cfg *config.Config) {
+	fmt.Printf("Config: %s\n", path)
+
+	servers := cfg.ServerEntries()
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+	fmt.Printf("\nServers (%d):\n", len(servers))
+	for _, entry := range servers {
+		srv := entry.Config
+		enabled := ""
+		if !srv.IsEnabled() {
+			enabled = " (disabled)"
+		}
+		fmt.Printf("  %s [%s]%s\n", entry.Name, formatKind(srv), enabled)
+		if srv.IsHTTP() {
+			fmt.Printf("    url: %s\n", srv.URL)
+			fmt.Printf("    auth: %s\n", getAuthType(srv))
+			if srv.Proxy != "" {
+				fmt.Printf("    proxy: %s\n", srv.Proxy)
+			}
+		} else {
+			fmt.Printf("    command: %s\n", formatCommand(srv))
+		}
+		if srv.Cwd != "" {
+			fmt.Printf("    cwd: %s\n", srv.Cwd)
+		}
+		printSortedMap("env", srv.Env)
+		printSortedMap("headers", srv.HTTPHeaders)
+		if srv.OAuth != nil && srv.OAuth.ClientSecret != "" {
+			fmt.Printf("    oauth client secret: %s\n", srv.OAuth.ClientSecret)
+		}
+		if len(srv.DeniedTools) > 0 {
+			fmt.Printf("    denied tools: %s\n", strings.Join(srv.DeniedTools, ", "))
+		}
+		if srv.Trusted {
+			fmt.Println("    trusted")
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Namespaces))
+	for name := range cfg.Namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("\nNamespaces (%d):\n", len(names))
+	for _, name := range names {
+		ns := cfg.Namespaces[name]
+		var flags []string
+		if name == cfg.DefaultNamespace {
+			flags = append(flags, "default")
+		}
+		if ns.DenyByDefault {
+			flags = append(flags, "deny-by-default")
+		}
+		suffix := ""
+		if len(flags) > 0 {
+			suffix = " (" + strings.Join(flags, ", ") + ")"
+		}
+		servers := "(none)"
+		if len(ns.ServerIDs) > 0 {
+			servers = strings.Join(ns.ServerIDs, ", ")
+		}
+		fmt.Printf("  %s%s: %s\n", name, suffix, servers)
+	}
+
+	fmt.Printf("\nTool permissions: %d\n", len(cfg.ToolPermissions))
+}
+
+func printSortedMap(label string, m map[string]string) {
+	if len(m) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("    %s:\n", label)
+	for _, k := range keys {
+		fmt.Printf("      %s=%s\n", k, m[k])
+	}
+}
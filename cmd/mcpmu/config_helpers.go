@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
 )
 
 func loadConfig(configPath string) (*config.Config, error) {
@@ -24,6 +27,52 @@ func loadConfig(configPath string) (*config.Config, error) {
 	return cfg, nil
 }
 
+// resolveConfigPath expands ~ in a user-provided config path, falling back
+// to the default config path when empty.
+func resolveConfigPath(configPath string) (string, error) {
+	if configPath == "" {
+		path, err := config.ConfigPath()
+		if err != nil {
+			return "", fmt.Errorf("failed to get config path: %w", err)
+		}
+		return path, nil
+	}
+	if strings.HasPrefix(configPath, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home dir: %w", err)
+		}
+		return filepath.Join(home, configPath[2:]), nil
+	}
+	return configPath, nil
+}
+
+// addYesFlag registers the --yes/-y flag destructive commands use to skip
+// their confirmation prompt.
+func addYesFlag(cmd *cobra.Command, yes *bool) {
+	cmd.Flags().BoolVarP(yes, "yes", "y", false, "Skip confirmation prompt")
+}
+
+// confirmDestructive asks before a destructive action, described in lower
+// case (e.g. `remove server "fs"`), unless yes is set. Without a terminal
+// there is nobody to ask, so it fails rather than guessing; scripts must
+// pass --yes.
+func confirmDestructive(action string, yes bool) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("refusing to %s without confirmation: stdin is not a terminal (pass --yes to proceed)", action)
+	}
+	return confirmAction(strings.ToUpper(action[:1]) + action[1:] + "?")
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 func confirmAction(msg string) (bool, error) {
 	fmt.Printf("%s [y/N] ", msg)
 	reader := bufio.NewReader(os.Stdin)
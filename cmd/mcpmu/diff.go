@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var diffJSON bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <config-a> <config-b>",
+	Short: "Compare two config files",
+	Long: `Compare two mcpmu config files and report what changed from the first to
+the second: servers, namespaces and tool permissions added (+), removed (-)
+or changed (~), plus any top-level settings that differ.
+
+The files are compared as parsed configs, so key order and formatting don't
+matter. Changed servers and namespaces list the fields that differ without
+their values, so secrets in env or headers are never printed. lastModified is
+ignored.
+
+Examples:
+  mcpmu diff ~/.config/mcpmu/config.json team-template.json
+  mcpmu diff old.json new.json --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	a, err := loadDiffConfig(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := loadDiffConfig(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := a.Diff(b)
+
+	if diffJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if result.Empty() {
+		fmt.Printf("No differences between %s and %s\n", args[0], args[1])
+		return nil
+	}
+
+	printEntryDiff("Servers:", result.Servers)
+	printEntryDiff("Namespaces:", result.Namespaces)
+
+	perms := result.Permissions
+	if len(perms.Added) > 0 || len(perms.Removed) > 0 || len(perms.Changed) > 0 {
+		fmt.Println("Permissions:")
+		for _, p := range perms.Added {
+			fmt.Printf("  + %s/%s.%s (%s)\n", p.Namespace, p.Server, p.ToolName, accessLabel(p.Enabled))
+		}
+		for _, p := range perms.Removed {
+			fmt.Printf("  - %s/%s.%s (%s)\n", p.Namespace, p.Server, p.ToolName, accessLabel(p.Enabled))
+		}
+		for _, p := range perms.Changed {
+			fmt.Printf("  ~ %s/%s.%s: %s -> %s\n", p.Namespace, p.Server, p.ToolName, accessLabel(p.From), accessLabel(p.To))
+		}
+		fmt.Println()
+	}
+
+	if len(result.Settings) > 0 {
+		fmt.Println("Settings:")
+		for _, name := range result.Settings {
+			fmt.Printf("  ~ %s\n", name)
+		}
+	}
+	return nil
+}
+
+// loadDiffConfig loads one side of a diff. Unlike loadConfig, a missing file
+// is an error rather than an empty config.
+func loadDiffConfig(arg string) (*config.Config, error) {
+	path, err := resolveConfigPath(arg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	cfg, err := config.LoadFrom(path)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", arg, err)
+	}
+	return cfg, nil
+}
+
+func printEntryDiff(title string, d config.EntryDiff) {
+	if len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 {
+		return
+	}
+	fmt.Println(title)
+	for _, name := range d.Added {
+		fmt.Printf("  + %s\n", name)
+	}
+	for _, name := range d.Removed {
+		fmt.Printf("  - %s\n", name)
+	}
+	for _, entry := range d.Changed {
+		fmt.Printf("  ~ %s (%s)\n", entry.Name, strings.Join(entry.Fields, ", "))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var disableReason string
+
+var enableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable an MCP server",
+	Long: `Enable a disabled MCP server, clearing any disabled reason.
+
+Examples:
+  mcpmu enable my-server`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnable,
+}
+
+var disableCmd = &cobra.Command{
+	Use:   "disable <name> [--reason <text>]",
+	Short: "Disable an MCP server",
+	Long: `Disable an MCP server without removing it from the configuration.
+
+Disabled servers are never started. --reason records why, so the note shows
+up in the TUI and in serve's logs until the server is enabled again.
+
+Examples:
+  mcpmu disable my-server
+  mcpmu disable my-server --reason "rate limited until Monday"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDisable,
+}
+
+func init() {
+	disableCmd.Flags().StringVar(&disableReason, "reason", "", "Why the server is disabled")
+
+	rootCmd.AddCommand(enableCmd)
+	rootCmd.AddCommand(disableCmd)
+}
+
+func runEnable(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	srv, ok := cfg.GetServer(name)
+	if !ok {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	srv.SetEnabled(true)
+	cfg.Servers[name] = srv
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Enabled server %q\n", name)
+	return nil
+}
+
+func runDisable(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	srv, ok := cfg.GetServer(name)
+	if !ok {
+		return fmt.Errorf("server %q not found", name)
+	}
+
+	srv.Disable(disableReason)
+	cfg.Servers[name] = srv
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+
+	if srv.DisabledReason != "" {
+		fmt.Printf("Disabled server %q (%s)\n", name, srv.DisabledReason)
+	} else {
+		fmt.Printf("Disabled server %q\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat     string
+	exportConfigPath string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export --format env",
+	Short: "List the environment variables the config expects",
+	Long: `List the environment variables the config reads secrets from.
+
+With --format env, every bearer_token_env_var and env_http_headers variable of
+the configured HTTP servers is printed as an empty NAME= line, preceded by a
+comment naming the server and whether the variable is currently set or
+missing. The output can be saved as a .env template or used as a checklist
+of CI secrets when moving a config to a new machine. Values are never
+printed.
+
+A bearer_token_env_var listing several names needs only one of them; its
+variables are grouped under one comment.
+
+Examples:
+  mcpmu export --format env
+  mcpmu export --format env > .env.example`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "env", "Output format (env)")
+	exportCmd.Flags().StringVarP(&exportConfigPath, "config", "c", "", "Path to config file")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+// envRequirement is a secret a server reads from the environment. Vars has
+// more than one entry for a bearer token with fallbacks; any one of them
+// satisfies it.
+type envRequirement struct {
+	Server  string
+	Purpose string
+	Vars    []string
+	SetVar  string // first variable in Vars that is set, if any
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "env" {
+		return fmt.Errorf("unsupported export format %q (supported: env)", exportFormat)
+	}
+
+	cfg, err := loadConfig(exportConfigPath)
+	if err != nil {
+		return err
+	}
+
+	reqs := collectEnvRequirements(cfg)
+	if len(reqs) == 0 {
+		fmt.Println("# No environment variables required")
+		return nil
+	}
+
+	missing := 0
+	for _, req := range reqs {
+		status := "set"
+		switch {
+		case req.SetVar == "":
+			status = "missing"
+			missing++
+		case len(req.Vars) > 1:
+			status = "set via " + req.SetVar
+		}
+		purpose := req.Purpose
+		if len(req.Vars) > 1 {
+			purpose += " (one of " + strings.Join(req.Vars, ", ") + ")"
+		}
+		fmt.Printf("# %s: %s - %s\n", req.Server, purpose, status)
+		for _, name := range req.Vars {
+			fmt.Printf("%s=\n", name)
+		}
+	}
+	fmt.Printf("\n# %d of %d required variable(s) missing\n", missing, len(reqs))
+	return nil
+}
+
+// collectEnvRequirements lists the environment variables read by the
+// config's HTTP servers, ordered by server name. A variable counts as set
+// when it is non-blank, matching how the supervisor resolves it.
+func collectEnvRequirements(cfg *config.Config) []envRequirement {
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var reqs []envRequirement
+	for _, name := range names {
+		srv := cfg.Servers[name]
+		if !srv.IsHTTP() {
+			continue
+		}
+
+		if strings.TrimSpace(srv.BearerTokenEnvVar) != "" {
+			var vars []string
+			for v := range strings.SplitSeq(srv.BearerTokenEnvVar, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					vars = append(vars, v)
+				}
+			}
+			reqs = append(reqs, newEnvRequirement(name, "bearer token", vars))
+		}
+
+		headers := make([]string, 0, len(srv.EnvHTTPHeaders))
+		for header := range srv.EnvHTTPHeaders {
+			headers = append(headers, header)
+		}
+		slices.Sort(headers)
+		for _, header := range headers {
+			reqs = append(reqs, newEnvRequirement(name, "header "+header, []string{srv.EnvHTTPHeaders[header]}))
+		}
+	}
+	return reqs
+}
+
+func newEnvRequirement(server, purpose string, vars []string) envRequirement {
+	req := envRequirement{Server: server, Purpose: purpose, Vars: vars}
+	for _, v := range vars {
+		if val, ok := os.LookupEnv(v); ok && strings.TrimSpace(val) != "" {
+			req.SetVar = v
+			break
+		}
+	}
+	return req
+}
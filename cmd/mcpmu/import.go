@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFrom       string
+	importOnConflict string
+	importNoServers  bool
+	importConfigPath string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --from mcpmu <file>",
+	Short: "Merge servers, namespaces and permissions from another config",
+	Long: `Merge another config file into this one.
+
+With --from mcpmu, the file is another mcpmu config.json. Its servers,
+namespaces and tool permissions are merged in, which makes it easy to share
+namespace and permission templates across machines or teams. The default
+namespace and top-level settings are not imported.
+
+Entries identical to existing ones are ignored. When an entry differs from an
+existing one with the same name, --on-conflict decides what happens:
+  fail       abort without changing anything (default)
+  skip       keep the existing entry
+  overwrite  replace it with the imported entry
+
+Imported namespaces and permissions must reference servers and namespaces
+that exist after the merge.
+
+Examples:
+  mcpmu import --from mcpmu team-template.json
+  mcpmu import --from mcpmu team-template.json --no-servers
+  mcpmu import --from mcpmu backup.json --on-conflict overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", "", "Source format (mcpmu)")
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", "fail", "What to do with conflicting entries: fail, skip or overwrite")
+	importCmd.Flags().BoolVar(&importNoServers, "no-servers", false, "Import only namespaces and tool permissions")
+	importCmd.Flags().StringVarP(&importConfigPath, "config", "c", "", "Path to config file")
+	_ = importCmd.MarkFlagRequired("from")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	if importFrom != "mcpmu" {
+		return fmt.Errorf("unsupported import source %q (supported: mcpmu)", importFrom)
+	}
+	policy, err := config.ParseMergePolicy(importOnConflict)
+	if err != nil {
+		return err
+	}
+
+	path, err := resolveConfigPath(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("read import file: %w", err)
+	}
+	src, err := config.LoadFrom(path)
+	if err != nil {
+		return fmt.Errorf("load import file: %w", err)
+	}
+
+	cfg, err := loadConfig(importConfigPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := cfg.Merge(src, config.MergeOptions{Policy: policy, SkipServers: importNoServers})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Added) == 0 && len(result.Updated) == 0 {
+		fmt.Println("Nothing to import")
+	} else if err := saveConfig(cfg, importConfigPath); err != nil {
+		return err
+	}
+
+	for _, entry := range result.Added {
+		fmt.Printf("  + %s\n", entry)
+	}
+	for _, entry := range result.Updated {
+		fmt.Printf("  ~ %s\n", entry)
+	}
+	for _, entry := range result.Skipped {
+		fmt.Printf("  = %s (kept existing)\n", entry)
+	}
+	if len(result.Added) > 0 || len(result.Updated) > 0 {
+		fmt.Printf("Imported %d new and %d updated entries from %s\n", len(result.Added), len(result.Updated), args[0])
+	}
+	return nil
+}
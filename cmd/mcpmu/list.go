@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
 	"github.com/spf13/cobra"
 )
 
 var (
 	listJSON       bool
+	listStatus     bool
+	listNoStart    bool
 	listConfigPath string
 )
 
@@ -22,20 +33,35 @@ var listCmd = &cobra.Command{
 
 By default, outputs a human-readable table. Use --json for machine-readable output.
 
+With --status, each enabled server is started briefly to report its live
+state, tool count and auth status in the JSON output. Add --no-start to skip
+starting servers and report tool counts from the tool cache instead.
+
 Examples:
   mcpmu list
-  mcpmu list --json`,
+  mcpmu list --json
+  mcpmu list --json --status
+  mcpmu list --json --status --no-start`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON")
+	listCmd.Flags().BoolVar(&listStatus, "status", false, "Include live server status in JSON output (starts servers briefly)")
+	listCmd.Flags().BoolVar(&listNoStart, "no-start", false, "With --status, report cached tool counts without starting servers")
 	listCmd.Flags().StringVarP(&listConfigPath, "config", "c", "", "Path to config file (default: ~/.config/mcpmu/config.json)")
 
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if listStatus && !listJSON {
+		return fmt.Errorf("--status requires --json")
+	}
+	if listNoStart && !listStatus {
+		return fmt.Errorf("--no-start requires --status")
+	}
+
 	// Load config
 	cfg, err := loadConfig(listConfigPath)
 	if err != nil {
@@ -49,12 +75,149 @@ func runList(cmd *cobra.Command, args []string) error {
 	})
 
 	if listJSON {
-		return outputJSON(servers)
+		var statuses map[string]*serverStatusView
+		if listStatus {
+			if listNoStart {
+				statuses, err = cachedServerStatuses(listConfigPath, servers)
+			} else {
+				statuses = probeServerStatuses(cfg, listConfigPath, servers)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return outputJSON(servers, statuses)
 	}
 	return outputTable(servers)
 }
 
-func outputJSON(servers []config.ServerEntry) error {
+// serverStatusView is the runtime status merged into `list --json --status`.
+type serverStatusView struct {
+	State           string `json:"state"`
+	ToolCount       *int   `json:"toolCount,omitempty"`
+	ToolsFrom       string `json:"toolsFrom,omitempty"`
+	AuthStatus      string `json:"authStatus,omitempty"`
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Status states reported by `list --status`.
+const (
+	listStateRunning  = "running"
+	listStateError    = "error"
+	listStateDisabled = "disabled"
+	listStateCached   = "cached"
+	listStateUncached = "uncached"
+)
+
+// cachedServerStatuses reports tool counts from the tool cache without
+// starting any servers.
+func cachedServerStatuses(configPath string, servers []config.ServerEntry) (map[string]*serverStatusView, error) {
+	toolCache, err := config.NewToolCache(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tool cache: %w", err)
+	}
+
+	statuses := make(map[string]*serverStatusView, len(servers))
+	for _, entry := range servers {
+		status := &serverStatusView{State: listStateUncached}
+		if tools, ok := toolCache.Get(entry.Name); ok {
+			count := len(tools)
+			status.State = listStateCached
+			status.ToolCount = &count
+			status.ToolsFrom = "cache"
+		}
+		if !entry.Config.IsEnabled() {
+			status.State = listStateDisabled
+		}
+		statuses[entry.Name] = status
+	}
+	return statuses, nil
+}
+
+// probeServerStatuses starts every enabled server concurrently, waits for
+// tool discovery (bounded by each server's startup timeout), records the
+// result and stops them all again.
+func probeServerStatuses(cfg *config.Config, configPath string, servers []config.ServerEntry) map[string]*serverStatusView {
+	supervisor, cleanup := newProbeSupervisor(cfg, configPath, "list")
+	defer cleanup()
+
+	statuses := make(map[string]*serverStatusView, len(servers))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, entry := range servers {
+		if !entry.Config.IsEnabled() {
+			statuses[entry.Name] = &serverStatusView{State: listStateDisabled}
+			continue
+		}
+		wg.Go(func() {
+			status := probeServer(supervisor, entry)
+			mu.Lock()
+			statuses[entry.Name] = status
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return statuses
+}
+
+// newProbeSupervisor returns a supervisor for briefly starting servers from
+// a one-shot command, and a cleanup func that stops them all. Logging is
+// discarded so it can't interleave with the command's output.
+func newProbeSupervisor(cfg *config.Config, configPath, pidFilePrefix string) (*process.Supervisor, func()) {
+	log.SetOutput(io.Discard)
+
+	var configDir string
+	if path, err := resolveConfigPath(configPath); err == nil {
+		configDir = filepath.Dir(path)
+	}
+
+	bus := events.NewBus()
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+		CredentialStoreMode:     cfg.MCPOAuthCredentialStore,
+		CredentialEncryption:    cfg.MCPOAuthCredentialEncryption,
+		GlobalOAuthCallbackPort: cfg.MCPOAuthCallbackPort,
+		PIDFilePrefix:           pidFilePrefix,
+		ConfigDir:               configDir,
+		MaxLogLines:             cfg.MaxLogLines,
+		InitRetries:             cfg.InitRetries,
+		InitRetryBackoff:        time.Duration(cfg.InitRetryBackoffMs) * time.Millisecond,
+	})
+	return supervisor, func() {
+		supervisor.StopAll()
+		bus.Close()
+	}
+}
+
+func probeServer(supervisor *process.Supervisor, entry config.ServerEntry) *serverStatusView {
+	timeout := time.Duration(entry.Config.StartupTimeout()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	handle, err := supervisor.Start(ctx, entry.Name, entry.Config)
+	if err != nil {
+		return &serverStatusView{State: listStateError, Error: redact.String(err.Error())}
+	}
+
+	status := &serverStatusView{
+		State:           listStateRunning,
+		AuthStatus:      string(handle.AuthStatus()),
+		ProtocolVersion: handle.ProtocolVersion(),
+	}
+	if err := handle.WaitForTools(ctx); err != nil {
+		status.State = listStateError
+		status.Error = redact.String(err.Error())
+		return status
+	}
+	count := len(handle.Tools())
+	status.ToolCount = &count
+	status.ToolsFrom = "live"
+	return status
+}
+
+func outputJSON(servers []config.ServerEntry, statuses map[string]*serverStatusView) error {
 	// Create a simplified view
 	type serverView struct {
 		Name      string            `json:"name"`
@@ -65,8 +228,10 @@ func outputJSON(servers []config.ServerEntry) error {
 		Cwd       string            `json:"cwd,omitempty"`
 		Env       map[string]string `json:"env,omitempty"`
 		Enabled   bool              `json:"enabled"`
+		Reason    string            `json:"disabledReason,omitempty"`
 		Autostart bool              `json:"autostart"`
 		Auth      string            `json:"auth,omitempty"`
+		Status    *serverStatusView `json:"status,omitempty"`
 	}
 
 	views := make([]serverView, len(servers))
@@ -80,8 +245,10 @@ func outputJSON(servers []config.ServerEntry) error {
 			Cwd:       entry.Config.Cwd,
 			Env:       entry.Config.Env,
 			Enabled:   entry.Config.IsEnabled(),
+			Reason:    entry.Config.DisabledReason,
 			Autostart: entry.Config.Autostart,
 			Auth:      getAuthType(entry.Config),
+			Status:    statuses[entry.Name],
 		}
 	}
 
@@ -148,6 +315,9 @@ func outputTable(servers []config.ServerEntry) error {
 		enabled := "yes"
 		if !entry.Config.IsEnabled() {
 			enabled = "no"
+			if entry.Config.DisabledReason != "" {
+				enabled += " (" + entry.Config.DisabledReason + ")"
+			}
 		}
 
 		auth := getAuthType(entry.Config)
@@ -5,13 +5,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/oauth"
 	"github.com/spf13/cobra"
 )
 
 var (
-	mcpConfigPath string
-	mcpScopes     []string
+	mcpConfigPath      string
+	mcpScopes          []string
+	mcpStoreEncryption string
 )
 
 var mcpCmd = &cobra.Command{
@@ -48,13 +50,42 @@ Examples:
 	RunE: runMCPLogout,
 }
 
+var mcpCredentialStoreCmd = &cobra.Command{
+	Use:   "credential-store [auto|keyring|file|pass|env]",
+	Short: "Show or set where OAuth credentials are stored",
+	Long: `Show the OAuth credential store backend, or set it in the config.
+
+Backends:
+  auto     System keychain if available, otherwise file (default)
+  keyring  System keychain (macOS Keychain, Secret Service/GNOME Keyring, Windows)
+  file     JSON file in the mcpmu directory
+  pass     The pass password manager, under mcpmu/ in the password store
+  env      Read-only seed from $MCPMU_OAUTH_CREDENTIALS; tokens live in memory only
+
+Credentials are not migrated when switching backends; log in again afterwards.
+
+The file backend (also used by auto without a keychain) can encrypt its
+file at rest with --encryption: keyring keeps a random key in the system
+keychain, passphrase derives the key from $MCPMU_CREDENTIALS_PASSPHRASE.
+An existing plaintext file is encrypted the next time credentials change.
+
+Examples:
+  mcpmu mcp credential-store
+  mcpmu mcp credential-store pass
+  mcpmu mcp credential-store file --encryption passphrase`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMCPCredentialStore,
+}
+
 func init() {
 	mcpCmd.PersistentFlags().StringVarP(&mcpConfigPath, "config", "c", "", "Path to config file")
 
 	mcpLoginCmd.Flags().StringSliceVar(&mcpScopes, "scopes", nil, "OAuth scopes to request (comma-separated)")
+	mcpCredentialStoreCmd.Flags().StringVar(&mcpStoreEncryption, "encryption", "", "Encrypt the file store at rest: none, keyring, or passphrase")
 
 	mcpCmd.AddCommand(mcpLoginCmd)
 	mcpCmd.AddCommand(mcpLogoutCmd)
+	mcpCmd.AddCommand(mcpCredentialStoreCmd)
 
 	rootCmd.AddCommand(mcpCmd)
 }
@@ -85,8 +116,7 @@ func runMCPLogin(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create credential store
-	storeMode := oauth.StoreMode(cfg.MCPOAuthCredentialStore)
-	store, err := oauth.NewCredentialStore(storeMode)
+	store, err := newCredentialStore(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create credential store: %w", err)
 	}
@@ -151,8 +181,7 @@ func runMCPLogout(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create credential store
-	storeMode := oauth.StoreMode(cfg.MCPOAuthCredentialStore)
-	store, err := oauth.NewCredentialStore(storeMode)
+	store, err := newCredentialStore(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create credential store: %w", err)
 	}
@@ -165,3 +194,70 @@ func runMCPLogout(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Logged out from %s\n", serverName)
 	return nil
 }
+
+func runMCPCredentialStore(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(mcpConfigPath)
+	if err != nil {
+		return err
+	}
+
+	encrypt := cmd.Flags().Changed("encryption")
+	if len(args) == 0 && !encrypt {
+		mode, err := oauth.ParseStoreMode(cfg.MCPOAuthCredentialStore)
+		if err != nil {
+			return err
+		}
+		enc, err := oauth.ParseEncryption(cfg.MCPOAuthCredentialEncryption)
+		if err != nil {
+			return err
+		}
+		if enc == oauth.EncryptionNone {
+			fmt.Println(mode)
+		} else {
+			fmt.Printf("%s (file encryption: %s)\n", mode, enc)
+		}
+		return nil
+	}
+
+	if len(args) > 0 {
+		mode, err := oauth.ParseStoreMode(args[0])
+		if err != nil {
+			return err
+		}
+		cfg.MCPOAuthCredentialStore = string(mode)
+	}
+	if encrypt {
+		enc, err := oauth.ParseEncryption(mcpStoreEncryption)
+		if err != nil {
+			return err
+		}
+		if enc == oauth.EncryptionNone {
+			cfg.MCPOAuthCredentialEncryption = ""
+		} else {
+			cfg.MCPOAuthCredentialEncryption = string(enc)
+		}
+	}
+	if err := saveConfig(cfg, mcpConfigPath); err != nil {
+		return err
+	}
+
+	mode, _ := oauth.ParseStoreMode(cfg.MCPOAuthCredentialStore)
+	fmt.Printf("OAuth credentials will be stored in: %s\n", mode)
+	if enc, _ := oauth.ParseEncryption(cfg.MCPOAuthCredentialEncryption); enc != oauth.EncryptionNone {
+		fmt.Printf("File store encryption: %s\n", enc)
+	}
+	return nil
+}
+
+// newCredentialStore opens the OAuth credential store, keeping the file store
+// in the mcpmu directory so it follows --config-dir.
+func newCredentialStore(cfg *config.Config) (oauth.CredentialStore, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return oauth.NewCredentialStoreWithOptions(oauth.StoreMode(cfg.MCPOAuthCredentialStore), oauth.StoreOptions{
+		Dir:        dir,
+		Encryption: oauth.Encryption(cfg.MCPOAuthCredentialEncryption),
+	})
+}
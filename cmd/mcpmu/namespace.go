@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
+	"github.com/Bigsy/mcpmu/internal/server"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +32,8 @@ Examples:
   mcpmu namespace add development --description "Dev environment"
   mcpmu namespace list
   mcpmu namespace assign development my-server
-  mcpmu namespace default development`,
+  mcpmu namespace default development
+  mcpmu namespace check development --start`,
 }
 
 func init() {
@@ -37,6 +47,13 @@ func init() {
 	namespaceCmd.AddCommand(namespaceUnassignCmd)
 	namespaceCmd.AddCommand(namespaceDefaultCmd)
 	namespaceCmd.AddCommand(namespaceSetDenyDefaultCmd)
+	namespaceCmd.AddCommand(namespaceSetStripPrefixCmd)
+	namespaceCmd.AddCommand(namespaceSetShowDeniedCmd)
+	namespaceCmd.AddCommand(namespaceSetDescriptionCmd)
+	namespaceCmd.AddCommand(namespaceSetServersCmd)
+	namespaceCmd.AddCommand(namespaceServersCmd)
+	namespaceCmd.AddCommand(namespaceToolsCmd)
+	namespaceCmd.AddCommand(namespaceCheckCmd)
 }
 
 // ============================================================================
@@ -129,13 +146,19 @@ func runNamespaceList(cmd *cobra.Command, args []string) error {
 		return namespaces[i].Name < namespaces[j].Name
 	})
 
+	// The last-used namespace (serve --select) lives in state.json, not the config
+	st, err := config.LoadState(namespaceListConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	if namespaceListJSON {
-		return outputNamespacesJSON(cfg, namespaces)
+		return outputNamespacesJSON(cfg, namespaces, st.LastUsedNamespace)
 	}
-	return outputNamespacesTable(cfg, namespaces)
+	return outputNamespacesTable(cfg, namespaces, st.LastUsedNamespace)
 }
 
-func outputNamespacesJSON(cfg *config.Config, namespaces []config.NamespaceEntry) error {
+func outputNamespacesJSON(cfg *config.Config, namespaces []config.NamespaceEntry, lastUsed string) error {
 	type namespaceView struct {
 		Name          string   `json:"name"`
 		Description   string   `json:"description,omitempty"`
@@ -143,6 +166,7 @@ func outputNamespacesJSON(cfg *config.Config, namespaces []config.NamespaceEntry
 		Servers       []string `json:"servers"`
 		DenyByDefault bool     `json:"denyByDefault"`
 		IsDefault     bool     `json:"isDefault"`
+		IsLastUsed    bool     `json:"isLastUsed"`
 	}
 
 	views := make([]namespaceView, len(namespaces))
@@ -154,6 +178,7 @@ func outputNamespacesJSON(cfg *config.Config, namespaces []config.NamespaceEntry
 			Servers:       entry.Config.ServerIDs, // Server names are stored directly
 			DenyByDefault: entry.Config.DenyByDefault,
 			IsDefault:     entry.Name == cfg.DefaultNamespace,
+			IsLastUsed:    entry.Name == lastUsed,
 		}
 	}
 
@@ -165,7 +190,7 @@ func outputNamespacesJSON(cfg *config.Config, namespaces []config.NamespaceEntry
 	return nil
 }
 
-func outputNamespacesTable(cfg *config.Config, namespaces []config.NamespaceEntry) error {
+func outputNamespacesTable(cfg *config.Config, namespaces []config.NamespaceEntry, lastUsed string) error {
 	if len(namespaces) == 0 {
 		fmt.Println("No namespaces configured")
 		return nil
@@ -190,7 +215,7 @@ func outputNamespacesTable(cfg *config.Config, namespaces []config.NamespaceEntr
 	}
 
 	// Print header
-	fmt.Printf("%-*s  %-*s  %s  %s  %s\n", nameWidth, "NAME", descWidth, "DESCRIPTION", "SERVERS", "DENY-DEFAULT", "DEFAULT")
+	fmt.Printf("%-*s  %-*s  %s  %s  %s  %s\n", nameWidth, "NAME", descWidth, "DESCRIPTION", "SERVERS", "DENY-DEFAULT", "DEFAULT", "LAST-USED")
 
 	// Print namespaces
 	for _, entry := range namespaces {
@@ -209,7 +234,16 @@ func outputNamespacesTable(cfg *config.Config, namespaces []config.NamespaceEntr
 			isDefault = "*"
 		}
 
-		fmt.Printf("%-*s  %-*s  %-7d  %-12s  %s\n", nameWidth, entry.Name, descWidth, desc, len(entry.Config.ServerIDs), denyDefault, isDefault)
+		isLastUsed := ""
+		if entry.Name == lastUsed {
+			isLastUsed = "*"
+		}
+
+		fmt.Printf("%-*s  %-*s  %-7d  %-12s  %-7s  %s\n", nameWidth, entry.Name, descWidth, desc, len(entry.Config.ServerIDs), denyDefault, isDefault, isLastUsed)
+	}
+
+	if cfg.DefaultNamespace == "" {
+		fmt.Println("\nNo default namespace set")
 	}
 
 	return nil
@@ -230,6 +264,8 @@ var namespaceRemoveCmd = &cobra.Command{
 	Long: `Remove a namespace by name.
 
 This will also remove all tool permissions associated with the namespace.
+Prompts for confirmation unless --yes is given; --yes is required when stdin
+is not a terminal.
 
 Examples:
   mcpmu namespace remove development
@@ -239,7 +275,7 @@ Examples:
 }
 
 func init() {
-	namespaceRemoveCmd.Flags().BoolVarP(&namespaceRemoveYes, "yes", "y", false, "Skip confirmation prompt")
+	addYesFlag(namespaceRemoveCmd, &namespaceRemoveYes)
 	namespaceRemoveCmd.Flags().StringVarP(&namespaceRemoveConfigPath, "config", "c", "", "Path to config file")
 }
 
@@ -257,15 +293,13 @@ func runNamespaceRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Confirm unless --yes
-	if !namespaceRemoveYes {
-		confirmed, err := confirmAction(fmt.Sprintf("Remove namespace %q?", name))
-		if err != nil {
-			return err
-		}
-		if !confirmed {
-			fmt.Println("Cancelled")
-			return nil
-		}
+	confirmed, err := confirmDestructive(fmt.Sprintf("remove namespace %q", name), namespaceRemoveYes)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Cancelled")
+		return nil
 	}
 
 	if err := cfg.DeleteNamespace(name); err != nil {
@@ -393,33 +427,63 @@ func runNamespaceUnassign(cmd *cobra.Command, args []string) error {
 // namespace default
 // ============================================================================
 
-var namespaceDefaultConfigPath string
+var (
+	namespaceDefaultConfigPath string
+	namespaceDefaultClear      bool
+)
 
 var namespaceDefaultCmd = &cobra.Command{
-	Use:   "default <name>",
-	Short: "Set the default namespace",
+	Use:   "default <name> | --clear",
+	Short: "Set or clear the default namespace",
 	Long: `Set the default namespace for stdio mode.
 
 When no --namespace flag is provided to 'serve --stdio', this namespace is used.
+With --clear (or an empty name), the default is removed and serve falls back
+to picking the only namespace, or exposing every enabled server when there
+are none.
 
 Examples:
-  mcpmu namespace default development`,
-	Args: cobra.ExactArgs(1),
+  mcpmu namespace default development
+  mcpmu namespace default --clear`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if namespaceDefaultClear {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runNamespaceDefault,
 }
 
 func init() {
 	namespaceDefaultCmd.Flags().StringVarP(&namespaceDefaultConfigPath, "config", "c", "", "Path to config file")
+	namespaceDefaultCmd.Flags().BoolVar(&namespaceDefaultClear, "clear", false, "Remove the default namespace")
 }
 
 func runNamespaceDefault(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
 
 	cfg, err := loadConfig(namespaceDefaultConfigPath)
 	if err != nil {
 		return err
 	}
 
+	if name == "" {
+		if cfg.DefaultNamespace == "" {
+			fmt.Println("No default namespace set")
+			return nil
+		}
+		previous := cfg.DefaultNamespace
+		cfg.DefaultNamespace = ""
+		if err := saveConfig(cfg, namespaceDefaultConfigPath); err != nil {
+			return err
+		}
+		fmt.Printf("Cleared default namespace (was %q)\n", previous)
+		return nil
+	}
+
 	// Lookup namespace by name
 	if err := requireNamespace(cfg, name); err != nil {
 		return err
@@ -497,3 +561,998 @@ func runNamespaceSetDenyDefault(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Deny-by-default %s for namespace %q\n", setting, namespaceName)
 	return nil
 }
+
+// ============================================================================
+// namespace set-strip-prefix
+// ============================================================================
+
+var namespaceSetStripPrefixConfigPath string
+
+var namespaceSetStripPrefixCmd = &cobra.Command{
+	Use:   "set-strip-prefix <namespace> <true|false>",
+	Short: "Expose unprefixed tool names for single-server namespaces",
+	Long: `Set whether serve mode strips the server prefix from tool names when the
+namespace contains exactly one server.
+
+When enabled and the namespace has a single server, tools are exposed as
+"read_file" instead of "myserver.read_file". Namespaces with more than one
+server always use prefixed names.
+
+Examples:
+  mcpmu namespace set-strip-prefix github true
+  mcpmu namespace set-strip-prefix github false`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNamespaceSetStripPrefix,
+}
+
+func init() {
+	namespaceSetStripPrefixCmd.Flags().StringVarP(&namespaceSetStripPrefixConfigPath, "config", "c", "", "Path to config file")
+}
+
+func runNamespaceSetStripPrefix(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+	valueStr := strings.ToLower(args[1])
+
+	stripPrefix, err := parseBoolFlag(valueStr, []string{"true", "yes", "1"}, []string{"false", "no", "0"}, "value", "true or false")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(namespaceSetStripPrefixConfigPath)
+	if err != nil {
+		return err
+	}
+
+	ns, ok := cfg.GetNamespace(namespaceName)
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	ns.StripPrefixWhenSingle = stripPrefix
+
+	if err := cfg.UpdateNamespace(namespaceName, ns); err != nil {
+		return err
+	}
+
+	if err := saveConfig(cfg, namespaceSetStripPrefixConfigPath); err != nil {
+		return err
+	}
+
+	setting := "disabled"
+	if stripPrefix {
+		setting = "enabled"
+	}
+	fmt.Printf("Strip-prefix-when-single %s for namespace %q\n", setting, namespaceName)
+	if stripPrefix && len(ns.ServerIDs) != 1 {
+		fmt.Printf("Note: namespace %q has %d servers; tool names stay prefixed until it has exactly one\n", namespaceName, len(ns.ServerIDs))
+	}
+	return nil
+}
+
+// ============================================================================
+// namespace set-show-denied
+// ============================================================================
+
+var namespaceSetShowDeniedConfigPath string
+
+var namespaceSetShowDeniedCmd = &cobra.Command{
+	Use:   "set-show-denied <namespace> <true|false>",
+	Short: "List denied tools as marked instead of hiding them",
+	Long: `Set whether serve mode lists tools the namespace denies.
+
+When enabled, denied tools stay in tools/list with "[denied]" at the start
+of their description, so agents and users can see what exists but is
+blocked. Calling them still fails with a tool-denied error. When disabled
+(the default), denied tools are left out of tools/list.
+
+Examples:
+  mcpmu namespace set-show-denied work true
+  mcpmu namespace set-show-denied work false`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNamespaceSetShowDenied,
+}
+
+func init() {
+	namespaceSetShowDeniedCmd.Flags().StringVarP(&namespaceSetShowDeniedConfigPath, "config", "c", "", "Path to config file")
+}
+
+func runNamespaceSetShowDenied(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+	valueStr := strings.ToLower(args[1])
+
+	showDenied, err := parseBoolFlag(valueStr, []string{"true", "yes", "1"}, []string{"false", "no", "0"}, "value", "true or false")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(namespaceSetShowDeniedConfigPath)
+	if err != nil {
+		return err
+	}
+
+	ns, ok := cfg.GetNamespace(namespaceName)
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	ns.ShowDeniedTools = showDenied
+
+	if err := cfg.UpdateNamespace(namespaceName, ns); err != nil {
+		return err
+	}
+
+	if err := saveConfig(cfg, namespaceSetShowDeniedConfigPath); err != nil {
+		return err
+	}
+
+	if showDenied {
+		fmt.Printf("Namespace %q now lists denied tools marked [denied]\n", namespaceName)
+	} else {
+		fmt.Printf("Namespace %q now hides denied tools\n", namespaceName)
+	}
+	return nil
+}
+
+// ============================================================================
+// namespace set-description
+// ============================================================================
+
+var namespaceSetDescriptionConfigPath string
+
+var namespaceSetDescriptionCmd = &cobra.Command{
+	Use:   "set-description <namespace> <text>",
+	Short: "Set a namespace's description",
+	Long: `Replace the description of a namespace. Pass "" to clear it.
+
+Examples:
+  mcpmu namespace set-description work "Servers for day-job projects"
+  mcpmu namespace set-description work ""`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNamespaceSetDescription,
+}
+
+func init() {
+	namespaceSetDescriptionCmd.Flags().StringVarP(&namespaceSetDescriptionConfigPath, "config", "c", "", "Path to config file")
+}
+
+func runNamespaceSetDescription(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+	description := args[1]
+
+	cfg, err := loadConfig(namespaceSetDescriptionConfigPath)
+	if err != nil {
+		return err
+	}
+
+	ns, ok := cfg.GetNamespace(namespaceName)
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	ns.Description = description
+
+	if err := cfg.UpdateNamespace(namespaceName, ns); err != nil {
+		return err
+	}
+
+	if err := saveConfig(cfg, namespaceSetDescriptionConfigPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set description for namespace %q\n", namespaceName)
+	return nil
+}
+
+// ============================================================================
+// namespace set-servers
+// ============================================================================
+
+var namespaceSetServersConfigPath string
+
+var namespaceSetServersCmd = &cobra.Command{
+	Use:   "set-servers <namespace> <server1,server2,...>",
+	Short: "Replace the servers assigned to a namespace",
+	Long: `Replace the whole set of servers assigned to a namespace in one step.
+
+Servers not in the list are unassigned. Every server must exist; if any is
+missing nothing is changed. Pass "" to unassign all servers.
+
+Examples:
+  mcpmu namespace set-servers work github,filesystem,postgres
+  mcpmu namespace set-servers work ""`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNamespaceSetServers,
+}
+
+func init() {
+	namespaceSetServersCmd.Flags().StringVarP(&namespaceSetServersConfigPath, "config", "c", "", "Path to config file")
+}
+
+func runNamespaceSetServers(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+
+	cfg, err := loadConfig(namespaceSetServersConfigPath)
+	if err != nil {
+		return err
+	}
+
+	ns, ok := cfg.GetNamespace(namespaceName)
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	serverIDs := []string{}
+	seen := make(map[string]bool)
+	var missing []string
+	for name := range strings.SplitSeq(args[1], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, ok := cfg.GetServer(name); !ok {
+			missing = append(missing, name)
+			continue
+		}
+		serverIDs = append(serverIDs, name)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("server(s) not found: %s", strings.Join(missing, ", "))
+	}
+
+	ns.ServerIDs = serverIDs
+	// Drop per-server defaults for unassigned servers, as unassign does
+	for name := range ns.ServerDefaults {
+		if !slices.Contains(serverIDs, name) {
+			delete(ns.ServerDefaults, name)
+		}
+	}
+	if len(ns.ServerDefaults) == 0 {
+		ns.ServerDefaults = nil
+	}
+
+	if err := cfg.UpdateNamespace(namespaceName, ns); err != nil {
+		return err
+	}
+
+	if err := saveConfig(cfg, namespaceSetServersConfigPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %d server(s) for namespace %q\n", len(serverIDs), namespaceName)
+	return nil
+}
+
+// ============================================================================
+// namespace servers
+// ============================================================================
+
+var (
+	namespaceServersJSON       bool
+	namespaceServersConfigPath string
+)
+
+var namespaceServersCmd = &cobra.Command{
+	Use:   "servers <namespace>",
+	Short: "List the servers in a namespace",
+	Long: `List the servers assigned to a namespace with their enabled and running state.
+
+A server counts as running when a live serve, TUI or web instance is managing
+its process; remote (HTTP) servers have no process and show as stopped.
+Servers the namespace references but that no longer exist are marked missing.
+
+Examples:
+  mcpmu namespace servers work
+  mcpmu namespace servers work --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNamespaceServers,
+}
+
+func init() {
+	namespaceServersCmd.Flags().BoolVar(&namespaceServersJSON, "json", false, "Output as JSON")
+	namespaceServersCmd.Flags().StringVarP(&namespaceServersConfigPath, "config", "c", "", "Path to config file")
+}
+
+// States reported by `namespace servers`.
+const (
+	namespaceServerRunning  = "running"
+	namespaceServerStopped  = "stopped"
+	namespaceServerDisabled = "disabled"
+	namespaceServerMissing  = "missing"
+)
+
+// namespaceServerView is one server reference in `namespace servers` output.
+type namespaceServerView struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Running bool   `json:"running"`
+	PID     int    `json:"pid,omitempty"`
+	Missing bool   `json:"missing,omitempty"` // referenced by the namespace but not configured
+	State   string `json:"state"`
+}
+
+// namespaceServerViews resolves a namespace's server references in assignment
+// order. running maps server names to the PIDs of live managed processes.
+func namespaceServerViews(cfg *config.Config, ns config.NamespaceConfig, running map[string]int) []namespaceServerView {
+	views := make([]namespaceServerView, 0, len(ns.ServerIDs))
+	for _, name := range ns.ServerIDs {
+		view := namespaceServerView{Name: name}
+		srv, ok := cfg.GetServer(name)
+		switch {
+		case !ok:
+			view.Missing = true
+			view.State = namespaceServerMissing
+		case !srv.IsEnabled():
+			view.State = namespaceServerDisabled
+		default:
+			view.Enabled = true
+			view.State = namespaceServerStopped
+			if pid, ok := running[name]; ok {
+				view.Running = true
+				view.PID = pid
+				view.State = namespaceServerRunning
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+func runNamespaceServers(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+
+	cfg, err := loadConfig(namespaceServersConfigPath)
+	if err != nil {
+		return err
+	}
+	ns, ok := cfg.GetNamespace(namespaceName)
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	// serve tracks its processes next to the config; the TUI and web UI use
+	// the default mcpmu directory
+	var dirs []string
+	if dir, err := config.StateDir(namespaceServersConfigPath); err == nil {
+		dirs = append(dirs, dir)
+	}
+	if dir, err := config.Dir(); err == nil && !slices.Contains(dirs, dir) {
+		dirs = append(dirs, dir)
+	}
+	views := namespaceServerViews(cfg, ns, process.RunningServers(dirs...))
+
+	if namespaceServersJSON {
+		data, err := json.MarshalIndent(views, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(views) == 0 {
+		fmt.Printf("No servers in namespace %q\n", namespaceName)
+		return nil
+	}
+
+	nameWidth := 6
+	for _, view := range views {
+		nameWidth = max(nameWidth, len(view.Name))
+	}
+	fmt.Printf("%-*s  %-7s  %-8s  %s\n", nameWidth, "SERVER", "ENABLED", "STATE", "PID")
+	for _, view := range views {
+		enabled := "no"
+		switch {
+		case view.Missing:
+			enabled = "-"
+		case view.Enabled:
+			enabled = "yes"
+		}
+		pid := "-"
+		if view.PID != 0 {
+			pid = fmt.Sprintf("%d", view.PID)
+		}
+		fmt.Printf("%-*s  %-7s  %-8s  %s\n", nameWidth, view.Name, enabled, view.State, pid)
+	}
+
+	var missing []string
+	for _, view := range views {
+		if view.Missing {
+			missing = append(missing, view.Name)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: namespace %q references missing server(s): %s\n", namespaceName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ============================================================================
+// namespace tools
+// ============================================================================
+
+var (
+	namespaceToolsDiff       bool
+	namespaceToolsJSON       bool
+	namespaceToolsConfigPath string
+)
+
+var namespaceToolsCmd = &cobra.Command{
+	Use:   "tools <namespace> | --diff <namespace-a> <namespace-b>",
+	Short: "Show or compare the tools a namespace exposes",
+	Long: `Show the effective permission of every tool a namespace exposes, or with
+--diff compare two namespaces.
+
+Tools come from the tool cache, which is filled whenever a server is started
+(serve, TUI or web). Servers that have never been started have no cached
+tools and are reported separately. Disabled servers are skipped.
+
+The diff lists tools allowed only in the first namespace (their server is not
+assigned to the second), tools allowed only in the second, and tools from
+servers in both namespaces whose permission differs.
+
+Examples:
+  mcpmu namespace tools prod
+  mcpmu namespace tools --diff staging prod
+  mcpmu namespace tools --diff staging prod --json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if namespaceToolsDiff {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runNamespaceTools,
+}
+
+func init() {
+	namespaceToolsCmd.Flags().BoolVar(&namespaceToolsDiff, "diff", false, "Compare the tools exposed by two namespaces")
+	namespaceToolsCmd.Flags().BoolVar(&namespaceToolsJSON, "json", false, "Output as JSON")
+	namespaceToolsCmd.Flags().StringVarP(&namespaceToolsConfigPath, "config", "c", "", "Path to config file")
+}
+
+// namespaceToolAccess is the permission outcome for one cached tool in a namespace.
+type namespaceToolAccess struct {
+	Tool    string `json:"tool"` // qualified serve-mode name
+	Server  string `json:"server"`
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule"`
+}
+
+// effectiveNamespaceTools evaluates every cached tool of the namespace's
+// enabled servers, keyed by qualified tool name. Also returns the servers
+// that have no cached tools.
+func effectiveNamespaceTools(cfg *config.Config, toolCache *config.ToolCache, namespaceName string) (map[string]namespaceToolAccess, []string) {
+	ns, _ := cfg.GetNamespace(namespaceName)
+	access := make(map[string]namespaceToolAccess)
+	var uncached []string
+	for _, serverName := range ns.ServerIDs {
+		srv, ok := cfg.GetServer(serverName)
+		if !ok || !srv.IsEnabled() {
+			continue
+		}
+		tools, ok := toolCache.Get(serverName)
+		if !ok {
+			uncached = append(uncached, serverName)
+			continue
+		}
+		prefix := cfg.ToolPrefix(serverName)
+		for _, tool := range tools {
+			decision := server.ExplainToolPermission(cfg, namespaceName, serverName, tool.Name)
+			qualified := prefix + "." + tool.Name
+			access[qualified] = namespaceToolAccess{
+				Tool:    qualified,
+				Server:  serverName,
+				Allowed: decision.Allowed,
+				Rule:    string(decision.Rule),
+			}
+		}
+	}
+	return access, uncached
+}
+
+// sortedToolAccess returns the access entries ordered by tool name.
+func sortedToolAccess(access map[string]namespaceToolAccess) []namespaceToolAccess {
+	entries := make([]namespaceToolAccess, 0, len(access))
+	for _, entry := range access {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Tool < entries[j].Tool })
+	return entries
+}
+
+func warnUncachedServers(servers []string) {
+	if len(servers) == 0 {
+		return
+	}
+	sort.Strings(servers)
+	fmt.Fprintf(os.Stderr, "Warning: no cached tools for %s; start them once to populate the tool cache\n", strings.Join(servers, ", "))
+}
+
+func runNamespaceTools(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(namespaceToolsConfigPath)
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		if err := requireNamespace(cfg, name); err != nil {
+			return err
+		}
+	}
+
+	toolCache, err := config.NewToolCache(namespaceToolsConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load tool cache: %w", err)
+	}
+
+	if namespaceToolsDiff {
+		return runNamespaceToolsDiff(cfg, toolCache, args[0], args[1])
+	}
+
+	access, uncached := effectiveNamespaceTools(cfg, toolCache, args[0])
+	entries := sortedToolAccess(access)
+
+	if namespaceToolsJSON {
+		sort.Strings(uncached)
+		output := struct {
+			Namespace       string                `json:"namespace"`
+			Tools           []namespaceToolAccess `json:"tools"`
+			UncachedServers []string              `json:"uncachedServers"`
+		}{args[0], entries, uncached}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	warnUncachedServers(uncached)
+	if len(entries) == 0 {
+		fmt.Printf("No cached tools for namespace %q\n", args[0])
+		return nil
+	}
+
+	toolWidth := 4
+	for _, entry := range entries {
+		toolWidth = max(toolWidth, len(entry.Tool))
+	}
+	fmt.Printf("%-*s  %-6s  %s\n", toolWidth, "TOOL", "ACCESS", "RULE")
+	for _, entry := range entries {
+		fmt.Printf("%-*s  %-6s  %s\n", toolWidth, entry.Tool, accessLabel(entry.Allowed), entry.Rule)
+	}
+	return nil
+}
+
+// namespaceToolDifference is a tool whose permission differs between two
+// namespaces that both include its server.
+type namespaceToolDifference struct {
+	Tool   string              `json:"tool"`
+	Server string              `json:"server"`
+	A      namespaceToolAccess `json:"a"`
+	B      namespaceToolAccess `json:"b"`
+}
+
+// namespaceToolsDiffResult buckets the tool exposure differences between two namespaces.
+type namespaceToolsDiffResult struct {
+	NamespaceA            string                    `json:"namespaceA"`
+	NamespaceB            string                    `json:"namespaceB"`
+	OnlyInA               []string                  `json:"onlyInA"`
+	OnlyInB               []string                  `json:"onlyInB"`
+	PermissionDifferences []namespaceToolDifference `json:"permissionDifferences"`
+	UncachedServers       []string                  `json:"uncachedServers"`
+}
+
+// diffNamespaceTools compares the effective tool access of two namespaces.
+func diffNamespaceTools(cfg *config.Config, toolCache *config.ToolCache, nsA, nsB string) namespaceToolsDiffResult {
+	accessA, uncachedA := effectiveNamespaceTools(cfg, toolCache, nsA)
+	accessB, uncachedB := effectiveNamespaceTools(cfg, toolCache, nsB)
+
+	result := namespaceToolsDiffResult{
+		NamespaceA:            nsA,
+		NamespaceB:            nsB,
+		OnlyInA:               []string{},
+		OnlyInB:               []string{},
+		PermissionDifferences: []namespaceToolDifference{},
+	}
+
+	for _, a := range sortedToolAccess(accessA) {
+		b, inB := accessB[a.Tool]
+		switch {
+		case !inB:
+			if a.Allowed {
+				result.OnlyInA = append(result.OnlyInA, a.Tool)
+			}
+		case a.Allowed != b.Allowed:
+			result.PermissionDifferences = append(result.PermissionDifferences, namespaceToolDifference{
+				Tool:   a.Tool,
+				Server: a.Server,
+				A:      a,
+				B:      b,
+			})
+		}
+	}
+	for _, b := range sortedToolAccess(accessB) {
+		if _, inA := accessA[b.Tool]; !inA && b.Allowed {
+			result.OnlyInB = append(result.OnlyInB, b.Tool)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range append(uncachedA, uncachedB...) {
+		if !seen[name] {
+			seen[name] = true
+			result.UncachedServers = append(result.UncachedServers, name)
+		}
+	}
+	sort.Strings(result.UncachedServers)
+	if result.UncachedServers == nil {
+		result.UncachedServers = []string{}
+	}
+	return result
+}
+
+func runNamespaceToolsDiff(cfg *config.Config, toolCache *config.ToolCache, nsA, nsB string) error {
+	result := diffNamespaceTools(cfg, toolCache, nsA, nsB)
+
+	if namespaceToolsJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	warnUncachedServers(result.UncachedServers)
+	if len(result.OnlyInA) == 0 && len(result.OnlyInB) == 0 && len(result.PermissionDifferences) == 0 {
+		fmt.Printf("No differences between %q and %q\n", nsA, nsB)
+		return nil
+	}
+
+	printToolList := func(title string, tools []string) {
+		if len(tools) == 0 {
+			return
+		}
+		fmt.Println(title)
+		for _, tool := range tools {
+			fmt.Printf("  %s\n", tool)
+		}
+		fmt.Println()
+	}
+	printToolList(fmt.Sprintf("Only in %s:", nsA), result.OnlyInA)
+	printToolList(fmt.Sprintf("Only in %s:", nsB), result.OnlyInB)
+
+	if len(result.PermissionDifferences) > 0 {
+		fmt.Println("Permission differences:")
+		for _, diff := range result.PermissionDifferences {
+			fmt.Printf("  %s: %s=%s (%s), %s=%s (%s)\n", diff.Tool,
+				nsA, accessLabel(diff.A.Allowed), diff.A.Rule,
+				nsB, accessLabel(diff.B.Allowed), diff.B.Rule)
+		}
+	}
+	return nil
+}
+
+func accessLabel(allowed bool) string {
+	if allowed {
+		return "allow"
+	}
+	return "deny"
+}
+
+// ============================================================================
+// namespace check
+// ============================================================================
+
+var (
+	namespaceCheckStart      bool
+	namespaceCheckJSON       bool
+	namespaceCheckConfigPath string
+)
+
+var namespaceCheckCmd = &cobra.Command{
+	Use:   "check <namespace>",
+	Short: "Check that a namespace is ready to serve",
+	Long: `Check a namespace for problems before pointing an agent at it.
+
+The static checks confirm that the namespace has at least one enabled server,
+that every server it references exists, and that its tool permissions refer
+to servers it includes. With --start, each enabled server is also started
+briefly to confirm that it initializes, that it exposes at least one tool
+the namespace permits, and that every explicitly allowed tool exists.
+
+Each problem is reported with a suggested fix. Errors make the command exit
+non-zero; warnings don't.
+
+Examples:
+  mcpmu namespace check work
+  mcpmu namespace check work --start
+  mcpmu namespace check work --start --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNamespaceCheck,
+}
+
+func init() {
+	namespaceCheckCmd.Flags().BoolVar(&namespaceCheckStart, "start", false, "Start the namespace's servers briefly and check their tools")
+	namespaceCheckCmd.Flags().BoolVar(&namespaceCheckJSON, "json", false, "Output as JSON")
+	namespaceCheckCmd.Flags().StringVarP(&namespaceCheckConfigPath, "config", "c", "", "Path to config file")
+}
+
+// Severities of the problems reported by `namespace check`.
+const (
+	checkError   = "error"
+	checkWarning = "warning"
+)
+
+// namespaceProblem is one issue found by `namespace check`.
+type namespaceProblem struct {
+	Severity string `json:"severity"`
+	Server   string `json:"server,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// namespaceServerCheck is the outcome of starting one server with --start.
+type namespaceServerCheck struct {
+	Name           string `json:"name"`
+	Started        bool   `json:"started"`
+	ToolCount      int    `json:"toolCount"`
+	PermittedTools int    `json:"permittedTools"`
+	Error          string `json:"error,omitempty"`
+}
+
+// checkNamespaceConfig runs the checks that need only the config: the
+// namespace has an enabled server, every server it references exists, and
+// its tool permissions belong to servers it includes.
+func checkNamespaceConfig(cfg *config.Config, name string) []namespaceProblem {
+	ns, _ := cfg.GetNamespace(name)
+	var problems []namespaceProblem
+
+	enabled := 0
+	for _, serverName := range ns.ServerIDs {
+		srv, ok := cfg.GetServer(serverName)
+		switch {
+		case !ok:
+			problems = append(problems, namespaceProblem{
+				Severity: checkError,
+				Server:   serverName,
+				Message:  fmt.Sprintf("server %q is assigned but not configured", serverName),
+				Fix:      fmt.Sprintf("mcpmu namespace unassign %s %s", name, serverName),
+			})
+		case !srv.IsEnabled():
+			problems = append(problems, namespaceProblem{
+				Severity: checkWarning,
+				Server:   serverName,
+				Message:  fmt.Sprintf("server %q is disabled and will be skipped", serverName),
+				Fix:      fmt.Sprintf("mcpmu enable %s", serverName),
+			})
+		default:
+			enabled++
+		}
+	}
+	switch {
+	case len(ns.ServerIDs) == 0:
+		problems = append(problems, namespaceProblem{
+			Severity: checkError,
+			Message:  "no servers are assigned",
+			Fix:      fmt.Sprintf("mcpmu namespace assign %s <server>", name),
+		})
+	case enabled == 0:
+		problems = append(problems, namespaceProblem{
+			Severity: checkError,
+			Message:  "none of the assigned servers is enabled",
+			Fix:      "mcpmu enable <server>",
+		})
+	}
+
+	reported := make(map[string]bool)
+	for _, tp := range cfg.GetToolPermissionsForNamespace(name) {
+		if reported[tp.Server] || slices.Contains(ns.ServerIDs, tp.Server) {
+			continue
+		}
+		reported[tp.Server] = true
+		problems = append(problems, namespaceProblem{
+			Severity: checkWarning,
+			Server:   tp.Server,
+			Message:  fmt.Sprintf("tool permissions for server %q have no effect because it is not assigned", tp.Server),
+			Fix:      fmt.Sprintf("mcpmu namespace assign %s %s", name, tp.Server),
+		})
+	}
+	return problems
+}
+
+// checkNamespaceServers starts the namespace's enabled servers concurrently
+// and checks that each initializes and exposes a permitted tool, that the
+// namespace as a whole permits at least one tool, and that explicitly
+// allowed tools exist.
+func checkNamespaceServers(cfg *config.Config, configPath, name string) ([]namespaceServerCheck, []namespaceProblem) {
+	ns, _ := cfg.GetNamespace(name)
+	var entries []config.ServerEntry
+	for _, serverName := range ns.ServerIDs {
+		if srv, ok := cfg.GetServer(serverName); ok && srv.IsEnabled() {
+			entries = append(entries, config.ServerEntry{Name: serverName, Config: srv})
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	supervisor, cleanup := newProbeSupervisor(cfg, configPath, "check")
+	defer cleanup()
+
+	tools := make([][]string, len(entries))
+	errs := make([]error, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Go(func() {
+			tools[i], errs[i] = startServerForCheck(supervisor, entry)
+		})
+	}
+	wg.Wait()
+
+	var (
+		results   []namespaceServerCheck
+		problems  []namespaceProblem
+		permitted int
+	)
+	for i, entry := range entries {
+		result := namespaceServerCheck{Name: entry.Name}
+		if err := errs[i]; err != nil {
+			result.Error = redact.String(err.Error())
+			fix := "check the server's command, env and credentials, or unassign it"
+			if mcp.ClassifyError(err) == mcp.ErrorAuth {
+				fix = fmt.Sprintf("mcpmu mcp login %s", entry.Name)
+			}
+			problems = append(problems, namespaceProblem{
+				Severity: checkError,
+				Server:   entry.Name,
+				Message:  fmt.Sprintf("server %q failed to start: %s", entry.Name, result.Error),
+				Fix:      fix,
+			})
+			results = append(results, result)
+			continue
+		}
+
+		result.Started = true
+		result.ToolCount = len(tools[i])
+		for _, tool := range tools[i] {
+			if server.ExplainToolPermission(cfg, name, entry.Name, tool).Allowed {
+				result.PermittedTools++
+			}
+		}
+		permitted += result.PermittedTools
+		if result.ToolCount > 0 && result.PermittedTools == 0 {
+			problems = append(problems, namespaceProblem{
+				Severity: checkWarning,
+				Server:   entry.Name,
+				Message:  fmt.Sprintf("all %d tools of server %q are denied in this namespace", result.ToolCount, entry.Name),
+				Fix:      fmt.Sprintf("mcpmu permission set %s %s <tool> allow", name, entry.Name),
+			})
+		}
+
+		for _, tp := range cfg.GetToolPermissionsForNamespace(name) {
+			if tp.Server != entry.Name || !tp.Enabled || slices.Contains(tools[i], tp.ToolName) {
+				continue
+			}
+			problems = append(problems, namespaceProblem{
+				Severity: checkError,
+				Server:   entry.Name,
+				Message:  fmt.Sprintf("tool %q is allowed but server %q doesn't expose it", tp.ToolName, entry.Name),
+				Fix:      fmt.Sprintf("mcpmu permission unset %s %s %s", name, entry.Name, tp.ToolName),
+			})
+		}
+		results = append(results, result)
+	}
+
+	if permitted == 0 && slices.ContainsFunc(results, func(r namespaceServerCheck) bool { return r.Started }) {
+		problems = append(problems, namespaceProblem{
+			Severity: checkError,
+			Message:  "the namespace permits no tools",
+			Fix:      fmt.Sprintf("mcpmu permission set %s <server> <tool> allow", name),
+		})
+	}
+	return results, problems
+}
+
+// startServerForCheck starts one server, bounded by its startup timeout, and
+// returns the names of the tools it exposes.
+func startServerForCheck(supervisor *process.Supervisor, entry config.ServerEntry) ([]string, error) {
+	timeout := time.Duration(entry.Config.StartupTimeout()) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	handle, err := supervisor.Start(ctx, entry.Name, entry.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := handle.WaitForTools(ctx); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, tool := range handle.Tools() {
+		names = append(names, tool.Name)
+	}
+	return names, nil
+}
+
+func runNamespaceCheck(cmd *cobra.Command, args []string) error {
+	namespaceName := args[0]
+
+	cfg, err := loadConfig(namespaceCheckConfigPath)
+	if err != nil {
+		return err
+	}
+	if err := requireNamespace(cfg, namespaceName); err != nil {
+		return err
+	}
+
+	problems := checkNamespaceConfig(cfg, namespaceName)
+	var servers []namespaceServerCheck
+	if namespaceCheckStart {
+		var startProblems []namespaceProblem
+		servers, startProblems = checkNamespaceServers(cfg, namespaceCheckConfigPath, namespaceName)
+		problems = append(problems, startProblems...)
+	}
+
+	errorCount := 0
+	for _, problem := range problems {
+		if problem.Severity == checkError {
+			errorCount++
+		}
+	}
+
+	if namespaceCheckJSON {
+		output := struct {
+			Namespace string                 `json:"namespace"`
+			OK        bool                   `json:"ok"`
+			Servers   []namespaceServerCheck `json:"servers,omitempty"`
+			Problems  []namespaceProblem     `json:"problems"`
+		}{namespaceName, errorCount == 0, servers, problems}
+		if output.Problems == nil {
+			output.Problems = []namespaceProblem{}
+		}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		outputNamespaceCheck(namespaceName, servers, problems)
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("namespace %q is not ready: %d error(s)", namespaceName, errorCount)
+	}
+	return nil
+}
+
+func outputNamespaceCheck(namespaceName string, servers []namespaceServerCheck, problems []namespaceProblem) {
+	if len(servers) > 0 {
+		nameWidth := 6
+		for _, s := range servers {
+			nameWidth = max(nameWidth, len(s.Name))
+		}
+		fmt.Printf("%-*s  %-7s  %-5s  %s\n", nameWidth, "SERVER", "STARTED", "TOOLS", "PERMITTED")
+		for _, s := range servers {
+			started, toolCount, permitted := "no", "-", "-"
+			if s.Started {
+				started = "yes"
+				toolCount = fmt.Sprintf("%d", s.ToolCount)
+				permitted = fmt.Sprintf("%d", s.PermittedTools)
+			}
+			fmt.Printf("%-*s  %-7s  %-5s  %s\n", nameWidth, s.Name, started, toolCount, permitted)
+		}
+		fmt.Println()
+	}
+
+	if len(problems) == 0 {
+		fmt.Printf("Namespace %q is ready to serve\n", namespaceName)
+		return
+	}
+	for _, problem := range problems {
+		fmt.Printf("%s: %s\n", problem.Severity, problem.Message)
+		if problem.Fix != "" {
+			fmt.Printf("  fix: %s\n", problem.Fix)
+		}
+	}
+}
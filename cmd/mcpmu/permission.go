@@ -3,10 +3,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"sort"
 	"strings"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/server"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +36,9 @@ func init() {
 	permissionCmd.AddCommand(permissionListCmd)
 	permissionCmd.AddCommand(permissionSetServerDefaultCmd)
 	permissionCmd.AddCommand(permissionUnsetServerDefaultCmd)
+	permissionCmd.AddCommand(permissionCheckCmd)
+	permissionCmd.AddCommand(permissionExportCmd)
+	permissionCmd.AddCommand(permissionImportCmd)
 }
 
 // ============================================================================
@@ -110,7 +116,10 @@ func runPermissionSet(cmd *cobra.Command, args []string) error {
 // permission unset
 // ============================================================================
 
-var permissionUnsetConfigPath string
+var (
+	permissionUnsetYes        bool
+	permissionUnsetConfigPath string
+)
 
 var permissionUnsetCmd = &cobra.Command{
 	Use:   "unset <namespace> <server> <tool>",
@@ -122,13 +131,18 @@ The tool name should be unqualified (e.g., "read_file").
 Qualified names like "<server-id>.read_file" are accepted for convenience.
 Tool names that include dots are allowed (e.g., "fs.read_file").
 
+Prompts for confirmation unless --yes is given; --yes is required when stdin
+is not a terminal.
+
 Examples:
-  mcpmu permission unset production api-server create_user`,
+  mcpmu permission unset production api-server create_user
+  mcpmu permission unset production api-server create_user --yes`,
 	Args: cobra.ExactArgs(3),
 	RunE: runPermissionUnset,
 }
 
 func init() {
+	addYesFlag(permissionUnsetCmd, &permissionUnsetYes)
 	permissionUnsetCmd.Flags().StringVarP(&permissionUnsetConfigPath, "config", "c", "", "Path to config file")
 }
 
@@ -154,6 +168,17 @@ func runPermissionUnset(cmd *cobra.Command, args []string) error {
 
 	toolName := normalizeToolName(toolNameRaw, serverName)
 
+	// Confirm unless --yes
+	action := fmt.Sprintf("remove the permission for %s.%s in namespace %q", serverName, toolName, namespaceName)
+	confirmed, err := confirmDestructive(action, permissionUnsetYes)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
 	if err := cfg.UnsetToolPermission(namespaceName, serverName, toolName); err != nil {
 		return err
 	}
@@ -448,6 +473,246 @@ func runPermissionUnsetServerDefault(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// ============================================================================
+// permission check
+// ============================================================================
+
+var (
+	permissionCheckConfigPath string
+	permissionCheckJSON       bool
+)
+
+var permissionCheckCmd = &cobra.Command{
+	Use:   "check [namespace] <server.tool>",
+	Short: "Show whether a tool would be allowed",
+	Long: `Evaluate a tool against the configured permissions without starting any
+servers, and explain which rule decided the outcome.
+
+The tool is given in its qualified serve-mode form (server.tool, or
+toolPrefix.tool when the server sets a tool prefix). Without a namespace the
+check matches serve mode with no namespace selected: only the server's global
+deny list applies.
+
+Examples:
+  mcpmu permission check prod filesystem.write_file
+  mcpmu permission check filesystem.delete_file
+  mcpmu permission check prod fs.read_file --json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPermissionCheck,
+}
+
+func init() {
+	permissionCheckCmd.Flags().StringVarP(&permissionCheckConfigPath, "config", "c", "", "Path to config file")
+	permissionCheckCmd.Flags().BoolVar(&permissionCheckJSON, "json", false, "Output as JSON")
+}
+
+// permissionRuleDescriptions explains each permission rule in plain words.
+var permissionRuleDescriptions = map[server.PermissionRule]string{
+	server.RuleGlobalDeny:           "listed in the server's global deny list (deniedTools)",
+	server.RuleNoNamespace:          "no namespace selected, all tools are allowed",
+	server.RuleNamespaceNotFound:    "namespace not found, all tools are allowed",
+	server.RuleExplicitAllow:        "explicitly allowed in this namespace",
+	server.RuleExplicitDeny:         "explicitly denied in this namespace",
+	server.RuleServerDenyDefault:    "no explicit permission, and the server denies by default in this namespace",
+	server.RuleServerAllowDefault:   "no explicit permission, and the server allows by default in this namespace",
+	server.RuleTrustedServer:        "no explicit permission, and the server is trusted, overriding the namespace's deny-by-default",
+	server.RuleNamespaceDenyDefault: "no explicit permission, and the namespace denies by default",
+	server.RuleNamespaceAllow:       "no explicit permission, and the namespace allows by default",
+}
+
+func runPermissionCheck(cmd *cobra.Command, args []string) error {
+	namespaceName := ""
+	qualifiedName := strings.TrimSpace(args[len(args)-1])
+	if len(args) == 2 {
+		namespaceName = args[0]
+	}
+
+	cfg, err := loadConfig(permissionCheckConfigPath)
+	if err != nil {
+		return err
+	}
+
+	if namespaceName != "" {
+		if err := requireNamespace(cfg, namespaceName); err != nil {
+			return err
+		}
+	}
+
+	serverName, toolName, isManager := server.ResolveToolName(cfg, qualifiedName)
+	if isManager {
+		return fmt.Errorf("%q is a manager tool; manager tools are not subject to tool permissions", qualifiedName)
+	}
+	if serverName == "" || toolName == "" {
+		return fmt.Errorf("tool must be qualified as server.tool, got %q", qualifiedName)
+	}
+	if err := requireServer(cfg, serverName); err != nil {
+		return err
+	}
+
+	decision := server.ExplainToolPermission(cfg, namespaceName, serverName, toolName)
+
+	if permissionCheckJSON {
+		output := struct {
+			Namespace string `json:"namespace"`
+			Server    string `json:"server"`
+			Tool      string `json:"tool"`
+			Allowed   bool   `json:"allowed"`
+			Rule      string `json:"rule"`
+			Reason    string `json:"reason,omitempty"`
+		}{
+			Namespace: namespaceName,
+			Server:    serverName,
+			Tool:      toolName,
+			Allowed:   decision.Allowed,
+			Rule:      string(decision.Rule),
+			Reason:    decision.Reason,
+		}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	verdict := "allowed"
+	if !decision.Allowed {
+		verdict = "denied"
+	}
+	scope := "with no namespace"
+	if namespaceName != "" {
+		scope = fmt.Sprintf("in namespace %q", namespaceName)
+	}
+	fmt.Printf("%s.%s is %s %s\n", serverName, toolName, verdict, scope)
+	fmt.Printf("Rule: %s (%s)\n", decision.Rule, permissionRuleDescriptions[decision.Rule])
+	return nil
+}
+
+// ============================================================================
+// permission export / import
+// ============================================================================
+
+var permissionExportConfigPath string
+
+var permissionExportCmd = &cobra.Command{
+	Use:   "export <namespace>",
+	Short: "Write a namespace's permission policy as JSON",
+	Long: `Write a namespace's deny-by-default setting, server defaults and tool
+permissions to stdout as JSON, for applying to another namespace or another
+machine's config with permission import.
+
+Examples:
+  mcpmu permission export production > production-policy.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPermissionExport,
+}
+
+func init() {
+	permissionExportCmd.Flags().StringVarP(&permissionExportConfigPath, "config", "c", "", "Path to config file")
+}
+
+func runPermissionExport(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(permissionExportConfigPath)
+	if err != nil {
+		return err
+	}
+	policy, err := cfg.ExportNamespacePolicy(args[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(policy, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+var (
+	permissionImportConfigPath string
+	permissionImportMap        []string
+	permissionImportReplace    bool
+)
+
+var permissionImportCmd = &cobra.Command{
+	Use:   "import <namespace> <file|->",
+	Short: "Apply a permission policy written by permission export",
+	Long: `Apply a policy written by permission export to a namespace. Use - to
+read it from stdin.
+
+The namespace takes the policy's deny-by-default setting, and its server
+defaults and tool permissions are merged in, the policy's winning. With
+--replace the namespace's existing server defaults and tool permissions are
+removed first, so it ends up with exactly the policy.
+
+Servers named differently in this config can be renamed with --map old=new.
+Every server the policy refers to must exist after renaming, otherwise
+nothing is imported.
+
+Examples:
+  mcpmu permission import staging production-policy.json
+  mcpmu permission import work policy.json --map gh=github --replace`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPermissionImport,
+}
+
+func init() {
+	permissionImportCmd.Flags().StringVarP(&permissionImportConfigPath, "config", "c", "", "Path to config file")
+	permissionImportCmd.Flags().StringArrayVar(&permissionImportMap, "map", nil, "Rename a server in the policy (old=new), can be repeated")
+	permissionImportCmd.Flags().BoolVar(&permissionImportReplace, "replace", false, "Remove the namespace's existing server defaults and permissions first")
+}
+
+func runPermissionImport(cmd *cobra.Command, args []string) error {
+	namespaceName, path := args[0], args[1]
+
+	serverMap, err := parseServerMap(permissionImportMap)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("read policy: %w", err)
+	}
+	var policy config.NamespacePolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("invalid policy JSON: %w", err)
+	}
+
+	cfg, err := loadConfig(permissionImportConfigPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ImportNamespacePolicy(namespaceName, &policy, serverMap, permissionImportReplace); err != nil {
+		return err
+	}
+	if err := saveConfig(cfg, permissionImportConfigPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d permission(s) and %d server default(s) into namespace %q\n",
+		len(policy.Permissions), len(policy.ServerDefaults), namespaceName)
+	return nil
+}
+
+// parseServerMap parses old=new pairs from --map flags.
+func parseServerMap(flags []string) (map[string]string, error) {
+	serverMap := make(map[string]string, len(flags))
+	for _, pair := range flags {
+		oldName, newName, ok := strings.Cut(pair, "=")
+		if !ok || oldName == "" || newName == "" {
+			return nil, fmt.Errorf("invalid --map %q: expected old=new", pair)
+		}
+		serverMap[oldName] = newName
+	}
+	return serverMap, nil
+}
+
 // normalizeToolName strips a qualified server prefix when it matches the
 // selected server. This allows users to paste tools/list output (serverName.tool)
 // while preserving legitimate tool names that include dots.
@@ -16,7 +16,8 @@ var removeCmd = &cobra.Command{
 	Short: "Remove an MCP server",
 	Long: `Remove an MCP server from the configuration.
 
-By default, prompts for confirmation. Use --yes to skip the prompt.
+By default, prompts for confirmation. Use --yes to skip the prompt; it is
+required when stdin is not a terminal.
 
 Examples:
   mcpmu remove my-server
@@ -26,7 +27,7 @@ Examples:
 }
 
 func init() {
-	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip confirmation prompt")
+	addYesFlag(removeCmd, &removeYes)
 	removeCmd.Flags().StringVarP(&removeConfigPath, "config", "c", "", "Path to config file (default: ~/.config/mcpmu/config.json)")
 
 	rootCmd.AddCommand(removeCmd)
@@ -47,15 +48,13 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Confirm unless --yes
-	if !removeYes {
-		confirmed, err := confirmAction(fmt.Sprintf("Remove server %q?", name))
-		if err != nil {
-			return err
-		}
-		if !confirmed {
-			fmt.Println("Cancelled")
-			return nil
-		}
+	confirmed, err := confirmDestructive(fmt.Sprintf("remove server %q", name), removeYes)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Cancelled")
+		return nil
 	}
 
 	// Remove server
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,9 @@ var (
 // configPath is the custom config file path (empty for default)
 var configPath string
 
+// configDir relocates mcpmu's directory (empty for default)
+var configDir string
+
 var rootCmd = &cobra.Command{
 	Use:   "mcpmu",
 	Short: "MCP server aggregator and manager",
@@ -24,6 +28,11 @@ var rootCmd = &cobra.Command{
 Running without a subcommand starts the interactive TUI.
 Use 'mcpmu serve --stdio' to run as an MCP server (spawned by Claude Code).`,
 	Version: fmt.Sprintf("%s (commit: %s)", version, commit),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if configDir != "" {
+			config.SetHome(configDir)
+		}
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default to TUI when no subcommand is given
 		return runTUI(cmd, args)
@@ -39,8 +48,16 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "",
 		"Path to config file (default: ~/.config/mcpmu/config.json)")
 
+	// Add persistent --config-dir flag relocating all of mcpmu's files
+	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "",
+		"Directory for the default config, tool cache, PID files and credentials (default: $MCPMU_HOME or ~/.config/mcpmu)")
+
 	// Add --debug flag to root command (for default TUI mode)
 	rootCmd.Flags().BoolVar(&tuiDebug, "debug", false, "Enable debug logging to /tmp/mcpmu-debug.log")
+	rootCmd.Flags().BoolVar(&tuiLastUsed, "last-used", false, "Open the TUI on the namespace last picked with serve --select")
+	rootCmd.Flags().BoolVar(&tuiNoAutostart, "no-autostart", false, "Open the TUI without starting autostart servers (press A to start them later)")
+	rootCmd.Flags().StringVar(&tuiLogFile, "log-file", "", "Append the TUI's own logs to this file (or set "+logFileEnv+")")
+	rootCmd.Flags().StringVar(&tuiLogLevel, "log-level", "", "Log level for --log-file or --debug: debug, info, warn, error (default: info, or debug with --debug)")
 }
 
 func Execute() {
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runConfigPath  string
+	runNamespace   string
+	runPermissions bool
+	runLogLevel    string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <server> [-- <extra args>]",
+	Short: "Proxy a single upstream server over stdio",
+	Long: `Connect to one configured server and relay raw MCP traffic between the
+client on stdio and that upstream. Unlike serve, there is no aggregation and
+tool names are not prefixed — the client talks to the server directly, with
+mcpmu supplying the command environment, bearer tokens and OAuth credentials.
+
+Arguments after -- are appended to a stdio server's configured args.
+
+The server's global deny list (deniedTools) is always enforced. Use
+--permissions to also apply namespace tool permissions, taken from
+--namespace or the default namespace.
+
+Examples:
+  mcpmu run filesystem
+  mcpmu run filesystem -- /extra/root
+  mcpmu run atlassian --permissions --namespace work`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	runCmd.Flags().StringVarP(&runConfigPath, "config", "c", "", "Path to config file (default: ~/.config/mcpmu/config.json)")
+	runCmd.Flags().StringVarP(&runNamespace, "namespace", "n", "", "Namespace whose permissions apply with --permissions (default: default namespace)")
+	runCmd.Flags().BoolVar(&runPermissions, "permissions", false, "Apply namespace tool permissions to tools/list and tools/call")
+	runCmd.Flags().StringVarP(&runLogLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
+
+	rootCmd.AddCommand(runCmd)
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	setupStdioLogging(runLogLevel)
+
+	serverName := args[0]
+	extraArgs := args[1:]
+
+	resolvedConfigPath, err := resolveConfigPath(runConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadFrom(resolvedConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	srv, ok := cfg.GetServer(serverName)
+	if !ok {
+		return fmt.Errorf("server %q not found", serverName)
+	}
+	if len(extraArgs) > 0 {
+		if srv.IsHTTP() {
+			return fmt.Errorf("server %q uses HTTP transport; extra args only apply to stdio servers", serverName)
+		}
+		srv.Args = append(append([]string{}, srv.Args...), extraArgs...)
+		cfg.Servers[serverName] = srv
+	}
+
+	namespace := ""
+	if runPermissions {
+		namespace = runNamespace
+		if namespace == "" {
+			namespace = cfg.DefaultNamespace
+		}
+		if namespace == "" {
+			return fmt.Errorf("--permissions requires --namespace or a default namespace")
+		}
+	} else if runNamespace != "" {
+		return fmt.Errorf("--namespace requires --permissions")
+	}
+
+	proxy, err := server.NewProxy(server.ProxyOptions{
+		Config:     cfg,
+		ConfigPath: resolvedConfigPath,
+		ServerName: serverName,
+		Namespace:  namespace,
+		Stdin:      os.Stdin,
+		Stdout:     os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigCh
+		log.Printf("Received signal %v, shutting down", sig)
+		cancel()
+	}()
+
+	log.Printf("mcpmu run proxying %s (version=%s)", serverName, version)
+	if err := proxy.Run(ctx); err != nil {
+		return fmt.Errorf("proxy error: %w", err)
+	}
+	return nil
+}
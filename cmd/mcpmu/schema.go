@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaLink   bool
+	schemaUnlink bool
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [--link | --unlink]",
+	Short: "Print the JSON Schema for the config file",
+	Long: `Print the JSON Schema describing config.json, for editors and linters.
+
+With --link, the config file gets a "$schema" reference to the published
+schema so editors such as VS Code validate and autocomplete it; the
+reference is kept on every later save. --unlink removes it.
+
+Examples:
+  mcpmu schema > mcpmu.schema.json
+  mcpmu schema --link`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+func init() {
+	schemaCmd.Flags().BoolVar(&schemaLink, "link", false, "Add a $schema reference to the config file")
+	schemaCmd.Flags().BoolVar(&schemaUnlink, "unlink", false, "Remove the $schema reference from the config file")
+	schemaCmd.MarkFlagsMutuallyExclusive("link", "unlink")
+
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if schemaLink || schemaUnlink {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+		cfg.Schema = ""
+		if schemaLink {
+			cfg.Schema = config.SchemaURL
+		}
+		if err := saveConfig(cfg, configPath); err != nil {
+			return err
+		}
+		if schemaLink {
+			fmt.Printf("Linked config to %s\n", config.SchemaURL)
+		} else {
+			fmt.Println("Removed $schema from config")
+		}
+		return nil
+	}
+
+	data, err := config.JSONSchema()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
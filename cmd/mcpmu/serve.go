@@ -7,9 +7,8 @@ import (
 	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/mcp"
@@ -20,11 +19,29 @@ import (
 var (
 	serveConfigPath         string
 	serveNamespace          string
+	serveAllNamespaces      bool
 	serveLogLevel           string
 	serveEager              bool
 	serveExposeManagerTools bool
 	serveResources          bool
 	servePrompts            bool
+	serveReloadDrainTimeout time.Duration
+	serveReloadDebounce     time.Duration
+	serveMaxResultBytes     int
+	serveReadOnly           bool
+	serveToolsRefresh       time.Duration
+	serveEvents             string
+	serveIdleTimeout        time.Duration
+	serveSelect             string
+	serveLastUsed           bool
+	serveDuplicateIDs       string
+	serveDiscoveryWorkers   int
+	serveShowDenied         bool
+	serveValidateArgs       bool
+	serveMaxTools           int
+	serveMaxToolsMode       string
+	serveDryRun             bool
+	serveInspect            bool
 )
 
 var serveCmd = &cobra.Command{
@@ -44,7 +61,17 @@ Configure in Claude Code's mcp_servers.json:
 
 Tool names are prefixed with the server ID (e.g., filesystem.read_file).
 Manager tools (mcpmu.servers_list, etc.) are hidden by default but remain
-callable. Use --expose-manager-tools to include them in tools/list.`,
+callable. Use --expose-manager-tools to include them in tools/list.
+
+--dry-run checks a config without connecting to anything: it resolves the
+namespace, lists the servers that would run and how they'd start, and the
+tools they'd expose after permission filtering as recorded in the tool
+cache, then exits.
+
+--inspect runs an interactive prompt instead of speaking MCP on stdio, for
+trying the aggregated server by hand: type a method such as tools/list or
+tools/call <name> <json arguments> and the response is pretty-printed.
+Logging defaults to errors only so it doesn't interleave with the prompt.`,
 	RunE: runServe,
 }
 
@@ -55,55 +82,86 @@ func init() {
 
 	serveCmd.Flags().StringVarP(&serveConfigPath, "config", "c", "", "Path to config file (default: ~/.config/mcpmu/config.json)")
 	serveCmd.Flags().StringVarP(&serveNamespace, "namespace", "n", "", "Namespace to expose (default: auto-select)")
+	serveCmd.Flags().BoolVar(&serveAllNamespaces, "all-namespaces", false, "Expose every enabled server, ignoring namespace permissions")
 	serveCmd.Flags().StringVarP(&serveLogLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
 	serveCmd.Flags().BoolVar(&serveEager, "eager", false, "Pre-start all servers on init (default: lazy start)")
 	serveCmd.Flags().BoolVar(&serveExposeManagerTools, "expose-manager-tools", false, "Include mcpmu.* tools in tools/list (default: hidden)")
 	serveCmd.Flags().BoolVar(&serveResources, "resources", true, "Passthrough resources/* from upstream servers")
 	serveCmd.Flags().BoolVar(&servePrompts, "prompts", true, "Passthrough prompts/* from upstream servers")
+	serveCmd.Flags().DurationVar(&serveReloadDrainTimeout, "reload-drain-timeout", server.DefaultReloadDrainTimeout, "Max wait for in-flight calls before stopping removed or changed servers on config reload")
+	serveCmd.Flags().DurationVar(&serveReloadDebounce, "reload-debounce", server.DefaultDebounceDelay, "Wait this long after the config file last changed before reloading it")
+	serveCmd.Flags().IntVar(&serveMaxResultBytes, "max-result-bytes", 0, "Truncate tools/call results larger than this many bytes (0 = unlimited)")
+	serveCmd.Flags().StringVar(&serveEvents, "events", "", "Write server lifecycle events as newline-delimited JSON to this file (- for stderr)")
+	serveCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "Deny tools whose names contain a write verb (delete, write, create, ...), regardless of permissions")
+	serveCmd.Flags().DurationVar(&serveToolsRefresh, "tools-refresh-interval", 0, "List an upstream's tools again on tools/list once its list is this old (0 = only after it restarts or reports tools/list_changed)")
+	serveCmd.Flags().BoolVar(&serveShowDenied, "show-denied-tools", false, "List denied tools marked [denied] instead of hiding them (calls are still rejected)")
+	serveCmd.Flags().StringVar(&serveSelect, "select", "", "Expose this namespace and remember it as the last-used namespace (does not change the default)")
+	serveCmd.Flags().BoolVar(&serveLastUsed, "last-used", false, "Expose the namespace last picked with --select, if it still exists")
+	serveCmd.Flags().StringVar(&serveDuplicateIDs, "duplicate-ids", string(server.DuplicateIDsQueue), "Handling of requests that reuse the id of one still in flight: queue or reject")
+	serveCmd.Flags().BoolVar(&serveValidateArgs, "validate-args", false, "Reject tool calls whose arguments don't match the tool's input schema without forwarding them")
+	serveCmd.Flags().IntVar(&serveMaxTools, "max-tools", 0, "Fail tools/list when it would expose more than this many tools (0 = unlimited)")
+	serveCmd.Flags().StringVar(&serveMaxToolsMode, "max-tools-mode", string(server.MaxToolsError), "What tools/list does over --max-tools: error or truncate")
+	serveCmd.Flags().IntVar(&serveDiscoveryWorkers, "discovery-concurrency", server.MaxConcurrentDiscovery, "Max upstream servers queried at once when listing tools, resources and prompts")
+	serveCmd.Flags().BoolVar(&serveDryRun, "dry-run", false, "Print the namespace, servers and cached tools serve would expose, then exit without starting anything")
+	serveCmd.Flags().BoolVar(&serveInspect, "inspect", false, "Type MCP methods at an interactive prompt instead of serving a client on stdio")
+	serveCmd.Flags().DurationVar(&serveIdleTimeout, "idle-timeout", 0, "Stop stdio servers with no requests for this long; they restart on the next call (0 = never)")
 
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
-	// In stdio mode, all output must go to stderr except MCP protocol
-	// Configure logging based on log level
-	switch serveLogLevel {
-	case "debug":
-		log.SetOutput(os.Stderr)
-		log.SetFlags(log.LstdFlags | log.Lshortfile)
-		server.DebugLogging = true
-		mcp.DebugLogging = true
-	case "info", "warn":
-		log.SetOutput(os.Stderr)
-		log.SetFlags(log.LstdFlags)
-	case "error":
-		log.SetOutput(io.Discard)
-	default:
-		log.SetOutput(io.Discard)
+	if serveMaxResultBytes < 0 {
+		return fmt.Errorf("--max-result-bytes must not be negative")
+	}
+	if serveToolsRefresh < 0 {
+		return fmt.Errorf("--tools-refresh-interval must not be negative")
+	}
+	if serveReloadDebounce <= 0 {
+		return fmt.Errorf("--reload-debounce must be positive")
+	}
+	if serveDiscoveryWorkers < 1 {
+		return fmt.Errorf("--discovery-concurrency must be at least 1")
+	}
+	duplicateIDs, err := server.ParseDuplicateIDPolicy(serveDuplicateIDs)
+	if err != nil {
+		return err
+	}
+	if serveMaxTools < 0 {
+		return fmt.Errorf("--max-tools must not be negative")
+	}
+	maxToolsMode, err := server.ParseMaxToolsPolicy(serveMaxToolsMode)
+	if err != nil {
+		return err
+	}
+	if serveAllNamespaces && serveNamespace != "" {
+		return fmt.Errorf("--all-namespaces and --namespace are mutually exclusive")
+	}
+	if serveSelect != "" || serveLastUsed {
+		if serveSelect != "" && serveLastUsed {
+			return fmt.Errorf("--select and --last-used are mutually exclusive")
+		}
+		if serveAllNamespaces || serveNamespace != "" {
+			return fmt.Errorf("--select and --last-used cannot be combined with --namespace or --all-namespaces")
+		}
+	}
+
+	if serveInspect {
+		if serveDryRun {
+			return fmt.Errorf("--inspect and --dry-run are mutually exclusive")
+		}
+		if !cmd.Flags().Changed("log-level") {
+			serveLogLevel = "error"
+		}
 	}
 
+	setupStdioLogging(serveLogLevel)
+
 	log.Printf("mcpmu serve starting (version=%s)", version)
 
 	// Resolve config path for hot-reload watching
-	var resolvedConfigPath string
-	if serveConfigPath != "" {
-		// Expand ~ in user-provided path
-		if strings.HasPrefix(serveConfigPath, "~/") {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return fmt.Errorf("failed to get home dir: %w", err)
-			}
-			resolvedConfigPath = filepath.Join(home, serveConfigPath[2:])
-		} else {
-			resolvedConfigPath = serveConfigPath
-		}
-	} else {
-		// Use default config path
-		var err error
-		resolvedConfigPath, err = config.ConfigPath()
-		if err != nil {
-			return fmt.Errorf("failed to get config path: %w", err)
-		}
+	resolvedConfigPath, err := resolveConfigPath(serveConfigPath)
+	if err != nil {
+		return err
 	}
 
 	// Load configuration
@@ -114,26 +172,35 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	log.Printf("Loaded config with %d servers, %d namespaces", len(cfg.Servers), len(cfg.Namespaces))
 
-	// Create server options
-	opts := server.Options{
-		Config:             cfg,
-		ConfigPath:         resolvedConfigPath, // For hot-reload watching
-		Namespace:          serveNamespace,
-		EagerStart:         serveEager,
-		ExposeManagerTools: serveExposeManagerTools,
-		ExposeResources:    serveResources,
-		ExposePrompts:      servePrompts,
-		LogLevel:           serveLogLevel,
-		Stdin:              os.Stdin,
-		Stdout:             os.Stdout,
-		Stderr:             os.Stderr,
-		ServerName:         "mcpmu",
-		ServerVersion:      version,
-		ProtocolVersion:    "2024-11-05",
+	if err := applyServeSelection(cfg, resolvedConfigPath); err != nil {
+		return err
+	}
+
+	if serveDryRun {
+		plan, err := server.BuildPlan(serveOptions(cfg, resolvedConfigPath, nil, duplicateIDs, maxToolsMode))
+		if err != nil {
+			return err
+		}
+		printServePlan(plan)
+		return nil
+	}
+
+	var eventsOutput io.Writer
+	switch serveEvents {
+	case "":
+	case "-":
+		eventsOutput = os.Stderr
+	default:
+		f, err := os.OpenFile(serveEvents, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open events output: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		eventsOutput = f
 	}
 
 	// Create and run server
-	srv, err := server.New(opts)
+	srv, err := server.New(serveOptions(cfg, resolvedConfigPath, eventsOutput, duplicateIDs, maxToolsMode))
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
@@ -151,6 +218,10 @@ func runServe(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
+	if serveInspect {
+		return srv.Inspect(ctx)
+	}
+
 	// Run the server
 	if err := srv.Run(ctx); err != nil && err != context.Canceled {
 		return fmt.Errorf("server error: %w", err)
@@ -159,3 +230,147 @@ func runServe(cmd *cobra.Command, args []string) error {
 	log.Println("mcpmu serve exiting")
 	return nil
 }
+
+// serveOptions builds the server options from the serve flags.
+func serveOptions(cfg *config.Config, configPath string, eventsOutput io.Writer, duplicateIDs server.DuplicateIDPolicy, maxToolsMode server.MaxToolsPolicy) server.Options {
+	return server.Options{
+		Config:               cfg,
+		ConfigPath:           configPath, // For hot-reload watching
+		Namespace:            serveNamespace,
+		AllNamespaces:        serveAllNamespaces,
+		EagerStart:           serveEager,
+		ExposeManagerTools:   serveExposeManagerTools,
+		ExposeResources:      serveResources,
+		ExposePrompts:        servePrompts,
+		DebounceDelay:        serveReloadDebounce,
+		ReloadDrainTimeout:   serveReloadDrainTimeout,
+		MaxResultBytes:       serveMaxResultBytes,
+		ReadOnly:             serveReadOnly,
+		ToolsRefreshInterval: serveToolsRefresh,
+		ShowDeniedTools:      serveShowDenied,
+		EventsOutput:         eventsOutput,
+		IdleTimeout:          serveIdleTimeout,
+		DuplicateIDs:         duplicateIDs,
+		DiscoveryConcurrency: serveDiscoveryWorkers,
+		ValidateArgs:         serveValidateArgs,
+		MaxTools:             serveMaxTools,
+		MaxToolsMode:         maxToolsMode,
+		LogLevel:             serveLogLevel,
+		Stdin:                os.Stdin,
+		Stdout:               os.Stdout,
+		Stderr:               os.Stderr,
+		ServerName:           "mcpmu",
+		ServerVersion:        version,
+		ProtocolVersion:      "2024-11-05",
+	}
+}
+
+// setupStdioLogging configures the standard logger for stdio MCP modes. All
+// log output goes to stderr (stdout carries the MCP protocol) and is
+// discarded entirely at the error level.
+func setupStdioLogging(level string) {
+	switch level {
+	case "debug":
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags | log.Lshortfile)
+		server.DebugLogging = true
+		mcp.DebugLogging = true
+	case "info", "warn":
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	default:
+		log.SetOutput(io.Discard)
+	}
+}
+
+// applyServeSelection handles --select and --last-used. --select exposes a
+// namespace and records it in state.json as the last-used namespace;
+// --last-used exposes that namespace, falling back to the usual default
+// namespace selection when none is recorded or it no longer exists.
+// Neither touches DefaultNamespace.
+func applyServeSelection(cfg *config.Config, configPath string) error {
+	switch {
+	case serveSelect != "":
+		if _, ok := cfg.GetNamespace(serveSelect); !ok {
+			return fmt.Errorf("namespace %q not found", serveSelect)
+		}
+		serveNamespace = serveSelect
+		if serveDryRun {
+			return nil
+		}
+
+		st, err := config.LoadState(configPath)
+		if err != nil {
+			log.Printf("Warning: failed to load state: %v", err)
+		}
+		st.LastUsedNamespace = serveSelect
+		if err := config.SaveState(configPath, st); err != nil {
+			log.Printf("Warning: failed to record last-used namespace: %v", err)
+		}
+
+	case serveLastUsed:
+		st, err := config.LoadState(configPath)
+		if err != nil {
+			log.Printf("Warning: failed to load state: %v", err)
+		}
+		if st.LastUsedNamespace == "" {
+			log.Printf("No last-used namespace recorded, using default selection")
+			return nil
+		}
+		if _, ok := cfg.GetNamespace(st.LastUsedNamespace); !ok {
+			log.Printf("Last-used namespace %q no longer exists, using default selection", st.LastUsedNamespace)
+			return nil
+		}
+		log.Printf("Using last-used namespace %q", st.LastUsedNamespace)
+		serveNamespace = st.LastUsedNamespace
+	}
+	return nil
+}
+
+// printServePlan prints the result of serve --dry-run.
+func printServePlan(plan *server.Plan) {
+	if plan.Namespace != "" {
+		fmt.Printf("Namespace: %s (selection: %s)\n", plan.Namespace, plan.Selection)
+	} else {
+		fmt.Printf("Namespace: none (selection: %s)\n", plan.Selection)
+	}
+	if len(plan.Servers) == 0 {
+		fmt.Println("No servers would be exposed")
+		return
+	}
+
+	nameWidth := len("NAME")
+	for _, srv := range plan.Servers {
+		nameWidth = max(nameWidth, len(srv.Name))
+	}
+	fmt.Printf("\n%-*s  %-9s  %-8s  %s\n", nameWidth, "NAME", "TRANSPORT", "START", "TOOLS")
+	uncached := 0
+	for _, srv := range plan.Servers {
+		transport := srv.Transport
+		if transport == "" {
+			transport = "-"
+		}
+		tools := "-"
+		switch {
+		case srv.Start == "missing" || srv.Start == "disabled":
+		case !srv.Cached:
+			tools = "not cached"
+			uncached++
+		case srv.Denied > 0:
+			tools = fmt.Sprintf("%d (%d denied)", len(srv.Tools), srv.Denied)
+		default:
+			tools = fmt.Sprintf("%d", len(srv.Tools))
+		}
+		fmt.Printf("%-*s  %-9s  %-8s  %s\n", nameWidth, srv.Name, transport, srv.Start, tools)
+	}
+
+	fmt.Printf("\n%d tool(s) exposed:\n", plan.ToolCount())
+	for _, srv := range plan.Servers {
+		for _, tool := range srv.Tools {
+			fmt.Printf("  %s\n", tool)
+		}
+	}
+	if uncached > 0 {
+		fmt.Printf("\n%d server(s) have no cached tools; their tools are listed once they have run\n", uncached)
+	}
+}
@@ -20,6 +20,8 @@ func init() {
 	serverCmd.AddCommand(serverDenyToolCmd)
 	serverCmd.AddCommand(serverAllowToolCmd)
 	serverCmd.AddCommand(serverDeniedToolsCmd)
+	serverCmd.AddCommand(serverTrustCmd)
+	serverCmd.AddCommand(serverUntrustCmd)
 }
 
 // ============================================================================
@@ -192,3 +194,62 @@ func runServerDeniedTools(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// ============================================================================
+// server trust / untrust
+// ============================================================================
+
+var serverTrustCmd = &cobra.Command{
+	Use:   "trust <server>",
+	Short: "Allow a server's tools by default in deny-by-default namespaces",
+	Long: `Mark a server as trusted.
+
+In a namespace that denies by default, a trusted server's tools are allowed
+unless a permission says otherwise, so they don't each need an explicit
+allow. Explicit tool denies, the namespace's per-server default
+(permission set-server-default) and the server's global deny list still
+take precedence. Namespaces that allow by default are unaffected.
+
+Examples:
+  mcpmu server trust filesystem`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServerSetTrusted(args[0], true)
+	},
+}
+
+var serverUntrustCmd = &cobra.Command{
+	Use:   "untrust <server>",
+	Short: "Stop treating a server as trusted",
+	Long: `Remove a server's trusted mark, so deny-by-default namespaces deny its
+tools unless they are explicitly allowed.
+
+Examples:
+  mcpmu server untrust filesystem`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServerSetTrusted(args[0], false)
+	},
+}
+
+func runServerSetTrusted(serverName string, trusted bool) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.SetServerTrusted(serverName, trusted); err != nil {
+		return err
+	}
+
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+
+	if trusted {
+		fmt.Printf("Server %q is now trusted\n", serverName)
+	} else {
+		fmt.Printf("Server %q is no longer trusted\n", serverName)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topNamespace  string
+	topInterval   time.Duration
+	topOnce       bool
+	topConfigPath string
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show a live table of server status",
+	Long: `Start the configured servers and show a compact, auto-refreshing table of
+their state, PID, uptime, tool count and last error. Press Ctrl+C to stop the
+servers and exit.
+
+This is a lightweight alternative to the TUI for tmux panes and SSH sessions.
+mcpmu top runs its own copies of the servers; it does not attach to a running
+serve or TUI instance. Disabled servers are listed but not started.
+
+With --once, the table is printed a single time after every server has
+finished starting, which is handy in scripts.
+
+Examples:
+  mcpmu top
+  mcpmu top --namespace work --interval 5s
+  mcpmu top --once`,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().StringVarP(&topNamespace, "namespace", "n", "", "Only show servers in this namespace")
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval")
+	topCmd.Flags().BoolVar(&topOnce, "once", false, "Print the table once after startup and exit")
+	topCmd.Flags().StringVarP(&topConfigPath, "config", "c", "", "Path to config file (default: ~/.config/mcpmu/config.json)")
+
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if topInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	cfg, err := loadConfig(topConfigPath)
+	if err != nil {
+		return err
+	}
+	servers, err := topServers(cfg, topNamespace)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		fmt.Println("No servers configured")
+		return nil
+	}
+
+	// Supervisor and transport logging would scribble over the table.
+	log.SetOutput(io.Discard)
+
+	var configDir string
+	if path, err := resolveConfigPath(topConfigPath); err == nil {
+		configDir = filepath.Dir(path)
+	}
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	board := newTopBoard(servers)
+	bus.Subscribe(board.handle)
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+		CredentialStoreMode:     cfg.MCPOAuthCredentialStore,
+		CredentialEncryption:    cfg.MCPOAuthCredentialEncryption,
+		GlobalOAuthCallbackPort: cfg.MCPOAuthCallbackPort,
+		PIDFilePrefix:           "top",
+		ConfigDir:               configDir,
+		MaxLogLines:             cfg.MaxLogLines,
+		InitRetries:             cfg.InitRetries,
+		InitRetryBackoff:        time.Duration(cfg.InitRetryBackoffMs) * time.Millisecond,
+	})
+	defer supervisor.StopAll()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, entry := range servers {
+		if !entry.Config.IsEnabled() {
+			continue
+		}
+		wg.Go(func() {
+			timeout := time.Duration(entry.Config.StartupTimeout()) * time.Second
+			startCtx, startCancel := context.WithTimeout(ctx, timeout)
+			defer startCancel()
+			handle, err := supervisor.Start(startCtx, entry.Name, entry.Config)
+			if err != nil {
+				board.setError(entry.Name, err.Error())
+				return
+			}
+			board.setStartedAt(entry.Name, handle.StartedAt())
+			if err := handle.WaitForTools(startCtx); err != nil {
+				board.setError(entry.Name, err.Error())
+			}
+		})
+	}
+
+	if topOnce {
+		wg.Wait()
+		board.render(os.Stdout, time.Now())
+		return nil
+	}
+
+	ticker := time.NewTicker(topInterval)
+	defer ticker.Stop()
+	for {
+		// Move the cursor home and clear the screen before each frame
+		fmt.Print("\033[H\033[2J")
+		board.render(os.Stdout, time.Now())
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nStopping servers...")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// topServers returns the servers shown by top, sorted by name: all of them,
+// or just those in the given namespace.
+func topServers(cfg *config.Config, namespace string) ([]config.ServerEntry, error) {
+	servers := cfg.ServerEntries()
+	if namespace != "" {
+		ns, ok := cfg.GetNamespace(namespace)
+		if !ok {
+			return nil, fmt.Errorf("namespace %q not found", namespace)
+		}
+		var filtered []config.ServerEntry
+		for _, entry := range servers {
+			for _, id := range ns.ServerIDs {
+				if entry.Name == id {
+					filtered = append(filtered, entry)
+					break
+				}
+			}
+		}
+		servers = filtered
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+	return servers, nil
+}
+
+// topRow is the live status of one server in the top table.
+type topRow struct {
+	name      string
+	disabled  bool
+	state     events.RuntimeState
+	pid       int
+	startedAt time.Time
+	tools     int
+	lastError string
+}
+
+// topBoard collects server status from the event bus for rendering.
+type topBoard struct {
+	mu   sync.Mutex
+	rows map[string]*topRow
+}
+
+func newTopBoard(servers []config.ServerEntry) *topBoard {
+	b := &topBoard{rows: make(map[string]*topRow, len(servers))}
+	for _, entry := range servers {
+		b.rows[entry.Name] = &topRow{name: entry.Name, disabled: !entry.Config.IsEnabled()}
+	}
+	return b
+}
+
+// handle updates the board from a bus event. Events for servers not on the
+// board are ignored.
+func (b *topBoard) handle(e events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	row, ok := b.rows[e.ServerID()]
+	if !ok {
+		return
+	}
+	switch evt := e.(type) {
+	case events.StatusChangedEvent:
+		row.state = evt.NewState
+		if evt.Status.PID != 0 || !evt.NewState.IsActive() {
+			row.pid = evt.Status.PID
+		}
+		if !evt.NewState.IsActive() {
+			row.startedAt = time.Time{}
+		}
+		if evt.Status.Error != "" {
+			row.lastError = redact.String(evt.Status.Error)
+		}
+	case events.ToolsUpdatedEvent:
+		row.tools = len(evt.Tools)
+	case events.ErrorEvent:
+		msg := evt.Message
+		if msg == "" && evt.Err != nil {
+			msg = evt.Err.Error()
+		}
+		row.lastError = redact.String(msg)
+	}
+}
+
+func (b *topBoard) setStartedAt(name string, t time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if row, ok := b.rows[name]; ok && row.state.IsActive() {
+		row.startedAt = t
+	}
+}
+
+// setError records a startup error returned to the caller, unless the
+// supervisor already reported a more specific one.
+func (b *topBoard) setError(name, msg string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if row, ok := b.rows[name]; ok && row.lastError == "" {
+		row.lastError = redact.String(msg)
+	}
+}
+
+// render writes one frame of the table, with uptimes measured at now.
+func (b *topBoard) render(w io.Writer, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	names := make([]string, 0, len(b.rows))
+	nameWidth := 6 // "SERVER"
+	for name := range b.rows {
+		names = append(names, name)
+		if len(name) > nameWidth {
+			nameWidth = len(name)
+		}
+	}
+	sort.Strings(names)
+
+	running := 0
+	for _, row := range b.rows {
+		if row.state == events.StateRunning {
+			running++
+		}
+	}
+	_, _ = fmt.Fprintf(w, "mcpmu top - %s - %d/%d running\n\n", now.Format("15:04:05"), running, len(b.rows))
+	_, _ = fmt.Fprintf(w, "%-*s  %-10s  %-7s  %-9s  %-5s  %s\n", nameWidth, "SERVER", "STATE", "PID", "UPTIME", "TOOLS", "LAST ERROR")
+
+	for _, name := range names {
+		row := b.rows[name]
+
+		state := row.state.String()
+		if row.disabled {
+			state = "disabled"
+		}
+		pid, uptime, tools := "-", "-", "-"
+		if row.pid != 0 {
+			pid = fmt.Sprint(row.pid)
+		}
+		if !row.startedAt.IsZero() {
+			uptime = now.Sub(row.startedAt).Round(time.Second).String()
+		}
+		if row.state == events.StateRunning {
+			tools = fmt.Sprint(row.tools)
+		}
+		lastError := row.lastError
+		if runes := []rune(lastError); len(runes) > 60 {
+			lastError = string(runes[:57]) + "..."
+		}
+		if lastError == "" {
+			lastError = "-"
+		}
+
+		_, _ = fmt.Fprintf(w, "%-*s  %-10s  %-7s  %-9s  %-5s  %s\n", nameWidth, name, state, pid, uptime, tools, lastError)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tryName               string
+	tryURL                string
+	tryBearerEnv          string
+	tryEnvFlags           []string
+	tryCwd                string
+	tryLogLevel           string
+	tryExposeManagerTools bool
+	tryVerbose            bool
+)
+
+var tryCmd = &cobra.Command{
+	Use:   "try [--url <url>] [-- <command> [args...]]",
+	Short: "Serve a single ad-hoc server without adding it to the config",
+	Long: `Run serve mode over stdio with one server built from flags. Nothing is
+written to the config file, so this is a quick way to try out a server
+before adding it.
+
+Tools are named <name>.<tool>, where --name defaults to "try". With
+--verbose, every JSON-RPC request and response exchanged with the server is
+echoed to stderr, pretty-printed with secrets masked, which helps when
+working out the argument shapes a tool expects.
+
+Examples:
+  mcpmu try -- npx -y @modelcontextprotocol/server-filesystem /tmp
+  mcpmu try --env API_KEY=secret -- ./server --flag
+  mcpmu try --url https://example.com/mcp --bearer-env API_TOKEN
+  mcpmu try --verbose -- ./server`,
+	RunE: runTry,
+}
+
+func init() {
+	tryCmd.Flags().StringVar(&tryName, "name", "try", "Server name used to qualify tool names")
+	tryCmd.Flags().StringVar(&tryURL, "url", "", "Server URL for HTTP transport (streamable HTTP)")
+	tryCmd.Flags().StringVar(&tryBearerEnv, "bearer-env", "", "Environment variable containing bearer token")
+	tryCmd.Flags().StringArrayVarP(&tryEnvFlags, "env", "e", nil, "Environment variable (KEY=VALUE), can be repeated")
+	tryCmd.Flags().StringVar(&tryCwd, "cwd", "", "Working directory for the server")
+	tryCmd.Flags().StringVarP(&tryLogLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
+	tryCmd.Flags().BoolVar(&tryExposeManagerTools, "expose-manager-tools", false, "Include mcpmu.* tools in tools/list (default: hidden)")
+	tryCmd.Flags().BoolVarP(&tryVerbose, "verbose", "v", false, "Echo JSON-RPC frames exchanged with the server to stderr (secrets masked)")
+
+	rootCmd.AddCommand(tryCmd)
+}
+
+func runTry(cmd *cobra.Command, args []string) error {
+	srv, err := tryServerConfig(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.NewConfig()
+	if err := cfg.AddServer(tryName, srv); err != nil {
+		return err
+	}
+
+	setupStdioLogging(tryLogLevel)
+	log.Printf("mcpmu try starting (version=%s)", version)
+
+	srvOpts := server.Options{
+		Config:             cfg,
+		PIDFilePrefix:      "try",
+		ExposeManagerTools: tryExposeManagerTools,
+		ExposeResources:    true,
+		ExposePrompts:      true,
+		LogLevel:           tryLogLevel,
+		Stdin:              os.Stdin,
+		Stdout:             os.Stdout,
+		Stderr:             os.Stderr,
+		ServerName:         "mcpmu",
+		ServerVersion:      version,
+		ProtocolVersion:    "2024-11-05",
+	}
+	if tryVerbose {
+		srvOpts.TraceOutput = os.Stderr
+	}
+
+	s, err := server.New(srvOpts)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigCh
+		log.Printf("Received signal %v, shutting down", sig)
+		cancel()
+	}()
+
+	if err := s.Run(ctx); err != nil && err != context.Canceled {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	log.Println("mcpmu try exiting")
+	return nil
+}
+
+// tryServerConfig builds the in-memory server config for try from its flags
+// and the command after --.
+func tryServerConfig(cmd *cobra.Command, args []string) (config.ServerConfig, error) {
+	env, err := parseEnvFlags(tryEnvFlags)
+	if err != nil {
+		return config.ServerConfig{}, err
+	}
+
+	dashIdx := cmd.ArgsLenAtDash()
+	if tryURL != "" {
+		if len(args) > 0 {
+			return config.ServerConfig{}, fmt.Errorf("--url cannot be combined with a command")
+		}
+		if tryCwd != "" {
+			return config.ServerConfig{}, fmt.Errorf("--cwd is only valid for stdio servers")
+		}
+		return config.ServerConfig{
+			URL:               tryURL,
+			BearerTokenEnvVar: tryBearerEnv,
+			Env:               env,
+		}, nil
+	}
+
+	if tryBearerEnv != "" {
+		return config.ServerConfig{}, fmt.Errorf("--bearer-env is only valid for HTTP servers")
+	}
+	if dashIdx != 0 || len(args) == 0 {
+		return config.ServerConfig{}, fmt.Errorf("missing command\n\nUsage: mcpmu try -- <command> [args...] or mcpmu try --url <url>")
+	}
+	return config.ServerConfig{
+		Command: args[0],
+		Args:    args[1:],
+		Cwd:     tryCwd,
+		Env:     env,
+	}, nil
+}
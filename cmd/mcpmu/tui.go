@@ -6,17 +6,33 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/events"
 	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
 	"github.com/Bigsy/mcpmu/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
-var tuiDebug bool
+var (
+	tuiDebug       bool
+	tuiLastUsed    bool
+	tuiNoAutostart bool
+	tuiLogFile     string
+	tuiLogLevel    string
+)
+
+// logFileEnv names the environment variable that sets the TUI's log file
+// when --log-file isn't given.
+const logFileEnv = "MCPMU_LOG_FILE"
+
+// debugLogPath is where --debug writes when no log file is chosen.
+const debugLogPath = "/tmp/mcpmu-debug.log"
 
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
@@ -33,23 +49,19 @@ Use this for:
 
 func init() {
 	tuiCmd.Flags().BoolVar(&tuiDebug, "debug", false, "Enable debug logging to /tmp/mcpmu-debug.log")
+	tuiCmd.Flags().BoolVar(&tuiLastUsed, "last-used", false, "Open on the namespace last picked with serve --select")
+	tuiCmd.Flags().BoolVar(&tuiNoAutostart, "no-autostart", false, "Don't start autostart servers on launch (press A to start them later)")
+	tuiCmd.Flags().StringVar(&tuiLogFile, "log-file", "", "Append the TUI's own logs to this file (or set "+logFileEnv+")")
+	tuiCmd.Flags().StringVar(&tuiLogLevel, "log-level", "", "Log level for --log-file or --debug: debug, info, warn, error (default: info, or debug with --debug)")
 	rootCmd.AddCommand(tuiCmd)
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
-	// Set up debug logging to file if enabled
-	if tuiDebug {
-		logFile, err := os.OpenFile("/tmp/mcpmu-debug.log", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err == nil {
-			log.SetOutput(logFile)
-			log.SetFlags(log.LstdFlags | log.Lshortfile)
-			defer func() { _ = logFile.Close() }()
-			log.Println("=== mcpmu TUI starting (debug mode) ===")
-		}
-	} else {
-		// Discard logs when not in debug mode
-		log.SetOutput(io.Discard)
+	closeLog, err := setupTUILogging(tuiDebug, tuiLogFile, tuiLogLevel)
+	if err != nil {
+		return err
 	}
+	defer closeLog()
 
 	// Acquire manager lock (prevents concurrent TUI/web instances for same config)
 	mgrLock, err := process.NewManagerLock(configPath)
@@ -86,11 +98,11 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		switch evt := e.(type) {
 		case events.StatusChangedEvent:
 			log.Printf("EVENT StatusChanged: server=%s old=%s new=%s err=%s",
-				evt.ServerID(), evt.OldState, evt.NewState, evt.Status.Error)
+				evt.ServerID(), evt.OldState, evt.NewState, redact.String(evt.Status.Error))
 		case events.LogReceivedEvent:
-			log.Printf("EVENT Log: server=%s line=%s", evt.ServerID(), evt.Line)
+			log.Printf("EVENT Log: server=%s line=%s", evt.ServerID(), redact.String(evt.Line))
 		case events.ErrorEvent:
-			log.Printf("EVENT Error: server=%s msg=%s err=%v", evt.ServerID(), evt.Message, evt.Err)
+			log.Printf("EVENT Error: server=%s msg=%s err=%s", evt.ServerID(), redact.String(evt.Message), redact.String(fmt.Sprint(evt.Err)))
 		case events.ToolsUpdatedEvent:
 			log.Printf("EVENT ToolsUpdated: server=%s count=%d", evt.ServerID(), len(evt.Tools))
 		}
@@ -102,16 +114,38 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		log.Printf("Warning: failed to create tool cache: %v", err)
 	}
 
+	resolvedConfigPath, err := resolveConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+
 	// Create process supervisor (PIDFilePrefix isolates from serve's pids.json)
 	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
 		CredentialStoreMode:     cfg.MCPOAuthCredentialStore,
+		CredentialEncryption:    cfg.MCPOAuthCredentialEncryption,
 		GlobalOAuthCallbackPort: cfg.MCPOAuthCallbackPort,
 		PIDFilePrefix:           "tui",
+		ConfigDir:               filepath.Dir(resolvedConfigPath),
+		MaxLogLines:             cfg.MaxLogLines,
+		InitRetries:             cfg.InitRetries,
+		InitRetryBackoff:        time.Duration(cfg.InitRetryBackoffMs) * time.Millisecond,
 	})
 	supervisor.SetToolCache(toolCache)
 
 	// Create TUI model
 	model := tui.NewModel(cfg, supervisor, bus, configPath, toolCache)
+	if tuiNoAutostart {
+		model.DisableAutostart()
+	}
+	if tuiLastUsed {
+		st, err := config.LoadState(configPath)
+		if err != nil {
+			log.Printf("Warning: failed to load state: %v", err)
+		}
+		if st.LastUsedNamespace != "" {
+			model.FocusNamespace(st.LastUsedNamespace)
+		}
+	}
 
 	// Set up signal handling for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -145,3 +179,57 @@ func runTUI(cmd *cobra.Command, args []string) error {
 	log.Println("=== mcpmu TUI exiting ===")
 	return nil
 }
+
+// setupTUILogging points the standard logger somewhere other than the
+// terminal the TUI draws on. Logs go to logFile (or $MCPMU_LOG_FILE),
+// appended so they persist across runs; with --debug and no log file they
+// go to /tmp/mcpmu-debug.log, truncated each run. Otherwise they are
+// discarded. The returned func closes the file.
+func setupTUILogging(debug bool, logFile, level string) (func(), error) {
+	switch level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return nil, fmt.Errorf("invalid --log-level %q: must be debug, info, warn or error", level)
+	}
+	if logFile == "" {
+		logFile = os.Getenv(logFileEnv)
+	}
+	if level == "" {
+		level = "info"
+		if debug {
+			level = "debug"
+		}
+	}
+
+	discard := func() (func(), error) {
+		log.SetOutput(io.Discard)
+		return func() {}, nil
+	}
+	if (logFile == "" && !debug) || level == "error" {
+		return discard()
+	}
+
+	var f *os.File
+	var err error
+	if logFile != "" {
+		f, err = os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("open log file: %w", err)
+		}
+	} else {
+		f, err = os.OpenFile(debugLogPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return discard()
+		}
+	}
+
+	log.SetOutput(f)
+	if level == "debug" {
+		log.SetFlags(log.LstdFlags | log.Lshortfile)
+		log.Println("=== mcpmu TUI starting (debug mode) ===")
+	} else {
+		log.SetFlags(log.LstdFlags)
+		log.Println("=== mcpmu TUI starting ===")
+	}
+	return func() { _ = f.Close() }, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupTUILogging_LogFile(t *testing.T) {
+	t.Cleanup(func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	})
+	t.Setenv(logFileEnv, "")
+
+	path := filepath.Join(t.TempDir(), "tui.log")
+	for _, msg := range []string{"first run", "second run"} {
+		closeLog, err := setupTUILogging(false, path, "")
+		if err != nil {
+			t.Fatalf("setupTUILogging: %v", err)
+		}
+		log.Printf("%s", msg)
+		closeLog()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the log file to be written: %v", err)
+	}
+	// Runs append rather than truncate
+	for _, want := range []string{"mcpmu TUI starting", "first run", "second run"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in the log file, got:\n%s", want, data)
+		}
+	}
+
+	// The environment variable stands in for the flag
+	envPath := filepath.Join(t.TempDir(), "env.log")
+	t.Setenv(logFileEnv, envPath)
+	closeLog, err := setupTUILogging(false, "", "debug")
+	if err != nil {
+		t.Fatalf("setupTUILogging with %s: %v", logFileEnv, err)
+	}
+	log.Printf("from env")
+	closeLog()
+	if data, _ := os.ReadFile(envPath); !strings.Contains(string(data), "from env") || !strings.Contains(string(data), "tui_test.go") {
+		t.Errorf("expected a debug-level line with its source file in %s, got:\n%s", envPath, data)
+	}
+
+	if _, err := setupTUILogging(false, path, "verbose"); err == nil {
+		t.Error("expected an invalid --log-level to be rejected")
+	}
+}
@@ -9,11 +9,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/events"
 	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
 	"github.com/Bigsy/mcpmu/internal/web"
 	"github.com/spf13/cobra"
 )
@@ -94,11 +97,11 @@ func runWeb(cmd *cobra.Command, args []string) error {
 		switch evt := e.(type) {
 		case events.StatusChangedEvent:
 			log.Printf("EVENT StatusChanged: server=%s old=%s new=%s err=%s",
-				evt.ServerID(), evt.OldState, evt.NewState, evt.Status.Error)
+				evt.ServerID(), evt.OldState, evt.NewState, redact.String(evt.Status.Error))
 		case events.LogReceivedEvent:
-			log.Printf("EVENT Log: server=%s line=%s", evt.ServerID(), evt.Line)
+			log.Printf("EVENT Log: server=%s line=%s", evt.ServerID(), redact.String(evt.Line))
 		case events.ErrorEvent:
-			log.Printf("EVENT Error: server=%s msg=%s err=%v", evt.ServerID(), evt.Message, evt.Err)
+			log.Printf("EVENT Error: server=%s msg=%s err=%s", evt.ServerID(), redact.String(evt.Message), redact.String(fmt.Sprint(evt.Err)))
 		case events.ToolsUpdatedEvent:
 			log.Printf("EVENT ToolsUpdated: server=%s count=%d", evt.ServerID(), len(evt.Tools))
 		}
@@ -110,11 +113,21 @@ func runWeb(cmd *cobra.Command, args []string) error {
 		log.Printf("Warning: failed to create tool cache: %v", err)
 	}
 
+	resolvedConfigPath, err := resolveConfigPath(configPath)
+	if err != nil {
+		return err
+	}
+
 	// Create process supervisor (PIDFilePrefix isolates from serve's pids.json)
 	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
 		CredentialStoreMode:     cfg.MCPOAuthCredentialStore,
+		CredentialEncryption:    cfg.MCPOAuthCredentialEncryption,
 		GlobalOAuthCallbackPort: cfg.MCPOAuthCallbackPort,
 		PIDFilePrefix:           "web",
+		ConfigDir:               filepath.Dir(resolvedConfigPath),
+		MaxLogLines:             cfg.MaxLogLines,
+		InitRetries:             cfg.InitRetries,
+		InitRetryBackoff:        time.Duration(cfg.InitRetryBackoffMs) * time.Millisecond,
 	})
 	supervisor.SetToolCache(toolCache)
 
@@ -143,14 +156,7 @@ func runWeb(cmd *cobra.Command, args []string) error {
 	defer cancel()
 	go srv.WatchConfig(ctx)
 
-	for name, srvCfg := range cfg.Servers {
-		if srvCfg.Autostart && srvCfg.IsEnabled() {
-			log.Printf("Autostarting server: %s", name)
-			if _, err := supervisor.Start(ctx, name, srvCfg); err != nil {
-				log.Printf("Warning: failed to autostart %s: %v", name, err)
-			}
-		}
-	}
+	go supervisor.Autostart(ctx, cfg.AutostartEntries(), cfg.AutostartConcurrency)
 
 	// Graceful shutdown on signal
 	sigCh := make(chan os.Signal, 1)
@@ -17,13 +17,102 @@ const (
 	configFile = "config.json"
 )
 
-// ConfigPath returns the full path to the config file.
-func ConfigPath() (string, error) {
+// ConfigPathEnv names the environment variable that overrides the default
+// config file path. An explicit --config flag takes precedence over it.
+const ConfigPathEnv = "MCPMU_CONFIG"
+
+// HomeEnv names the environment variable that relocates mcpmu's directory:
+// the default config file along with the tool cache, state, PID tracking,
+// manager lock and file credential store. The --config-dir flag takes
+// precedence over it.
+const HomeEnv = "MCPMU_HOME"
+
+// homeOverride is the directory set with SetHome (--config-dir).
+var homeOverride string
+
+// SetHome relocates mcpmu's directory for the rest of the process, taking
+// precedence over $MCPMU_HOME. Pass "" to clear it.
+func SetHome(dir string) {
+	homeOverride = dir
+}
+
+// homeOverridden returns the relocated mcpmu directory, or "" if neither
+// SetHome nor $MCPMU_HOME is in effect.
+func homeOverridden() (string, error) {
+	dir := homeOverride
+	if dir == "" {
+		dir = os.Getenv(HomeEnv)
+	}
+	if dir == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get home dir: %w", err)
+		}
+		dir = filepath.Join(home, dir[2:])
+	}
+	return dir, nil
+}
+
+// Dir returns mcpmu's directory: the --config-dir or $MCPMU_HOME override
+// when set, otherwise ~/.config/mcpmu.
+func Dir() (string, error) {
+	dir, err := homeOverridden()
+	if err != nil || dir != "" {
+		return dir, err
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("get home dir: %w", err)
 	}
-	return filepath.Join(home, configDir, configFile), nil
+	return filepath.Join(home, configDir), nil
+}
+
+// StateDir returns the directory for files kept alongside a config (tool
+// cache, state, manager lock): the --config-dir or $MCPMU_HOME override when
+// set, otherwise the directory holding configPath (or the default config).
+func StateDir(configPath string) (string, error) {
+	dir, err := homeOverridden()
+	if err != nil || dir != "" {
+		return dir, err
+	}
+	if configPath == "" {
+		path, err := ConfigPath()
+		if err != nil {
+			return "", err
+		}
+		configPath = path
+	}
+	if strings.HasPrefix(configPath, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("get home dir: %w", err)
+		}
+		configPath = filepath.Join(home, configPath[2:])
+	}
+	return filepath.Dir(configPath), nil
+}
+
+// ConfigPath returns the full path to the config file: $MCPMU_CONFIG when
+// set, otherwise config.json in Dir().
+func ConfigPath() (string, error) {
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		if strings.HasPrefix(path, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("get home dir: %w", err)
+			}
+			path = filepath.Join(home, path[2:])
+		}
+		return path, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFile), nil
 }
 
 // Load reads the configuration from the default path.
@@ -39,6 +128,18 @@ func Load() (*Config, error) {
 // LoadFrom reads the configuration from a specific path.
 // Returns a new empty config if the file doesn't exist.
 func LoadFrom(path string) (*Config, error) {
+	return load(path, true)
+}
+
+// LoadExisting is like LoadFrom but fails with an error wrapping
+// fs.ErrNotExist when the file is missing, rather than returning an empty
+// config. Config watchers use it so a file that is briefly gone during
+// another tool's rewrite doesn't read as "no servers".
+func LoadExisting(path string) (*Config, error) {
+	return load(path, false)
+}
+
+func load(path string, allowMissing bool) (*Config, error) {
 	// Expand ~ in path
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
@@ -50,7 +151,7 @@ func LoadFrom(path string) (*Config, error) {
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) && allowMissing {
 			return NewConfig(), nil
 		}
 		return nil, fmt.Errorf("read config: %w", err)
@@ -87,8 +188,13 @@ func Save(cfg *Config) error {
 }
 
 // SaveTo writes the configuration to a specific path atomically.
-// Uses a temp file + rename pattern for atomic writes.
+// Uses a temp file + rename pattern for atomic writes, serialized with other
+// writers (e.g. a running TUI and a CLI command) by an advisory file lock.
 func SaveTo(cfg *Config, path string) error {
+	if path == "" {
+		return errors.New("config path is empty")
+	}
+
 	// Expand ~ in path
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
@@ -109,6 +215,12 @@ func SaveTo(cfg *Config, path string) error {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Update timestamp
 	cfg.LastModified = time.Now()
 
@@ -167,12 +279,17 @@ func (c *Config) AddServer(name string, srv ServerConfig) error {
 	}
 
 	c.Servers[name] = srv
+	if err := c.validateToolPrefixes(); err != nil {
+		delete(c.Servers, name)
+		return err
+	}
 	return nil
 }
 
 // UpdateServer updates an existing server configuration.
 func (c *Config) UpdateServer(name string, srv ServerConfig) error {
-	if _, exists := c.Servers[name]; !exists {
+	old, exists := c.Servers[name]
+	if !exists {
 		return fmt.Errorf("server %q not found", name)
 	}
 
@@ -182,6 +299,10 @@ func (c *Config) UpdateServer(name string, srv ServerConfig) error {
 	}
 
 	c.Servers[name] = srv
+	if err := c.validateToolPrefixes(); err != nil {
+		c.Servers[name] = old
+		return err
+	}
 	return nil
 }
 
@@ -205,6 +326,11 @@ func (c *Config) DeleteServer(name string) error {
 		c.Namespaces[nsName] = ns
 	}
 
+	// Clean up group members
+	for group, members := range c.Groups {
+		c.Groups[group] = slices.DeleteFunc(slices.Clone(members), func(item string) bool { return item == name })
+	}
+
 	// Clean up tool permissions
 	filtered := make([]ToolPermission, 0, len(c.ToolPermissions))
 	for _, tp := range c.ToolPermissions {
@@ -214,6 +340,13 @@ func (c *Config) DeleteServer(name string) error {
 	}
 	c.ToolPermissions = filtered
 
+	// Clean up tool overrides
+	for key := range c.ToolOverrides {
+		if strings.HasPrefix(key, name+".") {
+			delete(c.ToolOverrides, key)
+		}
+	}
+
 	return nil
 }
 
@@ -229,6 +362,11 @@ func (c *Config) RenameServer(oldName, newName string) error {
 	if err := ValidateName(newName); err != nil {
 		return fmt.Errorf("invalid name: %w", err)
 	}
+	if srv.ToolPrefix == "" {
+		if owner, taken := c.ServerForToolPrefix(newName); taken {
+			return fmt.Errorf("name %q is already used as the tool prefix of server %q", newName, owner)
+		}
+	}
 
 	// Move in servers map
 	delete(c.Servers, oldName)
@@ -250,6 +388,15 @@ func (c *Config) RenameServer(oldName, newName string) error {
 		c.Namespaces[nsName] = ns
 	}
 
+	// Update group members
+	for _, members := range c.Groups {
+		for i, member := range members {
+			if member == oldName {
+				members[i] = newName
+			}
+		}
+	}
+
 	// Update tool permissions
 	for i, tp := range c.ToolPermissions {
 		if tp.Server == oldName {
@@ -257,6 +404,16 @@ func (c *Config) RenameServer(oldName, newName string) error {
 		}
 	}
 
+	// Update tool override keys ("server.tool")
+	renamed := make(map[string]ToolOverride)
+	for key, o := range c.ToolOverrides {
+		if tool, ok := strings.CutPrefix(key, oldName+"."); ok {
+			delete(c.ToolOverrides, key)
+			renamed[newName+"."+tool] = o
+		}
+	}
+	maps.Copy(c.ToolOverrides, renamed)
+
 	return nil
 }
 
@@ -564,6 +721,17 @@ func (c *Config) AllowTool(serverName, toolName string) error {
 	return nil
 }
 
+// SetServerTrusted marks a server as trusted or not (see ServerConfig.Trusted).
+func (c *Config) SetServerTrusted(serverName string, trusted bool) error {
+	srv, exists := c.Servers[serverName]
+	if !exists {
+		return fmt.Errorf("server %q not found", serverName)
+	}
+	srv.Trusted = trusted
+	c.Servers[serverName] = srv
+	return nil
+}
+
 // GetDeniedTools returns a sorted copy of a server's global deny list.
 func (c *Config) GetDeniedTools(serverName string) ([]string, error) {
 	srv, exists := c.Servers[serverName]
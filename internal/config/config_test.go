@@ -2,9 +2,15 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Bigsy/mcpmu/internal/testutil"
@@ -31,6 +37,25 @@ func TestLoad_NonExistentFile(t *testing.T) {
 	}
 }
 
+func TestLoadExisting_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	if _, err := LoadExisting(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"schemaVersion": 1, "servers": {"a": {"command": "echo"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadExisting(path)
+	if err != nil {
+		t.Fatalf("LoadExisting failed: %v", err)
+	}
+	if len(cfg.Servers) != 1 {
+		t.Errorf("expected 1 server, got %d", len(cfg.Servers))
+	}
+}
+
 func TestLoad_ValidConfig(t *testing.T) {
 	home := testutil.SetupTestHome(t)
 
@@ -164,6 +189,76 @@ func TestSave_PreservesSymlink(t *testing.T) {
 	}
 }
 
+func TestSaveTo_ConcurrentWritersSerialize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	// Two configs that differ in every server so interleaved writes would be
+	// detectable; many servers make each write large enough to interleave.
+	newNamedConfig := func(prefix string) *Config {
+		cfg := NewConfig()
+		for i := range 200 {
+			cfg.Servers[fmt.Sprintf("%s-%03d", prefix, i)] = ServerConfig{
+				Kind:    ServerKindStdio,
+				Command: prefix,
+				Args:    []string{strings.Repeat(prefix, 20)},
+			}
+		}
+		return cfg
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, prefix := range []string{"alpha", "beta"} {
+		wg.Go(func() {
+			cfg := newNamedConfig(prefix)
+			for range 25 {
+				if err := SaveTo(cfg, path); err != nil {
+					errs <- err
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("final config is not valid: %v", err)
+	}
+	if len(loaded.Servers) != 200 {
+		t.Fatalf("expected 200 servers from one complete write, got %d", len(loaded.Servers))
+	}
+	var prefix string
+	for name := range loaded.Servers {
+		p, _, _ := strings.Cut(name, "-")
+		if prefix == "" {
+			prefix = p
+		} else if p != prefix {
+			t.Fatalf("config mixes servers from both writers (%s and %s)", prefix, p)
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected temp file to be cleaned up, stat err = %v", err)
+	}
+}
+
+func TestSaveTo_RejectsEmptyPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	if err := SaveTo(NewConfig(), ""); err == nil {
+		t.Fatal("expected an error for an empty config path")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected nothing written to the working directory, found %d entries", len(entries))
+	}
+}
+
 func TestSave_CreatesDirectory(t *testing.T) {
 	testutil.SetupTestHome(t)
 
@@ -230,6 +325,94 @@ func TestServerConfig_SetEnabled(t *testing.T) {
 	}
 }
 
+func TestServerConfig_DisabledReason(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	cfg := NewConfig()
+	srv := ServerConfig{Command: "echo"}
+	srv.Disable("  waiting on API key ")
+	cfg.Servers["paused"] = srv
+
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	srv = loaded.Servers["paused"]
+	if srv.IsEnabled() {
+		t.Fatal("expected server to stay disabled")
+	}
+	if srv.DisabledReason != "waiting on API key" {
+		t.Errorf("DisabledReason = %q, want it to persist while disabled", srv.DisabledReason)
+	}
+
+	srv.SetEnabled(true)
+	if srv.DisabledReason != "" {
+		t.Errorf("DisabledReason = %q, want it cleared on enable", srv.DisabledReason)
+	}
+}
+
+func TestServerConfig_SameConnection(t *testing.T) {
+	base := ServerConfig{
+		Command: "npx",
+		Args:    []string{"-y", "server"},
+		Env:     map[string]string{"TOKEN": "abc"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*ServerConfig)
+		same   bool
+	}{
+		{"identical", func(s *ServerConfig) {}, true},
+		{"denied tools", func(s *ServerConfig) { s.DeniedTools = []string{"delete"} }, true},
+		{"tool prefix", func(s *ServerConfig) { s.ToolPrefix = "fs" }, true},
+		{"tool timeout", func(s *ServerConfig) { s.ToolTimeoutSec = 120 }, true},
+		{"autostart", func(s *ServerConfig) { s.Autostart = true }, true},
+		{"command", func(s *ServerConfig) { s.Command = "node" }, false},
+		{"args", func(s *ServerConfig) { s.Args = []string{"-y", "other"} }, false},
+		{"env", func(s *ServerConfig) { s.Env = map[string]string{"TOKEN": "xyz"} }, false},
+		{"cwd", func(s *ServerConfig) { s.Cwd = "/tmp" }, false},
+		{"protocol version", func(s *ServerConfig) { s.ProtocolVersion = "2024-11-05" }, false},
+		{"url", func(s *ServerConfig) { s.Command = ""; s.Args = nil; s.URL = "https://example.com/mcp" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			other.Args = slices.Clone(base.Args)
+			other.Env = maps.Clone(base.Env)
+			tt.modify(&other)
+			if got := base.SameConnection(other); got != tt.same {
+				t.Errorf("SameConnection() = %v, want %v", got, tt.same)
+			}
+		})
+	}
+
+	httpBase := ServerConfig{
+		URL:         "https://example.com/mcp",
+		HTTPHeaders: map[string]string{"X-Team": "a"},
+		OAuth:       &OAuthConfig{ClientID: "id"},
+	}
+	headers := httpBase
+	headers.HTTPHeaders = map[string]string{"X-Team": "b"}
+	if httpBase.SameConnection(headers) {
+		t.Error("expected header change to require a new connection")
+	}
+	oauth := httpBase
+	oauth.OAuth = &OAuthConfig{ClientID: "id"}
+	if !httpBase.SameConnection(oauth) {
+		t.Error("expected equal OAuth config to keep the connection")
+	}
+	oauth.OAuth = &OAuthConfig{ClientID: "other"}
+	if httpBase.SameConnection(oauth) {
+		t.Error("expected OAuth client change to require a new connection")
+	}
+}
+
 func TestValidateName(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -369,6 +552,7 @@ func TestConfig_DeleteServer_CleansUpReferences(t *testing.T) {
 		{Namespace: "ns1", Server: "srv1", ToolName: "tool1", Enabled: true},
 		{Namespace: "ns1", Server: "srv2", ToolName: "tool2", Enabled: true},
 	}
+	cfg.Groups = map[string][]string{"dev": {"srv1", "srv2"}}
 
 	err := cfg.DeleteServer("srv1")
 	if err != nil {
@@ -396,6 +580,11 @@ func TestConfig_DeleteServer_CleansUpReferences(t *testing.T) {
 	if val, ok := ns.ServerDefaults["srv2"]; !ok || val != false {
 		t.Error("expected srv2 server default to be preserved")
 	}
+
+	// Check group member was removed
+	if got := cfg.Groups["dev"]; len(got) != 1 || got[0] != "srv2" {
+		t.Errorf("expected srv1 to be removed from group, got %v", got)
+	}
 }
 
 func TestConfig_RenameServer(t *testing.T) {
@@ -408,6 +597,7 @@ func TestConfig_RenameServer(t *testing.T) {
 	cfg.ToolPermissions = []ToolPermission{
 		{Namespace: "ns1", Server: "old-name", ToolName: "tool1", Enabled: true},
 	}
+	cfg.Groups = map[string][]string{"dev": {"other", "old-name"}}
 
 	err := cfg.RenameServer("old-name", "new-name")
 	if err != nil {
@@ -452,6 +642,11 @@ func TestConfig_RenameServer(t *testing.T) {
 	if val, ok := ns.ServerDefaults["new-name"]; !ok || !val {
 		t.Error("expected new-name server default to be set to true")
 	}
+
+	// Check group member was renamed
+	if got := cfg.Groups["dev"]; len(got) != 2 || got[1] != "new-name" {
+		t.Errorf("expected group member to be renamed, got %v", got)
+	}
 }
 
 func TestConfig_RenameServer_Errors(t *testing.T) {
@@ -1248,6 +1443,94 @@ func TestUpdateServer_ValidatesConfig(t *testing.T) {
 	}
 }
 
+func TestServerConfig_Validate_ToolPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		wantErr bool
+	}{
+		{"", false},
+		{"fs", false},
+		{"my-tools", false},
+		{"has.dot", true},
+		{"has:colon", true},
+		{"mcpmu", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			err := ServerConfig{Command: "echo", ToolPrefix: tt.prefix}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with toolPrefix %q error = %v, wantErr %v", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ToolPrefix(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["filesystem"] = ServerConfig{Command: "echo", ToolPrefix: "fs"}
+	cfg.Servers["plain"] = ServerConfig{Command: "echo"}
+
+	if got := cfg.ToolPrefix("filesystem"); got != "fs" {
+		t.Errorf("ToolPrefix(filesystem) = %q, want fs", got)
+	}
+	if got := cfg.ToolPrefix("plain"); got != "plain" {
+		t.Errorf("ToolPrefix(plain) = %q, want plain", got)
+	}
+
+	if name, ok := cfg.ServerForToolPrefix("fs"); !ok || name != "filesystem" {
+		t.Errorf("ServerForToolPrefix(fs) = %q, %v; want filesystem, true", name, ok)
+	}
+	if name, ok := cfg.ServerForToolPrefix("plain"); !ok || name != "plain" {
+		t.Errorf("ServerForToolPrefix(plain) = %q, %v; want plain, true", name, ok)
+	}
+	// The raw name of a server with a custom prefix is not a valid prefix
+	if _, ok := cfg.ServerForToolPrefix("filesystem"); ok {
+		t.Error("expected ServerForToolPrefix(filesystem) to be unresolved")
+	}
+}
+
+func TestConfig_ToolPrefixCollisions(t *testing.T) {
+	cfg := NewConfig()
+	if err := cfg.AddServer("filesystem", ServerConfig{Command: "echo", ToolPrefix: "fs"}); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+
+	// Prefix colliding with another server's prefix
+	if err := cfg.AddServer("other", ServerConfig{Command: "echo", ToolPrefix: "fs"}); err == nil {
+		t.Error("expected error for duplicate tool prefix")
+	}
+	if _, ok := cfg.Servers["other"]; ok {
+		t.Error("server should not be added when its prefix collides")
+	}
+
+	// Server name colliding with an existing prefix
+	if err := cfg.AddServer("fs", ServerConfig{Command: "echo"}); err == nil {
+		t.Error("expected error for server name colliding with a tool prefix")
+	}
+
+	// Prefix colliding with another server's name
+	if err := cfg.AddServer("git", ServerConfig{Command: "echo"}); err != nil {
+		t.Fatalf("AddServer: %v", err)
+	}
+	if err := cfg.UpdateServer("filesystem", ServerConfig{Command: "echo", ToolPrefix: "git"}); err == nil {
+		t.Error("expected error for tool prefix colliding with a server name")
+	}
+	if cfg.Servers["filesystem"].ToolPrefix != "fs" {
+		t.Error("failed update should leave the original server config in place")
+	}
+
+	// Rename onto an existing prefix
+	if err := cfg.RenameServer("git", "fs"); err == nil {
+		t.Error("expected error renaming a server onto an existing tool prefix")
+	}
+
+	// Collisions are also caught when validating a loaded config
+	cfg.Servers["dup"] = ServerConfig{Command: "echo", ToolPrefix: "git"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected Validate to report tool prefix collision")
+	}
+}
+
 // ============================================================================
 // Server Default Tests
 // ============================================================================
@@ -1488,6 +1771,64 @@ func TestServerConfig_Validate_OAuthValidHTTP(t *testing.T) {
 	}
 }
 
+func TestServerConfig_Validate_TLS(t *testing.T) {
+	valid := ServerConfig{
+		URL: "https://internal.example.com/mcp",
+		TLS: &TLSConfig{CAFile: "/etc/ca.pem", CertFile: "/etc/client.pem", KeyFile: "/etc/client-key.pem"},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid tls config, got: %v", err)
+	}
+
+	halfPair := ServerConfig{URL: "https://internal.example.com/mcp", TLS: &TLSConfig{CertFile: "/etc/client.pem"}}
+	if err := halfPair.Validate(); err == nil || !strings.Contains(err.Error(), "cert_file and key_file") {
+		t.Errorf("expected cert/key pairing error, got: %v", err)
+	}
+
+	stdio := ServerConfig{Command: "node", TLS: &TLSConfig{InsecureSkipVerify: true}}
+	if err := stdio.Validate(); err == nil || !strings.Contains(err.Error(), "tls is only valid for http") {
+		t.Errorf("expected tls on stdio error, got: %v", err)
+	}
+}
+
+func TestServerConfig_Validate_Proxy(t *testing.T) {
+	for _, proxy := range []string{"http://proxy.corp:3128", "https://user:pw@proxy.corp", "socks5://127.0.0.1:1080", "socks5h://proxy.corp:1080", ProxyDirect} {
+		srv := ServerConfig{URL: "https://example.com/mcp", Proxy: proxy}
+		if err := srv.Validate(); err != nil {
+			t.Errorf("proxy %q: expected valid, got: %v", proxy, err)
+		}
+	}
+
+	for _, proxy := range []string{"ftp://proxy.corp", "proxy.corp:3128", "http://"} {
+		srv := ServerConfig{URL: "https://example.com/mcp", Proxy: proxy}
+		if err := srv.Validate(); err == nil || !strings.Contains(err.Error(), "invalid proxy") {
+			t.Errorf("proxy %q: expected invalid proxy error, got: %v", proxy, err)
+		}
+	}
+
+	stdio := ServerConfig{Command: "node", Proxy: "http://proxy.corp:3128"}
+	if err := stdio.Validate(); err == nil || !strings.Contains(err.Error(), "proxy is only valid for http") {
+		t.Errorf("expected proxy on stdio error, got: %v", err)
+	}
+}
+
+func TestServerConfig_Validate_ProtocolVersion(t *testing.T) {
+	for _, srv := range []ServerConfig{
+		{Command: "node", ProtocolVersion: "2024-11-05"},
+		{URL: "https://example.com/mcp", ProtocolVersion: "2025-06-18"},
+		{Command: "node"},
+	} {
+		if err := srv.Validate(); err != nil {
+			t.Errorf("protocolVersion %q: expected valid, got: %v", srv.ProtocolVersion, err)
+		}
+	}
+
+	srv := ServerConfig{Command: "node", ProtocolVersion: "2023-01-01"}
+	if err := srv.Validate(); err == nil || !strings.Contains(err.Error(), "unsupported protocolVersion") {
+		t.Errorf("expected unsupported protocolVersion error, got: %v", err)
+	}
+}
+
 func TestServerConfig_UnmarshalJSON_MigrateFlatFields(t *testing.T) {
 	// Old config with flat scopes and oauth_client_id
 	jsonData := `{
@@ -1573,6 +1914,34 @@ func TestServerConfig_UnmarshalJSON_NestedOnly(t *testing.T) {
 	}
 }
 
+func TestServerConfig_UnmarshalJSON_BearerTokenEnvVarList(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"string", `{"url": "https://example.com/mcp", "bearer_token_env_var": "API_TOKEN"}`, "API_TOKEN"},
+		{"comma-separated", `{"url": "https://example.com/mcp", "bearer_token_env_var": "CI_TOKEN,API_TOKEN"}`, "CI_TOKEN,API_TOKEN"},
+		{"list", `{"url": "https://example.com/mcp", "bearer_token_env_var": ["CI_TOKEN", "API_TOKEN"]}`, "CI_TOKEN,API_TOKEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var srv ServerConfig
+			if err := json.Unmarshal([]byte(tt.json), &srv); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			if srv.BearerTokenEnvVar != tt.want {
+				t.Errorf("BearerTokenEnvVar = %q, want %q", srv.BearerTokenEnvVar, tt.want)
+			}
+		})
+	}
+
+	var srv ServerConfig
+	if err := json.Unmarshal([]byte(`{"url": "https://example.com/mcp", "bearer_token_env_var": 42}`), &srv); err == nil {
+		t.Error("expected error for non-string bearer_token_env_var")
+	}
+}
+
 func TestConfig_OAuthRoundTrip(t *testing.T) {
 	testutil.SetupTestHome(t)
 
@@ -1799,6 +2168,58 @@ func TestConfig_RenameServer_PreservesDeniedTools(t *testing.T) {
 	}
 }
 
+func TestConfig_RenameServer_RewritesToolOverrides(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["old"] = ServerConfig{Command: "echo"}
+	cfg.Servers["older"] = ServerConfig{Command: "echo"}
+	cfg.ToolOverrides = map[string]ToolOverride{
+		"old.search":   {Description: "Search the docs"},
+		"old.read.raw": {Annotations: map[string]any{"readOnlyHint": true}},
+		"older.search": {Description: "Untouched"},
+	}
+
+	if err := cfg.RenameServer("old", "new"); err != nil {
+		t.Fatalf("RenameServer failed: %v", err)
+	}
+
+	if len(cfg.ToolOverrides) != 3 {
+		t.Fatalf("expected 3 overrides, got %v", cfg.ToolOverrides)
+	}
+	if o, ok := cfg.GetToolOverride("new", "search"); !ok || o.Description != "Search the docs" {
+		t.Errorf("new.search = %+v, %v; want the moved override", o, ok)
+	}
+	if o, ok := cfg.GetToolOverride("new", "read.raw"); !ok || o.Annotations["readOnlyHint"] != true {
+		t.Errorf("new.read.raw = %+v, %v; want the moved override", o, ok)
+	}
+	if _, ok := cfg.GetToolOverride("old", "search"); ok {
+		t.Error("expected old.search override to be gone")
+	}
+	if o, ok := cfg.GetToolOverride("older", "search"); !ok || o.Description != "Untouched" {
+		t.Errorf("older.search = %+v, %v; want it left alone", o, ok)
+	}
+}
+
+func TestConfig_DeleteServer_CleansToolOverrides(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["srv1"] = ServerConfig{Command: "echo"}
+	cfg.Servers["srv2"] = ServerConfig{Command: "echo"}
+	cfg.ToolOverrides = map[string]ToolOverride{
+		"srv1.search": {Description: "Gone"},
+		"srv2.search": {Description: "Kept"},
+	}
+
+	if err := cfg.DeleteServer("srv1"); err != nil {
+		t.Fatalf("DeleteServer failed: %v", err)
+	}
+
+	if _, ok := cfg.GetToolOverride("srv1", "search"); ok {
+		t.Error("expected srv1.search override to be removed")
+	}
+	if _, ok := cfg.GetToolOverride("srv2", "search"); !ok {
+		t.Error("expected srv2.search override to be kept")
+	}
+}
+
 func TestConfig_DeleteServer_CleansDeniedTools(t *testing.T) {
 	cfg := NewConfig()
 	cfg.Servers["srv1"] = ServerConfig{Command: "echo", DeniedTools: []string{"delete_file"}}
@@ -1891,3 +2312,423 @@ func TestSave_ServerWithDeniedTools_OmittedWhenEmpty(t *testing.T) {
 		t.Error("expected omitempty to suppress deniedTools key in JSON")
 	}
 }
+
+func TestLoadFrom_ToolOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	data := `{
+		"schemaVersion": 1,
+		"servers": {"fs": {"command": "echo"}},
+		"toolOverrides": {
+			"fs.read_file": {"description": "Read a UTF-8 file", "annotations": {"readOnlyHint": true}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	o, ok := cfg.GetToolOverride("fs", "read_file")
+	if !ok {
+		t.Fatal("expected override for fs.read_file")
+	}
+	if o.Description != "Read a UTF-8 file" || o.Annotations["readOnlyHint"] != true {
+		t.Errorf("override = %+v", o)
+	}
+	if _, ok := cfg.GetToolOverride("fs", "write_file"); ok {
+		t.Error("unexpected override for fs.write_file")
+	}
+
+	bad := `{"schemaVersion": 1, "servers": {}, "toolOverrides": {"read_file": {"description": "x"}}}`
+	if err := os.WriteFile(path, []byte(bad), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err == nil || !strings.Contains(err.Error(), "server.tool") {
+		t.Errorf("LoadFrom with unqualified override key: err = %v, want server.tool error", err)
+	}
+}
+
+func TestConfig_Merge_NamespacesAndPermissions(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["github"] = ServerConfig{Command: "gh-mcp"}
+	cfg.Servers["local"] = ServerConfig{Command: "local-mcp"}
+	cfg.Namespaces["personal"] = NamespaceConfig{ServerIDs: []string{"local"}}
+	if err := cfg.SetToolPermission("personal", "local", "rm", false); err != nil {
+		t.Fatalf("SetToolPermission: %v", err)
+	}
+
+	template := NewConfig()
+	template.Servers["github"] = ServerConfig{Command: "gh-mcp"}
+	template.Servers["jira"] = ServerConfig{Command: "jira-mcp"}
+	template.Namespaces["work"] = NamespaceConfig{
+		Description:   "Team namespace",
+		ServerIDs:     []string{"github", "jira"},
+		DenyByDefault: true,
+	}
+	template.ToolPermissions = []ToolPermission{
+		{Namespace: "work", Server: "github", ToolName: "search_code", Enabled: true},
+		{Namespace: "work", Server: "jira", ToolName: "get_issue", Enabled: true},
+	}
+
+	result, err := cfg.Merge(template, MergeOptions{})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	wantAdded := []string{
+		`server "jira"`,
+		`namespace "work"`,
+		"permission work/github.search_code",
+		"permission work/jira.get_issue",
+	}
+	if !slices.Equal(result.Added, wantAdded) {
+		t.Errorf("Added = %v, want %v", result.Added, wantAdded)
+	}
+	if len(result.Updated) != 0 || len(result.Skipped) != 0 {
+		t.Errorf("expected no updates or skips, got %+v", result)
+	}
+
+	if ns := cfg.Namespaces["work"]; !ns.DenyByDefault || !slices.Equal(ns.ServerIDs, []string{"github", "jira"}) {
+		t.Errorf("work namespace = %+v", ns)
+	}
+	if enabled, ok := cfg.GetToolPermission("work", "jira", "get_issue"); !ok || !enabled {
+		t.Error("expected work/jira.get_issue to be allowed")
+	}
+
+	// Unrelated existing entries are untouched
+	if ns := cfg.Namespaces["personal"]; !slices.Equal(ns.ServerIDs, []string{"local"}) {
+		t.Errorf("personal namespace changed: %+v", ns)
+	}
+	if enabled, ok := cfg.GetToolPermission("personal", "local", "rm"); !ok || enabled {
+		t.Error("expected personal/local.rm to stay denied")
+	}
+	if _, ok := cfg.Servers["local"]; !ok {
+		t.Error("expected server local to be kept")
+	}
+}
+
+func TestConfig_Merge_Conflicts(t *testing.T) {
+	newCfg := func() *Config {
+		cfg := NewConfig()
+		cfg.Servers["github"] = ServerConfig{Command: "gh-mcp"}
+		cfg.Namespaces["work"] = NamespaceConfig{Description: "mine", ServerIDs: []string{"github"}}
+		_ = cfg.SetToolPermission("work", "github", "delete_repo", false)
+		return cfg
+	}
+	template := NewConfig()
+	template.Servers["github"] = ServerConfig{Command: "gh-mcp"}
+	template.Namespaces["work"] = NamespaceConfig{Description: "theirs", ServerIDs: []string{"github"}}
+	template.ToolPermissions = []ToolPermission{
+		{Namespace: "work", Server: "github", ToolName: "delete_repo", Enabled: true},
+	}
+
+	// fail: nothing changes
+	cfg := newCfg()
+	if _, err := cfg.Merge(template, MergeOptions{Policy: MergeFail}); err == nil || !strings.Contains(err.Error(), `namespace "work"`) {
+		t.Fatalf("expected conflict error naming the namespace, got %v", err)
+	}
+	if cfg.Namespaces["work"].Description != "mine" {
+		t.Error("expected failed merge to leave config unchanged")
+	}
+
+	// skip: existing namespace and its permissions are kept
+	cfg = newCfg()
+	result, err := cfg.Merge(template, MergeOptions{Policy: MergeSkip})
+	if err != nil {
+		t.Fatalf("Merge skip: %v", err)
+	}
+	if len(result.Skipped) != 2 {
+		t.Errorf("Skipped = %v, want namespace and permission", result.Skipped)
+	}
+	if cfg.Namespaces["work"].Description != "mine" {
+		t.Error("expected skip to keep the existing namespace")
+	}
+	if enabled, _ := cfg.GetToolPermission("work", "github", "delete_repo"); enabled {
+		t.Error("expected skip to keep the existing permission")
+	}
+
+	// overwrite: imported entries win
+	cfg = newCfg()
+	if _, err := cfg.Merge(template, MergeOptions{Policy: MergeOverwrite}); err != nil {
+		t.Fatalf("Merge overwrite: %v", err)
+	}
+	if cfg.Namespaces["work"].Description != "theirs" {
+		t.Error("expected overwrite to replace the namespace")
+	}
+	if enabled, _ := cfg.GetToolPermission("work", "github", "delete_repo"); !enabled {
+		t.Error("expected overwrite to replace the permission")
+	}
+}
+
+func TestConfig_Merge_UnknownReferences(t *testing.T) {
+	cfg := NewConfig()
+	template := NewConfig()
+	template.Servers["jira"] = ServerConfig{Command: "jira-mcp"}
+	template.Namespaces["work"] = NamespaceConfig{ServerIDs: []string{"jira"}}
+
+	// Without its servers, the namespace references a server that doesn't exist
+	_, err := cfg.Merge(template, MergeOptions{SkipServers: true})
+	if err == nil || !strings.Contains(err.Error(), `unknown server "jira"`) {
+		t.Fatalf("expected unknown server error, got %v", err)
+	}
+	if len(cfg.Namespaces) != 0 || len(cfg.Servers) != 0 {
+		t.Error("expected failed merge to leave config unchanged")
+	}
+
+	template.ToolPermissions = []ToolPermission{
+		{Namespace: "other", Server: "jira", ToolName: "get_issue", Enabled: true},
+	}
+	_, err = cfg.Merge(template, MergeOptions{})
+	if err == nil || !strings.Contains(err.Error(), `unknown namespace "other"`) {
+		t.Fatalf("expected unknown namespace error, got %v", err)
+	}
+}
+
+func TestState_RoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	st, err := LoadState(configPath)
+	if err != nil {
+		t.Fatalf("LoadState (missing file): %v", err)
+	}
+	if st.LastUsedNamespace != "" {
+		t.Errorf("expected empty state, got %+v", st)
+	}
+
+	if err := SaveState(configPath, State{LastUsedNamespace: "work"}); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "state.json")); err != nil {
+		t.Errorf("expected state.json next to the config: %v", err)
+	}
+
+	st, err = LoadState(configPath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if st.LastUsedNamespace != "work" {
+		t.Errorf("LastUsedNamespace = %q, want work", st.LastUsedNamespace)
+	}
+}
+
+func TestHomeOverride_RelocatesFiles(t *testing.T) {
+	envDir := t.TempDir()
+	t.Setenv(HomeEnv, envDir)
+	t.Setenv(ConfigPathEnv, "")
+
+	check := func(dir string) {
+		t.Helper()
+		if got, err := Dir(); err != nil || got != dir {
+			t.Errorf("Dir() = %q, %v; want %q", got, err, dir)
+		}
+		if got, err := ConfigPath(); err != nil || got != filepath.Join(dir, "config.json") {
+			t.Errorf("ConfigPath() = %q, %v; want it under %q", got, err, dir)
+		}
+		// Sidecar files follow the override even for a config elsewhere
+		for _, configPath := range []string{"", "/elsewhere/config.json"} {
+			if got, err := ToolCachePath(configPath); err != nil || got != filepath.Join(dir, "toolcache.json") {
+				t.Errorf("ToolCachePath(%q) = %q, %v; want it under %q", configPath, got, err, dir)
+			}
+			if got, err := StatePath(configPath); err != nil || got != filepath.Join(dir, "state.json") {
+				t.Errorf("StatePath(%q) = %q, %v; want it under %q", configPath, got, err, dir)
+			}
+		}
+	}
+	check(envDir)
+
+	// SetHome (--config-dir) takes precedence over $MCPMU_HOME
+	flagDir := t.TempDir()
+	SetHome(flagDir)
+	t.Cleanup(func() { SetHome("") })
+	check(flagDir)
+
+	// An explicit config file path still wins for the config itself
+	t.Setenv(ConfigPathEnv, "/explicit/config.json")
+	if got, _ := ConfigPath(); got != "/explicit/config.json" {
+		t.Errorf("ConfigPath() with %s = %q", ConfigPathEnv, got)
+	}
+}
+
+func TestConfig_Validate_CredentialStore(t *testing.T) {
+	for _, store := range []string{"", "auto", "keyring", "file", "pass", "env"} {
+		cfg := NewConfig()
+		cfg.MCPOAuthCredentialStore = store
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("store %q: unexpected error: %v", store, err)
+		}
+	}
+
+	cfg := NewConfig()
+	cfg.MCPOAuthCredentialStore = "vault"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mcp_oauth_credentials_store") {
+		t.Errorf("expected mcp_oauth_credentials_store error, got %v", err)
+	}
+
+	cfg = NewConfig()
+	cfg.MCPOAuthCredentialEncryption = "rot13"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "mcp_oauth_credentials_encryption") {
+		t.Errorf("expected mcp_oauth_credentials_encryption error, got %v", err)
+	}
+}
+
+func TestConfig_Diff(t *testing.T) {
+	a := NewConfig()
+	a.Servers["github"] = ServerConfig{Command: "gh-mcp", Args: []string{"--stdio"}}
+	a.Servers["jira"] = ServerConfig{Command: "jira-mcp"}
+	a.Servers["same"] = ServerConfig{Command: "same-mcp", Env: map[string]string{"A": "1", "B": "2"}}
+	a.Namespaces["work"] = NamespaceConfig{ServerIDs: []string{"github", "jira"}}
+	a.Namespaces["old"] = NamespaceConfig{ServerIDs: []string{"jira"}}
+	a.ToolPermissions = []ToolPermission{
+		{Namespace: "work", Server: "github", ToolName: "create_issue", Enabled: true},
+		{Namespace: "work", Server: "jira", ToolName: "get_issue", Enabled: true},
+	}
+
+	b := NewConfig()
+	b.DefaultNamespace = "work"
+	b.Servers["github"] = ServerConfig{Command: "gh-mcp", Args: []string{"--stdio", "--verbose"}, Autostart: true}
+	b.Servers["same"] = ServerConfig{Command: "same-mcp", Env: map[string]string{"B": "2", "A": "1"}}
+	b.Servers["slack"] = ServerConfig{URL: "https://slack.example.com/mcp"}
+	b.Namespaces["work"] = NamespaceConfig{ServerIDs: []string{"github"}, DenyByDefault: true}
+	b.Namespaces["new"] = NamespaceConfig{ServerIDs: []string{"slack"}}
+	b.ToolPermissions = []ToolPermission{
+		{Namespace: "work", Server: "github", ToolName: "create_issue", Enabled: false},
+		{Namespace: "work", Server: "github", ToolName: "search_code", Enabled: true},
+	}
+	b.LastModified = a.LastModified.AddDate(0, 0, 1)
+
+	d := a.Diff(b)
+
+	if !slices.Equal(d.Servers.Added, []string{"slack"}) || !slices.Equal(d.Servers.Removed, []string{"jira"}) {
+		t.Errorf("Servers added/removed = %v/%v", d.Servers.Added, d.Servers.Removed)
+	}
+	if len(d.Servers.Changed) != 1 || d.Servers.Changed[0].Name != "github" ||
+		!slices.Equal(d.Servers.Changed[0].Fields, []string{"args", "autostart"}) {
+		t.Errorf("Servers.Changed = %+v, want github [args autostart]", d.Servers.Changed)
+	}
+
+	if !slices.Equal(d.Namespaces.Added, []string{"new"}) || !slices.Equal(d.Namespaces.Removed, []string{"old"}) {
+		t.Errorf("Namespaces added/removed = %v/%v", d.Namespaces.Added, d.Namespaces.Removed)
+	}
+	if len(d.Namespaces.Changed) != 1 || d.Namespaces.Changed[0].Name != "work" ||
+		!slices.Equal(d.Namespaces.Changed[0].Fields, []string{"denyByDefault", "serverIds"}) {
+		t.Errorf("Namespaces.Changed = %+v, want work [denyByDefault serverIds]", d.Namespaces.Changed)
+	}
+
+	if len(d.Permissions.Added) != 1 || d.Permissions.Added[0].ToolName != "search_code" {
+		t.Errorf("Permissions.Added = %+v", d.Permissions.Added)
+	}
+	if len(d.Permissions.Removed) != 1 || d.Permissions.Removed[0].ToolName != "get_issue" {
+		t.Errorf("Permissions.Removed = %+v", d.Permissions.Removed)
+	}
+	wantChange := PermissionChange{Namespace: "work", Server: "github", ToolName: "create_issue", From: true, To: false}
+	if len(d.Permissions.Changed) != 1 || d.Permissions.Changed[0] != wantChange {
+		t.Errorf("Permissions.Changed = %+v, want %+v", d.Permissions.Changed, wantChange)
+	}
+
+	// lastModified is ignored
+	if !slices.Equal(d.Settings, []string{"defaultNamespace"}) {
+		t.Errorf("Settings = %v, want [defaultNamespace]", d.Settings)
+	}
+	if d.Empty() {
+		t.Error("expected non-empty diff")
+	}
+}
+
+func TestConfig_Diff_IgnoresKeyOrder(t *testing.T) {
+	dir := t.TempDir()
+	pathA := filepath.Join(dir, "a.json")
+	pathB := filepath.Join(dir, "b.json")
+	dataA := `{"schemaVersion": 1, "servers": {"a": {"command": "x", "env": {"K": "1", "L": "2"}}, "b": {"command": "y"}}}`
+	dataB := `{"servers": {"b": {"command": "y"}, "a": {"env": {"L": "2", "K": "1"}, "command": "x"}}, "schemaVersion": 1}`
+	if err := os.WriteFile(pathA, []byte(dataA), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pathB, []byte(dataB), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := LoadFrom(pathA)
+	if err != nil {
+		t.Fatalf("LoadFrom a: %v", err)
+	}
+	b, err := LoadFrom(pathB)
+	if err != nil {
+		t.Fatalf("LoadFrom b: %v", err)
+	}
+	if d := a.Diff(b); !d.Empty() {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+}
+
+func TestConfig_ImportNamespacePolicy_Merges(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Servers["github"] = ServerConfig{Command: "gh-mcp"}
+	cfg.Servers["local"] = ServerConfig{Command: "local-mcp"}
+	cfg.Namespaces["work"] = NamespaceConfig{ServerIDs: []string{"github", "local"}}
+	_ = cfg.SetToolPermission("work", "local", "rm", false)
+	_ = cfg.SetToolPermission("work", "github", "search", false)
+
+	policy := &NamespacePolicy{
+		DenyByDefault: true,
+		Permissions: []PolicyPermission{
+			{Server: "gh", ToolName: "search", Enabled: true},
+		},
+	}
+
+	// An unknown server leaves the config untouched
+	if err := cfg.ImportNamespacePolicy("work", policy, nil, false); err == nil {
+		t.Fatal("expected an error for the unknown server gh")
+	}
+	if cfg.Namespaces["work"].DenyByDefault {
+		t.Error("expected a failed import to change nothing")
+	}
+
+	if err := cfg.ImportNamespacePolicy("work", policy, map[string]string{"gh": "github"}, false); err != nil {
+		t.Fatalf("ImportNamespacePolicy: %v", err)
+	}
+	if !cfg.Namespaces["work"].DenyByDefault {
+		t.Error("expected the policy's deny-by-default to be applied")
+	}
+	if enabled, ok := cfg.GetToolPermission("work", "github", "search"); !ok || !enabled {
+		t.Error("expected the imported permission to win over the existing one")
+	}
+	if enabled, ok := cfg.GetToolPermission("work", "local", "rm"); !ok || enabled {
+		t.Error("expected the existing local.rm permission to be kept without replace")
+	}
+}
+
+func TestConfig_Validate_MaxLogLines(t *testing.T) {
+	cfg := NewConfig()
+	cfg.MaxLogLines = 5000
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with maxLogLines 5000 = %v, want nil", err)
+	}
+
+	cfg.MaxLogLines = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "maxLogLines") {
+		t.Errorf("Validate() with negative maxLogLines = %v, want a maxLogLines error", err)
+	}
+}
+
+func TestConfig_Validate_InitRetries(t *testing.T) {
+	cfg := NewConfig()
+	cfg.InitRetries = 5
+	cfg.InitRetryBackoffMs = 1000
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with initRetries 5 and initRetryBackoffMs 1000 = %v, want nil", err)
+	}
+
+	cfg.InitRetries = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "initRetries") {
+		t.Errorf("Validate() with negative initRetries = %v, want an initRetries error", err)
+	}
+
+	cfg.InitRetries = 0
+	cfg.InitRetryBackoffMs = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "initRetryBackoffMs") {
+		t.Errorf("Validate() with negative initRetryBackoffMs = %v, want an initRetryBackoffMs error", err)
+	}
+}
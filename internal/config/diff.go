@@ -0,0 +1,185 @@
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// ConfigDiff is a structural comparison of two configs. It compares parsed
+// values, so key order and formatting in the files don't matter.
+type ConfigDiff struct {
+	Servers     EntryDiff      `json:"servers"`
+	Namespaces  EntryDiff      `json:"namespaces"`
+	Permissions PermissionDiff `json:"permissions"`
+	// Settings lists the top-level fields (defaultNamespace,
+	// readOnlyDenyVerbs, ...) whose values differ.
+	Settings []string `json:"settings"`
+}
+
+// EntryDiff lists the named entries (servers or namespaces) added, removed
+// and changed between two configs, sorted by name.
+type EntryDiff struct {
+	Added   []string       `json:"added"`
+	Removed []string       `json:"removed"`
+	Changed []ChangedEntry `json:"changed"`
+}
+
+// ChangedEntry is an entry present in both configs with different values.
+// Fields holds the JSON names of the fields that differ; values are left
+// out since they may be secrets.
+type ChangedEntry struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// PermissionDiff lists tool permissions added, removed and flipped.
+type PermissionDiff struct {
+	Added   []ToolPermission   `json:"added"`
+	Removed []ToolPermission   `json:"removed"`
+	Changed []PermissionChange `json:"changed"`
+}
+
+// PermissionChange is a tool permission whose enabled state differs.
+type PermissionChange struct {
+	Namespace string `json:"namespace"`
+	Server    string `json:"server"`
+	ToolName  string `json:"toolName"`
+	From      bool   `json:"from"`
+	To        bool   `json:"to"`
+}
+
+// Empty reports whether the two configs are structurally identical.
+func (d ConfigDiff) Empty() bool {
+	return d.Servers.empty() && d.Namespaces.empty() && len(d.Settings) == 0 &&
+		len(d.Permissions.Added) == 0 && len(d.Permissions.Removed) == 0 && len(d.Permissions.Changed) == 0
+}
+
+func (d EntryDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares c (before) with other (after). LastModified is ignored.
+func (c *Config) Diff(other *Config) ConfigDiff {
+	return ConfigDiff{
+		Servers:     diffEntries(c.Servers, other.Servers),
+		Namespaces:  diffEntries(c.Namespaces, other.Namespaces),
+		Permissions: diffPermissions(c.ToolPermissions, other.ToolPermissions),
+		Settings:    diffSettings(c, other),
+	}
+}
+
+// diffEntries compares two maps of named entries field by field.
+func diffEntries[T any](before, after map[string]T) EntryDiff {
+	d := EntryDiff{Added: []string{}, Removed: []string{}, Changed: []ChangedEntry{}}
+	for name, a := range before {
+		b, ok := after[name]
+		if !ok {
+			d.Removed = append(d.Removed, name)
+			continue
+		}
+		if fields := changedFields(a, b); len(fields) > 0 {
+			d.Changed = append(d.Changed, ChangedEntry{Name: name, Fields: fields})
+		}
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			d.Added = append(d.Added, name)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	slices.SortFunc(d.Changed, func(x, y ChangedEntry) int { return cmp.Compare(x.Name, y.Name) })
+	return d
+}
+
+// changedFields returns the sorted JSON names of the fields that differ
+// between a and b. Comparing the JSON form treats an omitted field and its
+// zero value alike, as the config file does.
+func changedFields(a, b any) []string {
+	fa, fb := jsonFields(a), jsonFields(b)
+	var fields []string
+	for name, va := range fa {
+		if vb, ok := fb[name]; !ok || !reflect.DeepEqual(va, vb) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range fb {
+		if _, ok := fa[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// jsonFields decodes v's JSON encoding into a field map.
+func jsonFields(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// diffPermissions compares tool permissions keyed by namespace, server and tool.
+func diffPermissions(before, after []ToolPermission) PermissionDiff {
+	type key struct{ ns, server, tool string }
+	index := func(perms []ToolPermission) map[key]ToolPermission {
+		m := make(map[key]ToolPermission, len(perms))
+		for _, p := range perms {
+			m[key{p.Namespace, p.Server, p.ToolName}] = p
+		}
+		return m
+	}
+	a, b := index(before), index(after)
+
+	d := PermissionDiff{Added: []ToolPermission{}, Removed: []ToolPermission{}, Changed: []PermissionChange{}}
+	for k, pa := range a {
+		pb, ok := b[k]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, pa)
+		case pa.Enabled != pb.Enabled:
+			d.Changed = append(d.Changed, PermissionChange{
+				Namespace: k.ns, Server: k.server, ToolName: k.tool,
+				From: pa.Enabled, To: pb.Enabled,
+			})
+		}
+	}
+	for k, pb := range b {
+		if _, ok := a[k]; !ok {
+			d.Added = append(d.Added, pb)
+		}
+	}
+
+	byPerm := func(x, y ToolPermission) int {
+		return cmp.Or(cmp.Compare(x.Namespace, y.Namespace), cmp.Compare(x.Server, y.Server), cmp.Compare(x.ToolName, y.ToolName))
+	}
+	slices.SortFunc(d.Added, byPerm)
+	slices.SortFunc(d.Removed, byPerm)
+	slices.SortFunc(d.Changed, func(x, y PermissionChange) int {
+		return cmp.Or(cmp.Compare(x.Namespace, y.Namespace), cmp.Compare(x.Server, y.Server), cmp.Compare(x.ToolName, y.ToolName))
+	})
+	return d
+}
+
+// diffSettings compares the top-level fields other than the servers,
+// namespaces and permissions, which are diffed entry by entry.
+func diffSettings(a, b *Config) []string {
+	settings := []string{}
+	for _, name := range changedFields(a, b) {
+		switch name {
+		case "servers", "namespaces", "toolPermissions", "lastModified":
+			continue
+		}
+		settings = append(settings, name)
+	}
+	return settings
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/mcp"
+)
+
+// SchemaURL is where the published JSON Schema for the config file lives.
+// Saved configs reference it via "$schema" once `mcpmu schema --link` is run.
+const SchemaURL = "https://raw.githubusercontent.com/Bigsy/mcpmu/main/docs/config.schema.json"
+
+// schemaOverrides replaces the reflected schema of individual properties,
+// keyed by "GoType.jsonName", for values the Go types can't express.
+var schemaOverrides = map[string]map[string]any{
+	"ServerConfig.kind": {
+		"type": "string",
+		"enum": []string{string(ServerKindStdio), string(ServerKindStreamableHTTP)},
+	},
+	"ServerConfig.bearer_token_env_var": {
+		"description": "Env var(s) holding the bearer token, tried in order",
+		"oneOf": []any{
+			map[string]any{"type": "string"},
+			map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	},
+	"ServerConfig.protocolVersion": {
+		"type": "string",
+		"enum": mcp.SupportedProtocolVersions,
+	},
+	"Config.mcp_oauth_credentials_store": {
+		"type": "string",
+		"enum": []string{"auto", "keyring", "file", "pass", "env"},
+	},
+	"Config.mcp_oauth_credentials_encryption": {
+		"type": "string",
+		"enum": []string{"none", "keyring", "passphrase"},
+	},
+}
+
+// schemaLegacyProperties are accepted on load for older configs but never
+// written back; see ServerConfig.UnmarshalJSON.
+var schemaLegacyProperties = map[string]map[string]any{
+	"ServerConfig": {
+		"scopes":          map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "deprecated": true},
+		"oauth_client_id": map[string]any{"type": "string", "deprecated": true},
+	},
+}
+
+// schemaRequired lists the properties a type can't do without.
+var schemaRequired = map[string][]string{
+	"ToolPermission": {"namespace", "server", "toolName", "enabled"},
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing the config
+// file. It is generated from the Config struct, so new fields show up
+// without further changes.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: make(map[string]any)}
+	root := g.structSchema(reflect.TypeFor[Config]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaURL
+	root["title"] = "mcpmu config"
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+// typeSchema returns the schema for a Go type, registering named structs
+// under $defs and referencing them.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]any {
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == reflect.TypeFor[json.RawMessage]() {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // placeholder for recursive types
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		// interface{} and anything else: no constraint
+		return map[string]any{}
+	}
+}
+
+// structSchema describes a struct's JSON fields as a closed object, so
+// misspelled keys are flagged.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+		if override, ok := schemaOverrides[t.Name()+"."+name]; ok {
+			props[name] = override
+			continue
+		}
+		props[name] = g.typeSchema(f.Type)
+	}
+	for name, schema := range schemaLegacyProperties[t.Name()] {
+		props[name] = schema
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if required := schemaRequired[t.Name()]; len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// jsonFieldName returns the key a struct field is encoded under, or "" if
+// encoding/json skips it.
+func jsonFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
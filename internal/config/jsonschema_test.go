@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func loadTestSchema(t *testing.T) map[string]any {
+	t.Helper()
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	return schema
+}
+
+func TestJSONSchema_MatchesPublished(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	published, err := os.ReadFile(filepath.Join("..", "..", "docs", "config.schema.json"))
+	if err != nil {
+		t.Fatalf("read published schema: %v", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(published), data) {
+		t.Error("docs/config.schema.json is stale; regenerate with: go run ./cmd/mcpmu schema > docs/config.schema.json")
+	}
+}
+
+func TestJSONSchema_CoversAllFields(t *testing.T) {
+	schema := loadTestSchema(t)
+	defs := schema["$defs"].(map[string]any)
+
+	check := func(name string, typ reflect.Type, obj map[string]any) {
+		props := obj["properties"].(map[string]any)
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			key := jsonFieldName(f)
+			if !f.IsExported() || key == "" {
+				continue
+			}
+			if _, ok := props[key]; !ok {
+				t.Errorf("%s.%s (%q) missing from schema", name, f.Name, key)
+			}
+		}
+	}
+
+	check("Config", reflect.TypeFor[Config](), schema)
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[ServerConfig](),
+		reflect.TypeFor[NamespaceConfig](),
+		reflect.TypeFor[ToolPermission](),
+		reflect.TypeFor[OAuthConfig](),
+		reflect.TypeFor[TLSConfig](),
+		reflect.TypeFor[ToolOverride](),
+	} {
+		def, ok := defs[typ.Name()].(map[string]any)
+		if !ok {
+			t.Errorf("%s missing from $defs", typ.Name())
+			continue
+		}
+		check(typ.Name(), typ, def)
+	}
+}
+
+func TestJSONSchema_ValidatesConfigs(t *testing.T) {
+	schema := loadTestSchema(t)
+
+	good := `{
+		"$schema": "` + SchemaURL + `",
+		"schemaVersion": 1,
+		"defaultNamespace": "work",
+		"autostartConcurrency": 2,
+		"maxLogLines": 5000,
+		"initRetries": 5,
+		"initRetryBackoffMs": 1000,
+		"servers": {
+			"fs": {"command": "npx", "args": ["-y", "server-fs"], "autostart": true, "startPriority": 5, "env": {"DEBUG": "1"}},
+			"remote": {"kind": "streamable_http", "url": "https://example.com/mcp", "bearer_token_env_var": ["A", "B"], "tls": {"ca_file": "ca.pem"}},
+			"old": {"url": "https://old.example.com/mcp", "scopes": ["legacy"], "enabled": false, "disabledReason": "retired"}
+		},
+		"namespaces": {"work": {"serverIds": ["fs"], "denyByDefault": true}},
+		"toolPermissions": [{"namespace": "work", "server": "fs", "toolName": "read", "enabled": true}],
+		"toolOverrides": {"fs.read": {"annotations": {"readOnlyHint": true}}},
+		"groups": {"dev": ["fs", "remote"]},
+		"mcp_oauth_credentials_store": "file",
+		"lastModified": "2026-01-02T03:04:05Z"
+	}`
+	if errs := validateAgainst(t, schema, good); len(errs) > 0 {
+		t.Errorf("known-good config rejected: %v", errs)
+	}
+
+	// The schema must agree with the loader about what is valid
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(good), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFrom(path); err != nil {
+		t.Errorf("known-good config failed to load: %v", err)
+	}
+
+	bad := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"misspelled key", `{"schemaVersion": 1, "servers": {"fs": {"comand": "npx"}}}`, "comand"},
+		{"wrong type", `{"schemaVersion": 1, "servers": {"fs": {"command": "npx", "autostart": "yes"}}}`, "autostart"},
+		{"bad enum", `{"schemaVersion": 1, "servers": {}, "mcp_oauth_credentials_store": "vault"}`, "mcp_oauth_credentials_store"},
+		{"missing required", `{"schemaVersion": 1, "servers": {}, "toolPermissions": [{"namespace": "work"}]}`, "toolName"},
+	}
+	for _, tt := range bad {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateAgainst(t, schema, tt.config)
+			if len(errs) == 0 {
+				t.Fatal("expected known-bad config to be rejected")
+			}
+			if !strings.Contains(strings.Join(errs, "\n"), tt.want) {
+				t.Errorf("expected an error mentioning %q, got %v", tt.want, errs)
+			}
+		})
+	}
+}
+
+func TestSaveTo_KeepsSchemaRef(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	cfg := NewConfig()
+	cfg.Schema = SchemaURL
+	if err := SaveTo(cfg, path); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	loaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if loaded.Schema != SchemaURL {
+		t.Errorf("$schema = %q, want %q", loaded.Schema, SchemaURL)
+	}
+
+	cfg = NewConfig()
+	if err := SaveTo(cfg, path); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte(`"$schema"`)) {
+		t.Errorf("expected no $schema without a reference, got %s", data)
+	}
+}
+
+// validateAgainst checks a JSON document against the subset of JSON Schema
+// that JSONSchema emits, returning one message per violation.
+func validateAgainst(t *testing.T, root map[string]any, doc string) []string {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(doc), &value); err != nil {
+		t.Fatalf("parse document: %v", err)
+	}
+	var errs []string
+	validateValue(root, root, value, "$", &errs)
+	return errs
+}
+
+func validateValue(root, schema map[string]any, value any, path string, errs *[]string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")]
+		validateValue(root, def.(map[string]any), value, path, errs)
+		return
+	}
+	if variants, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, v := range variants {
+			var sub []string
+			validateValue(root, v.(map[string]any), value, path, &sub)
+			if len(sub) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			*errs = append(*errs, fmt.Sprintf("%s: matches %d of oneOf", path, matched))
+		}
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: %v not in %v", path, value, enum))
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			*errs = append(*errs, path+": expected object")
+			return
+		}
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, req := range required {
+			if _, ok := obj[req.(string)]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: missing %s", path, req))
+			}
+		}
+		for key, v := range obj {
+			if prop, ok := props[key]; ok {
+				validateValue(root, prop.(map[string]any), v, path+"."+key, errs)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					*errs = append(*errs, fmt.Sprintf("%s: unknown property %s", path, key))
+				}
+			case map[string]any:
+				validateValue(root, extra, v, path+"."+key, errs)
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			*errs = append(*errs, path+": expected array")
+			return
+		}
+		for i, v := range arr {
+			validateValue(root, schema["items"].(map[string]any), v, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			*errs = append(*errs, path+": expected string")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, path+": expected boolean")
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			*errs = append(*errs, path+": expected integer")
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			*errs = append(*errs, path+": expected number")
+		}
+	}
+}
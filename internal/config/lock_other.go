@@ -0,0 +1,17 @@
+//go:build !unix
+
+package config
+
+import "sync"
+
+// configWriteMu serializes config writers within this process on platforms
+// without flock.
+var configWriteMu sync.Mutex
+
+// lockConfigFile serializes writers of the config at path. Without flock,
+// only writers in this process are serialized; SaveTo's atomic rename still
+// keeps readers from seeing a partial file.
+func lockConfigFile(path string) (func(), error) {
+	configWriteMu.Lock()
+	return configWriteMu.Unlock, nil
+}
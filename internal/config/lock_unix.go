@@ -0,0 +1,30 @@
+//go:build unix
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockConfigFile takes an exclusive advisory lock on a ".lock" file next to
+// the config at path, blocking until any other writer (in this or another
+// process) releases it. The returned function releases the lock.
+//
+// Only writers take the lock. Readers never block: SaveTo replaces the config
+// with an atomic rename, so a reader sees either the old or the new file.
+func lockConfigFile(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open config lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("lock config: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
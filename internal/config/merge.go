@@ -0,0 +1,226 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MergePolicy decides what Merge does when an imported server, namespace or
+// tool permission differs from an existing entry with the same name.
+type MergePolicy int
+
+const (
+	// MergeFail rejects the whole merge if anything conflicts.
+	MergeFail MergePolicy = iota
+	// MergeSkip keeps the existing entry.
+	MergeSkip
+	// MergeOverwrite replaces the existing entry with the imported one.
+	MergeOverwrite
+)
+
+// ParseMergePolicy parses a --on-conflict value.
+func ParseMergePolicy(s string) (MergePolicy, error) {
+	switch s {
+	case "fail":
+		return MergeFail, nil
+	case "skip":
+		return MergeSkip, nil
+	case "overwrite":
+		return MergeOverwrite, nil
+	default:
+		return 0, fmt.Errorf("invalid conflict policy %q (must be fail, skip or overwrite)", s)
+	}
+}
+
+// MergeOptions controls Merge.
+type MergeOptions struct {
+	Policy      MergePolicy
+	SkipServers bool // Import only namespaces and tool permissions
+}
+
+// MergeResult lists what Merge changed, as human-readable entries such as
+// `namespace "work"` or `permission work/github.create_issue`.
+type MergeResult struct {
+	Added   []string
+	Updated []string
+	Skipped []string
+}
+
+// Merge imports servers, namespaces and tool permissions from src. Entries
+// identical to existing ones are left alone; entries that differ are handled
+// by opts.Policy. The default namespace and top-level settings are not
+// imported. Every imported namespace and permission must reference a server
+// and namespace that exists after the merge, otherwise nothing is changed.
+func (c *Config) Merge(src *Config, opts MergeOptions) (MergeResult, error) {
+	var result MergeResult
+	var conflicts []string
+
+	// Servers
+	var servers []string
+	if !opts.SkipServers {
+		for _, entry := range src.ServerEntries() {
+			existing, exists := c.Servers[entry.Name]
+			if exists && reflect.DeepEqual(existing, entry.Config) {
+				continue
+			}
+			if exists {
+				conflicts = append(conflicts, fmt.Sprintf("server %q", entry.Name))
+			}
+			servers = append(servers, entry.Name)
+		}
+	}
+
+	// Namespaces
+	var namespaces []string
+	for _, entry := range src.NamespaceEntries() {
+		existing, exists := c.Namespaces[entry.Name]
+		if exists && reflect.DeepEqual(existing, entry.Config) {
+			continue
+		}
+		if exists {
+			conflicts = append(conflicts, fmt.Sprintf("namespace %q", entry.Name))
+		}
+		namespaces = append(namespaces, entry.Name)
+	}
+
+	// Tool permissions
+	var perms []ToolPermission
+	for _, tp := range src.ToolPermissions {
+		enabled, exists := c.GetToolPermission(tp.Namespace, tp.Server, tp.ToolName)
+		if exists && enabled == tp.Enabled {
+			continue
+		}
+		if exists {
+			conflicts = append(conflicts, "permission "+permissionLabel(tp))
+		}
+		perms = append(perms, tp)
+	}
+
+	if len(conflicts) > 0 && opts.Policy == MergeFail {
+		return result, fmt.Errorf("conflicting entries: %s (use --on-conflict skip or overwrite)", strings.Join(conflicts, ", "))
+	}
+
+	// Work out which imported entries survive the conflict policy
+	skip := func(exists bool, label string) bool {
+		if exists && opts.Policy == MergeSkip {
+			result.Skipped = append(result.Skipped, label)
+			return true
+		}
+		return false
+	}
+
+	newServers := make(map[string]ServerConfig)
+	for _, name := range servers {
+		_, exists := c.Servers[name]
+		if !skip(exists, fmt.Sprintf("server %q", name)) {
+			newServers[name] = src.Servers[name]
+		}
+	}
+	newNamespaces := make(map[string]NamespaceConfig)
+	skippedNamespaces := make(map[string]bool)
+	for _, name := range namespaces {
+		_, exists := c.Namespaces[name]
+		if skip(exists, fmt.Sprintf("namespace %q", name)) {
+			skippedNamespaces[name] = true
+			continue
+		}
+		newNamespaces[name] = src.Namespaces[name]
+	}
+	var newPerms []ToolPermission
+	for _, tp := range perms {
+		// A skipped namespace keeps its existing permissions too
+		if skippedNamespaces[tp.Namespace] {
+			result.Skipped = append(result.Skipped, "permission "+permissionLabel(tp))
+			continue
+		}
+		_, exists := c.GetToolPermission(tp.Namespace, tp.Server, tp.ToolName)
+		if !skip(exists, "permission "+permissionLabel(tp)) {
+			newPerms = append(newPerms, tp)
+		}
+	}
+
+	// Check references against the merged result before changing anything
+	hasServer := func(name string) bool {
+		_, ok := c.Servers[name]
+		if !ok {
+			_, ok = newServers[name]
+		}
+		return ok
+	}
+	hasNamespace := func(name string) bool {
+		_, ok := c.Namespaces[name]
+		if !ok {
+			_, ok = newNamespaces[name]
+		}
+		return ok
+	}
+	for name := range newServers {
+		if err := ValidateName(name); err != nil {
+			return result, fmt.Errorf("server %q: invalid name: %w", name, err)
+		}
+	}
+	for name, ns := range newNamespaces {
+		if err := ValidateName(name); err != nil {
+			return result, fmt.Errorf("namespace %q: invalid name: %w", name, err)
+		}
+		for _, sid := range ns.ServerIDs {
+			if !hasServer(sid) {
+				return result, fmt.Errorf("namespace %q references unknown server %q", name, sid)
+			}
+		}
+		for sid := range ns.ServerDefaults {
+			if !hasServer(sid) {
+				return result, fmt.Errorf("namespace %q has a server default for unknown server %q", name, sid)
+			}
+		}
+	}
+	for _, tp := range newPerms {
+		if !hasNamespace(tp.Namespace) {
+			return result, fmt.Errorf("permission %s references unknown namespace %q", permissionLabel(tp), tp.Namespace)
+		}
+		if !hasServer(tp.Server) {
+			return result, fmt.Errorf("permission %s references unknown server %q", permissionLabel(tp), tp.Server)
+		}
+	}
+
+	// Apply
+	for _, name := range slices.Sorted(maps.Keys(newServers)) {
+		label := fmt.Sprintf("server %q", name)
+		if _, exists := c.Servers[name]; exists {
+			result.Updated = append(result.Updated, label)
+		} else {
+			result.Added = append(result.Added, label)
+		}
+		c.Servers[name] = newServers[name]
+	}
+	for _, name := range slices.Sorted(maps.Keys(newNamespaces)) {
+		label := fmt.Sprintf("namespace %q", name)
+		if _, exists := c.Namespaces[name]; exists {
+			result.Updated = append(result.Updated, label)
+		} else {
+			result.Added = append(result.Added, label)
+		}
+		c.Namespaces[name] = newNamespaces[name]
+	}
+	for _, tp := range newPerms {
+		label := "permission " + permissionLabel(tp)
+		if _, exists := c.GetToolPermission(tp.Namespace, tp.Server, tp.ToolName); exists {
+			result.Updated = append(result.Updated, label)
+		} else {
+			result.Added = append(result.Added, label)
+		}
+		if err := c.SetToolPermission(tp.Namespace, tp.Server, tp.ToolName, tp.Enabled); err != nil {
+			return result, err
+		}
+	}
+
+	return result, c.Validate()
+}
+
+// permissionLabel formats a tool permission as namespace/server.tool.
+func permissionLabel(tp ToolPermission) string {
+	return tp.Namespace + "/" + tp.Server + "." + tp.ToolName
+}
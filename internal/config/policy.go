@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// NamespacePolicy is a namespace's tool permission policy on its own, as
+// written by permission export, so it can be applied to another namespace or
+// another machine's config.
+type NamespacePolicy struct {
+	DenyByDefault  bool               `json:"denyByDefault"`
+	ServerDefaults map[string]bool    `json:"serverDefaults,omitempty"` // true = deny by default
+	Permissions    []PolicyPermission `json:"permissions"`
+}
+
+// PolicyPermission is a ToolPermission without its namespace.
+type PolicyPermission struct {
+	Server   string `json:"server"`
+	ToolName string `json:"toolName"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// ExportNamespacePolicy returns a namespace's default policy, server
+// defaults and tool permissions, ordered by server then tool.
+func (c *Config) ExportNamespacePolicy(namespaceName string) (*NamespacePolicy, error) {
+	ns, ok := c.Namespaces[namespaceName]
+	if !ok {
+		return nil, fmt.Errorf("namespace %q not found", namespaceName)
+	}
+	policy := &NamespacePolicy{
+		DenyByDefault: ns.DenyByDefault,
+		Permissions:   []PolicyPermission{},
+	}
+	if len(ns.ServerDefaults) > 0 {
+		policy.ServerDefaults = maps.Clone(ns.ServerDefaults)
+	}
+	for _, tp := range c.GetToolPermissionsForNamespace(namespaceName) {
+		policy.Permissions = append(policy.Permissions, PolicyPermission{
+			Server:   tp.Server,
+			ToolName: tp.ToolName,
+			Enabled:  tp.Enabled,
+		})
+	}
+	slices.SortFunc(policy.Permissions, func(a, b PolicyPermission) int {
+		if a.Server != b.Server {
+			return strings.Compare(a.Server, b.Server)
+		}
+		return strings.Compare(a.ToolName, b.ToolName)
+	})
+	return policy, nil
+}
+
+// ImportNamespacePolicy applies a policy to a namespace, renaming servers
+// via serverMap (old name to new name). The namespace's default policy is
+// replaced and the server defaults and permissions are merged in, imported
+// entries winning; with replace, the namespace's existing server defaults
+// and permissions are removed first. Every server the policy references,
+// after renaming, must exist, and every serverMap key must appear in the
+// policy; otherwise nothing is changed.
+func (c *Config) ImportNamespacePolicy(namespaceName string, policy *NamespacePolicy, serverMap map[string]string, replace bool) error {
+	ns, ok := c.Namespaces[namespaceName]
+	if !ok {
+		return fmt.Errorf("namespace %q not found", namespaceName)
+	}
+
+	referenced := make(map[string]bool)
+	for server := range policy.ServerDefaults {
+		referenced[server] = true
+	}
+	for _, p := range policy.Permissions {
+		if p.Server == "" || p.ToolName == "" {
+			return fmt.Errorf("policy has a permission without a server or tool name")
+		}
+		referenced[p.Server] = true
+	}
+	for old := range serverMap {
+		if !referenced[old] {
+			return fmt.Errorf("server %q is mapped but the policy doesn't reference it", old)
+		}
+	}
+	rename := func(server string) string {
+		if renamed, ok := serverMap[server]; ok {
+			return renamed
+		}
+		return server
+	}
+	var missing []string
+	for server := range referenced {
+		if _, ok := c.GetServer(rename(server)); !ok {
+			missing = append(missing, rename(server))
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("policy references unknown server(s): %s", strings.Join(missing, ", "))
+	}
+
+	if replace {
+		ns.ServerDefaults = nil
+		c.ToolPermissions = slices.DeleteFunc(c.ToolPermissions, func(tp ToolPermission) bool {
+			return tp.Namespace == namespaceName
+		})
+	}
+	ns.DenyByDefault = policy.DenyByDefault
+	for server, deny := range policy.ServerDefaults {
+		if ns.ServerDefaults == nil {
+			ns.ServerDefaults = make(map[string]bool)
+		}
+		ns.ServerDefaults[rename(server)] = deny
+	}
+	c.Namespaces[namespaceName] = ns
+
+	for _, p := range policy.Permissions {
+		if err := c.SetToolPermission(namespaceName, rename(p.Server), p.ToolName, p.Enabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,15 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Bigsy/mcpmu/internal/mcp"
 )
 
 // SchemaVersion is the current config schema version.
 const SchemaVersion = 1
 
+// ManagerToolPrefix is the reserved prefix for mcpmu's own manager tools.
+const ManagerToolPrefix = "mcpmu"
+
 // ServerKind represents the transport type for an MCP server.
 type ServerKind string
 
@@ -30,24 +39,73 @@ type OAuthConfig struct {
 	Scopes       []string `json:"scopes,omitempty"`
 }
 
+// TLSConfig holds per-server TLS settings for HTTP servers.
+type TLSConfig struct {
+	CAFile   string `json:"ca_file,omitempty"`   // PEM bundle of CAs trusted in addition to the system roots
+	CertFile string `json:"cert_file,omitempty"` // PEM client certificate (requires key_file)
+	KeyFile  string `json:"key_file,omitempty"`  // PEM client private key (requires cert_file)
+
+	// InsecureSkipVerify disables server certificate verification entirely.
+	// For local development only: anyone on the network path can intercept
+	// the connection, including bearer tokens and OAuth credentials.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// ProxyDirect as a server's proxy bypasses any proxy set in the environment.
+const ProxyDirect = "direct"
+
+// ParseProxyURL parses a server's proxy setting. The scheme must be http,
+// https, socks5 or socks5h (proxy-side DNS).
+func ParseProxyURL(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https, socks5 or socks5h", proxy)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", proxy)
+	}
+	return u, nil
+}
+
 // ServerConfig represents an MCP server configuration.
 // Field names are compatible with mcpServers format (Claude Desktop, Cursor, etc).
 // The server name/identifier is the map key, not stored in this struct.
 type ServerConfig struct {
-	Kind      ServerKind        `json:"kind,omitempty"`      // optional, inferred from command vs url
-	Enabled   *bool             `json:"enabled,omitempty"`   // nil treated as true (enabled by default)
-	Autostart bool              `json:"autostart,omitempty"` // start server automatically on app launch
-	Command   string            `json:"command,omitempty"`   // stdio only
-	Args      []string          `json:"args,omitempty"`      // stdio only
-	Cwd       string            `json:"cwd,omitempty"`
-	Env       map[string]string `json:"env,omitempty"`
+	Kind           ServerKind        `json:"kind,omitempty"`           // optional, inferred from command vs url
+	Enabled        *bool             `json:"enabled,omitempty"`        // nil treated as true (enabled by default)
+	DisabledReason string            `json:"disabledReason,omitempty"` // why the server was disabled; cleared on enable
+	Autostart      bool              `json:"autostart,omitempty"`      // start server automatically on app launch
+	StartPriority  int               `json:"startPriority,omitempty"`  // higher autostarts first; ties start in name order
+	Command        string            `json:"command,omitempty"`        // stdio only
+	Args           []string          `json:"args,omitempty"`           // stdio only; may use {{home}}, {{config_dir}}, ${ENV}
+	Cwd            string            `json:"cwd,omitempty"`            // may use {{tempdir}}, {{home}}, {{config_dir}}
+	Env            map[string]string `json:"env,omitempty"`
+
+	// CleanEnv starts a stdio server with only PATH (augmented as usual),
+	// Env and the host variables named in PassEnv, instead of inheriting
+	// mcpmu's whole environment. Use it to keep host secrets away from
+	// untrusted servers.
+	CleanEnv bool     `json:"cleanEnv,omitempty"`
+	PassEnv  []string `json:"passEnv,omitempty"`
+
+	// PromptOnStart names env keys whose values the TUI asks for when it
+	// starts. The answers are kept in memory for the session only, so
+	// secrets never touch the config file.
+	PromptOnStart []string `json:"promptOnStart,omitempty"`
 
 	// Streamable HTTP fields (mutually exclusive with Command)
 	URL               string            `json:"url,omitempty"`                  // Server URL for HTTP transport
-	BearerTokenEnvVar string            `json:"bearer_token_env_var,omitempty"` // Env var containing bearer token
+	BearerTokenEnvVar string            `json:"bearer_token_env_var,omitempty"` // Env var(s) containing bearer token, comma-separated and tried in order
 	HTTPHeaders       map[string]string `json:"http_headers,omitempty"`         // Static HTTP headers
 	EnvHTTPHeaders    map[string]string `json:"env_http_headers,omitempty"`     // HTTP headers from env vars (key=header name, value=env var name)
 	OAuth             *OAuthConfig      `json:"oauth,omitempty"`                // OAuth configuration (HTTP only)
+	TLS               *TLSConfig        `json:"tls,omitempty"`                  // Custom CA, client certificate (HTTP only)
+	Proxy             string            `json:"proxy,omitempty"`                // http://, https:// or socks5:// proxy URL, or "direct" (empty = HTTPS_PROXY/HTTP_PROXY env)
 
 	// Timeouts (seconds)
 	StartupTimeoutSec int `json:"startup_timeout_sec,omitempty"` // Default 10
@@ -55,10 +113,50 @@ type ServerConfig struct {
 
 	// Global deny list — tools listed here are denied regardless of namespace permissions
 	DeniedTools []string `json:"deniedTools,omitempty"`
+
+	// Trusted servers allow their tools by default in namespaces that deny
+	// by default. Explicit tool permissions, the namespace's per-server
+	// default and DeniedTools still take precedence.
+	Trusted bool `json:"trusted,omitempty"`
+
+	// ToolPrefix replaces the server name when qualifying tool names in serve
+	// mode (prefix.tool_name). Empty means the server name is used.
+	ToolPrefix string `json:"toolPrefix,omitempty"`
+
+	// MaxResultBytes caps the size of tools/call result content in serve
+	// mode, overriding the serve-wide limit. Zero means use the serve default.
+	MaxResultBytes int `json:"maxResultBytes,omitempty"`
+
+	// MaxLogLines is how many stderr lines are retained for this server,
+	// overriding the supervisor default (1000). Zero means the default.
+	MaxLogLines int `json:"maxLogLines,omitempty"`
+
+	// LogLevelPattern is a regular expression that finds the level word in
+	// this server's stderr lines, for coloring and filtering in the TUI log
+	// panel. The level is taken from a group named "level", or else the
+	// first capture group. Empty means the built-in pattern.
+	LogLevelPattern string `json:"logLevelPattern,omitempty"`
+
+	// InitRetries is the maximum number of MCP initialization attempts for a
+	// stdio server (default 3). InitRetryBackoffMs is the base delay between
+	// attempts, doubled after each failure (default 500). Zero means default.
+	InitRetries        int `json:"initRetries,omitempty"`
+	InitRetryBackoffMs int `json:"initRetryBackoffMs,omitempty"`
+
+	// IdleTimeoutSec stops a stdio server in serve mode after this many
+	// seconds without a request, overriding serve --idle-timeout. It starts
+	// again lazily on the next call. Zero means use the serve default.
+	IdleTimeoutSec int `json:"idleTimeoutSec,omitempty"`
+
+	// ProtocolVersion pins the MCP protocol version offered to the server,
+	// one of mcp.SupportedProtocolVersions. Empty means negotiate, falling
+	// back through older versions until one is accepted.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for backward compatibility.
-// Migrates old flat fields (scopes, oauth_client_id) into the nested oauth block.
+// Migrates old flat fields (scopes, oauth_client_id) into the nested oauth block,
+// and accepts bearer_token_env_var as a list, stored comma-separated.
 func (s *ServerConfig) UnmarshalJSON(data []byte) error {
 	// Alias to avoid recursion
 	type Alias ServerConfig
@@ -68,6 +166,8 @@ func (s *ServerConfig) UnmarshalJSON(data []byte) error {
 		*Alias
 		LegacyScopes        []string `json:"scopes,omitempty"`
 		LegacyOAuthClientID string   `json:"oauth_client_id,omitempty"`
+		// Shadows Alias.BearerTokenEnvVar so either form decodes.
+		BearerTokenEnvVar json.RawMessage `json:"bearer_token_env_var,omitempty"`
 	}{
 		Alias: (*Alias)(s),
 	}
@@ -76,6 +176,15 @@ func (s *ServerConfig) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	if len(aux.BearerTokenEnvVar) > 0 {
+		var names []string
+		if err := json.Unmarshal(aux.BearerTokenEnvVar, &names); err == nil {
+			s.BearerTokenEnvVar = strings.Join(names, ",")
+		} else if err := json.Unmarshal(aux.BearerTokenEnvVar, &s.BearerTokenEnvVar); err != nil {
+			return fmt.Errorf("bearer_token_env_var must be a string or list of strings: %w", err)
+		}
+	}
+
 	// Migrate legacy flat fields into OAuth block (nested block takes precedence)
 	if aux.LegacyOAuthClientID != "" || len(aux.LegacyScopes) > 0 {
 		if s.OAuth == nil {
@@ -106,6 +215,19 @@ type NamespaceConfig struct {
 	ServerIDs      []string        `json:"serverIds"`
 	DenyByDefault  bool            `json:"denyByDefault,omitempty"`  // If true, unconfigured tools are denied
 	ServerDefaults map[string]bool `json:"serverDefaults,omitempty"` // Per-server deny-default override (true = deny)
+
+	// StripPrefixWhenSingle exposes unqualified tool names in serve mode when
+	// the namespace contains exactly one server.
+	StripPrefixWhenSingle bool `json:"stripPrefixWhenSingle,omitempty"`
+
+	// MaxCallsPerMinute caps tools/call requests through this namespace in
+	// serve mode (token bucket, bursts up to the limit). Zero disables it.
+	MaxCallsPerMinute int `json:"maxCallsPerMinute,omitempty"`
+
+	// ShowDeniedTools keeps denied tools in serve mode's tools/list, marked
+	// "[denied]" in their description, instead of hiding them. Calls to
+	// them are still rejected.
+	ShowDeniedTools bool `json:"showDeniedTools,omitempty"`
 }
 
 // NamespaceEntry pairs a namespace name with its configuration.
@@ -125,6 +247,10 @@ type ToolPermission struct {
 
 // Config is the root configuration structure.
 type Config struct {
+	// Schema is the optional "$schema" reference editors use to validate
+	// and autocomplete the file (see SchemaURL).
+	Schema string `json:"$schema,omitempty"`
+
 	SchemaVersion    int                        `json:"schemaVersion"`
 	DefaultNamespace string                     `json:"defaultNamespace,omitempty"`
 	Servers          map[string]ServerConfig    `json:"servers"`
@@ -132,9 +258,68 @@ type Config struct {
 	ToolPermissions  []ToolPermission           `json:"toolPermissions,omitempty"`
 	LastModified     time.Time                  `json:"lastModified"`
 
+	// ReadOnlyDenyVerbs are the verbs that mark a tool as write-ish under
+	// serve --read-only. Empty means DefaultReadOnlyDenyVerbs.
+	ReadOnlyDenyVerbs []string `json:"readOnlyDenyVerbs,omitempty"`
+
+	// ToolOverrides rewrites how upstream tools are presented in serve mode,
+	// keyed by "server.tool" (server name, not tool prefix).
+	ToolOverrides map[string]ToolOverride `json:"toolOverrides,omitempty"`
+
+	// Groups are named sets of servers the TUI starts, stops and restarts
+	// together. Unlike namespaces they are purely operational and have no
+	// effect on serve mode.
+	Groups map[string][]string `json:"groups,omitempty"`
+
+	// AutostartConcurrency caps how many autostart servers start at once.
+	// Zero means no limit.
+	AutostartConcurrency int `json:"autostartConcurrency,omitempty"`
+
+	// MaxLogLines is how many stderr lines are retained per server when the
+	// server doesn't set its own maxLogLines. Zero means the supervisor
+	// default (1000).
+	MaxLogLines int `json:"maxLogLines,omitempty"`
+
+	// InitRetries and InitRetryBackoffMs are the MCP initialization attempts
+	// and base backoff for stdio servers that don't set their own. Zero means
+	// the supervisor defaults (3 attempts, 500ms).
+	InitRetries        int `json:"initRetries,omitempty"`
+	InitRetryBackoffMs int `json:"initRetryBackoffMs,omitempty"`
+
 	// OAuth settings (Codex-compatible)
-	MCPOAuthCredentialStore string `json:"mcp_oauth_credentials_store,omitempty"` // "auto", "keyring", "file"
-	MCPOAuthCallbackPort    *int   `json:"mcp_oauth_callback_port,omitempty"`     // nil = random, 0 invalid
+	MCPOAuthCredentialStore      string `json:"mcp_oauth_credentials_store,omitempty"`      // "auto", "keyring", "file", "pass", "env"
+	MCPOAuthCredentialEncryption string `json:"mcp_oauth_credentials_encryption,omitempty"` // file store at rest: "none", "keyring", "passphrase"
+	MCPOAuthCallbackPort         *int   `json:"mcp_oauth_callback_port,omitempty"`          // nil = random, 0 invalid
+}
+
+// ToolOverride replaces a tool's description and/or sets its MCP annotations
+// (readOnlyHint, destructiveHint, title, ...) in tools/list. The input schema
+// and call routing are never changed.
+type ToolOverride struct {
+	Description string         `json:"description,omitempty"`
+	Annotations map[string]any `json:"annotations,omitempty"`
+}
+
+// GetToolOverride returns the override configured for a server's tool.
+func (c *Config) GetToolOverride(serverName, toolName string) (ToolOverride, bool) {
+	o, ok := c.ToolOverrides[serverName+"."+toolName]
+	return o, ok
+}
+
+// DefaultReadOnlyDenyVerbs are the verbs denied in read-only serve mode when
+// the config does not set readOnlyDenyVerbs.
+var DefaultReadOnlyDenyVerbs = []string{
+	"create", "delete", "drop", "edit", "insert", "modify", "move", "patch",
+	"put", "remove", "rename", "set", "update", "upload", "write",
+}
+
+// ReadOnlyDenyVerbList returns the configured read-only deny verbs, or the
+// defaults when none are configured.
+func (c *Config) ReadOnlyDenyVerbList() []string {
+	if len(c.ReadOnlyDenyVerbs) > 0 {
+		return c.ReadOnlyDenyVerbs
+	}
+	return DefaultReadOnlyDenyVerbs
 }
 
 // NewConfig creates a new empty configuration with default values.
@@ -190,9 +375,42 @@ func (s ServerConfig) IsToolDenied(toolName string) bool {
 	return slices.Contains(s.DeniedTools, toolName)
 }
 
-// SetEnabled sets the enabled state.
+// SetEnabled sets the enabled state. Enabling clears any DisabledReason.
 func (s *ServerConfig) SetEnabled(enabled bool) {
 	s.Enabled = &enabled
+	if enabled {
+		s.DisabledReason = ""
+	}
+}
+
+// Disable disables the server, recording why. An empty reason clears any
+// previous one.
+func (s *ServerConfig) Disable(reason string) {
+	s.SetEnabled(false)
+	s.DisabledReason = strings.TrimSpace(reason)
+}
+
+// SameConnection reports whether other would produce the same upstream
+// connection: same transport, command line, environment, URL, headers and
+// auth. Fields read per call (deny lists, tool prefix, timeouts) are ignored,
+// so a running server can keep its connection when only those change.
+func (s ServerConfig) SameConnection(other ServerConfig) bool {
+	return s.GetKind() == other.GetKind() &&
+		s.Command == other.Command &&
+		slices.Equal(s.Args, other.Args) &&
+		s.Cwd == other.Cwd &&
+		maps.Equal(s.Env, other.Env) &&
+		s.CleanEnv == other.CleanEnv &&
+		slices.Equal(s.PassEnv, other.PassEnv) &&
+		slices.Equal(s.PromptOnStart, other.PromptOnStart) &&
+		s.URL == other.URL &&
+		s.BearerTokenEnvVar == other.BearerTokenEnvVar &&
+		maps.Equal(s.HTTPHeaders, other.HTTPHeaders) &&
+		maps.Equal(s.EnvHTTPHeaders, other.EnvHTTPHeaders) &&
+		reflect.DeepEqual(s.OAuth, other.OAuth) &&
+		reflect.DeepEqual(s.TLS, other.TLS) &&
+		s.Proxy == other.Proxy &&
+		s.ProtocolVersion == other.ProtocolVersion
 }
 
 // Validate checks that the ServerConfig is in a valid state.
@@ -212,6 +430,43 @@ func (s ServerConfig) Validate() error {
 		return errors.New("must set either command (for stdio) or url (for http)")
 	}
 
+	if s.ToolPrefix != "" {
+		if err := ValidateName(s.ToolPrefix); err != nil {
+			return fmt.Errorf("invalid toolPrefix: %w", err)
+		}
+		if s.ToolPrefix == ManagerToolPrefix {
+			return fmt.Errorf("toolPrefix %q is reserved for manager tools", s.ToolPrefix)
+		}
+	}
+
+	if s.MaxResultBytes < 0 {
+		return fmt.Errorf("maxResultBytes must not be negative, got %d", s.MaxResultBytes)
+	}
+	if s.MaxLogLines < 0 {
+		return fmt.Errorf("maxLogLines must not be negative, got %d", s.MaxLogLines)
+	}
+	if s.LogLevelPattern != "" {
+		re, err := regexp.Compile(s.LogLevelPattern)
+		if err != nil {
+			return fmt.Errorf("invalid logLevelPattern: %w", err)
+		}
+		if re.NumSubexp() == 0 {
+			return errors.New("logLevelPattern must have a capture group for the level")
+		}
+	}
+	if s.InitRetries < 0 {
+		return fmt.Errorf("initRetries must not be negative, got %d", s.InitRetries)
+	}
+	if s.InitRetryBackoffMs < 0 {
+		return fmt.Errorf("initRetryBackoffMs must not be negative, got %d", s.InitRetryBackoffMs)
+	}
+	if s.IdleTimeoutSec < 0 {
+		return fmt.Errorf("idleTimeoutSec must not be negative, got %d", s.IdleTimeoutSec)
+	}
+	if s.ProtocolVersion != "" && !slices.Contains(mcp.SupportedProtocolVersions, s.ProtocolVersion) {
+		return fmt.Errorf("unsupported protocolVersion %q (supported: %s)", s.ProtocolVersion, strings.Join(mcp.SupportedProtocolVersions, ", "))
+	}
+
 	// If Kind is explicitly set, it must match the fields
 	if s.Kind != "" {
 		if s.Kind == ServerKindStdio && hasURL {
@@ -238,6 +493,16 @@ func (s ServerConfig) Validate() error {
 		if s.OAuth != nil {
 			return errors.New("oauth is only valid for http servers")
 		}
+		if s.TLS != nil {
+			return errors.New("tls is only valid for http servers")
+		}
+		if s.Proxy != "" {
+			return errors.New("proxy is only valid for http servers")
+		}
+	}
+
+	if len(s.PassEnv) > 0 && !s.CleanEnv {
+		return errors.New("passEnv requires cleanEnv")
 	}
 
 	// HTTP-specific validation
@@ -246,6 +511,12 @@ func (s ServerConfig) Validate() error {
 		if len(s.Args) > 0 {
 			return errors.New("args is only valid for stdio servers")
 		}
+		if s.CleanEnv {
+			return errors.New("cleanEnv is only valid for stdio servers")
+		}
+		if len(s.PromptOnStart) > 0 {
+			return errors.New("promptOnStart is only valid for stdio servers")
+		}
 
 		// bearer_token_env_var and oauth are mutually exclusive
 		if s.BearerTokenEnvVar != "" && s.OAuth != nil {
@@ -259,11 +530,58 @@ func (s ServerConfig) Validate() error {
 				return fmt.Errorf("oauth callback_port must be 1-65535, got %d", port)
 			}
 		}
+
+		if s.TLS != nil && (s.TLS.CertFile == "") != (s.TLS.KeyFile == "") {
+			return errors.New("tls cert_file and key_file must be set together")
+		}
+
+		if s.Proxy != "" && s.Proxy != ProxyDirect {
+			if _, err := ParseProxyURL(s.Proxy); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// ToolPrefix returns the prefix used to qualify a server's tool names:
+// the server's configured ToolPrefix, or the server name when unset.
+func (c *Config) ToolPrefix(serverName string) string {
+	if srv, ok := c.Servers[serverName]; ok && srv.ToolPrefix != "" {
+		return srv.ToolPrefix
+	}
+	return serverName
+}
+
+// ServerForToolPrefix maps a tool prefix back to the name of the server that
+// owns it. Returns false if no server uses the prefix.
+func (c *Config) ServerForToolPrefix(prefix string) (string, bool) {
+	for name, srv := range c.Servers {
+		if srv.ToolPrefix == prefix {
+			return name, true
+		}
+	}
+	if srv, ok := c.Servers[prefix]; ok && srv.ToolPrefix == "" {
+		return prefix, true
+	}
+	return "", false
+}
+
+// validateToolPrefixes checks that no two servers expose tools under the same
+// prefix (either an explicit ToolPrefix or the server name).
+func (c *Config) validateToolPrefixes() error {
+	owners := make(map[string]string, len(c.Servers))
+	for _, entry := range c.ServerEntries() {
+		prefix := c.ToolPrefix(entry.Name)
+		if other, ok := owners[prefix]; ok {
+			return fmt.Errorf("tool prefix %q is used by both server %q and server %q", prefix, other, entry.Name)
+		}
+		owners[prefix] = entry.Name
+	}
+	return nil
+}
+
 // ServerEntries returns the servers as name/config pairs, sorted by name for display.
 func (c *Config) ServerEntries() []ServerEntry {
 	entries := make([]ServerEntry, 0, len(c.Servers))
@@ -279,6 +597,21 @@ func (c *Config) ServerEntries() []ServerEntry {
 	return entries
 }
 
+// AutostartEntries returns the enabled autostart servers in start order:
+// highest StartPriority first, then by name.
+func (c *Config) AutostartEntries() []ServerEntry {
+	var entries []ServerEntry
+	for _, entry := range c.ServerEntries() {
+		if entry.Config.Autostart && entry.Config.IsEnabled() {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Config.StartPriority > entries[j].Config.StartPriority
+	})
+	return entries
+}
+
 // NamespaceEntries returns the namespaces as name/config pairs, sorted by name for display.
 func (c *Config) NamespaceEntries() []NamespaceEntry {
 	entries := make([]NamespaceEntry, 0, len(c.Namespaces))
@@ -294,6 +627,31 @@ func (c *Config) NamespaceEntries() []NamespaceEntry {
 	return entries
 }
 
+// GroupNames returns the server group names, sorted for display.
+func (c *Config) GroupNames() []string {
+	names := make([]string, 0, len(c.Groups))
+	for name := range c.Groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// RemovedServers returns the names of servers in c that are missing from
+// newCfg, sorted.
+func (c *Config) RemovedServers(newCfg *Config) []string {
+	var removed []string
+	for name := range c.Servers {
+		if _, ok := newCfg.Servers[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
 // GetServer returns a server by name and whether it was found.
 func (c *Config) GetServer(name string) (ServerConfig, bool) {
 	s, ok := c.Servers[name]
@@ -324,5 +682,47 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("server %q: %w", name, err)
 		}
 	}
+	for name, ns := range c.Namespaces {
+		if ns.MaxCallsPerMinute < 0 {
+			return fmt.Errorf("namespace %q: maxCallsPerMinute must not be negative", name)
+		}
+	}
+	if c.AutostartConcurrency < 0 {
+		return errors.New("autostartConcurrency must not be negative")
+	}
+	if c.MaxLogLines < 0 {
+		return fmt.Errorf("maxLogLines must not be negative, got %d", c.MaxLogLines)
+	}
+	if c.InitRetries < 0 {
+		return fmt.Errorf("initRetries must not be negative, got %d", c.InitRetries)
+	}
+	if c.InitRetryBackoffMs < 0 {
+		return fmt.Errorf("initRetryBackoffMs must not be negative, got %d", c.InitRetryBackoffMs)
+	}
+	switch c.MCPOAuthCredentialStore {
+	case "", "auto", "keyring", "file", "pass", "env":
+	default:
+		return fmt.Errorf("mcp_oauth_credentials_store %q must be auto, keyring, file, pass, or env", c.MCPOAuthCredentialStore)
+	}
+	switch c.MCPOAuthCredentialEncryption {
+	case "", "none", "keyring", "passphrase":
+	default:
+		return fmt.Errorf("mcp_oauth_credentials_encryption %q must be none, keyring, or passphrase", c.MCPOAuthCredentialEncryption)
+	}
+	if err := c.validateToolPrefixes(); err != nil {
+		return err
+	}
+	return c.validateToolOverrides()
+}
+
+// validateToolOverrides checks that every toolOverrides key has the form
+// "server.tool".
+func (c *Config) validateToolOverrides() error {
+	for key := range c.ToolOverrides {
+		server, tool, ok := strings.Cut(key, ".")
+		if !ok || server == "" || tool == "" {
+			return fmt.Errorf("toolOverrides key %q must have the form server.tool", key)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State holds local preferences that are remembered between runs but are not
+// part of the config proper, so recording them never rewrites config.json
+// (and never triggers serve-mode hot reload). It is persisted as state.json
+// alongside the active config file.
+type State struct {
+	// LastUsedNamespace is the namespace last picked with serve --select.
+	// Unlike DefaultNamespace it is only used when asked for (--last-used).
+	LastUsedNamespace string `json:"lastUsedNamespace,omitempty"`
+}
+
+// StatePath returns the state file path co-located with the active config,
+// or in the --config-dir / $MCPMU_HOME directory when one is set.
+func StatePath(configPath string) (string, error) {
+	dir, err := StateDir(configPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// LoadState reads the state for the given config path. A missing file yields
+// an empty state.
+func LoadState(configPath string) (State, error) {
+	path, err := StatePath(configPath)
+	if err != nil {
+		return State{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return State{}, nil
+		}
+		return State{}, fmt.Errorf("read state: %w", err)
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return State{}, fmt.Errorf("parse state: %w", err)
+	}
+	return st, nil
+}
+
+// SaveState writes the state for the given config path atomically.
+func SaveState(configPath string, st State) error {
+	path, err := StatePath(configPath)
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+		return fmt.Errorf("write temp state: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		_ = os.Remove(tmpFile)
+		return fmt.Errorf("rename state: %w", err)
+	}
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 
@@ -41,24 +40,14 @@ type CachedTool struct {
 	TokenCount  int             `json:"tokenCount"`
 }
 
-// ToolCachePath returns the cache file path co-located with the active config.
+// ToolCachePath returns the cache file path co-located with the active config,
+// or in the --config-dir / $MCPMU_HOME directory when one is set.
 func ToolCachePath(configPath string) (string, error) {
-	if configPath != "" {
-		expanded := configPath
-		if strings.HasPrefix(expanded, "~/") {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return "", fmt.Errorf("get home dir: %w", err)
-			}
-			expanded = filepath.Join(home, expanded[2:])
-		}
-		return filepath.Join(filepath.Dir(expanded), "toolcache.json"), nil
-	}
-	home, err := os.UserHomeDir()
+	dir, err := StateDir(configPath)
 	if err != nil {
-		return "", fmt.Errorf("get home dir: %w", err)
+		return "", err
 	}
-	return filepath.Join(home, ".config", "mcpmu", "toolcache.json"), nil
+	return filepath.Join(dir, "toolcache.json"), nil
 }
 
 // NewToolCache creates or loads a tool cache for the given config path.
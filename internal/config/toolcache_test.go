@@ -196,6 +196,63 @@ func TestToolCache_Persistence(t *testing.T) {
 	}
 }
 
+func TestToolCache_InputSchemaPersisted(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+
+	tc1, err := NewToolCache(configPath)
+	if err != nil {
+		t.Fatalf("NewToolCache: %v", err)
+	}
+	_ = tc1.Update("srv", sampleTools())
+
+	tc2, err := NewToolCache(configPath)
+	if err != nil {
+		t.Fatalf("NewToolCache: %v", err)
+	}
+	tools, ok := tc2.Get("srv")
+	if !ok || len(tools) != 2 {
+		t.Fatalf("expected 2 cached tools, got %v", tools)
+	}
+	for i, want := range sampleTools() {
+		var got, expected any
+		if err := json.Unmarshal(tools[i].InputSchema, &got); err != nil {
+			t.Fatalf("%s: cached schema is not JSON: %v (%s)", want.Name, err, tools[i].InputSchema)
+		}
+		_ = json.Unmarshal(want.InputSchema, &expected)
+		gotJSON, _ := json.Marshal(got)
+		expectedJSON, _ := json.Marshal(expected)
+		if string(gotJSON) != string(expectedJSON) {
+			t.Errorf("%s: schema = %s, want %s", want.Name, gotJSON, expectedJSON)
+		}
+	}
+}
+
+func TestToolCache_LoadsEntriesWithoutSchema(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	cachePath, _ := ToolCachePath(configPath)
+
+	// Caches written before schemas were stored have no inputSchema
+	data := `{"version":1,"servers":{"srv":{"tools":[{"name":"tool","description":"Old tool","tokenCount":42}]}}}`
+	_ = os.WriteFile(cachePath, []byte(data), 0600)
+
+	tc, err := NewToolCache(configPath)
+	if err != nil {
+		t.Fatalf("NewToolCache: %v", err)
+	}
+	tools, ok := tc.Get("srv")
+	if !ok || len(tools) != 1 {
+		t.Fatalf("expected the old entry to load, got %v", tools)
+	}
+	if tools[0].Name != "tool" || tools[0].TokenCount != 42 {
+		t.Errorf("unexpected tool %+v", tools[0])
+	}
+	if len(tools[0].InputSchema) != 0 {
+		t.Errorf("expected no schema, got %s", tools[0].InputSchema)
+	}
+}
+
 func TestToolCache_FilePermissions(t *testing.T) {
 	dir := t.TempDir()
 	configPath := filepath.Join(dir, "config.json")
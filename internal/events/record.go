@@ -0,0 +1,69 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/redact"
+)
+
+// Record is the JSON form of an Event, written one per line by
+// `serve --events`. Type discriminates the event kind; the remaining fields
+// are set according to it.
+type Record struct {
+	Type      string    `json:"type"`
+	Server    string    `json:"server"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// status_changed
+	OldState string `json:"oldState,omitempty"`
+	NewState string `json:"newState,omitempty"`
+	PID      int    `json:"pid,omitempty"`
+
+	// status_changed and error
+	Error string `json:"error,omitempty"`
+
+	// log_received
+	Line string `json:"line,omitempty"`
+
+	// tools_updated
+	ToolCount *int     `json:"toolCount,omitempty"`
+	Tools     []string `json:"tools,omitempty"`
+
+	// error
+	Message string `json:"message,omitempty"`
+	Warning bool   `json:"warning,omitempty"`
+}
+
+// NewRecord converts e to its JSON record. Secrets in log lines and error
+// text are redacted.
+func NewRecord(e Event) Record {
+	rec := Record{
+		Type:      e.Type().String(),
+		Server:    e.ServerID(),
+		Timestamp: e.Timestamp(),
+	}
+	switch evt := e.(type) {
+	case StatusChangedEvent:
+		rec.OldState = evt.OldState.String()
+		rec.NewState = evt.NewState.String()
+		rec.PID = evt.Status.PID
+		rec.Error = redact.String(evt.Status.Error)
+	case LogReceivedEvent:
+		rec.Line = redact.String(evt.Line)
+	case ToolsUpdatedEvent:
+		count := len(evt.Tools)
+		rec.ToolCount = &count
+		rec.Tools = make([]string, count)
+		for i, t := range evt.Tools {
+			rec.Tools[i] = t.Name
+		}
+	case ErrorEvent:
+		rec.Message = evt.Message
+		rec.Warning = evt.Warning
+		if evt.Err != nil {
+			rec.Error = redact.String(fmt.Sprint(evt.Err))
+		}
+	}
+	return rec
+}
@@ -4,6 +4,8 @@ package events
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/Bigsy/mcpmu/internal/redact"
 )
 
 // RuntimeState represents the current state of a server process.
@@ -64,6 +66,9 @@ type ServerStatus struct {
 	ToolCount int          `json:"toolCount"`
 	Error     string       `json:"error,omitempty"`
 	StartedAt *time.Time   `json:"startedAt,omitempty"`
+	// ProtocolVersion is the MCP protocol version negotiated at
+	// initialize, set once the server is running.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
 }
 
 // McpTool represents a tool exposed by an MCP server.
@@ -166,20 +171,30 @@ func NewToolsUpdatedEvent(serverID string, tools []McpTool) ToolsUpdatedEvent {
 	}
 }
 
-// ErrorEvent is emitted when an error occurs.
+// ErrorEvent is emitted when an error occurs. Warning marks problems that
+// leave the server usable, such as a server reporting no tools.
 type ErrorEvent struct {
 	baseEvent
 	Err     error
 	Message string
+	Warning bool
 }
 
 func (e ErrorEvent) Type() EventType { return EventError }
 
-// NewErrorEvent creates a new error event.
+// NewErrorEvent creates a new error event. Secrets embedded in message
+// (URL credentials, bearer tokens) are redacted.
 func NewErrorEvent(serverID string, err error, message string) ErrorEvent {
 	return ErrorEvent{
 		baseEvent: baseEvent{serverID: serverID, timestamp: time.Now()},
 		Err:       err,
-		Message:   message,
+		Message:   redact.String(message),
 	}
 }
+
+// NewWarningEvent creates an error event flagged as a warning.
+func NewWarningEvent(serverID string, err error, message string) ErrorEvent {
+	e := NewErrorEvent(serverID, err, message)
+	e.Warning = true
+	return e
+}
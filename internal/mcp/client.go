@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -23,6 +24,21 @@ const (
 // goroutine. Dispatch to a goroutine if the work may block.
 type NotificationHandler func(method string, params json.RawMessage)
 
+// RequestHandler answers a JSON-RPC request the server sends to the client,
+// such as sampling/createMessage. It runs on its own goroutine, so it may
+// block. Return a *RequestError to choose the error code of the response.
+type RequestHandler func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error)
+
+// RequestError is a JSON-RPC error returned by a RequestHandler.
+type RequestError struct {
+	Code    int
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
 // Client implements McpClient using a Transport. Messages are demultiplexed
 // by a single reader goroutine so that responses and notifications can be
 // delivered independently.
@@ -46,12 +62,20 @@ type Client struct {
 	readerErr  atomic.Value // holds error; nil until set
 
 	notifHandler atomic.Pointer[NotificationHandler]
+	reqHandler   atomic.Pointer[RequestHandler]
+	trace        atomic.Pointer[Trace]
 
 	// Server info from initialization
 	serverName      string
 	serverVersion   string
 	protocolVersion string             // Negotiated protocol version
 	capabilities    ServerCapabilities // Typed capabilities from initialize.
+
+	// pinnedVersion, when set, is the only protocol version Initialize offers.
+	pinnedVersion string
+
+	// clientCapabilities is the capabilities object Initialize declares.
+	clientCapabilities map[string]any
 }
 
 // rpcRequest is a JSON-RPC 2.0 request.
@@ -74,12 +98,29 @@ type rpcError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+
+	// cause is the transport error behind a locally generated error, such
+	// as the one pending calls get when the connection drops.
+	cause error
 }
 
 func (e *rpcError) Error() string {
 	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
 }
 
+func (e *rpcError) Unwrap() error {
+	return e.cause
+}
+
+// rpcReply is a JSON-RPC 2.0 response to a request from the server. ID
+// echoes the server's id verbatim, whatever its type.
+type rpcReply struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
 // rawMessage is the envelope used to classify incoming JSON-RPC frames.
 // ID uses *json.RawMessage so a concrete id value can be distinguished from
 // the field being absent. Note that encoding/json decodes JSON literal null
@@ -147,6 +188,41 @@ func (c *Client) SetNotificationHandler(h NotificationHandler) {
 	c.notifHandler.Store(&h)
 }
 
+// SetRequestHandler installs a handler that answers requests from the
+// server. Pass nil to clear; requests then get a method-not-found error.
+func (c *Client) SetRequestHandler(h RequestHandler) {
+	if h == nil {
+		c.reqHandler.Store(nil)
+		return
+	}
+	c.reqHandler.Store(&h)
+}
+
+// SetTrace starts capturing every frame sent and received into t. Pass nil
+// to stop tracing.
+func (c *Client) SetTrace(t *Trace) {
+	c.trace.Store(t)
+}
+
+// Trace returns the trace installed with SetTrace, or nil.
+func (c *Client) Trace() *Trace {
+	return c.trace.Load()
+}
+
+// PinProtocolVersion makes Initialize offer only version instead of falling
+// back through SupportedProtocolVersions, for servers that misbehave during
+// negotiation. Pass "" to negotiate. Call it before Initialize.
+func (c *Client) PinProtocolVersion(version string) {
+	c.pinnedVersion = version
+}
+
+// SetClientCapabilities sets the capabilities Initialize declares to the
+// server, e.g. roots or sampling supported by whoever sits behind this
+// client. Nil declares none. Call it before Initialize.
+func (c *Client) SetClientCapabilities(caps map[string]any) {
+	c.clientCapabilities = caps
+}
+
 // readLoop is the demultiplexing reader. It runs until the transport's
 // Receive returns an error, at which point it delivers a transport-closed
 // response to every pending waiter and closes readerDone.
@@ -165,6 +241,7 @@ func (c *Client) readLoop() {
 			errResp := rpcResponse{Error: &rpcError{
 				Code:    -32000,
 				Message: "transport closed: " + err.Error(),
+				cause:   err,
 			}}
 			for _, ch := range pending {
 				select {
@@ -174,6 +251,9 @@ func (c *Client) readLoop() {
 			}
 			return
 		}
+		if t := c.trace.Load(); t != nil {
+			t.record(TraceRecv, data)
+		}
 
 		var env rawMessage
 		if err := json.Unmarshal(data, &env); err != nil {
@@ -217,10 +297,9 @@ func (c *Client) readLoop() {
 			}
 
 		case hasID && hasMethod:
-			if DebugLogging {
-				log.Printf("MCP Recv: server->client request dropped: method=%s id=%s",
-					*env.Method, string(*env.ID))
-			}
+			// Answered off the reader so a slow handler can't stall
+			// responses to our own calls.
+			go c.serveRequest(*env.ID, *env.Method, env.Params)
 
 		default:
 			if DebugLogging {
@@ -233,13 +312,24 @@ func (c *Client) readLoop() {
 // Initialize performs the MCP initialization handshake.
 // For stdio transports, it tries protocol versions in order until one is accepted.
 // For HTTP transports, version negotiation is handled by the transport layer.
+// A version set with PinProtocolVersion is the only one tried.
 func (c *Client) Initialize(ctx context.Context) error {
+	versions := SupportedProtocolVersions
+	if c.pinnedVersion != "" {
+		versions = []string{c.pinnedVersion}
+	}
+
+	caps := c.clientCapabilities
+	if caps == nil {
+		caps = map[string]any{}
+	}
+
 	// Try each supported version until one works
 	var lastErr error
-	for _, version := range SupportedProtocolVersions {
+	for _, version := range versions {
 		params := initializeParams{
 			ProtocolVersion: version,
-			Capabilities:    map[string]any{},
+			Capabilities:    caps,
 			ClientInfo: clientInfo{
 				Name:    "mcpmu-go",
 				Version: "0.1.0",
@@ -261,7 +351,15 @@ func (c *Client) Initialize(ctx context.Context) error {
 		// Success!
 		c.serverName = result.ServerInfo.Name
 		c.serverVersion = result.ServerInfo.Version
-		c.protocolVersion = version
+		// The server's answer is the negotiated version; it may differ
+		// from the one requested.
+		c.protocolVersion = result.ProtocolVersion
+		if c.protocolVersion == "" {
+			c.protocolVersion = version
+		}
+		if c.pinnedVersion != "" && c.protocolVersion != c.pinnedVersion {
+			log.Printf("MCP server answered pinned protocol version %s with %s", c.pinnedVersion, c.protocolVersion)
+		}
 		c.capabilities = result.Capabilities
 
 		// Send initialized notification
@@ -273,6 +371,9 @@ func (c *Client) Initialize(ctx context.Context) error {
 	}
 
 	if lastErr != nil {
+		if c.pinnedVersion != "" {
+			return fmt.Errorf("pinned protocol version %s rejected: %w", c.pinnedVersion, lastErr)
+		}
 		return fmt.Errorf("all protocol versions rejected: %w", lastErr)
 	}
 	return fmt.Errorf("initialize: no protocol versions to try")
@@ -368,6 +469,15 @@ func (c *Client) GetPrompt(ctx context.Context, name string, arguments map[strin
 	return result.Messages, nil
 }
 
+// SetLogLevel asks the server to send log messages at level and above
+// (logging/setLevel).
+func (c *Client) SetLogLevel(ctx context.Context, level string) error {
+	if err := c.call(ctx, "logging/setLevel", map[string]string{"level": level}, nil); err != nil {
+		return fmt.Errorf("logging/setLevel: %w", err)
+	}
+	return nil
+}
+
 // ServerInfo returns information about the connected server.
 func (c *Client) ServerInfo() (name, version string) {
 	return c.serverName, c.serverVersion
@@ -379,6 +489,9 @@ func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMe
 		Name:      name,
 		Arguments: arguments,
 	}
+	if token, ok := ctx.Value(progressTokenKey{}).(json.RawMessage); ok {
+		params.Meta = &requestMeta{ProgressToken: token}
+	}
 
 	var result toolCallResult
 	if err := c.call(ctx, "tools/call", params, &result); err != nil {
@@ -422,6 +535,20 @@ type promptGetResult struct {
 type toolCallParams struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      *requestMeta    `json:"_meta,omitempty"`
+}
+
+// requestMeta is the _meta field of a request's params.
+type requestMeta struct {
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+}
+
+type progressTokenKey struct{}
+
+// WithProgressToken returns a context under which CallTool asks the server
+// to report progress with notifications/progress carrying token.
+func WithProgressToken(ctx context.Context, token json.RawMessage) context.Context {
+	return context.WithValue(ctx, progressTokenKey{}, token)
 }
 
 // toolCallResult is the result of tools/call.
@@ -504,6 +631,9 @@ func (c *Client) call(ctx context.Context, method string, params any, result any
 		return fmt.Errorf("marshal request: %w", err)
 	}
 
+	if t := c.trace.Load(); t != nil {
+		t.record(TraceSend, data)
+	}
 	c.sendMu.Lock()
 	sendErr := c.transport.Send(ctx, data)
 	c.sendMu.Unlock()
@@ -523,6 +653,11 @@ func (c *Client) call(ctx context.Context, method string, params any, result any
 		}
 		return nil
 	case <-ctx.Done():
+		// Let the server stop work nobody is waiting for; initialize
+		// must not be cancelled.
+		if method != "initialize" {
+			go c.cancelRequest(id, ctx.Err())
+		}
 		return ctx.Err()
 	case <-c.readerDone:
 		if errVal, ok := c.readerErr.Load().(error); ok && errVal != nil {
@@ -532,6 +667,61 @@ func (c *Client) call(ctx context.Context, method string, params any, result any
 	}
 }
 
+// cancelRequest sends notifications/cancelled for a request this client
+// stopped waiting for.
+func (c *Client) cancelRequest(id int64, reason error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	params := map[string]any{"requestId": id, "reason": reason.Error()}
+	if err := c.notify(ctx, "notifications/cancelled", params); err != nil && DebugLogging {
+		log.Printf("MCP Send: cancelling request %d failed: %v", id, err)
+	}
+}
+
+// serveRequest answers a request from the server with the installed
+// RequestHandler, or with method not found if there is none.
+func (c *Client) serveRequest(id json.RawMessage, method string, params json.RawMessage) {
+	reply := rpcReply{JSONRPC: "2.0", ID: id}
+	h := c.reqHandler.Load()
+	if h == nil || *h == nil {
+		if DebugLogging {
+			log.Printf("MCP Recv: server->client request refused: method=%s id=%s", method, string(id))
+		}
+		reply.Error = &rpcError{Code: -32601, Message: "Method not found: " + method}
+	} else if result, err := (*h)(context.Background(), method, params); err != nil {
+		reply.Error = &rpcError{Code: -32603, Message: err.Error()}
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			reply.Error = &rpcError{Code: reqErr.Code, Message: reqErr.Message}
+		}
+	} else {
+		if result == nil {
+			result = json.RawMessage("{}")
+		}
+		reply.Result = result
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		log.Printf("MCP: marshal response to %s: %v", method, err)
+		return
+	}
+	if t := c.trace.Load(); t != nil {
+		t.record(TraceSend, data)
+	}
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if err := c.transport.Send(context.Background(), data); err != nil && DebugLogging {
+		log.Printf("MCP Send: response to %s failed: %v", method, err)
+	}
+}
+
+// Notify sends a notification to the server, e.g. one relayed from the
+// client this one acts for.
+func (c *Client) Notify(ctx context.Context, method string, params any) error {
+	return c.notify(ctx, method, params)
+}
+
 // notify sends a JSON-RPC notification (no response expected). Serialized
 // with call via sendMu so NDJSON frames cannot interleave on stdio.
 func (c *Client) notify(ctx context.Context, method string, params any) error {
@@ -551,6 +741,9 @@ func (c *Client) notify(ctx context.Context, method string, params any) error {
 	if err != nil {
 		return fmt.Errorf("marshal notification: %w", err)
 	}
+	if t := c.trace.Load(); t != nil {
+		t.record(TraceSend, data)
+	}
 
 	c.sendMu.Lock()
 	defer c.sendMu.Unlock()
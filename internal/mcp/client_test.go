@@ -66,6 +66,10 @@ func TestClient_HappyPath(t *testing.T) {
 	if version != "1.0.0" {
 		t.Errorf("expected server version '1.0.0', got %q", version)
 	}
+	// The fake server answers 2024-11-05 whatever version was requested
+	if got := client.ProtocolVersion(); got != "2024-11-05" {
+		t.Errorf("expected negotiated protocol version '2024-11-05', got %q", got)
+	}
 
 	// List tools
 	tools, err := client.ListTools(ctx)
@@ -592,15 +596,33 @@ func TestClient_UnknownResponseID(t *testing.T) {
 }
 
 // TestClient_ServerToClientRequest verifies that a frame with both an id and a
-// method (a server-initiated request such as sampling/roots) is dropped
-// without panic.
+// method (a server-initiated request such as sampling/roots) is answered by
+// the RequestHandler, or with method not found when none is installed, and
+// that the reader keeps serving calls afterwards.
 func TestClient_ServerToClientRequest(t *testing.T) {
 	tp := newSyntheticTransport()
 	client := NewClient(tp)
 	defer func() { _ = client.Close() }()
 
-	// Inject a server→client request. Stage 1 logs + drops.
 	tp.inject([]byte(`{"jsonrpc":"2.0","id":42,"method":"sampling/createMessage","params":{}}`))
+	if got := string(tp.nextSent(t, 2*time.Second)); !strings.Contains(got, `"id":42`) || !strings.Contains(got, "-32601") {
+		t.Errorf("expected a method-not-found response without a handler, got %s", got)
+	}
+
+	client.SetRequestHandler(func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+		if method == "roots/list" {
+			return json.RawMessage(`{"roots":[]}`), nil
+		}
+		return nil, &RequestError{Code: -32600, Message: "declined"}
+	})
+	tp.inject([]byte(`{"jsonrpc":"2.0","id":"r1","method":"roots/list"}`))
+	if got := string(tp.nextSent(t, 2*time.Second)); got != `{"jsonrpc":"2.0","id":"r1","result":{"roots":[]}}` {
+		t.Errorf("unexpected response to roots/list: %s", got)
+	}
+	tp.inject([]byte(`{"jsonrpc":"2.0","id":43,"method":"sampling/createMessage","params":{}}`))
+	if got := string(tp.nextSent(t, 2*time.Second)); !strings.Contains(got, `"code":-32600`) || !strings.Contains(got, "declined") {
+		t.Errorf("expected the handler's RequestError in the response, got %s", got)
+	}
 
 	// Verify the reader is still alive by completing a normal call afterwards.
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -724,6 +746,69 @@ func TestClient_Capabilities(t *testing.T) {
 	<-serverDone
 }
 
+// TestClient_PinnedProtocolVersion verifies that a pinned version is the only
+// one offered: it is sent as requested, and a rejection is not followed by
+// the usual fallback to older versions.
+func TestClient_PinnedProtocolVersion(t *testing.T) {
+	const pinned = "2025-03-26"
+
+	initialize := func(t *testing.T, tp *syntheticTransport, client *Client) (<-chan error, int64) {
+		t.Helper()
+		errCh := make(chan error, 1)
+		go func() { errCh <- client.Initialize(context.Background()) }()
+
+		var req struct {
+			ID     int64  `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				ProtocolVersion string `json:"protocolVersion"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(tp.nextSent(t, time.Second), &req); err != nil {
+			t.Fatalf("unmarshal initialize: %v", err)
+		}
+		if req.Method != "initialize" || req.Params.ProtocolVersion != pinned {
+			t.Fatalf("sent %s with protocolVersion %q, want initialize with %q", req.Method, req.Params.ProtocolVersion, pinned)
+		}
+		return errCh, req.ID
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		tp := newSyntheticTransport()
+		client := NewClient(tp)
+		defer func() { _ = client.Close() }()
+		client.PinProtocolVersion(pinned)
+
+		errCh, id := initialize(t, tp, client)
+		tp.inject(fmt.Appendf(nil, `{"jsonrpc":"2.0","id":%d,"result":{"protocolVersion":%q,"serverInfo":{"name":"s","version":"1"}}}`, id, pinned))
+		if err := <-errCh; err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		if got := client.ProtocolVersion(); got != pinned {
+			t.Errorf("ProtocolVersion() = %q, want %q", got, pinned)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		tp := newSyntheticTransport()
+		client := NewClient(tp)
+		defer func() { _ = client.Close() }()
+		client.PinProtocolVersion(pinned)
+
+		errCh, id := initialize(t, tp, client)
+		tp.inject(fmt.Appendf(nil, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"Unsupported protocol version"}}`, id))
+		err := <-errCh
+		if err == nil || !strings.Contains(err.Error(), "pinned protocol version "+pinned+" rejected") {
+			t.Fatalf("expected pinned version rejection, got %v", err)
+		}
+		select {
+		case msg := <-tp.out:
+			t.Errorf("expected no fallback attempt, client sent %s", msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
 // TestClient_CloseWithoutInitialize ensures Close is safe on a client that
 // was never Initialize'd — the reader goroutine started in NewClient must
 // still tear down cleanly.
@@ -743,3 +828,56 @@ func TestClient_CloseWithoutInitialize(t *testing.T) {
 		t.Fatal("Close did not return on un-Initialized client")
 	}
 }
+
+// TestClient_Trace verifies that an installed trace captures the request and
+// response frames of a call with secrets masked, and stops when cleared.
+func TestClient_Trace(t *testing.T) {
+	tp := newSyntheticTransport()
+	client := NewClient(tp)
+	defer func() { _ = client.Close() }()
+
+	trace := NewTrace(10)
+	client.SetTrace(trace)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.CallTool(ctx, "login", json.RawMessage(`{"password":"hunter2"}`))
+		errCh <- err
+	}()
+
+	sent := tp.nextSent(t, 2*time.Second)
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(sent, &req); err != nil {
+		t.Fatalf("unmarshal sent frame: %v", err)
+	}
+	tp.inject(fmt.Appendf(nil, `{"jsonrpc":"2.0","id":%d,"result":{"content":[{"type":"text","text":"ok"}]}}`, req.ID))
+	if err := <-errCh; err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	entries := trace.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 traced frames, got %d", len(entries))
+	}
+	if entries[0].Direction != TraceSend || !strings.Contains(string(entries[0].Frame), `"method":"tools/call"`) {
+		t.Errorf("first entry = %s %s, want sent tools/call", entries[0].Direction, entries[0].Frame)
+	}
+	if strings.Contains(string(entries[0].Frame), "hunter2") {
+		t.Errorf("trace leaked secret: %s", entries[0].Frame)
+	}
+	if entries[1].Direction != TraceRecv || !strings.Contains(string(entries[1].Frame), `"text":"ok"`) {
+		t.Errorf("second entry = %s %s, want received result", entries[1].Direction, entries[1].Frame)
+	}
+
+	client.SetTrace(nil)
+	tp.inject([]byte(`{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`))
+	time.Sleep(50 * time.Millisecond)
+	if n := trace.Len(); n != 2 {
+		t.Errorf("expected trace to stop at 2 frames, got %d", n)
+	}
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+)
+
+// HTTPStatusError is returned when an HTTP server answers a request with an
+// unexpected status. 401 is reported as UnauthorizedError instead.
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string // e.g. "503 Service Unavailable"
+	Body       string // start of the response body
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("request failed: %s - %s", e.Status, e.Body)
+}
+
+// ErrorClass says whether retrying a failed request could succeed.
+type ErrorClass int
+
+const (
+	// ErrorUnknown is an error that can't be classified, e.g. a JSON-RPC
+	// internal error from the server. Callers keep their usual behaviour.
+	ErrorUnknown ErrorClass = iota
+	// ErrorTransient is a failure that may clear up on its own: a dropped
+	// or refused connection, a timeout, an HTTP 5xx, 408 or 429.
+	ErrorTransient
+	// ErrorPermanent is a failure that will recur on retry: an unknown
+	// method, invalid params, or an HTTP 4xx other than 401, 408 and 429.
+	ErrorPermanent
+	// ErrorAuth is an HTTP 401. Retrying only helps once the credentials
+	// have been refreshed or the user has logged in.
+	ErrorAuth
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorTransient:
+		return "transient"
+	case ErrorPermanent:
+		return "permanent"
+	case ErrorAuth:
+		return "auth"
+	default:
+		return "unknown"
+	}
+}
+
+// JSON-RPC error codes that mean the request itself is wrong.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// ClassifyError sorts an error returned by the client or a transport into
+// an ErrorClass.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorUnknown
+	}
+
+	var unauthErr *UnauthorizedError
+	if errors.As(err, &unauthErr) {
+		return ErrorAuth
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode >= 500,
+			statusErr.StatusCode == http.StatusRequestTimeout,
+			statusErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorTransient
+		case statusErr.StatusCode >= 400:
+			return ErrorPermanent
+		}
+		return ErrorUnknown
+	}
+
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case codeParseError, codeInvalidRequest, codeMethodNotFound, codeInvalidParams:
+			return ErrorPermanent
+		}
+		// A lost connection is delivered to waiters as an rpcError that
+		// wraps the transport's error; classify that instead.
+		if rpcErr.cause != nil {
+			return ClassifyError(rpcErr.cause)
+		}
+		return ErrorUnknown
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorPermanent
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, os.ErrDeadlineExceeded),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE):
+		return ErrorTransient
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorTransient
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorTransient
+	}
+	return ErrorUnknown
+}
+
+// IsRetryable reports whether err is transient, so the same request may
+// succeed if sent again.
+func IsRetryable(err error) bool {
+	return ClassifyError(err) == ErrorTransient
+}
+
+// IsPermanent reports whether err will recur however often the request is
+// retried.
+func IsPermanent(err error) bool {
+	return ClassifyError(err) == ErrorPermanent
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"nil", nil, ErrorUnknown},
+		{"plain error", errors.New("boom"), ErrorUnknown},
+
+		{"EOF", fmt.Errorf("send: %w", io.EOF), ErrorTransient},
+		{"unexpected EOF", io.ErrUnexpectedEOF, ErrorTransient},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, ErrorTransient},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, ErrorTransient},
+		{"broken pipe", fmt.Errorf("write: %w", syscall.EPIPE), ErrorTransient},
+		{"deadline exceeded", fmt.Errorf("call: %w", context.DeadlineExceeded), ErrorTransient},
+		{"i/o timeout", os.ErrDeadlineExceeded, ErrorTransient},
+		{"canceled", context.Canceled, ErrorPermanent},
+
+		{"500", &HTTPStatusError{StatusCode: 500, Status: "500 Internal Server Error"}, ErrorTransient},
+		{"503 wrapped", fmt.Errorf("send: %w", &HTTPStatusError{StatusCode: 503}), ErrorTransient},
+		{"429", &HTTPStatusError{StatusCode: 429}, ErrorTransient},
+		{"408", &HTTPStatusError{StatusCode: 408}, ErrorTransient},
+		{"400", &HTTPStatusError{StatusCode: 400}, ErrorPermanent},
+		{"403", &HTTPStatusError{StatusCode: 403}, ErrorPermanent},
+		{"404", &HTTPStatusError{StatusCode: 404}, ErrorPermanent},
+		{"401", fmt.Errorf("send: %w", &UnauthorizedError{}), ErrorAuth},
+
+		{"method not found", &rpcError{Code: -32601, Message: "Method not found"}, ErrorPermanent},
+		{"invalid params", &rpcError{Code: -32602, Message: "Invalid params"}, ErrorPermanent},
+		{"invalid request", &rpcError{Code: -32600, Message: "Invalid Request"}, ErrorPermanent},
+		{"parse error", &rpcError{Code: -32700, Message: "Parse error"}, ErrorPermanent},
+		{"internal error", &rpcError{Code: -32603, Message: "Internal error"}, ErrorUnknown},
+		{"server-defined error", &rpcError{Code: -32001, Message: "Busy"}, ErrorUnknown},
+		{"connection lost mid-call", &rpcError{Code: -32000, Message: "transport closed: EOF", cause: io.EOF}, ErrorTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+			if got := IsRetryable(tt.err); got != (tt.want == ErrorTransient) {
+				t.Errorf("IsRetryable(%v) = %v", tt.err, got)
+			}
+			if got := IsPermanent(tt.err); got != (tt.want == ErrorPermanent) {
+				t.Errorf("IsPermanent(%v) = %v", tt.err, got)
+			}
+		})
+	}
+}
+
+func TestClassifyError_HTTPTransport(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	transport := NewStreamableHTTPTransport(StreamableHTTPConfig{URL: srv.URL})
+	defer func() { _ = transport.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	if err := transport.Send(ctx, msg); !IsRetryable(err) {
+		t.Errorf("expected a 503 to be retryable, got %v (%s)", err, ClassifyError(err))
+	}
+
+	status = http.StatusForbidden
+	if err := transport.Send(ctx, msg); !IsPermanent(err) {
+		t.Errorf("expected a 403 to be permanent, got %v (%s)", err, ClassifyError(err))
+	}
+}
+
+func TestClassifyError_ConnectionLostDuringCall(t *testing.T) {
+	tp := newSyntheticTransport()
+	client := NewClient(tp)
+	defer func() { _ = client.Close() }()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := client.ListTools(context.Background())
+		errCh <- err
+	}()
+	tp.nextSent(t, time.Second)
+	close(tp.in) // the server goes away without answering
+
+	select {
+	case err := <-errCh:
+		if !IsRetryable(err) {
+			t.Errorf("expected a dropped connection to be retryable, got %v (%s)", err, ClassifyError(err))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("call did not fail after the connection dropped")
+	}
+}
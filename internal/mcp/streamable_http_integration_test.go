@@ -376,6 +376,46 @@ func TestStreamableHTTPTransport_VersionNegotiation_AllRejected(t *testing.T) {
 	}
 }
 
+func TestStreamableHTTPTransport_PinnedProtocolVersion(t *testing.T) {
+	const pinned = "2025-03-26"
+	var attemptedVersions []string
+	reject := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get("MCP-Protocol-Version")
+		attemptedVersions = append(attemptedVersions, version)
+		if reject {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"error":"Unsupported MCP-Protocol-Version: %s"}`, version)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	}))
+	defer server.Close()
+
+	transport := NewStreamableHTTPTransport(StreamableHTTPConfig{URL: server.URL, ProtocolVersion: pinned})
+	ctx := context.Background()
+
+	if err := transport.Send(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"test"}`)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(attemptedVersions) != 1 || attemptedVersions[0] != pinned {
+		t.Errorf("attempted versions = %v, want only %s", attemptedVersions, pinned)
+	}
+
+	// A rejection of the pinned version is final
+	reject = true
+	attemptedVersions = nil
+	err := transport.Send(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"test"}`))
+	if err == nil || !strings.Contains(err.Error(), "pinned protocol version rejected") {
+		t.Fatalf("expected pinned version rejection, got %v", err)
+	}
+	if len(attemptedVersions) != 1 || attemptedVersions[0] != pinned {
+		t.Errorf("attempted versions = %v, want only %s without fallback", attemptedVersions, pinned)
+	}
+}
+
 func TestStreamableHTTPTransport_VersionNegotiation_LenientThenStrict(t *testing.T) {
 	// Simulate a server like Sentry that is lenient on first request but strict on subsequent
 	// First request accepts any version, second request only accepts specific versions
@@ -674,6 +714,32 @@ func TestValidateBearerTokenEnvVar(t *testing.T) {
 	}
 }
 
+func TestValidateBearerTokenEnvVar_Fallback(t *testing.T) {
+	t.Setenv("MCP_STUDIO_CI_BEARER", "")
+	t.Setenv("MCP_STUDIO_DEV_BEARER", "dev-token")
+	t.Setenv("MCP_STUDIO_PROD_BEARER", "prod-token")
+
+	got, err := ValidateBearerTokenEnvVar("MCP_STUDIO_CI_BEARER, MCP_STUDIO_DEV_BEARER,MCP_STUDIO_PROD_BEARER")
+	if err != nil {
+		t.Fatalf("ValidateBearerTokenEnvVar returned error: %v", err)
+	}
+	if got != "dev-token" {
+		t.Errorf("token: got %q, want the first set variable's %q", got, "dev-token")
+	}
+
+	_, err = ValidateBearerTokenEnvVar("MCP_STUDIO_CI_BEARER,MCP_STUDIO_NOT_SET")
+	if err == nil {
+		t.Fatal("expected error when no env var is set")
+	}
+	if want := "none of the bearer token env vars MCP_STUDIO_CI_BEARER, MCP_STUDIO_NOT_SET is set"; err.Error() != want {
+		t.Errorf("error: got %q, want %q", err, want)
+	}
+
+	if _, err := ValidateBearerTokenEnvVar("MCP_STUDIO_DEV_BEARER,1INVALID"); err == nil {
+		t.Fatal("expected error for invalid env var name in list")
+	}
+}
+
 // LegacySSEMockServer simulates a legacy HTTP+SSE MCP server (like Atlassian)
 // that sends session ID via endpoint event and requires it as a query parameter.
 type LegacySSEMockServer struct {
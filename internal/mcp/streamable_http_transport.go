@@ -61,6 +61,11 @@ type StreamableHTTPConfig struct {
 
 	// Client is the HTTP client to use. If nil, http.DefaultClient is used.
 	Client *http.Client
+
+	// ProtocolVersion, when set, is the only version sent in the
+	// MCP-Protocol-Version header; a rejection is not retried with older
+	// versions.
+	ProtocolVersion string
 }
 
 // StreamableHTTPTransport implements Transport over HTTP with SSE streaming.
@@ -182,7 +187,9 @@ func (t *StreamableHTTPTransport) Send(ctx context.Context, msg []byte) error {
 	// Determine which versions to try
 	versionsToTry := SupportedProtocolVersions
 	startIdx := 0
-	if negotiatedVersion != "" {
+	if t.config.ProtocolVersion != "" {
+		versionsToTry = []string{t.config.ProtocolVersion}
+	} else if negotiatedVersion != "" {
 		// Already negotiated - start from that version but allow fallback if rejected
 		for i, v := range SupportedProtocolVersions {
 			if v == negotiatedVersion {
@@ -241,11 +248,14 @@ func (t *StreamableHTTPTransport) Send(ctx context.Context, msg []byte) error {
 				if i < len(versionsToTry)-1 {
 					continue
 				}
+				if t.config.ProtocolVersion != "" {
+					return fmt.Errorf("pinned protocol version rejected by server: %w", lastErr)
+				}
 				return fmt.Errorf("all protocol versions rejected by server: %w", lastErr)
 			}
 
 			// Not a version rejection - return the error
-			return fmt.Errorf("request failed: %s - %s", resp.Status, bodyStr)
+			return &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: bodyStr}
 		}
 
 		// Capture session ID from response
@@ -263,9 +273,10 @@ func (t *StreamableHTTPTransport) Send(ctx context.Context, msg []byte) error {
 				// Parse WWW-Authenticate headers for OAuth discovery (RFC 9728)
 				// Uses all header values to find Bearer challenge with resource_metadata
 				challenge := oauth.ParseBearerChallenge(resp.Header)
-				return &UnauthorizedError{Challenge: challenge}
+				token, _ := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+				return &UnauthorizedError{Challenge: challenge, Token: token}
 			}
-			return fmt.Errorf("request failed: %s - %s", resp.Status, string(body))
+			return &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}
 		}
 
 		// Success! Store the negotiated version
@@ -566,12 +577,25 @@ type AuthChallenge = oauth.BearerChallenge
 // errors.As() to extract challenge info for OAuth discovery.
 type UnauthorizedError struct {
 	Challenge *oauth.BearerChallenge
+	Token     string // Bearer token the rejected request carried, if any
 }
 
 func (e *UnauthorizedError) Error() string {
 	return "unauthorized - authentication required"
 }
 
+// TokenRejected reports whether the 401 means the bearer token itself was
+// refused (RFC 6750 error="invalid_token", or a Bearer challenge with no
+// error code), so a refreshed token may succeed. A 401 without a Bearer
+// challenge (a proxy's Basic auth, an expired session) or with another
+// error code is not fixed by refreshing.
+func (e *UnauthorizedError) TokenRejected() bool {
+	if e.Challenge == nil {
+		return false
+	}
+	return e.Challenge.Error == "" || e.Challenge.Error == "invalid_token"
+}
+
 // HTTPClientConfig holds configuration for creating an HTTP transport from server config.
 type HTTPClientConfig struct {
 	URL         string
@@ -579,23 +603,36 @@ type HTTPClientConfig struct {
 	Headers     map[string]string
 }
 
-// ValidateBearerTokenEnvVar checks if the bearer token environment variable is set.
-// Returns an error if the env var is configured but not present.
-func ValidateBearerTokenEnvVar(envVarName string) (string, error) {
-	if envVarName == "" {
+// ValidateBearerTokenEnvVar resolves the bearer token from envVarNames, a
+// comma-separated list of environment variables tried in order. The first
+// one that is set wins. Returns an error if a name is invalid or none of
+// them is set.
+func ValidateBearerTokenEnvVar(envVarNames string) (string, error) {
+	if strings.TrimSpace(envVarNames) == "" {
 		return "", nil
 	}
-	if !isValidEnvVarName(envVarName) {
-		return "", fmt.Errorf("invalid bearer token env var name %q", envVarName)
+	var names []string
+	for name := range strings.SplitSeq(envVarNames, ",") {
+		name = strings.TrimSpace(name)
+		if !isValidEnvVarName(name) {
+			return "", fmt.Errorf("invalid bearer token env var name %q", name)
+		}
+		names = append(names, name)
 	}
-	val, ok := os.LookupEnv(envVarName)
-	if !ok || strings.TrimSpace(val) == "" {
-		return "", fmt.Errorf("bearer token env var %s is not set", envVarName)
+	for _, name := range names {
+		val, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(val) == "" {
+			continue
+		}
+		if strings.ContainsAny(val, "\r\n") {
+			return "", fmt.Errorf("bearer token env var %s must not contain newlines", name)
+		}
+		return val, nil
 	}
-	if strings.ContainsAny(val, "\r\n") {
-		return "", fmt.Errorf("bearer token env var %s must not contain newlines", envVarName)
+	if len(names) == 1 {
+		return "", fmt.Errorf("bearer token env var %s is not set", names[0])
 	}
-	return val, nil
+	return "", fmt.Errorf("none of the bearer token env vars %s is set", strings.Join(names, ", "))
 }
 
 // MarshalJSON for AuthStatus to use string representation.
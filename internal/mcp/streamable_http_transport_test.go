@@ -232,3 +232,25 @@ func TestUnauthorizedError(t *testing.T) {
 		t.Error("UnauthorizedError should return non-empty error message")
 	}
 }
+
+func TestUnauthorizedError_TokenRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge *oauth.BearerChallenge
+		want      bool
+	}{
+		{"no challenge", nil, false},
+		{"bearer without error", &oauth.BearerChallenge{Realm: "mcp"}, true},
+		{"invalid token", &oauth.BearerChallenge{Error: "invalid_token"}, true},
+		{"insufficient scope", &oauth.BearerChallenge{Error: "insufficient_scope"}, false},
+		{"invalid request", &oauth.BearerChallenge{Error: "invalid_request"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &UnauthorizedError{Challenge: tt.challenge}
+			if got := err.TokenRejected(); got != tt.want {
+				t.Errorf("TokenRejected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
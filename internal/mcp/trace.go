@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/redact"
+)
+
+// DefaultTraceCapacity is the number of frames a Trace keeps by default.
+const DefaultTraceCapacity = 200
+
+// TraceDirection says whether a traced frame was sent to or received from
+// the server.
+type TraceDirection string
+
+const (
+	TraceSend TraceDirection = "send"
+	TraceRecv TraceDirection = "recv"
+)
+
+// TraceEntry is one JSON-RPC frame captured by a Trace. Frame has secrets
+// masked with redact.JSON.
+type TraceEntry struct {
+	Time      time.Time       `json:"time"`
+	Direction TraceDirection  `json:"direction"`
+	Frame     json.RawMessage `json:"frame"`
+}
+
+// FormatTraceEntry renders a traced frame for a terminal: a line with its
+// time, direction and server, then the frame pretty-printed.
+func FormatTraceEntry(server string, e TraceEntry) string {
+	arrow := "→"
+	if e.Direction == TraceRecv {
+		arrow = "←"
+	}
+	var frame bytes.Buffer
+	if err := json.Indent(&frame, e.Frame, "", "  "); err != nil {
+		frame.Reset()
+		frame.Write(e.Frame)
+	}
+	return fmt.Sprintf("%s %s %s\n%s\n", e.Time.Format("15:04:05.000"), arrow, server, frame.String())
+}
+
+// Trace is a ring buffer of the raw JSON-RPC frames flowing through a
+// Client. Once full, the oldest frames are dropped. It is safe for
+// concurrent use.
+type Trace struct {
+	mu      sync.Mutex
+	entries []TraceEntry
+	next    int // index of the oldest entry once the buffer is full
+	cap     int
+	tap     func(TraceEntry)
+}
+
+// NewTrace creates a trace keeping the last capacity frames.
+// A capacity <= 0 uses DefaultTraceCapacity.
+func NewTrace(capacity int) *Trace {
+	if capacity <= 0 {
+		capacity = DefaultTraceCapacity
+	}
+	return &Trace{cap: capacity}
+}
+
+// SetTap registers fn to be called with every frame as it is recorded, for
+// streaming a trace as well as buffering it. fn may be called concurrently.
+func (t *Trace) SetTap(fn func(TraceEntry)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tap = fn
+}
+
+// record masks and stores a frame.
+func (t *Trace) record(dir TraceDirection, frame []byte) {
+	entry := TraceEntry{Time: time.Now(), Direction: dir, Frame: redact.JSON(frame)}
+	if !json.Valid(entry.Frame) {
+		// Keep Export's output valid JSON even for malformed frames.
+		quoted, _ := json.Marshal(string(entry.Frame))
+		entry.Frame = quoted
+	}
+
+	t.mu.Lock()
+	if len(t.entries) < t.cap {
+		t.entries = append(t.entries, entry)
+	} else {
+		t.entries[t.next] = entry
+		t.next = (t.next + 1) % t.cap
+	}
+	tap := t.tap
+	t.mu.Unlock()
+
+	if tap != nil {
+		tap(entry)
+	}
+}
+
+// Entries returns the captured frames, oldest first.
+func (t *Trace) Entries() []TraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]TraceEntry, 0, len(t.entries))
+	out = append(out, t.entries[t.next:]...)
+	return append(out, t.entries[:t.next]...)
+}
+
+// Len returns the number of captured frames.
+func (t *Trace) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
+
+// Export writes the captured frames to w as newline-delimited JSON, one
+// TraceEntry per line.
+func (t *Trace) Export(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range t.Entries() {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTrace_RingBuffer(t *testing.T) {
+	trace := NewTrace(3)
+	for i := 1; i <= 5; i++ {
+		trace.record(TraceSend, fmt.Appendf(nil, `{"id":%d}`, i))
+	}
+
+	entries := trace.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []string{`{"id":3}`, `{"id":4}`, `{"id":5}`} {
+		if string(entries[i].Frame) != want {
+			t.Errorf("entry %d = %s, want %s", i, entries[i].Frame, want)
+		}
+	}
+}
+
+func TestTrace_Export(t *testing.T) {
+	trace := NewTrace(0)
+	trace.record(TraceSend, []byte(`{"method":"tools/call","params":{"arguments":{"token":"abc"}}}`))
+	trace.record(TraceRecv, []byte(`not json`))
+
+	var buf bytes.Buffer
+	if err := trace.Export(&buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var entry TraceEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Errorf("line is not a valid entry: %v: %s", err, line)
+		}
+	}
+	if strings.Contains(buf.String(), "abc") {
+		t.Errorf("export leaked secret: %s", buf.String())
+	}
+}
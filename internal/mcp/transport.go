@@ -70,6 +70,13 @@ type NotificationSink interface {
 	OnUpstreamNotification(serverName, method string, params json.RawMessage)
 }
 
+// RequestSink answers requests that upstream MCP servers send to the client,
+// such as `sampling/createMessage`. A NotificationSink that also implements
+// RequestSink is wired in as each client's RequestHandler.
+type RequestSink interface {
+	OnUpstreamRequest(ctx context.Context, serverName, method string, params json.RawMessage) (json.RawMessage, error)
+}
+
 // Tool represents an MCP tool definition.
 type Tool struct {
 	Name        string `json:"name"`
@@ -36,6 +36,7 @@ type Config struct {
 
 	// Retry testing: fail on specific attempt, succeed on others
 	FailOnAttempt map[string]int `json:"failOnAttempt"` // method -> attempt number to fail (1-indexed)
+	FailFirstN    map[string]int `json:"failFirstN"`    // method -> number of leading attempts to fail
 
 	// Protocol edge cases for stream realism
 	// These options test that the client handles interleaved messages correctly.
@@ -78,17 +79,61 @@ type Config struct {
 	// remove its local mapping after the unsubscribe RPC returns.
 	PostUnsubscribeEmitDelayMs int `json:"postUnsubscribeEmitDelayMs,omitempty"`
 
+	// ToolsListChangedOn names a tool whose call makes the server emit
+	// notifications/tools/list_changed before its response. Setting it also
+	// makes the server advertise tools.listChanged.
+	ToolsListChangedOn string `json:"toolsListChangedOn,omitempty"`
+
 	// RequestLogPath is a file path the server appends every handled JSON-RPC
 	// method name to, one per line. Tests use this to assert whether a
 	// particular method (e.g., resources/unsubscribe) was invoked upstream.
 	// Writes are best-effort and guarded against concurrent callers.
 	RequestLogPath string `json:"requestLogPath,omitempty"`
 
+	// ClientRequests maps a tool name to a request the server sends to the
+	// client when that tool is called, e.g. sampling/createMessage. The tool
+	// call waits for the client's response and returns it, result or error,
+	// as JSON text.
+	ClientRequests map[string]ClientRequest `json:"clientRequests,omitempty"`
+
+	// LogMessages are emitted as notifications/message params during each
+	// tools/call, before its response. Setting any also makes the server
+	// advertise the logging capability; logging/setLevel is always accepted.
+	LogMessages []json.RawMessage `json:"logMessages,omitempty"`
+
+	// ProgressSteps makes a tools/call that carries a progress token emit
+	// that many notifications/progress frames (progress 1..N of N) before
+	// its response.
+	ProgressSteps int `json:"progressSteps,omitempty"`
+
+	// InitializeParamsPath is a file path the server writes the params of
+	// each initialize request to, so tests can inspect what the client
+	// declared (e.g. its capabilities).
+	InitializeParamsPath string `json:"initializeParamsPath,omitempty"`
+
 	// EmitStartupUpdates lists URIs for which the server emits
 	// notifications/resources/updated frames shortly after initialize. Used
 	// to test stray-notification filtering in downstream code.
 	EmitStartupUpdates []string `json:"emitStartupUpdates,omitempty"`
 
+	// StartupStderr lines are written to stderr as soon as the server starts,
+	// before any request is read. Combined with a crash option this mimics a
+	// server that logs why it can't start and exits.
+	StartupStderr []string `json:"startupStderr,omitempty"`
+
+	// StderrEnv names environment variables the server writes to stderr as
+	// NAME=value at startup, so tests can inspect the child environment.
+	StderrEnv []string `json:"stderrEnv,omitempty"`
+
+	// StderrArgs makes the server write each command-line argument after
+	// "--" to stderr as ARG=value at startup, so tests can inspect how its
+	// args were expanded.
+	StderrArgs bool `json:"stderrArgs,omitempty"`
+
+	// ServerInfo overrides the name and version reported at initialize
+	// (default: fake-server 1.0.0).
+	ServerInfo *ServerInfo `json:"serverInfo,omitempty"`
+
 	// UpdateHook receives a function the test can call to emit an out-of-band
 	// notifications/resources/updated{uri} frame on this server's output. The
 	// hook is wired when Serve starts; the test should capture it via the
@@ -132,6 +177,12 @@ type rpcResponse struct {
 	Error   *JSONRPCError   `json:"error,omitempty"`
 }
 
+// ClientRequest is a request the server sends to the client.
+type ClientRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
 // rpcNotification is a JSON-RPC 2.0 notification.
 type rpcNotification struct {
 	JSONRPC string `json:"jsonrpc"`
@@ -157,6 +208,7 @@ type Capabilities struct {
 	Tools     *ToolsCapability     `json:"tools,omitempty"`
 	Resources *ResourcesCapability `json:"resources,omitempty"`
 	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
+	Logging   *struct{}            `json:"logging,omitempty"`
 }
 
 // ResourcesCapability indicates the server supports resources.
@@ -184,6 +236,9 @@ type ToolsListResult struct {
 type ToolCallParams struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      struct {
+		ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+	} `json:"_meta"`
 }
 
 // ToolCallResult is the result of tools/call.
@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sync"
 	"time"
 )
@@ -26,12 +28,55 @@ func logRequest(path, method string) {
 	_, _ = f.WriteString(method + "\n")
 }
 
+// requestClient sends req to the client with the given id and reads frames
+// until the client's response arrives, returning its result or error as
+// JSON. Other frames read meanwhile are dropped.
+func requestClient(reader *bufio.Reader, out io.Writer, id string, req ClientRequest) (string, error) {
+	rawID, _ := json.Marshal(id)
+	if err := writeFrame(out, rpcRequest{JSONRPC: "2.0", ID: rawID, Method: req.Method, Params: req.Params}); err != nil {
+		return "", err
+	}
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return "", err
+		}
+		var resp struct {
+			rpcResponse
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(bytes.TrimSpace(line), &resp) != nil || resp.Method != "" || string(resp.ID) != string(rawID) {
+			continue
+		}
+		if resp.Error != nil {
+			data, _ := json.Marshal(resp.Error)
+			return string(data), nil
+		}
+		return string(resp.Result), nil
+	}
+}
+
 // Serve runs the fake MCP server, reading requests from in and writing responses to out.
 // It handles initialize and tools/list methods, with configurable delays, errors, and crashes.
 func Serve(ctx context.Context, in io.Reader, out io.Writer, cfg Config) error {
+	for _, line := range cfg.StartupStderr {
+		fmt.Fprintln(os.Stderr, line)
+	}
+	for _, name := range cfg.StderrEnv {
+		fmt.Fprintf(os.Stderr, "%s=%s\n", name, os.Getenv(name))
+	}
+	if cfg.StderrArgs {
+		if i := slices.Index(os.Args, "--"); i >= 0 {
+			for _, arg := range os.Args[i+1:] {
+				fmt.Fprintf(os.Stderr, "ARG=%s\n", arg)
+			}
+		}
+	}
+
 	reader := bufio.NewReader(in)
 	requestCount := 0
 	methodAttempts := make(map[string]int) // track attempts per method for FailOnAttempt
+	clientRequestCount := 0
 
 	// Serializes concurrent writes between the main request loop and any
 	// out-of-band notification emitted by a SetUpdateHook caller.
@@ -112,6 +157,13 @@ func Serve(ctx context.Context, in io.Reader, out io.Writer, cfg Config) error {
 			}
 		}
 
+		if n, ok := cfg.FailFirstN[req.Method]; ok && methodAttempts[req.Method] <= n {
+			_ = writeErrorResponse(out, req.ID, JSONRPCError{
+				Code: -32603, Message: "Simulated failure on attempt",
+			}, cfg)
+			continue
+		}
+
 		// Check for forced error
 		if rpcErr, ok := cfg.Errors[req.Method]; ok {
 			_ = writeErrorResponse(out, req.ID, rpcErr, cfg)
@@ -121,19 +173,32 @@ func Serve(ctx context.Context, in io.Reader, out io.Writer, cfg Config) error {
 		// Handle methods
 		switch req.Method {
 		case "initialize":
-			caps := Capabilities{Tools: &ToolsCapability{}}
+			if cfg.InitializeParamsPath != "" {
+				_ = os.WriteFile(cfg.InitializeParamsPath, req.Params, 0o644)
+			}
+			caps := Capabilities{Tools: &ToolsCapability{ListChanged: cfg.ToolsListChangedOn != ""}}
 			if len(cfg.Resources) > 0 || cfg.ResourceContents != nil || cfg.ResourcesSubscribe {
 				caps.Resources = &ResourcesCapability{Subscribe: cfg.ResourcesSubscribe}
 			}
 			if len(cfg.Prompts) > 0 || cfg.PromptMessages != nil {
 				caps.Prompts = &PromptsCapability{}
 			}
+			if len(cfg.LogMessages) > 0 {
+				caps.Logging = &struct{}{}
+			}
+			info := ServerInfo{Name: "fake-server", Version: "1.0.0"}
+			if cfg.ServerInfo != nil {
+				info = *cfg.ServerInfo
+			}
 			_ = writeResponse(out, req.ID, InitializeResult{
 				ProtocolVersion: "2024-11-05",
-				ServerInfo:      ServerInfo{Name: "fake-server", Version: "1.0.0"},
+				ServerInfo:      info,
 				Capabilities:    caps,
 			}, cfg)
 
+		case "logging/setLevel":
+			_ = writeResponse(out, req.ID, struct{}{}, cfg)
+
 		case "tools/list":
 			tools := cfg.Tools
 			if tools == nil {
@@ -150,6 +215,39 @@ func Serve(ctx context.Context, in io.Reader, out io.Writer, cfg Config) error {
 				continue
 			}
 
+			if cfg.ToolsListChangedOn != "" && params.Name == cfg.ToolsListChangedOn {
+				_ = writeFrame(out, rpcNotification{JSONRPC: "2.0", Method: "notifications/tools/list_changed"})
+			}
+
+			for _, msg := range cfg.LogMessages {
+				_ = writeFrame(out, rpcNotification{JSONRPC: "2.0", Method: "notifications/message", Params: msg})
+			}
+			if len(params.Meta.ProgressToken) > 0 {
+				for i := 1; i <= cfg.ProgressSteps; i++ {
+					_ = writeFrame(out, rpcNotification{
+						JSONRPC: "2.0",
+						Method:  "notifications/progress",
+						Params: map[string]any{
+							"progressToken": params.Meta.ProgressToken,
+							"progress":      i,
+							"total":         cfg.ProgressSteps,
+						},
+					})
+				}
+			}
+
+			if clientReq, ok := cfg.ClientRequests[params.Name]; ok {
+				clientRequestCount++
+				text, err := requestClient(reader, out, fmt.Sprintf("fake-%d", clientRequestCount), clientReq)
+				if err != nil {
+					return err
+				}
+				_ = writeResponse(out, req.ID, ToolCallResult{
+					Content: []ContentBlock{{Type: "text", Text: text}},
+				}, cfg)
+				continue
+			}
+
 			// Check if we have a custom handler
 			if cfg.ToolHandler != nil {
 				content, isError, err := cfg.ToolHandler(params.Name, params.Arguments)
@@ -90,6 +90,17 @@ func FailOnAttemptConfig(method string, attempt int) FakeServerConfig {
 	}
 }
 
+// FailFirstNConfig returns a config that fails the first n attempts of a
+// method and succeeds afterwards. Useful for testing retry limits.
+func FailFirstNConfig(method string, n int) FakeServerConfig {
+	return FakeServerConfig{
+		Tools: []Tool{{Name: "test_tool"}},
+		FailFirstN: map[string]int{
+			method: n,
+		},
+	}
+}
+
 // NotificationBeforeResponseConfig returns a config that sends a notification before each response.
 // Tests that clients properly skip notifications when waiting for responses.
 func NotificationBeforeResponseConfig() FakeServerConfig {
@@ -3,6 +3,7 @@ package oauth
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -113,10 +114,55 @@ const (
 
 	// StoreModeFile uses a JSON file.
 	StoreModeFile StoreMode = "file"
+
+	// StoreModePass uses pass, the standard Unix password manager.
+	StoreModePass StoreMode = "pass"
+
+	// StoreModeEnv seeds an in-memory store from $MCPMU_OAUTH_CREDENTIALS and
+	// never persists anything.
+	StoreModeEnv StoreMode = "env"
 )
 
-// NewCredentialStore creates a credential store based on the mode.
+// StoreModes lists the valid credential store modes.
+var StoreModes = []StoreMode{StoreModeAuto, StoreModeKeyring, StoreModeFile, StoreModePass, StoreModeEnv}
+
+// ParseStoreMode parses a credential store mode. An empty string means auto.
+func ParseStoreMode(s string) (StoreMode, error) {
+	if s == "" {
+		return StoreModeAuto, nil
+	}
+	for _, mode := range StoreModes {
+		if StoreMode(s) == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid credential store %q (must be auto, keyring, file, pass, or env)", s)
+}
+
+// NewCredentialStore creates a credential store based on the mode, using the
+// default directory for the file store.
 func NewCredentialStore(mode StoreMode) (CredentialStore, error) {
+	return NewCredentialStoreInDir(mode, "")
+}
+
+// NewCredentialStoreInDir creates a credential store based on the mode. The
+// file store keeps its credentials in dir, or in ~/.config/mcpmu if empty.
+func NewCredentialStoreInDir(mode StoreMode, dir string) (CredentialStore, error) {
+	return NewCredentialStoreWithOptions(mode, StoreOptions{Dir: dir})
+}
+
+// StoreOptions configures the file store behind a credential store.
+type StoreOptions struct {
+	// Dir holds the credentials file; empty means ~/.config/mcpmu.
+	Dir string
+
+	// Encryption protects the credentials file at rest; empty means none.
+	Encryption Encryption
+}
+
+// NewCredentialStoreWithOptions creates a credential store based on the mode.
+func NewCredentialStoreWithOptions(mode StoreMode, opts StoreOptions) (CredentialStore, error) {
+	dir := opts.Dir
 	switch mode {
 	case StoreModeKeyring:
 		store, err := NewKeyringStore()
@@ -126,7 +172,21 @@ func NewCredentialStore(mode StoreMode) (CredentialStore, error) {
 		return store, nil
 
 	case StoreModeFile:
-		return NewFileStore()
+		return NewEncryptedFileStoreInDir(dir, opts.Encryption)
+
+	case StoreModePass:
+		store, err := NewPassStore()
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+
+	case StoreModeEnv:
+		store, err := NewEnvStore()
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
 
 	case StoreModeAuto, "":
 		// Try keyring first, fall back to file
@@ -134,9 +194,9 @@ func NewCredentialStore(mode StoreMode) (CredentialStore, error) {
 		if err == nil {
 			return store, nil
 		}
-		return NewFileStore()
+		return NewEncryptedFileStoreInDir(dir, opts.Encryption)
 
 	default:
-		return NewFileStore()
+		return NewEncryptedFileStoreInDir(dir, opts.Encryption)
 	}
 }
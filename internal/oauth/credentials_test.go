@@ -366,3 +366,30 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestParseStoreMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    StoreMode
+		wantErr bool
+	}{
+		{"", StoreModeAuto, false},
+		{"auto", StoreModeAuto, false},
+		{"keyring", StoreModeKeyring, false},
+		{"file", StoreModeFile, false},
+		{"pass", StoreModePass, false},
+		{"env", StoreModeEnv, false},
+		{"gnome-keyring", "", true},
+		{"FILE", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStoreMode(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStoreMode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseStoreMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
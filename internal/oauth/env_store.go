@@ -0,0 +1,97 @@
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// CredentialsEnv names the environment variable the env store is seeded
+// from: a JSON array of credentials in the same format as the file store.
+const CredentialsEnv = "MCPMU_OAUTH_CREDENTIALS"
+
+// EnvStore is an ephemeral credential store for containers and CI. It is
+// seeded from $MCPMU_OAUTH_CREDENTIALS and keeps everything in memory:
+// refreshed tokens and logins last only as long as the process, and nothing
+// is ever written to disk or a keychain.
+type EnvStore struct {
+	mu    sync.RWMutex
+	creds []*Credential
+}
+
+// NewEnvStore creates an env-seeded credential store. An unset variable
+// yields an empty store.
+func NewEnvStore() (*EnvStore, error) {
+	s := &EnvStore{}
+	data := os.Getenv(CredentialsEnv)
+	if data == "" {
+		return s, nil
+	}
+	if err := json.Unmarshal([]byte(data), &s.creds); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", CredentialsEnv, err)
+	}
+	for _, cred := range s.creds {
+		if err := cred.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", CredentialsEnv, err)
+		}
+	}
+	return s, nil
+}
+
+// Get retrieves credentials for a server by URL.
+func (s *EnvStore) Get(serverURL string) (*Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.creds {
+		if c.ServerURL == serverURL {
+			cred := *c
+			return &cred, nil
+		}
+	}
+	return nil, nil
+}
+
+// Put stores credentials for a server for the life of the process.
+func (s *EnvStore) Put(cred *Credential) error {
+	if err := cred.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *cred
+	for i, c := range s.creds {
+		if c.ServerURL == cred.ServerURL {
+			s.creds[i] = &stored
+			return nil
+		}
+	}
+	s.creds = append(s.creds, &stored)
+	return nil
+}
+
+// Delete removes credentials for a server.
+func (s *EnvStore) Delete(serverURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.creds = slices.DeleteFunc(s.creds, func(c *Credential) bool { return c.ServerURL == serverURL })
+	return nil
+}
+
+// List returns all stored credentials.
+func (s *EnvStore) List() ([]*Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	creds := make([]*Credential, 0, len(s.creds))
+	for _, c := range s.creds {
+		cred := *c
+		creds = append(creds, &cred)
+	}
+	return creds, nil
+}
@@ -0,0 +1,107 @@
+package oauth
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEnvStore_SeededFromEnv(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UnixMilli()
+	seed, _ := json.Marshal([]*Credential{{
+		ServerName:  "ci",
+		ServerURL:   "https://mcp.example.com/mcp",
+		ClientID:    "client-123",
+		AccessToken: "seeded-token",
+		ExpiresAt:   expiresAt,
+	}})
+	t.Setenv(CredentialsEnv, string(seed))
+
+	store, err := NewEnvStore()
+	if err != nil {
+		t.Fatalf("NewEnvStore failed: %v", err)
+	}
+
+	got, err := store.Get("https://mcp.example.com/mcp")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil || got.AccessToken != "seeded-token" || got.ExpiresAt != expiresAt {
+		t.Fatalf("Get = %+v, want seeded credential", got)
+	}
+
+	// Mutating a returned credential doesn't change the store
+	got.AccessToken = "mutated"
+	again, _ := store.Get("https://mcp.example.com/mcp")
+	if again.AccessToken != "seeded-token" {
+		t.Errorf("store was mutated through Get result: %q", again.AccessToken)
+	}
+}
+
+func TestEnvStore_RoundTrip(t *testing.T) {
+	t.Setenv(CredentialsEnv, "")
+
+	store, err := NewEnvStore()
+	if err != nil {
+		t.Fatalf("NewEnvStore failed: %v", err)
+	}
+
+	cred := &Credential{
+		ServerURL:   "https://mcp.example.com/mcp",
+		ClientID:    "client-123",
+		AccessToken: "access-token",
+		ExpiresAt:   time.Now().Add(time.Hour).UnixMilli(),
+	}
+	if err := store.Put(cred); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	cred.AccessToken = "refreshed"
+	if err := store.Put(cred); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	creds, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(creds) != 1 || creds[0].AccessToken != "refreshed" {
+		t.Fatalf("List = %+v, want one refreshed credential", creds)
+	}
+
+	if err := store.Delete(cred.ServerURL); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err := store.Get(cred.ServerURL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil after delete, got %+v", got)
+	}
+}
+
+func TestEnvStore_InvalidSeed(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`[{"server_url":"https://mcp.example.com/mcp"}]`,
+	}
+	for _, seed := range tests {
+		t.Setenv(CredentialsEnv, seed)
+		if _, err := NewEnvStore(); err == nil {
+			t.Errorf("NewEnvStore with %s: expected error", seed)
+		}
+	}
+}
+
+func TestNewCredentialStore_Env(t *testing.T) {
+	t.Setenv(CredentialsEnv, "")
+
+	store, err := NewCredentialStoreInDir(StoreModeEnv, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCredentialStoreInDir failed: %v", err)
+	}
+	if _, ok := store.(*EnvStore); !ok {
+		t.Errorf("store = %T, want *EnvStore", store)
+	}
+}
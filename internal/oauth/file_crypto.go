@@ -0,0 +1,233 @@
+package oauth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Encryption selects how the file store protects credentials at rest.
+type Encryption string
+
+const (
+	// EncryptionNone writes plaintext JSON (the default).
+	EncryptionNone Encryption = "none"
+
+	// EncryptionKeyring encrypts with a random key kept in the system keychain.
+	EncryptionKeyring Encryption = "keyring"
+
+	// EncryptionPassphrase encrypts with a key derived from
+	// $MCPMU_CREDENTIALS_PASSPHRASE.
+	EncryptionPassphrase Encryption = "passphrase"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase for
+// EncryptionPassphrase.
+const PassphraseEnv = "MCPMU_CREDENTIALS_PASSPHRASE"
+
+const (
+	// fileKeyName is the keychain entry holding the file store's key.
+	fileKeyName = "_file_store_key"
+
+	// pbkdf2Iterations follows OWASP's recommendation for PBKDF2-HMAC-SHA256.
+	pbkdf2Iterations = 600_000
+
+	envelopeVersion = 1
+)
+
+// ErrDecrypt is returned when an encrypted credentials file can't be opened
+// with the available key.
+var ErrDecrypt = errors.New("decrypt credentials: wrong key or passphrase, or file is corrupted")
+
+// ParseEncryption parses a credential encryption setting. An empty string
+// means none.
+func ParseEncryption(s string) (Encryption, error) {
+	switch e := Encryption(s); e {
+	case "":
+		return EncryptionNone, nil
+	case EncryptionNone, EncryptionKeyring, EncryptionPassphrase:
+		return e, nil
+	default:
+		return "", fmt.Errorf("invalid credential encryption %q (must be none, keyring, or passphrase)", s)
+	}
+}
+
+// envelope is the on-disk format of an encrypted credentials file. Plaintext
+// files are a bare JSON array, so the two are told apart by their first byte.
+type envelope struct {
+	Version    int        `json:"version"`
+	KeySource  Encryption `json:"key_source"`
+	Salt       []byte     `json:"salt,omitempty"`
+	Iterations int        `json:"iterations,omitempty"`
+	Nonce      []byte     `json:"nonce"`
+	Ciphertext []byte     `json:"ciphertext"`
+}
+
+// isEnvelope reports whether data is an encrypted credentials file.
+func isEnvelope(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// fileCipher encrypts and decrypts credentials files. Derived keys are
+// cached per salt since PBKDF2 is deliberately slow.
+type fileCipher struct {
+	mu         sync.Mutex
+	iterations int
+	passphrase func() string
+	keyringKey func(create bool) ([]byte, error)
+
+	// salt is reused across writes so the cached key stays valid; each
+	// write still gets a fresh nonce.
+	salt []byte
+	keys map[string][]byte
+}
+
+func newFileCipher() *fileCipher {
+	return &fileCipher{
+		iterations: pbkdf2Iterations,
+		passphrase: func() string { return os.Getenv(PassphraseEnv) },
+		keyringKey: keyringFileKey,
+		keys:       make(map[string][]byte),
+	}
+}
+
+// keyringFileKey reads the file store key from the system keychain,
+// generating and storing one if create is set and none exists.
+func keyringFileKey(create bool) ([]byte, error) {
+	data, err := keyring.Get(keyringService, fileKeyName)
+	if err == nil {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		return nil, fmt.Errorf("keyring get: %w", err)
+	}
+	if !create {
+		return nil, errors.New("credentials file is encrypted but its key is missing from the system keychain")
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	if err := keyring.Set(keyringService, fileKeyName, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("keyring set: %w", err)
+	}
+	return key, nil
+}
+
+// key returns the AES key for the given source. create allows generating a
+// keychain key on first write. Caller must hold c.mu.
+func (c *fileCipher) key(source Encryption, salt []byte, iterations int, create bool) ([]byte, error) {
+	switch source {
+	case EncryptionKeyring:
+		return c.keyringKey(create)
+
+	case EncryptionPassphrase:
+		passphrase := c.passphrase()
+		if passphrase == "" {
+			return nil, fmt.Errorf("credentials are passphrase-encrypted but %s is not set", PassphraseEnv)
+		}
+		cacheKey := passphrase + "\x00" + string(salt) + "\x00" + fmt.Sprint(iterations)
+		if key, ok := c.keys[cacheKey]; ok {
+			return key, nil
+		}
+		key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("derive key: %w", err)
+		}
+		c.keys[cacheKey] = key
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("unknown key source %q", source)
+	}
+}
+
+// seal encrypts plaintext into an envelope.
+func (c *fileCipher) seal(source Encryption, plaintext []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	env := envelope{Version: envelopeVersion, KeySource: source}
+	if source == EncryptionPassphrase {
+		if c.salt == nil {
+			c.salt = make([]byte, 16)
+			if _, err := rand.Read(c.salt); err != nil {
+				return nil, fmt.Errorf("generate salt: %w", err)
+			}
+		}
+		env.Salt = c.salt
+		env.Iterations = c.iterations
+	}
+
+	key, err := c.key(source, env.Salt, env.Iterations, true)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, nil)
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// open decrypts an envelope, returning the plaintext.
+func (c *fileCipher) open(data []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parse encrypted credentials: %w", err)
+	}
+	if env.Version != envelopeVersion {
+		return nil, fmt.Errorf("unsupported credentials file version %d", env.Version)
+	}
+
+	key, err := c.key(env.KeySource, env.Salt, env.Iterations, false)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	if env.KeySource == EncryptionPassphrase {
+		c.salt = env.Salt
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
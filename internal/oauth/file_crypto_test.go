@@ -0,0 +1,185 @@
+package oauth
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestEncryptedStore returns a file store at path encrypting with enc.
+// The passphrase is read from $MCPMU_CREDENTIALS_PASSPHRASE; PBKDF2 runs with
+// few iterations and the keychain is replaced by key.
+func newTestEncryptedStore(path string, enc Encryption, key []byte) *FileStore {
+	store := NewFileStoreAt(path)
+	store.encryption = enc
+	store.cipher.iterations = 1000
+	store.cipher.keyringKey = func(create bool) ([]byte, error) {
+		if key == nil {
+			return nil, errors.New("no key")
+		}
+		return key, nil
+	}
+	return store
+}
+
+func testCredential() *Credential {
+	return &Credential{
+		ServerName:   "test-server",
+		ServerURL:    "https://mcp.example.com/mcp",
+		ClientID:     "client-123",
+		AccessToken:  "secret-access-token",
+		RefreshToken: "secret-refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).UnixMilli(),
+	}
+}
+
+func TestFileStore_PassphraseEncryption(t *testing.T) {
+	t.Setenv(PassphraseEnv, "correct horse battery staple")
+	path := filepath.Join(t.TempDir(), "creds.json")
+
+	store := newTestEncryptedStore(path, EncryptionPassphrase, nil)
+	if err := store.Put(testCredential()); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if bytes.Contains(data, []byte("secret-access-token")) || bytes.Contains(data, []byte("secret-refresh-token")) {
+		t.Fatalf("tokens written in plaintext:\n%s", data)
+	}
+
+	// A fresh store (fresh key cache) reloads and decrypts
+	reloaded := newTestEncryptedStore(path, EncryptionPassphrase, nil)
+	got, err := reloaded.Get("https://mcp.example.com/mcp")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil || got.AccessToken != "secret-access-token" || got.RefreshToken != "secret-refresh-token" {
+		t.Fatalf("Get = %+v, want decrypted credential", got)
+	}
+}
+
+func TestFileStore_WrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+
+	t.Setenv(PassphraseEnv, "right")
+	if err := newTestEncryptedStore(path, EncryptionPassphrase, nil).Put(testCredential()); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	t.Setenv(PassphraseEnv, "wrong")
+	store := newTestEncryptedStore(path, EncryptionPassphrase, nil)
+	if _, err := store.Get("https://mcp.example.com/mcp"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Get with wrong passphrase: err = %v, want ErrDecrypt", err)
+	}
+	if err := store.Put(testCredential()); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Put with wrong passphrase: err = %v, want ErrDecrypt", err)
+	}
+
+	t.Setenv(PassphraseEnv, "")
+	if _, err := store.List(); err == nil {
+		t.Error("List without passphrase: expected error")
+	}
+}
+
+func TestFileStore_KeyringEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	key := bytes.Repeat([]byte{7}, 32)
+
+	if err := newTestEncryptedStore(path, EncryptionKeyring, key).Put(testCredential()); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	got, err := newTestEncryptedStore(path, EncryptionKeyring, key).Get("https://mcp.example.com/mcp")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil || got.AccessToken != "secret-access-token" {
+		t.Fatalf("Get = %+v, want decrypted credential", got)
+	}
+
+	other := bytes.Repeat([]byte{8}, 32)
+	if _, err := newTestEncryptedStore(path, EncryptionKeyring, other).Get("https://mcp.example.com/mcp"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Get with wrong key: err = %v, want ErrDecrypt", err)
+	}
+	if _, err := newTestEncryptedStore(path, EncryptionKeyring, nil).Get("https://mcp.example.com/mcp"); err == nil {
+		t.Error("Get with missing key: expected error")
+	}
+}
+
+func TestFileStore_MigratesPlaintextOnWrite(t *testing.T) {
+	t.Setenv(PassphraseEnv, "migrate-me")
+	path := filepath.Join(t.TempDir(), "creds.json")
+
+	// Existing plaintext store
+	if err := NewFileStoreAt(path).Put(testCredential()); err != nil {
+		t.Fatalf("plaintext Put failed: %v", err)
+	}
+
+	store := newTestEncryptedStore(path, EncryptionPassphrase, nil)
+
+	// Reads still work before any write
+	got, err := store.Get("https://mcp.example.com/mcp")
+	if err != nil || got == nil {
+		t.Fatalf("Get from plaintext file = %+v, %v", got, err)
+	}
+	data, _ := os.ReadFile(path)
+	if isEnvelope(data) {
+		t.Fatal("file encrypted by a read")
+	}
+
+	// The first write migrates every credential
+	second := testCredential()
+	second.ServerURL = "https://other.example.com/mcp"
+	if err := store.Put(second); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !isEnvelope(data) || bytes.Contains(data, []byte("secret-access-token")) {
+		t.Fatalf("file not encrypted after write:\n%s", data)
+	}
+
+	creds, err := newTestEncryptedStore(path, EncryptionPassphrase, nil).List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(creds) != 2 {
+		t.Errorf("expected 2 migrated credentials, got %d", len(creds))
+	}
+}
+
+func TestNewEncryptedFileStore_RequiresPassphrase(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	if _, err := NewEncryptedFileStoreInDir(t.TempDir(), EncryptionPassphrase); err == nil {
+		t.Error("expected error without passphrase")
+	}
+}
+
+func TestParseEncryption(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Encryption
+		wantErr bool
+	}{
+		{"", EncryptionNone, false},
+		{"none", EncryptionNone, false},
+		{"keyring", EncryptionKeyring, false},
+		{"passphrase", EncryptionPassphrase, false},
+		{"aes", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEncryption(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEncryption(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEncryption(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -13,26 +13,52 @@ const (
 	credentialsFile = ".credentials.json"
 )
 
-// FileStore stores credentials in a JSON file.
+// FileStore stores credentials in a JSON file, optionally encrypted.
+// Encrypted files are always readable given their key; the encryption
+// setting decides the format of the next write, so a plaintext file is
+// migrated the first time credentials change.
 type FileStore struct {
-	path string
-	mu   sync.RWMutex
+	path       string
+	encryption Encryption
+	cipher     *fileCipher
+	mu         sync.RWMutex
 }
 
 // NewFileStore creates a new file-based credential store.
 func NewFileStore() (*FileStore, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("get home dir: %w", err)
-	}
+	return NewFileStoreInDir("")
+}
 
-	path := filepath.Join(home, credentialsDir, credentialsFile)
-	return &FileStore{path: path}, nil
+// NewFileStoreInDir creates a file-based credential store in dir, or in
+// ~/.config/mcpmu if dir is empty.
+func NewFileStoreInDir(dir string) (*FileStore, error) {
+	return NewEncryptedFileStoreInDir(dir, EncryptionNone)
+}
+
+// NewEncryptedFileStoreInDir creates a file-based credential store in dir
+// (or ~/.config/mcpmu) that writes credentials with the given encryption.
+func NewEncryptedFileStoreInDir(dir string, enc Encryption) (*FileStore, error) {
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("get home dir: %w", err)
+		}
+		dir = filepath.Join(home, credentialsDir)
+	}
+	if enc == "" {
+		enc = EncryptionNone
+	}
+	if enc == EncryptionPassphrase && os.Getenv(PassphraseEnv) == "" {
+		return nil, fmt.Errorf("passphrase encryption requires %s", PassphraseEnv)
+	}
+	store := NewFileStoreAt(filepath.Join(dir, credentialsFile))
+	store.encryption = enc
+	return store, nil
 }
 
 // NewFileStoreAt creates a file store at a specific path (for testing).
 func NewFileStoreAt(path string) *FileStore {
-	return &FileStore{path: path}
+	return &FileStore{path: path, encryption: EncryptionNone, cipher: newFileCipher()}
 }
 
 // Get retrieves credentials for a server by URL.
@@ -123,6 +149,12 @@ func (s *FileStore) load() ([]*Credential, error) {
 		return nil, fmt.Errorf("read credentials: %w", err)
 	}
 
+	if isEnvelope(data) {
+		if data, err = s.cipher.open(data); err != nil {
+			return nil, err
+		}
+	}
+
 	var creds []*Credential
 	if err := json.Unmarshal(data, &creds); err != nil {
 		return nil, fmt.Errorf("parse credentials: %w", err)
@@ -149,6 +181,11 @@ func (s *FileStore) save(creds []*Credential) error {
 	if err != nil {
 		return fmt.Errorf("marshal credentials: %w", err)
 	}
+	if s.encryption != EncryptionNone {
+		if data, err = s.cipher.seal(s.encryption, data); err != nil {
+			return err
+		}
+	}
 
 	// Write to temp file first
 	tmpPath := path + ".tmp"
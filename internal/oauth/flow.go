@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -461,6 +462,7 @@ type WarningHandler func(serverURL string, warning error)
 // TokenManager handles automatic token refresh.
 type TokenManager struct {
 	store     CredentialStore
+	mu        sync.Mutex                              // serializes refreshes and guards metadata
 	metadata  map[string]*AuthorizationServerMetadata // cached by server URL
 	onWarning WarningHandler
 }
@@ -481,16 +483,56 @@ func (m *TokenManager) SetWarningHandler(handler WarningHandler) {
 
 // GetAccessToken returns a valid access token for a server, refreshing if needed.
 func (m *TokenManager) GetAccessToken(ctx context.Context, serverURL string) (string, error) {
+	cred, err := m.credential(serverURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Check if token needs refresh
+	if !cred.NeedsRefresh() {
+		return cred.AccessToken, nil
+	}
+
+	return m.refresh(ctx, serverURL, (*Credential).NeedsRefresh)
+}
+
+// RefreshAccessToken refreshes a server's access token even if it has not
+// expired yet, for when the server rejected it with a 401 (revoked early,
+// clock skew). rejected is the token the server refused: if the stored token
+// has already been replaced, by a concurrent call that got the same 401, it
+// is returned without refreshing again. Returns the new access token.
+func (m *TokenManager) RefreshAccessToken(ctx context.Context, serverURL, rejected string) (string, error) {
+	return m.refresh(ctx, serverURL, func(cred *Credential) bool {
+		return rejected == "" || cred.AccessToken == rejected
+	})
+}
+
+// credential loads the stored credential for a server.
+func (m *TokenManager) credential(serverURL string) (*Credential, error) {
 	cred, err := m.store.Get(serverURL)
 	if err != nil {
-		return "", fmt.Errorf("get credential: %w", err)
+		return nil, fmt.Errorf("get credential: %w", err)
 	}
 	if cred == nil {
-		return "", fmt.Errorf("no credentials for %s", serverURL)
+		return nil, fmt.Errorf("no credentials for %s", serverURL)
 	}
+	return cred, nil
+}
 
-	// Check if token needs refresh
-	if !cred.NeedsRefresh() {
+// refresh exchanges the stored refresh token for a new access token and
+// stores it, unless stale reports that the stored credential no longer needs
+// it. The credential is read under the lock: refresh tokens may be rotated,
+// so a caller that read it before another refresh finished would present a
+// spent refresh token.
+func (m *TokenManager) refresh(ctx context.Context, serverURL string, stale func(*Credential) bool) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, err := m.credential(serverURL)
+	if err != nil {
+		return "", err
+	}
+	if !stale(cred) {
 		return cred.AccessToken, nil
 	}
 
@@ -0,0 +1,175 @@
+package oauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// passPrefix is the folder in the password store holding mcpmu's entries.
+const passPrefix = "mcpmu/"
+
+// errPassNotFound is returned by a passRunner when the entry doesn't exist.
+var errPassNotFound = errors.New("not in the password store")
+
+// passRunner runs the pass command with args, feeding it stdin, and returns
+// its stdout. It returns errPassNotFound for a missing entry.
+type passRunner func(stdin string, args ...string) (string, error)
+
+// PassStore stores credentials in pass, the standard Unix password manager,
+// one GPG-encrypted entry per server under mcpmu/ plus an index entry.
+type PassStore struct {
+	run passRunner
+	mu  sync.RWMutex
+}
+
+// NewPassStore creates a pass-based credential store. Returns an error if
+// the pass command is not installed.
+func NewPassStore() (*PassStore, error) {
+	path, err := exec.LookPath("pass")
+	if err != nil {
+		return nil, fmt.Errorf("pass not available: %w", err)
+	}
+	return &PassStore{run: execPass(path)}, nil
+}
+
+// execPass returns a passRunner that executes the pass binary at path.
+func execPass(path string) passRunner {
+	return func(stdin string, args ...string) (string, error) {
+		cmd := exec.Command(path, args...)
+		cmd.Stdin = strings.NewReader(stdin)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if strings.Contains(msg, "is not in the password store") {
+				return "", errPassNotFound
+			}
+			return "", fmt.Errorf("pass %s: %w: %s", args[0], err, msg)
+		}
+		return stdout.String(), nil
+	}
+}
+
+// Get retrieves credentials for a server by URL.
+func (s *PassStore) Get(serverURL string) (*Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.get(serverURL)
+}
+
+// get reads one entry (caller must hold lock).
+func (s *PassStore) get(serverURL string) (*Credential, error) {
+	data, err := s.run("", "show", passPrefix+urlToKey(serverURL))
+	if err != nil {
+		if errors.Is(err, errPassNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cred Credential
+	if err := json.Unmarshal([]byte(data), &cred); err != nil {
+		return nil, fmt.Errorf("parse credential: %w", err)
+	}
+	return &cred, nil
+}
+
+// Put stores credentials for a server.
+func (s *PassStore) Put(cred *Credential) error {
+	if err := cred.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("marshal credential: %w", err)
+	}
+	if _, err := s.run(string(data)+"\n", "insert", "--multiline", "--force", passPrefix+urlToKey(cred.ServerURL)); err != nil {
+		return err
+	}
+
+	urls, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(urls, cred.ServerURL) {
+		return nil
+	}
+	return s.saveIndex(append(urls, cred.ServerURL))
+}
+
+// Delete removes credentials for a server.
+func (s *PassStore) Delete(serverURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.run("", "rm", "--force", passPrefix+urlToKey(serverURL)); err != nil && !errors.Is(err, errPassNotFound) {
+		return err
+	}
+
+	urls, err := s.loadIndex()
+	if err != nil {
+		return err
+	}
+	return s.saveIndex(slices.DeleteFunc(urls, func(u string) bool { return u == serverURL }))
+}
+
+// List returns all stored credentials.
+func (s *PassStore) List() ([]*Credential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	urls, err := s.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]*Credential, 0, len(urls))
+	for _, url := range urls {
+		cred, err := s.get(url)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			creds = append(creds, cred)
+		}
+	}
+	return creds, nil
+}
+
+// loadIndex reads the list of stored server URLs (caller must hold lock).
+func (s *PassStore) loadIndex() ([]string, error) {
+	data, err := s.run("", "show", passPrefix+keyringIndexKey)
+	if err != nil {
+		if errors.Is(err, errPassNotFound) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	var urls []string
+	if err := json.Unmarshal([]byte(data), &urls); err != nil {
+		return nil, fmt.Errorf("parse index: %w", err)
+	}
+	return urls, nil
+}
+
+// saveIndex writes the list of stored server URLs (caller must hold lock).
+func (s *PassStore) saveIndex(urls []string) error {
+	data, err := json.Marshal(urls)
+	if err != nil {
+		return fmt.Errorf("marshal index: %w", err)
+	}
+	_, err = s.run(string(data)+"\n", "insert", "--multiline", "--force", passPrefix+keyringIndexKey)
+	return err
+}
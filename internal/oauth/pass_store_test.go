@@ -0,0 +1,142 @@
+package oauth
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePass is an in-memory stand-in for the pass command.
+type fakePass struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newFakePassStore() (*PassStore, *fakePass) {
+	fp := &fakePass{entries: make(map[string]string)}
+	return &PassStore{run: fp.run}, fp
+}
+
+func (f *fakePass) run(stdin string, args ...string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := args[len(args)-1]
+	switch args[0] {
+	case "show":
+		data, ok := f.entries[name]
+		if !ok {
+			return "", errPassNotFound
+		}
+		return data, nil
+	case "insert":
+		f.entries[name] = stdin
+		return "", nil
+	case "rm":
+		if _, ok := f.entries[name]; !ok {
+			return "", errPassNotFound
+		}
+		delete(f.entries, name)
+		return "", nil
+	}
+	return "", nil
+}
+
+func TestPassStore_RoundTrip(t *testing.T) {
+	store, fp := newFakePassStore()
+
+	cred := &Credential{
+		ServerName:   "test-server",
+		ServerURL:    "https://mcp.example.com/mcp",
+		ClientID:     "client-123",
+		AccessToken:  "access-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).UnixMilli(),
+	}
+	if err := store.Put(cred); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	// Entries live under mcpmu/ in the password store
+	for name := range fp.entries {
+		if !strings.HasPrefix(name, passPrefix) {
+			t.Errorf("entry %q not under %s", name, passPrefix)
+		}
+	}
+
+	got, err := store.Get(cred.ServerURL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got == nil || got.AccessToken != "access-token" || got.RefreshToken != "refresh-token" {
+		t.Fatalf("Get = %+v, want stored credential", got)
+	}
+
+	// Overwrite keeps a single index entry
+	cred.AccessToken = "new-token"
+	if err := store.Put(cred); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	creds, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(creds) != 1 || creds[0].AccessToken != "new-token" {
+		t.Fatalf("List = %+v, want one updated credential", creds)
+	}
+
+	if err := store.Delete(cred.ServerURL); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err = store.Get(cred.ServerURL)
+	if err != nil {
+		t.Fatalf("Get after delete failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil after delete, got %+v", got)
+	}
+	creds, err = store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(creds) != 0 {
+		t.Errorf("expected empty list after delete, got %d", len(creds))
+	}
+}
+
+func TestPassStore_EmptyStore(t *testing.T) {
+	store, _ := newFakePassStore()
+
+	got, err := store.Get("https://nonexistent.com/mcp")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil, got %+v", got)
+	}
+
+	creds, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(creds) != 0 {
+		t.Errorf("expected empty list, got %d", len(creds))
+	}
+
+	// Deleting a missing entry is not an error
+	if err := store.Delete("https://nonexistent.com/mcp"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+}
+
+func TestPassStore_Put_Validation(t *testing.T) {
+	store, fp := newFakePassStore()
+
+	if err := store.Put(&Credential{ServerURL: "https://mcp.example.com/mcp"}); err == nil {
+		t.Error("expected validation error")
+	}
+	if len(fp.entries) != 0 {
+		t.Errorf("invalid credential was written: %v", fp.entries)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -75,3 +76,148 @@ func TestTokenManager_RefreshTokenFailure_ReturnsError(t *testing.T) {
 		t.Fatalf("AccessToken mutated on refresh failure: got %q, want %q", stored.AccessToken, "expired-token")
 	}
 }
+
+func TestTokenManager_RefreshAccessToken_RefreshesUnexpiredToken(t *testing.T) {
+	var tokenEndpointURL string
+	var refreshes int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server/mcp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 "https://issuer.example",
+			"authorization_endpoint": "https://auth.example/authorize",
+			"token_endpoint":         tokenEndpointURL,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "fresh-token",
+			"refresh_token": "rotated-refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tokenEndpointURL = server.URL + "/token"
+	serverURL := server.URL + "/mcp"
+
+	store := NewFileStoreAt(filepath.Join(t.TempDir(), "creds.json"))
+	if err := store.Put(&Credential{
+		ServerName:   "test",
+		ServerURL:    serverURL,
+		ClientID:     "client-123",
+		AccessToken:  "revoked-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).UnixMilli(),
+	}); err != nil {
+		t.Fatalf("failed to store credential: %v", err)
+	}
+
+	manager := NewTokenManager(store)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Not expired, so GetAccessToken hands back the stored token as is
+	if token, err := manager.GetAccessToken(ctx, serverURL); err != nil || token != "revoked-token" {
+		t.Fatalf("GetAccessToken = %q, %v; want the stored token", token, err)
+	}
+
+	token, err := manager.RefreshAccessToken(ctx, serverURL, "revoked-token")
+	if err != nil {
+		t.Fatalf("RefreshAccessToken: %v", err)
+	}
+	if token != "fresh-token" || refreshes != 1 {
+		t.Errorf("RefreshAccessToken = %q after %d refreshes, want fresh-token after 1", token, refreshes)
+	}
+
+	stored, err := store.Get(serverURL)
+	if err != nil {
+		t.Fatalf("failed to re-read credential: %v", err)
+	}
+	if stored.AccessToken != "fresh-token" || stored.RefreshToken != "rotated-refresh-token" {
+		t.Errorf("stored credential not updated: access=%q refresh=%q", stored.AccessToken, stored.RefreshToken)
+	}
+}
+
+func TestTokenManager_RefreshAccessToken_ConcurrentRejectionsRefreshOnce(t *testing.T) {
+	var tokenEndpointURL string
+	var mu sync.Mutex
+	var refreshes int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server/mcp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 "https://issuer.example",
+			"authorization_endpoint": "https://auth.example/authorize",
+			"token_endpoint":         tokenEndpointURL,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		// Refresh tokens are rotated: the original one only works once
+		if r.FormValue("refresh_token") != "refresh-token" || refreshes > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_grant"})
+			return
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "fresh-token",
+			"refresh_token": "rotated-refresh-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tokenEndpointURL = server.URL + "/token"
+	serverURL := server.URL + "/mcp"
+
+	store := NewFileStoreAt(filepath.Join(t.TempDir(), "creds.json"))
+	if err := store.Put(&Credential{
+		ServerName:   "test",
+		ServerURL:    serverURL,
+		ClientID:     "client-123",
+		AccessToken:  "revoked-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).UnixMilli(),
+	}); err != nil {
+		t.Fatalf("failed to store credential: %v", err)
+	}
+
+	manager := NewTokenManager(store)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Several calls got a 401 for the same token and refresh at once
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	errs := make([]error, 5)
+	for i := range tokens {
+		wg.Go(func() {
+			tokens[i], errs[i] = manager.RefreshAccessToken(ctx, serverURL, "revoked-token")
+		})
+	}
+	wg.Wait()
+
+	for i := range tokens {
+		if errs[i] != nil || tokens[i] != "fresh-token" {
+			t.Errorf("RefreshAccessToken #%d = %q, %v; want fresh-token", i, tokens[i], errs[i])
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+}
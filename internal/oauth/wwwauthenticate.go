@@ -17,6 +17,9 @@ type BearerChallenge struct {
 
 	// Scope from scope="..." parameter.
 	Scope string
+
+	// Error from error="..." parameter (RFC 6750), e.g. "invalid_token".
+	Error string
 }
 
 // ParseBearerChallenge extracts Bearer challenge info from HTTP response headers.
@@ -37,6 +40,7 @@ func ParseBearerChallengeValues(values []string) *BearerChallenge {
 					ResourceMetadata: ch.params["resource_metadata"],
 					Realm:            ch.params["realm"],
 					Scope:            ch.params["scope"],
+					Error:            ch.params["error"],
 				}
 			}
 		}
@@ -45,6 +45,14 @@ func TestParseBearerChallengeValues(t *testing.T) {
 				ResourceMetadata: "https://auth.example.com/resource-metadata",
 			},
 		},
+		{
+			name:   "bearer with error",
+			values: []string{`Bearer realm="example", error="invalid_token", error_description="The access token expired"`},
+			want: &BearerChallenge{
+				Realm: "example",
+				Error: "invalid_token",
+			},
+		},
 		{
 			name:   "bearer case insensitive scheme",
 			values: []string{`BEARER resource_metadata="https://example.com"`},
@@ -212,6 +220,9 @@ func TestParseBearerChallengeValues(t *testing.T) {
 			if got.Scope != tt.want.Scope {
 				t.Errorf("Scope = %q, want %q", got.Scope, tt.want.Scope)
 			}
+			if got.Error != tt.want.Error {
+				t.Errorf("Error = %q, want %q", got.Error, tt.want.Error)
+			}
 		})
 	}
 }
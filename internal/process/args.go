@@ -0,0 +1,63 @@
+package process
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// envRefPattern matches a ${NAME} reference in a server arg.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandArgs expands placeholders in a stdio server's args: {{home}} and
+// {{config_dir}} as in cwd, and ${NAME} from env, the environment the
+// process is started with. Args without placeholders are passed through
+// untouched. A reference to an unset variable is an error rather than an
+// empty string, which would silently change the argument's meaning.
+func (s *Supervisor) expandArgs(args, env []string) ([]string, error) {
+	var expanded []string
+	for i, arg := range args {
+		if !strings.Contains(arg, "{{") && !strings.Contains(arg, "${") {
+			continue
+		}
+		if expanded == nil {
+			expanded = append([]string(nil), args...)
+		}
+
+		value, err := s.expandPathPlaceholders(arg)
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %w", i, err)
+		}
+
+		var missing string
+		value = envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			v, ok := lookupEnv(env, name)
+			if !ok && missing == "" {
+				missing = name
+			}
+			return v
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("arg %d: environment variable %s is not set", i, missing)
+		}
+		expanded[i] = value
+	}
+
+	if expanded == nil {
+		return args, nil
+	}
+	return expanded, nil
+}
+
+// lookupEnv finds name in a NAME=value environment list. The last entry
+// wins, matching how the process sees duplicates.
+func lookupEnv(env []string, name string) (string, bool) {
+	prefix := name + "="
+	for i := len(env) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(env[i], prefix); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,48 @@
+package process
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSupervisor_ExpandArgs(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Supervisor{configDir: "/etc/mcpmu"}
+	env := []string{"PATH=/usr/bin", "DATA_ROOT=/srv/data", "DATA_ROOT=/srv/override"}
+
+	args := []string{
+		"-y",
+		"--root={{config_dir}}/fs",
+		"{{home}}/projects",
+		"${DATA_ROOT}/cache",
+		"$DATA_ROOT",
+		"{{tempdir}}",
+	}
+	got, err := s.expandArgs(args, env)
+	if err != nil {
+		t.Fatalf("expandArgs: %v", err)
+	}
+	want := []string{
+		"-y",
+		"--root=/etc/mcpmu/fs",
+		home + "/projects",
+		"/srv/override/cache",
+		"$DATA_ROOT",
+		"{{tempdir}}",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expandArgs = %q, want %q", got, want)
+	}
+	if args[1] != "--root={{config_dir}}/fs" {
+		t.Errorf("expandArgs modified the config's args: %q", args)
+	}
+
+	if _, err := s.expandArgs([]string{"${MCPMU_UNSET_VAR}"}, env); err == nil || !strings.Contains(err.Error(), "MCPMU_UNSET_VAR") {
+		t.Errorf("expected an error naming the unset variable, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package process
+
+import (
+	"context"
+	"log"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// Autostart starts the given servers in order, with at most limit starting
+// at once (no limit when limit <= 0). A stdio server holds its slot until it
+// has initialized, so a limit of 1 brings servers up one after another. It
+// blocks until every start has finished; failures are logged.
+func (s *Supervisor) Autostart(ctx context.Context, entries []config.ServerEntry, limit int) {
+	if limit <= 0 {
+		limit = max(len(entries), 1)
+	}
+	sem := make(chan struct{}, limit)
+	for _, entry := range entries {
+		sem <- struct{}{}
+		log.Printf("Autostarting server: %s", entry.Name)
+		go func(name string, srv config.ServerConfig) {
+			defer func() { <-sem }()
+			handle, err := s.Start(ctx, name, srv)
+			if err != nil {
+				log.Printf("Failed to autostart server %s: %v", name, err)
+				return
+			}
+			// Stdio servers initialize in the background; hold the slot
+			// until they are up (HTTP starts are already done)
+			if !srv.IsHTTP() {
+				_ = handle.WaitForTools(ctx)
+			}
+		}(entry.Name, entry.Config)
+	}
+	// Wait for the last starts to release their slots
+	for range limit {
+		sem <- struct{}{}
+	}
+}
@@ -0,0 +1,50 @@
+package process
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// fastExitWindow is how soon after start an unexpected exit counts as a
+// startup failure worth diagnosing from stderr.
+const fastExitWindow = 10 * time.Second
+
+// stderrDrainTimeout bounds how long exit handling waits for the last stderr
+// lines of a process that exited during startup.
+const stderrDrainTimeout = 500 * time.Millisecond
+
+// startupConflict is a stderr pattern that points at a port or resource
+// clash, with the hint reported when it matches.
+type startupConflict struct {
+	pattern *regexp.Regexp
+	hint    string
+}
+
+// startupConflicts are checked in order; the first match wins. They are
+// heuristics over common runtimes' wording, so keep them specific enough not
+// to fire on unrelated errors.
+var startupConflicts = []startupConflict{
+	{
+		pattern: regexp.MustCompile(`(?i)address already in use|EADDRINUSE|port (is )?already (in use|allocated)|only one usage of each socket address`),
+		hint:    "port conflict: the port it binds is already in use, possibly by another configured server or a previous instance",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)already running|another instance|lock ?file|database is locked|could not (acquire|obtain) (the )?lock|resource temporarily unavailable`),
+		hint:    "resource conflict: another instance appears to hold its lock file or shared resource",
+	},
+}
+
+// diagnoseStartupFailure scans the stderr of a server that exited during
+// startup for a known port or resource conflict. It returns an actionable
+// message quoting the offending line, or "" if nothing matched.
+func diagnoseStartupFailure(logs []string) string {
+	for _, c := range startupConflicts {
+		for i := len(logs) - 1; i >= 0; i-- {
+			if c.pattern.MatchString(logs[i]) {
+				return c.hint + " (stderr: " + strings.TrimSpace(logs[i]) + ")"
+			}
+		}
+	}
+	return ""
+}
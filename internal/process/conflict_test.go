@@ -0,0 +1,38 @@
+package process
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseStartupFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		logs []string
+		want string // prefix of the hint, "" for no match
+	}{
+		{"go bind", []string{"starting", "listen tcp :8080: bind: address already in use"}, "port conflict"},
+		{"node", []string{"Error: listen EADDRINUSE: address already in use :::3000"}, "port conflict"},
+		{"lock file", []string{"fatal: could not acquire lock on /tmp/srv.lock"}, "resource conflict"},
+		{"sqlite", []string{"Error: database is locked"}, "resource conflict"},
+		{"unrelated", []string{"panic: nil pointer dereference"}, ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diagnoseStartupFailure(tt.logs)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("diagnoseStartupFailure() = %q, want no match", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("diagnoseStartupFailure() = %q, want prefix %q", got, tt.want)
+			}
+			if !strings.Contains(got, tt.logs[len(tt.logs)-1]) {
+				t.Errorf("diagnoseStartupFailure() = %q, want it to quote the stderr line", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package process
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestBuildEnv_InheritsByDefault(t *testing.T) {
+	t.Setenv("PATH", "/custom/bin")
+	t.Setenv("MCPMU_TEST_HOST_SECRET", "s3cret")
+
+	env := buildEnv(config.ServerConfig{Env: map[string]string{"FOO": "bar"}})
+
+	for _, want := range []string{
+		"PATH=" + AugmentPath("/custom/bin"),
+		"MCPMU_TEST_HOST_SECRET=s3cret",
+		"FOO=bar",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env missing %q", want)
+		}
+	}
+}
+
+func TestBuildEnv_CleanEnv(t *testing.T) {
+	t.Setenv("PATH", "/custom/bin")
+	t.Setenv("MCPMU_TEST_HOST_SECRET", "s3cret")
+	t.Setenv("MCPMU_TEST_LANG", "en_GB.UTF-8")
+
+	env := buildEnv(config.ServerConfig{
+		CleanEnv: true,
+		PassEnv:  []string{"MCPMU_TEST_LANG", "MCPMU_TEST_UNSET"},
+		Env:      map[string]string{"FOO": "bar"},
+	})
+	slices.Sort(env)
+
+	want := []string{
+		"FOO=bar",
+		"MCPMU_TEST_LANG=en_GB.UTF-8",
+		"PATH=" + AugmentPath("/custom/bin"),
+	}
+	if !slices.Equal(env, want) {
+		t.Errorf("env = %v, want %v", env, want)
+	}
+	for _, e := range env {
+		if strings.HasPrefix(e, "MCPMU_TEST_HOST_SECRET=") {
+			t.Errorf("host secret leaked into clean environment: %q", e)
+		}
+	}
+}
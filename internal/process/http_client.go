@@ -0,0 +1,96 @@
+package process
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// httpClient builds the HTTP client for a server's TLS and proxy settings.
+// It returns nil (use the default client) when the server has neither.
+func httpClient(name string, srv config.ServerConfig) (*http.Client, error) {
+	transport, err := HTTPTransport(srv)
+	if err != nil || transport == nil {
+		return nil, err
+	}
+	if srv.TLS != nil && srv.TLS.InsecureSkipVerify {
+		log.Printf("WARNING: TLS certificate verification is DISABLED for server %s (tls.insecure_skip_verify). "+
+			"Anyone on the network path can impersonate it and read its traffic, including credentials. Use only for local development.", name)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// HTTPTransport builds the transport for a server's TLS and proxy settings,
+// for other code that talks to the server directly. It returns nil (use the
+// default transport) when the server has neither.
+func HTTPTransport(srv config.ServerConfig) (http.RoundTripper, error) {
+	if srv.TLS == nil && srv.Proxy == "" {
+		return nil, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if srv.TLS != nil {
+		conf, err := buildTLSConfig(srv.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		transport.TLSClientConfig = conf
+	}
+
+	switch srv.Proxy {
+	case "":
+		// Keep the default: HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	case config.ProxyDirect:
+		transport.Proxy = nil
+	default:
+		proxyURL, err := config.ParseProxyURL(srv.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+// buildTLSConfig loads the CA bundle and client certificate named in cfg.
+func buildTLSConfig(cfg *config.TLSConfig) (*tls.Config, error) {
+	conf := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // explicit per-server opt-in, warned about by httpClient
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s contains no PEM certificates", cfg.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("cert_file and key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,318 @@
+package process
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// writeServerCA writes the test server's certificate as a PEM CA bundle.
+func writeServerCA(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func getURL(t *testing.T, client *http.Client, url string) error {
+	t.Helper()
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func TestHTTPClient_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// Without the CA the self-signed certificate is rejected
+	client, err := httpClient("srv", config.ServerConfig{})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, srv.URL); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected certificate error without ca_file, got %v", err)
+	}
+
+	client, err = httpClient("srv", config.ServerConfig{TLS: &config.TLSConfig{CAFile: writeServerCA(t, srv)}})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, srv.URL); err != nil {
+		t.Errorf("expected connection with ca_file to succeed, got %v", err)
+	}
+}
+
+func TestHTTPClient_InsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client, err := httpClient("srv", config.ServerConfig{TLS: &config.TLSConfig{InsecureSkipVerify: true}})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, srv.URL); err != nil {
+		t.Errorf("expected insecure connection to succeed, got %v", err)
+	}
+}
+
+func TestHTTPClient_ClientCertificate(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := writeServerCA(t, srv)
+
+	client, err := httpClient("srv", config.ServerConfig{TLS: &config.TLSConfig{CAFile: caFile}})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, srv.URL); err == nil {
+		t.Error("expected connection without a client certificate to fail")
+	}
+
+	certFile, keyFile := writeClientCert(t)
+	client, err = httpClient("srv", config.ServerConfig{TLS: &config.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, srv.URL); err != nil {
+		t.Errorf("expected connection with a client certificate to succeed, got %v", err)
+	}
+}
+
+func TestHTTPClient_Errors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.TLSConfig
+		want string
+	}{
+		{"missing ca_file", config.TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}, "read ca_file"},
+		{"ca_file without certificates", config.TLSConfig{CAFile: notPEM}, "no PEM certificates"},
+		{"cert without key", config.TLSConfig{CertFile: notPEM}, "must be set together"},
+		{"bad key pair", config.TLSConfig{CertFile: notPEM, KeyFile: notPEM}, "load client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := httpClient("srv", config.ServerConfig{TLS: &tt.cfg})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// writeClientCert writes a self-signed client certificate and key.
+func writeClientCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mcpmu-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestHTTPClient_HTTPProxy(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.Host)
+		mu.Unlock()
+	}))
+	defer proxy.Close()
+
+	client, err := httpClient("srv", config.ServerConfig{URL: "http://mcp.internal.test/mcp", Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, "http://mcp.internal.test/mcp"); err != nil {
+		t.Fatalf("request through proxy: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(proxied) != 1 || proxied[0] != "mcp.internal.test" {
+		t.Errorf("proxy saw hosts %v, want [mcp.internal.test]", proxied)
+	}
+}
+
+func TestHTTPClient_SOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer target.Close()
+
+	proxyAddr, requested := startSOCKS5Proxy(t, target.Listener.Addr().String())
+
+	client, err := httpClient("srv", config.ServerConfig{URL: "http://mcp.internal.test/mcp", Proxy: "socks5h://" + proxyAddr})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	if err := getURL(t, client, "http://mcp.internal.test:8080/mcp"); err != nil {
+		t.Fatalf("request through SOCKS5 proxy: %v", err)
+	}
+
+	select {
+	case host := <-requested:
+		if host != "mcp.internal.test:8080" {
+			t.Errorf("SOCKS5 proxy was asked for %q, want mcp.internal.test:8080", host)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SOCKS5 proxy was not used")
+	}
+}
+
+func TestHTTPClient_InvalidProxy(t *testing.T) {
+	if _, err := httpClient("srv", config.ServerConfig{URL: "http://x.test", Proxy: "ftp://proxy:21"}); err == nil {
+		t.Error("expected error for unsupported proxy scheme")
+	}
+}
+
+// startSOCKS5Proxy runs a minimal no-auth SOCKS5 proxy that reports each
+// CONNECT destination on the returned channel and forwards the connection to
+// target regardless of the destination asked for.
+func startSOCKS5Proxy(t *testing.T, target string) (addr string, requested <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	hosts := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, target, hosts)
+		}
+	}()
+	return ln.Addr().String(), hosts
+}
+
+func serveSOCKS5(conn net.Conn, target string, hosts chan<- string) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	// Greeting: version, method count, methods. Reply "no auth".
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(r, make([]byte, header[1])); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return
+	}
+
+	// Request: version, CONNECT, reserved, address type, address, port.
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(r, req); err != nil {
+		return
+	}
+	var host string
+	switch req[3] {
+	case 1: // IPv4
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(r, ip); err != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	case 3: // domain name
+		n, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		name := make([]byte, n)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return
+		}
+		host = string(name)
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(r, port); err != nil {
+		return
+	}
+	hosts <- net.JoinHostPort(host, fmt.Sprint(binary.BigEndian.Uint16(port)))
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() { _, _ = io.Copy(upstream, r); done <- struct{}{} }()
+	go func() { _, _ = io.Copy(conn, upstream); done <- struct{}{} }()
+	<-done
+}
@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"syscall"
+
+	"github.com/Bigsy/mcpmu/internal/config"
 )
 
 const lockFileName = "manager.lock"
@@ -120,23 +121,8 @@ func (l *ManagerLock) readFile() (ManagerLockInfo, error) {
 }
 
 // lockDir returns the directory where the lock file should live,
-// co-located with the active config file. Follows the same pattern
-// as ToolCachePath.
+// co-located with the active config file (or in the relocated mcpmu
+// directory). Follows the same pattern as ToolCachePath.
 func lockDir(configPath string) (string, error) {
-	if configPath != "" {
-		expanded := configPath
-		if strings.HasPrefix(expanded, "~/") {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return "", fmt.Errorf("get home dir: %w", err)
-			}
-			expanded = filepath.Join(home, expanded[2:])
-		}
-		return filepath.Dir(expanded), nil
-	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("get home dir: %w", err)
-	}
-	return filepath.Join(home, ".config", "mcpmu"), nil
+	return config.StateDir(configPath)
 }
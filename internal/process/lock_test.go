@@ -4,6 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/oauth"
 )
 
 func TestManagerLock_AcquireRelease(t *testing.T) {
@@ -217,3 +221,43 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestConfigDirOverride_RelocatesSupervisorState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MCPMU_HOME", dir)
+
+	bus := events.NewBus()
+	defer bus.Close()
+	s := NewSupervisorWithOptions(bus, SupervisorOptions{CredentialStoreMode: "file"})
+
+	if err := s.pidTracker.Add("srv", os.Getpid(), "srv", nil); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	defer func() { _ = s.pidTracker.Remove("srv") }()
+
+	if err := s.CredentialStore().Put(&oauth.Credential{
+		ServerName:  "api",
+		ServerURL:   "https://api.example.com/mcp",
+		ClientID:    "client",
+		AccessToken: "token",
+		ExpiresAt:   time.Now().Add(time.Hour).UnixMilli(),
+	}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	lock, err := NewManagerLock("")
+	if err != nil {
+		t.Fatalf("NewManagerLock: %v", err)
+	}
+	if err := lock.Acquire("tui"); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer lock.Release()
+
+	for _, name := range []string{"pids.json", ".credentials.json", "manager.lock"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s in the relocated directory: %v", name, err)
+		}
+	}
+}
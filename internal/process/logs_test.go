@@ -0,0 +1,51 @@
+package process
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+)
+
+func TestHandle_ReadStderr_RetainsLastNLines(t *testing.T) {
+	bus := events.NewBus()
+	defer bus.Close()
+
+	h := &Handle{id: "chatty", bus: bus, maxLogLines: 3, stderrDone: make(chan struct{})}
+
+	var input strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&input, "line %d\n", i)
+	}
+	h.readStderr(io.NopCloser(strings.NewReader(input.String())))
+
+	want := []string{"line 8", "line 9", "line 10"}
+	if got := h.Logs(); !slices.Equal(got, want) {
+		t.Errorf("Logs() = %v, want %v", got, want)
+	}
+}
+
+func TestSupervisor_LogLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		supervisor int
+		server     int
+		want       int
+	}{
+		{"default", 0, 0, DefaultMaxLogLines},
+		{"supervisor option", 50, 0, 50},
+		{"server overrides supervisor", 50, 5000, 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Supervisor{maxLogLines: tt.supervisor}
+			if got := s.logLimit(config.ServerConfig{MaxLogLines: tt.server}); got != tt.want {
+				t.Errorf("logLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,11 +10,15 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/redact"
 )
 
 const (
@@ -32,6 +36,9 @@ type pidEntry struct {
 	Args              []string  `json:"args,omitempty"`              // Arguments for better matching
 	StartedAt         time.Time `json:"startedAt"`                   // Wall clock time when we started it
 	ProcessStartTicks int64     `json:"processStartTicks,omitempty"` // OS-level process start time (for PID reuse detection)
+	Cmdline           []string  `json:"cmdline,omitempty"`           // Live command line at start (identity when start ticks are unavailable)
+	OwnerPID          int       `json:"ownerPid,omitempty"`          // mcpmu process that started it
+	OwnerStartTicks   int64     `json:"ownerStartTicks,omitempty"`   // Owner's start time, so a reused owner PID isn't mistaken for it
 	RetryCount        int       `json:"retryCount,omitempty"`        // Number of failed verification attempts
 }
 
@@ -40,6 +47,9 @@ type PIDTracker struct {
 	path string
 	pids map[string]pidEntry // serverID -> entry
 	mu   sync.Mutex
+
+	// ownerStartTicks is this process's start time, recorded with each entry
+	ownerStartTicks int64
 }
 
 // NewPIDTracker creates a new PID tracker using the default directory.
@@ -53,16 +63,16 @@ func NewPIDTrackerWithDir(dir string) (*PIDTracker, error) {
 }
 
 // NewPIDTrackerInDir creates a PID tracker in the given directory with an optional file prefix.
-// If dir is empty, uses the default ~/.config/mcpmu/ directory.
+// If dir is empty, uses config.Dir() (~/.config/mcpmu/ unless relocated).
 // If prefix is non-empty (e.g. "web"), the file becomes "pids-web.json" instead of "pids.json",
 // isolating different manager modes so they don't kill each other's tracked processes.
 func NewPIDTrackerInDir(dir, prefix string) (*PIDTracker, error) {
 	if dir == "" {
-		home, err := os.UserHomeDir()
+		var err error
+		dir, err = config.Dir()
 		if err != nil {
 			return nil, err
 		}
-		dir = filepath.Join(home, ".config", "mcpmu")
 	}
 
 	fileName := pidsFile
@@ -74,6 +84,9 @@ func NewPIDTrackerInDir(dir, prefix string) (*PIDTracker, error) {
 		path: filepath.Join(dir, fileName),
 		pids: make(map[string]pidEntry),
 	}
+	if ticks, err := getProcessStartTicks(os.Getpid()); err == nil {
+		pt.ownerStartTicks = ticks
+	}
 
 	// Load existing PIDs
 	pt.load()
@@ -111,16 +124,20 @@ func (pt *PIDTracker) save() error {
 	return os.WriteFile(pt.path, data, 0600)
 }
 
-// Add tracks a new PID for a server.
+// Add tracks a new PID for a server. args are the server's configured
+// arguments before placeholder expansion; they and the recorded command line
+// are redacted, since pids.json must not hold expanded secrets.
 func (pt *PIDTracker) Add(serverID string, pid int, command string, args []string) error {
 	pt.mu.Lock()
 	defer pt.mu.Unlock()
 
 	entry := pidEntry{
-		PID:       pid,
-		Command:   command,
-		Args:      args,
-		StartedAt: time.Now(),
+		PID:             pid,
+		Command:         command,
+		Args:            redact.Args(args),
+		StartedAt:       time.Now(),
+		OwnerPID:        os.Getpid(),
+		OwnerStartTicks: pt.ownerStartTicks,
 	}
 
 	// Capture process start time for PID reuse detection
@@ -129,6 +146,9 @@ func (pt *PIDTracker) Add(serverID string, pid int, command string, args []strin
 	} else {
 		log.Printf("Warning: could not get start ticks for PID %d: %v", pid, err)
 	}
+	if cmdline, err := getProcessCmdline(pid); err == nil {
+		entry.Cmdline = cmdlineIdentity(cmdline, entry.Args)
+	}
 
 	pt.pids[serverID] = entry
 	return pt.save()
@@ -151,6 +171,7 @@ const (
 	verifyConfirmedReused                     // PID was reused by another process - safe to remove entry
 	verifyProcessGone                         // Process no longer exists - safe to remove entry
 	verifyUncertain                           // Can't verify ownership - keep entry and retry later
+	verifyOwnerAlive                          // Another live mcpmu instance started it - not an orphan
 )
 
 // CleanupOrphans checks for and terminates orphaned processes.
@@ -177,6 +198,10 @@ func (pt *PIDTracker) CleanupOrphans() int {
 				entry.PID, serverID)
 			toDelete = append(toDelete, serverID)
 
+		case verifyOwnerAlive:
+			log.Printf("Process %d (server=%s) belongs to running mcpmu pid=%d, leaving it alone",
+				entry.PID, serverID, entry.OwnerPID)
+
 		case verifyConfirmedOwned:
 			log.Printf("Found orphan process: server=%s pid=%d cmd=%s, terminating",
 				serverID, entry.PID, entry.Command)
@@ -224,6 +249,12 @@ func (pt *PIDTracker) verifyProcessOwnership(entry pidEntry) verifyResult {
 		return verifyProcessGone
 	}
 
+	// A process whose manager is still running isn't an orphan, even if it
+	// is the process we recorded (e.g. another serve instance sharing this file)
+	if entry.OwnerPID > 0 && entry.OwnerPID != os.Getpid() && isOwnerAlive(entry) {
+		return verifyOwnerAlive
+	}
+
 	// Primary verification: process start time (most reliable for PID reuse detection)
 	if entry.ProcessStartTicks > 0 {
 		currentTicks, err := getProcessStartTicks(entry.PID)
@@ -248,7 +279,16 @@ func (pt *PIDTracker) verifyProcessOwnership(entry pidEntry) verifyResult {
 		}
 	}
 
-	// Secondary verification: cmdline matching (when start ticks unavailable)
+	// Secondary verification: the exact command line recorded at start.
+	// A reused PID running the same program with other arguments differs.
+	if len(entry.Cmdline) > 0 {
+		if current, err := getProcessCmdline(entry.PID); err == nil && slices.Equal(cmdlineIdentity(current, entry.Args), entry.Cmdline) {
+			return verifyConfirmedOwned
+		}
+		return verifyUncertain
+	}
+
+	// Legacy entries: loose cmdline matching
 	if matchesCmdline(entry.PID, entry.Command, entry.Args) {
 		return verifyConfirmedOwned
 	}
@@ -257,6 +297,20 @@ func (pt *PIDTracker) verifyProcessOwnership(entry pidEntry) verifyResult {
 	return verifyUncertain
 }
 
+// isOwnerAlive reports whether the mcpmu process that recorded entry is still
+// running. Without a recorded start time the owner's PID may have been
+// reused, so it counts as alive only if its start time matches.
+func isOwnerAlive(entry pidEntry) bool {
+	if !isProcessRunning(entry.OwnerPID) {
+		return false
+	}
+	if entry.OwnerStartTicks == 0 {
+		return false
+	}
+	ticks, err := getProcessStartTicks(entry.OwnerPID)
+	return err == nil && ticks == entry.OwnerStartTicks
+}
+
 // isProcessRunning checks if a process with the given PID exists.
 func isProcessRunning(pid int) bool {
 	// Signal 0 doesn't send a signal but checks if the process exists
@@ -395,6 +449,22 @@ func getProcessCmdlineDarwin(pid int) ([]string, error) {
 	return strings.Fields(line), nil
 }
 
+// cmdlineIdentity returns the form of a process's command line recorded in
+// pids.json: redacted, and with the arguments that were expanded from
+// ${ENV} references masked. Those are the trailing len(args) elements, after
+// the command and any interpreter a wrapper script added.
+func cmdlineIdentity(cmdline, args []string) []string {
+	out := slices.Clone(cmdline)
+	if offset := len(out) - len(args); offset >= 0 {
+		for i, arg := range args {
+			if strings.Contains(arg, "${") {
+				out[offset+i] = redact.Mask
+			}
+		}
+	}
+	return redact.Args(out)
+}
+
 // matchesCmdline checks if the process cmdline contains our expected command.
 // Uses tokenized matching: checks if any cmdline arg's basename matches the expected command basename,
 // or if any of our args appear in the cmdline.
@@ -432,7 +502,7 @@ func matchesCmdline(pid int, expectedCmd string, expectedArgs []string) bool {
 	}
 
 	log.Printf("PID %d cmdline mismatch: expected cmd=%q args=%v, actual=%v",
-		pid, expectedCmd, expectedArgs, actualCmdline)
+		pid, expectedCmd, redact.Args(expectedArgs), redact.Args(actualCmdline))
 	return false
 }
 
@@ -454,6 +524,33 @@ func killProcess(pid int) error {
 	return nil
 }
 
+// RunningServers reads every PID tracking file (pids.json and the per-mode
+// pids-*.json) in the given directories and returns the PID of each server
+// whose process is alive and still owned by a running mcpmu instance. Only
+// stdio servers have tracked processes, so remote servers never appear.
+func RunningServers(dirs ...string) map[string]int {
+	running := make(map[string]int)
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "pids*.json"))
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var entries map[string]pidEntry
+			if err := json.Unmarshal(data, &entries); err != nil {
+				continue
+			}
+			for serverID, entry := range entries {
+				if isProcessRunning(entry.PID) && isOwnerAlive(entry) {
+					running[serverID] = entry.PID
+				}
+			}
+		}
+	}
+	return running
+}
+
 // Legacy compatibility: Add with old signature (for existing callers)
 // Deprecated: Use AddWithArgs instead.
 func (pt *PIDTracker) AddLegacy(serverID string, pid int, command string) error {
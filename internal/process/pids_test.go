@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -104,6 +105,36 @@ func TestPIDTracker_CleanupOrphans_ProcessGone(t *testing.T) {
 	}
 }
 
+func TestRunningServers(t *testing.T) {
+	skipIfPsUnavailable(t)
+	dir := t.TempDir()
+
+	// This test process stands in for both a managed server and its owner
+	pt, err := NewPIDTrackerInDir(dir, "tui")
+	if err != nil {
+		t.Fatalf("NewPIDTrackerInDir failed: %v", err)
+	}
+	if err := pt.Add("live", os.Getpid(), os.Args[0], nil); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	pt.pids["gone"] = pidEntry{PID: 999999, OwnerPID: os.Getpid(), OwnerStartTicks: pt.ownerStartTicks}
+	pt.pids["orphan"] = pidEntry{PID: os.Getpid(), OwnerPID: 999999, OwnerStartTicks: 1}
+	if err := pt.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	running := RunningServers(dir, t.TempDir())
+	if pid, ok := running["live"]; !ok || pid != os.Getpid() {
+		t.Errorf("expected live server with PID %d, got %v", os.Getpid(), running)
+	}
+	if _, ok := running["gone"]; ok {
+		t.Error("expected exited process to be excluded")
+	}
+	if _, ok := running["orphan"]; ok {
+		t.Error("expected process without a live owner to be excluded")
+	}
+}
+
 func TestPIDTracker_CleanupOrphans_RetryCount(t *testing.T) {
 	skipIfPsUnavailable(t)
 	testutil.SetupTestHome(t)
@@ -217,6 +248,7 @@ func TestVerifyResult_Constants(t *testing.T) {
 		verifyConfirmedReused,
 		verifyProcessGone,
 		verifyUncertain,
+		verifyOwnerAlive,
 	}
 
 	seen := make(map[verifyResult]bool)
@@ -449,3 +481,189 @@ func TestPIDTracker_CleanupOrphans_MultiplePIDStates(t *testing.T) {
 		t.Errorf("expected RetryCount 1, got %d", entry.RetryCount)
 	}
 }
+
+// startUnrelatedProcess starts a long-running process that mcpmu didn't
+// launch, standing in for whatever reused a tracked PID. The returned channel
+// is closed when it exits.
+func startUnrelatedProcess(t *testing.T) (*exec.Cmd, <-chan struct{}) {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	})
+	return cmd, exited
+}
+
+// waitExited reports whether exited is closed within timeout.
+func waitExited(exited <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// TestPIDTracker_PIDReuse_SameProgramDifferentCmdline simulates a tracked
+// server whose start time couldn't be read dying and its PID being reused by
+// an unrelated process running the same program. The loose command match
+// alone would kill it; the recorded command line must not.
+func TestPIDTracker_PIDReuse_SameProgramDifferentCmdline(t *testing.T) {
+	skipIfPsUnavailable(t)
+	testutil.SetupTestHome(t)
+
+	pt, err := NewPIDTracker()
+	if err != nil {
+		t.Fatalf("NewPIDTracker failed: %v", err)
+	}
+
+	unrelated, exited := startUnrelatedProcess(t)
+	pt.pids["reused-server"] = pidEntry{
+		PID:       unrelated.Process.Pid,
+		Command:   "sleep",
+		Args:      []string{"999"},
+		Cmdline:   []string{"sleep", "999"},
+		StartedAt: time.Now().Add(-time.Hour),
+		OwnerPID:  999999, // Owner is gone
+		// No ProcessStartTicks - identity rests on the recorded cmdline
+	}
+
+	if killed := pt.CleanupOrphans(); killed != 0 {
+		t.Errorf("expected 0 killed, got %d", killed)
+	}
+	if waitExited(exited, 200*time.Millisecond) {
+		t.Fatal("unrelated process was killed")
+	}
+	if entry, ok := pt.pids["reused-server"]; !ok || entry.RetryCount != 1 {
+		t.Errorf("expected entry kept for retry, got %+v (ok=%v)", entry, ok)
+	}
+}
+
+// TestPIDTracker_CleanupOrphans_LiveOwner checks that a process started by
+// another running mcpmu instance sharing the PID file is left alone, while
+// the same process is reaped once its owner is gone.
+func TestPIDTracker_CleanupOrphans_LiveOwner(t *testing.T) {
+	skipIfPsUnavailable(t)
+	testutil.SetupTestHome(t)
+
+	pt, err := NewPIDTracker()
+	if err != nil {
+		t.Fatalf("NewPIDTracker failed: %v", err)
+	}
+
+	child, exited := startUnrelatedProcess(t)
+	childTicks, err := getProcessStartTicks(child.Process.Pid)
+	if err != nil {
+		t.Fatalf("getProcessStartTicks failed: %v", err)
+	}
+
+	// The owner is another live process: the sleep's "manager" is our parent
+	owner := os.Getppid()
+	ownerTicks, err := getProcessStartTicks(owner)
+	if err != nil {
+		t.Skipf("cannot read parent start ticks: %v", err)
+	}
+
+	entry := pidEntry{
+		PID:               child.Process.Pid,
+		Command:           "sleep",
+		Args:              []string{"30"},
+		StartedAt:         time.Now(),
+		ProcessStartTicks: childTicks,
+		OwnerPID:          owner,
+		OwnerStartTicks:   ownerTicks,
+	}
+	pt.pids["shared-server"] = entry
+
+	if killed := pt.CleanupOrphans(); killed != 0 {
+		t.Errorf("expected 0 killed with live owner, got %d", killed)
+	}
+	if _, ok := pt.pids["shared-server"]; !ok {
+		t.Error("entry of a live owner should stay tracked")
+	}
+
+	// Owner PID reused by a different process: it's an orphan now
+	entry.OwnerStartTicks = ownerTicks + 99999
+	pt.pids["shared-server"] = entry
+
+	if killed := pt.CleanupOrphans(); killed != 1 {
+		t.Errorf("expected 1 killed with dead owner, got %d", killed)
+	}
+	if !waitExited(exited, 2*time.Second) {
+		t.Error("orphan was not terminated")
+	}
+}
+
+func TestPIDTracker_Add_RecordsIdentity(t *testing.T) {
+	skipIfPsUnavailable(t)
+	testutil.SetupTestHome(t)
+
+	pt, err := NewPIDTracker()
+	if err != nil {
+		t.Fatalf("NewPIDTracker failed: %v", err)
+	}
+
+	child, _ := startUnrelatedProcess(t)
+	if err := pt.Add("srv", child.Process.Pid, "sleep", []string{"30"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	entry := pt.pids["srv"]
+	if entry.OwnerPID != os.Getpid() {
+		t.Errorf("OwnerPID = %d, want %d", entry.OwnerPID, os.Getpid())
+	}
+	if entry.OwnerStartTicks == 0 || entry.ProcessStartTicks == 0 {
+		t.Errorf("start ticks not recorded: %+v", entry)
+	}
+	if len(entry.Cmdline) != 2 || entry.Cmdline[1] != "30" {
+		t.Errorf("Cmdline = %v, want [sleep 30]", entry.Cmdline)
+	}
+}
+
+func TestPIDTracker_Add_RedactsExpandedArgs(t *testing.T) {
+	skipIfPsUnavailable(t)
+	testutil.SetupTestHome(t)
+
+	pt, err := NewPIDTracker()
+	if err != nil {
+		t.Fatalf("NewPIDTracker failed: %v", err)
+	}
+
+	cmd := exec.Command("sh", "-c", "sleep 30", "mcpmu-test", "s3cr3t-positional", "--token", "s3cr3t-flag")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sh: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	args := []string{"-c", "sleep 30", "mcpmu-test", "${API_SECRET}", "--token", "${TOKEN}"}
+	if err := pt.Add("srv", cmd.Process.Pid, "sh", args); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	data, err := os.ReadFile(pt.path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("pids.json holds an expanded secret: %s", data)
+	}
+
+	// The redacted command line still identifies the process
+	entry := pt.pids["srv"]
+	entry.ProcessStartTicks = 0
+	if got := pt.verifyProcessOwnership(entry); got != verifyConfirmedOwned {
+		t.Errorf("verifyProcessOwnership = %v, want verifyConfirmedOwned (Cmdline %v)", got, entry.Cmdline)
+	}
+}
@@ -0,0 +1,164 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/redact"
+)
+
+// RawConn is a connection to a single upstream server that skips the MCP
+// handshake and tool discovery, so callers can relay JSON-RPC traffic
+// verbatim. It reuses the supervisor's environment, PID tracking and
+// credential handling.
+type RawConn struct {
+	mcp.Transport
+
+	name string
+	cmd  *exec.Cmd
+	done chan struct{}
+	sup  *Supervisor
+
+	closeOnce sync.Once
+}
+
+// OpenRaw starts a stdio server process or connects to an HTTP server and
+// returns its transport without initializing an MCP client.
+func (s *Supervisor) OpenRaw(ctx context.Context, name string, srv config.ServerConfig) (*RawConn, error) {
+	if srv.IsHTTP() {
+		return s.openRawHTTP(ctx, name, srv)
+	}
+	return s.openRawStdio(name, srv)
+}
+
+func (s *Supervisor) openRawStdio(name string, srv config.ServerConfig) (*RawConn, error) {
+	log.Printf("Starting stdio server (raw): name=%s cmd=%s args=%v", name, srv.Command, redact.Args(srv.Args))
+
+	env := buildEnv(srv)
+	args, err := s.expandArgs(srv.Args, env)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(srv.Command, args...)
+	dir, tempDir, err := s.workDir(name, srv.Cwd)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = dir
+	cmd.Env = env
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		removeTempDir(tempDir)
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		removeTempDir(tempDir)
+		return nil, fmt.Errorf("stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		removeTempDir(tempDir)
+		return nil, fmt.Errorf("stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		removeTempDir(tempDir)
+		return nil, fmt.Errorf("start process: %w", err)
+	}
+
+	if s.pidTracker != nil {
+		if err := s.pidTracker.Add(name, cmd.Process.Pid, srv.Command, srv.Args); err != nil {
+			log.Printf("Warning: failed to track PID: %v", err)
+		}
+	}
+
+	conn := &RawConn{
+		Transport: mcp.NewStdioTransport(stdin, stdout),
+		name:      name,
+		cmd:       cmd,
+		done:      make(chan struct{}),
+		sup:       s,
+	}
+
+	// Upstream stderr goes to our log so it isn't lost (stdout is the protocol stream)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Printf("[%s] %s", name, scanner.Text())
+		}
+	}()
+
+	go func() {
+		_ = cmd.Wait()
+		removeTempDir(tempDir)
+		close(conn.done)
+	}()
+
+	return conn, nil
+}
+
+func (s *Supervisor) openRawHTTP(ctx context.Context, name string, srv config.ServerConfig) (*RawConn, error) {
+	log.Printf("Connecting to HTTP server (raw): name=%s url=%s", name, redact.URL(srv.URL))
+
+	transportConfig, authStatus, err := s.httpTransportConfig(ctx, name, srv)
+	if err != nil {
+		return nil, err
+	}
+	if authStatus == mcp.AuthStatusOAuthNeeds {
+		return nil, fmt.Errorf("server %s requires OAuth login (run: mcpmu mcp login %s)", name, name)
+	}
+
+	httpTransport := mcp.NewStreamableHTTPTransport(transportConfig)
+	if err := httpTransport.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connect HTTP transport: %w", err)
+	}
+
+	return &RawConn{
+		Transport: httpTransport,
+		name:      name,
+		sup:       s,
+	}, nil
+}
+
+// Done returns a channel that is closed when the upstream process exits.
+// For HTTP connections the channel is nil and never fires.
+func (c *RawConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the transport and, for stdio servers, stops the process
+// (SIGTERM, then SIGKILL after GracefulShutdownTimeout).
+func (c *RawConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.Transport.Close()
+		if c.cmd == nil || c.cmd.Process == nil {
+			return
+		}
+
+		_ = c.cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-c.done:
+		case <-time.After(GracefulShutdownTimeout):
+			_ = c.cmd.Process.Signal(syscall.SIGKILL)
+			<-c.done
+		}
+
+		if c.sup.pidTracker != nil {
+			if removeErr := c.sup.pidTracker.Remove(c.name); removeErr != nil {
+				log.Printf("Warning: failed to remove PID tracking: %v", removeErr)
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,65 @@
+package process
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/events"
+)
+
+// TrackedStatus is the latest state the supervisor reported for a server,
+// with the most recent error it saw. It outlives the server's handle, so a
+// server that failed to start still has one.
+type TrackedStatus struct {
+	State     events.RuntimeState
+	Since     time.Time
+	LastError string
+}
+
+// statusTracker records status changes and errors published on the bus.
+type statusTracker struct {
+	mu       sync.RWMutex
+	statuses map[string]TrackedStatus
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{statuses: make(map[string]TrackedStatus)}
+}
+
+// handle is the bus subscriber.
+func (t *statusTracker) handle(e events.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.statuses[e.ServerID()]
+	switch evt := e.(type) {
+	case events.StatusChangedEvent:
+		if evt.NewState != st.State {
+			st.State = evt.NewState
+			st.Since = evt.Timestamp()
+		}
+		if evt.Status.Error != "" {
+			st.LastError = evt.Status.Error
+		}
+	case events.ErrorEvent:
+		if evt.Warning || evt.Message == "" {
+			return
+		}
+		st.LastError = evt.Message
+	default:
+		return
+	}
+	t.statuses[e.ServerID()] = st
+}
+
+// Status returns the latest state and error the supervisor reported for a
+// server. ok is false if the server has never been started.
+func (s *Supervisor) Status(id string) (status TrackedStatus, ok bool) {
+	if s.statuses == nil {
+		return TrackedStatus{}, false
+	}
+	s.statuses.mu.RLock()
+	defer s.statuses.mu.RUnlock()
+	status, ok = s.statuses.statuses[id]
+	return status, ok
+}
@@ -14,6 +14,7 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -21,16 +22,18 @@ import (
 	"github.com/Bigsy/mcpmu/internal/events"
 	"github.com/Bigsy/mcpmu/internal/mcp"
 	"github.com/Bigsy/mcpmu/internal/oauth"
+	"github.com/Bigsy/mcpmu/internal/redact"
 )
 
 const (
 	// GracefulShutdownTimeout is how long to wait for SIGTERM before SIGKILL.
 	GracefulShutdownTimeout = 5 * time.Second
 
-	// MaxInitRetries is the maximum number of MCP initialization attempts.
+	// MaxInitRetries is the default maximum number of MCP initialization attempts.
 	MaxInitRetries = 3
 
-	// InitRetryBaseDelay is the base delay between retry attempts.
+	// InitRetryBaseDelay is the default base delay between retry attempts.
+	// The delay doubles after each failed attempt.
 	InitRetryBaseDelay = 500 * time.Millisecond
 )
 
@@ -43,12 +46,39 @@ type Supervisor struct {
 	tokenManager            *oauth.TokenManager
 	toolCache               *config.ToolCache
 	globalOAuthCallbackPort *int
+	maxLogLines             int
+	initRetries             int
+	initRetryBackoff        time.Duration
+	configDir               string
 	mu                      sync.RWMutex
 
 	// notificationSink receives upstream notifications. Set once via
 	// SetNotificationSink before any client is started; read under sinkMu.
 	sinkMu           sync.RWMutex
 	notificationSink mcp.NotificationSink
+
+	// clientCaps is declared by every new client at initialize; read under
+	// sinkMu.
+	clientCaps map[string]any
+
+	// logLevel is set with logging/setLevel on every new client whose
+	// server supports logging ("" = leave the server's default); read under
+	// sinkMu.
+	logLevel string
+
+	// traces holds the per-server JSON-RPC traces enabled with SetTrace,
+	// reinstalled on every client the server gets across restarts.
+	tracesMu sync.RWMutex
+	traces   map[string]*mcp.Trace
+
+	// sessionEnv holds per-server env values supplied at runtime (e.g. the
+	// TUI's promptOnStart answers). They override the config's env and are
+	// never persisted.
+	sessionEnvMu sync.RWMutex
+	sessionEnv   map[string]map[string]string
+
+	// statuses follows the status and error events published on the bus
+	statuses *statusTracker
 }
 
 // SetToolCache sets the tool cache for token counting.
@@ -56,6 +86,40 @@ func (s *Supervisor) SetToolCache(tc *config.ToolCache) {
 	s.toolCache = tc
 }
 
+// SetSessionEnv sets env values for a server's subprocess that live only as
+// long as this supervisor, applied on top of the config's env at every
+// start. Pass nil to clear them.
+func (s *Supervisor) SetSessionEnv(name string, env map[string]string) {
+	s.sessionEnvMu.Lock()
+	defer s.sessionEnvMu.Unlock()
+	if len(env) == 0 {
+		delete(s.sessionEnv, name)
+		return
+	}
+	if s.sessionEnv == nil {
+		s.sessionEnv = make(map[string]map[string]string)
+	}
+	s.sessionEnv[name] = maps.Clone(env)
+}
+
+// withSessionEnv returns srv with the server's session env merged into a
+// copy of its env.
+func (s *Supervisor) withSessionEnv(name string, srv config.ServerConfig) config.ServerConfig {
+	s.sessionEnvMu.RLock()
+	session := s.sessionEnv[name]
+	s.sessionEnvMu.RUnlock()
+	if len(session) == 0 {
+		return srv
+	}
+	env := maps.Clone(srv.Env)
+	if env == nil {
+		env = make(map[string]string, len(session))
+	}
+	maps.Copy(env, session)
+	srv.Env = env
+	return srv
+}
+
 // SetNotificationSink installs a sink that receives notifications from every
 // upstream client. Must be called before Start() so that all clients have the
 // handler wired immediately after Initialize.
@@ -66,7 +130,8 @@ func (s *Supervisor) SetNotificationSink(sink mcp.NotificationSink) {
 }
 
 // installNotificationHandler wires the sink (if any) into a client so that
-// upstream notifications are forwarded with the server name attached.
+// upstream notifications, and requests if the sink is also an
+// mcp.RequestSink, are forwarded with the server name attached.
 func (s *Supervisor) installNotificationHandler(name string, client *mcp.Client) {
 	s.sinkMu.RLock()
 	sink := s.notificationSink
@@ -77,6 +142,103 @@ func (s *Supervisor) installNotificationHandler(name string, client *mcp.Client)
 	client.SetNotificationHandler(func(method string, params json.RawMessage) {
 		sink.OnUpstreamNotification(name, method, params)
 	})
+	if reqSink, ok := sink.(mcp.RequestSink); ok {
+		client.SetRequestHandler(func(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+			return reqSink.OnUpstreamRequest(ctx, name, method, params)
+		})
+	}
+}
+
+// SetTrace starts capturing the JSON-RPC frames exchanged with a server into
+// trace, including on clients created by later restarts. Pass nil to stop.
+func (s *Supervisor) SetTrace(name string, trace *mcp.Trace) {
+	s.tracesMu.Lock()
+	if trace == nil {
+		delete(s.traces, name)
+	} else {
+		if s.traces == nil {
+			s.traces = make(map[string]*mcp.Trace)
+		}
+		s.traces[name] = trace
+	}
+	s.tracesMu.Unlock()
+
+	if h := s.Get(name); h != nil {
+		if client := h.Client(); client != nil {
+			client.SetTrace(trace)
+		}
+	}
+}
+
+// Trace returns the trace enabled for a server, or nil.
+func (s *Supervisor) Trace(name string) *mcp.Trace {
+	s.tracesMu.RLock()
+	defer s.tracesMu.RUnlock()
+	return s.traces[name]
+}
+
+// SetClientCapabilities sets the capabilities every client declares to its
+// server at initialize, so upstreams learn what the downstream client
+// supports. Only clients started afterwards are affected.
+func (s *Supervisor) SetClientCapabilities(caps map[string]any) {
+	s.sinkMu.Lock()
+	s.clientCaps = caps
+	s.sinkMu.Unlock()
+}
+
+// SetLogLevel sets the level of the log messages servers send, on running
+// servers that support logging and on those started later. Errors from
+// running servers are logged rather than returned.
+func (s *Supervisor) SetLogLevel(ctx context.Context, level string) {
+	s.sinkMu.Lock()
+	s.logLevel = level
+	s.sinkMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, name := range s.RunningServers() {
+		h := s.Get(name)
+		if h == nil || h.Client() == nil || h.Client().Capabilities().Logging == nil {
+			continue
+		}
+		client := h.Client()
+		wg.Go(func() {
+			if err := client.SetLogLevel(ctx, level); err != nil {
+				log.Printf("Failed to set log level on %s: %v", name, err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// applyLogLevel sets the level from SetLogLevel, if any, on a newly
+// initialized client whose server supports logging.
+func (s *Supervisor) applyLogLevel(name string, client *mcp.Client) {
+	s.sinkMu.RLock()
+	level := s.logLevel
+	s.sinkMu.RUnlock()
+	if level == "" || client.Capabilities().Logging == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mcp.DefaultTimeout)
+		defer cancel()
+		if err := client.SetLogLevel(ctx, level); err != nil {
+			log.Printf("Failed to set log level on %s: %v", name, err)
+		}
+	}()
+}
+
+// newClient creates a client for a server, with its trace (if any) installed
+// before the first frame is sent, its protocol version pinned if set and the
+// client capabilities from SetClientCapabilities declared.
+func (s *Supervisor) newClient(name string, srv config.ServerConfig, transport mcp.Transport) *mcp.Client {
+	client := mcp.NewClient(transport)
+	client.SetTrace(s.Trace(name))
+	client.PinProtocolVersion(srv.ProtocolVersion)
+	s.sinkMu.RLock()
+	client.SetClientCapabilities(s.clientCaps)
+	s.sinkMu.RUnlock()
+	return client
 }
 
 // SupervisorOptions configures a Supervisor.
@@ -86,9 +248,17 @@ type SupervisorOptions struct {
 	CredentialStoreMode string
 
 	// PIDTrackerDir overrides the directory used for the PID tracking file.
-	// If empty, the default ~/.config/mcpmu/ directory is used.
+	// If empty, config.Dir() is used.
 	PIDTrackerDir string
 
+	// CredentialEncryption protects the file credential store at rest:
+	// "none" (default), "keyring", or "passphrase".
+	CredentialEncryption string
+
+	// CredentialsDir is the directory for the file credential store. If
+	// empty, config.Dir() is used.
+	CredentialsDir string
+
 	// PIDFilePrefix scopes the PID tracking file to a specific manager mode.
 	// When set (e.g., "web", "tui"), the file becomes "pids-web.json" instead
 	// of "pids.json". This prevents different manager modes from interfering
@@ -98,8 +268,30 @@ type SupervisorOptions struct {
 	// GlobalOAuthCallbackPort is the global fallback OAuth callback port.
 	// Per-server oauth.callback_port takes precedence over this.
 	GlobalOAuthCallbackPort *int
+
+	// MaxLogLines is how many stderr lines each handle retains. Per-server
+	// maxLogLines takes precedence over this. Zero means DefaultMaxLogLines.
+	MaxLogLines int
+
+	// InitRetries is the maximum number of MCP initialization attempts for
+	// stdio servers. Per-server initRetries takes precedence over this. Zero
+	// means MaxInitRetries.
+	InitRetries int
+
+	// InitRetryBackoff is the base delay between initialization attempts,
+	// doubled after each failure. Per-server initRetryBackoffMs takes
+	// precedence over this. Zero means InitRetryBaseDelay.
+	InitRetryBackoff time.Duration
+
+	// ConfigDir is the directory substituted for {{config_dir}} in a
+	// server's cwd and args. If empty, the default config directory is used.
+	ConfigDir string
 }
 
+// DefaultMaxLogLines is the number of stderr lines a handle retains when
+// neither the supervisor nor the server config sets a limit.
+const DefaultMaxLogLines = 1000
+
 // NewSupervisor creates a new process supervisor.
 // It also cleans up any orphan processes from previous runs.
 func NewSupervisor(bus *events.Bus) *Supervisor {
@@ -131,7 +323,16 @@ func NewSupervisorWithOptions(bus *events.Bus, opts SupervisorOptions) *Supervis
 	}
 
 	// Create credential store for OAuth
-	credStore, err := oauth.NewCredentialStore(storeMode)
+	credDir := opts.CredentialsDir
+	if credDir == "" {
+		if dir, err := config.Dir(); err == nil {
+			credDir = dir
+		}
+	}
+	credStore, err := oauth.NewCredentialStoreWithOptions(storeMode, oauth.StoreOptions{
+		Dir:        credDir,
+		Encryption: oauth.Encryption(opts.CredentialEncryption),
+	})
 	if err != nil {
 		log.Printf("Warning: failed to create credential store: %v", err)
 	}
@@ -145,14 +346,50 @@ func NewSupervisorWithOptions(bus *events.Bus, opts SupervisorOptions) *Supervis
 		})
 	}
 
-	return &Supervisor{
+	s := &Supervisor{
 		bus:                     bus,
 		handles:                 make(map[string]*Handle),
 		pidTracker:              pidTracker,
 		credStore:               credStore,
 		tokenManager:            tokenManager,
 		globalOAuthCallbackPort: opts.GlobalOAuthCallbackPort,
+		maxLogLines:             opts.MaxLogLines,
+		initRetries:             opts.InitRetries,
+		initRetryBackoff:        opts.InitRetryBackoff,
+		configDir:               opts.ConfigDir,
+		statuses:                newStatusTracker(),
+	}
+	bus.Subscribe(s.statuses.handle)
+	return s
+}
+
+// logLimit returns how many stderr lines to retain for srv.
+func (s *Supervisor) logLimit(srv config.ServerConfig) int {
+	if srv.MaxLogLines > 0 {
+		return srv.MaxLogLines
 	}
+	if s.maxLogLines > 0 {
+		return s.maxLogLines
+	}
+	return DefaultMaxLogLines
+}
+
+// initRetryPolicy returns the number of MCP initialization attempts and the
+// base backoff delay for srv.
+func (s *Supervisor) initRetryPolicy(srv config.ServerConfig) (int, time.Duration) {
+	attempts := MaxInitRetries
+	if srv.InitRetries > 0 {
+		attempts = srv.InitRetries
+	} else if s.initRetries > 0 {
+		attempts = s.initRetries
+	}
+	backoff := InitRetryBaseDelay
+	if srv.InitRetryBackoffMs > 0 {
+		backoff = time.Duration(srv.InitRetryBackoffMs) * time.Millisecond
+	} else if s.initRetryBackoff > 0 {
+		backoff = s.initRetryBackoff
+	}
+	return attempts, backoff
 }
 
 // CredentialStore returns the OAuth credential store.
@@ -198,7 +435,7 @@ func (s *Supervisor) Start(ctx context.Context, name string, srv config.ServerCo
 
 // startStdio starts a stdio-based MCP server process.
 func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.ServerConfig) (*Handle, error) {
-	log.Printf("Starting stdio server: name=%s cmd=%s args=%v", name, srv.Command, srv.Args)
+	log.Printf("Starting stdio server: name=%s cmd=%s args=%v", name, srv.Command, redact.Args(srv.Args))
 
 	// Emit starting event
 	s.emitStatus(name, events.StateStarting, 0, nil, "")
@@ -207,37 +444,57 @@ func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.Ser
 	// managed by Handle.Stop() (SIGTERM → SIGKILL). Tying the process to
 	// a caller context would kill it when short-lived contexts (like the
 	// tools/list grace period) expire.
-	cmd := exec.Command(srv.Command, srv.Args...)
+	env := buildEnv(s.withSessionEnv(name, srv))
+	args, err := s.expandArgs(srv.Args, env)
+	if err != nil {
+		s.emitStatus(name, events.StateError, 0, nil, err.Error())
+		return nil, err
+	}
+	cmd := exec.Command(srv.Command, args...)
 
-	// Set working directory
-	if srv.Cwd != "" {
-		cmd.Dir = srv.Cwd
+	// Set working directory, expanding placeholders ({{tempdir}} etc.)
+	dir, tempDir, err := s.workDir(name, srv.Cwd)
+	if err != nil {
+		s.emitStatus(name, events.StateError, 0, nil, err.Error())
+		return nil, err
 	}
+	cmd.Dir = dir
 
 	// Set environment with PATH augmentation
-	cmd.Env = buildEnv(srv.Env)
+	cmd.Env = env
 
 	// Set up pipes
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
+		removeTempDir(tempDir)
 		s.emitStatus(name, events.StateError, 0, nil, err.Error())
 		return nil, fmt.Errorf("stdin pipe: %w", err)
 	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
+		removeTempDir(tempDir)
 		s.emitStatus(name, events.StateError, 0, nil, err.Error())
 		return nil, fmt.Errorf("stdout pipe: %w", err)
 	}
 
-	stderr, err := cmd.StderrPipe()
+	// Use our own pipe for stderr rather than cmd.StderrPipe: Wait closes the
+	// read end of the latter as soon as the process exits, dropping the last
+	// lines a crashing server prints, which are the ones worth reading.
+	stderr, stderrW, err := os.Pipe()
 	if err != nil {
+		removeTempDir(tempDir)
 		s.emitStatus(name, events.StateError, 0, nil, err.Error())
 		return nil, fmt.Errorf("stderr pipe: %w", err)
 	}
+	cmd.Stderr = stderrW
 
 	// Start the process
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	_ = stderrW.Close() // the child has its own copy
+	if err != nil {
+		_ = stderr.Close()
+		removeTempDir(tempDir)
 		s.emitStatus(name, events.StateError, 0, nil, err.Error())
 		return nil, fmt.Errorf("start process: %w", err)
 	}
@@ -251,7 +508,7 @@ func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.Ser
 
 	// Create transport and client
 	transport := mcp.NewStdioTransport(stdin, stdout)
-	client := mcp.NewClient(transport)
+	client := s.newClient(name, srv, transport)
 
 	// Create handle and register under lock
 	handleCtx, handleCancel := context.WithCancel(context.Background())
@@ -261,13 +518,15 @@ func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.Ser
 		ctx:            handleCtx,
 		ctxCancel:      handleCancel,
 		cmd:            cmd,
+		tempDir:        tempDir,
 		client:         client,
 		stdioTransport: transport,
-		logs:           make([]string, 0, 1000),
+		maxLogLines:    s.logLimit(srv),
 		toolsReady:     make(chan struct{}),
 		bus:            s.bus,
 		startedAt:      time.Now(),
 		done:           make(chan struct{}),
+		stderrDone:     make(chan struct{}),
 	}
 
 	s.mu.Lock()
@@ -285,7 +544,8 @@ func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.Ser
 	// Callers wait via handle.WaitForTools(), which blocks until init + discovery
 	// complete (or the caller's context expires). The process stays alive even if
 	// the caller's context expires — only handle.Stop() kills it.
-	go s.initAndDiscoverAsync(handle, client, name)
+	attempts, backoff := s.initRetryPolicy(srv)
+	go s.initAndDiscoverAsync(handle, client, name, attempts, backoff)
 
 	return handle, nil
 }
@@ -294,13 +554,13 @@ func (s *Supervisor) startStdio(ctx context.Context, name string, srv config.Ser
 // discovers tools. It signals handle.toolsReady when done (success or failure).
 // Uses handle.ctx so the init is not tied to any caller's short-lived context
 // (e.g. the tools/list grace period).
-func (s *Supervisor) initAndDiscoverAsync(handle *Handle, client *mcp.Client, name string) {
+func (s *Supervisor) initAndDiscoverAsync(handle *Handle, client *mcp.Client, name string, maxAttempts int, baseDelay time.Duration) {
 	defer handle.signalToolsReady()
 
 	// Initialize MCP connection with retry and exponential backoff
 	var initErr error
 initLoop:
-	for attempt := 1; attempt <= MaxInitRetries; attempt++ {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		initCtx, cancel := context.WithTimeout(handle.ctx, 30*time.Second)
 		initErr = client.Initialize(initCtx)
 		cancel()
@@ -309,11 +569,19 @@ initLoop:
 			break
 		}
 
-		log.Printf("MCP init attempt %d/%d failed: %v", attempt, MaxInitRetries, initErr)
+		log.Printf("MCP init attempt %d/%d failed: %v", attempt, maxAttempts, initErr)
+
+		// Don't retry what can't succeed, e.g. a server that rejects
+		// initialize as an invalid request
+		if mcp.IsPermanent(initErr) {
+			log.Printf("Not retrying: %s error", mcp.ClassifyError(initErr))
+			maxAttempts = attempt
+			break
+		}
 
-		if attempt < MaxInitRetries {
-			// Exponential backoff: 500ms, 1s, 2s... (context-aware)
-			delay := InitRetryBaseDelay * time.Duration(1<<(attempt-1))
+		if attempt < maxAttempts {
+			// Exponential backoff: 500ms, 1s, 2s... by default (context-aware)
+			delay := baseDelay * time.Duration(1<<(attempt-1))
 			log.Printf("Retrying in %v", delay)
 			select {
 			case <-handle.ctx.Done():
@@ -327,15 +595,22 @@ initLoop:
 	if initErr != nil {
 		handle.setInitError(initErr)
 		_ = handle.Stop()
-		s.emitStatus(name, events.StateError, handle.PID(), nil, fmt.Sprintf("MCP init failed after %d attempts: %v", MaxInitRetries, initErr))
+		msg := fmt.Sprintf("MCP init failed after %d attempts: %v", maxAttempts, initErr)
+		if hint := handle.startupFailure(); hint != "" {
+			msg = hint + "; " + msg
+		}
+		s.emitStatus(name, events.StateError, handle.PID(), nil, msg)
 		return
 	}
 
+	handle.markInitialized()
+
 	// Install notification handler now that initialization succeeded.
 	s.installNotificationHandler(name, client)
+	s.applyLogLevel(name, client)
 
 	// Emit running event
-	s.emitStatus(name, events.StateRunning, handle.PID(), nil, "")
+	s.emitRunning(name, handle.PID(), client)
 
 	// Discover tools
 	ctx, cancel := context.WithTimeout(handle.ctx, 30*time.Second)
@@ -343,103 +618,28 @@ initLoop:
 
 	tools, err := client.ListTools(ctx)
 	if err != nil {
+		handle.setToolsError(err)
 		s.bus.Publish(events.NewErrorEvent(name, err, "Failed to list tools"))
 		return
 	}
 
-	handle.SetTools(tools)
-
-	mcpTools := make([]events.McpTool, len(tools))
-	for i, t := range tools {
-		var schema json.RawMessage
-		if t.InputSchema != nil {
-			schema, _ = json.Marshal(t.InputSchema)
-		}
-		mcpTools[i] = events.McpTool{
-			Name:        t.Name,
-			Description: t.Description,
-			InputSchema: schema,
-		}
-	}
-	// Update tool cache before publishing event so TUI reads fresh data
-	if s.toolCache != nil {
-		cacheInputs := make([]config.CachedToolInput, len(mcpTools))
-		for i, t := range mcpTools {
-			cacheInputs[i] = config.CachedToolInput{
-				Name:        t.Name,
-				Description: t.Description,
-				InputSchema: t.InputSchema,
-			}
-		}
-		if err := s.toolCache.Update(name, cacheInputs); err != nil {
-			log.Printf("Warning: failed to update tool cache for %s: %v", name, err)
-		}
-	}
-
-	s.bus.Publish(events.NewToolsUpdatedEvent(name, mcpTools))
+	s.storeTools(name, handle, tools)
 }
 
 // startHTTP starts an HTTP-based MCP server connection.
 func (s *Supervisor) startHTTP(ctx context.Context, name string, srv config.ServerConfig) (*Handle, error) {
-	log.Printf("Starting HTTP server: name=%s url=%s", name, srv.URL)
+	log.Printf("Starting HTTP server: name=%s url=%s", name, redact.URL(srv.URL))
 
 	// Emit starting event
 	s.emitStatus(name, events.StateStarting, 0, nil, "")
 
-	// Determine authentication
-	var bearerToken string
-	var bearerTokenProvider func(context.Context) (string, error)
-	authStatus := mcp.AuthStatusNone
-
-	// Check bearer token first (highest priority)
-	if srv.BearerTokenEnvVar != "" {
-		token := os.Getenv(srv.BearerTokenEnvVar)
-		if token == "" {
-			err := fmt.Errorf("bearer token env var %s is not set", srv.BearerTokenEnvVar)
-			s.emitStatus(name, events.StateError, 0, nil, err.Error())
-			return nil, err
-		}
-		bearerToken = token
-		authStatus = mcp.AuthStatusBearer
-	} else if s.tokenManager != nil {
-		// Check for OAuth credentials
-		log.Printf("Looking up OAuth token for URL: %s", srv.URL)
-		token, err := s.tokenManager.GetAccessToken(ctx, srv.URL)
-		if err == nil && token != "" {
-			log.Printf("Found OAuth token for %s (len=%d)", name, len(token))
-			bearerToken = token
-			bearerTokenProvider = func(callCtx context.Context) (string, error) {
-				return s.tokenManager.GetAccessToken(callCtx, srv.URL)
-			}
-			authStatus = mcp.AuthStatusOAuthOK
-		} else {
-			log.Printf("No OAuth token found for %s: err=%v", name, err)
-			// Try to discover OAuth support
-			metadata, _ := oauth.SupportsOAuth(ctx, srv.URL)
-			if metadata != nil {
-				authStatus = mcp.AuthStatusOAuthNeeds
-				// Don't fail - server might work without auth, or user can login later
-				log.Printf("Server %s supports OAuth but needs login", name)
-			}
-		}
-	}
-
-	// Build HTTP headers
-	headers := make(map[string]string)
-	maps.Copy(headers, srv.HTTPHeaders)
-	for headerName, envVarName := range srv.EnvHTTPHeaders {
-		if value := os.Getenv(envVarName); value != "" {
-			headers[headerName] = value
-		}
+	transportConfig, authStatus, err := s.httpTransportConfig(ctx, name, srv)
+	if err != nil {
+		s.emitStatus(name, events.StateError, 0, nil, err.Error())
+		return nil, err
 	}
 
 	// Create HTTP transport
-	transportConfig := mcp.StreamableHTTPConfig{
-		URL:                 srv.URL,
-		BearerToken:         bearerToken,
-		BearerTokenProvider: bearerTokenProvider,
-		HTTPHeaders:         headers,
-	}
 	httpTransport := mcp.NewStreamableHTTPTransport(transportConfig)
 
 	// Connect SSE stream
@@ -453,7 +653,7 @@ func (s *Supervisor) startHTTP(ctx context.Context, name string, srv config.Serv
 	}
 
 	// Create client
-	client := mcp.NewClient(httpTransport)
+	client := s.newClient(name, srv, httpTransport)
 
 	// Create handle and register under lock
 	handleCtx, handleCancel := context.WithCancel(context.Background())
@@ -467,7 +667,7 @@ func (s *Supervisor) startHTTP(ctx context.Context, name string, srv config.Serv
 		authStatus:    authStatus,
 		serverURL:     srv.URL,
 		serverConfig:  srv,
-		logs:          make([]string, 0, 1000),
+		maxLogLines:   s.logLimit(srv),
 		toolsReady:    make(chan struct{}),
 		bus:           s.bus,
 		startedAt:     time.Now(),
@@ -526,11 +726,14 @@ func (s *Supervisor) startHTTP(ctx context.Context, name string, srv config.Serv
 		return nil, fmt.Errorf("initialize mcp: %w", err)
 	}
 
+	handle.markInitialized()
+
 	// Install notification handler now that initialization succeeded.
 	s.installNotificationHandler(name, client)
+	s.applyLogLevel(name, client)
 
 	// Emit running event immediately (tool discovery happens in background)
-	s.emitStatus(name, events.StateRunning, 0, nil, "")
+	s.emitRunning(name, 0, client)
 
 	// Discover tools in background (non-blocking)
 	go s.discoverToolsAsync(handle, client, name)
@@ -538,6 +741,69 @@ func (s *Supervisor) startHTTP(ctx context.Context, name string, srv config.Serv
 	return handle, nil
 }
 
+// httpTransportConfig resolves authentication (bearer token env var or stored
+// OAuth credentials) and headers for an HTTP server.
+func (s *Supervisor) httpTransportConfig(ctx context.Context, name string, srv config.ServerConfig) (mcp.StreamableHTTPConfig, mcp.AuthStatus, error) {
+	// Determine authentication
+	var bearerToken string
+	var bearerTokenProvider func(context.Context) (string, error)
+	authStatus := mcp.AuthStatusNone
+
+	// Check bearer token first (highest priority)
+	if srv.BearerTokenEnvVar != "" {
+		token, err := mcp.ValidateBearerTokenEnvVar(srv.BearerTokenEnvVar)
+		if err != nil {
+			return mcp.StreamableHTTPConfig{}, authStatus, err
+		}
+		bearerToken = token
+		authStatus = mcp.AuthStatusBearer
+	} else if s.tokenManager != nil {
+		// Check for OAuth credentials
+		log.Printf("Looking up OAuth token for URL: %s", redact.URL(srv.URL))
+		token, err := s.tokenManager.GetAccessToken(ctx, srv.URL)
+		if err == nil && token != "" {
+			log.Printf("Found OAuth token for %s (len=%d)", name, len(token))
+			bearerToken = token
+			bearerTokenProvider = func(callCtx context.Context) (string, error) {
+				return s.tokenManager.GetAccessToken(callCtx, srv.URL)
+			}
+			authStatus = mcp.AuthStatusOAuthOK
+		} else {
+			log.Printf("No OAuth token found for %s: err=%v", name, err)
+			// Try to discover OAuth support
+			metadata, _ := oauth.SupportsOAuth(ctx, srv.URL)
+			if metadata != nil {
+				authStatus = mcp.AuthStatusOAuthNeeds
+				// Don't fail - server might work without auth, or user can login later
+				log.Printf("Server %s supports OAuth but needs login", name)
+			}
+		}
+	}
+
+	// Build HTTP headers
+	headers := make(map[string]string)
+	maps.Copy(headers, srv.HTTPHeaders)
+	for headerName, envVarName := range srv.EnvHTTPHeaders {
+		if value := os.Getenv(envVarName); value != "" {
+			headers[headerName] = value
+		}
+	}
+
+	client, err := httpClient(name, srv)
+	if err != nil {
+		return mcp.StreamableHTTPConfig{}, authStatus, err
+	}
+
+	return mcp.StreamableHTTPConfig{
+		URL:                 srv.URL,
+		BearerToken:         bearerToken,
+		BearerTokenProvider: bearerTokenProvider,
+		HTTPHeaders:         headers,
+		Client:              client,
+		ProtocolVersion:     srv.ProtocolVersion,
+	}, authStatus, nil
+}
+
 // Stop stops a running MCP server process.
 func (s *Supervisor) Stop(id string) error {
 	s.mu.Lock()
@@ -567,6 +833,26 @@ func (s *Supervisor) Get(id string) *Handle {
 	return s.handles[id]
 }
 
+// RefreshOAuthToken forces a refresh of the OAuth access token an HTTP
+// server is using, after the server rejected it with a 401. rejected is the
+// token the server refused; if another call has already replaced it, the
+// stored token is kept. The transport reads the token afresh on each
+// request, so the next call uses the new one.
+func (s *Supervisor) RefreshOAuthToken(ctx context.Context, id, rejected string) error {
+	handle := s.Get(id)
+	if handle == nil {
+		return fmt.Errorf("server %s not found", id)
+	}
+	if handle.authStatus != mcp.AuthStatusOAuthOK || s.tokenManager == nil {
+		return fmt.Errorf("server %s is not using OAuth", id)
+	}
+	if _, err := s.tokenManager.RefreshAccessToken(ctx, handle.serverURL, rejected); err != nil {
+		return err
+	}
+	log.Printf("Refreshed OAuth token for %s", id)
+	return nil
+}
+
 // StopAll stops all running servers gracefully.
 // Logs any errors that occur during shutdown but does not return them,
 // as this is typically called during application shutdown where we want
@@ -624,11 +910,23 @@ func (s *Supervisor) emitStatus(id string, state events.RuntimeState, pid int, l
 		State:    state,
 		PID:      pid,
 		LastExit: lastExit,
-		Error:    errMsg,
+		Error:    redact.String(errMsg),
 	}
 	s.bus.Publish(events.NewStatusChangedEvent(id, events.StateIdle, state, status))
 }
 
+// emitRunning publishes the running status for a freshly initialized
+// server, including the protocol version it negotiated.
+func (s *Supervisor) emitRunning(id string, pid int, client *mcp.Client) {
+	status := events.ServerStatus{
+		ID:              id,
+		State:           events.StateRunning,
+		PID:             pid,
+		ProtocolVersion: client.ProtocolVersion(),
+	}
+	s.bus.Publish(events.NewStatusChangedEvent(id, events.StateIdle, events.StateRunning, status))
+}
+
 // discoverToolsAsync discovers tools from an already-initialized MCP server in the background.
 // Used by startHTTP (which does its own sync init) and retryHTTPConnection.
 func (s *Supervisor) discoverToolsAsync(handle *Handle, client *mcp.Client, name string) {
@@ -639,10 +937,17 @@ func (s *Supervisor) discoverToolsAsync(handle *Handle, client *mcp.Client, name
 
 	tools, err := client.ListTools(ctx)
 	if err != nil {
+		handle.setToolsError(err)
 		s.bus.Publish(events.NewErrorEvent(name, err, "Failed to list tools"))
 		return
 	}
 
+	s.storeTools(name, handle, tools)
+}
+
+// storeTools records a server's freshly listed tools on its handle and in
+// the tool cache, then publishes them.
+func (s *Supervisor) storeTools(name string, handle *Handle, tools []mcp.Tool) {
 	handle.SetTools(tools)
 
 	mcpTools := make([]events.McpTool, len(tools))
@@ -657,6 +962,8 @@ func (s *Supervisor) discoverToolsAsync(handle *Handle, client *mcp.Client, name
 			InputSchema: schema,
 		}
 	}
+
+	// Update tool cache before publishing event so TUI reads fresh data
 	if s.toolCache != nil {
 		cacheInputs := make([]config.CachedToolInput, len(mcpTools))
 		for i, t := range mcpTools {
@@ -672,34 +979,126 @@ func (s *Supervisor) discoverToolsAsync(handle *Handle, client *mcp.Client, name
 	}
 
 	s.bus.Publish(events.NewToolsUpdatedEvent(name, mcpTools))
+	s.warnIfNoTools(name, tools)
 }
 
-// buildEnv creates the environment for a subprocess with PATH augmentation.
-func buildEnv(customEnv map[string]string) []string {
-	// Start with current environment
-	env := os.Environ()
+// ErrNoTools is the warning published when a server initializes and lists
+// tools successfully but reports none, as opposed to discovery failing.
+var ErrNoTools = errors.New("server reported no tools")
 
-	// Augment PATH with common binary locations
-	pathDirs := []string{
-		"/opt/homebrew/bin",
-		"/usr/local/bin",
-		"/usr/bin",
-		"/bin",
+// warnIfNoTools logs and publishes a warning when a server's tool list is
+// empty, which usually means it is misconfigured (missing credentials or
+// toolsets) and would otherwise leave its namespaces silently empty.
+func (s *Supervisor) warnIfNoTools(name string, tools []mcp.Tool) {
+	if len(tools) > 0 {
+		return
+	}
+	log.Printf("Warning: server %s initialized but reported no tools", name)
+	s.bus.Publish(events.NewWarningEvent(name, ErrNoTools, fmt.Sprintf("Server %q initialized but reported no tools", name)))
+}
+
+// toolsRefreshRetryDelay is how long RefreshStaleTools leaves a server alone
+// after listing its tools again failed, so a broken server isn't asked (and
+// warned about) on every tools/list.
+const toolsRefreshRetryDelay = 30 * time.Second
+
+// RefreshStaleTools lists a running server's tools again if they are stale:
+// the server reported notifications/tools/list_changed, or they were listed
+// more than interval ago (0 = never by age). Concurrent calls for the same
+// server share one tools/list request. When it fails the previous tools are
+// kept and the server isn't asked again for toolsRefreshRetryDelay.
+func (s *Supervisor) RefreshStaleTools(ctx context.Context, id string, interval time.Duration) error {
+	handle := s.Get(id)
+	if handle == nil || !handle.IsRunning() || handle.client == nil {
+		return fmt.Errorf("server %s is not running", id)
+	}
+
+	handle.toolsMu.Lock()
+	if r := handle.toolsRefresh; r != nil {
+		handle.toolsMu.Unlock()
+		select {
+		case <-r.done:
+			return r.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if !handle.toolsStaleLocked(interval) {
+		handle.toolsMu.Unlock()
+		return nil
+	}
+	r := &toolsRefresh{done: make(chan struct{})}
+	handle.toolsRefresh = r
+	handle.toolsMu.Unlock()
+
+	tools, err := handle.client.ListTools(ctx)
+	if err == nil {
+		s.storeTools(id, handle, tools)
+	}
+
+	handle.toolsMu.Lock()
+	handle.toolsRefresh = nil
+	if err != nil {
+		handle.toolsRetryAt = time.Now().Add(toolsRefreshRetryDelay)
+	}
+	handle.toolsMu.Unlock()
+
+	r.err = err
+	close(r.done)
+	return err
+}
+
+// toolsRefresh is an in-flight RefreshStaleTools that concurrent callers for
+// the same server wait on instead of listing tools themselves.
+type toolsRefresh struct {
+	done chan struct{} // closed when the refresh finishes
+	err  error         // its result, set before done is closed
+}
+
+// pathDirs are common binary locations prepended to PATH for stdio servers,
+// so tools installed by Homebrew or into /usr/local resolve even when mcpmu
+// is launched from a GUI client with a minimal PATH.
+var pathDirs = []string{
+	"/opt/homebrew/bin",
+	"/usr/local/bin",
+	"/usr/bin",
+	"/bin",
+}
+
+// AugmentPath returns path with the common binary locations prepended, as
+// applied to every stdio server's environment.
+func AugmentPath(path string) string {
+	return strings.Join(pathDirs, ":") + ":" + path
+}
+
+// buildEnv creates the environment for a stdio server's subprocess with
+// PATH augmentation. It starts from mcpmu's own environment, or with
+// srv.CleanEnv from just PATH and the srv.PassEnv variables, then applies
+// srv.Env on top.
+func buildEnv(srv config.ServerConfig) []string {
+	var env []string
+	if srv.CleanEnv {
+		env = append(env, "PATH="+os.Getenv("PATH"))
+		for _, k := range srv.PassEnv {
+			if v, ok := os.LookupEnv(k); ok && k != "PATH" {
+				env = append(env, k+"="+v)
+			}
+		}
+	} else {
+		// Start with current environment
+		env = os.Environ()
 	}
 
 	// Find and update PATH
 	for i, e := range env {
 		if after, ok := strings.CutPrefix(e, "PATH="); ok {
-			currentPath := after
-			// Prepend additional paths
-			newPath := strings.Join(pathDirs, ":") + ":" + currentPath
-			env[i] = "PATH=" + newPath
+			env[i] = "PATH=" + AugmentPath(after)
 			break
 		}
 	}
 
 	// Add custom environment variables
-	for k, v := range customEnv {
+	for k, v := range srv.Env {
 		found := false
 		prefix := k + "="
 		for i, e := range env {
@@ -733,6 +1132,7 @@ type Handle struct {
 	// Stdio-specific fields
 	cmd            *exec.Cmd
 	stdioTransport *mcp.StdioTransport
+	tempDir        string // per-start {{tempdir}} cwd, removed on exit
 
 	// HTTP-specific fields
 	httpTransport *mcp.StreamableHTTPTransport
@@ -743,22 +1143,32 @@ type Handle struct {
 	authChallenge *oauth.BearerChallenge             // Cached WWW-Authenticate challenge
 
 	// Common fields
-	ctx          context.Context    // cancelled when server stops
-	ctxCancel    context.CancelFunc // cancels ctx
-	client       *mcp.Client
-	tools        []mcp.Tool
-	toolsMu      sync.RWMutex
-	toolsReady   chan struct{} // closed when init + tool discovery complete
-	toolsReadyMu sync.Mutex    // protects toolsReady close
-	initErr      error         // set if MCP init fails (checked by WaitForTools)
-	initErrMu    sync.Mutex
-	logs         []string
-	logsMu       sync.RWMutex
-	bus          *events.Bus
-	startedAt    time.Time
-	stopped      bool
-	stopMu       sync.Mutex
-	done         chan struct{} // closed when server stops
+	ctx           context.Context    // cancelled when server stops
+	ctxCancel     context.CancelFunc // cancels ctx
+	client        *mcp.Client
+	tools         []mcp.Tool
+	toolsListedAt time.Time     // when tools was last set
+	toolsStale    bool          // server reported tools/list_changed since
+	toolsRetryAt  time.Time     // a failed refresh holds off the next until then
+	toolsRefresh  *toolsRefresh // in-flight RefreshStaleTools (guarded by toolsMu)
+	toolsMu       sync.RWMutex
+	toolsReady    chan struct{} // closed when init + tool discovery complete
+	toolsReadyMu  sync.Mutex    // protects toolsReady close
+	initErr       error         // set if MCP init fails (checked by WaitForTools)
+	toolsErr      error         // set if tools/list fails after a successful init
+	exitHint      string        // diagnosed port/resource conflict after an early exit
+	initErrMu     sync.Mutex    // protects initErr, toolsErr and exitHint
+	logs          []string
+	logsMu        sync.RWMutex
+	maxLogLines   int // stderr lines retained in logs
+	bus           *events.Bus
+	startedAt     time.Time
+	initializedAt atomic.Int64 // UnixNano when MCP initialization succeeded (0 = not yet)
+	lastActivity  atomic.Int64 // UnixNano of the last Touch (0 = none)
+	stopped       bool
+	stopMu        sync.Mutex
+	done          chan struct{} // closed when server stops
+	stderrDone    chan struct{} // closed when stderr reaches EOF (stdio only)
 }
 
 // ID returns the server ID.
@@ -771,6 +1181,15 @@ func (h *Handle) Client() *mcp.Client {
 	return h.client
 }
 
+// ProtocolVersion returns the MCP protocol version negotiated with the
+// upstream server, or "" if the handle has no client yet.
+func (h *Handle) ProtocolVersion() string {
+	if h.client == nil {
+		return ""
+	}
+	return h.client.ProtocolVersion()
+}
+
 // Capabilities returns the capabilities advertised by the upstream server at
 // initialize time. Returns the zero value if the handle has no client yet
 // (e.g., before initialization completes or for needs-auth HTTP handles).
@@ -793,6 +1212,28 @@ func (h *Handle) SetTools(tools []mcp.Tool) {
 	h.toolsMu.Lock()
 	defer h.toolsMu.Unlock()
 	h.tools = tools
+	h.toolsListedAt = time.Now()
+	h.toolsStale = false
+}
+
+// InvalidateTools marks the discovered tools as out of date, so
+// RefreshStaleTools lists them again.
+func (h *Handle) InvalidateTools() {
+	h.toolsMu.Lock()
+	defer h.toolsMu.Unlock()
+	h.toolsStale = true
+}
+
+// toolsStaleLocked reports whether the discovered tools should be listed
+// again: the server has invalidated them, or they are older than interval
+// (0 = never). Tools that were never listed are not stale, since discovery
+// is still pending, and neither are tools whose last refresh failed less
+// than toolsRefreshRetryDelay ago. Callers hold toolsMu.
+func (h *Handle) toolsStaleLocked(interval time.Duration) bool {
+	if h.toolsListedAt.IsZero() || time.Now().Before(h.toolsRetryAt) {
+		return false
+	}
+	return h.toolsStale || (interval > 0 && time.Since(h.toolsListedAt) > interval)
 }
 
 // signalToolsReady signals that tool discovery is complete.
@@ -838,6 +1279,14 @@ func (h *Handle) setInitError(err error) {
 	h.initErr = err
 }
 
+// startupFailure returns the diagnosed cause of an exit during startup, if
+// stderr matched a known port or resource conflict.
+func (h *Handle) startupFailure() string {
+	h.initErrMu.Lock()
+	defer h.initErrMu.Unlock()
+	return h.exitHint
+}
+
 // InitError returns the MCP initialization error, if any.
 func (h *Handle) InitError() error {
 	h.initErrMu.Lock()
@@ -845,6 +1294,21 @@ func (h *Handle) InitError() error {
 	return h.initErr
 }
 
+// setToolsError records a failed tools/list after initialization.
+func (h *Handle) setToolsError(err error) {
+	h.initErrMu.Lock()
+	defer h.initErrMu.Unlock()
+	h.toolsErr = err
+}
+
+// ToolsError returns the error from tool discovery, if tools/list failed.
+// The server stays running with no tools; WaitForTools does not report it.
+func (h *Handle) ToolsError() error {
+	h.initErrMu.Lock()
+	defer h.initErrMu.Unlock()
+	return h.toolsErr
+}
+
 // Logs returns the captured stderr logs.
 func (h *Handle) Logs() []string {
 	h.logsMu.RLock()
@@ -882,6 +1346,36 @@ func (h *Handle) StartedAt() time.Time {
 	return h.startedAt
 }
 
+// markInitialized records that MCP initialization has just succeeded.
+func (h *Handle) markInitialized() {
+	h.initializedAt.Store(time.Now().UnixNano())
+}
+
+// InitializedAt returns when MCP initialization succeeded, or the zero time
+// if it hasn't (yet).
+func (h *Handle) InitializedAt() time.Time {
+	ns := h.initializedAt.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// Touch records activity on the server, resetting its idle time.
+func (h *Handle) Touch() {
+	h.lastActivity.Store(time.Now().UnixNano())
+}
+
+// IdleFor returns how long it has been since the server last saw activity
+// (a Touch), or since it started if it has had none.
+func (h *Handle) IdleFor() time.Duration {
+	last := h.lastActivity.Load()
+	if last == 0 {
+		return time.Since(h.startedAt)
+	}
+	return time.Since(time.Unix(0, last))
+}
+
 // Uptime returns how long the process has been running.
 func (h *Handle) Uptime() time.Duration {
 	return time.Since(h.startedAt)
@@ -966,15 +1460,18 @@ func (h *Handle) Stop() error {
 
 // readStderr reads stderr and publishes log events.
 func (h *Handle) readStderr(stderr io.ReadCloser) {
+	defer close(h.stderrDone)
+	defer func() { _ = stderr.Close() }()
+
 	scanner := bufio.NewScanner(stderr)
 	for scanner.Scan() {
 		line := scanner.Text()
 
 		h.logsMu.Lock()
 		h.logs = append(h.logs, line)
-		// Keep only last 1000 lines
-		if len(h.logs) > 1000 {
-			h.logs = h.logs[len(h.logs)-1000:]
+		// Keep only the last maxLogLines lines
+		if len(h.logs) > h.maxLogLines {
+			h.logs = h.logs[len(h.logs)-h.maxLogLines:]
 		}
 		h.logsMu.Unlock()
 
@@ -986,6 +1483,25 @@ func (h *Handle) readStderr(stderr io.ReadCloser) {
 func (h *Handle) watchProcess() {
 	err := h.cmd.Wait()
 
+	// An exit during startup is often a port or lock clash with another
+	// server; look for one in the final stderr lines before signalling, so
+	// the hint is in place by the time Stop returns.
+	if time.Since(h.startedAt) < fastExitWindow {
+		select {
+		case <-h.stderrDone:
+		case <-time.After(stderrDrainTimeout):
+		}
+		if hint := diagnoseStartupFailure(h.Logs()); hint != "" {
+			h.initErrMu.Lock()
+			h.exitHint = hint
+			h.initErrMu.Unlock()
+		}
+	}
+
+	// Remove the per-start temp dir before signalling, so it is gone by the
+	// time Stop returns
+	removeTempDir(h.tempDir)
+
 	// Signal that process has exited
 	close(h.done)
 
@@ -1020,10 +1536,19 @@ func (h *Handle) watchProcess() {
 		newState = events.StateStopped
 	}
 
+	var errMsg string
+	if !wasStopped {
+		if hint := h.startupFailure(); hint != "" {
+			errMsg = hint
+			h.bus.Publish(events.NewErrorEvent(h.id, errors.New(hint), "Server exited during startup"))
+		}
+	}
+
 	h.bus.Publish(events.NewStatusChangedEvent(h.id, events.StateRunning, newState, events.ServerStatus{
 		ID:       h.id,
 		State:    newState,
 		LastExit: lastExit,
+		Error:    errMsg,
 	}))
 }
 
@@ -1144,6 +1669,12 @@ func (s *Supervisor) retryHTTPConnection(ctx context.Context, name string) error
 		}
 	}
 
+	transportClient, err := httpClient(name, cfg)
+	if err != nil {
+		s.emitStatus(name, events.StateError, 0, nil, err.Error())
+		return err
+	}
+
 	// Create transport with token
 	transportConfig := mcp.StreamableHTTPConfig{
 		URL:         handle.serverURL,
@@ -1151,7 +1682,9 @@ func (s *Supervisor) retryHTTPConnection(ctx context.Context, name string) error
 		BearerTokenProvider: func(callCtx context.Context) (string, error) {
 			return s.tokenManager.GetAccessToken(callCtx, handle.serverURL)
 		},
-		HTTPHeaders: headers,
+		HTTPHeaders:     headers,
+		Client:          transportClient,
+		ProtocolVersion: cfg.ProtocolVersion,
 	}
 	httpTransport := mcp.NewStreamableHTTPTransport(transportConfig)
 
@@ -1162,7 +1695,7 @@ func (s *Supervisor) retryHTTPConnection(ctx context.Context, name string) error
 	}
 
 	// Create client and initialize
-	client := mcp.NewClient(httpTransport)
+	client := s.newClient(name, cfg, httpTransport)
 
 	initCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -1175,6 +1708,7 @@ func (s *Supervisor) retryHTTPConnection(ctx context.Context, name string) error
 
 	// Install notification handler now that initialization succeeded.
 	s.installNotificationHandler(name, client)
+	s.applyLogLevel(name, client)
 
 	// Update handle
 	handle.ctx, handle.ctxCancel = context.WithCancel(context.Background())
@@ -1184,7 +1718,7 @@ func (s *Supervisor) retryHTTPConnection(ctx context.Context, name string) error
 	handle.done = make(chan struct{}) // Reset done channel
 	handle.startedAt = time.Now()
 
-	s.emitStatus(name, events.StateRunning, 0, nil, "")
+	s.emitRunning(name, 0, client)
 
 	// Discover tools in background
 	go s.discoverToolsAsync(handle, client, name)
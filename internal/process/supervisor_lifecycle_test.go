@@ -5,7 +5,11 @@ package process_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -250,6 +254,154 @@ func TestSupervisor_Lifecycle(t *testing.T) {
 	}
 }
 
+func TestSupervisor_InitRetries(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	tests := []struct {
+		name          string
+		failFirst     int
+		serverRetries int
+		optsRetries   int
+		permanent     bool
+		wantAttempts  int
+		wantErr       bool
+	}{
+		{"server config allows more attempts", 3, 4, 0, false, 4, false},
+		{"server config fails fast", 2, 2, 0, false, 2, true},
+		{"supervisor option", 4, 0, 5, false, 5, false},
+		{"server config overrides supervisor option", 1, 1, 5, false, 1, true},
+		{"default", 3, 0, 0, false, process.MaxInitRetries, true},
+		{"permanent error is not retried", 0, 4, 0, true, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := events.NewBus()
+			defer bus.Close()
+
+			supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+				InitRetries:      tt.optsRetries,
+				InitRetryBackoff: 10 * time.Millisecond,
+			})
+			defer supervisor.StopAll()
+
+			requestLog := filepath.Join(t.TempDir(), "requests.log")
+			fakeCfg := mcptest.FailFirstNConfig("initialize", tt.failFirst)
+			if tt.permanent {
+				fakeCfg = mcptest.ErrorOnInitConfig(-32601, "Method not found")
+			}
+			fakeCfg.RequestLogPath = requestLog
+			srvCfg := fakeServerConfig(t, "retry", fakeCfg)
+			srvCfg.InitRetries = tt.serverRetries
+
+			handle, err := supervisor.Start(context.Background(), "retry", srvCfg)
+			if err != nil {
+				t.Fatalf("Start() failed: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			waitErr := handle.WaitForTools(ctx)
+			if tt.wantErr != (waitErr != nil) {
+				t.Fatalf("WaitForTools() error = %v, wantErr %v", waitErr, tt.wantErr)
+			}
+
+			data, err := os.ReadFile(requestLog)
+			if err != nil {
+				t.Fatalf("read request log: %v", err)
+			}
+			if got := strings.Count(string(data), "initialize\n"); got != tt.wantAttempts {
+				t.Errorf("initialize attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestSupervisor_TempDirCwd(t *testing.T) {
+	testutil.SetupTestHome(t)
+	tmpRoot := t.TempDir()
+	t.Setenv("TMPDIR", tmpRoot)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	supervisor := process.NewSupervisor(bus)
+	defer supervisor.StopAll()
+
+	// A relative request log lands in the server's working directory
+	fakeCfg := mcptest.DefaultConfig()
+	fakeCfg.RequestLogPath = "requests.log"
+	srvCfg := fakeServerConfig(t, "sandbox", fakeCfg)
+	srvCfg.Cwd = "{{tempdir}}"
+
+	handle, err := supervisor.Start(context.Background(), "sandbox", srvCfg)
+	if err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := handle.WaitForTools(ctx); err != nil {
+		t.Fatalf("WaitForTools() failed: %v", err)
+	}
+
+	dirs, _ := filepath.Glob(filepath.Join(tmpRoot, "mcpmu-sandbox-*"))
+	if len(dirs) != 1 {
+		t.Fatalf("expected one temp dir for the server, got %v", dirs)
+	}
+	if _, err := os.Stat(filepath.Join(dirs[0], "requests.log")); err != nil {
+		t.Errorf("expected the temp dir to be the process cwd: %v", err)
+	}
+
+	if err := supervisor.Stop("sandbox"); err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if _, err := os.Stat(dirs[0]); !os.IsNotExist(err) {
+		t.Errorf("expected temp dir to be removed after Stop, stat err = %v", err)
+	}
+}
+
+func TestSupervisor_ArgsPlaceholders(t *testing.T) {
+	home := testutil.SetupTestHome(t)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{ConfigDir: "/etc/mcpmu"})
+	defer supervisor.StopAll()
+
+	fakeCfg := mcptest.DefaultConfig()
+	fakeCfg.StderrArgs = true
+	srvCfg := fakeServerConfig(t, "templated", fakeCfg)
+	srvCfg.Env["DATA_ROOT"] = "/srv/data"
+	srvCfg.Args = append(srvCfg.Args, "{{config_dir}}/fs", "--home={{home}}", "${DATA_ROOT}/cache", "literal")
+
+	handle, err := supervisor.Start(context.Background(), "templated", srvCfg)
+	if err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := handle.WaitForTools(ctx); err != nil {
+		t.Fatalf("WaitForTools() failed: %v", err)
+	}
+
+	want := []string{"ARG=/etc/mcpmu/fs", "ARG=--home=" + home, "ARG=/srv/data/cache", "ARG=literal"}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var got []string
+		for _, line := range handle.Logs() {
+			if strings.HasPrefix(line, "ARG=") {
+				got = append(got, line)
+			}
+		}
+		if slices.Equal(got, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("child args = %q, want %q", got, want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
 func TestSupervisor_CrashMidSession(t *testing.T) {
 	testutil.SetupTestHome(t)
 
@@ -285,6 +437,156 @@ func TestSupervisor_CrashMidSession(t *testing.T) {
 	t.Logf("observed states: %v", states)
 }
 
+func TestSupervisor_StartupPortConflict(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	collector := testutil.NewEventCollector()
+	bus.Subscribe(collector.Handler)
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+		InitRetries:      1,
+		InitRetryBackoff: 10 * time.Millisecond,
+	})
+	defer supervisor.StopAll()
+
+	// The server logs a bind failure and exits as soon as it's initialized
+	fakeCfg := mcptest.CrashOnInitConfig(1)
+	fakeCfg.StartupStderr = []string{"listen tcp 127.0.0.1:8080: bind: address already in use"}
+	srvCfg := fakeServerConfig(t, "clash", fakeCfg)
+
+	if _, err := supervisor.Start(context.Background(), "clash", srvCfg); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if !collector.WaitForState("clash", events.StateError, 10*time.Second) {
+		t.Fatalf("server never reached error state, states: %v", collector.StatesFor("clash"))
+	}
+
+	var errMsg string
+	for _, e := range collector.Events() {
+		if sc, ok := e.(events.StatusChangedEvent); ok && sc.ServerID() == "clash" && sc.NewState == events.StateError {
+			errMsg = sc.Status.Error
+		}
+	}
+	if !strings.Contains(errMsg, "port conflict") {
+		t.Errorf("error status = %q, want a port conflict diagnosis", errMsg)
+	}
+	if !strings.Contains(errMsg, "address already in use") {
+		t.Errorf("error status = %q, want it to quote the stderr line", errMsg)
+	}
+}
+
+func TestSupervisor_ZeroToolsWarning(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	collector := testutil.NewEventCollector()
+	bus.Subscribe(collector.Handler)
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{})
+	defer supervisor.StopAll()
+
+	// "empty" initializes and lists tools fine but has none; "broken" fails
+	// tool discovery outright.
+	empty := fakeServerConfig(t, "empty", mcptest.FakeServerConfig{})
+	broken := fakeServerConfig(t, "broken", mcptest.FakeServerConfig{
+		Errors: map[string]mcptest.JSONRPCError{"tools/list": {Code: -32603, Message: "boom"}},
+	})
+	for name, cfg := range map[string]config.ServerConfig{"empty": empty, "broken": broken} {
+		if _, err := supervisor.Start(context.Background(), name, cfg); err != nil {
+			t.Fatalf("Start(%s) failed: %v", name, err)
+		}
+	}
+
+	errorEvent := func(server string) (events.ErrorEvent, bool) {
+		for _, e := range collector.Events() {
+			if ee, ok := e.(events.ErrorEvent); ok && ee.ServerID() == server {
+				return ee, true
+			}
+		}
+		return events.ErrorEvent{}, false
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		_, gotEmpty := errorEvent("empty")
+		_, gotBroken := errorEvent("broken")
+		if gotEmpty && gotBroken {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	warning, ok := errorEvent("empty")
+	if !ok {
+		t.Fatal("expected a zero-tools warning for the empty server")
+	}
+	if !warning.Warning || !errors.Is(warning.Err, process.ErrNoTools) {
+		t.Errorf("empty server event = %+v, want a warning wrapping ErrNoTools", warning)
+	}
+	if !strings.Contains(warning.Message, "reported no tools") {
+		t.Errorf("warning message = %q", warning.Message)
+	}
+
+	failure, ok := errorEvent("broken")
+	if !ok {
+		t.Fatal("expected a discovery error for the broken server")
+	}
+	if failure.Warning || errors.Is(failure.Err, process.ErrNoTools) {
+		t.Errorf("broken server event = %+v, want a plain discovery error", failure)
+	}
+}
+
+// TestSupervisor_RefreshStaleToolsIsShared: concurrent refreshes of a
+// server's invalidated tools share one upstream tools/list.
+func TestSupervisor_RefreshStaleToolsIsShared(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{})
+	defer supervisor.StopAll()
+
+	logPath := filepath.Join(t.TempDir(), "requests.log")
+	srv := fakeServerConfig(t, "slow", mcptest.FakeServerConfig{
+		Tools:          []mcptest.Tool{{Name: "work"}},
+		Delays:         map[string]time.Duration{"tools/list": 200 * time.Millisecond},
+		RequestLogPath: logPath,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	handle, err := supervisor.Start(ctx, "slow", srv)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := handle.WaitForTools(ctx); err != nil {
+		t.Fatalf("WaitForTools failed: %v", err)
+	}
+
+	handle.InvalidateTools()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if err := supervisor.RefreshStaleTools(ctx, "slow", 0); err != nil {
+				t.Errorf("RefreshStaleTools failed: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read request log: %v", err)
+	}
+	if n := strings.Count(string(data), "tools/list\n"); n != 2 {
+		t.Errorf("expected discovery plus one shared refresh, got %d tools/list:\n%s", n, data)
+	}
+}
+
 func TestSupervisor_ConcurrentStartStop(t *testing.T) {
 	testutil.SetupTestHome(t)
 
@@ -494,3 +796,33 @@ func TestStatesContainSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestSupervisor_AutostartWaitsForEveryServer(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	bus := events.NewBus()
+	defer bus.Close()
+
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{})
+	defer supervisor.StopAll()
+
+	var entries []config.ServerEntry
+	for _, name := range []string{"a", "b", "c"} {
+		entries = append(entries, config.ServerEntry{Name: name, Config: fakeServerConfig(t, name, mcptest.DefaultConfig())})
+	}
+	entries = append(entries, config.ServerEntry{Name: "bad", Config: config.ServerConfig{Command: "/nonexistent/mcp-server"}})
+
+	supervisor.Autostart(context.Background(), entries, 2)
+
+	// Autostart returns once every start has finished, failed ones included
+	running := supervisor.RunningServers()
+	slices.Sort(running)
+	if !slices.Equal(running, []string{"a", "b", "c"}) {
+		t.Errorf("running after Autostart = %v, want [a b c]", running)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if h := supervisor.Get(name); h == nil || len(h.Tools()) == 0 {
+			t.Errorf("%s: expected tools discovered by the time Autostart returns", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package process
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// Placeholders expanded in a server's cwd.
+const (
+	cwdTempDir   = "{{tempdir}}"
+	cwdHome      = "{{home}}"
+	cwdConfigDir = "{{config_dir}}"
+)
+
+// workDir expands the placeholders in a server's cwd. {{tempdir}} becomes a
+// fresh directory created for this start, returned as tempDir so the caller
+// can remove it once the process exits; {{home}} and {{config_dir}} become
+// the user's home directory and the directory holding the config file.
+func (s *Supervisor) workDir(name, cwd string) (dir, tempDir string, err error) {
+	if !strings.Contains(cwd, "{{") {
+		return cwd, "", nil
+	}
+
+	cwd, err = s.expandPathPlaceholders(cwd)
+	if err != nil {
+		return "", "", err
+	}
+
+	if strings.Contains(cwd, cwdTempDir) {
+		tempDir, err = os.MkdirTemp("", "mcpmu-"+name+"-")
+		if err != nil {
+			return "", "", fmt.Errorf("create temp dir: %w", err)
+		}
+		cwd = strings.ReplaceAll(cwd, cwdTempDir, tempDir)
+		if err := os.MkdirAll(cwd, 0700); err != nil {
+			removeTempDir(tempDir)
+			return "", "", fmt.Errorf("create working dir: %w", err)
+		}
+	}
+
+	return cwd, tempDir, nil
+}
+
+// expandPathPlaceholders replaces {{home}} and {{config_dir}} in value with
+// the user's home directory and the directory holding the config file.
+func (s *Supervisor) expandPathPlaceholders(value string) (string, error) {
+	if strings.Contains(value, cwdHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand %s: %w", cwdHome, err)
+		}
+		value = strings.ReplaceAll(value, cwdHome, home)
+	}
+
+	if strings.Contains(value, cwdConfigDir) {
+		configDir := s.configDir
+		if configDir == "" {
+			path, err := config.ConfigPath()
+			if err != nil {
+				return "", fmt.Errorf("expand %s: %w", cwdConfigDir, err)
+			}
+			configDir = filepath.Dir(path)
+		}
+		value = strings.ReplaceAll(value, cwdConfigDir, configDir)
+	}
+
+	return value, nil
+}
+
+// removeTempDir deletes a per-start temp directory created by workDir.
+func removeTempDir(dir string) {
+	if dir == "" {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		log.Printf("Warning: failed to remove temp dir %s: %v", dir, err)
+	}
+}
@@ -0,0 +1,49 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSupervisor_WorkDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+	s := &Supervisor{configDir: "/etc/mcpmu"}
+
+	tests := []struct {
+		cwd  string
+		want string
+	}{
+		{"", ""},
+		{"/srv/data", "/srv/data"},
+		{"{{home}}/projects", home + "/projects"},
+		{"{{config_dir}}/servers/fs", "/etc/mcpmu/servers/fs"},
+	}
+	for _, tt := range tests {
+		dir, tempDir, err := s.workDir("fs", tt.cwd)
+		if err != nil {
+			t.Errorf("workDir(%q) error: %v", tt.cwd, err)
+			continue
+		}
+		if dir != tt.want || tempDir != "" {
+			t.Errorf("workDir(%q) = %q, %q; want %q, no temp dir", tt.cwd, dir, tempDir, tt.want)
+		}
+	}
+
+	dir, tempDir, err := s.workDir("fs", "{{tempdir}}/root")
+	if err != nil {
+		t.Fatalf("workDir({{tempdir}}) error: %v", err)
+	}
+	defer removeTempDir(tempDir)
+	if !strings.HasPrefix(filepath.Base(tempDir), "mcpmu-fs-") || dir != filepath.Join(tempDir, "root") {
+		t.Errorf("workDir({{tempdir}}/root) = %q, %q", dir, tempDir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("expected %s to be created: %v", dir, err)
+	}
+}
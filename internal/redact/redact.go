@@ -0,0 +1,197 @@
+// Package redact masks secrets in values that end up in logs and events:
+// environment variables, command-line arguments, URLs and error messages.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces redacted values.
+const Mask = "REDACTED"
+
+// secretMarkers are substrings that mark a key as secret once it has been
+// upper-cased and stripped of separators (api_key, apiKey and API-KEY all
+// normalise to APIKEY).
+var secretMarkers = []string{
+	"TOKEN", "SECRET", "PASSWORD", "PASSWD", "APIKEY", "ACCESSKEY",
+	"PRIVATEKEY", "CREDENTIAL", "AUTHORIZATION", "BEARER", "SIGNATURE",
+}
+
+// secretExactKeys are short names that are only secret on their own;
+// matching them as substrings would mask harmless keys like "monkey".
+var secretExactKeys = []string{"KEY", "PWD", "SIG", "PASS"}
+
+// IsSecretKey reports whether an env var, query parameter or flag name looks
+// like it holds a secret.
+func IsSecretKey(key string) bool {
+	isSeparator := func(r rune) bool { return r == '_' || r == '-' || r == '.' }
+	upper := strings.ToUpper(key)
+	normalized := strings.Map(func(r rune) rune {
+		if isSeparator(r) {
+			return -1
+		}
+		return r
+	}, upper)
+	if normalized == "" {
+		return false
+	}
+	// AUTH only as the last word (BASIC_AUTH, X-Auth): as a substring it
+	// would catch AUTHOR, OAUTH_SCOPES and AUTH_URL
+	if words := strings.FieldsFunc(upper, isSeparator); words[len(words)-1] == "AUTH" {
+		return true
+	}
+	for _, marker := range secretMarkers {
+		if strings.Contains(normalized, marker) {
+			return true
+		}
+	}
+	for _, exact := range secretExactKeys {
+		if normalized == exact {
+			return true
+		}
+	}
+	return false
+}
+
+// Env returns a copy of env with the values of secret-looking keys masked.
+func Env(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	out := make(map[string]string, len(env))
+	for k, v := range env {
+		if IsSecretKey(k) && v != "" {
+			v = Mask
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// Args returns a copy of args with secret flag values masked. Both
+// "--token=abc" and "--token abc" forms are handled, and URLs in arguments
+// are redacted with URL.
+func Args(args []string) []string {
+	if args == nil {
+		return nil
+	}
+	out := make([]string, len(args))
+	maskNext := false
+	for i, arg := range args {
+		if maskNext && !strings.HasPrefix(arg, "-") {
+			out[i] = Mask
+			maskNext = false
+			continue
+		}
+		maskNext = false
+		if strings.HasPrefix(arg, "-") {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if IsSecretKey(name) {
+				if hasValue {
+					out[i] = arg[:strings.Index(arg, "=")+1] + Mask
+					continue
+				}
+				maskNext = true
+			}
+		}
+		out[i] = String(arg)
+	}
+	return out
+}
+
+// URL masks the password in a URL's user info and the values of query
+// parameters with secret-looking names (?token=, ?api_key=). Other
+// parameters such as ?sessionId= are kept. Strings that do not parse as a
+// URL are returned unchanged.
+func URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return raw
+	}
+
+	changed := false
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), Mask)
+			changed = true
+		}
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key, values := range query {
+			if !IsSecretKey(key) {
+				continue
+			}
+			for i := range values {
+				values[i] = Mask
+			}
+			changed = true
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	if !changed {
+		return raw
+	}
+	return u.String()
+}
+
+var (
+	urlPattern    = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// String redacts URLs and bearer tokens embedded in free text such as error
+// messages and log lines.
+func String(s string) string {
+	s = urlPattern.ReplaceAllStringFunc(s, URL)
+	return bearerPattern.ReplaceAllString(s, "${1}"+Mask)
+}
+
+// JSON masks a JSON document: string values under secret-looking keys are
+// replaced with Mask and other strings are redacted with String. Documents
+// that don't parse are redacted as free text. The result is compact JSON
+// with object keys sorted.
+func JSON(data []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []byte(String(string(data)))
+	}
+	out, err := json.Marshal(jsonValue(v))
+	if err != nil {
+		return []byte(String(string(data)))
+	}
+	return out
+}
+
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if s, ok := val.(string); ok && s != "" && IsSecretKey(key) {
+				v[key] = Mask
+				continue
+			}
+			v[key] = jsonValue(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = jsonValue(val)
+		}
+		return v
+	case string:
+		return String(v)
+	default:
+		return v
+	}
+}
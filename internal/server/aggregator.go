@@ -1,10 +1,13 @@
 package server
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -17,7 +20,7 @@ const (
 	// DefaultToolDiscoveryTimeout is the fallback timeout for tool discovery per server.
 	// Per-server StartupTimeout (from config) is preferred when available.
 	DefaultToolDiscoveryTimeout = 30 * time.Second
-	// MaxConcurrentDiscovery is the max number of servers to discover tools from concurrently
+	// MaxConcurrentDiscovery is the default max number of servers to discover tools from concurrently
 	MaxConcurrentDiscovery = 8
 	// ListToolsGracePeriod is the max time tools/list will block waiting for
 	// server discovery before returning partial results. Kept under typical
@@ -30,6 +33,7 @@ type AggregatedTool struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description,omitempty"`
 	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
 
 	// Internal metadata (not serialized to MCP)
 	serverID   string
@@ -44,20 +48,37 @@ type Aggregator struct {
 
 	// Tool cache
 	tools   map[string]AggregatedTool // qualified name -> tool
-	toolsMu sync.RWMutex
+	failed  map[string]string         // server name -> why its tools are missing
+	toolsMu sync.RWMutex              // protects tools and failed
 
 	// Manager tools
 	managerTools       []AggregatedTool
 	exposeManagerTools bool
+
+	// Max servers queried at once by ListTools
+	concurrency int
+
+	// How old a server's listed tools get before ListTools lists them
+	// again (0 = only after it restarts or reports tools/list_changed)
+	toolsRefresh time.Duration
 }
 
-// NewAggregator creates a new tool aggregator.
-func NewAggregator(cfg *config.Config, supervisor *process.Supervisor, exposeManagerTools bool) *Aggregator {
+// NewAggregator creates a new tool aggregator. concurrency bounds how many
+// servers ListTools queries at once (0 = MaxConcurrentDiscovery); toolsRefresh
+// is how old a server's tools get before being listed again (0 = only after it
+// restarts or reports tools/list_changed).
+func NewAggregator(cfg *config.Config, supervisor *process.Supervisor, exposeManagerTools bool, concurrency int, toolsRefresh time.Duration) *Aggregator {
+	if concurrency <= 0 {
+		concurrency = MaxConcurrentDiscovery
+	}
 	a := &Aggregator{
 		cfg:                cfg,
 		supervisor:         supervisor,
 		tools:              make(map[string]AggregatedTool),
+		failed:             make(map[string]string),
 		exposeManagerTools: exposeManagerTools,
+		concurrency:        concurrency,
+		toolsRefresh:       toolsRefresh,
 	}
 	a.managerTools = a.buildManagerTools()
 	return a
@@ -66,34 +87,55 @@ func NewAggregator(cfg *config.Config, supervisor *process.Supervisor, exposeMan
 // ListTools discovers and returns all tools from the specified servers.
 // This may start servers lazily if they're not running.
 // serverNames is a list of server names (map keys).
+//
+// Servers are queried in parallel, at most a.concurrency at a time, and each
+// gets its startup timeout to produce tools; a server that fails or times out
+// is left out of the result with a logged warning and recorded in
+// DiscoveryFailures. Servers still starting when ctx ends are not failures.
+// Tools are returned sorted by server name, then tool name, followed by any
+// manager tools.
 func (a *Aggregator) ListTools(ctx context.Context, serverNames []string) ([]AggregatedTool, error) {
 	// Discover tools from servers concurrently with bounded parallelism
-	sem := make(chan struct{}, MaxConcurrentDiscovery)
+	sem := make(chan struct{}, a.concurrency)
 	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var allTools []AggregatedTool
+	results := make([][]AggregatedTool, len(serverNames))
 
-	for _, name := range serverNames {
+	for i, name := range serverNames {
 		wg.Add(1)
-		go func(serverName string) {
+		go func(i int, serverName string) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				log.Printf("Skipped tool discovery for %s: %v", serverName, ctx.Err())
+				return
+			}
 			defer func() { <-sem }()
 
-			tools, err := a.discoverServerTools(ctx, serverName)
+			serverCtx, cancel := context.WithTimeout(ctx, a.discoveryTimeout(serverName))
+			defer cancel()
+
+			tools, err := a.discoverServerTools(serverCtx, serverName)
 			if err != nil {
-				log.Printf("Failed to discover tools from %s: %v", serverName, err)
+				log.Printf("Warning: leaving %s out of tools/list: %v", serverName, err)
+				if ctx.Err() == nil {
+					a.recordDiscovery(serverName, err)
+				}
 				return
 			}
-
-			mu.Lock()
-			allTools = append(allTools, tools...)
-			mu.Unlock()
-		}(name)
+			a.recordDiscovery(serverName, nil)
+			results[i] = tools
+		}(i, name)
 	}
 
 	wg.Wait()
 
+	var allTools []AggregatedTool
+	for _, tools := range results {
+		allTools = append(allTools, tools...)
+	}
+	sortTools(allTools)
+
 	// Update cache
 	a.toolsMu.Lock()
 	a.tools = make(map[string]AggregatedTool)
@@ -113,6 +155,46 @@ func (a *Aggregator) ListTools(ctx context.Context, serverNames []string) ([]Agg
 	return allTools, nil
 }
 
+// discoveryTimeout is how long ListTools waits for one server's tools: its
+// startup timeout, since that bounds how long init may legitimately take.
+func (a *Aggregator) discoveryTimeout(serverName string) time.Duration {
+	if srv, ok := a.cfg.GetServer(serverName); ok {
+		return time.Duration(srv.StartupTimeout()) * time.Second
+	}
+	return DefaultToolDiscoveryTimeout
+}
+
+// sortTools orders tools by server name, then upstream tool name, so
+// tools/list is stable regardless of which server answered first.
+func sortTools(tools []AggregatedTool) {
+	slices.SortStableFunc(tools, func(x, y AggregatedTool) int {
+		if c := cmp.Compare(x.serverName, y.serverName); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.origName, y.origName)
+	})
+}
+
+// recordDiscovery notes whether a server's tools made it into the cache; a
+// nil err clears an earlier failure.
+func (a *Aggregator) recordDiscovery(serverName string, err error) {
+	a.toolsMu.Lock()
+	defer a.toolsMu.Unlock()
+	if err != nil {
+		a.failed[serverName] = err.Error()
+	} else {
+		delete(a.failed, serverName)
+	}
+}
+
+// DiscoveryFailures returns the servers whose last tool discovery failed,
+// mapped to the error, so callers can tell tools/list is incomplete.
+func (a *Aggregator) DiscoveryFailures() map[string]string {
+	a.toolsMu.RLock()
+	defer a.toolsMu.RUnlock()
+	return maps.Clone(a.failed)
+}
+
 // PendingServers returns enabled servers that have not yet finished tool discovery.
 func (a *Aggregator) PendingServers(serverNames []string) []string {
 	var pending []string
@@ -144,6 +226,19 @@ func (a *Aggregator) GetTool(name string) (AggregatedTool, bool) {
 	return t, ok
 }
 
+// ValidateArguments checks tools/call arguments against the named tool's
+// input schema. Tools not yet discovered are not checked.
+func (a *Aggregator) ValidateArguments(name string, arguments json.RawMessage) *RPCError {
+	tool, ok := a.GetTool(name)
+	if !ok {
+		return nil
+	}
+	if problems := validateArguments(tool.InputSchema, arguments); len(problems) > 0 {
+		return ErrInvalidArguments(name, problems)
+	}
+	return nil
+}
+
 // discoverServerTools starts a server (if needed) and retrieves its tools.
 // serverName is the server's map key (identifier).
 func (a *Aggregator) discoverServerTools(ctx context.Context, serverName string) ([]AggregatedTool, error) {
@@ -153,7 +248,7 @@ func (a *Aggregator) discoverServerTools(ctx context.Context, serverName string)
 	}
 
 	if !srv.IsEnabled() {
-		log.Printf("Server %s is disabled, skipping", serverName)
+		log.Printf("%s, skipping", disabledMessage(serverName, srv))
 		return nil, nil
 	}
 
@@ -172,21 +267,47 @@ func (a *Aggregator) discoverServerTools(ctx context.Context, serverName string)
 	if err := handle.WaitForTools(ctx); err != nil {
 		return nil, fmt.Errorf("wait for tools: %w", err)
 	}
+	// The server came up but tools/list failed, so it contributes nothing
+	if err := handle.ToolsError(); err != nil {
+		return nil, fmt.Errorf("list tools: %w", err)
+	}
+
+	// Tools older than the refresh interval, or that the server has reported
+	// changed, are listed again. On failure the previous list is kept.
+	if err := a.supervisor.RefreshStaleTools(ctx, serverName, a.toolsRefresh); err != nil {
+		log.Printf("Warning: keeping previous tools for %s, listing them again failed: %v", serverName, err)
+	}
 
 	// Get tools from the running server
 	mcpTools := handle.Tools()
 
+	// Tool names are qualified with the server's tool prefix (defaults to the server name)
+	prefix := a.cfg.ToolPrefix(serverName)
+
 	tools := make([]AggregatedTool, len(mcpTools))
 	for i, t := range mcpTools {
-		// Qualify tool name: serverName.toolName
-		qualifiedName := serverName + "." + t.Name
+		// Qualify tool name: prefix.toolName
+		qualifiedName := prefix + "." + t.Name
 
-		// Prefix description with server name
+		// Config overrides replace the upstream description and add annotations
 		desc := t.Description
+		var annotations json.RawMessage
+		if o, ok := a.cfg.GetToolOverride(serverName, t.Name); ok {
+			if o.Description != "" {
+				desc = o.Description
+			}
+			if len(o.Annotations) > 0 {
+				if b, err := json.Marshal(o.Annotations); err == nil {
+					annotations = b
+				}
+			}
+		}
+
+		// Prefix description with the tool prefix
 		if desc != "" {
-			desc = fmt.Sprintf("[%s] %s", serverName, desc)
+			desc = fmt.Sprintf("[%s] %s", prefix, desc)
 		} else {
-			desc = fmt.Sprintf("[%s]", serverName)
+			desc = fmt.Sprintf("[%s]", prefix)
 		}
 
 		// Convert InputSchema
@@ -201,6 +322,7 @@ func (a *Aggregator) discoverServerTools(ctx context.Context, serverName string)
 			Name:        qualifiedName,
 			Description: desc,
 			InputSchema: schemaJSON,
+			Annotations: annotations,
 			serverID:    serverName,
 			serverName:  serverName,
 			origName:    t.Name,
@@ -225,6 +347,20 @@ func ParseToolName(qualifiedName string) (serverID, toolName string, isManager b
 	return parts[0], parts[1], false
 }
 
+// ResolveToolName parses a qualified tool name and maps its prefix back to
+// the owning server, honouring per-server ToolPrefix overrides. If no server
+// claims the prefix, the prefix is returned unchanged as the server name.
+func ResolveToolName(cfg *config.Config, qualifiedName string) (serverName, toolName string, isManager bool) {
+	prefix, toolName, isManager := ParseToolName(qualifiedName)
+	if isManager || prefix == "" {
+		return prefix, toolName, isManager
+	}
+	if name, ok := cfg.ServerForToolPrefix(prefix); ok {
+		return name, toolName, false
+	}
+	return prefix, toolName, false
+}
+
 // buildManagerTools creates the mcpmu.* meta-tools.
 func (a *Aggregator) buildManagerTools() []AggregatedTool {
 	return []AggregatedTool{
@@ -253,6 +389,21 @@ func (a *Aggregator) buildManagerTools() []AggregatedTool {
 			Description: "Get recent log lines from a server's stderr",
 			InputSchema: json.RawMessage(`{"type": "object", "properties": {"server_id": {"type": "string", "description": "The ID of the server"}, "lines": {"type": "integer", "description": "Number of lines to return (default: 50)", "default": 50}}, "required": ["server_id"]}`),
 		},
+		{
+			Name:        "mcpmu.servers_info",
+			Description: "Show the name, version and negotiated protocol version each running MCP server reported, with its tool count",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+		},
+		{
+			Name:        "mcpmu.health",
+			Description: "Report the health of every configured MCP server: state, last error, uptime, tool count and auth status, with running/total counts and any servers whose tools failed to load into tools/list. Use it to check whether a server is up when a tool call fails or a tool is missing",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {}}`),
+		},
+		{
+			Name:        "mcpmu.tools_filter",
+			Description: "Narrow the tools exposed to this session to the named ones (as listed by tools/list), within what the namespace already allows. Not saved; call without tools to remove the filter. Returns the resulting tool list",
+			InputSchema: json.RawMessage(`{"type": "object", "properties": {"tools": {"type": "array", "items": {"type": "string"}, "description": "Tool names to keep; omit to expose every allowed tool again"}}}`),
+		},
 		{
 			Name:        "mcpmu.namespaces_list",
 			Description: "List all namespaces and show which is active",
@@ -265,6 +416,7 @@ func (a *Aggregator) buildManagerTools() []AggregatedTool {
 // Returns the tools found, or an error if discovery failed.
 func (a *Aggregator) DiscoverServer(ctx context.Context, serverName string) ([]AggregatedTool, error) {
 	tools, err := a.discoverServerTools(ctx, serverName)
+	a.recordDiscovery(serverName, err)
 	if err != nil {
 		return nil, err
 	}
@@ -281,6 +433,7 @@ func (a *Aggregator) DiscoverServer(ctx context.Context, serverName string) ([]A
 // RefreshServerTools refreshes the tool cache for a specific server.
 func (a *Aggregator) RefreshServerTools(ctx context.Context, serverName string) error {
 	tools, err := a.discoverServerTools(ctx, serverName)
+	a.recordDiscovery(serverName, err)
 	if err != nil {
 		return err
 	}
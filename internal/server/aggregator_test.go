@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/process"
+)
+
+func TestAggregator_ListTools_SlowServerDoesNotBlock(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	slow := fakeServerConfig(t, map[string]any{
+		"tools":  []map[string]any{{"name": "never"}},
+		"delays": map[string]any{"initialize": (5 * time.Second).Nanoseconds()},
+	})
+	slow.StartupTimeoutSec = 1
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"zeta":  fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "b"}, {"name": "a"}}}),
+			"alpha": fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "z"}, {"name": "y"}}}),
+			"slow":  slow,
+		},
+	}
+
+	bus := events.NewBus()
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+		PIDTrackerDir:       t.TempDir(),
+		CredentialStoreMode: "file",
+		CredentialsDir:      t.TempDir(),
+	})
+	t.Cleanup(func() {
+		supervisor.StopAll()
+		bus.Close()
+	})
+
+	// A single worker still gets through: slow gives up after its 1s
+	// startup timeout instead of holding the others until ctx expires.
+	agg := NewAggregator(cfg, supervisor, false, 1, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	tools, err := agg.ListTools(ctx, []string{"zeta", "slow", "alpha"})
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("ListTools took %v, want it bounded by the slow server's startup timeout", elapsed)
+	}
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	want := []string{"alpha.y", "alpha.z", "zeta.a", "zeta.b"}
+	if len(names) != len(want) {
+		t.Fatalf("tools = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("tools = %v, want %v", names, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// validateArguments checks tools/call arguments against a tool's JSON input
+// schema and returns one message per problem found. It understands the parts
+// of JSON Schema tool schemas commonly use (type, required, properties,
+// additionalProperties, items and enum) and ignores everything else, so a
+// schema it can't interpret never rejects a call.
+func validateArguments(schema, arguments json.RawMessage) []string {
+	if len(bytes.TrimSpace(schema)) == 0 {
+		return nil
+	}
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil
+	}
+
+	var args any = map[string]any{}
+	if trimmed := bytes.TrimSpace(arguments); len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &args); err != nil {
+			return []string{"arguments are not valid JSON: " + err.Error()}
+		}
+	}
+
+	var problems []string
+	checkSchema(s, args, "arguments", &problems)
+	return problems
+}
+
+// checkSchema validates value against schema, appending problems found at path.
+func checkSchema(schema map[string]any, value any, path string, problems *[]string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		if !slices.ContainsFunc(types, func(t string) bool { return matchesType(t, value) }) {
+			*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonTypeName(value)))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		if !slices.ContainsFunc(enum, func(v any) bool { return reflect.DeepEqual(v, value) }) {
+			*problems = append(*problems, fmt.Sprintf("%s: must be one of %s", path, formatEnum(enum)))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		checkObject(schema, v, path, problems)
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				checkSchema(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// checkObject validates an object's required, properties and
+// additionalProperties keywords.
+func checkObject(schema map[string]any, obj map[string]any, path string, problems *[]string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				*problems = append(*problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "." + name
+		if prop, ok := properties[name].(map[string]any); ok {
+			checkSchema(prop, obj[name], propPath, problems)
+			continue
+		}
+		if _, declared := properties[name]; declared {
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*problems = append(*problems, fmt.Sprintf("%s: unknown property %q", path, name))
+			}
+		case map[string]any:
+			checkSchema(extra, obj[name], propPath, problems)
+		}
+	}
+}
+
+// schemaTypes returns the type keyword as a list ("string" or ["string", "null"]).
+func schemaTypes(t any) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesType reports whether a decoded JSON value has the given schema
+// type. Unknown types match anything.
+func matchesType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// jsonTypeName names the JSON type of a decoded value.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, v := range enum {
+		b, _ := json.Marshal(v)
+		parts[i] = string(b)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
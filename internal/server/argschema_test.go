@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateArguments(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string"},
+			"limit": {"type": "integer"},
+			"mode": {"type": "string", "enum": ["fast", "slow"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"opts": {"type": "object", "properties": {"deep": {"type": "boolean"}}, "additionalProperties": false},
+			"note": {"type": ["string", "null"]}
+		},
+		"required": ["path"]
+	}`)
+
+	tests := []struct {
+		name string
+		args string
+		want []string // substrings, one per expected problem
+	}{
+		{name: "valid", args: `{"path": "/tmp", "limit": 3, "mode": "fast", "tags": ["a"], "opts": {"deep": true}, "note": null}`},
+		{name: "extra properties allowed by default", args: `{"path": "/tmp", "other": 1}`},
+		{name: "missing required", args: `{}`, want: []string{`missing required property "path"`}},
+		{name: "null arguments", args: `null`, want: []string{`missing required property "path"`}},
+		{name: "no arguments", args: ``, want: []string{`missing required property "path"`}},
+		{name: "wrong type", args: `{"path": 42}`, want: []string{"arguments.path: expected string, got number"}},
+		{name: "integer", args: `{"path": "x", "limit": 1.5}`, want: []string{"arguments.limit: expected integer"}},
+		{name: "enum", args: `{"path": "x", "mode": "medium"}`, want: []string{`arguments.mode: must be one of ["fast", "slow"]`}},
+		{name: "array items", args: `{"path": "x", "tags": ["a", 2]}`, want: []string{"arguments.tags[1]: expected string"}},
+		{name: "nested additionalProperties", args: `{"path": "x", "opts": {"wide": true}}`, want: []string{`arguments.opts: unknown property "wide"`}},
+		{name: "multiple problems", args: `{"limit": "many"}`, want: []string{`missing required property "path"`, "arguments.limit: expected integer, got string"}},
+		{name: "not an object", args: `[1]`, want: []string{"arguments: expected object, got array"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := validateArguments(schema, json.RawMessage(tt.args))
+			if len(problems) != len(tt.want) {
+				t.Fatalf("problems = %q, want %d matching %q", problems, len(tt.want), tt.want)
+			}
+			for _, want := range tt.want {
+				if !slices.ContainsFunc(problems, func(p string) bool { return strings.Contains(p, want) }) {
+					t.Errorf("problems = %q, want one containing %q", problems, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateArguments_LenientSchemas(t *testing.T) {
+	for _, schema := range []string{``, `not json`, `{}`, `{"type": "object"}`, `{"type": "object", "properties": {"x": {"format": "uri"}}}`} {
+		if problems := validateArguments(json.RawMessage(schema), json.RawMessage(`{"x": 1}`)); len(problems) != 0 {
+			t.Errorf("schema %q: problems = %q, want none", schema, problems)
+		}
+	}
+}
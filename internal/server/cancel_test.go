@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_CancelledToolCallReachesUpstream(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	requestLog := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":          []map[string]any{{"name": "slow"}},
+				"delays":         map[string]any{"tools/call": 2 * time.Second},
+				"requestLogPath": requestLog,
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.slow","arguments":{}}}`,
+	)
+	c.response(t, 1)
+
+	// Wait for the call to reach the upstream before cancelling it
+	id := json.RawMessage("2")
+	waitFor(t, "the tools/call to reach the upstream", func() bool {
+		data, _ := os.ReadFile(requestLog)
+		return strings.Contains(string(data), "tools/call")
+	})
+	cancelled := time.Now()
+	c.write(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2,"reason":"user aborted"}}`)
+
+	waitFor(t, "the cancelled call to finish", func() bool { return !c.srv.requests.busy(id) })
+	if elapsed := time.Since(cancelled); elapsed > time.Second {
+		t.Errorf("cancelled call took %s to finish, want well under the upstream's 2s", elapsed)
+	}
+	waitFor(t, "the upstream to receive notifications/cancelled", func() bool {
+		data, _ := os.ReadFile(requestLog)
+		return strings.Contains(string(data), "notifications/cancelled")
+	})
+
+	// A cancelled request gets no response
+	c.write(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	c.next(t, "ping response", func(m rpcMessage) bool {
+		if idKey(m.ID) == "2" {
+			t.Errorf("unexpected response to the cancelled request: result=%s error=%v", m.Result, m.Error)
+		}
+		return idKey(m.ID) == "3"
+	})
+}
+
+// waitFor polls cond for up to 5 seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_ForwardsClientCapabilitiesToUpstreams(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	paramsPath := filepath.Join(t.TempDir(), "initialize.json")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":                []map[string]any{{"name": "a1"}},
+				"initializeParamsPath": paramsPath,
+			}),
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{"roots":{"listChanged":true},"sampling":{},"elicitation":{}},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	h.settle(500 * time.Millisecond)
+	h.close(t)
+
+	data, err := os.ReadFile(paramsPath)
+	if err != nil {
+		t.Fatalf("expected the upstream to be initialized: %v", err)
+	}
+	var params struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("Unmarshal initialize params: %v", err)
+	}
+
+	// Only capabilities mcpmu can relay are declared upstream
+	want := map[string]any{
+		"roots":    map[string]any{"listChanged": true},
+		"sampling": map[string]any{},
+	}
+	if !reflect.DeepEqual(params.Capabilities, want) {
+		t.Errorf("upstream saw capabilities %v, want %v", params.Capabilities, want)
+	}
+}
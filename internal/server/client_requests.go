@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/mcp"
+)
+
+// ClientRequestTimeout is how long a request relayed from an upstream to the
+// client waits for an answer. Sampling may wait on the user approving the
+// request, so it is generous.
+const ClientRequestTimeout = 5 * time.Minute
+
+// clientResponse is the client's answer to a request relayed to it.
+type clientResponse struct {
+	Result json.RawMessage
+	Error  *RPCError
+}
+
+// clientRequestTable correlates requests mcpmu sends to the downstream client
+// on behalf of upstreams with the client's responses. Ids are mcpmu's own
+// ("mcpmu-1", ...), so an upstream's ids never reach the client and two
+// upstreams can't collide.
+type clientRequestTable struct {
+	mu      sync.Mutex
+	nextID  int64
+	closed  bool
+	pending map[string]chan clientResponse
+}
+
+func newClientRequestTable() *clientRequestTable {
+	return &clientRequestTable{pending: make(map[string]chan clientResponse)}
+}
+
+// add allocates an id for a new request and the channel its response is
+// delivered on. It fails once the table is closed.
+func (t *clientRequestTable) add() (json.RawMessage, chan clientResponse, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, nil, fmt.Errorf("server is shutting down")
+	}
+	t.nextID++
+	id, _ := json.Marshal(fmt.Sprintf("mcpmu-%d", t.nextID))
+	ch := make(chan clientResponse, 1)
+	t.pending[idKey(id)] = ch
+	return id, ch, nil
+}
+
+// remove forgets a request, e.g. after it timed out.
+func (t *clientRequestTable) remove(id json.RawMessage) {
+	t.mu.Lock()
+	delete(t.pending, idKey(id))
+	t.mu.Unlock()
+}
+
+// resolve delivers the client's response to the request with the given id,
+// reporting whether one was waiting.
+func (t *clientRequestTable) resolve(id json.RawMessage, resp clientResponse) bool {
+	t.mu.Lock()
+	ch, ok := t.pending[idKey(id)]
+	delete(t.pending, idKey(id))
+	t.mu.Unlock()
+	if ok {
+		ch <- resp
+	}
+	return ok
+}
+
+// close fails every pending request and refuses new ones.
+func (t *clientRequestTable) close() {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[string]chan clientResponse)
+	t.closed = true
+	t.mu.Unlock()
+	for _, ch := range pending {
+		ch <- clientResponse{Error: NewRPCError(ErrCodeInternalError, "server is shutting down", nil)}
+	}
+}
+
+// OnUpstreamRequest implements mcp.RequestSink. Requests the downstream
+// client can answer are relayed to it and its response returned to the
+// upstream that asked; anything else gets method not found.
+func (s *Server) OnUpstreamRequest(ctx context.Context, serverName, method string, params json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case "ping":
+		return json.RawMessage("{}"), nil
+	case "sampling/createMessage":
+		if !s.clientSupports("sampling") {
+			return nil, &mcp.RequestError{Code: ErrCodeMethodNotFound, Message: "client does not support sampling"}
+		}
+	case "roots/list":
+		if !s.clientSupports("roots") {
+			return nil, &mcp.RequestError{Code: ErrCodeMethodNotFound, Message: "client does not support roots"}
+		}
+	default:
+		return nil, &mcp.RequestError{Code: ErrCodeMethodNotFound, Message: "Method not found: " + method}
+	}
+
+	log.Printf("Relaying %s from %s to the client", method, serverName)
+	return s.requestClient(ctx, method, params)
+}
+
+// clientSupports reports whether the downstream client declared a capability
+// at initialize.
+func (s *Server) clientSupports(capability string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.clientCaps[capability]
+	return ok && v != nil
+}
+
+// requestClient sends a request to the downstream client and waits up to
+// ClientRequestTimeout for its response.
+func (s *Server) requestClient(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, error) {
+	id, ch, err := s.clientRequests.add()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, ClientRequestTimeout)
+	defer cancel()
+
+	s.handlersWG.Go(func() {
+		s.send(rpcMessage{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	})
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, &mcp.RequestError{Code: resp.Error.Code, Message: resp.Error.Message}
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		s.clientRequests.remove(id)
+		return nil, &mcp.RequestError{Code: ErrCodeInternalError, Message: fmt.Sprintf("client did not answer %s: %v", method, ctx.Err())}
+	}
+}
+
+// notifyUpstreams relays a notification from the client to every running
+// upstream, off the caller's goroutine.
+func (s *Server) notifyUpstreams(ctx context.Context, method string, params json.RawMessage) {
+	var p any
+	if len(params) > 0 {
+		p = params
+	}
+	for _, name := range s.supervisor.RunningServers() {
+		handle := s.supervisor.Get(name)
+		if handle == nil || handle.Client() == nil {
+			continue
+		}
+		client := handle.Client()
+		s.handlersWG.Go(func() {
+			if err := client.Notify(ctx, method, p); err != nil {
+				log.Printf("Failed to relay %s to %s: %v", method, name, err)
+			}
+		})
+	}
+}
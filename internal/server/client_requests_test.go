@@ -0,0 +1,246 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// stubClient drives srv.Run over pipes in both directions, so a test can act
+// as the downstream client: read each frame as it is written and answer
+// requests the server relays to it.
+type stubClient struct {
+	srv     *Server
+	in      *io.PipeWriter
+	frames  chan rpcMessage
+	runDone chan struct{}
+	cancel  context.CancelFunc
+}
+
+func startStubClient(t *testing.T, opts Options) *stubClient {
+	t.Helper()
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	opts.Stdin = stdinR
+	opts.Stdout = stdoutW
+	opts.ServerName = "mcpmu-test"
+	opts.ServerVersion = "1.0.0"
+	opts.ProtocolVersion = "2024-11-05"
+	opts.LogLevel = "error"
+	opts.PIDTrackerDir = t.TempDir()
+
+	srv, err := New(opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &stubClient{srv: srv, in: stdinW, frames: make(chan rpcMessage, 64), runDone: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(c.runDone)
+		_ = srv.Run(ctx)
+		_ = stdoutW.Close()
+	}()
+	go func() {
+		defer close(c.frames)
+		scanner := bufio.NewScanner(stdoutR)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			var msg rpcMessage
+			if json.Unmarshal(scanner.Bytes(), &msg) == nil {
+				c.frames <- msg
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		_ = stdinW.Close()
+		select {
+		case <-c.runDone:
+		case <-time.After(10 * time.Second):
+			cancel()
+			<-c.runDone
+		}
+	})
+	return c
+}
+
+func (c *stubClient) write(frames ...string) {
+	for _, f := range frames {
+		_, _ = c.in.Write([]byte(f + "\n"))
+	}
+}
+
+// next returns the first frame matching match, skipping others.
+func (c *stubClient) next(t *testing.T, what string, match func(rpcMessage) bool) rpcMessage {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.frames:
+			if !ok {
+				t.Fatalf("server exited waiting for %s", what)
+			}
+			if match(msg) {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+// request returns the next request the server sends with method.
+func (c *stubClient) request(t *testing.T, method string) rpcMessage {
+	t.Helper()
+	return c.next(t, method+" request", func(m rpcMessage) bool { return m.Method == method && m.ID != nil })
+}
+
+// response returns the server's response to the request with id.
+func (c *stubClient) response(t *testing.T, id int) rpcMessage {
+	t.Helper()
+	want, _ := json.Marshal(id)
+	return c.next(t, "response "+string(want), func(m rpcMessage) bool { return m.Method == "" && idKey(m.ID) == string(want) })
+}
+
+// toolText returns the text of the first content block of a tools/call
+// response.
+func toolText(t *testing.T, msg rpcMessage) string {
+	t.Helper()
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if msg.Error != nil || json.Unmarshal(msg.Result, &result) != nil || len(result.Content) == 0 {
+		t.Fatalf("unexpected tools/call response: result=%s error=%v", msg.Result, msg.Error)
+	}
+	return result.Content[0].Text
+}
+
+func TestServer_RelaysSamplingToClient(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "summarise"}},
+				"clientRequests": map[string]any{
+					"summarise": map[string]any{
+						"method": "sampling/createMessage",
+						"params": map[string]any{
+							"messages":  []map[string]any{{"role": "user", "content": map[string]any{"type": "text", "text": "Summarise this"}}},
+							"maxTokens": 100,
+						},
+					},
+				},
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{"sampling":{}},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.summarise","arguments":{}}}`,
+	)
+
+	req := c.request(t, "sampling/createMessage")
+	if !strings.Contains(string(req.Params), "Summarise this") {
+		t.Errorf("expected the upstream's params to be relayed, got %s", req.Params)
+	}
+	c.write(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{"role":"assistant","content":{"type":"text","text":"A summary"},"model":"stub-model"}}`)
+
+	if got := toolText(t, c.response(t, 2)); !strings.Contains(got, "stub-model") || !strings.Contains(got, "A summary") {
+		t.Errorf("expected the client's sampling result to reach the upstream, got %s", got)
+	}
+}
+
+func TestServer_RefusesSamplingWithoutClientSupport(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "summarise"}},
+				"clientRequests": map[string]any{
+					"summarise": map[string]any{"method": "sampling/createMessage", "params": map[string]any{"messages": []any{}, "maxTokens": 1}},
+				},
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.summarise","arguments":{}}}`,
+	)
+
+	got := toolText(t, c.next(t, "tools/call response", func(m rpcMessage) bool {
+		if m.Method == "sampling/createMessage" {
+			t.Errorf("sampling request relayed to a client without sampling support")
+		}
+		return m.Method == "" && idKey(m.ID) == "2"
+	}))
+	if !strings.Contains(got, "-32601") || !strings.Contains(got, "does not support sampling") {
+		t.Errorf("expected the upstream to get a method-not-found error, got %s", got)
+	}
+}
+
+func TestServer_RelaysRootsToClient(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	requestLog := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":          []map[string]any{{"name": "workspace"}},
+				"clientRequests": map[string]any{"workspace": map[string]any{"method": "roots/list"}},
+				"requestLogPath": requestLog,
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{"roots":{"listChanged":true}},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.workspace","arguments":{}}}`,
+	)
+
+	req := c.request(t, "roots/list")
+	c.write(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{"roots":[{"uri":"file:///work/project","name":"project"}]}}`)
+	if got := toolText(t, c.response(t, 2)); !strings.Contains(got, "file:///work/project") {
+		t.Errorf("expected the client's roots to reach the upstream, got %s", got)
+	}
+
+	// The client's roots change notification reaches the running upstream
+	c.write(`{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(requestLog)
+		if strings.Contains(string(data), "notifications/roots/list_changed") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("upstream never received notifications/roots/list_changed; it saw:\n%s", data)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
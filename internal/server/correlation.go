@@ -0,0 +1,179 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// DuplicateIDPolicy decides what happens when a client sends a request whose
+// id matches one that is still in flight. JSON-RPC requires ids to be unique
+// among pending requests, but a client that reuses them would otherwise get
+// two responses with the same id in arbitrary order and could pair them up
+// with the wrong requests.
+type DuplicateIDPolicy string
+
+const (
+	// DuplicateIDsQueue holds the request until the earlier one with the same
+	// id has been answered, so responses for an id arrive in request order.
+	DuplicateIDsQueue DuplicateIDPolicy = "queue"
+	// DuplicateIDsReject answers the request with an Invalid Request error.
+	DuplicateIDsReject DuplicateIDPolicy = "reject"
+)
+
+// ParseDuplicateIDPolicy parses a --duplicate-ids value.
+func ParseDuplicateIDPolicy(s string) (DuplicateIDPolicy, error) {
+	switch p := DuplicateIDPolicy(s); p {
+	case DuplicateIDsQueue, DuplicateIDsReject:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate id policy %q (must be queue or reject)", s)
+	}
+}
+
+// requestTable correlates downstream request ids with in-flight handlers.
+// Upstream requests get fresh ids from each server's mcp.Client, so the
+// client's ids never reach an upstream; this table only ensures that a
+// reused client id can't cross responses on the way back.
+//
+// Claims are made from the main read loop only, so checking and claiming an
+// id is race-free; releases happen on handler goroutines.
+type requestTable struct {
+	mu sync.Mutex
+	// tails maps an id to the done channel of the most recent request
+	// claiming it. Each claim waits on the previous tail, forming a FIFO
+	// chain per id.
+	tails map[string]chan struct{}
+	// running maps an id to the request with that id being handled now, so
+	// notifications/cancelled can abort it.
+	running map[string]*runningRequest
+}
+
+// runningRequest is a request being handled, cancellable by the client.
+type runningRequest struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
+func newRequestTable() *requestTable {
+	return &requestTable{
+		tails:   make(map[string]chan struct{}),
+		running: make(map[string]*runningRequest),
+	}
+}
+
+// idKey canonicalizes a request id. Ids are compared by their JSON text, so
+// 1 and "1" stay distinct, as JSON-RPC requires.
+func idKey(id json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, id); err != nil {
+		return string(id)
+	}
+	return buf.String()
+}
+
+// busy reports whether a request with the given id is still in flight.
+func (t *requestTable) busy(id json.RawMessage) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.tails[idKey(id)]
+	return ok
+}
+
+// claim registers a request with the given id. prev is closed once the
+// previous request with the same id has been answered, or is nil if none is
+// in flight. release must be called after the request has been answered.
+func (t *requestTable) claim(id json.RawMessage) (prev <-chan struct{}, release func()) {
+	key := idKey(id)
+	done := make(chan struct{})
+
+	t.mu.Lock()
+	if ch, ok := t.tails[key]; ok {
+		prev = ch
+	}
+	t.tails[key] = done
+	t.mu.Unlock()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			t.mu.Lock()
+			if t.tails[key] == done {
+				delete(t.tails, key)
+			}
+			t.mu.Unlock()
+			close(done)
+		})
+	}
+	return prev, release
+}
+
+// start records that the request with the given id is now being handled
+// and can be aborted with cancel. finish must be called once it's done.
+func (t *requestTable) start(id json.RawMessage, cancel context.CancelFunc) *runningRequest {
+	r := &runningRequest{cancel: cancel}
+	t.mu.Lock()
+	t.running[idKey(id)] = r
+	t.mu.Unlock()
+	return r
+}
+
+// finish forgets a request recorded with start.
+func (t *requestTable) finish(id json.RawMessage, r *runningRequest) {
+	t.mu.Lock()
+	if t.running[idKey(id)] == r {
+		delete(t.running, idKey(id))
+	}
+	t.mu.Unlock()
+}
+
+// cancel aborts the request with the given id being handled now, reporting
+// whether there was one.
+func (t *requestTable) cancel(id json.RawMessage) bool {
+	t.mu.Lock()
+	r, ok := t.running[idKey(id)]
+	t.mu.Unlock()
+	if ok {
+		r.cancelled.Store(true)
+		r.cancel()
+	}
+	return ok
+}
+
+// correlationKey is the context key for a request's correlation ID.
+type correlationKey struct{}
+
+// newCorrelationID returns a short random ID for one downstream request.
+// Unlike the client's request id it is unique across the session, and it
+// is what ties the request's log lines to its upstream dispatch.
+func newCorrelationID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// withCorrelationID returns ctx carrying the correlation ID id.
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// correlationID returns the correlation ID carried by ctx, or "".
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// logf logs like log.Printf, prefixed with ctx's correlation ID as
+// "[req <id>]" when it has one.
+func logf(ctx context.Context, format string, args ...any) {
+	if id := correlationID(ctx); id != "" {
+		format = "[req " + id + "] " + format
+	}
+	log.Printf(format, args...)
+}
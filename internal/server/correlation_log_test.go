@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// Not parallel: captures the global logger's output.
+func TestServer_CorrelationIDInLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "echo"}},
+			}),
+		},
+	}
+
+	var logBuf bytes.Buffer
+	originalOutput := log.Writer()
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(originalOutput)
+
+	h := startSubscribeTestServer(t, Options{Config: cfg, EagerStart: true})
+	h.write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	h.settle(300 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.echo","arguments":{}}}`)
+	h.settle(500 * time.Millisecond)
+	h.close(t)
+
+	log.SetOutput(originalOutput)
+	logs := logBuf.String()
+
+	m := regexp.MustCompile(`\[req ([0-9a-f]+)\] Received tools/call \(id 2\)`).FindStringSubmatch(logs)
+	if m == nil {
+		t.Fatalf("no inbound log line for tools/call id 2 in:\n%s", logs)
+	}
+	cid := m[1]
+
+	var dispatched, answered bool
+	for line := range strings.SplitSeq(logs, "\n") {
+		if !strings.Contains(line, "[req "+cid+"]") {
+			continue
+		}
+		if strings.Contains(line, "Dispatching echo to upstream alpha") {
+			dispatched = true
+		}
+		if strings.Contains(line, "tools/call answered in") {
+			answered = true
+		}
+	}
+	if !dispatched {
+		t.Errorf("upstream dispatch log line doesn't carry correlation ID %s:\n%s", cid, logs)
+	}
+	if !answered {
+		t.Errorf("completion log line doesn't carry correlation ID %s:\n%s", cid, logs)
+	}
+}
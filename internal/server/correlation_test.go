@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// responseLine is a response frame from the stdout transcript, in order.
+type responseLine struct {
+	id   string
+	body string
+}
+
+func responsesInOrder(t *testing.T, stdout string) []responseLine {
+	t.Helper()
+	var out []responseLine
+	for line := range strings.SplitSeq(strings.TrimSpace(stdout), "\n") {
+		var env struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(line), &env); err != nil || env.ID == nil {
+			continue
+		}
+		out = append(out, responseLine{id: string(env.ID), body: line})
+	}
+	return out
+}
+
+func TestServer_DuplicateRequestIDs(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	tests := []struct {
+		policy DuplicateIDPolicy
+		// Expected response bodies for id 1, in order, as substrings
+		want []string
+	}{
+		// Without queueing, fast's reply would arrive first and the client
+		// would pair it with the slow request.
+		{DuplicateIDsQueue, []string{`{\"n\":\"a\"}`, `{\"n\":\"b\"}`}},
+		{DuplicateIDsReject, []string{`already in flight`, `{\"n\":\"a\"}`}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{
+				SchemaVersion: 1,
+				Servers: map[string]config.ServerConfig{
+					"slow": fakeServerConfig(t, map[string]any{
+						"tools":         []map[string]any{{"name": "op"}},
+						"echoToolCalls": true,
+						"delays":        map[string]any{"tools/call": (400 * time.Millisecond).Nanoseconds()},
+					}),
+					"fast": fakeServerConfig(t, map[string]any{
+						"tools":         []map[string]any{{"name": "op"}},
+						"echoToolCalls": true,
+					}),
+				},
+			}
+
+			h := startSubscribeTestServer(t, Options{Config: cfg, EagerStart: true, DuplicateIDs: tt.policy})
+			h.write(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+			h.settle(500 * time.Millisecond)
+
+			h.write(
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow.op","arguments":{"n":"a"}}}`,
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fast.op","arguments":{"n":"b"}}}`,
+				`{"jsonrpc":"2.0","id":"1","method":"tools/call","params":{"name":"fast.op","arguments":{"n":"c"}}}`,
+			)
+			h.settle(1500 * time.Millisecond)
+			h.close(t)
+
+			var gotOne []string
+			var gotString []string
+			for _, r := range responsesInOrder(t, h.stdout.String()) {
+				switch r.id {
+				case `1`:
+					gotOne = append(gotOne, r.body)
+				case `"1"`:
+					gotString = append(gotString, r.body)
+				}
+			}
+
+			if len(gotOne) != len(tt.want) {
+				t.Fatalf("got %d responses for id 1, want %d:\n%s", len(gotOne), len(tt.want), strings.Join(gotOne, "\n"))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(gotOne[i], want) {
+					t.Errorf("response %d for id 1 = %s, want it to contain %s", i, gotOne[i], want)
+				}
+			}
+
+			// A string id that looks like a number is a different id
+			if len(gotString) != 1 || !strings.Contains(gotString[0], `{\"n\":\"c\"}`) {
+				t.Errorf("responses for id \"1\" = %v, want the n=c result", gotString)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// DefaultReloadDrainTimeout is how long a config reload waits for in-flight
+// calls to finish on servers it is about to stop.
+const DefaultReloadDrainTimeout = 10 * time.Second
+
+// shutdownDrainTimeout is how long serve waits, once the client has closed
+// stdin, for requests already in flight before cancelling them.
+const shutdownDrainTimeout = 5 * time.Second
+
+// drainHandlers waits up to shutdownDrainTimeout for in-flight request
+// handlers to finish, then cancels the ones left via cancel.
+func (s *Server) drainHandlers(cancel context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		s.handlersWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownDrainTimeout):
+		log.Printf("Cancelling requests still in flight %s after the client disconnected", shutdownDrainTimeout)
+		cancel()
+	}
+}
+
+// inflightTracker counts in-flight upstream calls per server so a reload can
+// drain a server before stopping it. While a server is draining, new calls
+// to it are rejected; while it is being stopped for idleness, they wait.
+type inflightTracker struct {
+	mu       sync.Mutex
+	counts   map[string]int
+	draining map[string]bool
+	stopping map[string]bool // claimed by claimIdle
+	idle     *sync.Cond
+}
+
+func newInflightTracker() *inflightTracker {
+	t := &inflightTracker{
+		counts:   make(map[string]int),
+		draining: make(map[string]bool),
+		stopping: make(map[string]bool),
+	}
+	t.idle = sync.NewCond(&t.mu)
+	return t
+}
+
+// acquire registers an in-flight call to serverName, first waiting out an
+// idle stop in progress. Returns false if the server is draining and must
+// not receive new calls.
+func (t *inflightTracker) acquire(serverName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.stopping[serverName] {
+		t.idle.Wait()
+	}
+	if t.draining[serverName] {
+		return false
+	}
+	t.counts[serverName]++
+	return true
+}
+
+// claimIdle reserves serverName for an idle stop if it has no calls in
+// flight, holding new calls in acquire until releaseIdle. Returns false if
+// the server is busy or already claimed.
+func (t *inflightTracker) claimIdle(serverName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.counts[serverName] > 0 || t.stopping[serverName] {
+		return false
+	}
+	t.stopping[serverName] = true
+	return true
+}
+
+// releaseIdle lets calls held by claimIdle through.
+func (t *inflightTracker) releaseIdle(serverName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.stopping, serverName)
+	t.idle.Broadcast()
+}
+
+// release marks an in-flight call to serverName as finished.
+func (t *inflightTracker) release(serverName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts[serverName]--
+	if t.counts[serverName] <= 0 {
+		delete(t.counts, serverName)
+		t.idle.Broadcast()
+	}
+}
+
+// busy reports whether serverName has calls in flight.
+func (t *inflightTracker) busy(serverName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.counts[serverName] > 0
+}
+
+// startDrain rejects new calls to the given servers.
+func (t *inflightTracker) startDrain(serverNames []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, name := range serverNames {
+		t.draining[name] = true
+	}
+}
+
+// finishDrain accepts calls to the given servers again.
+func (t *inflightTracker) finishDrain(serverNames []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, name := range serverNames {
+		delete(t.draining, name)
+	}
+}
+
+// wait blocks until no calls to the given servers are in flight, the
+// timeout elapses, or ctx is cancelled. Returns the servers that still had
+// calls in flight.
+func (t *inflightTracker) wait(ctx context.Context, serverNames []string, timeout time.Duration) []string {
+	deadline := time.Now().Add(timeout)
+
+	// sync.Cond has no timed wait; wake waiters at the deadline or on
+	// cancellation instead.
+	wake := func() {
+		t.mu.Lock()
+		t.idle.Broadcast()
+		t.mu.Unlock()
+	}
+	timer := time.AfterFunc(timeout, wake)
+	defer timer.Stop()
+	stop := context.AfterFunc(ctx, wake)
+	defer stop()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for {
+		var busy []string
+		for _, name := range serverNames {
+			if t.counts[name] > 0 {
+				busy = append(busy, name)
+			}
+		}
+		if len(busy) == 0 || !time.Now().Before(deadline) || ctx.Err() != nil {
+			return busy
+		}
+		t.idle.Wait()
+	}
+}
+
+// staleServers returns running servers that must be stopped after a reload:
+// servers removed from the config, disabled, no longer in the active server
+// set, or whose connection settings changed. Everything else keeps its
+// running handle.
+func (s *Server) staleServers(oldCfg, newCfg *config.Config, activeServerNames []string) []string {
+	active := make(map[string]bool, len(activeServerNames))
+	for _, name := range activeServerNames {
+		active[name] = true
+	}
+
+	var stale []string
+	for _, name := range s.supervisor.RunningServers() {
+		newSrv, ok := newCfg.GetServer(name)
+		if !ok || !newSrv.IsEnabled() || !active[name] {
+			stale = append(stale, name)
+			continue
+		}
+		oldSrv, ok := oldCfg.GetServer(name)
+		if !ok || !oldSrv.SameConnection(newSrv) {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// drainAndStop waits for in-flight calls to the given servers to finish (up
+// to the configured drain timeout), then stops them. New calls are rejected
+// from the moment the drain starts. Runs in the background so requests to
+// other servers keep flowing during the drain.
+func (s *Server) drainAndStop(ctx context.Context, serverNames []string) {
+	timeout := s.opts.ReloadDrainTimeout
+	if timeout == 0 {
+		timeout = DefaultReloadDrainTimeout
+	}
+
+	if busy := s.inflight.wait(ctx, serverNames, timeout); len(busy) > 0 {
+		log.Printf("Reload drain timed out after %v, stopping servers with in-flight calls: %v", timeout, busy)
+	}
+
+	for _, name := range serverNames {
+		log.Printf("Stopping server %s after config reload", name)
+		if err := s.supervisor.Stop(name); err != nil {
+			log.Printf("Warning: failed to stop server %q: %v", name, err)
+		}
+	}
+	s.inflight.finishDrain(serverNames)
+
+	// Changed servers that are still active come back up with their new config
+	if s.opts.EagerStart && ctx.Err() == nil {
+		s.mu.RLock()
+		active := s.activeServerNames
+		s.mu.RUnlock()
+		s.startServers(ctx, slices.DeleteFunc(slices.Clone(serverNames), func(name string) bool {
+			return !slices.Contains(active, name)
+		}))
+	}
+}
@@ -4,6 +4,8 @@ package server
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // MCP JSON-RPC error codes
@@ -23,6 +25,8 @@ const (
 	ErrCodeNamespaceNotFound   = -32004
 	ErrCodeToolNotFound        = -32005
 	ErrCodeToolDenied          = -32006
+	ErrCodeRateLimited         = -32007
+	ErrCodeTooManyTools        = -32008
 )
 
 // RPCError represents a JSON-RPC 2.0 error.
@@ -68,6 +72,12 @@ func ErrInvalidParams(detail string) *RPCError {
 	return NewRPCError(ErrCodeInvalidParams, "Invalid params: "+detail, nil)
 }
 
+func ErrInvalidArguments(toolName string, problems []string) *RPCError {
+	return NewRPCError(ErrCodeInvalidParams,
+		fmt.Sprintf("Invalid params: arguments for %s do not match its input schema: %s", toolName, strings.Join(problems, "; ")),
+		map[string]any{"toolName": toolName, "errors": problems})
+}
+
 func ErrInternalError(detail string) *RPCError {
 	return NewRPCError(ErrCodeInternalError, "Internal error: "+detail, nil)
 }
@@ -88,6 +98,10 @@ func ErrServerNotRunning(serverID string) *RPCError {
 	return NewRPCError(ErrCodeServerNotRunning, fmt.Sprintf("Server not running: %s", serverID), map[string]string{"serverId": serverID})
 }
 
+func ErrServerDraining(serverID string) *RPCError {
+	return NewRPCError(ErrCodeServerNotRunning, fmt.Sprintf("Server restarting after config reload: %s", serverID), map[string]string{"serverId": serverID})
+}
+
 func ErrNamespaceNotFound(namespaceID string) *RPCError {
 	return NewRPCError(ErrCodeNamespaceNotFound, fmt.Sprintf("Namespace not found: %s", namespaceID), map[string]string{"namespaceId": namespaceID})
 }
@@ -99,3 +113,19 @@ func ErrToolNotFound(toolName string) *RPCError {
 func ErrToolDenied(toolName, reason string) *RPCError {
 	return NewRPCError(ErrCodeToolDenied, fmt.Sprintf("Tool denied: %s - %s", toolName, reason), map[string]string{"toolName": toolName, "reason": reason})
 }
+
+func ErrRateLimited(namespaceName string, retryAfter time.Duration) *RPCError {
+	return NewRPCError(ErrCodeRateLimited,
+		fmt.Sprintf("Rate limit exceeded for namespace %s, retry after %s", namespaceName, retryAfter.Round(time.Millisecond)),
+		map[string]any{"namespace": namespaceName, "retryAfterMs": retryAfter.Milliseconds()})
+}
+
+func ErrTooManyTools(namespaceName string, count, maxTools int) *RPCError {
+	scope := "the exposed servers"
+	if namespaceName != "" {
+		scope = fmt.Sprintf("namespace %s", namespaceName)
+	}
+	return NewRPCError(ErrCodeTooManyTools,
+		fmt.Sprintf("Too many tools: %s exposes %d tools, over the --max-tools limit of %d; deny tools or remove servers from it, or raise --max-tools", scope, count, maxTools),
+		map[string]any{"namespace": namespaceName, "count": count, "maxTools": maxTools})
+}
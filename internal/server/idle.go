@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// idleCheckInterval is how often serve mode looks for idle servers to stop.
+const idleCheckInterval = time.Second
+
+// idleTimeout returns how long a server may sit without requests before it
+// is stopped: its idleTimeoutSec, else --idle-timeout. Zero disables it.
+func (s *Server) idleTimeout(serverName string) time.Duration {
+	s.mu.RLock()
+	cfg := s.cfg
+	s.mu.RUnlock()
+
+	srv, ok := cfg.GetServer(serverName)
+	if !ok || srv.IsHTTP() {
+		return 0
+	}
+	if srv.IdleTimeoutSec > 0 {
+		return time.Duration(srv.IdleTimeoutSec) * time.Second
+	}
+	return s.opts.IdleTimeout
+}
+
+// watchIdle periodically stops stdio servers that have been idle for longer
+// than their idle timeout. They start again lazily on the next call.
+func (s *Server) watchIdle(ctx context.Context) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.stopIdleServers()
+		}
+	}
+}
+
+// stopIdleServers stops every running stdio server past its idle timeout
+// that has no calls in flight. Calls arriving meanwhile wait for the stop
+// and then start the server again, rather than racing it.
+func (s *Server) stopIdleServers() {
+	for _, name := range s.supervisor.RunningServers() {
+		timeout := s.idleTimeout(name)
+		if timeout <= 0 {
+			continue
+		}
+		if !s.inflight.claimIdle(name) {
+			continue
+		}
+		s.stopIfIdle(name, timeout)
+		s.inflight.releaseIdle(name)
+	}
+}
+
+// stopIfIdle stops a server claimed with claimIdle if it has been idle for
+// timeout.
+func (s *Server) stopIfIdle(name string, timeout time.Duration) {
+	handle := s.supervisor.Get(name)
+	if handle == nil || handle.IdleFor() < timeout {
+		return
+	}
+	log.Printf("Stopping server %s after %v idle", name, timeout)
+	if err := s.supervisor.Stop(name); err != nil {
+		log.Printf("Warning: failed to stop idle server %q: %v", name, err)
+	}
+}
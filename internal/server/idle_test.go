@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_IdleTimeoutStopsAndRestarts(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	srv := fakeServerConfig(t, map[string]any{
+		"tools":         []map[string]any{{"name": "query", "description": "Query"}},
+		"echoToolCalls": true,
+	})
+	srv.IdleTimeoutSec = 1
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"db": srv},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg})
+	running := func() bool {
+		return slices.Contains(h.srv.supervisor.RunningServers(), "db")
+	}
+
+	h.write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	h.write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"db.query","arguments":{}}}`)
+	h.settle(500 * time.Millisecond)
+	if !running() {
+		t.Fatal("expected db to be running after the first call")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for running() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected db to be stopped after its idle timeout")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	h.write(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"db.query","arguments":{}}}`)
+	h.settle(500 * time.Millisecond)
+	if !running() {
+		t.Error("expected db to restart on the next call")
+	}
+	h.close(t)
+
+	responses := parseResponsesByID(t, h.stdout.String())
+	for _, id := range []int{2, 3} {
+		var resp struct {
+			Error *RPCError `json:"error"`
+		}
+		if err := json.Unmarshal(responses[id], &resp); err != nil {
+			t.Fatalf("Unmarshal response %d: %v", id, err)
+		}
+		if resp.Error != nil {
+			t.Errorf("response %d: unexpected error %v", id, resp.Error)
+		}
+	}
+}
+
+func TestInflightTracker_ClaimIdleHoldsNewCalls(t *testing.T) {
+	t.Parallel()
+
+	tracker := newInflightTracker()
+	tracker.acquire("srv")
+	if tracker.claimIdle("srv") {
+		t.Fatal("claimIdle should fail while a call is in flight")
+	}
+	tracker.release("srv")
+
+	if !tracker.claimIdle("srv") {
+		t.Fatal("claimIdle should succeed with no calls in flight")
+	}
+	if tracker.claimIdle("srv") {
+		t.Error("claimIdle should fail while already claimed")
+	}
+
+	acquired := make(chan bool, 1)
+	go func() { acquired <- tracker.acquire("srv") }()
+	select {
+	case <-acquired:
+		t.Fatal("acquire should wait while the server is claimed for an idle stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if !tracker.acquire("other") {
+		t.Error("acquire should not wait for other servers")
+	}
+
+	tracker.releaseIdle("srv")
+	select {
+	case ok := <-acquired:
+		if !ok {
+			t.Error("acquire should succeed once the idle stop is released")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("acquire did not return after releaseIdle")
+	}
+	if !tracker.busy("srv") {
+		t.Error("expected the held call to be counted in flight")
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// inspectPrompt is printed before each line Inspect reads.
+const inspectPrompt = "mcpmu> "
+
+const inspectHelp = `Type an MCP method with optional JSON params, e.g.
+  tools/list
+  tools/call <name> [json arguments]
+  resources/read {"uri": "file:///tmp/a.txt"}
+  prompts/get {"name": "server.prompt", "arguments": {}}
+  ping
+help shows this, quit or Ctrl-D exits.
+`
+
+// Inspect runs an interactive session in place of the JSON-RPC protocol:
+// each line read from Stdin is a method and optional JSON params,
+// dispatched as a client's request would be, and the response is written
+// to Stdout pretty-printed. Notifications from the server are shown as
+// they happen. Inspect returns when Stdin is exhausted, on quit, or when
+// ctx is cancelled, stopping every upstream server.
+func (s *Server) Inspect(ctx context.Context) error {
+	defer s.shutdown()
+	defer s.handlersWG.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := s.writer
+	s.writer = &inspectNotifications{out: out}
+
+	params, _ := json.Marshal(initializeRequest{
+		ProtocolVersion: s.opts.ProtocolVersion,
+		ClientInfo:      clientInfo{Name: "mcpmu-inspect", Version: s.opts.ServerVersion},
+	})
+	if _, rpcErr := s.handleInitialize(ctx, params); rpcErr != nil {
+		return rpcErr
+	}
+	_ = s.handleNotification(ctx, "notifications/initialized", nil)
+
+	s.printInspect(out, "Connected to %s %s. Type help for commands.\n", s.opts.ServerName, s.opts.ServerVersion)
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := s.reader.ReadString('\n')
+			if line != "" {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		s.printInspect(out, "%s", inspectPrompt)
+		var line string
+		select {
+		case <-ctx.Done():
+			return nil
+		case l, ok := <-lines:
+			if !ok {
+				s.printInspect(out, "\n")
+				return nil
+			}
+			line = strings.TrimSpace(l)
+		}
+
+		switch line {
+		case "":
+			continue
+		case "help":
+			s.printInspect(out, "%s", inspectHelp)
+			continue
+		case "quit", "exit":
+			return nil
+		}
+		s.printInspect(out, "%s", s.inspectLine(ctx, line))
+	}
+}
+
+// inspectLine dispatches one REPL line and returns what to print.
+func (s *Server) inspectLine(ctx context.Context, line string) string {
+	method, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	var params json.RawMessage
+	if method == "tools/call" {
+		// tools/call <name> [json arguments]
+		name, args, _ := strings.Cut(rest, " ")
+		if name == "" {
+			return "Usage: tools/call <name> [json arguments]\n"
+		}
+		args = strings.TrimSpace(args)
+		if args == "" {
+			args = "{}"
+		}
+		if !json.Valid([]byte(args)) {
+			return "Invalid JSON arguments\n"
+		}
+		params, _ = json.Marshal(toolsCallRequest{Name: name, Arguments: json.RawMessage(args)})
+	} else if rest != "" {
+		if !json.Valid([]byte(rest)) {
+			return "Invalid JSON params\n"
+		}
+		params = json.RawMessage(rest)
+	}
+
+	if strings.HasPrefix(method, "notifications/") {
+		_ = s.handleNotification(ctx, method, params)
+		return "Sent " + method + "\n"
+	}
+
+	result, rpcErr := s.handleRequest(withCorrelationID(ctx, newCorrelationID()), method, params)
+	if rpcErr != nil {
+		msg := fmt.Sprintf("Error %d: %s\n", rpcErr.Code, rpcErr.Message)
+		if len(rpcErr.Data) > 0 {
+			msg += indentJSON(rpcErr.Data) + "\n"
+		}
+		return msg
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Sprintf("Failed to encode result: %v\n", err)
+	}
+	return indentJSON(data) + "\n"
+}
+
+// printInspect writes REPL output, serialized with notifications.
+func (s *Server) printInspect(out io.Writer, format string, args ...any) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	fmt.Fprintf(out, format, args...)
+}
+
+// indentJSON pretty-prints a JSON value, or returns it as is if it isn't
+// valid JSON.
+func indentJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+// inspectNotifications stands in for the client connection during Inspect,
+// printing each notification the server sends on a line of its own. send
+// writes each frame and its newline separately, under writeMu.
+type inspectNotifications struct {
+	out io.Writer
+}
+
+func (w *inspectNotifications) Write(p []byte) (int, error) {
+	var msg rpcMessage
+	if json.Unmarshal(p, &msg) != nil || msg.Method == "" {
+		return len(p), nil
+	}
+	if len(msg.Params) > 0 {
+		fmt.Fprintf(w.out, "\n← %s %s\n", msg.Method, msg.Params)
+	} else {
+		fmt.Fprintf(w.out, "\n← %s\n", msg.Method)
+	}
+	return len(p), nil
+}
@@ -5,14 +5,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
 	"github.com/Bigsy/mcpmu/internal/mcptest"
 )
 
@@ -148,26 +155,42 @@ func TestServer_ManagerTool_ServersList(t *testing.T) {
 	}
 }
 
-func TestServer_ManagerTool_NamespacesList(t *testing.T) {
+func TestServer_ManagerTool_ServersInfo(t *testing.T) {
 	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	disabled := false
+	stopped := fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "noop"}}})
+	stopped.Enabled = &disabled
 	cfg := &config.Config{
 		SchemaVersion: 1,
-		Servers:       map[string]config.ServerConfig{},
-		Namespaces: map[string]config.NamespaceConfig{
-			"work":     {Description: "Tools for work projects", ServerIDs: []string{"srv1"}},
-			"personal": {ServerIDs: []string{"srv2", "srv3"}},
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":      []map[string]any{{"name": "a1"}, {"name": "a2"}},
+				"serverInfo": map[string]any{"name": "alpha-server", "version": "2.3.4"},
+			}),
+			"beta": fakeServerConfig(t, map[string]any{
+				"tools":      []map[string]any{{"name": "b1"}},
+				"serverInfo": map[string]any{"name": "beta-server", "version": "0.9.0"},
+			}),
+			"gamma": stopped,
 		},
 	}
 
 	var stdout bytes.Buffer
-	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
-{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"mcpmu.namespaces_list","arguments":{}}}
-`)
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"mcpmu.servers_info","arguments":{}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
-		Namespace:       "work", // Select work namespace to pass init
+		AllNamespaces:   true,
+		EagerStart:      true,
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -179,62 +202,71 @@ func TestServer_ManagerTool_NamespacesList(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
-	}
-
 	var resp struct {
-		ID     int `json:"id"`
 		Result struct {
 			Content []struct {
-				Type string `json:"type"`
 				Text string `json:"text"`
 			} `json:"content"`
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-
-	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+	if err := json.Unmarshal(parseResponsesByID(t, stdout.String())[3], &resp); err != nil {
 		t.Fatalf("Unmarshal response: %v", err)
 	}
-
-	if resp.Error != nil {
-		t.Fatalf("Unexpected error: %v", resp.Error)
+	if resp.Error != nil || len(resp.Result.Content) == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
 	}
 
-	text := resp.Result.Content[0].Text
-	if !strings.Contains(text, "work") {
-		t.Errorf("Response should contain work namespace: %s", text)
+	var infos []UpstreamInfo
+	if err := json.Unmarshal([]byte(resp.Result.Content[0].Text), &infos); err != nil {
+		t.Fatalf("servers_info text is not JSON: %v: %s", err, resp.Result.Content[0].Text)
 	}
-	if !strings.Contains(text, "personal") {
-		t.Errorf("Response should contain personal namespace: %s", text)
+	want := []UpstreamInfo{
+		{Name: "alpha", ServerName: "alpha-server", ServerVersion: "2.3.4", ProtocolVersion: "2024-11-05", ToolCount: 2},
+		{Name: "beta", ServerName: "beta-server", ServerVersion: "0.9.0", ProtocolVersion: "2024-11-05", ToolCount: 1},
 	}
-	if !strings.Contains(text, "Tools for work projects") {
-		t.Errorf("Response should contain namespace description: %s", text)
+	if !reflect.DeepEqual(infos, want) {
+		t.Errorf("servers_info = %+v, want %+v", infos, want)
 	}
 }
 
-func TestServer_ToolsCall_UnknownTool(t *testing.T) {
+func TestServer_ManagerTool_Health(t *testing.T) {
 	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
-		Servers:       map[string]config.ServerConfig{},
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "a1"}, {"name": "a2"}},
+			}),
+			"broken": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: filepath.Join(t.TempDir(), "no-such-server"),
+			},
+		},
 	}
 
 	var stdout bytes.Buffer
-	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
-{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"unknown.tool","arguments":{}}}
-`)
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"mcpmu.health","arguments":{}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
+		AllNamespaces:   true,
+		EagerStart:      true,
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -246,60 +278,72 @@ func TestServer_ToolsCall_UnknownTool(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
-	}
-
 	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-
-	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+	if err := json.Unmarshal(parseResponsesByID(t, stdout.String())[3], &resp); err != nil {
 		t.Fatalf("Unmarshal response: %v", err)
 	}
+	if resp.Error != nil || len(resp.Result.Content) == 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
 
-	if resp.Error == nil {
-		t.Fatal("Expected error for unknown tool")
+	var report HealthReport
+	if err := json.Unmarshal([]byte(resp.Result.Content[0].Text), &report); err != nil {
+		t.Fatalf("health text is not JSON: %v: %s", err, resp.Result.Content[0].Text)
+	}
+	if report.Running != 1 || report.Total != 2 || len(report.Servers) != 2 {
+		t.Fatalf("expected 1 of 2 servers running, got %+v", report)
 	}
 
-	if resp.Error.Code != ErrCodeServerNotFound {
-		t.Errorf("Error code = %d, want %d (server not found)", resp.Error.Code, ErrCodeServerNotFound)
+	alpha, broken := report.Servers[0], report.Servers[1]
+	if alpha.Name != "alpha" || alpha.State != "running" || alpha.ToolCount != 2 || alpha.Uptime == "" || alpha.LastError != "" {
+		t.Errorf("unexpected alpha health: %+v", alpha)
+	}
+	if broken.Name != "broken" || broken.State != "error" || !strings.Contains(broken.LastError, "no-such-server") || broken.ToolCount != 0 {
+		t.Errorf("unexpected broken health: %+v", broken)
 	}
 }
 
-func TestServer_ToolsCall_PermissionDenied(t *testing.T) {
+func TestServer_ToolsList_PartialFailure(t *testing.T) {
 	t.Parallel()
-	enabled := true
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"srv1": {Kind: config.ServerKindStdio, Enabled: &enabled, Command: "echo"},
-		},
-		Namespaces: map[string]config.NamespaceConfig{
-			"restricted": {DenyByDefault: true, ServerIDs: []string{"srv1"}},
-		},
-		ToolPermissions: []config.ToolPermission{
-			{Namespace: "restricted", Server: "srv1", ToolName: "allowed_tool", Enabled: true},
-			{Namespace: "restricted", Server: "srv1", ToolName: "denied_tool", Enabled: false},
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "a1"}, {"name": "a2"}},
+			}),
+			"flaky": fakeServerConfig(t, map[string]any{
+				"tools":  []map[string]any{{"name": "f1"}},
+				"errors": map[string]any{"tools/list": map[string]any{"code": -32603, "message": "backend unavailable"}},
+			}),
 		},
 	}
 
 	var stdout bytes.Buffer
-	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
-{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.denied_tool","arguments":{}}}
-{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.unknown_tool","arguments":{}}}
-`)
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"mcpmu.health","arguments":{}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
-		Namespace:       "restricted",
+		AllNamespaces:   true,
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -311,68 +355,93 @@ func TestServer_ToolsCall_PermissionDenied(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 3 {
-		t.Fatalf("Expected 3 responses, got %d", len(lines))
-	}
+	responses := parseResponsesByID(t, stdout.String())
 
-	// Check explicitly denied tool
-	var resp2 struct {
-		ID    int       `json:"id"`
+	var list struct {
+		Result *struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(lines[1]), &resp2); err != nil {
-		t.Fatalf("Unmarshal response 2: %v", err)
+	if err := json.Unmarshal(responses[2], &list); err != nil {
+		t.Fatalf("Unmarshal tools/list response: %v", err)
 	}
-	if resp2.Error == nil {
-		t.Fatal("Expected error for explicitly denied tool")
+	if list.Error != nil || list.Result == nil {
+		t.Fatalf("tools/list should succeed despite one failing server: %s", responses[2])
 	}
-	if resp2.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Error code = %d, want %d (tool denied)", resp2.Error.Code, ErrCodeToolDenied)
+	var names []string
+	for _, tool := range list.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "alpha.a1,alpha.a2" {
+		t.Errorf("tools = %v, want alpha's tools only", names)
 	}
 
-	// Check tool denied by DenyByDefault
-	var resp3 struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	var call struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
 	}
-	if err := json.Unmarshal([]byte(lines[2]), &resp3); err != nil {
-		t.Fatalf("Unmarshal response 3: %v", err)
+	if err := json.Unmarshal(responses[3], &call); err != nil || len(call.Result.Content) == 0 {
+		t.Fatalf("unexpected health response: %s", responses[3])
 	}
-	if resp3.Error == nil {
-		t.Fatal("Expected error for tool denied by DenyByDefault")
+	var report HealthReport
+	if err := json.Unmarshal([]byte(call.Result.Content[0].Text), &report); err != nil {
+		t.Fatalf("health text is not JSON: %v", err)
 	}
-	if resp3.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Error code = %d, want %d (tool denied)", resp3.Error.Code, ErrCodeToolDenied)
+	if !reflect.DeepEqual(report.Degraded, []string{"flaky"}) {
+		t.Errorf("degraded = %v, want [flaky]", report.Degraded)
+	}
+	for _, health := range report.Servers {
+		switch health.Name {
+		case "alpha":
+			if health.ToolsError != "" {
+				t.Errorf("alpha should have no tools error, got %q", health.ToolsError)
+			}
+		case "flaky":
+			if !strings.Contains(health.ToolsError, "backend unavailable") {
+				t.Errorf("flaky toolsError = %q, want the tools/list failure", health.ToolsError)
+			}
+		}
 	}
 }
 
-func TestServer_ToolsCall_NoNamespace_AllowsAll(t *testing.T) {
+func TestServer_TraceOutput(t *testing.T) {
 	t.Parallel()
-	// When no namespaces are configured (selection=all), permission checks are bypassed
-	enabled := true
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"srv1": {Kind: config.ServerKindStdio, Enabled: &enabled, Command: "echo"},
+			"echo": fakeServerConfig(t, map[string]any{
+				"tools":         []map[string]any{{"name": "login"}},
+				"echoToolCalls": true,
+			}),
 		},
-		// No namespaces - should allow all tools
 	}
 
-	var stdout bytes.Buffer
-	// Call a tool - it should NOT be denied (though it may fail for other reasons like server not running)
-	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
-{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.any_tool","arguments":{}}}
-`)
+	var stdout, trace bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo.login","arguments":{"user":"alice","password":"hunter2"}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
+		AllNamespaces:   true,
+		EagerStart:      true,
+		TraceOutput:     &trace,
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -384,255 +453,261 @@ func TestServer_ToolsCall_NoNamespace_AllowsAll(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
+	if _, ok := parseResponsesByID(t, stdout.String())[2]; !ok {
+		t.Fatalf("no tools/call response: %s", stdout.String())
 	}
 
-	var resp struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
-		t.Fatalf("Unmarshal response: %v", err)
-	}
+	srv.traceMu.Lock()
+	out := trace.String()
+	srv.traceMu.Unlock()
 
-	// Should NOT be a permission denied error
-	// (It may fail for other reasons like server not running, which is fine)
-	if resp.Error != nil && resp.Error.Code == ErrCodeToolDenied {
-		t.Error("Expected tool to NOT be denied when no namespaces configured")
+	for _, want := range []string{
+		"→ echo",
+		"← echo",
+		`"method": "tools/call"`,
+		`"name": "login"`,
+		`"user": "alice"`,
+		`"password": "REDACTED"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"password": "hunter2"`) {
+		t.Errorf("trace output leaked a secret argument:\n%s", out)
 	}
 }
 
-// TestEndToEnd_WithRealBinary tests the full stdio server by spawning the actual binary.
-// This test requires building the binary first.
-func TestEndToEnd_WithRealBinary(t *testing.T) {
+func TestServer_ToolsList_DeterministicOrder(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
 		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Build the binary
-	tmpBin := t.TempDir() + "/mcpmu"
-	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("Failed to build binary: %v\n%s", err, output)
-	}
-
-	// Create a test config with no servers (to avoid loading real config)
-	tmpConfig := t.TempDir() + "/config.json"
 	cfg := &config.Config{
 		SchemaVersion: 1,
-		Servers:       map[string]config.ServerConfig{},
-		Namespaces:    map[string]config.NamespaceConfig{},
-	}
-	cfgData, _ := json.Marshal(cfg)
-	if err := os.WriteFile(tmpConfig, cfgData, 0644); err != nil {
-		t.Fatalf("Failed to write config: %v", err)
+		Servers: map[string]config.ServerConfig{
+			"zeta":  fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "walk"}, {"name": "apply"}}}),
+			"alpha": fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "zip"}, {"name": "get"}, {"name": "fetch"}}}),
+			"mid":   fakeServerConfig(t, map[string]any{"tools": []map[string]any{{"name": "only"}}}),
+		},
 	}
 
-	// Start the server
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	const calls = 3
+	input := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n"
+	for i := range calls {
+		input += fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/list"}`, i+2) + "\n"
+	}
 
-	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "error", "--expose-manager-tools")
-	stdin, err := serverCmd.StdinPipe()
+	var stdout bytes.Buffer
+	srv, err := New(Options{
+		Config:             cfg,
+		PIDTrackerDir:      t.TempDir(),
+		AllNamespaces:      true,
+		EagerStart:         true,
+		ExposeManagerTools: true,
+		Stdin:              strings.NewReader(input),
+		Stdout:             &stdout,
+		ServerName:         "mcpmu-test",
+		ServerVersion:      "1.0.0",
+		ProtocolVersion:    "2024-11-05",
+		LogLevel:           "error",
+	})
 	if err != nil {
-		t.Fatalf("StdinPipe: %v", err)
+		t.Fatalf("New: %v", err)
 	}
-	stdout, err := serverCmd.StdoutPipe()
-	if err != nil {
-		t.Fatalf("StdoutPipe: %v", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_ = srv.Run(ctx)
+
+	want := []string{
+		"alpha.fetch", "alpha.get", "alpha.zip",
+		"mid.only",
+		"zeta.apply", "zeta.walk",
 	}
-	stderr, err := serverCmd.StderrPipe()
-	if err != nil {
-		t.Fatalf("StderrPipe: %v", err)
+	for _, tool := range srv.aggregator.managerTools {
+		want = append(want, tool.Name)
 	}
 
-	// Drain stderr
-	go func() { _, _ = io.Copy(io.Discard, stderr) }()
+	responses := parseResponsesByID(t, stdout.String())
+	for i := range calls {
+		var resp struct {
+			Result struct {
+				Tools []struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(responses[i+2], &resp); err != nil {
+			t.Fatalf("Unmarshal tools/list %d: %v", i+1, err)
+		}
+		var names []string
+		for _, tool := range resp.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("tools/list call %d order:\n got %v\nwant %v", i+1, names, want)
+		}
+	}
+}
 
-	if err := serverCmd.Start(); err != nil {
-		t.Fatalf("Start: %v", err)
+func TestServer_ManagerTool_NamespacesList(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{},
+		Namespaces: map[string]config.NamespaceConfig{
+			"work":     {Description: "Tools for work projects", ServerIDs: []string{"srv1"}},
+			"personal": {ServerIDs: []string{"srv2", "srv3"}},
+		},
 	}
 
-	t.Cleanup(func() {
-		_ = stdin.Close()
-		_ = serverCmd.Process.Kill()
-		_ = serverCmd.Wait()
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"mcpmu.namespaces_list","arguments":{}}}
+`)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Namespace:       "work", // Select work namespace to pass init
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
 	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
 
-	// Use a buffered reader for proper NDJSON handling
-	reader := bufio.NewReader(stdout)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
 
-	// Send initialize request
-	initReq := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test-client","version":"1.0.0"}}}` + "\n"
-	if _, err := stdin.Write([]byte(initReq)); err != nil {
-		t.Fatalf("Write initialize: %v", err)
-	}
+	_ = srv.Run(ctx)
 
-	// Read response line
-	respLine, err := reader.ReadBytes('\n')
-	if err != nil {
-		t.Fatalf("Read response: %v", err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
 	}
 
 	var resp struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      int    `json:"id"`
-		Result  struct {
-			ProtocolVersion string `json:"protocolVersion"`
-			ServerInfo      struct {
-				Name    string `json:"name"`
-				Version string `json:"version"`
-			} `json:"serverInfo"`
+		ID     int `json:"id"`
+		Result struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
 
-	if err := json.Unmarshal(respLine, &resp); err != nil {
-		t.Fatalf("Unmarshal response: %v\nRaw: %s", err, string(respLine))
+	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v", err)
 	}
 
 	if resp.Error != nil {
 		t.Fatalf("Unexpected error: %v", resp.Error)
 	}
 
-	if resp.Result.ServerInfo.Name != "mcpmu" {
-		t.Errorf("ServerInfo.Name = %q, want %q", resp.Result.ServerInfo.Name, "mcpmu")
+	text := resp.Result.Content[0].Text
+	if !strings.Contains(text, "work") {
+		t.Errorf("Response should contain work namespace: %s", text)
+	}
+	if !strings.Contains(text, "personal") {
+		t.Errorf("Response should contain personal namespace: %s", text)
+	}
+	if !strings.Contains(text, "Tools for work projects") {
+		t.Errorf("Response should contain namespace description: %s", text)
 	}
+}
 
-	// Send tools/list request
-	toolsReq := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
-	if _, err := stdin.Write([]byte(toolsReq)); err != nil {
-		t.Fatalf("Write tools/list: %v", err)
+func TestServer_ToolsCall_UnknownTool(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{},
 	}
 
-	// Read tools/list response line
-	toolsLine, err := reader.ReadBytes('\n')
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"unknown.tool","arguments":{}}}
+`)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
 	if err != nil {
-		t.Fatalf("Read tools/list response: %v", err)
+		t.Fatalf("New: %v", err)
 	}
 
-	var toolsResp struct {
-		Result struct {
-			Tools []struct {
-				Name        string `json:"name"`
-				Description string `json:"description"`
-			} `json:"tools"`
-		} `json:"result"`
-		Error *RPCError `json:"error"`
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
 
-	if err := json.Unmarshal(toolsLine, &toolsResp); err != nil {
-		t.Fatalf("Unmarshal tools/list response: %v\nRaw: %s", err, string(toolsLine))
+	_ = srv.Run(ctx)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
 	}
 
-	if toolsResp.Error != nil {
-		t.Fatalf("Unexpected error: %v", toolsResp.Error)
+	var resp struct {
+		Error *RPCError `json:"error"`
 	}
 
-	// Should have manager tools
-	hasServersList := false
-	for _, tool := range toolsResp.Result.Tools {
-		if tool.Name == "mcpmu.servers_list" {
-			hasServersList = true
-			break
-		}
+	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v", err)
 	}
 
-	if !hasServersList {
-		t.Error("Expected mcpmu.servers_list tool in response")
+	if resp.Error == nil {
+		t.Fatal("Expected error for unknown tool")
 	}
 
-	t.Logf("End-to-end test passed! Found %d tools", len(toolsResp.Result.Tools))
+	if resp.Error.Code != ErrCodeServerNotFound {
+		t.Errorf("Error code = %d, want %d (server not found)", resp.Error.Code, ErrCodeServerNotFound)
+	}
 }
 
-// TestServer_NamespaceToolPermissions_EndToEnd tests the full flow:
-// 1. Two upstream servers with different tools
-// 2. A namespace with DenyByDefault=true
-// 3. Some tools explicitly allowed, some denied
-// 4. Verify tools/list shows only allowed tools (denied ones are filtered)
-// 5. Verify allowed tools can be called
-// 6. Verify denied tools return permission error
-func TestServer_NamespaceToolPermissions_EndToEnd(t *testing.T) {
+func TestServer_ToolsCall_PermissionDenied(t *testing.T) {
 	t.Parallel()
-	if testing.Short() {
-		t.Skip("Skipping end-to-end test in short mode")
-	}
-
-	// Create config with two fake servers in a restricted namespace
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"srv1": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read a file"},{"name":"write_file","description":"Write a file"},{"name":"delete_file","description":"Delete a file"}],"echoToolCalls":true}`,
-				},
-			},
-			"srv2": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"get_time","description":"Get current time"},{"name":"set_timezone","description":"Set timezone"}],"echoToolCalls":true}`,
-				},
-			},
+			"srv1": {Kind: config.ServerKindStdio, Enabled: &enabled, Command: "echo"},
 		},
 		Namespaces: map[string]config.NamespaceConfig{
-			"restricted": {
-				Description:   "Restricted namespace for testing",
-				DenyByDefault: true,
-				ServerIDs:     []string{"srv1", "srv2"},
-			},
+			"restricted": {DenyByDefault: true, ServerIDs: []string{"srv1"}},
 		},
 		ToolPermissions: []config.ToolPermission{
-			// Explicitly allow read_file from srv1
-			{Namespace: "restricted", Server: "srv1", ToolName: "read_file", Enabled: true},
-			// Explicitly deny delete_file from srv1
-			{Namespace: "restricted", Server: "srv1", ToolName: "delete_file", Enabled: false},
-			// Explicitly allow get_time from srv2
-			{Namespace: "restricted", Server: "srv2", ToolName: "get_time", Enabled: true},
-			// write_file and set_timezone have no explicit permission -> denied by DenyByDefault
+			{Namespace: "restricted", Server: "srv1", ToolName: "allowed_tool", Enabled: true},
+			{Namespace: "restricted", Server: "srv1", ToolName: "denied_tool", Enabled: false},
 		},
 	}
 
 	var stdout bytes.Buffer
-	stdin := strings.NewReader(
-		// Initialize
-		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			// List tools
-			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
-			// Call allowed tool (read_file)
-			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{"path":"/test"}}}` + "\n" +
-			// Call explicitly denied tool (delete_file)
-			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.delete_file","arguments":{"path":"/test"}}}` + "\n" +
-			// Call tool denied by DenyByDefault (write_file)
-			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"srv1.write_file","arguments":{"path":"/test","content":"hello"}}}` + "\n" +
-			// Call allowed tool from second server (get_time)
-			`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"srv2.get_time","arguments":{}}}` + "\n" +
-			// Call tool denied by DenyByDefault from second server (set_timezone)
-			`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"srv2.set_timezone","arguments":{"tz":"UTC"}}}` + "\n",
-	)
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.denied_tool","arguments":{}}}
+{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.unknown_tool","arguments":{}}}
+`)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
 		Namespace:       "restricted",
-		EagerStart:      false, // Use lazy start - servers start when tools are called
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -644,235 +719,1763 @@ func TestServer_NamespaceToolPermissions_EndToEnd(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
 	_ = srv.Run(ctx)
 
-	responses := parseResponsesByID(t, stdout.String())
-	if len(responses) < 7 {
-		t.Fatalf("Expected at least 7 responses, got %d:\n%s", len(responses), stdout.String())
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(lines))
 	}
 
-	// Response 1: Initialize - should succeed
-	var initResp struct {
-		ID     int       `json:"id"`
-		Result any       `json:"result"`
-		Error  *RPCError `json:"error"`
+	// Check explicitly denied tool
+	var resp2 struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(responses[1], &initResp); err != nil {
-		t.Fatalf("Unmarshal init response: %v", err)
+	if err := json.Unmarshal([]byte(lines[1]), &resp2); err != nil {
+		t.Fatalf("Unmarshal response 2: %v", err)
 	}
-	if initResp.Error != nil {
-		t.Fatalf("Initialize failed: %v", initResp.Error)
+	if resp2.Error == nil {
+		t.Fatal("Expected error for explicitly denied tool")
+	}
+	if resp2.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Error code = %d, want %d (tool denied)", resp2.Error.Code, ErrCodeToolDenied)
 	}
-	t.Log("Initialize: OK")
 
-	// Response 2: tools/list - should show only allowed tools (denied ones are filtered)
-	var toolsResp struct {
-		ID     int `json:"id"`
-		Result struct {
-			Tools []struct {
-				Name        string `json:"name"`
-				Description string `json:"description"`
-			} `json:"tools"`
-		} `json:"result"`
+	// Check tool denied by DenyByDefault
+	var resp3 struct {
+		ID    int       `json:"id"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(responses[2], &toolsResp); err != nil {
-		t.Fatalf("Unmarshal tools/list response: %v", err)
+	if err := json.Unmarshal([]byte(lines[2]), &resp3); err != nil {
+		t.Fatalf("Unmarshal response 3: %v", err)
 	}
-	if toolsResp.Error != nil {
-		t.Fatalf("tools/list failed: %v", toolsResp.Error)
+	if resp3.Error == nil {
+		t.Fatal("Expected error for tool denied by DenyByDefault")
 	}
-
-	// Verify we got only allowed tools (plus manager tools)
+	if resp3.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Error code = %d, want %d (tool denied)", resp3.Error.Code, ErrCodeToolDenied)
+	}
+}
+
+func TestServer_ToolsCall_NoNamespace_AllowsAll(t *testing.T) {
+	t.Parallel()
+	// When no namespaces are configured (selection=all), permission checks are bypassed
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {Kind: config.ServerKindStdio, Enabled: &enabled, Command: "echo"},
+		},
+		// No namespaces - should allow all tools
+	}
+
+	var stdout bytes.Buffer
+	// Call a tool - it should NOT be denied (though it may fail for other reasons like server not running)
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.any_tool","arguments":{}}}
+`)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
+	}
+
+	var resp struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v", err)
+	}
+
+	// Should NOT be a permission denied error
+	// (It may fail for other reasons like server not running, which is fine)
+	if resp.Error != nil && resp.Error.Code == ErrCodeToolDenied {
+		t.Error("Expected tool to NOT be denied when no namespaces configured")
+	}
+}
+
+// TestEndToEnd_WithRealBinary tests the full stdio server by spawning the actual binary.
+// This test requires building the binary first.
+func TestEndToEnd_WithRealBinary(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	// Build the binary
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	// Create a test config with no servers (to avoid loading real config)
+	tmpConfig := t.TempDir() + "/config.json"
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{},
+		Namespaces:    map[string]config.NamespaceConfig{},
+	}
+	cfgData, _ := json.Marshal(cfg)
+	if err := os.WriteFile(tmpConfig, cfgData, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// Start the server
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "error", "--expose-manager-tools")
+	stdin, err := serverCmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("StdinPipe: %v", err)
+	}
+	stdout, err := serverCmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe: %v", err)
+	}
+	stderr, err := serverCmd.StderrPipe()
+	if err != nil {
+		t.Fatalf("StderrPipe: %v", err)
+	}
+
+	// Drain stderr
+	go func() { _, _ = io.Copy(io.Discard, stderr) }()
+
+	if err := serverCmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = stdin.Close()
+		_ = serverCmd.Process.Kill()
+		_ = serverCmd.Wait()
+	})
+
+	// Use a buffered reader for proper NDJSON handling
+	reader := bufio.NewReader(stdout)
+
+	// Send initialize request
+	initReq := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test-client","version":"1.0.0"}}}` + "\n"
+	if _, err := stdin.Write([]byte(initReq)); err != nil {
+		t.Fatalf("Write initialize: %v", err)
+	}
+
+	// Read response line
+	respLine, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Read response: %v", err)
+	}
+
+	var resp struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      int    `json:"id"`
+		Result  struct {
+			ProtocolVersion string `json:"protocolVersion"`
+			ServerInfo      struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"serverInfo"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+
+	if err := json.Unmarshal(respLine, &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v\nRaw: %s", err, string(respLine))
+	}
+
+	if resp.Error != nil {
+		t.Fatalf("Unexpected error: %v", resp.Error)
+	}
+
+	if resp.Result.ServerInfo.Name != "mcpmu" {
+		t.Errorf("ServerInfo.Name = %q, want %q", resp.Result.ServerInfo.Name, "mcpmu")
+	}
+
+	// Send tools/list request
+	toolsReq := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+	if _, err := stdin.Write([]byte(toolsReq)); err != nil {
+		t.Fatalf("Write tools/list: %v", err)
+	}
+
+	// Read tools/list response line
+	toolsLine, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Read tools/list response: %v", err)
+	}
+
+	var toolsResp struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+
+	if err := json.Unmarshal(toolsLine, &toolsResp); err != nil {
+		t.Fatalf("Unmarshal tools/list response: %v\nRaw: %s", err, string(toolsLine))
+	}
+
+	if toolsResp.Error != nil {
+		t.Fatalf("Unexpected error: %v", toolsResp.Error)
+	}
+
+	// Should have manager tools
+	hasServersList := false
+	for _, tool := range toolsResp.Result.Tools {
+		if tool.Name == "mcpmu.servers_list" {
+			hasServersList = true
+			break
+		}
+	}
+
+	if !hasServersList {
+		t.Error("Expected mcpmu.servers_list tool in response")
+	}
+
+	t.Logf("End-to-end test passed! Found %d tools", len(toolsResp.Result.Tools))
+}
+
+// TestServer_NamespaceToolPermissions_EndToEnd tests the full flow:
+// 1. Two upstream servers with different tools
+// 2. A namespace with DenyByDefault=true
+// 3. Some tools explicitly allowed, some denied
+// 4. Verify tools/list shows only allowed tools (denied ones are filtered)
+// 5. Verify allowed tools can be called
+// 6. Verify denied tools return permission error
+func TestServer_NamespaceToolPermissions_EndToEnd(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	// Create config with two fake servers in a restricted namespace
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read a file"},{"name":"write_file","description":"Write a file"},{"name":"delete_file","description":"Delete a file"}],"echoToolCalls":true}`,
+				},
+			},
+			"srv2": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"get_time","description":"Get current time"},{"name":"set_timezone","description":"Set timezone"}],"echoToolCalls":true}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{
+			"restricted": {
+				Description:   "Restricted namespace for testing",
+				DenyByDefault: true,
+				ServerIDs:     []string{"srv1", "srv2"},
+			},
+		},
+		ToolPermissions: []config.ToolPermission{
+			// Explicitly allow read_file from srv1
+			{Namespace: "restricted", Server: "srv1", ToolName: "read_file", Enabled: true},
+			// Explicitly deny delete_file from srv1
+			{Namespace: "restricted", Server: "srv1", ToolName: "delete_file", Enabled: false},
+			// Explicitly allow get_time from srv2
+			{Namespace: "restricted", Server: "srv2", ToolName: "get_time", Enabled: true},
+			// write_file and set_timezone have no explicit permission -> denied by DenyByDefault
+		},
+	}
+
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		// Initialize
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			// List tools
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			// Call allowed tool (read_file)
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{"path":"/test"}}}` + "\n" +
+			// Call explicitly denied tool (delete_file)
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.delete_file","arguments":{"path":"/test"}}}` + "\n" +
+			// Call tool denied by DenyByDefault (write_file)
+			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"srv1.write_file","arguments":{"path":"/test","content":"hello"}}}` + "\n" +
+			// Call allowed tool from second server (get_time)
+			`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"srv2.get_time","arguments":{}}}` + "\n" +
+			// Call tool denied by DenyByDefault from second server (set_timezone)
+			`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"srv2.set_timezone","arguments":{"tz":"UTC"}}}` + "\n",
+	)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Namespace:       "restricted",
+		EagerStart:      false, // Use lazy start - servers start when tools are called
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	responses := parseResponsesByID(t, stdout.String())
+	if len(responses) < 7 {
+		t.Fatalf("Expected at least 7 responses, got %d:\n%s", len(responses), stdout.String())
+	}
+
+	// Response 1: Initialize - should succeed
+	var initResp struct {
+		ID     int       `json:"id"`
+		Result any       `json:"result"`
+		Error  *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[1], &initResp); err != nil {
+		t.Fatalf("Unmarshal init response: %v", err)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("Initialize failed: %v", initResp.Error)
+	}
+	t.Log("Initialize: OK")
+
+	// Response 2: tools/list - should show only allowed tools (denied ones are filtered)
+	var toolsResp struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[2], &toolsResp); err != nil {
+		t.Fatalf("Unmarshal tools/list response: %v", err)
+	}
+	if toolsResp.Error != nil {
+		t.Fatalf("tools/list failed: %v", toolsResp.Error)
+	}
+
+	// Verify we got only allowed tools (plus manager tools)
 	// Denied tools should be filtered: srv1.write_file (DenyByDefault), srv1.delete_file (explicit), srv2.set_timezone (DenyByDefault)
 	toolNames := make(map[string]bool)
 	for _, tool := range toolsResp.Result.Tools {
 		toolNames[tool.Name] = true
 	}
-	expectedTools := []string{
-		"srv1.read_file", // explicitly allowed
-		"srv2.get_time",  // explicitly allowed
+	expectedTools := []string{
+		"srv1.read_file", // explicitly allowed
+		"srv2.get_time",  // explicitly allowed
+	}
+	for _, name := range expectedTools {
+		if !toolNames[name] {
+			t.Errorf("tools/list missing expected tool %q", name)
+		}
+	}
+	// Verify denied tools are NOT in the list
+	deniedTools := []string{
+		"srv1.write_file",   // denied by DenyByDefault
+		"srv1.delete_file",  // explicitly denied
+		"srv2.set_timezone", // denied by DenyByDefault
+	}
+	for _, name := range deniedTools {
+		if toolNames[name] {
+			t.Errorf("tools/list should NOT contain denied tool %q", name)
+		}
+	}
+	t.Logf("tools/list: OK (%d tools, denied tools filtered)", len(toolsResp.Result.Tools))
+
+	// Response id=3: Call allowed tool (srv1.read_file)
+	// Note: This may fail with EOF due to known server connection management issues,
+	// but it should NOT fail with ErrCodeToolDenied (which would indicate permission problem)
+	var allowedResp struct {
+		ID     int       `json:"id"`
+		Result any       `json:"result"`
+		Error  *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[3], &allowedResp); err != nil {
+		t.Fatalf("Unmarshal allowed tool response: %v", err)
+	}
+	if allowedResp.Error != nil {
+		if allowedResp.Error.Code == ErrCodeToolDenied {
+			t.Errorf("Allowed tool (srv1.read_file) should NOT be denied, got: %v", allowedResp.Error)
+		} else {
+			// Connection errors (EOF) are known issues with server connection management
+			t.Logf("srv1.read_file (allowed): connection error (known issue): %v", allowedResp.Error)
+		}
+	} else {
+		t.Log("srv1.read_file (allowed): OK")
+	}
+
+	// Response id=4: Call explicitly denied tool (srv1.delete_file) - should fail
+	var deniedResp struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[4], &deniedResp); err != nil {
+		t.Fatalf("Unmarshal denied tool response: %v", err)
+	}
+	if deniedResp.Error == nil {
+		t.Error("Explicitly denied tool (srv1.delete_file) should fail")
+	} else if deniedResp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, deniedResp.Error.Code)
+	} else {
+		t.Log("srv1.delete_file (denied): correctly rejected")
+	}
+
+	// Response id=5: Call tool denied by DenyByDefault (srv1.write_file) - should fail
+	var defaultDeniedResp struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[5], &defaultDeniedResp); err != nil {
+		t.Fatalf("Unmarshal default-denied tool response: %v", err)
+	}
+	if defaultDeniedResp.Error == nil {
+		t.Error("Tool denied by DenyByDefault (srv1.write_file) should fail")
+	} else if defaultDeniedResp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, defaultDeniedResp.Error.Code)
+	} else {
+		t.Log("srv1.write_file (default-denied): correctly rejected")
+	}
+
+	// Response id=6: Call allowed tool from second server (srv2.get_time) - should succeed
+	var allowed2Resp struct {
+		ID     int       `json:"id"`
+		Result any       `json:"result"`
+		Error  *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[6], &allowed2Resp); err != nil {
+		t.Fatalf("Unmarshal srv2 allowed tool response: %v", err)
+	}
+	if allowed2Resp.Error != nil {
+		t.Errorf("Allowed tool (srv2.get_time) should succeed, got error: %v", allowed2Resp.Error)
+	} else {
+		t.Log("srv2.get_time (allowed): OK")
+	}
+
+	// Response id=7: Call tool denied by DenyByDefault from second server (srv2.set_timezone) - should fail
+	var defaultDenied2Resp struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[7], &defaultDenied2Resp); err != nil {
+		t.Fatalf("Unmarshal srv2 default-denied tool response: %v", err)
+	}
+	if defaultDenied2Resp.Error == nil {
+		t.Error("Tool denied by DenyByDefault (srv2.set_timezone) should fail")
+	} else if defaultDenied2Resp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, defaultDenied2Resp.Error.Code)
+	} else {
+		t.Log("srv2.set_timezone (default-denied): correctly rejected")
+	}
+
+	t.Log("End-to-end namespace tool permissions test passed!")
+}
+
+// TestServer_NamespaceServerDefaults_EndToEnd tests per-server deny-default:
+// - Namespace DenyByDefault=false (allow by default)
+// - srv1 has ServerDefaults["srv1"]=true (deny by default for srv1)
+// - srv2 has no server default (inherits namespace allow)
+// - Explicit allow for one srv1 tool
+// - Verify tools/list: srv1 tools denied except explicit allow, srv2 tools all visible
+// - Verify tools/call: denied srv1 tool returns permission error, srv2 tool succeeds
+func TestServer_NamespaceServerDefaults_EndToEnd(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read a file"},{"name":"write_file","description":"Write a file"},{"name":"delete_file","description":"Delete a file"}],"echoToolCalls":true}`,
+				},
+			},
+			"srv2": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"get_time","description":"Get current time"},{"name":"set_timezone","description":"Set timezone"}],"echoToolCalls":true}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{
+			"mixed": {
+				Description:    "Mixed server defaults",
+				DenyByDefault:  false, // namespace allows by default
+				ServerIDs:      []string{"srv1", "srv2"},
+				ServerDefaults: map[string]bool{"srv1": true}, // srv1 denies by default
+			},
+		},
+		ToolPermissions: []config.ToolPermission{
+			// Explicitly allow read_file from srv1 (overrides server default deny)
+			{Namespace: "mixed", Server: "srv1", ToolName: "read_file", Enabled: true},
+			// write_file and delete_file from srv1: no explicit permission -> server default deny
+			// get_time and set_timezone from srv2: no explicit permission -> namespace default allow
+		},
+	}
+
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			// Call allowed tool (srv1.read_file - explicitly allowed, overrides server default)
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{"path":"/test"}}}` + "\n" +
+			// Call tool denied by server default (srv1.write_file)
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.write_file","arguments":{"path":"/test","content":"hi"}}}` + "\n" +
+			// Call tool from srv2 (allowed, inherits namespace default)
+			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"srv2.get_time","arguments":{}}}` + "\n",
+	)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Namespace:       "mixed",
+		EagerStart:      false,
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	responses := parseResponsesByID(t, stdout.String())
+	if len(responses) < 5 {
+		t.Fatalf("Expected at least 5 responses, got %d:\n%s", len(responses), stdout.String())
+	}
+
+	// Response id=1: Initialize
+	var initResp struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[1], &initResp); err != nil {
+		t.Fatalf("Unmarshal init: %v", err)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("Initialize failed: %v", initResp.Error)
+	}
+
+	// Response id=2: tools/list - should show srv1.read_file (explicit allow) + all srv2 tools
+	var toolsResp struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[2], &toolsResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
+	}
+	if toolsResp.Error != nil {
+		t.Fatalf("tools/list failed: %v", toolsResp.Error)
+	}
+
+	toolNames := make(map[string]bool)
+	for _, tool := range toolsResp.Result.Tools {
+		toolNames[tool.Name] = true
+	}
+
+	// Should be visible
+	for _, name := range []string{"srv1.read_file", "srv2.get_time", "srv2.set_timezone"} {
+		if !toolNames[name] {
+			t.Errorf("tools/list missing expected tool %q", name)
+		}
+	}
+	// Should be denied (server default deny for srv1)
+	for _, name := range []string{"srv1.write_file", "srv1.delete_file"} {
+		if toolNames[name] {
+			t.Errorf("tools/list should NOT contain %q (denied by server default)", name)
+		}
+	}
+
+	// Response id=3: srv1.read_file (allowed) - should not get ErrCodeToolDenied
+	var resp3 struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[3], &resp3); err != nil {
+		t.Fatalf("Unmarshal resp3: %v", err)
+	}
+	if resp3.Error != nil && resp3.Error.Code == ErrCodeToolDenied {
+		t.Errorf("srv1.read_file should NOT be denied: %v", resp3.Error)
+	}
+
+	// Response id=4: srv1.write_file (denied by server default)
+	var resp4 struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[4], &resp4); err != nil {
+		t.Fatalf("Unmarshal resp4: %v", err)
+	}
+	if resp4.Error == nil {
+		t.Error("srv1.write_file should be denied by server default")
+	} else if resp4.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, resp4.Error.Code)
+	}
+
+	// Response id=5: srv2.get_time (allowed, inherits namespace default)
+	var resp5 struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[5], &resp5); err != nil {
+		t.Fatalf("Unmarshal resp5: %v", err)
+	}
+	if resp5.Error != nil {
+		t.Errorf("srv2.get_time should succeed (namespace allows): %v", resp5.Error)
+	}
+
+	t.Log("Server defaults end-to-end test passed!")
+}
+
+// TestServer_ProgressiveDiscovery verifies that:
+// 1. tools/list returns immediately with tools from fast servers only
+// 2. A notifications/tools/list_changed notification is sent when slow servers finish
+// 3. A second tools/list returns the complete tool set
+//
+// Uses an in-process server with a short grace period (100ms) so the slow server
+// (500ms tools/list delay) reliably exceeds it, guaranteeing the notification path
+// is exercised every run.
+func TestServer_ProgressiveDiscovery(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	// Config: one fast server, one slow server (500ms delay on tools/list)
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"fast-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"A fast tool"}],"echoToolCalls":true}`,
+				},
+			},
+			"slow-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					// 500ms delay on tools/list — exceeds the 100ms test grace period
+					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool","description":"A slow tool"}],"delays":{"tools/list":500000000},"echoToolCalls":true}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+
+	// Use pipes so we control the server lifecycle and can read output interactively
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdin pipe: %v", err)
+	}
+	defer func() { _ = stdinReader.Close() }()
+	defer func() { _ = stdinWriter.Close() }()
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	defer func() { _ = stdoutReader.Close() }()
+	defer func() { _ = stdoutWriter.Close() }()
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdinReader,
+		Stdout:          stdoutWriter,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Set a very short grace period so the slow server (500ms) always exceeds it
+	srv.listToolsGracePeriod = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Run server in background
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	// Helper: send a JSON-RPC request
+	send := func(msg string) {
+		t.Helper()
+		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	// Step 1: Initialize
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	// Give the server time to process
+	time.Sleep(100 * time.Millisecond)
+
+	// Use a goroutine to read stdout lines so we can interleave sends and reads
+	type lineResult struct {
+		data string
+		err  error
+	}
+	outLines := make(chan lineResult, 10)
+	outReader := bufio.NewReader(stdoutReader)
+	go func() {
+		for {
+			line, err := outReader.ReadString('\n')
+			if line != "" {
+				outLines <- lineResult{data: strings.TrimSpace(line)}
+			}
+			if err != nil {
+				outLines <- lineResult{err: err}
+				return
+			}
+		}
+	}()
+
+	readLine := func(timeout time.Duration) (string, error) {
+		select {
+		case r := <-outLines:
+			return r.data, r.err
+		case <-time.After(timeout):
+			return "", context.DeadlineExceeded
+		}
+	}
+
+	// Wait for init response
+	time.Sleep(100 * time.Millisecond)
+	initLine, err := readLine(2 * time.Second)
+	if err != nil {
+		t.Fatalf("Init read: %v", err)
+	}
+
+	var initResp struct {
+		Result struct {
+			Capabilities struct {
+				Tools struct {
+					ListChanged bool `json:"listChanged"`
+				} `json:"tools"`
+			} `json:"capabilities"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
+		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("Init error: %v", initResp.Error)
+	}
+	if !initResp.Result.Capabilities.Tools.ListChanged {
+		t.Error("Expected tools.listChanged=true in capabilities")
+	}
+
+	// Step 2: Send tools/list — grace period is 100ms, so the slow server (500ms) will be pending
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	// Read first tools/list response
+	toolsLine1, err := readLine(5 * time.Second)
+	if err != nil {
+		t.Fatalf("tools/list read: %v", err)
+	}
+
+	var toolsResp1 struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(toolsLine1), &toolsResp1); err != nil {
+		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine1)
+	}
+	if toolsResp1.Error != nil {
+		t.Fatalf("tools/list error: %v", toolsResp1.Error)
+	}
+
+	firstToolNames := make(map[string]bool)
+	for _, tool := range toolsResp1.Result.Tools {
+		firstToolNames[tool.Name] = true
+	}
+	t.Logf("First tools/list: %v", firstToolNames)
+
+	if !firstToolNames["fast-srv.fast_tool"] {
+		t.Error("Expected fast-srv.fast_tool in first tools/list")
+	}
+	if firstToolNames["slow-srv.slow_tool"] {
+		t.Fatal("slow-srv.slow_tool should NOT be in first tools/list (grace period too short)")
+	}
+
+	// Step 3: Wait for notifications/tools/list_changed from background discovery
+	notifLine, err := readLine(10 * time.Second)
+	if err != nil {
+		t.Fatalf("notification read: %v", err)
+	}
+	var notif struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal([]byte(notifLine), &notif); err != nil {
+		t.Fatalf("Parse notification: %v\nLine: %s", err, notifLine)
+	}
+	if notif.Method != "notifications/tools/list_changed" {
+		t.Fatalf("Expected notifications/tools/list_changed, got %q", notif.Method)
+	}
+	t.Log("Received notifications/tools/list_changed")
+
+	// Step 4: Send tools/list again — should now include slow server's tools
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+
+	toolsLine2, err := readLine(15 * time.Second)
+	if err != nil {
+		t.Fatalf("second tools/list read: %v", err)
+	}
+
+	var toolsResp2 struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(toolsLine2), &toolsResp2); err != nil {
+		t.Fatalf("Parse second tools/list: %v\nLine: %s", err, toolsLine2)
+	}
+	if toolsResp2.Error != nil {
+		t.Fatalf("second tools/list error: %v", toolsResp2.Error)
+	}
+
+	secondToolNames := make(map[string]bool)
+	for _, tool := range toolsResp2.Result.Tools {
+		secondToolNames[tool.Name] = true
+	}
+	t.Logf("Second tools/list: %v", secondToolNames)
+
+	if !secondToolNames["fast-srv.fast_tool"] {
+		t.Error("Expected fast-srv.fast_tool in second tools/list")
+	}
+	if !secondToolNames["slow-srv.slow_tool"] {
+		t.Error("Expected slow-srv.slow_tool in second tools/list after notification")
+	}
+
+	// Cleanup: close stdin to stop the server
+	_ = stdinWriter.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("Server did not stop")
+	}
+
+	t.Log("Progressive discovery test passed!")
+}
+
+// TestServer_ConcurrentServerInit verifies that multiple servers initialize
+// concurrently rather than sequentially. This catches mutex serialization bugs
+// in Supervisor.Start() where a global lock during initialization would cause
+// slow-starting servers to block fast ones.
+//
+// Setup:
+//   - 1 fast server (no init delay)
+//   - 2 slow servers (each with init delay exceeding the grace period)
+//   - Grace period shorter than a single slow server's init time
+//
+// If starts are serialized, the fast server would be blocked behind the slow
+// ones and no tools would be returned within the grace period.
+// If starts are concurrent, the fast server finishes independently and its
+// tools appear in the first tools/list response.
+func TestServer_ConcurrentServerInit(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"fast-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"A fast tool"}]}`,
+				},
+			},
+			"slow-srv-1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					// 800ms init delay — exceeds the 200ms grace period on its own
+					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool_1","description":"Slow tool 1"}],"delays":{"initialize":800000000}}`,
+				},
+			},
+			"slow-srv-2": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					// 800ms init delay
+					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool_2","description":"Slow tool 2"}],"delays":{"initialize":800000000}}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdin pipe: %v", err)
+	}
+	defer func() { _ = stdinReader.Close() }()
+	defer func() { _ = stdinWriter.Close() }()
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	defer func() { _ = stdoutReader.Close() }()
+	defer func() { _ = stdoutWriter.Close() }()
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdinReader,
+		Stdout:          stdoutWriter,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Grace period shorter than a single slow server's init.
+	// If starts are serialized (fast behind slow), fast would be blocked
+	// and this test would fail.
+	srv.listToolsGracePeriod = 200 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	send := func(msg string) {
+		t.Helper()
+		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	// Initialize
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	time.Sleep(100 * time.Millisecond)
+
+	type concLineResult struct {
+		data string
+		err  error
+	}
+	outLines := make(chan concLineResult, 10)
+	outReader := bufio.NewReader(stdoutReader)
+	go func() {
+		for {
+			line, err := outReader.ReadString('\n')
+			if line != "" {
+				outLines <- concLineResult{data: strings.TrimSpace(line)}
+			}
+			if err != nil {
+				outLines <- concLineResult{err: err}
+				return
+			}
+		}
+	}()
+
+	readLine := func(timeout time.Duration) (string, error) {
+		select {
+		case r := <-outLines:
+			return r.data, r.err
+		case <-time.After(timeout):
+			return "", context.DeadlineExceeded
+		}
+	}
+
+	// Read init response
+	initLine, err := readLine(2 * time.Second)
+	if err != nil {
+		t.Fatalf("Init read: %v", err)
+	}
+	var initResp struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
+		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("Init error: %v", initResp.Error)
+	}
+
+	// Send tools/list
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	toolsLine, err := readLine(5 * time.Second)
+	if err != nil {
+		t.Fatalf("tools/list read: %v", err)
+	}
+
+	var toolsResp struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(toolsLine), &toolsResp); err != nil {
+		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine)
+	}
+	if toolsResp.Error != nil {
+		t.Fatalf("tools/list error: %v", toolsResp.Error)
+	}
+
+	toolNames := make(map[string]bool)
+	for _, tool := range toolsResp.Result.Tools {
+		toolNames[tool.Name] = true
+	}
+	t.Logf("First tools/list returned: %v", toolNames)
+
+	// Key assertion: the fast server's tools MUST be present.
+	// With serialized starts, the slow servers would block the fast one
+	// and no tools would be returned within the 200ms grace period.
+	if !toolNames["fast-srv.fast_tool"] {
+		t.Fatal("fast-srv.fast_tool missing from first tools/list — " +
+			"concurrent server initialization may be broken (slow servers blocking fast ones)")
+	}
+
+	// Cleanup
+	_ = stdinWriter.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("Server did not stop")
+	}
+
+	t.Log("Concurrent server init test passed!")
+}
+
+// TestServer_SlowInitNotification is a realistic end-to-end test that verifies
+// the full progressive discovery cycle when a server's initialization exceeds
+// the grace period:
+//
+//  1. tools/list returns partial results (fast server only) within the grace period
+//  2. The slow-init server finishes in the background
+//  3. A notifications/tools/list_changed is sent promptly (not blocked by failures)
+//  4. A second tools/list returns the complete tool set
+//
+// This simulates the real scenario: e.g. playwright finishes fast, grafana/sentry
+// take longer to initialise, and kubernetes/atlassian are broken. The client
+// should learn about grafana/sentry as soon as they're ready, without waiting
+// for the broken servers to time out.
+func TestServer_SlowInitNotification(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"fast-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"Fast tool"}]}`,
+				},
+			},
+			"slow-init-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					// 400ms init delay — exceeds the 100ms grace period but finishes
+					// well within the 30s background timeout.
+					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_init_tool","description":"Slow init tool"}],"delays":{"initialize":400000000}}`,
+				},
+			},
+			"broken-srv": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					// Crash on init — simulates kubernetes/atlassian that never come up
+					"FAKE_MCP_CFG": `{"crashOnMethod":"initialize","crashExitCode":1}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdin pipe: %v", err)
+	}
+	defer func() { _ = stdinReader.Close() }()
+	defer func() { _ = stdinWriter.Close() }()
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("stdout pipe: %v", err)
+	}
+	defer func() { _ = stdoutReader.Close() }()
+	defer func() { _ = stdoutWriter.Close() }()
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdinReader,
+		Stdout:          stdoutWriter,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	// Grace period: fast-srv finishes, slow-init-srv (400ms init) does not,
+	// broken-srv crashes. Background picks up the stragglers.
+	srv.listToolsGracePeriod = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	send := func(msg string) {
+		t.Helper()
+		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	type notifLineResult struct {
+		data string
+		err  error
+	}
+	outLines := make(chan notifLineResult, 10)
+	go func() {
+		r := bufio.NewReader(stdoutReader)
+		for {
+			line, err := r.ReadString('\n')
+			if line != "" {
+				outLines <- notifLineResult{data: strings.TrimSpace(line)}
+			}
+			if err != nil {
+				outLines <- notifLineResult{err: err}
+				return
+			}
+		}
+	}()
+
+	readLine := func(timeout time.Duration) (string, error) {
+		select {
+		case r := <-outLines:
+			return r.data, r.err
+		case <-time.After(timeout):
+			return "", context.DeadlineExceeded
+		}
+	}
+
+	// Step 1: Initialize
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	time.Sleep(100 * time.Millisecond)
+
+	initLine, err := readLine(2 * time.Second)
+	if err != nil {
+		t.Fatalf("Init read: %v", err)
+	}
+	var initResp struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
+		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("Init error: %v", initResp.Error)
+	}
+
+	// Step 2: tools/list — grace period is 100ms, so slow-init-srv (400ms) will be pending
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+
+	toolsLine1, err := readLine(5 * time.Second)
+	if err != nil {
+		t.Fatalf("tools/list read: %v", err)
+	}
+	var toolsResp1 struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(toolsLine1), &toolsResp1); err != nil {
+		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine1)
+	}
+	if toolsResp1.Error != nil {
+		t.Fatalf("tools/list error: %v", toolsResp1.Error)
+	}
+
+	firstTools := make(map[string]bool)
+	for _, tool := range toolsResp1.Result.Tools {
+		firstTools[tool.Name] = true
+	}
+	t.Logf("First tools/list: %v", firstTools)
+
+	if !firstTools["fast-srv.fast_tool"] {
+		t.Fatal("fast-srv.fast_tool missing from first tools/list")
+	}
+	if firstTools["slow-init-srv.slow_init_tool"] {
+		t.Fatal("slow-init-srv.slow_init_tool should NOT be in first tools/list (grace period too short)")
+	}
+
+	// Step 3: Wait for notifications/tools/list_changed.
+	// The slow-init-srv takes 400ms — the notification should arrive within a
+	// few seconds, NOT after 30s (which would mean it's blocked by broken-srv).
+	start := time.Now()
+	notifLine, err := readLine(10 * time.Second)
+	notifLatency := time.Since(start)
+	if err != nil {
+		t.Fatalf("notification read: %v (waited %v — broken-srv may be blocking notification)", err, notifLatency)
 	}
-	for _, name := range expectedTools {
-		if !toolNames[name] {
-			t.Errorf("tools/list missing expected tool %q", name)
+	var notif struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal([]byte(notifLine), &notif); err != nil {
+		t.Fatalf("Parse notification: %v\nLine: %s", err, notifLine)
+	}
+	if notif.Method != "notifications/tools/list_changed" {
+		t.Fatalf("Expected notifications/tools/list_changed, got %q", notif.Method)
+	}
+	t.Logf("Received notifications/tools/list_changed after %v", notifLatency)
+
+	// The notification should arrive promptly (slow-init-srv finishes in ~400ms
+	// after the grace period). If it takes >5s, the notification is likely blocked
+	// by the broken server's retries/timeouts.
+	if notifLatency > 5*time.Second {
+		t.Errorf("Notification took %v — should arrive within a few seconds, not blocked by broken servers", notifLatency)
+	}
+
+	// Step 4: Second tools/list should include the slow-init-srv's tools
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+
+	toolsLine2, err := readLine(5 * time.Second)
+	if err != nil {
+		t.Fatalf("second tools/list read: %v", err)
+	}
+	var toolsResp2 struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(toolsLine2), &toolsResp2); err != nil {
+		t.Fatalf("Parse second tools/list: %v\nLine: %s", err, toolsLine2)
+	}
+	if toolsResp2.Error != nil {
+		t.Fatalf("second tools/list error: %v", toolsResp2.Error)
+	}
+
+	secondTools := make(map[string]bool)
+	for _, tool := range toolsResp2.Result.Tools {
+		secondTools[tool.Name] = true
+	}
+	t.Logf("Second tools/list: %v", secondTools)
+
+	if !secondTools["fast-srv.fast_tool"] {
+		t.Error("fast-srv.fast_tool missing from second tools/list")
+	}
+	if !secondTools["slow-init-srv.slow_init_tool"] {
+		t.Error("slow-init-srv.slow_init_tool missing from second tools/list after notification")
+	}
+
+	// Cleanup
+	_ = stdinWriter.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("Server did not stop")
+	}
+
+	t.Log("Slow init notification test passed!")
+}
+
+// TestServer_ReloadSendsToolsListChanged verifies that config reloads
+// send a notifications/tools/list_changed notification to the client.
+func TestServer_ReloadSendsToolsListChanged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	// Build the binary
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	// Create initial config with one server
+	tmpConfig := t.TempDir() + "/config.json"
+	enabled := true
+	initialCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+	if err := config.SaveTo(initialCfg, tmpConfig); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// Start the server
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "debug")
+	stdin, err := serverCmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("StdinPipe: %v", err)
+	}
+	stdout, err := serverCmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe: %v", err)
+	}
+	stderr, err := serverCmd.StderrPipe()
+	if err != nil {
+		t.Fatalf("StderrPipe: %v", err)
+	}
+
+	stderrBuf := &bytes.Buffer{}
+	stderrDone := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(stderrBuf, stderr)
+		close(stderrDone)
+	}()
+
+	if err := serverCmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = stdin.Close()
+		_ = serverCmd.Process.Kill()
+		_ = serverCmd.Wait()
+		<-stderrDone
+		if t.Failed() {
+			t.Logf("Server stderr:\n%s", stderrBuf.String())
+		}
+	})
+
+	reader := bufio.NewReader(stdout)
+
+	// Use a single reader goroutine to avoid races on bufio.Reader
+	type lineResult struct {
+		data []byte
+		err  error
+	}
+	lines := make(chan lineResult, 10)
+	go func() {
+		for {
+			line, err := reader.ReadBytes('\n')
+			lines <- lineResult{line, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(req string) {
+		t.Helper()
+		if _, err := stdin.Write([]byte(req + "\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	readMsg := func(timeout time.Duration) (json.RawMessage, error) {
+		select {
+		case r := <-lines:
+			return json.RawMessage(r.data), r.err
+		case <-time.After(timeout):
+			return nil, context.DeadlineExceeded
 		}
 	}
-	// Verify denied tools are NOT in the list
-	deniedTools := []string{
-		"srv1.write_file",   // denied by DenyByDefault
-		"srv1.delete_file",  // explicitly denied
-		"srv2.set_timezone", // denied by DenyByDefault
+
+	// Step 1: Initialize
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	initResp, err := readMsg(15 * time.Second)
+	if err != nil {
+		t.Fatalf("Initialize read: %v", err)
+	}
+	var initResult struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(initResp, &initResult); err != nil {
+		t.Fatalf("Parse init: %v", err)
+	}
+	if initResult.Error != nil {
+		t.Fatalf("Init error: %v", initResult.Error)
+	}
+	t.Log("Initialize: OK")
+
+	// Step 2: Get initial tools
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	toolsResp1, err := readMsg(15 * time.Second)
+	if err != nil {
+		t.Fatalf("tools/list read: %v", err)
+	}
+
+	var toolsResult1 struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(toolsResp1, &toolsResult1); err != nil {
+		t.Fatalf("Parse tools/list: %v", err)
+	}
+
+	initialToolCount := len(toolsResult1.Result.Tools)
+	t.Logf("Initial tools: %d", initialToolCount)
+
+	// Drain any pending notification from background discovery (if any)
+	drainedNotif, _ := readMsg(500 * time.Millisecond)
+	if drainedNotif != nil {
+		t.Log("Drained background discovery notification")
+	}
+
+	// Step 3: Modify config to add a second server
+	updatedCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+				},
+			},
+			"srv2": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_b","description":"Tool B"}],"echoToolCalls":true}`,
+				},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+	if err := config.SaveTo(updatedCfg, tmpConfig); err != nil {
+		t.Fatalf("Failed to save updated config: %v", err)
 	}
-	for _, name := range deniedTools {
-		if toolNames[name] {
-			t.Errorf("tools/list should NOT contain denied tool %q", name)
+	t.Log("Config updated: added srv2")
+
+	// Step 4: Read reload notifications (tools, resources, prompts list_changed).
+	// Drain all of them — order is not guaranteed.
+	gotToolsChanged := false
+	for range 3 {
+		notifMsg, err := readMsg(5 * time.Second)
+		if err != nil {
+			break // fewer notifications than expected is OK
+		}
+		var notif struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(notifMsg, &notif); err != nil {
+			t.Fatalf("Parse notification: %v", err)
+		}
+		t.Logf("Received %s after config reload", notif.Method)
+		if notif.Method == "notifications/tools/list_changed" {
+			gotToolsChanged = true
 		}
 	}
-	t.Logf("tools/list: OK (%d tools, denied tools filtered)", len(toolsResp.Result.Tools))
+	if !gotToolsChanged {
+		t.Error("Expected notifications/tools/list_changed among reload notifications")
+	}
 
-	// Response id=3: Call allowed tool (srv1.read_file)
-	// Note: This may fail with EOF due to known server connection management issues,
-	// but it should NOT fail with ErrCodeToolDenied (which would indicate permission problem)
-	var allowedResp struct {
-		ID     int       `json:"id"`
-		Result any       `json:"result"`
-		Error  *RPCError `json:"error"`
+	// Step 5: Send tools/list again to get updated tools
+	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	toolsResp2, err := readMsg(15 * time.Second)
+	if err != nil {
+		t.Fatalf("second tools/list read: %v", err)
 	}
-	if err := json.Unmarshal(responses[3], &allowedResp); err != nil {
-		t.Fatalf("Unmarshal allowed tool response: %v", err)
+
+	var toolsResult2 struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
 	}
-	if allowedResp.Error != nil {
-		if allowedResp.Error.Code == ErrCodeToolDenied {
-			t.Errorf("Allowed tool (srv1.read_file) should NOT be denied, got: %v", allowedResp.Error)
-		} else {
-			// Connection errors (EOF) are known issues with server connection management
-			t.Logf("srv1.read_file (allowed): connection error (known issue): %v", allowedResp.Error)
+
+	// Skip any notifications (resources/list_changed, prompts/list_changed, etc.)
+	// until we get the actual tools/list response (has a non-zero ID).
+	for {
+		if err := json.Unmarshal(toolsResp2, &toolsResult2); err != nil {
+			t.Fatalf("Parse second tools/list: %v", err)
+		}
+		if toolsResult2.ID != 0 {
+			break
+		}
+		t.Log("Skipping notification, reading next message")
+		toolsResp2, err = readMsg(15 * time.Second)
+		if err != nil {
+			t.Fatalf("reading after notification: %v", err)
 		}
-	} else {
-		t.Log("srv1.read_file (allowed): OK")
 	}
 
-	// Response id=4: Call explicitly denied tool (srv1.delete_file) - should fail
-	var deniedResp struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	if toolsResult2.Error != nil {
+		t.Fatalf("second tools/list error: %v", toolsResult2.Error)
 	}
-	if err := json.Unmarshal(responses[4], &deniedResp); err != nil {
-		t.Fatalf("Unmarshal denied tool response: %v", err)
+
+	toolNames := make(map[string]bool)
+	for _, tool := range toolsResult2.Result.Tools {
+		toolNames[tool.Name] = true
 	}
-	if deniedResp.Error == nil {
-		t.Error("Explicitly denied tool (srv1.delete_file) should fail")
-	} else if deniedResp.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, deniedResp.Error.Code)
-	} else {
-		t.Log("srv1.delete_file (denied): correctly rejected")
+	t.Logf("After reload tools/list: %d tools: %v", len(toolsResult2.Result.Tools), toolNames)
+
+	if !toolNames["srv1.tool_a"] {
+		t.Error("Expected srv1.tool_a after reload")
+	}
+	if !toolNames["srv2.tool_b"] {
+		t.Error("Expected srv2.tool_b after reload")
 	}
 
-	// Response id=5: Call tool denied by DenyByDefault (srv1.write_file) - should fail
-	var defaultDeniedResp struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	t.Log("Reload notification test passed!")
+}
+
+// ============================================================================
+// Global Deny Integration Tests
+// ============================================================================
+
+func TestServer_ToolsCall_GlobalDenyNoNamespace(t *testing.T) {
+	t.Parallel()
+	// No namespaces configured (selection=all), but server has deniedTools.
+	// The globally denied tool should still be blocked.
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:        config.ServerKindStdio,
+				Enabled:     &enabled,
+				Command:     "echo",
+				DeniedTools: []string{"dangerous_tool"},
+			},
+		},
+		// No namespaces
 	}
-	if err := json.Unmarshal(responses[5], &defaultDeniedResp); err != nil {
-		t.Fatalf("Unmarshal default-denied tool response: %v", err)
+
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.dangerous_tool","arguments":{}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.safe_tool","arguments":{}}}` + "\n",
+	)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
-	if defaultDeniedResp.Error == nil {
-		t.Error("Tool denied by DenyByDefault (srv1.write_file) should fail")
-	} else if defaultDeniedResp.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, defaultDeniedResp.Error.Code)
-	} else {
-		t.Log("srv1.write_file (default-denied): correctly rejected")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(lines))
 	}
 
-	// Response id=6: Call allowed tool from second server (srv2.get_time) - should succeed
-	var allowed2Resp struct {
-		ID     int       `json:"id"`
-		Result any       `json:"result"`
-		Error  *RPCError `json:"error"`
+	// Response 2: globally denied tool should be blocked
+	var resp2 struct {
+		ID    int       `json:"id"`
+		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(responses[6], &allowed2Resp); err != nil {
-		t.Fatalf("Unmarshal srv2 allowed tool response: %v", err)
+	if err := json.Unmarshal([]byte(lines[1]), &resp2); err != nil {
+		t.Fatalf("Unmarshal response 2: %v", err)
 	}
-	if allowed2Resp.Error != nil {
-		t.Errorf("Allowed tool (srv2.get_time) should succeed, got error: %v", allowed2Resp.Error)
-	} else {
-		t.Log("srv2.get_time (allowed): OK")
+	if resp2.Error == nil || resp2.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied for globally denied tool, got: %+v", resp2.Error)
 	}
 
-	// Response id=7: Call tool denied by DenyByDefault from second server (srv2.set_timezone) - should fail
-	var defaultDenied2Resp struct {
+	// Response 3: non-denied tool should NOT be tool-denied
+	var resp3 struct {
 		ID    int       `json:"id"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(responses[7], &defaultDenied2Resp); err != nil {
-		t.Fatalf("Unmarshal srv2 default-denied tool response: %v", err)
+	if err := json.Unmarshal([]byte(lines[2]), &resp3); err != nil {
+		t.Fatalf("Unmarshal response 3: %v", err)
 	}
-	if defaultDenied2Resp.Error == nil {
-		t.Error("Tool denied by DenyByDefault (srv2.set_timezone) should fail")
-	} else if defaultDenied2Resp.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, defaultDenied2Resp.Error.Code)
-	} else {
-		t.Log("srv2.set_timezone (default-denied): correctly rejected")
+	if resp3.Error != nil && resp3.Error.Code == ErrCodeToolDenied {
+		t.Error("Non-denied tool should NOT be blocked by global deny")
 	}
-
-	t.Log("End-to-end namespace tool permissions test passed!")
 }
 
-// TestServer_NamespaceServerDefaults_EndToEnd tests per-server deny-default:
-// - Namespace DenyByDefault=false (allow by default)
-// - srv1 has ServerDefaults["srv1"]=true (deny by default for srv1)
-// - srv2 has no server default (inherits namespace allow)
-// - Explicit allow for one srv1 tool
-// - Verify tools/list: srv1 tools denied except explicit allow, srv2 tools all visible
-// - Verify tools/call: denied srv1 tool returns permission error, srv2 tool succeeds
-func TestServer_NamespaceServerDefaults_EndToEnd(t *testing.T) {
+func TestServer_ToolsCall_GlobalDenyWithNamespaceAllow(t *testing.T) {
 	t.Parallel()
-	if testing.Short() {
-		t.Skip("Skipping end-to-end test in short mode")
-	}
-
+	// Namespace explicitly allows the tool, but it's in server deniedTools.
+	// Global deny should win.
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
 			"srv1": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read a file"},{"name":"write_file","description":"Write a file"},{"name":"delete_file","description":"Delete a file"}],"echoToolCalls":true}`,
-				},
-			},
-			"srv2": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"get_time","description":"Get current time"},{"name":"set_timezone","description":"Set timezone"}],"echoToolCalls":true}`,
-				},
+				Kind:        config.ServerKindStdio,
+				Enabled:     &enabled,
+				Command:     "echo",
+				DeniedTools: []string{"dangerous_tool"},
 			},
 		},
 		Namespaces: map[string]config.NamespaceConfig{
-			"mixed": {
-				Description:    "Mixed server defaults",
-				DenyByDefault:  false, // namespace allows by default
-				ServerIDs:      []string{"srv1", "srv2"},
-				ServerDefaults: map[string]bool{"srv1": true}, // srv1 denies by default
-			},
+			"ns1": {ServerIDs: []string{"srv1"}},
 		},
 		ToolPermissions: []config.ToolPermission{
-			// Explicitly allow read_file from srv1 (overrides server default deny)
-			{Namespace: "mixed", Server: "srv1", ToolName: "read_file", Enabled: true},
-			// write_file and delete_file from srv1: no explicit permission -> server default deny
-			// get_time and set_timezone from srv2: no explicit permission -> namespace default allow
+			{Namespace: "ns1", Server: "srv1", ToolName: "dangerous_tool", Enabled: true},
 		},
 	}
 
 	var stdout bytes.Buffer
 	stdin := strings.NewReader(
 		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
-			// Call allowed tool (srv1.read_file - explicitly allowed, overrides server default)
-			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{"path":"/test"}}}` + "\n" +
-			// Call tool denied by server default (srv1.write_file)
-			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.write_file","arguments":{"path":"/test","content":"hi"}}}` + "\n" +
-			// Call tool from srv2 (allowed, inherits namespace default)
-			`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"srv2.get_time","arguments":{}}}` + "\n",
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.dangerous_tool","arguments":{}}}` + "\n",
 	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
-		Namespace:       "mixed",
-		EagerStart:      false,
+		Namespace:       "ns1",
 		Stdin:           stdin,
 		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
@@ -884,265 +2487,90 @@ func TestServer_NamespaceServerDefaults_EndToEnd(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
 	_ = srv.Run(ctx)
 
-	responses := parseResponsesByID(t, stdout.String())
-	if len(responses) < 5 {
-		t.Fatalf("Expected at least 5 responses, got %d:\n%s", len(responses), stdout.String())
-	}
-
-	// Response id=1: Initialize
-	var initResp struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal(responses[1], &initResp); err != nil {
-		t.Fatalf("Unmarshal init: %v", err)
-	}
-	if initResp.Error != nil {
-		t.Fatalf("Initialize failed: %v", initResp.Error)
-	}
-
-	// Response id=2: tools/list - should show srv1.read_file (explicit allow) + all srv2 tools
-	var toolsResp struct {
-		ID     int `json:"id"`
-		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
-		} `json:"result"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal(responses[2], &toolsResp); err != nil {
-		t.Fatalf("Unmarshal tools/list: %v", err)
-	}
-	if toolsResp.Error != nil {
-		t.Fatalf("tools/list failed: %v", toolsResp.Error)
-	}
-
-	toolNames := make(map[string]bool)
-	for _, tool := range toolsResp.Result.Tools {
-		toolNames[tool.Name] = true
-	}
-
-	// Should be visible
-	for _, name := range []string{"srv1.read_file", "srv2.get_time", "srv2.set_timezone"} {
-		if !toolNames[name] {
-			t.Errorf("tools/list missing expected tool %q", name)
-		}
-	}
-	// Should be denied (server default deny for srv1)
-	for _, name := range []string{"srv1.write_file", "srv1.delete_file"} {
-		if toolNames[name] {
-			t.Errorf("tools/list should NOT contain %q (denied by server default)", name)
-		}
-	}
-
-	// Response id=3: srv1.read_file (allowed) - should not get ErrCodeToolDenied
-	var resp3 struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal(responses[3], &resp3); err != nil {
-		t.Fatalf("Unmarshal resp3: %v", err)
-	}
-	if resp3.Error != nil && resp3.Error.Code == ErrCodeToolDenied {
-		t.Errorf("srv1.read_file should NOT be denied: %v", resp3.Error)
-	}
-
-	// Response id=4: srv1.write_file (denied by server default)
-	var resp4 struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal(responses[4], &resp4); err != nil {
-		t.Fatalf("Unmarshal resp4: %v", err)
-	}
-	if resp4.Error == nil {
-		t.Error("srv1.write_file should be denied by server default")
-	} else if resp4.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied (%d), got %d", ErrCodeToolDenied, resp4.Error.Code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
 	}
 
-	// Response id=5: srv2.get_time (allowed, inherits namespace default)
-	var resp5 struct {
+	var resp struct {
 		ID    int       `json:"id"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(responses[5], &resp5); err != nil {
-		t.Fatalf("Unmarshal resp5: %v", err)
+	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
+		t.Fatalf("Unmarshal response: %v", err)
 	}
-	if resp5.Error != nil {
-		t.Errorf("srv2.get_time should succeed (namespace allows): %v", resp5.Error)
+	if resp.Error == nil || resp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected ErrCodeToolDenied even with namespace allow, got: %+v", resp.Error)
 	}
-
-	t.Log("Server defaults end-to-end test passed!")
 }
 
-// TestServer_ProgressiveDiscovery verifies that:
-// 1. tools/list returns immediately with tools from fast servers only
-// 2. A notifications/tools/list_changed notification is sent when slow servers finish
-// 3. A second tools/list returns the complete tool set
-//
-// Uses an in-process server with a short grace period (100ms) so the slow server
-// (500ms tools/list delay) reliably exceeds it, guaranteeing the notification path
-// is exercised every run.
-func TestServer_ProgressiveDiscovery(t *testing.T) {
+func TestServer_ToolsList_GlobalDenyFiltering(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Config: one fast server, one slow server (500ms delay on tools/list)
+	// Use a real fake server with tools including a globally denied one
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"fast-srv": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"A fast tool"}],"echoToolCalls":true}`,
-				},
-			},
-			"slow-srv": {
+			"srv1": {
 				Kind:    config.ServerKindStdio,
 				Enabled: &enabled,
 				Command: os.Args[0],
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					// 500ms delay on tools/list — exceeds the 100ms test grace period
-					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool","description":"A slow tool"}],"delays":{"tools/list":500000000},"echoToolCalls":true}`,
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
 				},
+				DeniedTools: []string{"delete_file"},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{},
-	}
-
-	// Use pipes so we control the server lifecycle and can read output interactively
-	stdinReader, stdinWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdin pipe: %v", err)
+		Namespaces: map[string]config.NamespaceConfig{
+			"ns1": {ServerIDs: []string{"srv1"}},
+		},
 	}
-	defer func() { _ = stdinReader.Close() }()
-	defer func() { _ = stdinWriter.Close() }()
 
-	stdoutReader, stdoutWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdout pipe: %v", err)
-	}
-	defer func() { _ = stdoutReader.Close() }()
-	defer func() { _ = stdoutWriter.Close() }()
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+	)
 
 	srv, err := New(Options{
-		Config:          cfg,
-		PIDTrackerDir:   t.TempDir(),
-		Stdin:           stdinReader,
-		Stdout:          stdoutWriter,
-		ServerName:      "mcpmu-test",
-		ServerVersion:   "1.0.0",
-		ProtocolVersion: "2024-11-05",
-		LogLevel:        "error",
+		Config:             cfg,
+		PIDTrackerDir:      t.TempDir(),
+		Namespace:          "ns1",
+		EagerStart:         true,
+		ExposeManagerTools: true,
+		Stdin:              stdin,
+		Stdout:             &stdout,
+		ServerName:         "mcpmu-test",
+		ServerVersion:      "1.0.0",
+		ProtocolVersion:    "2024-11-05",
+		LogLevel:           "error",
 	})
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
-	// Set a very short grace period so the slow server (500ms) always exceeds it
-	srv.listToolsGracePeriod = 100 * time.Millisecond
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Run server in background
-	done := make(chan error, 1)
-	go func() { done <- srv.Run(ctx) }()
-
-	// Helper: send a JSON-RPC request
-	send := func(msg string) {
-		t.Helper()
-		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
-			t.Fatalf("Write: %v", err)
-		}
-	}
-
-	// Step 1: Initialize
-	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
-	// Give the server time to process
-	time.Sleep(100 * time.Millisecond)
-
-	// Use a goroutine to read stdout lines so we can interleave sends and reads
-	type lineResult struct {
-		data string
-		err  error
-	}
-	outLines := make(chan lineResult, 10)
-	outReader := bufio.NewReader(stdoutReader)
-	go func() {
-		for {
-			line, err := outReader.ReadString('\n')
-			if line != "" {
-				outLines <- lineResult{data: strings.TrimSpace(line)}
-			}
-			if err != nil {
-				outLines <- lineResult{err: err}
-				return
-			}
-		}
-	}()
-
-	readLine := func(timeout time.Duration) (string, error) {
-		select {
-		case r := <-outLines:
-			return r.data, r.err
-		case <-time.After(timeout):
-			return "", context.DeadlineExceeded
-		}
-	}
-
-	// Wait for init response
-	time.Sleep(100 * time.Millisecond)
-	initLine, err := readLine(2 * time.Second)
-	if err != nil {
-		t.Fatalf("Init read: %v", err)
-	}
-
-	var initResp struct {
-		Result struct {
-			Capabilities struct {
-				Tools struct {
-					ListChanged bool `json:"listChanged"`
-				} `json:"tools"`
-			} `json:"capabilities"`
-		} `json:"result"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
-		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
-	}
-	if initResp.Error != nil {
-		t.Fatalf("Init error: %v", initResp.Error)
-	}
-	if !initResp.Result.Capabilities.Tools.ListChanged {
-		t.Error("Expected tools.listChanged=true in capabilities")
-	}
 
-	// Step 2: Send tools/list — grace period is 100ms, so the slow server (500ms) will be pending
-	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	// Read first tools/list response
-	toolsLine1, err := readLine(5 * time.Second)
-	if err != nil {
-		t.Fatalf("tools/list read: %v", err)
+	_ = srv.Run(ctx)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
 	}
 
-	var toolsResp1 struct {
+	var toolsResp struct {
 		ID     int `json:"id"`
 		Result struct {
 			Tools []struct {
@@ -1151,169 +2579,183 @@ func TestServer_ProgressiveDiscovery(t *testing.T) {
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(toolsLine1), &toolsResp1); err != nil {
-		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine1)
+	if err := json.Unmarshal([]byte(lines[1]), &toolsResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
 	}
-	if toolsResp1.Error != nil {
-		t.Fatalf("tools/list error: %v", toolsResp1.Error)
+	if toolsResp.Error != nil {
+		t.Fatalf("tools/list error: %v", toolsResp.Error)
 	}
 
-	firstToolNames := make(map[string]bool)
-	for _, tool := range toolsResp1.Result.Tools {
-		firstToolNames[tool.Name] = true
+	toolNames := make(map[string]bool)
+	for _, tool := range toolsResp.Result.Tools {
+		toolNames[tool.Name] = true
 	}
-	t.Logf("First tools/list: %v", firstToolNames)
 
-	if !firstToolNames["fast-srv.fast_tool"] {
-		t.Error("Expected fast-srv.fast_tool in first tools/list")
-	}
-	if firstToolNames["slow-srv.slow_tool"] {
-		t.Fatal("slow-srv.slow_tool should NOT be in first tools/list (grace period too short)")
+	// read_file should be present
+	if !toolNames["srv1.read_file"] {
+		t.Error("Expected srv1.read_file in tools/list")
 	}
 
-	// Step 3: Wait for notifications/tools/list_changed from background discovery
-	notifLine, err := readLine(10 * time.Second)
-	if err != nil {
-		t.Fatalf("notification read: %v", err)
-	}
-	var notif struct {
-		Method string `json:"method"`
+	// delete_file should be filtered (globally denied)
+	if toolNames["srv1.delete_file"] {
+		t.Error("Expected srv1.delete_file to be filtered from tools/list (globally denied)")
 	}
-	if err := json.Unmarshal([]byte(notifLine), &notif); err != nil {
-		t.Fatalf("Parse notification: %v\nLine: %s", err, notifLine)
+
+	// Manager tools should still be present
+	if !toolNames["mcpmu.servers_list"] {
+		t.Error("Expected mcpmu.servers_list in tools/list (manager tools should survive filtering)")
 	}
-	if notif.Method != "notifications/tools/list_changed" {
-		t.Fatalf("Expected notifications/tools/list_changed, got %q", notif.Method)
+}
+
+func TestServer_ToolsList_ShowDeniedTools(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
-	t.Log("Received notifications/tools/list_changed")
 
-	// Step 4: Send tools/list again — should now include slow server's tools
-	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+	tests := []struct {
+		name       string
+		nsShow     bool
+		optShow    bool
+		wantListed bool
+	}{
+		{name: "hidden by default"},
+		{name: "namespace setting", nsShow: true, wantListed: true},
+		{name: "serve option", optShow: true, wantListed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{
+				SchemaVersion: 1,
+				Servers: map[string]config.ServerConfig{
+					"srv1": fakeServerConfig(t, map[string]any{
+						"tools": []map[string]any{
+							{"name": "read_file", "description": "Read"},
+							{"name": "delete_file", "description": "Delete"},
+						},
+						"echoToolCalls": true,
+					}),
+				},
+				Namespaces: map[string]config.NamespaceConfig{
+					"ns1": {ServerIDs: []string{"srv1"}, ShowDeniedTools: tt.nsShow},
+				},
+				ToolPermissions: []config.ToolPermission{
+					{Namespace: "ns1", Server: "srv1", ToolName: "delete_file", Enabled: false},
+				},
+			}
 
-	toolsLine2, err := readLine(15 * time.Second)
-	if err != nil {
-		t.Fatalf("second tools/list read: %v", err)
-	}
+			var stdout bytes.Buffer
+			stdin := strings.NewReader(
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+					`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+					`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.delete_file","arguments":{}}}` + "\n",
+			)
+
+			srv, err := New(Options{
+				Config:          cfg,
+				PIDTrackerDir:   t.TempDir(),
+				Namespace:       "ns1",
+				EagerStart:      true,
+				ShowDeniedTools: tt.optShow,
+				Stdin:           stdin,
+				Stdout:          &stdout,
+				ServerName:      "mcpmu-test",
+				ServerVersion:   "1.0.0",
+				ProtocolVersion: "2024-11-05",
+				LogLevel:        "error",
+			})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
 
-	var toolsResp2 struct {
-		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
-		} `json:"result"`
-		Error *RPCError `json:"error"`
-	}
-	if err := json.Unmarshal([]byte(toolsLine2), &toolsResp2); err != nil {
-		t.Fatalf("Parse second tools/list: %v\nLine: %s", err, toolsLine2)
-	}
-	if toolsResp2.Error != nil {
-		t.Fatalf("second tools/list error: %v", toolsResp2.Error)
-	}
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			_ = srv.Run(ctx)
 
-	secondToolNames := make(map[string]bool)
-	for _, tool := range toolsResp2.Result.Tools {
-		secondToolNames[tool.Name] = true
-	}
-	t.Logf("Second tools/list: %v", secondToolNames)
+			responses := parseResponsesByID(t, stdout.String())
 
-	if !secondToolNames["fast-srv.fast_tool"] {
-		t.Error("Expected fast-srv.fast_tool in second tools/list")
-	}
-	if !secondToolNames["slow-srv.slow_tool"] {
-		t.Error("Expected slow-srv.slow_tool in second tools/list after notification")
-	}
+			var toolsResp struct {
+				Result struct {
+					Tools []struct {
+						Name        string `json:"name"`
+						Description string `json:"description"`
+					} `json:"tools"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(responses[2], &toolsResp); err != nil {
+				t.Fatalf("Unmarshal tools/list: %v", err)
+			}
+			descriptions := make(map[string]string)
+			for _, tool := range toolsResp.Result.Tools {
+				descriptions[tool.Name] = tool.Description
+			}
 
-	// Cleanup: close stdin to stop the server
-	_ = stdinWriter.Close()
-	select {
-	case <-done:
-	case <-time.After(5 * time.Second):
-		cancel()
-		t.Fatal("Server did not stop")
-	}
+			if desc := descriptions["srv1.read_file"]; strings.HasPrefix(desc, "[denied]") {
+				t.Errorf("allowed tool marked denied: %q", desc)
+			}
+			desc, listed := descriptions["srv1.delete_file"]
+			if listed != tt.wantListed {
+				t.Fatalf("srv1.delete_file listed = %v, want %v (tools: %v)", listed, tt.wantListed, descriptions)
+			}
+			if listed && desc != "[denied] [srv1] Delete" {
+				t.Errorf("denied tool description = %q, want [denied] marker", desc)
+			}
 
-	t.Log("Progressive discovery test passed!")
+			// Listed or not, calling it is rejected
+			var callResp struct {
+				Error *RPCError `json:"error"`
+			}
+			if err := json.Unmarshal(responses[3], &callResp); err != nil {
+				t.Fatalf("Unmarshal tools/call: %v", err)
+			}
+			if callResp.Error == nil || callResp.Error.Code != ErrCodeToolDenied {
+				t.Errorf("tools/call error = %+v, want code %d", callResp.Error, ErrCodeToolDenied)
+			}
+		})
+	}
 }
 
-// TestServer_ConcurrentServerInit verifies that multiple servers initialize
-// concurrently rather than sequentially. This catches mutex serialization bugs
-// in Supervisor.Start() where a global lock during initialization would cause
-// slow-starting servers to block fast ones.
-//
-// Setup:
-//   - 1 fast server (no init delay)
-//   - 2 slow servers (each with init delay exceeding the grace period)
-//   - Grace period shorter than a single slow server's init time
-//
-// If starts are serialized, the fast server would be blocked behind the slow
-// ones and no tools would be returned within the grace period.
-// If starts are concurrent, the fast server finishes independently and its
-// tools appear in the first tools/list response.
-func TestServer_ConcurrentServerInit(t *testing.T) {
+func TestServer_ToolPrefix_ListAndCall(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"fast-srv": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"A fast tool"}]}`,
-				},
-			},
-			"slow-srv-1": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					// 800ms init delay — exceeds the 200ms grace period on its own
-					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool_1","description":"Slow tool 1"}],"delays":{"initialize":800000000}}`,
-				},
-			},
-			"slow-srv-2": {
+			"filesystem": {
 				Kind:    config.ServerKindStdio,
 				Enabled: &enabled,
 				Command: os.Args[0],
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					// 800ms init delay
-					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_tool_2","description":"Slow tool 2"}],"delays":{"initialize":800000000}}`,
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
 				},
+				DeniedTools: []string{"delete_file"},
+				ToolPrefix:  "fs",
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{},
-	}
-
-	stdinReader, stdinWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdin pipe: %v", err)
 	}
-	defer func() { _ = stdinReader.Close() }()
-	defer func() { _ = stdinWriter.Close() }()
 
-	stdoutReader, stdoutWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdout pipe: %v", err)
-	}
-	defer func() { _ = stdoutReader.Close() }()
-	defer func() { _ = stdoutWriter.Close() }()
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fs.read_file","arguments":{"path":"/tmp"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"fs.delete_file","arguments":{}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
-		Stdin:           stdinReader,
-		Stdout:          stdoutWriter,
+		EagerStart:      true,
+		Stdin:           stdin,
+		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
 		ServerVersion:   "1.0.0",
 		ProtocolVersion: "2024-11-05",
@@ -1322,199 +2764,118 @@ func TestServer_ConcurrentServerInit(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
-	// Grace period shorter than a single slow server's init.
-	// If starts are serialized (fast behind slow), fast would be blocked
-	// and this test would fail.
-	srv.listToolsGracePeriod = 200 * time.Millisecond
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	done := make(chan error, 1)
-	go func() { done <- srv.Run(ctx) }()
-
-	send := func(msg string) {
-		t.Helper()
-		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
-			t.Fatalf("Write: %v", err)
-		}
-	}
-
-	// Initialize
-	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
-	time.Sleep(100 * time.Millisecond)
-
-	type concLineResult struct {
-		data string
-		err  error
-	}
-	outLines := make(chan concLineResult, 10)
-	outReader := bufio.NewReader(stdoutReader)
-	go func() {
-		for {
-			line, err := outReader.ReadString('\n')
-			if line != "" {
-				outLines <- concLineResult{data: strings.TrimSpace(line)}
-			}
-			if err != nil {
-				outLines <- concLineResult{err: err}
-				return
-			}
-		}
-	}()
+	_ = srv.Run(ctx)
 
-	readLine := func(timeout time.Duration) (string, error) {
-		select {
-		case r := <-outLines:
-			return r.data, r.err
-		case <-time.After(timeout):
-			return "", context.DeadlineExceeded
-		}
-	}
+	responses := parseResponsesByID(t, stdout.String())
 
-	// Read init response
-	initLine, err := readLine(2 * time.Second)
-	if err != nil {
-		t.Fatalf("Init read: %v", err)
-	}
-	var initResp struct {
+	var listResp struct {
+		Result struct {
+			Tools []struct {
+				Name        string `json:"name"`
+				Description string `json:"description"`
+			} `json:"tools"`
+		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
-		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
+	if err := json.Unmarshal(responses[2], &listResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
 	}
-	if initResp.Error != nil {
-		t.Fatalf("Init error: %v", initResp.Error)
+	if listResp.Error != nil {
+		t.Fatalf("tools/list error: %v", listResp.Error)
 	}
-
-	// Send tools/list
-	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
-
-	toolsLine, err := readLine(5 * time.Second)
-	if err != nil {
-		t.Fatalf("tools/list read: %v", err)
+	toolDescs := make(map[string]string)
+	for _, tool := range listResp.Result.Tools {
+		toolDescs[tool.Name] = tool.Description
+	}
+	if desc, ok := toolDescs["fs.read_file"]; !ok {
+		t.Errorf("Expected fs.read_file in tools/list, got %v", toolDescs)
+	} else if desc != "[fs] Read" {
+		t.Errorf("Expected description %q, got %q", "[fs] Read", desc)
+	}
+	if _, ok := toolDescs["filesystem.read_file"]; ok {
+		t.Error("Expected raw server name not to be used when toolPrefix is set")
+	}
+	// Global deny still applies through the prefix mapping
+	if _, ok := toolDescs["fs.delete_file"]; ok {
+		t.Error("Expected fs.delete_file to be filtered (globally denied)")
 	}
 
-	var toolsResp struct {
+	var callResp struct {
 		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(toolsLine), &toolsResp); err != nil {
-		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine)
+	if err := json.Unmarshal(responses[3], &callResp); err != nil {
+		t.Fatalf("Unmarshal tools/call: %v", err)
 	}
-	if toolsResp.Error != nil {
-		t.Fatalf("tools/list error: %v", toolsResp.Error)
+	if callResp.Error != nil {
+		t.Fatalf("tools/call fs.read_file error: %v", callResp.Error)
 	}
-
-	toolNames := make(map[string]bool)
-	for _, tool := range toolsResp.Result.Tools {
-		toolNames[tool.Name] = true
+	if len(callResp.Result.Content) == 0 || !strings.Contains(callResp.Result.Content[0].Text, "Called tool: read_file") {
+		t.Errorf("Expected call routed to upstream read_file, got %+v", callResp.Result)
 	}
-	t.Logf("First tools/list returned: %v", toolNames)
 
-	// Key assertion: the fast server's tools MUST be present.
-	// With serialized starts, the slow servers would block the fast one
-	// and no tools would be returned within the 200ms grace period.
-	if !toolNames["fast-srv.fast_tool"] {
-		t.Fatal("fast-srv.fast_tool missing from first tools/list — " +
-			"concurrent server initialization may be broken (slow servers blocking fast ones)")
+	var denyResp struct {
+		Error *RPCError `json:"error"`
 	}
-
-	// Cleanup
-	_ = stdinWriter.Close()
-	select {
-	case <-done:
-	case <-time.After(5 * time.Second):
-		cancel()
-		t.Fatal("Server did not stop")
+	if err := json.Unmarshal(responses[4], &denyResp); err != nil {
+		t.Fatalf("Unmarshal tools/call (denied): %v", err)
+	}
+	if denyResp.Error == nil || denyResp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected tool denied error for fs.delete_file, got %+v", denyResp.Error)
 	}
-
-	t.Log("Concurrent server init test passed!")
 }
 
-// TestServer_SlowInitNotification is a realistic end-to-end test that verifies
-// the full progressive discovery cycle when a server's initialization exceeds
-// the grace period:
-//
-//  1. tools/list returns partial results (fast server only) within the grace period
-//  2. The slow-init server finishes in the background
-//  3. A notifications/tools/list_changed is sent promptly (not blocked by failures)
-//  4. A second tools/list returns the complete tool set
-//
-// This simulates the real scenario: e.g. playwright finishes fast, grafana/sentry
-// take longer to initialise, and kubernetes/atlassian are broken. The client
-// should learn about grafana/sentry as soon as they're ready, without waiting
-// for the broken servers to time out.
-func TestServer_SlowInitNotification(t *testing.T) {
+func TestServer_ToolOverrides(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"fast-srv": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"fast_tool","description":"Fast tool"}]}`,
-				},
-			},
-			"slow-init-srv": {
+			"filesystem": {
 				Kind:    config.ServerKindStdio,
 				Enabled: &enabled,
 				Command: os.Args[0],
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					// 400ms init delay — exceeds the 100ms grace period but finishes
-					// well within the 30s background timeout.
-					"FAKE_MCP_CFG": `{"tools":[{"name":"slow_init_tool","description":"Slow init tool"}],"delays":{"initialize":400000000}}`,
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"rf","inputSchema":{"type":"object","properties":{"path":{"type":"string"}}}},{"name":"write_file","description":"Write"}],"echoToolCalls":true}`,
 				},
+				ToolPrefix: "fs",
 			},
-			"broken-srv": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					// Crash on init — simulates kubernetes/atlassian that never come up
-					"FAKE_MCP_CFG": `{"crashOnMethod":"initialize","crashExitCode":1}`,
-				},
+		},
+		ToolOverrides: map[string]config.ToolOverride{
+			"filesystem.read_file": {
+				Description: "Read a UTF-8 text file by absolute path",
+				Annotations: map[string]any{"readOnlyHint": true},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{},
-	}
-
-	stdinReader, stdinWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdin pipe: %v", err)
 	}
-	defer func() { _ = stdinReader.Close() }()
-	defer func() { _ = stdinWriter.Close() }()
 
-	stdoutReader, stdoutWriter, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("stdout pipe: %v", err)
-	}
-	defer func() { _ = stdoutReader.Close() }()
-	defer func() { _ = stdoutWriter.Close() }()
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fs.read_file","arguments":{"path":"/tmp"}}}` + "\n",
+	)
 
 	srv, err := New(Options{
 		Config:          cfg,
 		PIDTrackerDir:   t.TempDir(),
-		Stdin:           stdinReader,
-		Stdout:          stdoutWriter,
+		EagerStart:      true,
+		Stdin:           stdin,
+		Stdout:          &stdout,
 		ServerName:      "mcpmu-test",
 		ServerVersion:   "1.0.0",
 		ProtocolVersion: "2024-11-05",
@@ -1523,196 +2884,223 @@ func TestServer_SlowInitNotification(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
-	// Grace period: fast-srv finishes, slow-init-srv (400ms init) does not,
-	// broken-srv crashes. Background picks up the stragglers.
-	srv.listToolsGracePeriod = 100 * time.Millisecond
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	done := make(chan error, 1)
-	go func() { done <- srv.Run(ctx) }()
+	_ = srv.Run(ctx)
 
-	send := func(msg string) {
-		t.Helper()
-		if _, err := stdinWriter.WriteString(msg + "\n"); err != nil {
-			t.Fatalf("Write: %v", err)
-		}
-	}
+	responses := parseResponsesByID(t, stdout.String())
 
-	type notifLineResult struct {
-		data string
-		err  error
+	type listedTool struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		InputSchema json.RawMessage `json:"inputSchema"`
+		Annotations map[string]any  `json:"annotations"`
 	}
-	outLines := make(chan notifLineResult, 10)
-	go func() {
-		r := bufio.NewReader(stdoutReader)
-		for {
-			line, err := r.ReadString('\n')
-			if line != "" {
-				outLines <- notifLineResult{data: strings.TrimSpace(line)}
-			}
-			if err != nil {
-				outLines <- notifLineResult{err: err}
-				return
-			}
-		}
-	}()
-
-	readLine := func(timeout time.Duration) (string, error) {
-		select {
-		case r := <-outLines:
-			return r.data, r.err
-		case <-time.After(timeout):
-			return "", context.DeadlineExceeded
-		}
+	var listResp struct {
+		Result struct {
+			Tools []listedTool `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[2], &listResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
+	}
+	if listResp.Error != nil {
+		t.Fatalf("tools/list error: %v", listResp.Error)
+	}
+	tools := make(map[string]listedTool)
+	for _, tool := range listResp.Result.Tools {
+		tools[tool.Name] = tool
 	}
 
-	// Step 1: Initialize
-	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
-	time.Sleep(100 * time.Millisecond)
-
-	initLine, err := readLine(2 * time.Second)
-	if err != nil {
-		t.Fatalf("Init read: %v", err)
+	readFile, ok := tools["fs.read_file"]
+	if !ok {
+		t.Fatalf("Expected fs.read_file in tools/list, got %v", tools)
 	}
-	var initResp struct {
-		Error *RPCError `json:"error"`
+	if want := "[fs] Read a UTF-8 text file by absolute path"; readFile.Description != want {
+		t.Errorf("Expected description %q, got %q", want, readFile.Description)
 	}
-	if err := json.Unmarshal([]byte(initLine), &initResp); err != nil {
-		t.Fatalf("Parse init: %v\nLine: %s", err, initLine)
+	if readFile.Annotations["readOnlyHint"] != true {
+		t.Errorf("Expected readOnlyHint annotation, got %v", readFile.Annotations)
 	}
-	if initResp.Error != nil {
-		t.Fatalf("Init error: %v", initResp.Error)
+	if !strings.Contains(string(readFile.InputSchema), `"path"`) {
+		t.Errorf("Expected upstream input schema to be preserved, got %s", readFile.InputSchema)
 	}
 
-	// Step 2: tools/list — grace period is 100ms, so slow-init-srv (400ms) will be pending
-	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
-
-	toolsLine1, err := readLine(5 * time.Second)
-	if err != nil {
-		t.Fatalf("tools/list read: %v", err)
+	writeFile := tools["fs.write_file"]
+	if writeFile.Description != "[fs] Write" || writeFile.Annotations != nil {
+		t.Errorf("Expected fs.write_file untouched, got %+v", writeFile)
 	}
-	var toolsResp1 struct {
+
+	var callResp struct {
 		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(toolsLine1), &toolsResp1); err != nil {
-		t.Fatalf("Parse tools/list: %v\nLine: %s", err, toolsLine1)
+	if err := json.Unmarshal(responses[3], &callResp); err != nil {
+		t.Fatalf("Unmarshal tools/call: %v", err)
 	}
-	if toolsResp1.Error != nil {
-		t.Fatalf("tools/list error: %v", toolsResp1.Error)
+	if callResp.Error != nil {
+		t.Fatalf("tools/call fs.read_file error: %v", callResp.Error)
 	}
-
-	firstTools := make(map[string]bool)
-	for _, tool := range toolsResp1.Result.Tools {
-		firstTools[tool.Name] = true
+	if len(callResp.Result.Content) == 0 || !strings.Contains(callResp.Result.Content[0].Text, "Called tool: read_file") {
+		t.Errorf("Expected call routed to upstream read_file, got %+v", callResp.Result)
 	}
-	t.Logf("First tools/list: %v", firstTools)
+}
 
-	if !firstTools["fast-srv.fast_tool"] {
-		t.Fatal("fast-srv.fast_tool missing from first tools/list")
-	}
-	if firstTools["slow-init-srv.slow_init_tool"] {
-		t.Fatal("slow-init-srv.slow_init_tool should NOT be in first tools/list (grace period too short)")
+func TestServer_StripPrefixWhenSingle(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Step 3: Wait for notifications/tools/list_changed.
-	// The slow-init-srv takes 400ms — the notification should arrive within a
-	// few seconds, NOT after 30s (which would mean it's blocked by broken-srv).
-	start := time.Now()
-	notifLine, err := readLine(10 * time.Second)
-	notifLatency := time.Since(start)
-	if err != nil {
-		t.Fatalf("notification read: %v (waited %v — broken-srv may be blocking notification)", err, notifLatency)
-	}
-	var notif struct {
-		Method string `json:"method"`
-	}
-	if err := json.Unmarshal([]byte(notifLine), &notif); err != nil {
-		t.Fatalf("Parse notification: %v\nLine: %s", err, notifLine)
-	}
-	if notif.Method != "notifications/tools/list_changed" {
-		t.Fatalf("Expected notifications/tools/list_changed, got %q", notif.Method)
+	fakeServer := func(toolName string) config.ServerConfig {
+		enabled := true
+		return config.ServerConfig{
+			Kind:    config.ServerKindStdio,
+			Enabled: &enabled,
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestHelperProcess", "--"},
+			Env: map[string]string{
+				"GO_WANT_HELPER_PROCESS": "1",
+				"FAKE_MCP_CFG":           `{"tools":[{"name":"` + toolName + `","description":"Tool"}],"echoToolCalls":true}`,
+			},
+		}
 	}
-	t.Logf("Received notifications/tools/list_changed after %v", notifLatency)
 
-	// The notification should arrive promptly (slow-init-srv finishes in ~400ms
-	// after the grace period). If it takes >5s, the notification is likely blocked
-	// by the broken server's retries/timeouts.
-	if notifLatency > 5*time.Second {
-		t.Errorf("Notification took %v — should arrive within a few seconds, not blocked by broken servers", notifLatency)
-	}
+	runServe := func(t *testing.T, namespace string) map[int]json.RawMessage {
+		t.Helper()
+		cfg := &config.Config{
+			SchemaVersion: 1,
+			Servers: map[string]config.ServerConfig{
+				"srv1": fakeServer("read_file"),
+				"srv2": fakeServer("get_time"),
+			},
+			Namespaces: map[string]config.NamespaceConfig{
+				"single": {ServerIDs: []string{"srv1"}, StripPrefixWhenSingle: true},
+				"multi":  {ServerIDs: []string{"srv1", "srv2"}, StripPrefixWhenSingle: true},
+			},
+		}
 
-	// Step 4: Second tools/list should include the slow-init-srv's tools
-	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+		var stdout bytes.Buffer
+		stdin := strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+				`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+				`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"read_file","arguments":{}}}` + "\n" +
+				`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{}}}` + "\n",
+		)
+
+		srv, err := New(Options{
+			Config:             cfg,
+			PIDTrackerDir:      t.TempDir(),
+			Namespace:          namespace,
+			EagerStart:         true,
+			ExposeManagerTools: true,
+			Stdin:              stdin,
+			Stdout:             &stdout,
+			ServerName:         "mcpmu-test",
+			ServerVersion:      "1.0.0",
+			ProtocolVersion:    "2024-11-05",
+			LogLevel:           "error",
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
 
-	toolsLine2, err := readLine(5 * time.Second)
-	if err != nil {
-		t.Fatalf("second tools/list read: %v", err)
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		_ = srv.Run(ctx)
+
+		return parseResponsesByID(t, stdout.String())
 	}
-	var toolsResp2 struct {
+
+	type rpcResponse struct {
 		Result struct {
 			Tools []struct {
 				Name string `json:"name"`
 			} `json:"tools"`
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
 		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(toolsLine2), &toolsResp2); err != nil {
-		t.Fatalf("Parse second tools/list: %v\nLine: %s", err, toolsLine2)
+	decode := func(t *testing.T, raw json.RawMessage) rpcResponse {
+		t.Helper()
+		var resp rpcResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		return resp
 	}
-	if toolsResp2.Error != nil {
-		t.Fatalf("second tools/list error: %v", toolsResp2.Error)
+	toolNames := func(resp rpcResponse) map[string]bool {
+		names := make(map[string]bool)
+		for _, tool := range resp.Result.Tools {
+			names[tool.Name] = true
+		}
+		return names
 	}
 
-	secondTools := make(map[string]bool)
-	for _, tool := range toolsResp2.Result.Tools {
-		secondTools[tool.Name] = true
-	}
-	t.Logf("Second tools/list: %v", secondTools)
+	t.Run("single server is unprefixed", func(t *testing.T) {
+		t.Parallel()
+		responses := runServe(t, "single")
+
+		names := toolNames(decode(t, responses[2]))
+		if !names["read_file"] {
+			t.Errorf("Expected unprefixed read_file in tools/list, got %v", names)
+		}
+		if names["srv1.read_file"] {
+			t.Error("Expected srv1.read_file to be exposed without its prefix")
+		}
+		if !names["mcpmu.servers_list"] {
+			t.Error("Expected manager tools to keep the mcpmu. prefix")
+		}
+
+		call := decode(t, responses[3])
+		if call.Error != nil {
+			t.Fatalf("tools/call read_file error: %v", call.Error)
+		}
+		if len(call.Result.Content) == 0 || !strings.Contains(call.Result.Content[0].Text, "Called tool: read_file") {
+			t.Errorf("Expected call routed to srv1 read_file, got %+v", call.Result)
+		}
+	})
 
-	if !secondTools["fast-srv.fast_tool"] {
-		t.Error("fast-srv.fast_tool missing from second tools/list")
-	}
-	if !secondTools["slow-init-srv.slow_init_tool"] {
-		t.Error("slow-init-srv.slow_init_tool missing from second tools/list after notification")
-	}
+	t.Run("multi server stays prefixed", func(t *testing.T) {
+		t.Parallel()
+		responses := runServe(t, "multi")
 
-	// Cleanup
-	_ = stdinWriter.Close()
-	select {
-	case <-done:
-	case <-time.After(5 * time.Second):
-		cancel()
-		t.Fatal("Server did not stop")
-	}
+		names := toolNames(decode(t, responses[2]))
+		if !names["srv1.read_file"] || !names["srv2.get_time"] {
+			t.Errorf("Expected prefixed tool names in multi-server namespace, got %v", names)
+		}
+		if names["read_file"] {
+			t.Error("Expected no unprefixed tools in multi-server namespace")
+		}
 
-	t.Log("Slow init notification test passed!")
+		if call := decode(t, responses[3]); call.Error == nil {
+			t.Error("Expected unqualified tools/call to fail in multi-server namespace")
+		}
+		call := decode(t, responses[4])
+		if call.Error != nil {
+			t.Fatalf("tools/call srv1.read_file error: %v", call.Error)
+		}
+	})
 }
 
-// TestServer_ReloadSendsToolsListChanged verifies that config reloads
-// send a notifications/tools/list_changed notification to the client.
-func TestServer_ReloadSendsToolsListChanged(t *testing.T) {
+func TestServer_ToolsList_GlobalDenyNoNamespace(t *testing.T) {
+	t.Parallel()
 	if testing.Short() {
 		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Build the binary
-	tmpBin := t.TempDir() + "/mcpmu"
-	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		t.Fatalf("Failed to build binary: %v\n%s", err, output)
-	}
-
-	// Create initial config with one server
-	tmpConfig := t.TempDir() + "/config.json"
+	// No namespace (selection=all), but with global deny
 	enabled := true
-	initialCfg := &config.Config{
+	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
 			"srv1": {
@@ -1722,268 +3110,324 @@ func TestServer_ReloadSendsToolsListChanged(t *testing.T) {
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
 				},
+				DeniedTools: []string{"delete_file"},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{},
+		// No namespaces
 	}
-	if err := config.SaveTo(initialCfg, tmpConfig); err != nil {
-		t.Fatalf("Failed to write config: %v", err)
+
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+	)
+
+	srv, err := New(Options{
+		Config:             cfg,
+		PIDTrackerDir:      t.TempDir(),
+		EagerStart:         true,
+		ExposeManagerTools: true,
+		Stdin:              stdin,
+		Stdout:             &stdout,
+		ServerName:         "mcpmu-test",
+		ServerVersion:      "1.0.0",
+		ProtocolVersion:    "2024-11-05",
+		LogLevel:           "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
 	}
 
-	// Start the server
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "debug")
-	stdin, err := serverCmd.StdinPipe()
-	if err != nil {
-		t.Fatalf("StdinPipe: %v", err)
+	_ = srv.Run(ctx)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(lines))
 	}
-	stdout, err := serverCmd.StdoutPipe()
-	if err != nil {
-		t.Fatalf("StdoutPipe: %v", err)
+
+	var toolsResp struct {
+		ID     int `json:"id"`
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
 	}
-	stderr, err := serverCmd.StderrPipe()
-	if err != nil {
-		t.Fatalf("StderrPipe: %v", err)
+	if err := json.Unmarshal([]byte(lines[1]), &toolsResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
+	}
+	if toolsResp.Error != nil {
+		t.Fatalf("tools/list error: %v", toolsResp.Error)
 	}
 
-	stderrBuf := &bytes.Buffer{}
-	stderrDone := make(chan struct{})
-	go func() {
-		_, _ = io.Copy(stderrBuf, stderr)
-		close(stderrDone)
-	}()
-
-	if err := serverCmd.Start(); err != nil {
-		t.Fatalf("Start: %v", err)
+	toolNames := make(map[string]bool)
+	for _, tool := range toolsResp.Result.Tools {
+		toolNames[tool.Name] = true
 	}
 
-	t.Cleanup(func() {
-		_ = stdin.Close()
-		_ = serverCmd.Process.Kill()
-		_ = serverCmd.Wait()
-		<-stderrDone
-		if t.Failed() {
-			t.Logf("Server stderr:\n%s", stderrBuf.String())
-		}
-	})
+	// read_file should be present
+	if !toolNames["srv1.read_file"] {
+		t.Error("Expected srv1.read_file in tools/list")
+	}
 
-	reader := bufio.NewReader(stdout)
+	// delete_file should be filtered (globally denied) even without namespace
+	if toolNames["srv1.delete_file"] {
+		t.Error("Expected srv1.delete_file to be filtered (globally denied, no namespace)")
+	}
 
-	// Use a single reader goroutine to avoid races on bufio.Reader
-	type lineResult struct {
-		data []byte
-		err  error
+	// Manager tools should survive unconditional filtering
+	if !toolNames["mcpmu.servers_list"] {
+		t.Error("Expected mcpmu.servers_list in tools/list after unconditional filtering")
 	}
-	lines := make(chan lineResult, 10)
-	go func() {
-		for {
-			line, err := reader.ReadBytes('\n')
-			lines <- lineResult{line, err}
-			if err != nil {
-				return
-			}
-		}
-	}()
+}
 
-	send := func(req string) {
-		t.Helper()
-		if _, err := stdin.Write([]byte(req + "\n")); err != nil {
-			t.Fatalf("Write: %v", err)
-		}
+func TestServer_ToolsCall_ResultSizeLimit(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	readMsg := func(timeout time.Duration) (json.RawMessage, error) {
-		select {
-		case r := <-lines:
-			return json.RawMessage(r.data), r.err
-		case <-time.After(timeout):
-			return nil, context.DeadlineExceeded
+	enabled := true
+	echoServer := func(maxResultBytes int) config.ServerConfig {
+		return config.ServerConfig{
+			Kind:    config.ServerKindStdio,
+			Enabled: &enabled,
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestHelperProcess", "--"},
+			Env: map[string]string{
+				"GO_WANT_HELPER_PROCESS": "1",
+				"FAKE_MCP_CFG":           `{"tools":[{"name":"echo","description":"Echo"}],"echoToolCalls":true}`,
+			},
+			MaxResultBytes: maxResultBytes,
 		}
 	}
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"global":   echoServer(0),
+			"override": echoServer(200),
+		},
+	}
 
-	// Step 1: Initialize
-	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
-	initResp, err := readMsg(15 * time.Second)
+	big := strings.Repeat("x", 5000)
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"global.echo","arguments":{"data":"small"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"global.echo","arguments":{"data":"` + big + `"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"override.echo","arguments":{"data":"` + big + `"}}}` + "\n",
+	)
+
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		MaxResultBytes:  1000,
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
 	if err != nil {
-		t.Fatalf("Initialize read: %v", err)
+		t.Fatalf("New: %v", err)
 	}
-	var initResult struct {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	responses := parseResponsesByID(t, stdout.String())
+
+	type callResp struct {
+		Result struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+			Meta map[string]any `json:"_meta"`
+		} `json:"result"`
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal(initResp, &initResult); err != nil {
-		t.Fatalf("Parse init: %v", err)
-	}
-	if initResult.Error != nil {
-		t.Fatalf("Init error: %v", initResult.Error)
+	decode := func(id int) callResp {
+		t.Helper()
+		var resp callResp
+		if err := json.Unmarshal(responses[id], &resp); err != nil {
+			t.Fatalf("Unmarshal id %d: %v", id, err)
+		}
+		if resp.Error != nil {
+			t.Fatalf("tools/call id %d error: %v", id, resp.Error)
+		}
+		if len(resp.Result.Content) != 1 {
+			t.Fatalf("Expected 1 content block for id %d, got %d", id, len(resp.Result.Content))
+		}
+		return resp
 	}
-	t.Log("Initialize: OK")
 
-	// Step 2: Get initial tools
-	send(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
-	toolsResp1, err := readMsg(15 * time.Second)
-	if err != nil {
-		t.Fatalf("tools/list read: %v", err)
+	small := decode(2)
+	if small.Result.Meta[metaTruncated] != nil {
+		t.Error("Expected small result not to be truncated")
+	}
+	if strings.Contains(small.Result.Content[0].Text, "truncated by mcpmu") {
+		t.Error("Expected small result to have no truncation marker")
 	}
 
-	var toolsResult1 struct {
-		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
-		} `json:"result"`
+	tests := []struct {
+		id    int
+		limit int
+	}{
+		{3, 1000}, // serve-wide limit
+		{4, 200},  // per-server override
 	}
-	if err := json.Unmarshal(toolsResp1, &toolsResult1); err != nil {
-		t.Fatalf("Parse tools/list: %v", err)
+	for _, tt := range tests {
+		resp := decode(tt.id)
+		if resp.Result.Meta[metaTruncated] != true {
+			t.Errorf("id %d: expected _meta[%q] = true, got %v", tt.id, metaTruncated, resp.Result.Meta)
+		}
+		text := resp.Result.Content[0].Text
+		if !strings.Contains(text, fmt.Sprintf("limit is %d bytes", tt.limit)) {
+			t.Errorf("id %d: expected truncation marker for limit %d, got %q", tt.id, tt.limit, text)
+		}
+		body, _, _ := strings.Cut(text, "\n\n[truncated by mcpmu")
+		if len(body) > tt.limit {
+			t.Errorf("id %d: expected at most %d bytes of content, got %d", tt.id, tt.limit, len(body))
+		}
 	}
+}
 
-	initialToolCount := len(toolsResult1.Result.Tools)
-	t.Logf("Initial tools: %d", initialToolCount)
-
-	// Drain any pending notification from background discovery (if any)
-	drainedNotif, _ := readMsg(500 * time.Millisecond)
-	if drainedNotif != nil {
-		t.Log("Drained background discovery notification")
+func TestServer_ReadOnly(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Step 3: Modify config to add a second server
-	updatedCfg := &config.Config{
+	enabled := true
+	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"srv1": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
-				},
-			},
-			"srv2": {
+			"filesystem": {
 				Kind:    config.ServerKindStdio,
 				Enabled: &enabled,
 				Command: os.Args[0],
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_b","description":"Tool B"}],"echoToolCalls":true}`,
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
 				},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{},
-	}
-	if err := config.SaveTo(updatedCfg, tmpConfig); err != nil {
-		t.Fatalf("Failed to save updated config: %v", err)
+		Namespaces: map[string]config.NamespaceConfig{
+			"work": {ServerIDs: []string{"filesystem"}},
+		},
+		// An explicit allow does not override read-only mode
+		ToolPermissions: []config.ToolPermission{
+			{Namespace: "work", Server: "filesystem", ToolName: "delete_file", Enabled: true},
+		},
 	}
-	t.Log("Config updated: added srv2")
 
-	// Step 4: Read reload notifications (tools, resources, prompts list_changed).
-	// Drain all of them — order is not guaranteed.
-	gotToolsChanged := false
-	for range 3 {
-		notifMsg, err := readMsg(5 * time.Second)
-		if err != nil {
-			break // fewer notifications than expected is OK
-		}
-		var notif struct {
-			Method string `json:"method"`
-		}
-		if err := json.Unmarshal(notifMsg, &notif); err != nil {
-			t.Fatalf("Parse notification: %v", err)
-		}
-		t.Logf("Received %s after config reload", notif.Method)
-		if notif.Method == "notifications/tools/list_changed" {
-			gotToolsChanged = true
-		}
-	}
-	if !gotToolsChanged {
-		t.Error("Expected notifications/tools/list_changed among reload notifications")
-	}
+	var stdout bytes.Buffer
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"filesystem.read_file","arguments":{}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"filesystem.delete_file","arguments":{}}}` + "\n",
+	)
 
-	// Step 5: Send tools/list again to get updated tools
-	send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
-	toolsResp2, err := readMsg(15 * time.Second)
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Namespace:       "work",
+		ReadOnly:        true,
+		EagerStart:      true,
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
 	if err != nil {
-		t.Fatalf("second tools/list read: %v", err)
+		t.Fatalf("New: %v", err)
 	}
 
-	var toolsResult2 struct {
-		ID     int `json:"id"`
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_ = srv.Run(ctx)
+
+	responses := parseResponsesByID(t, stdout.String())
+
+	var listResp struct {
 		Result struct {
 			Tools []struct {
 				Name string `json:"name"`
 			} `json:"tools"`
 		} `json:"result"`
-		Error *RPCError `json:"error"`
 	}
-
-	// Skip any notifications (resources/list_changed, prompts/list_changed, etc.)
-	// until we get the actual tools/list response (has a non-zero ID).
-	for {
-		if err := json.Unmarshal(toolsResp2, &toolsResult2); err != nil {
-			t.Fatalf("Parse second tools/list: %v", err)
-		}
-		if toolsResult2.ID != 0 {
-			break
-		}
-		t.Log("Skipping notification, reading next message")
-		toolsResp2, err = readMsg(15 * time.Second)
-		if err != nil {
-			t.Fatalf("reading after notification: %v", err)
-		}
+	if err := json.Unmarshal(responses[2], &listResp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
 	}
-
-	if toolsResult2.Error != nil {
-		t.Fatalf("second tools/list error: %v", toolsResult2.Error)
+	names := make(map[string]bool)
+	for _, tool := range listResp.Result.Tools {
+		names[tool.Name] = true
 	}
-
-	toolNames := make(map[string]bool)
-	for _, tool := range toolsResult2.Result.Tools {
-		toolNames[tool.Name] = true
+	if !names["filesystem.read_file"] {
+		t.Errorf("Expected read_file in tools/list, got %v", names)
+	}
+	if names["filesystem.delete_file"] {
+		t.Error("Expected delete_file to be hidden in read-only mode")
 	}
-	t.Logf("After reload tools/list: %d tools: %v", len(toolsResult2.Result.Tools), toolNames)
 
-	if !toolNames["srv1.tool_a"] {
-		t.Error("Expected srv1.tool_a after reload")
+	var readResp, deleteResp struct {
+		Error *RPCError `json:"error"`
 	}
-	if !toolNames["srv2.tool_b"] {
-		t.Error("Expected srv2.tool_b after reload")
+	if err := json.Unmarshal(responses[3], &readResp); err != nil {
+		t.Fatalf("Unmarshal read_file call: %v", err)
+	}
+	if readResp.Error != nil {
+		t.Errorf("Expected read_file to be allowed, got %v", readResp.Error)
+	}
+	if err := json.Unmarshal(responses[4], &deleteResp); err != nil {
+		t.Fatalf("Unmarshal delete_file call: %v", err)
+	}
+	if deleteResp.Error == nil || deleteResp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected tool denied error for delete_file, got %+v", deleteResp.Error)
 	}
-
-	t.Log("Reload notification test passed!")
 }
 
-// ============================================================================
-// Global Deny Integration Tests
-// ============================================================================
-
-func TestServer_ToolsCall_GlobalDenyNoNamespace(t *testing.T) {
+// TestServer_ToolsList_DoesNotRequeryUpstream: tools are discovered once per
+// upstream connection and served from the handle afterwards, so repeated
+// tools/list calls from a chatty client never reach the upstream again.
+func TestServer_ToolsList_DoesNotRequeryUpstream(t *testing.T) {
 	t.Parallel()
-	// No namespaces configured (selection=all), but server has deniedTools.
-	// The globally denied tool should still be blocked.
-	enabled := true
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "requests.log")
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
-			"srv1": {
-				Kind:        config.ServerKindStdio,
-				Enabled:     &enabled,
-				Command:     "echo",
-				DeniedTools: []string{"dangerous_tool"},
-			},
+			"srv1": fakeServerConfig(t, map[string]any{
+				"tools":          []any{map[string]any{"name": "tool_a", "description": "Tool A"}},
+				"requestLogPath": logPath,
+			}),
 		},
-		// No namespaces
 	}
 
 	var stdout bytes.Buffer
 	stdin := strings.NewReader(
 		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.dangerous_tool","arguments":{}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.safe_tool","arguments":{}}}` + "\n",
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":4,"method":"tools/list"}` + "\n",
 	)
 
 	srv, err := New(Options{
@@ -2000,114 +3444,337 @@ func TestServer_ToolsCall_GlobalDenyNoNamespace(t *testing.T) {
 		t.Fatalf("New: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 3 {
-		t.Fatalf("Expected 3 responses, got %d", len(lines))
+	responses := parseResponsesByID(t, stdout.String())
+	for _, id := range []int{2, 3, 4} {
+		if !strings.Contains(string(responses[id]), "srv1.tool_a") {
+			t.Errorf("Expected srv1.tool_a in tools/list id %d, got %s", id, responses[id])
+		}
 	}
 
-	// Response 2: globally denied tool should be blocked
-	var resp2 struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	logBytes, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read request log: %v", err)
 	}
-	if err := json.Unmarshal([]byte(lines[1]), &resp2); err != nil {
-		t.Fatalf("Unmarshal response 2: %v", err)
+	if n := strings.Count(string(logBytes), "tools/list"); n != 1 {
+		t.Errorf("Expected exactly 1 upstream tools/list, got %d; log:\n%s", n, logBytes)
 	}
-	if resp2.Error == nil || resp2.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied for globally denied tool, got: %+v", resp2.Error)
+}
+
+func TestServer_AllNamespaces(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Response 3: non-denied tool should NOT be tool-denied
-	var resp3 struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	fakeServer := func(tools string, enabled bool) config.ServerConfig {
+		return config.ServerConfig{
+			Kind:    config.ServerKindStdio,
+			Enabled: &enabled,
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestHelperProcess", "--"},
+			Env: map[string]string{
+				"GO_WANT_HELPER_PROCESS": "1",
+				"FAKE_MCP_CFG":           `{"tools":` + tools + `,"echoToolCalls":true}`,
+			},
+		}
 	}
-	if err := json.Unmarshal([]byte(lines[2]), &resp3); err != nil {
-		t.Fatalf("Unmarshal response 3: %v", err)
+	newConfig := func() *config.Config {
+		return &config.Config{
+			SchemaVersion: 1,
+			Servers: map[string]config.ServerConfig{
+				"docs":     fakeServer(`[{"name":"search"}]`, true),
+				"tracker":  fakeServer(`[{"name":"get_issue"},{"name":"close_issue"}]`, true),
+				"scratch":  fakeServer(`[{"name":"note"}]`, true),
+				"disabled": fakeServer(`[{"name":"hidden"}]`, false),
+			},
+			Namespaces: map[string]config.NamespaceConfig{
+				"reading": {ServerIDs: []string{"docs"}},
+				"triage":  {ServerIDs: []string{"tracker"}, DenyByDefault: true},
+			},
+		}
 	}
-	if resp3.Error != nil && resp3.Error.Code == ErrCodeToolDenied {
-		t.Error("Non-denied tool should NOT be blocked by global deny")
+
+	run := func(t *testing.T, allNamespaces bool) map[int]json.RawMessage {
+		t.Helper()
+		var stdout bytes.Buffer
+		stdin := strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+				`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+		)
+		srv, err := New(Options{
+			Config:          newConfig(),
+			PIDTrackerDir:   t.TempDir(),
+			AllNamespaces:   allNamespaces,
+			Stdin:           stdin,
+			Stdout:          &stdout,
+			ServerName:      "mcpmu-test",
+			ServerVersion:   "1.0.0",
+			ProtocolVersion: "2024-11-05",
+			LogLevel:        "error",
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		_ = srv.Run(ctx)
+		return parseResponsesByID(t, stdout.String())
 	}
+
+	t.Run("union of enabled servers", func(t *testing.T) {
+		t.Parallel()
+		responses := run(t, true)
+
+		var listResp struct {
+			Result struct {
+				Tools []struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+			} `json:"result"`
+			Error *RPCError `json:"error"`
+		}
+		if err := json.Unmarshal(responses[2], &listResp); err != nil {
+			t.Fatalf("Unmarshal tools/list: %v", err)
+		}
+		if listResp.Error != nil {
+			t.Fatalf("tools/list failed: %v", listResp.Error)
+		}
+		var names []string
+		for _, tool := range listResp.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		sort.Strings(names)
+		// triage's DenyByDefault does not apply: namespace permissions are ignored
+		want := []string{"docs.search", "scratch.note", "tracker.close_issue", "tracker.get_issue"}
+		if !slices.Equal(names, want) {
+			t.Errorf("tools/list = %v, want %v", names, want)
+		}
+	})
+
+	t.Run("requires the flag", func(t *testing.T) {
+		t.Parallel()
+		responses := run(t, false)
+		if !strings.Contains(string(responses[1]), "Multiple namespaces configured") {
+			t.Errorf("Expected namespace resolution error without --all-namespaces, got %s", responses[1])
+		}
+	})
 }
 
-func TestServer_ToolsCall_GlobalDenyWithNamespaceAllow(t *testing.T) {
+// TestEndToEnd_EventsOutput verifies that serve --events writes the
+// lifecycle event stream as NDJSON records.
+func TestEndToEnd_EventsOutput(t *testing.T) {
 	t.Parallel()
-	// Namespace explicitly allows the tool, but it's in server deniedTools.
-	// Global deny should win.
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	dir := t.TempDir()
+	tmpConfig := filepath.Join(dir, "config.json")
+	eventsPath := filepath.Join(dir, "events.ndjson")
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
 		Servers: map[string]config.ServerConfig{
 			"srv1": {
-				Kind:        config.ServerKindStdio,
-				Enabled:     &enabled,
-				Command:     "echo",
-				DeniedTools: []string{"dangerous_tool"},
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+				},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{
-			"ns1": {ServerIDs: []string{"srv1"}},
-		},
-		ToolPermissions: []config.ToolPermission{
-			{Namespace: "ns1", Server: "srv1", ToolName: "dangerous_tool", Enabled: true},
+	}
+	if err := config.SaveTo(cfg, tmpConfig); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "error", "--events", eventsPath)
+	stdin, err := serverCmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("StdinPipe: %v", err)
+	}
+	serverCmd.Stdout = io.Discard
+	if err := serverCmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stdin.Close()
+		_ = serverCmd.Process.Kill()
+		_ = serverCmd.Wait()
+	})
+
+	_, _ = stdin.Write([]byte(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.tool_a","arguments":{}}}` + "\n",
+	))
+
+	var running *events.Record
+	var toolsUpdated bool
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) && (running == nil || !toolsUpdated) {
+		time.Sleep(50 * time.Millisecond)
+		data, err := os.ReadFile(eventsPath)
+		if err != nil {
+			continue
+		}
+		for line := range strings.SplitSeq(strings.TrimSpace(string(data)), "\n") {
+			var rec events.Record
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				continue
+			}
+			if rec.Type == "status_changed" && rec.NewState == "running" {
+				running = &rec
+			}
+			if rec.Type == "tools_updated" && slices.Contains(rec.Tools, "tool_a") {
+				toolsUpdated = true
+			}
+		}
+	}
+
+	if running == nil {
+		data, _ := os.ReadFile(eventsPath)
+		t.Fatalf("no status_changed running event in events output:\n%s", data)
+	}
+	if running.Server != "srv1" || running.PID <= 0 || running.Timestamp.IsZero() {
+		t.Errorf("running event = %+v, want srv1 with a PID and timestamp", *running)
+	}
+	if !toolsUpdated {
+		t.Error("expected a tools_updated event listing tool_a")
+	}
+}
+
+func TestEndToEnd_SIGTERMStopsUpstreams(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	dir := t.TempDir()
+	tmpConfig := filepath.Join(dir, "config.json")
+	pidsPath := filepath.Join(dir, "pids.json")
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+				},
+			},
 		},
 	}
+	if err := config.SaveTo(cfg, tmpConfig); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
 
-	var stdout bytes.Buffer
-	stdin := strings.NewReader(
-		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.dangerous_tool","arguments":{}}}` + "\n",
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	srv, err := New(Options{
-		Config:          cfg,
-		PIDTrackerDir:   t.TempDir(),
-		Namespace:       "ns1",
-		Stdin:           stdin,
-		Stdout:          &stdout,
-		ServerName:      "mcpmu-test",
-		ServerVersion:   "1.0.0",
-		ProtocolVersion: "2024-11-05",
-		LogLevel:        "error",
-	})
+	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "error")
+	stdin, err := serverCmd.StdinPipe()
 	if err != nil {
-		t.Fatalf("New: %v", err)
+		t.Fatalf("StdinPipe: %v", err)
 	}
+	serverCmd.Stdout = io.Discard
+	if err := serverCmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stdin.Close()
+		_ = serverCmd.Process.Kill()
+		_ = serverCmd.Wait()
+	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	_ = srv.Run(ctx)
+	_, _ = stdin.Write([]byte(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.tool_a","arguments":{}}}` + "\n",
+	))
+
+	readPIDs := func() map[string]struct {
+		PID int `json:"pid"`
+	} {
+		var pids map[string]struct {
+			PID int `json:"pid"`
+		}
+		data, err := os.ReadFile(pidsPath)
+		if err != nil {
+			return nil
+		}
+		_ = json.Unmarshal(data, &pids)
+		return pids
+	}
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
+	var upstreamPID int
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) && upstreamPID == 0 {
+		time.Sleep(50 * time.Millisecond)
+		upstreamPID = readPIDs()["srv1"].PID
+	}
+	if upstreamPID == 0 {
+		t.Fatal("upstream srv1 was never recorded in pids.json")
 	}
 
-	var resp struct {
-		ID    int       `json:"id"`
-		Error *RPCError `json:"error"`
+	// Leave stdin open so shutdown is driven by the signal, not by EOF.
+	if err := serverCmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("Signal: %v", err)
 	}
-	if err := json.Unmarshal([]byte(lines[1]), &resp); err != nil {
-		t.Fatalf("Unmarshal response: %v", err)
+	if err := serverCmd.Wait(); err != nil {
+		t.Fatalf("serve did not exit cleanly after SIGTERM: %v", err)
 	}
-	if resp.Error == nil || resp.Error.Code != ErrCodeToolDenied {
-		t.Errorf("Expected ErrCodeToolDenied even with namespace allow, got: %+v", resp.Error)
+
+	if pids := readPIDs(); len(pids) != 0 {
+		t.Errorf("pids.json still tracks %v after shutdown", pids)
+	}
+	// The upstream is serve's child, so once serve has exited it is reaped
+	// (or reparented); either way it must no longer be running.
+	if err := syscall.Kill(upstreamPID, 0); err == nil {
+		t.Errorf("upstream process %d still running after serve exited", upstreamPID)
 	}
 }
 
-func TestServer_ToolsList_GlobalDenyFiltering(t *testing.T) {
+func TestEndToEnd_StdinEOFStopsUpstreams(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
 		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Use a real fake server with tools including a globally denied one
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	dir := t.TempDir()
+	tmpConfig := filepath.Join(dir, "config.json")
+	pidsPath := filepath.Join(dir, "pids.json")
 	enabled := true
 	cfg := &config.Config{
 		SchemaVersion: 1,
@@ -2119,129 +3786,269 @@ func TestServer_ToolsList_GlobalDenyFiltering(t *testing.T) {
 				Args:    []string{"-test.run=TestHelperProcess", "--"},
 				Env: map[string]string{
 					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
+					// The call is still in flight when the client goes away
+					"FAKE_MCP_CFG": `{"tools":[{"name":"slow","description":"Slow"}],"delays":{"tools/call":60000000000}}`,
 				},
-				DeniedTools: []string{"delete_file"},
 			},
 		},
-		Namespaces: map[string]config.NamespaceConfig{
-			"ns1": {ServerIDs: []string{"srv1"}},
-		},
+	}
+	if err := config.SaveTo(cfg, tmpConfig); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
 	}
 
-	var stdout bytes.Buffer
-	stdin := strings.NewReader(
-		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	srv, err := New(Options{
-		Config:             cfg,
-		PIDTrackerDir:      t.TempDir(),
-		Namespace:          "ns1",
-		EagerStart:         true,
-		ExposeManagerTools: true,
-		Stdin:              stdin,
-		Stdout:             &stdout,
-		ServerName:         "mcpmu-test",
-		ServerVersion:      "1.0.0",
-		ProtocolVersion:    "2024-11-05",
-		LogLevel:           "error",
-	})
+	serverCmd := exec.CommandContext(ctx, tmpBin, "serve", "--stdio", "--config", tmpConfig, "--log-level", "error")
+	stdin, err := serverCmd.StdinPipe()
 	if err != nil {
-		t.Fatalf("New: %v", err)
+		t.Fatalf("StdinPipe: %v", err)
+	}
+	serverCmd.Stdout = io.Discard
+	if err := serverCmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
 	}
+	t.Cleanup(func() {
+		_ = serverCmd.Process.Kill()
+		_ = serverCmd.Wait()
+	})
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	_, _ = stdin.Write([]byte(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"srv1.slow","arguments":{}}}` + "\n",
+	))
+
+	readPIDs := func() map[string]struct {
+		PID int `json:"pid"`
+	} {
+		var pids map[string]struct {
+			PID int `json:"pid"`
+		}
+		data, err := os.ReadFile(pidsPath)
+		if err != nil {
+			return nil
+		}
+		_ = json.Unmarshal(data, &pids)
+		return pids
+	}
 
-	_ = srv.Run(ctx)
+	var upstreamPID int
+	deadline := time.Now().Add(15 * time.Second)
+	for time.Now().Before(deadline) && upstreamPID == 0 {
+		time.Sleep(50 * time.Millisecond)
+		upstreamPID = readPIDs()["srv1"].PID
+	}
+	if upstreamPID == 0 {
+		t.Fatal("upstream srv1 was never recorded in pids.json")
+	}
+	// Let the call reach the upstream
+	time.Sleep(200 * time.Millisecond)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
+	_ = stdin.Close()
+	exited := make(chan error, 1)
+	go func() { exited <- serverCmd.Wait() }()
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Fatalf("serve did not exit cleanly after EOF: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("serve did not exit within 10s of stdin closing")
 	}
 
-	var toolsResp struct {
-		ID     int `json:"id"`
+	if pids := readPIDs(); len(pids) != 0 {
+		t.Errorf("pids.json still tracks %v after shutdown", pids)
+	}
+	if err := syscall.Kill(upstreamPID, 0); err == nil {
+		t.Errorf("upstream process %d still running after serve exited", upstreamPID)
+	}
+}
+
+func TestEndToEnd_TryCommand(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	tmpBin := t.TempDir() + "/mcpmu"
+	cmd := exec.Command("go", "build", "-o", tmpBin, "../../cmd/mcpmu")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build binary: %v\n%s", err, output)
+	}
+
+	home := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tryCmd := exec.CommandContext(ctx, tmpBin, "try", "--name", "scratch", "--log-level", "error",
+		"--env", "GO_WANT_HELPER_PROCESS=1",
+		"--env", `FAKE_MCP_CFG={"tools":[{"name":"tool_a","description":"Tool A"}],"echoToolCalls":true}`,
+		"--", os.Args[0], "-test.run=TestHelperProcess", "--")
+	tryCmd.Env = append(os.Environ(), "HOME="+home)
+	tryCmd.Stdin = strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"scratch.tool_a","arguments":{}}}` + "\n",
+	)
+	var stdout, stderr bytes.Buffer
+	tryCmd.Stdout = &stdout
+	tryCmd.Stderr = &stderr
+	if err := tryCmd.Run(); err != nil {
+		t.Fatalf("try failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	responses := parseResponsesByID(t, stdout.String())
+
+	var listResp struct {
 		Result struct {
 			Tools []struct {
 				Name string `json:"name"`
 			} `json:"tools"`
 		} `json:"result"`
-		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(lines[1]), &toolsResp); err != nil {
+	if err := json.Unmarshal(responses[2], &listResp); err != nil {
 		t.Fatalf("Unmarshal tools/list: %v", err)
 	}
-	if toolsResp.Error != nil {
-		t.Fatalf("tools/list error: %v", toolsResp.Error)
+	if len(listResp.Result.Tools) != 1 || listResp.Result.Tools[0].Name != "scratch.tool_a" {
+		t.Errorf("tools/list = %+v, want only scratch.tool_a", listResp.Result.Tools)
 	}
 
-	toolNames := make(map[string]bool)
-	for _, tool := range toolsResp.Result.Tools {
-		toolNames[tool.Name] = true
+	if !strings.Contains(string(responses[3]), "Called tool: tool_a") {
+		t.Errorf("Expected call routed to upstream tool_a, got %s", responses[3])
 	}
 
-	// read_file should be present
-	if !toolNames["srv1.read_file"] {
-		t.Error("Expected srv1.read_file in tools/list")
+	// try never writes a config file
+	if _, err := os.Stat(filepath.Join(home, ".config", "mcpmu", "config.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no config file to be written, stat err = %v", err)
 	}
+}
 
-	// delete_file should be filtered (globally denied)
-	if toolNames["srv1.delete_file"] {
-		t.Error("Expected srv1.delete_file to be filtered from tools/list (globally denied)")
+func TestServer_ToolsCall_ValidateArgs(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// Manager tools should still be present
-	if !toolNames["mcpmu.servers_list"] {
-		t.Error("Expected mcpmu.servers_list in tools/list (manager tools should survive filtering)")
+	for _, validate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("validate=%v", validate), func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{
+				SchemaVersion: 1,
+				Servers: map[string]config.ServerConfig{
+					"srv1": fakeServerConfig(t, map[string]any{
+						"tools": []map[string]any{{
+							"name": "read_file",
+							"inputSchema": map[string]any{
+								"type":       "object",
+								"properties": map[string]any{"path": map[string]any{"type": "string"}},
+								"required":   []string{"path"},
+							},
+						}},
+						"echoToolCalls": true,
+					}),
+				},
+			}
+
+			var stdout bytes.Buffer
+			stdin := strings.NewReader(
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+					`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+					`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{}}}` + "\n" +
+					`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"srv1.read_file","arguments":{"path":"/tmp/x"}}}` + "\n",
+			)
+
+			srv, err := New(Options{
+				Config:          cfg,
+				PIDTrackerDir:   t.TempDir(),
+				AllNamespaces:   true,
+				EagerStart:      true,
+				ValidateArgs:    validate,
+				Stdin:           stdin,
+				Stdout:          &stdout,
+				ServerName:      "mcpmu-test",
+				ServerVersion:   "1.0.0",
+				ProtocolVersion: "2024-11-05",
+				LogLevel:        "error",
+			})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			_ = srv.Run(ctx)
+
+			responses := parseResponsesByID(t, stdout.String())
+
+			var missing struct {
+				Result json.RawMessage `json:"result"`
+				Error  *RPCError       `json:"error"`
+			}
+			if err := json.Unmarshal(responses[3], &missing); err != nil {
+				t.Fatalf("Unmarshal missing-param call: %v", err)
+			}
+			if validate {
+				if missing.Error == nil || missing.Error.Code != ErrCodeInvalidParams {
+					t.Fatalf("expected invalid params error, got %s", responses[3])
+				}
+				if !strings.Contains(missing.Error.Message, `missing required property "path"`) {
+					t.Errorf("error message = %q, want missing path", missing.Error.Message)
+				}
+			} else if missing.Error != nil || !strings.Contains(string(missing.Result), "Called tool: read_file") {
+				// Without validation the call reaches the upstream, which echoes it
+				t.Errorf("expected call forwarded upstream, got %s", responses[3])
+			}
+
+			if !strings.Contains(string(responses[4]), "Called tool: read_file") {
+				t.Errorf("expected valid call forwarded upstream, got %s", responses[4])
+			}
+		})
 	}
 }
 
-func TestServer_ToolsList_GlobalDenyNoNamespace(t *testing.T) {
+func TestServer_ToolsCall_ValidateArgs_DeniedToolIsDeniedFirst(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
 		t.Skip("Skipping end-to-end test in short mode")
 	}
 
-	// No namespace (selection=all), but with global deny
-	enabled := true
+	srv1 := fakeServerConfig(t, map[string]any{
+		"tools": []map[string]any{{
+			"name": "delete_file",
+			"inputSchema": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"path": map[string]any{"type": "string"}},
+				"required":   []string{"path"},
+			},
+		}},
+		"echoToolCalls": true,
+	})
+	srv1.DeniedTools = []string{"delete_file"}
 	cfg := &config.Config{
 		SchemaVersion: 1,
-		Servers: map[string]config.ServerConfig{
-			"srv1": {
-				Kind:    config.ServerKindStdio,
-				Enabled: &enabled,
-				Command: os.Args[0],
-				Args:    []string{"-test.run=TestHelperProcess", "--"},
-				Env: map[string]string{
-					"GO_WANT_HELPER_PROCESS": "1",
-					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
-				},
-				DeniedTools: []string{"delete_file"},
-			},
-		},
-		// No namespaces
+		Servers:       map[string]config.ServerConfig{"srv1": srv1},
 	}
 
 	var stdout bytes.Buffer
 	stdin := strings.NewReader(
 		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
-			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n" +
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"srv1.delete_file","arguments":{}}}` + "\n",
 	)
 
 	srv, err := New(Options{
-		Config:             cfg,
-		PIDTrackerDir:      t.TempDir(),
-		EagerStart:         true,
-		ExposeManagerTools: true,
-		Stdin:              stdin,
-		Stdout:             &stdout,
-		ServerName:         "mcpmu-test",
-		ServerVersion:      "1.0.0",
-		ProtocolVersion:    "2024-11-05",
-		LogLevel:           "error",
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		AllNamespaces:   true,
+		EagerStart:      true,
+		ValidateArgs:    true,
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
 	})
 	if err != nil {
 		t.Fatalf("New: %v", err)
@@ -2249,47 +4056,16 @@ func TestServer_ToolsList_GlobalDenyNoNamespace(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
-
 	_ = srv.Run(ctx)
 
-	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
-	if len(lines) < 2 {
-		t.Fatalf("Expected 2 responses, got %d", len(lines))
-	}
-
-	var toolsResp struct {
-		ID     int `json:"id"`
-		Result struct {
-			Tools []struct {
-				Name string `json:"name"`
-			} `json:"tools"`
-		} `json:"result"`
+	responses := parseResponsesByID(t, stdout.String())
+	var resp struct {
 		Error *RPCError `json:"error"`
 	}
-	if err := json.Unmarshal([]byte(lines[1]), &toolsResp); err != nil {
-		t.Fatalf("Unmarshal tools/list: %v", err)
-	}
-	if toolsResp.Error != nil {
-		t.Fatalf("tools/list error: %v", toolsResp.Error)
-	}
-
-	toolNames := make(map[string]bool)
-	for _, tool := range toolsResp.Result.Tools {
-		toolNames[tool.Name] = true
-	}
-
-	// read_file should be present
-	if !toolNames["srv1.read_file"] {
-		t.Error("Expected srv1.read_file in tools/list")
-	}
-
-	// delete_file should be filtered (globally denied) even without namespace
-	if toolNames["srv1.delete_file"] {
-		t.Error("Expected srv1.delete_file to be filtered (globally denied, no namespace)")
+	if err := json.Unmarshal(responses[3], &resp); err != nil {
+		t.Fatalf("Unmarshal denied call: %v", err)
 	}
-
-	// Manager tools should survive unconditional filtering
-	if !toolNames["mcpmu.servers_list"] {
-		t.Error("Expected mcpmu.servers_list in tools/list after unconditional filtering")
+	if resp.Error == nil || resp.Error.Code != ErrCodeToolDenied {
+		t.Errorf("expected tool denied error ahead of schema validation, got %s", responses[3])
 	}
 }
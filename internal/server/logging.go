@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+)
+
+// mcpLogLevels are the MCP log levels (RFC 5424 severities), least severe
+// first.
+var mcpLogLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// handleLoggingSetLevel records the client's minimum log level and fans it
+// out to every upstream that supports logging, including ones started later.
+func (s *Server) handleLoggingSetLevel(ctx context.Context, params json.RawMessage) (any, *RPCError) {
+	var req struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, ErrInvalidParams(err.Error())
+	}
+	if !slices.Contains(mcpLogLevels, req.Level) {
+		return nil, ErrInvalidParams("unknown log level: " + req.Level)
+	}
+
+	s.mu.Lock()
+	s.clientLogLevel = req.Level
+	s.mu.Unlock()
+
+	s.supervisor.SetLogLevel(ctx, req.Level)
+	return struct{}{}, nil
+}
+
+// relayLogMessage passes an upstream's notifications/message to the client
+// with the server name prefixed to its logger, so the client can tell the
+// servers' logs apart. Messages below the client's level are dropped, in
+// case the upstream ignored logging/setLevel.
+func (s *Server) relayLogMessage(serverName string, params json.RawMessage) {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(params, &p); err != nil {
+		if DebugLogging {
+			log.Printf("notifications/message: malformed params from %s: %v", serverName, err)
+		}
+		return
+	}
+	var level, logger string
+	_ = json.Unmarshal(p["level"], &level)
+	_ = json.Unmarshal(p["logger"], &logger)
+
+	s.mu.RLock()
+	minLevel := s.clientLogLevel
+	s.mu.RUnlock()
+	if minLevel != "" && slices.Index(mcpLogLevels, level) < slices.Index(mcpLogLevels, minLevel) {
+		return
+	}
+
+	if logger != "" {
+		logger = serverName + "." + logger
+	} else {
+		logger = serverName
+	}
+	p["logger"], _ = json.Marshal(logger)
+
+	s.handlersWG.Go(func() {
+		s.sendNotificationWithParams("notifications/message", p)
+	})
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_RelaysUpstreamLogMessages(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	requestLog := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "work"}},
+				"logMessages": []map[string]any{
+					{"level": "info", "data": "chatty"},
+					{"level": "error", "logger": "db", "data": "connection lost"},
+					{"level": "warning", "data": "slow query"},
+				},
+				"requestLogPath": requestLog,
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	if init := c.response(t, 1); !strings.Contains(string(init.Result), `"logging":{}`) {
+		t.Errorf("expected the logging capability to be advertised, got %s", init.Result)
+	}
+	c.response(t, 2)
+
+	c.write(`{"jsonrpc":"2.0","id":3,"method":"logging/setLevel","params":{"level":"warning"}}`)
+	if resp := c.response(t, 3); resp.Error != nil {
+		t.Fatalf("logging/setLevel failed: %v", resp.Error)
+	}
+	waitFor(t, "logging/setLevel to reach the upstream", func() bool {
+		data, _ := os.ReadFile(requestLog)
+		return strings.Contains(string(data), "logging/setLevel")
+	})
+
+	c.write(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"alpha.work","arguments":{}}}`)
+
+	// Relayed messages are sent off the reader goroutine, so they may trail
+	// the response; the info message is below the level and never comes
+	type logMessage struct {
+		Level  string `json:"level"`
+		Logger string `json:"logger"`
+		Data   string `json:"data"`
+	}
+	got := map[string]logMessage{}
+	answered := false
+	for !answered || len(got) < 2 {
+		m := c.next(t, "tools/call response and log messages", func(m rpcMessage) bool {
+			return m.Method == "notifications/message" || idKey(m.ID) == "4"
+		})
+		if m.Method == "" {
+			answered = true
+			continue
+		}
+		var msg logMessage
+		if err := json.Unmarshal(m.Params, &msg); err != nil {
+			t.Fatalf("Unmarshal log message: %v", err)
+		}
+		got[msg.Data] = msg
+	}
+	want := map[string]logMessage{
+		"connection lost": {Level: "error", Logger: "alpha.db", Data: "connection lost"},
+		"slow query":      {Level: "warning", Logger: "alpha", Data: "slow query"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("relayed log messages = %+v, want %+v", got, want)
+	}
+
+	c.write(`{"jsonrpc":"2.0","id":5,"method":"logging/setLevel","params":{"level":"loud"}}`)
+	if resp := c.response(t, 5); resp.Error == nil || resp.Error.Code != ErrCodeInvalidParams {
+		t.Errorf("expected an unknown level to be rejected, got %+v", resp)
+	}
+}
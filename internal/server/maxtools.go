@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"log"
+)
+
+// MaxToolsPolicy decides what tools/list does when the permission-filtered
+// tool count exceeds --max-tools. Some clients misbehave with hundreds of
+// tools, so the limit is a prompt to tighten the namespace.
+type MaxToolsPolicy string
+
+const (
+	// MaxToolsError fails tools/list with ErrCodeTooManyTools.
+	MaxToolsError MaxToolsPolicy = "error"
+	// MaxToolsTruncate lists the first N tools and logs a warning.
+	MaxToolsTruncate MaxToolsPolicy = "truncate"
+)
+
+// ParseMaxToolsPolicy parses a --max-tools-mode value.
+func ParseMaxToolsPolicy(s string) (MaxToolsPolicy, error) {
+	switch p := MaxToolsPolicy(s); p {
+	case MaxToolsError, MaxToolsTruncate:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid max tools mode %q (must be error or truncate)", s)
+	}
+}
+
+// limitTools applies the --max-tools limit to a tools/list result. Tools
+// arrive sorted by server then name, so truncation keeps that order and
+// drops from the end.
+func (s *Server) limitTools(tools []AggregatedTool, namespaceName string) ([]AggregatedTool, *RPCError) {
+	maxTools := s.opts.MaxTools
+	if maxTools <= 0 || len(tools) <= maxTools {
+		return tools, nil
+	}
+	if s.opts.MaxToolsMode == MaxToolsTruncate {
+		log.Printf("Warning: %d tools exceed --max-tools %d, listing only the first %d; tighten the namespace to choose which", len(tools), maxTools, maxTools)
+		return tools[:maxTools], nil
+	}
+	log.Printf("Refusing tools/list: %d tools exceed --max-tools %d", len(tools), maxTools)
+	return nil, ErrTooManyTools(namespaceName, len(tools), maxTools)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestParseMaxToolsPolicy(t *testing.T) {
+	for _, s := range []string{"error", "truncate"} {
+		if p, err := ParseMaxToolsPolicy(s); err != nil || string(p) != s {
+			t.Errorf("ParseMaxToolsPolicy(%q) = %q, %v", s, p, err)
+		}
+	}
+	if _, err := ParseMaxToolsPolicy("drop"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestServer_MaxTools(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	fakeServer := func(tools string) config.ServerConfig {
+		enabled := true
+		return config.ServerConfig{
+			Kind:    config.ServerKindStdio,
+			Enabled: &enabled,
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestHelperProcess", "--"},
+			Env: map[string]string{
+				"GO_WANT_HELPER_PROCESS": "1",
+				"FAKE_MCP_CFG":           `{"tools":` + tools + `}`,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mode      MaxToolsPolicy
+		wantTools []string
+	}{
+		{name: "error", mode: MaxToolsError},
+		{name: "truncate", mode: MaxToolsTruncate, wantTools: []string{"alpha.a1", "alpha.a2", "beta.b1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &config.Config{
+				SchemaVersion: 1,
+				Servers: map[string]config.ServerConfig{
+					"alpha": fakeServer(`[{"name":"a1"},{"name":"a2"}]`),
+					"beta":  fakeServer(`[{"name":"b1"},{"name":"b2"},{"name":"b3"}]`),
+				},
+				Namespaces: map[string]config.NamespaceConfig{
+					"work": {ServerIDs: []string{"alpha", "beta"}},
+				},
+			}
+
+			var stdout strings.Builder
+			stdin := strings.NewReader(
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+					`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n",
+			)
+			srv, err := New(Options{
+				Config:          cfg,
+				PIDTrackerDir:   t.TempDir(),
+				Stdin:           stdin,
+				Stdout:          &stdout,
+				MaxTools:        3,
+				MaxToolsMode:    tt.mode,
+				ServerName:      "mcpmu-test",
+				ServerVersion:   "1.0.0",
+				ProtocolVersion: "2024-11-05",
+				LogLevel:        "error",
+			})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_ = srv.Run(ctx)
+
+			var resp struct {
+				Result *struct {
+					Tools []struct {
+						Name string `json:"name"`
+					} `json:"tools"`
+				} `json:"result"`
+				Error *RPCError `json:"error"`
+			}
+			if err := json.Unmarshal(parseResponsesByID(t, stdout.String())[2], &resp); err != nil {
+				t.Fatalf("Unmarshal tools/list response: %v\n%s", err, stdout.String())
+			}
+
+			if tt.mode == MaxToolsError {
+				if resp.Error == nil || resp.Error.Code != ErrCodeTooManyTools {
+					t.Fatalf("expected a too-many-tools error, got %s", stdout.String())
+				}
+				if !strings.Contains(resp.Error.Message, "exposes 5 tools") || !strings.Contains(resp.Error.Message, "namespace work") {
+					t.Errorf("expected the message to report the count and namespace, got %q", resp.Error.Message)
+				}
+				var data struct {
+					Count    int `json:"count"`
+					MaxTools int `json:"maxTools"`
+				}
+				if err := json.Unmarshal(resp.Error.Data, &data); err != nil || data.Count != 5 || data.MaxTools != 3 {
+					t.Errorf("unexpected error data %s", resp.Error.Data)
+				}
+				return
+			}
+
+			if resp.Error != nil {
+				t.Fatalf("tools/list failed: %v", resp.Error)
+			}
+			var names []string
+			for _, tool := range resp.Result.Tools {
+				names = append(names, tool.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantTools, ",") {
+				t.Errorf("tools = %v, want %v", names, tt.wantTools)
+			}
+		})
+	}
+}
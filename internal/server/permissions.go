@@ -1,6 +1,10 @@
 package server
 
 import (
+	"slices"
+	"strings"
+	"unicode"
+
 	"github.com/Bigsy/mcpmu/internal/config"
 )
 
@@ -48,52 +52,128 @@ func CheckPermission(cfg *config.Config, namespaceName, serverName, toolName str
 	return PermissionDefault
 }
 
-// IsToolAllowed checks if a tool call should be allowed, taking into account
-// per-server defaults and the namespace's DenyByDefault setting.
+// PermissionRule identifies which rule decided a permission check.
+type PermissionRule string
+
+const (
+	RuleGlobalDeny           PermissionRule = "global-deny"            // server deniedTools
+	RuleNoNamespace          PermissionRule = "no-namespace"           // no namespace selected, all tools allowed
+	RuleNamespaceNotFound    PermissionRule = "namespace-not-found"    // unknown namespace, allowed
+	RuleExplicitAllow        PermissionRule = "explicit-allow"         // ToolPermission entry
+	RuleExplicitDeny         PermissionRule = "explicit-deny"          // ToolPermission entry
+	RuleServerDenyDefault    PermissionRule = "server-deny-default"    // ServerDefaults entry
+	RuleServerAllowDefault   PermissionRule = "server-allow-default"   // ServerDefaults entry
+	RuleTrustedServer        PermissionRule = "trusted-server"         // ServerConfig.Trusted in a deny-by-default namespace
+	RuleNamespaceDenyDefault PermissionRule = "namespace-deny-default" // namespace DenyByDefault
+	RuleNamespaceAllow       PermissionRule = "namespace-allow-default"
+)
+
+// PermissionDecision is the outcome of a permission check and the rule that
+// produced it. Reason is empty for allowed tools.
+type PermissionDecision struct {
+	Allowed bool
+	Rule    PermissionRule
+	Reason  string
+}
+
+// ExplainToolPermission evaluates whether a tool call is allowed and reports
+// which rule decided it. IsToolAllowed is a thin wrapper around it, so the
+// explanation always matches what serve mode enforces.
 //
 // Evaluation order:
-// 1. If no namespace (namespaceName empty), allow all
-// 2. Check explicit ToolPermission → use it
-// 3. No explicit entry → check per-server default (ServerDefaults)
-// 4. No server default → check namespace DenyByDefault
-// 5. If deny → deny; otherwise → allow
-func IsToolAllowed(cfg *config.Config, namespaceName, serverName, toolName string) (bool, string) {
+// 1. Server-level global deny (applies even without a namespace)
+// 2. If no namespace (namespaceName empty), allow all
+// 3. Check explicit ToolPermission → use it
+// 4. No explicit entry → check per-server default (ServerDefaults)
+// 5. No server default → a trusted server allows its tools
+// 6. Otherwise → check namespace DenyByDefault
+func ExplainToolPermission(cfg *config.Config, namespaceName, serverName, toolName string) PermissionDecision {
 	// Check server-level global deny first (applies even without a namespace)
 	if srv, ok := cfg.GetServer(serverName); ok && srv.IsToolDenied(toolName) {
-		return false, "tool is globally denied on this server"
+		return PermissionDecision{Rule: RuleGlobalDeny, Reason: "tool is globally denied on this server"}
 	}
 
 	// No namespace means no further permission enforcement
 	if namespaceName == "" {
-		return true, ""
+		return PermissionDecision{Allowed: true, Rule: RuleNoNamespace}
 	}
 
 	// Get namespace for DenyByDefault setting
 	ns, ok := cfg.GetNamespace(namespaceName)
 	if !ok {
 		// Namespace not found, allow (shouldn't happen in normal use)
-		return true, ""
+		return PermissionDecision{Allowed: true, Rule: RuleNamespaceNotFound}
 	}
 
 	// Check permission
-	result := CheckPermission(cfg, namespaceName, serverName, toolName)
-	switch result {
+	switch CheckPermission(cfg, namespaceName, serverName, toolName) {
 	case PermissionAllow:
-		return true, ""
+		return PermissionDecision{Allowed: true, Rule: RuleExplicitAllow}
 	case PermissionDeny:
-		return false, "tool is explicitly denied in this namespace"
+		return PermissionDecision{Rule: RuleExplicitDeny, Reason: "tool is explicitly denied in this namespace"}
 	default:
 		// PermissionDefault - check per-server default first
 		if serverDefault, found := cfg.GetServerDefault(namespaceName, serverName); found {
 			if serverDefault {
-				return false, "tool is not explicitly allowed and server denies by default in this namespace"
+				return PermissionDecision{Rule: RuleServerDenyDefault, Reason: "tool is not explicitly allowed and server denies by default in this namespace"}
 			}
-			return true, ""
+			return PermissionDecision{Allowed: true, Rule: RuleServerAllowDefault}
 		}
-		// Fall through to namespace default
+		// Fall through to namespace default, which trusted servers override
 		if ns.DenyByDefault {
-			return false, "tool is not explicitly allowed and namespace denies by default"
+			if srv, ok := cfg.GetServer(serverName); ok && srv.Trusted {
+				return PermissionDecision{Allowed: true, Rule: RuleTrustedServer}
+			}
+			return PermissionDecision{Rule: RuleNamespaceDenyDefault, Reason: "tool is not explicitly allowed and namespace denies by default"}
+		}
+		return PermissionDecision{Allowed: true, Rule: RuleNamespaceAllow}
+	}
+}
+
+// IsToolAllowed checks if a tool call should be allowed, taking into account
+// per-server defaults, trusted servers and the namespace's DenyByDefault
+// setting. Returns the
+// denial reason when the tool is not allowed. See ExplainToolPermission for
+// the evaluation order.
+func IsToolAllowed(cfg *config.Config, namespaceName, serverName, toolName string) (bool, string) {
+	decision := ExplainToolPermission(cfg, namespaceName, serverName, toolName)
+	return decision.Allowed, decision.Reason
+}
+
+// MatchesDenyVerb reports whether toolName contains one of verbs as a word.
+// Tool names are split on non-alphanumeric characters and camelCase
+// boundaries, so delete_file, deleteFile and file-delete all match "delete"
+// while "undeleted" does not. Matching is case-insensitive.
+func MatchesDenyVerb(verbs []string, toolName string) bool {
+	for _, word := range toolNameWords(toolName) {
+		if slices.ContainsFunc(verbs, func(verb string) bool { return strings.EqualFold(verb, word) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolNameWords splits a tool name into lowercase words.
+func toolNameWords(toolName string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	runes := []rune(toolName)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			flush()
 		}
-		return true, ""
+		word = append(word, r)
 	}
+	flush()
+	return words
 }
@@ -363,3 +363,129 @@ func TestIsToolAllowed_ServerDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainToolPermission_TrustedServer(t *testing.T) {
+	t.Parallel()
+	cfg := config.NewConfig()
+	cfg.Servers["trusted"] = config.ServerConfig{Command: "echo", Trusted: true, DeniedTools: []string{"nuke"}}
+	cfg.Servers["other"] = config.ServerConfig{Command: "echo"}
+	cfg.Namespaces = map[string]config.NamespaceConfig{
+		"closed": {DenyByDefault: true},
+		"strict": {DenyByDefault: true, ServerDefaults: map[string]bool{"trusted": true}},
+		"open":   {},
+	}
+	cfg.ToolPermissions = []config.ToolPermission{
+		{Namespace: "closed", Server: "trusted", ToolName: "delete_file", Enabled: false},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		server    string
+		tool      string
+		allowed   bool
+		rule      PermissionRule
+	}{
+		{"trusted server allows unconfigured tools", "closed", "trusted", "read_file", true, RuleTrustedServer},
+		{"explicit deny beats trusted", "closed", "trusted", "delete_file", false, RuleExplicitDeny},
+		{"global deny beats trusted", "closed", "trusted", "nuke", false, RuleGlobalDeny},
+		{"server default beats trusted", "strict", "trusted", "read_file", false, RuleServerDenyDefault},
+		{"untrusted server still denied", "closed", "other", "read_file", false, RuleNamespaceDenyDefault},
+		{"allow namespace unaffected", "open", "trusted", "read_file", true, RuleNamespaceAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := ExplainToolPermission(cfg, tt.namespace, tt.server, tt.tool)
+			if decision.Allowed != tt.allowed || decision.Rule != tt.rule {
+				t.Errorf("ExplainToolPermission() = %+v, want allowed=%v rule=%s", decision, tt.allowed, tt.rule)
+			}
+		})
+	}
+}
+
+func TestExplainToolPermission(t *testing.T) {
+	t.Parallel()
+	cfg := config.NewConfig()
+	cfg.Servers["srv1"] = config.ServerConfig{Command: "echo", DeniedTools: []string{"nuke"}}
+	cfg.Servers["srv2"] = config.ServerConfig{Command: "echo"}
+	cfg.Namespaces = map[string]config.NamespaceConfig{
+		"open":   {ServerDefaults: map[string]bool{"srv2": true}},
+		"closed": {DenyByDefault: true, ServerDefaults: map[string]bool{"srv2": false}},
+	}
+	cfg.ToolPermissions = []config.ToolPermission{
+		{Namespace: "open", Server: "srv1", ToolName: "write_file", Enabled: false},
+		{Namespace: "closed", Server: "srv1", ToolName: "read_file", Enabled: true},
+		{Namespace: "closed", Server: "srv1", ToolName: "nuke", Enabled: true},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		server    string
+		tool      string
+		allowed   bool
+		rule      PermissionRule
+	}{
+		{"global deny beats explicit allow", "closed", "srv1", "nuke", false, RuleGlobalDeny},
+		{"global deny without namespace", "", "srv1", "nuke", false, RuleGlobalDeny},
+		{"no namespace allows all", "", "srv1", "anything", true, RuleNoNamespace},
+		{"unknown namespace allows", "missing", "srv1", "anything", true, RuleNamespaceNotFound},
+		{"explicit allow", "closed", "srv1", "read_file", true, RuleExplicitAllow},
+		{"explicit deny", "open", "srv1", "write_file", false, RuleExplicitDeny},
+		{"server deny default", "open", "srv2", "anything", false, RuleServerDenyDefault},
+		{"server allow default", "closed", "srv2", "anything", true, RuleServerAllowDefault},
+		{"namespace deny default", "closed", "srv1", "anything", false, RuleNamespaceDenyDefault},
+		{"namespace allow default", "open", "srv1", "anything", true, RuleNamespaceAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := ExplainToolPermission(cfg, tt.namespace, tt.server, tt.tool)
+			if decision.Allowed != tt.allowed || decision.Rule != tt.rule {
+				t.Errorf("ExplainToolPermission() = %+v, want allowed=%v rule=%s", decision, tt.allowed, tt.rule)
+			}
+			if decision.Allowed == (decision.Reason != "") {
+				t.Errorf("Expected a reason only for denied tools, got %+v", decision)
+			}
+
+			allowed, reason := IsToolAllowed(cfg, tt.namespace, tt.server, tt.tool)
+			if allowed != decision.Allowed || reason != decision.Reason {
+				t.Errorf("IsToolAllowed() = (%v, %q), want (%v, %q)", allowed, reason, decision.Allowed, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestMatchesDenyVerb(t *testing.T) {
+	t.Parallel()
+	verbs := config.DefaultReadOnlyDenyVerbs
+
+	tests := []struct {
+		toolName string
+		want     bool
+	}{
+		{"delete_file", true},
+		{"deleteFile", true},
+		{"file-delete", true},
+		{"WRITE_FILE", true},
+		{"set.value", true},
+		{"read_file", false},
+		{"list_directory", false},
+		{"undeleted_items", false},
+		{"settings", false},
+		{"getHTTPSettings", false},
+	}
+	for _, tt := range tests {
+		if got := MatchesDenyVerb(verbs, tt.toolName); got != tt.want {
+			t.Errorf("MatchesDenyVerb(%q) = %v, want %v", tt.toolName, got, tt.want)
+		}
+	}
+
+	if !MatchesDenyVerb([]string{"Purge"}, "purge_cache") {
+		t.Error("Expected custom verb list to match case-insensitively")
+	}
+	if MatchesDenyVerb([]string{"purge"}, "delete_file") {
+		t.Error("Expected custom verb list to replace the defaults")
+	}
+}
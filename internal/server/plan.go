@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// Plan describes what serve would expose with a set of options, worked out
+// from the config and the tool cache alone. Building one starts nothing.
+type Plan struct {
+	Namespace string          `json:"namespace,omitempty"`
+	Selection SelectionMethod `json:"selection"`
+	Servers   []PlannedServer `json:"servers"`
+}
+
+// PlannedServer is one server in a Plan.
+type PlannedServer struct {
+	Name string `json:"name"`
+	// Transport is "stdio" or "http"; empty for a missing server.
+	Transport string `json:"transport,omitempty"`
+	// Start is "eager", "lazy", "disabled" (never started) or "missing"
+	// (referenced by the namespace but not configured).
+	Start string `json:"start"`
+	// Cached is false when the tool cache has nothing for the server, so
+	// its tools are unknown until it has run once.
+	Cached bool `json:"cached"`
+	// Tools are the callable tool names tools/list would expose. Denied
+	// counts the cached tools the permissions filter out (listed, marked,
+	// under --show-denied-tools, but still not callable).
+	Tools  []string `json:"tools,omitempty"`
+	Denied int      `json:"denied,omitempty"`
+}
+
+// ToolCount returns the number of tools the plan exposes.
+func (p *Plan) ToolCount() int {
+	n := 0
+	for _, srv := range p.Servers {
+		n += len(srv.Tools)
+	}
+	return n
+}
+
+// BuildPlan resolves the namespace opts select and lists the servers serve
+// would run and, from the tool cache next to opts.ConfigPath, the tools it
+// would expose. Manager tools and --max-tools are left out. Servers whose
+// tools aren't cached are listed with Cached false.
+func BuildPlan(opts Options) (*Plan, error) {
+	s := &Server{opts: opts, cfg: opts.Config}
+	if rpcErr := s.resolveNamespace(); rpcErr != nil {
+		return nil, fmt.Errorf("%s", rpcErr.Message)
+	}
+
+	var toolCache *config.ToolCache
+	if opts.ConfigPath != "" {
+		tc, err := config.NewToolCache(opts.ConfigPath)
+		if err != nil {
+			log.Printf("Warning: failed to load tool cache: %v", err)
+		} else {
+			toolCache = tc
+		}
+	}
+
+	strippedPrefix := s.strippedToolPrefix()
+
+	plan := &Plan{Namespace: s.activeNamespaceName, Selection: s.selectionMethod}
+	for _, name := range s.activeServerNames {
+		planned := PlannedServer{Name: name, Start: "lazy"}
+		srv, ok := s.cfg.GetServer(name)
+		switch {
+		case !ok:
+			planned.Start = "missing"
+			plan.Servers = append(plan.Servers, planned)
+			continue
+		case !srv.IsEnabled():
+			planned.Start = "disabled"
+		case opts.EagerStart:
+			planned.Start = "eager"
+		}
+		planned.Transport = "stdio"
+		if srv.IsHTTP() {
+			planned.Transport = "http"
+		}
+
+		if toolCache != nil && srv.IsEnabled() {
+			var cached []config.CachedTool
+			cached, planned.Cached = toolCache.Get(name)
+			prefix := s.cfg.ToolPrefix(name)
+			for _, tool := range cached {
+				if !s.toolAllowed(s.activeNamespaceName, name, tool.Name) {
+					planned.Denied++
+					continue
+				}
+				qualified := prefix + "." + tool.Name
+				if strippedPrefix != "" {
+					qualified = strings.TrimPrefix(qualified, strippedPrefix+".")
+				}
+				planned.Tools = append(planned.Tools, qualified)
+			}
+			slices.Sort(planned.Tools)
+		}
+		plan.Servers = append(plan.Servers, planned)
+	}
+	return plan, nil
+}
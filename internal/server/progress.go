@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// progressCall is a tools/call in flight whose client asked for progress.
+type progressCall struct {
+	server string          // Upstream the call went to
+	token  json.RawMessage // The client's progress token
+}
+
+// progressTable maps the progress tokens mcpmu gives upstreams to the tokens
+// the client chose. Upstreams get mcpmu's own tokens, so two calls can't be
+// confused even if the client reuses a token across servers.
+type progressTable struct {
+	mu     sync.Mutex
+	nextID int64
+	calls  map[string]progressCall
+}
+
+func newProgressTable() *progressTable {
+	return &progressTable{calls: make(map[string]progressCall)}
+}
+
+// add registers a call to server with the client's token and returns the
+// token to send upstream in its place. remove must be called once the call
+// has been answered.
+func (t *progressTable) add(server string, token json.RawMessage) json.RawMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	upstream, _ := json.Marshal(fmt.Sprintf("mcpmu-progress-%d", t.nextID))
+	t.calls[idKey(upstream)] = progressCall{server: server, token: token}
+	return upstream
+}
+
+// remove forgets a call. No relay for it is running once remove returns.
+func (t *progressTable) remove(upstream json.RawMessage) {
+	t.mu.Lock()
+	delete(t.calls, idKey(upstream))
+	t.mu.Unlock()
+}
+
+// relay rewrites a notifications/progress from server to carry the client's
+// token and passes it to send. Notifications for calls that have been
+// answered, or that another server made, are dropped. send runs under the
+// table's lock so it can't outlive the call.
+func (t *progressTable) relay(server string, params json.RawMessage, send func(params map[string]json.RawMessage)) {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(params, &p); err != nil || p["progressToken"] == nil {
+		if DebugLogging {
+			log.Printf("notifications/progress: malformed params from %s: %v", server, err)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	call, ok := t.calls[idKey(p["progressToken"])]
+	if !ok || call.server != server {
+		if DebugLogging {
+			log.Printf("notifications/progress: dropping stray progress %s from %s", p["progressToken"], server)
+		}
+		return
+	}
+	p["progressToken"] = call.token
+	send(p)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_RelaysProgressBeforeResult(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":         []map[string]any{{"name": "slow"}},
+				"progressSteps": 3,
+			}),
+		},
+	}
+
+	c := startStubClient(t, Options{Config: cfg})
+	c.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"alpha.slow","arguments":{},"_meta":{"progressToken":"tok-1"}}}`,
+	)
+
+	var progress []float64
+	c.next(t, "tools/call response", func(m rpcMessage) bool {
+		if m.Method == "notifications/progress" {
+			var p struct {
+				ProgressToken string  `json:"progressToken"`
+				Progress      float64 `json:"progress"`
+				Total         float64 `json:"total"`
+			}
+			if err := json.Unmarshal(m.Params, &p); err != nil {
+				t.Fatalf("Unmarshal progress: %v", err)
+			}
+			if p.ProgressToken != "tok-1" || p.Total != 3 {
+				t.Errorf("expected the client's token and total 3, got %s", m.Params)
+			}
+			progress = append(progress, p.Progress)
+		}
+		return m.Method == "" && idKey(m.ID) == "2"
+	})
+	if len(progress) != 3 || progress[0] != 1 || progress[1] != 2 || progress[2] != 3 {
+		t.Errorf("expected progress 1, 2, 3 before the result, got %v", progress)
+	}
+
+	// Without a progress token the upstream isn't asked for progress
+	c.write(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"alpha.slow","arguments":{}}}`)
+	c.next(t, "tools/call response", func(m rpcMessage) bool {
+		if m.Method == "notifications/progress" {
+			t.Errorf("unexpected progress for a call without a token: %s", m.Params)
+		}
+		return m.Method == "" && idKey(m.ID) == "3"
+	})
+}
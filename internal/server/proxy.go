@@ -0,0 +1,279 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/process"
+)
+
+// ProxyOptions configures a single-server passthrough proxy.
+type ProxyOptions struct {
+	Config        *config.Config
+	ConfigPath    string // Used to derive the PID tracker directory
+	PIDTrackerDir string // Directory for PID tracking file (empty = derive from ConfigPath or default)
+	ServerName    string // Server to proxy (map key)
+	Namespace     string // Namespace whose permissions are applied (empty = global deny only)
+	Stdin         io.Reader
+	Stdout        io.Writer
+}
+
+// Proxy relays raw JSON-RPC traffic between a client on stdio and a single
+// upstream server. Messages are forwarded verbatim: no aggregation, no tool
+// name prefixing. The only interception is permission filtering — denied
+// tools are removed from tools/list results and tools/call for them is
+// answered locally with ErrToolDenied.
+type Proxy struct {
+	opts ProxyOptions
+	conn *process.RawConn
+
+	writeMu sync.Mutex
+
+	// In-flight client requests keyed by raw JSON-RPC id. The value records
+	// whether the request was tools/list so the response can be filtered.
+	mu        sync.Mutex
+	inflight  map[string]bool
+	clientEOF bool
+	idle      chan struct{} // closed once the client hit EOF and nothing is in flight
+	idleOnce  sync.Once
+}
+
+// proxyMessage is the subset of a JSON-RPC message the proxy inspects.
+type proxyMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// NewProxy creates a proxy for the given server.
+func NewProxy(opts ProxyOptions) (*Proxy, error) {
+	if _, ok := opts.Config.GetServer(opts.ServerName); !ok {
+		return nil, fmt.Errorf("server %q not found", opts.ServerName)
+	}
+	if opts.Namespace != "" {
+		if _, ok := opts.Config.GetNamespace(opts.Namespace); !ok {
+			return nil, fmt.Errorf("namespace %q not found", opts.Namespace)
+		}
+	}
+	return &Proxy{
+		opts:     opts,
+		inflight: make(map[string]bool),
+		idle:     make(chan struct{}),
+	}, nil
+}
+
+// Run connects to the upstream server and relays messages until the client
+// closes stdin (after outstanding requests are answered), the upstream goes
+// away, or ctx is cancelled.
+func (p *Proxy) Run(ctx context.Context) error {
+	srv, _ := p.opts.Config.GetServer(p.opts.ServerName)
+
+	pidTrackerDir := p.opts.PIDTrackerDir
+	if pidTrackerDir == "" && p.opts.ConfigPath != "" {
+		pidTrackerDir = filepath.Dir(p.opts.ConfigPath)
+	}
+	var configDir string
+	if p.opts.ConfigPath != "" {
+		configDir = filepath.Dir(p.opts.ConfigPath)
+	}
+	supervisor := process.NewSupervisorWithOptions(events.NewBus(), process.SupervisorOptions{
+		CredentialStoreMode:     p.opts.Config.MCPOAuthCredentialStore,
+		CredentialEncryption:    p.opts.Config.MCPOAuthCredentialEncryption,
+		PIDTrackerDir:           pidTrackerDir,
+		PIDFilePrefix:           "run",
+		ConfigDir:               configDir,
+		GlobalOAuthCallbackPort: p.opts.Config.MCPOAuthCallbackPort,
+		MaxLogLines:             p.opts.Config.MaxLogLines,
+	})
+
+	conn, err := supervisor.OpenRaw(ctx, p.opts.ServerName, srv)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", p.opts.ServerName, err)
+	}
+	p.conn = conn
+	defer func() { _ = conn.Close() }()
+
+	upstreamErr := make(chan error, 1)
+	go func() { upstreamErr <- p.relayUpstream(ctx) }()
+	go p.relayClient(ctx)
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-p.idle:
+		log.Printf("Client closed connection (EOF)")
+		return nil
+	case err := <-upstreamErr:
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("upstream %s: %w", p.opts.ServerName, err)
+	}
+}
+
+// relayClient forwards client messages upstream, answering denied tool
+// calls locally.
+func (p *Proxy) relayClient(ctx context.Context) {
+	reader := bufio.NewReader(p.opts.Stdin)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if msg := bytes.TrimSpace(line); len(msg) > 0 {
+			p.forwardClientMessage(ctx, msg)
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("Read error: %v", err)
+			}
+			p.mu.Lock()
+			p.clientEOF = true
+			p.checkIdleLocked()
+			p.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (p *Proxy) forwardClientMessage(ctx context.Context, data []byte) {
+	var msg proxyMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		// Not ours to judge — let the upstream report the parse error
+		p.sendUpstream(ctx, data)
+		return
+	}
+
+	isRequest := msg.Method != "" && len(msg.ID) > 0
+	if isRequest && msg.Method == "tools/call" {
+		var params toolsCallRequest
+		if err := json.Unmarshal(msg.Params, &params); err == nil {
+			if allowed, reason := IsToolAllowed(p.opts.Config, p.opts.Namespace, p.opts.ServerName, params.Name); !allowed {
+				p.writeClient(rpcResponse{
+					JSONRPC: "2.0",
+					ID:      msg.ID,
+					Error:   ErrToolDenied(params.Name, reason),
+				})
+				return
+			}
+		}
+	}
+
+	if isRequest {
+		p.mu.Lock()
+		p.inflight[string(msg.ID)] = msg.Method == "tools/list"
+		p.mu.Unlock()
+	}
+	p.sendUpstream(ctx, data)
+}
+
+func (p *Proxy) sendUpstream(ctx context.Context, data []byte) {
+	if err := p.conn.Send(ctx, data); err != nil {
+		log.Printf("Failed to forward message to %s: %v", p.opts.ServerName, err)
+	}
+}
+
+// relayUpstream forwards upstream messages to the client, filtering tools/list
+// results through the permission check.
+func (p *Proxy) relayUpstream(ctx context.Context) error {
+	for {
+		data, err := p.conn.Receive(ctx)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		var msg proxyMessage
+		if err := json.Unmarshal(data, &msg); err == nil && msg.Method == "" && len(msg.ID) > 0 {
+			p.mu.Lock()
+			isToolsList, tracked := p.inflight[string(msg.ID)]
+			delete(p.inflight, string(msg.ID))
+			p.mu.Unlock()
+
+			if tracked && isToolsList && len(msg.Result) > 0 {
+				data = p.filterToolsList(data, msg)
+			}
+		}
+
+		p.writeMu.Lock()
+		_, _ = p.opts.Stdout.Write(append(data, '\n'))
+		p.writeMu.Unlock()
+
+		p.mu.Lock()
+		p.checkIdleLocked()
+		p.mu.Unlock()
+	}
+}
+
+// filterToolsList removes denied tools from a tools/list response. Unknown
+// result fields are preserved. Returns the original data if nothing changed.
+func (p *Proxy) filterToolsList(data []byte, msg proxyMessage) []byte {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Result, &result); err != nil {
+		return data
+	}
+	var tools []json.RawMessage
+	if err := json.Unmarshal(result["tools"], &tools); err != nil {
+		return data
+	}
+
+	filtered := make([]json.RawMessage, 0, len(tools))
+	for _, raw := range tools {
+		var tool struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &tool); err != nil {
+			filtered = append(filtered, raw)
+			continue
+		}
+		if allowed, _ := IsToolAllowed(p.opts.Config, p.opts.Namespace, p.opts.ServerName, tool.Name); allowed {
+			filtered = append(filtered, raw)
+		}
+	}
+	if len(filtered) == len(tools) {
+		return data
+	}
+
+	toolsJSON, err := json.Marshal(filtered)
+	if err != nil {
+		return data
+	}
+	result["tools"] = toolsJSON
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return data
+	}
+	out, err := json.Marshal(rpcResponse{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(resultJSON)})
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func (p *Proxy) writeClient(resp rpcResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("Failed to marshal response: %v", err)
+		return
+	}
+	p.writeMu.Lock()
+	_, _ = p.opts.Stdout.Write(append(data, '\n'))
+	p.writeMu.Unlock()
+}
+
+// checkIdleLocked signals idle once the client is gone and every forwarded
+// request has been answered. Caller must hold p.mu.
+func (p *Proxy) checkIdleLocked() {
+	if p.clientEOF && len(p.inflight) == 0 {
+		p.idleOnce.Do(func() { close(p.idle) })
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func proxyTestConfig() *config.Config {
+	enabled := true
+	return &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": {
+				Kind:    config.ServerKindStdio,
+				Enabled: &enabled,
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestHelperProcess", "--"},
+				Env: map[string]string{
+					"GO_WANT_HELPER_PROCESS": "1",
+					"FAKE_MCP_CFG":           `{"tools":[{"name":"read_file","description":"Read"},{"name":"write_file","description":"Write"},{"name":"delete_file","description":"Delete"}],"echoToolCalls":true}`,
+				},
+				DeniedTools: []string{"delete_file"},
+			},
+		},
+		Namespaces: map[string]config.NamespaceConfig{
+			"ro": {ServerIDs: []string{"srv1"}},
+		},
+		ToolPermissions: []config.ToolPermission{
+			{Namespace: "ro", Server: "srv1", ToolName: "write_file", Enabled: false},
+		},
+	}
+}
+
+func runProxy(t *testing.T, cfg *config.Config, namespace, input string) map[int]json.RawMessage {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	proxy, err := NewProxy(ProxyOptions{
+		Config:        cfg,
+		PIDTrackerDir: t.TempDir(),
+		ServerName:    "srv1",
+		Namespace:     namespace,
+		Stdin:         strings.NewReader(input),
+		Stdout:        &stdout,
+	})
+	if err != nil {
+		t.Fatalf("NewProxy: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := proxy.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return parseResponsesByID(t, stdout.String())
+}
+
+func proxyToolNames(t *testing.T, raw json.RawMessage) map[string]bool {
+	t.Helper()
+	var resp struct {
+		Result struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatalf("Unmarshal tools/list: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("tools/list error: %v", resp.Error)
+	}
+	names := make(map[string]bool)
+	for _, tool := range resp.Result.Tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+func TestProxy_InitializeAndToolsList(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	responses := runProxy(t, proxyTestConfig(), "",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`+"\n"+
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n"+
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n",
+	)
+
+	// initialize is answered by the upstream itself, not by mcpmu
+	var initResp struct {
+		Result struct {
+			ServerInfo struct {
+				Name string `json:"name"`
+			} `json:"serverInfo"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[1], &initResp); err != nil {
+		t.Fatalf("Unmarshal initialize: %v", err)
+	}
+	if initResp.Error != nil {
+		t.Fatalf("initialize error: %v", initResp.Error)
+	}
+	if initResp.Result.ServerInfo.Name == "" || initResp.Result.ServerInfo.Name == "mcpmu" {
+		t.Errorf("Expected upstream serverInfo, got %q", initResp.Result.ServerInfo.Name)
+	}
+
+	names := proxyToolNames(t, responses[2])
+	if !names["read_file"] || !names["write_file"] {
+		t.Errorf("Expected unprefixed upstream tools, got %v", names)
+	}
+	if names["srv1.read_file"] {
+		t.Error("Expected proxy not to prefix tool names")
+	}
+	// Global deny applies even without a namespace
+	if names["delete_file"] {
+		t.Error("Expected globally denied delete_file to be filtered")
+	}
+}
+
+func TestProxy_NamespacePermissions(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	responses := runProxy(t, proxyTestConfig(), "ro",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`+"\n"+
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n"+
+			`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"write_file","arguments":{}}}`+"\n"+
+			`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"read_file","arguments":{}}}`+"\n",
+	)
+
+	names := proxyToolNames(t, responses[2])
+	if !names["read_file"] {
+		t.Errorf("Expected read_file in tools/list, got %v", names)
+	}
+	if names["write_file"] {
+		t.Error("Expected write_file to be filtered by namespace permissions")
+	}
+
+	var denied struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[3], &denied); err != nil {
+		t.Fatalf("Unmarshal denied call: %v", err)
+	}
+	if denied.Error == nil || denied.Error.Code != ErrCodeToolDenied {
+		t.Errorf("Expected tool denied error for write_file, got %+v", denied.Error)
+	}
+
+	var allowed struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[4], &allowed); err != nil {
+		t.Fatalf("Unmarshal allowed call: %v", err)
+	}
+	if allowed.Error != nil {
+		t.Fatalf("tools/call read_file error: %v", allowed.Error)
+	}
+	if len(allowed.Result.Content) == 0 || !strings.Contains(allowed.Result.Content[0].Text, "Called tool: read_file") {
+		t.Errorf("Expected read_file call to reach upstream, got %+v", allowed.Result)
+	}
+}
+
+func TestNewProxy_UnknownServer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewProxy(ProxyOptions{Config: proxyTestConfig(), ServerName: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
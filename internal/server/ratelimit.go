@@ -0,0 +1,60 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// callLimiter enforces per-namespace MaxCallsPerMinute with one token bucket
+// per namespace. Buckets hold up to the limit (allowing a burst of that many
+// calls) and refill continuously at limit/minute. It lives on the Server so
+// buckets survive config reloads; a bucket is reset when its limit changes.
+type callLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	limit  int
+	tokens float64
+	last   time.Time
+}
+
+func newCallLimiter() *callLimiter {
+	return &callLimiter{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the namespace's bucket. When the bucket is empty
+// it returns false and how long until the next token is available. A limit
+// of zero or less always allows.
+func (l *callLimiter) allow(namespaceName string, limit int) (bool, time.Duration) {
+	if limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[namespaceName]
+	if !ok || b.limit != limit {
+		b = &tokenBucket{limit: limit, tokens: float64(limit), last: now}
+		l.buckets[namespaceName] = b
+	}
+
+	perToken := time.Minute / time.Duration(limit)
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(limit), b.tokens+float64(elapsed)/float64(perToken))
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	b.tokens--
+	return true, 0
+}
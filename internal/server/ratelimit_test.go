@@ -0,0 +1,210 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// fakeClock is a manually advanced clock for the call limiter.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestCallLimiter_Allow(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	l := newCallLimiter()
+	l.now = clock.Now
+
+	// Burst up to the limit, then reject
+	for i := range 3 {
+		if ok, _ := l.allow("work", 3); !ok {
+			t.Fatalf("call %d rejected within limit", i+1)
+		}
+	}
+	ok, retryAfter := l.allow("work", 3)
+	if ok {
+		t.Fatal("expected call over the limit to be rejected")
+	}
+	if retryAfter != 20*time.Second {
+		t.Errorf("retryAfter = %v, want 20s", retryAfter)
+	}
+
+	// Other namespaces have their own bucket
+	if ok, _ := l.allow("personal", 3); !ok {
+		t.Error("expected separate bucket per namespace")
+	}
+
+	// One token refills every minute/limit
+	clock.Advance(20 * time.Second)
+	if ok, _ := l.allow("work", 3); !ok {
+		t.Error("expected a call to be allowed after one refill interval")
+	}
+	if ok, _ := l.allow("work", 3); ok {
+		t.Error("expected only one token to have refilled")
+	}
+
+	// A full window refills the whole bucket, but never beyond the limit
+	clock.Advance(5 * time.Minute)
+	for i := range 3 {
+		if ok, _ := l.allow("work", 3); !ok {
+			t.Fatalf("call %d rejected after window reset", i+1)
+		}
+	}
+	if ok, _ := l.allow("work", 3); ok {
+		t.Error("expected bucket to be capped at the limit")
+	}
+
+	// Zero disables limiting
+	for range 100 {
+		if ok, _ := l.allow("unlimited", 0); !ok {
+			t.Fatal("expected limit 0 to allow every call")
+		}
+	}
+}
+
+func TestServer_NamespaceRateLimit(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	srv := fakeServerConfig(t, map[string]any{
+		"tools":         []map[string]any{{"name": "query", "description": "Query"}},
+		"echoToolCalls": true,
+	})
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"db": srv},
+		Namespaces: map[string]config.NamespaceConfig{
+			"work": {ServerIDs: []string{"db"}, MaxCallsPerMinute: 2},
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg, Namespace: "work"})
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	h.srv.limiter.now = clock.Now
+
+	call := func(id int) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"db.query","arguments":{}}}`, id)
+	}
+	h.write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	h.write(call(2), call(3), call(4))
+	h.settle(2 * time.Second)
+
+	// After the window the bucket has refilled
+	clock.Advance(time.Minute)
+	h.write(call(5))
+	h.settle(500 * time.Millisecond)
+	h.close(t)
+
+	responses := parseResponsesByID(t, h.stdout.String())
+
+	var limited int
+	for id := 2; id <= 4; id++ {
+		var resp struct {
+			Error *RPCError `json:"error"`
+		}
+		if err := json.Unmarshal(responses[id], &resp); err != nil {
+			t.Fatalf("Unmarshal response %d: %v", id, err)
+		}
+		if resp.Error == nil {
+			continue
+		}
+		if resp.Error.Code != ErrCodeRateLimited {
+			t.Errorf("response %d: unexpected error %v", id, resp.Error)
+			continue
+		}
+		var data struct {
+			Namespace    string `json:"namespace"`
+			RetryAfterMs int64  `json:"retryAfterMs"`
+		}
+		if err := json.Unmarshal(resp.Error.Data, &data); err != nil || data.Namespace != "work" || data.RetryAfterMs != 30000 {
+			t.Errorf("response %d: error data = %s, want namespace work and retryAfterMs 30000", id, resp.Error.Data)
+		}
+		limited++
+	}
+	if limited != 1 {
+		t.Errorf("expected exactly 1 of 3 calls to be rate limited, got %d", limited)
+	}
+
+	var after struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[5], &after); err != nil {
+		t.Fatalf("Unmarshal response 5: %v", err)
+	}
+	if after.Error != nil {
+		t.Errorf("expected call after the window to succeed, got %v", after.Error)
+	}
+}
+
+func TestServer_NamespaceRateLimit_DeniedCallsDontCount(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	srv := fakeServerConfig(t, map[string]any{
+		"tools":         []map[string]any{{"name": "query", "description": "Query"}, {"name": "drop", "description": "Drop"}},
+		"echoToolCalls": true,
+	})
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"db": srv},
+		Namespaces: map[string]config.NamespaceConfig{
+			"work": {ServerIDs: []string{"db"}, MaxCallsPerMinute: 1},
+		},
+		ToolPermissions: []config.ToolPermission{
+			{Namespace: "work", Server: "db", ToolName: "drop", Enabled: false},
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg, Namespace: "work"})
+	h.srv.limiter.now = (&fakeClock{now: time.Unix(1000, 0)}).Now
+
+	call := func(id int, tool string) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"db.%s","arguments":{}}}`, id, tool)
+	}
+	h.write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+	h.write(call(2, "drop"), call(3, "drop"))
+	h.settle(500 * time.Millisecond)
+	h.write(call(4, "query"))
+	h.settle(2 * time.Second)
+	h.close(t)
+
+	responses := parseResponsesByID(t, h.stdout.String())
+	wantCodes := map[int]int{2: ErrCodeToolDenied, 3: ErrCodeToolDenied, 4: 0}
+	for id, want := range wantCodes {
+		var resp struct {
+			Error *RPCError `json:"error"`
+		}
+		if err := json.Unmarshal(responses[id], &resp); err != nil {
+			t.Fatalf("Unmarshal response %d: %v", id, err)
+		}
+		got := 0
+		if resp.Error != nil {
+			got = resp.Error.Code
+		}
+		if got != want {
+			t.Errorf("response %d: error = %v, want code %d", id, resp.Error, want)
+		}
+	}
+}
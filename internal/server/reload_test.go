@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/process"
 )
 
 // testDebounceDelay is a short debounce delay for tests.
@@ -606,6 +607,86 @@ func TestServer_WatchConfig_IgnoresParseErrors(t *testing.T) {
 	}
 }
 
+func TestServer_WatchConfig_SurvivesDeleteAndRecreate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		remove func(dir, configPath string) error
+	}{
+		{"file", func(dir, configPath string) error { return os.Remove(configPath) }},
+		{"directory", func(dir, configPath string) error { return os.RemoveAll(dir) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := filepath.Join(t.TempDir(), "mcpmu")
+			if err := os.Mkdir(dir, 0o700); err != nil {
+				t.Fatal(err)
+			}
+			configPath := filepath.Join(dir, "config.json")
+
+			configWith := func(names ...string) *config.Config {
+				cfg := &config.Config{SchemaVersion: 1, Servers: map[string]config.ServerConfig{}}
+				for _, name := range names {
+					cfg.Servers[name] = config.ServerConfig{Command: "echo"}
+				}
+				return cfg
+			}
+			initialCfg := configWith("srv1")
+			if err := config.SaveTo(initialCfg, configPath); err != nil {
+				t.Fatalf("Failed to save initial config: %v", err)
+			}
+
+			h := startSubscribeTestServer(t, Options{
+				Config:        initialCfg,
+				ConfigPath:    configPath,
+				DebounceDelay: testDebounceDelay,
+			})
+			defer h.close(t)
+			h.write(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`)
+			time.Sleep(50 * time.Millisecond)
+
+			serverCount := func() int {
+				h.srv.mu.RLock()
+				defer h.srv.mu.RUnlock()
+				return len(h.srv.cfg.Servers)
+			}
+			waitForServerCount := func(want int) {
+				t.Helper()
+				deadline := time.Now().Add(5 * time.Second)
+				for serverCount() != want {
+					if time.Now().After(deadline) {
+						t.Fatalf("expected %d servers after reload, got %d", want, serverCount())
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+
+			if err := tt.remove(dir, configPath); err != nil {
+				t.Fatalf("remove: %v", err)
+			}
+			time.Sleep(100 * time.Millisecond)
+			if got := serverCount(); got != 1 {
+				t.Fatalf("expected the last good config to be kept while the file is missing, got %d servers", got)
+			}
+
+			if err := os.MkdirAll(dir, 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := config.SaveTo(configWith("srv1", "srv2"), configPath); err != nil {
+				t.Fatalf("Failed to recreate config: %v", err)
+			}
+			waitForServerCount(2)
+
+			// Watching carries on after the recreate
+			if err := config.SaveTo(configWith("srv1", "srv2", "srv3"), configPath); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			waitForServerCount(3)
+		})
+	}
+}
+
 // TestEndToEnd_HotReload_ToolsChange is an integration test that:
 // 1. Builds the real binary
 // 2. Starts serve mode with a config file
@@ -862,10 +943,11 @@ func TestEndToEnd_HotReload_ToolsChange(t *testing.T) {
 // the reload is processed AFTER the current request completes, not during it.
 // This test verifies:
 //
-// 1. In-flight requests complete before reload is applied (serialization guarantee)
-// 2. Reload kills upstream server processes (StopAll)
-// 3. Subsequent tool calls may fail with EOF (stale handle) until server restarts
-// 4. Server remains functional for other operations (tools/list works)
+//  1. The in-flight request completes: reload drains calls to a changed server
+//     before stopping it, instead of killing it mid-request
+//  2. New calls to the draining server are rejected until it has stopped, then
+//     it restarts lazily with the new config
+//  3. Server remains functional for other operations (tools/list works)
 //
 // This is important test coverage for ensuring graceful degradation when
 // config changes happen during active use.
@@ -1033,14 +1115,13 @@ func TestServer_ReloadDuringActiveRequest(t *testing.T) {
 	// Wait for hot-reload (debounce ~150ms + processing)
 	time.Sleep(400 * time.Millisecond)
 
-	// Step 4: Wait for the in-flight tools/call to complete
-	// It should either fail (due to StopAll killing the upstream) or complete (if timing allows)
-	// With a 2s delay on the fake server and reload happening ~1s in, we expect the request to be interrupted
+	// Step 4: Wait for the in-flight tools/call to complete. The reload
+	// changed slow-srv's config, so it is drained (not killed) before restart.
 	requestWasInterrupted := false
 	select {
 	case result := <-toolsCallDone:
 		if result.err != nil {
-			t.Logf("In-flight tools/call returned IO error (expected if killed by reload): %v", result.err)
+			t.Logf("In-flight tools/call returned IO error: %v", result.err)
 			requestWasInterrupted = true
 		} else {
 			var toolsCallResp struct {
@@ -1058,25 +1139,19 @@ func TestServer_ReloadDuringActiveRequest(t *testing.T) {
 				t.Logf("In-flight tools/call response parse error: %v (raw: %s)", err, string(result.resp))
 				requestWasInterrupted = true
 			} else if toolsCallResp.Error != nil {
-				t.Logf("In-flight tools/call returned RPC error (expected if killed by reload): code=%d msg=%s",
+				t.Logf("In-flight tools/call returned RPC error: code=%d msg=%s",
 					toolsCallResp.Error.Code, toolsCallResp.Error.Message)
 				requestWasInterrupted = true
 			} else {
-				// Request completed successfully - this can happen if the reload timing didn't interrupt it
-				// We log this but don't fail the test since timing is inherently non-deterministic
-				t.Logf("In-flight tools/call completed successfully (timing allowed completion before reload)")
+				t.Logf("In-flight tools/call completed successfully (drained before the server was stopped)")
 			}
 		}
 	case <-time.After(5 * time.Second):
 		t.Fatal("In-flight tools/call did not complete within timeout")
 	}
 
-	// Log whether the request was interrupted (the main purpose of this test is to verify
-	// the server handles this scenario gracefully, not to guarantee interruption)
 	if requestWasInterrupted {
-		t.Log("Request was interrupted by config reload (expected behavior)")
-	} else {
-		t.Log("Request completed before reload took effect (acceptable - timing dependent)")
+		t.Error("In-flight request was interrupted by config reload; expected it to drain")
 	}
 
 	// Step 5: Verify server is still functional after reload by calling tools/list
@@ -1151,3 +1226,382 @@ func TestServer_ReloadDuringActiveRequest(t *testing.T) {
 
 	t.Log("Reload during active request test passed!")
 }
+
+// waitForReload blocks until the server has swapped in cfg.
+func waitForReload(t *testing.T, srv *Server, cfg *config.Config) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		srv.mu.RLock()
+		applied := srv.cfg == cfg
+		srv.mu.RUnlock()
+		if applied {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("config reload was not applied")
+}
+
+// waitForRunningHandle blocks until the named server is running with tools discovered.
+func waitForRunningHandle(t *testing.T, srv *Server, name string) *process.Handle {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if handle := srv.supervisor.Get(name); handle != nil && handle.IsRunning() && handle.ToolsReady() {
+			return handle
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server %s did not start", name)
+	return nil
+}
+
+func TestServer_Reload_KeepsPersistingServerHandle(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	srv1 := fakeServerConfig(t, map[string]any{
+		"tools": []any{map[string]any{"name": "tool_a", "description": "Tool A"}},
+	})
+	oldCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"srv1": srv1},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: oldCfg, EagerStart: true})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	before := waitForRunningHandle(t, h.srv, "srv1")
+
+	// Reload that only adds another server
+	newCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"srv1": srv1,
+			"srv2": fakeServerConfig(t, map[string]any{
+				"tools": []any{map[string]any{"name": "tool_b", "description": "Tool B"}},
+			}),
+		},
+	}
+	h.srv.reloadCh <- newCfg
+	waitForReload(t, h.srv, newCfg)
+	waitForRunningHandle(t, h.srv, "srv2")
+
+	after := h.srv.supervisor.Get("srv1")
+	if after != before {
+		t.Error("Expected srv1 handle to be reused across reload, but it was rebuilt")
+	}
+	if !after.IsRunning() {
+		t.Error("Expected srv1 to still be running after reload")
+	}
+
+	h.close(t)
+}
+
+func TestServer_Reload_KeepsUnchangedServerWhenAnotherChanges(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	srv1 := fakeServerConfig(t, map[string]any{
+		"tools": []any{map[string]any{"name": "tool_a", "description": "Tool A"}},
+	})
+	srv2 := fakeServerConfig(t, map[string]any{
+		"tools": []any{map[string]any{"name": "tool_b", "description": "Tool B"}},
+	})
+	oldCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"srv1": srv1, "srv2": srv2},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: oldCfg, EagerStart: true})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	srv1Before := waitForRunningHandle(t, h.srv, "srv1")
+	srv2Before := waitForRunningHandle(t, h.srv, "srv2")
+
+	// Change srv2's environment; srv1 is untouched apart from a deny list edit,
+	// which does not affect its connection
+	changedSrv2 := fakeServerConfig(t, map[string]any{
+		"tools": []any{map[string]any{"name": "tool_b", "description": "Tool B"}},
+	})
+	changedSrv2.Env["RELOAD_GENERATION"] = "2"
+	srv1.DeniedTools = []string{"tool_x"}
+	newCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"srv1": srv1, "srv2": changedSrv2},
+	}
+	h.srv.reloadCh <- newCfg
+	waitForReload(t, h.srv, newCfg)
+
+	// srv2 is restarted with its new config
+	deadline := time.Now().Add(10 * time.Second)
+	var srv2After *process.Handle
+	for time.Now().Before(deadline) {
+		if handle := h.srv.supervisor.Get("srv2"); handle != nil && handle != srv2Before && handle.IsRunning() {
+			srv2After = handle
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if srv2After == nil {
+		t.Error("Expected changed srv2 to be restarted with a new handle")
+	}
+	if srv2Before.IsRunning() {
+		t.Error("Expected the old srv2 handle to be stopped")
+	}
+
+	if got := h.srv.supervisor.Get("srv1"); got != srv1Before {
+		t.Error("Expected unchanged srv1 handle to survive the reload")
+	}
+	if !srv1Before.IsRunning() {
+		t.Error("Expected srv1 to still be running after reload")
+	}
+
+	h.close(t)
+}
+
+// TestServer_Reload_StartsAddedServerDuringDrain: a server added by a reload
+// starts straight away, without waiting for a changed server's in-flight
+// calls to drain; the changed server is restarted once they have.
+func TestServer_Reload_StartsAddedServerDuringDrain(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	busy := fakeServerConfig(t, map[string]any{
+		"tools":  []any{map[string]any{"name": "slow_tool", "description": "Slow"}},
+		"delays": map[string]any{"tools/call": int64(2 * time.Second)},
+	})
+	oldCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"busy": busy},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: oldCfg, EagerStart: true})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	busyBefore := waitForRunningHandle(t, h.srv, "busy")
+
+	h.write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"busy.slow_tool","arguments":{}}}`)
+	time.Sleep(100 * time.Millisecond) // let the call reach the upstream
+
+	// Reload that changes the busy server and adds another
+	changedBusy := fakeServerConfig(t, map[string]any{
+		"tools":  []any{map[string]any{"name": "slow_tool", "description": "Slow"}},
+		"delays": map[string]any{"tools/call": int64(2 * time.Second)},
+	})
+	changedBusy.Env["RELOAD_GENERATION"] = "2"
+	newCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"busy": changedBusy,
+			"added": fakeServerConfig(t, map[string]any{
+				"tools": []any{map[string]any{"name": "tool_b", "description": "Tool B"}},
+			}),
+		},
+	}
+	h.srv.reloadCh <- newCfg
+	waitForReload(t, h.srv, newCfg)
+
+	waitForRunningHandle(t, h.srv, "added")
+	if !busyBefore.IsRunning() {
+		t.Fatal("Expected the added server to start while the busy server was still draining")
+	}
+
+	// The busy server is restarted with its new config once drained
+	deadline := time.Now().Add(10 * time.Second)
+	var busyAfter *process.Handle
+	for time.Now().Before(deadline) {
+		if handle := h.srv.supervisor.Get("busy"); handle != nil && handle != busyBefore && handle.IsRunning() {
+			busyAfter = handle
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if busyAfter == nil {
+		t.Error("Expected the changed busy server to be restarted with a new handle")
+	}
+	if busyBefore.IsRunning() {
+		t.Error("Expected the old busy handle to be stopped once drained")
+	}
+
+	h.close(t)
+	responses := parseResponsesByID(t, h.stdout.String())
+	var resp struct {
+		Error *RPCError `json:"error"`
+	}
+	if err := json.Unmarshal(responses[2], &resp); err != nil {
+		t.Fatalf("Unmarshal id 2: %v", err)
+	}
+	if resp.Error != nil {
+		t.Errorf("Expected the in-flight call to finish, got error: %v", resp.Error)
+	}
+}
+
+func TestServer_Reload_DrainsInFlightCallsOnRemovedServer(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	oldCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"slow": fakeServerConfig(t, map[string]any{
+				"tools":         []any{map[string]any{"name": "slow_tool", "description": "Slow"}},
+				"delays":        map[string]any{"tools/call": int64(500 * time.Millisecond)},
+				"echoToolCalls": true,
+			}),
+			"other": fakeServerConfig(t, map[string]any{
+				"tools":         []any{map[string]any{"name": "fast_tool", "description": "Fast"}},
+				"echoToolCalls": true,
+			}),
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: oldCfg, EagerStart: true})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	slowHandle := waitForRunningHandle(t, h.srv, "slow")
+	waitForRunningHandle(t, h.srv, "other")
+
+	h.write(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow.slow_tool","arguments":{}}}`)
+	time.Sleep(100 * time.Millisecond) // let the call reach the upstream
+
+	// Reload that removes the server with the in-flight call
+	newCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"other": oldCfg.Servers["other"]},
+	}
+	h.srv.reloadCh <- newCfg
+	waitForReload(t, h.srv, newCfg)
+
+	// Calls to the remaining server are unaffected by the drain
+	h.write(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"other.fast_tool","arguments":{}}}`)
+	h.settle(800 * time.Millisecond)
+
+	if slowHandle.IsRunning() {
+		t.Error("Expected removed server to be stopped once drained")
+	}
+
+	h.close(t)
+	responses := parseResponsesByID(t, h.stdout.String())
+	for _, id := range []int{2, 3} {
+		var resp struct {
+			Error *RPCError `json:"error"`
+		}
+		raw, ok := responses[id]
+		if !ok {
+			t.Fatalf("Missing response for id %d", id)
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			t.Fatalf("Unmarshal id %d: %v", id, err)
+		}
+		if resp.Error != nil {
+			t.Errorf("Expected id %d to succeed, got error: %v", id, resp.Error)
+		}
+	}
+}
+
+func TestInflightTracker_DrainRejectsNewCalls(t *testing.T) {
+	t.Parallel()
+
+	tracker := newInflightTracker()
+	if !tracker.acquire("srv") {
+		t.Fatal("acquire should succeed before drain")
+	}
+
+	tracker.startDrain([]string{"srv"})
+	if tracker.acquire("srv") {
+		t.Error("acquire should fail while draining")
+	}
+	if !tracker.acquire("other") {
+		t.Error("acquire should succeed for servers that are not draining")
+	}
+	tracker.release("other")
+
+	done := make(chan []string, 1)
+	go func() { done <- tracker.wait(context.Background(), []string{"srv"}, 5*time.Second) }()
+
+	time.Sleep(20 * time.Millisecond)
+	tracker.release("srv")
+
+	select {
+	case busy := <-done:
+		if len(busy) != 0 {
+			t.Errorf("Expected drain to complete, still busy: %v", busy)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("wait did not return after in-flight call released")
+	}
+
+	// Timeout path
+	tracker.acquire("other")
+	if busy := tracker.wait(context.Background(), []string{"other"}, 20*time.Millisecond); len(busy) != 1 {
+		t.Errorf("Expected wait to time out with other busy, got %v", busy)
+	}
+
+	tracker.finishDrain([]string{"srv"})
+	if !tracker.acquire("srv") {
+		t.Error("acquire should succeed after drain finished")
+	}
+}
+
+func TestServer_RemovedServersSummary_ListsServersAndTools(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	disabled := false
+	srv1 := fakeServerConfig(t, map[string]any{
+		"tools": []any{
+			map[string]any{"name": "tool_a", "description": "Tool A"},
+			map[string]any{"name": "tool_b", "description": "Tool B"},
+		},
+	})
+	idle := fakeServerConfig(t, map[string]any{})
+	idle.Enabled = &disabled
+	oldCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"srv1": srv1, "idle": idle, "kept": idle},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: oldCfg, EagerStart: true})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	)
+	waitForRunningHandle(t, h.srv, "srv1")
+
+	newCfg := &config.Config{
+		SchemaVersion: 1,
+		Servers:       map[string]config.ServerConfig{"kept": idle},
+	}
+	removed := oldCfg.RemovedServers(newCfg)
+	if strings.Join(removed, ",") != "idle,srv1" {
+		t.Fatalf("RemovedServers = %v, want [idle srv1]", removed)
+	}
+
+	got := h.srv.removedServersSummary(removed)
+	want := "Config reload removes 2 server(s): idle (not running); srv1 (2 tools: tool_a, tool_b)"
+	if got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+
+	h.close(t)
+}
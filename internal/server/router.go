@@ -3,12 +3,15 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/mcp"
 	"github.com/Bigsy/mcpmu/internal/process"
 )
 
@@ -45,10 +48,10 @@ func (r *Router) SetActiveNamespace(namespaceName string, selection SelectionMet
 
 // CallTool routes a tool call to the appropriate server and returns the result.
 func (r *Router) CallTool(ctx context.Context, qualifiedName string, arguments json.RawMessage) (*ToolCallResult, *RPCError) {
-	log.Printf("CallTool: %s", qualifiedName)
+	logf(ctx, "CallTool: %s", qualifiedName)
 
 	// Parse the tool name
-	serverName, toolName, isManager := ParseToolName(qualifiedName)
+	serverName, toolName, isManager := ResolveToolName(r.cfg, qualifiedName)
 
 	// Handle manager tools (always allowed, no permission check)
 	if isManager {
@@ -89,7 +92,10 @@ func (r *Router) CallTool(ctx context.Context, qualifiedName string, arguments j
 		}
 	}
 
-	// Call the tool on the upstream server
+	// Call the tool on the upstream server. Activity is recorded on both
+	// sides of the call so a long call doesn't count as idle time.
+	handle.Touch()
+	defer handle.Touch()
 	client := handle.Client()
 	if client == nil {
 		return nil, ErrServerNotRunning(serverName)
@@ -100,16 +106,36 @@ func (r *Router) CallTool(ctx context.Context, qualifiedName string, arguments j
 	callCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	logf(ctx, "Dispatching %s to upstream %s", toolName, serverName)
 	result, err := client.CallTool(callCtx, toolName, arguments)
 	if err != nil {
 		if callCtx.Err() == context.DeadlineExceeded {
 			return nil, ErrToolCallTimeout(serverName, toolName)
 		}
 
-		// On 4xx errors (stale session, server reset, etc.), reinitialize and retry once.
-		// 401 is excluded — the transport returns UnauthorizedError for that, not "request failed: 4xx".
-		if isRetriableHTTPError(err) {
-			log.Printf("CallTool: 4xx error for %s.%s, reinitializing: %v", serverName, toolName, err)
+		// A 401 rejecting an OAuth token (revoked early, clock skew) is retried
+		// once with a freshly refreshed token.
+		var unauthErr *mcp.UnauthorizedError
+		if errors.As(err, &unauthErr) && unauthErr.TokenRejected() && handle.AuthStatus() == mcp.AuthStatusOAuthOK {
+			logf(ctx, "CallTool: %s rejected its OAuth token, refreshing and retrying %s", serverName, toolName)
+
+			if refreshErr := r.supervisor.RefreshOAuthToken(ctx, serverName, unauthErr.Token); refreshErr != nil {
+				return nil, ErrInternalError(fmt.Sprintf("tool call failed: %v (token refresh: %v)", err, refreshErr))
+			}
+
+			retryCtx, retryCancel := context.WithTimeout(ctx, timeout)
+			defer retryCancel()
+
+			result, err = client.CallTool(retryCtx, toolName, arguments)
+			if err != nil {
+				return nil, ErrInternalError(fmt.Sprintf("tool call failed after token refresh: %v", err))
+			}
+
+			logf(ctx, "CallTool: retry succeeded for %s.%s after token refresh", serverName, toolName)
+		} else if isRetriableHTTPError(err) {
+			// On 4xx errors (stale session, server reset, etc.), reinitialize and retry once.
+			// 401 is excluded — the transport returns UnauthorizedError for that, not HTTPStatusError.
+			logf(ctx, "CallTool: 4xx error for %s.%s, reinitializing: %v", serverName, toolName, err)
 
 			_ = r.supervisor.Stop(serverName)
 
@@ -137,7 +163,7 @@ func (r *Router) CallTool(ctx context.Context, qualifiedName string, arguments j
 				return nil, ErrInternalError(fmt.Sprintf("tool call failed after reinit: %v", err))
 			}
 
-			log.Printf("CallTool: retry succeeded for %s.%s after reinit", serverName, toolName)
+			logf(ctx, "CallTool: retry succeeded for %s.%s after reinit", serverName, toolName)
 		} else {
 			return nil, ErrInternalError(fmt.Sprintf("tool call failed: %v", err))
 		}
@@ -160,6 +186,10 @@ func (r *Router) handleManagerTool(ctx context.Context, toolName string, argumen
 	switch toolName {
 	case "mcpmu.servers_list":
 		return r.handleServersList(ctx)
+	case "mcpmu.servers_info":
+		return r.handleServersInfo(ctx)
+	case "mcpmu.health":
+		return r.handleHealth(ctx)
 	case "mcpmu.servers_start":
 		return r.handleServersStart(ctx, arguments)
 	case "mcpmu.servers_stop":
@@ -178,7 +208,8 @@ func (r *Router) handleManagerTool(ctx context.Context, toolName string, argumen
 // handleServersList returns the list of configured servers with status.
 func (r *Router) handleServersList(ctx context.Context) (*ToolCallResult, *RPCError) {
 	servers := make([]ServerInfo, 0, len(r.cfg.Servers))
-	for name, srv := range r.cfg.Servers {
+	for _, entry := range r.cfg.ServerEntries() {
+		name, srv := entry.Name, entry.Config
 		info := ServerInfo{
 			ID:      name, // Use name as ID for backwards compatibility in output
 			Name:    name,
@@ -204,6 +235,76 @@ func (r *Router) handleServersList(ctx context.Context) (*ToolCallResult, *RPCEr
 	return textResult(mustJSON(servers)), nil
 }
 
+// handleServersInfo reports what each running upstream said about itself at
+// initialize, sorted by server name.
+func (r *Router) handleServersInfo(ctx context.Context) (*ToolCallResult, *RPCError) {
+	infos := make([]UpstreamInfo, 0, len(r.cfg.Servers))
+	for _, entry := range r.cfg.ServerEntries() {
+		name := entry.Name
+		handle := r.supervisor.Get(name)
+		if handle == nil || !handle.IsRunning() || handle.Client() == nil {
+			continue
+		}
+		serverName, serverVersion := handle.Client().ServerInfo()
+		infos = append(infos, UpstreamInfo{
+			Name:            name,
+			ServerName:      serverName,
+			ServerVersion:   serverVersion,
+			ProtocolVersion: handle.Client().ProtocolVersion(),
+			ToolCount:       len(handle.Tools()),
+		})
+	}
+
+	return textResult(mustJSON(infos)), nil
+}
+
+// handleHealth reports each configured server's state, last error, uptime,
+// tool count and auth status, with overall running/total counts.
+func (r *Router) handleHealth(ctx context.Context) (*ToolCallResult, *RPCError) {
+	report := HealthReport{Servers: make([]ServerHealth, 0, len(r.cfg.Servers))}
+	failures := r.aggregator.DiscoveryFailures()
+	for _, entry := range r.cfg.ServerEntries() {
+		name := entry.Name
+		health := ServerHealth{Name: name, Enabled: entry.Config.IsEnabled()}
+		if msg, ok := failures[name]; ok {
+			health.ToolsError = msg
+			report.Degraded = append(report.Degraded, name)
+		}
+
+		state := events.StateIdle
+		tracked, ok := r.supervisor.Status(name)
+		if ok {
+			state = tracked.State
+			health.LastError = tracked.LastError
+		}
+
+		handle := r.supervisor.Get(name)
+		running := handle != nil && handle.IsRunning()
+		switch {
+		case running && !state.IsActive():
+			state = events.StateRunning
+		case !running && state.IsActive():
+			state = events.StateStopped
+		}
+		if running {
+			health.Uptime = handle.Uptime().Round(time.Second).String()
+			health.ToolCount = len(handle.Tools())
+		}
+		if handle != nil && handle.Kind() == process.HandleKindHTTP {
+			health.Auth = string(handle.AuthStatus())
+		}
+
+		health.State = state.String()
+		if state == events.StateRunning {
+			report.Running++
+		}
+		report.Servers = append(report.Servers, health)
+	}
+	report.Total = len(report.Servers)
+
+	return textResult(mustJSON(report)), nil
+}
+
 // handleServersStart starts a server by name.
 func (r *Router) handleServersStart(ctx context.Context, arguments json.RawMessage) (*ToolCallResult, *RPCError) {
 	var args struct {
@@ -360,7 +461,8 @@ func (r *Router) handleServerLogs(ctx context.Context, arguments json.RawMessage
 // handleNamespacesList returns the list of namespaces with active namespace info.
 func (r *Router) handleNamespacesList(ctx context.Context) (*ToolCallResult, *RPCError) {
 	namespaces := make([]NamespaceInfo, 0, len(r.cfg.Namespaces))
-	for name, ns := range r.cfg.Namespaces {
+	for _, entry := range r.cfg.NamespaceEntries() {
+		name, ns := entry.Name, entry.Config
 		namespaces = append(namespaces, NamespaceInfo{
 			ID:          name, // Use name as ID for backwards compatibility
 			Name:        name,
@@ -393,6 +495,38 @@ type ServerInfo struct {
 	ToolCount int    `json:"toolCount,omitempty"`
 }
 
+// HealthReport is the result of mcpmu.health.
+type HealthReport struct {
+	Running int            `json:"running"`
+	Total   int            `json:"total"`
+	Servers []ServerHealth `json:"servers"`
+	// Degraded lists servers whose tools are missing from tools/list
+	// because their discovery failed.
+	Degraded []string `json:"degraded,omitempty"`
+}
+
+// ServerHealth is one server's entry in a HealthReport.
+type ServerHealth struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	State      string `json:"state"`
+	LastError  string `json:"lastError,omitempty"`
+	Uptime     string `json:"uptime,omitempty"`
+	ToolCount  int    `json:"toolCount"`
+	Auth       string `json:"auth,omitempty"`
+	ToolsError string `json:"toolsError,omitempty"` // why the server's tools are missing from tools/list
+}
+
+// UpstreamInfo describes a running upstream server as it reported itself
+// at initialize.
+type UpstreamInfo struct {
+	Name            string `json:"name"`
+	ServerName      string `json:"serverName"`
+	ServerVersion   string `json:"serverVersion"`
+	ProtocolVersion string `json:"protocolVersion"`
+	ToolCount       int    `json:"toolCount"`
+}
+
 // NamespaceInfo represents namespace information.
 type NamespaceInfo struct {
 	ID          string   `json:"id"`
@@ -413,6 +547,7 @@ type NamespacesListResult struct {
 type ToolCallResult struct {
 	Content []json.RawMessage `json:"content"`
 	IsError bool              `json:"isError,omitempty"`
+	Meta    map[string]any    `json:"_meta,omitempty"`
 }
 
 // textResult creates a text content result.
@@ -428,11 +563,8 @@ func textResult(text string) *ToolCallResult {
 // 401 Unauthorized is excluded as it returns a distinct UnauthorizedError type
 // and has its own OAuth handling flow.
 func isRetriableHTTPError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "request failed: 4")
+	var statusErr *mcp.HTTPStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
 }
 
 // mustJSON marshals a value to JSON, panicking on error.
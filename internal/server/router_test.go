@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/oauth"
+	"github.com/Bigsy/mcpmu/internal/testutil"
+)
+
+// oauthMCPServer is an HTTP MCP server behind OAuth. Once its tools have been
+// listed it revokes the token the session started with, answering 401
+// invalid_token until a refreshed token is presented.
+type oauthMCPServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	revoked   bool
+	rejected  int
+	refreshes int
+}
+
+func newOAuthMCPServer(t *testing.T) *oauthMCPServer {
+	t.Helper()
+	m := &oauthMCPServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server/mcp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.refreshes++
+		m.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "fresh-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+	mux.HandleFunc("/mcp", m.handleMCP)
+
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *oauthMCPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	m.mu.Lock()
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token != "fresh-token" && (m.revoked || token != "stale-token") {
+		m.rejected++
+		m.mu.Unlock()
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="token revoked"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	m.mu.Unlock()
+
+	var req struct {
+		ID     *int   `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if req.ID == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var result string
+	switch req.Method {
+	case "initialize":
+		result = `{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"oauth-mock","version":"1.0"}}`
+	case "tools/list":
+		result = `{"tools":[{"name":"whoami","description":"Who am I","inputSchema":{"type":"object"}}]}`
+		m.mu.Lock()
+		m.revoked = true
+		m.mu.Unlock()
+	case "tools/call":
+		result = `{"content":[{"type":"text","text":"hello"}]}`
+	default:
+		result = `{}`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, result)
+}
+
+func TestRouter_CallTool_RefreshesRejectedOAuthToken(t *testing.T) {
+	home := testutil.SetupTestHome(t)
+	upstream := newOAuthMCPServer(t)
+	serverURL := upstream.URL + "/mcp"
+
+	// Seed an unexpired token that the server is about to revoke
+	store, err := oauth.NewFileStoreInDir(filepath.Join(home, ".config", "mcpmu"))
+	if err != nil {
+		t.Fatalf("NewFileStoreInDir: %v", err)
+	}
+	if err := store.Put(&oauth.Credential{
+		ServerName:   "remote",
+		ServerURL:    serverURL,
+		ClientID:     "client-123",
+		AccessToken:  "stale-token",
+		RefreshToken: "refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).UnixMilli(),
+	}); err != nil {
+		t.Fatalf("store credential: %v", err)
+	}
+
+	enabled := true
+	cfg := &config.Config{
+		SchemaVersion:           1,
+		MCPOAuthCredentialStore: "file",
+		Servers: map[string]config.ServerConfig{
+			"remote": {Kind: config.ServerKindStreamableHTTP, Enabled: &enabled, URL: serverURL},
+		},
+		Namespaces: map[string]config.NamespaceConfig{},
+	}
+
+	var stdout strings.Builder
+	stdin := strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"remote.whoami","arguments":{}}}` + "\n",
+	)
+	srv, err := New(Options{
+		Config:          cfg,
+		PIDTrackerDir:   t.TempDir(),
+		Stdin:           stdin,
+		Stdout:          &stdout,
+		ServerName:      "mcpmu-test",
+		ServerVersion:   "1.0.0",
+		ProtocolVersion: "2024-11-05",
+		LogLevel:        "error",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_ = srv.Run(ctx)
+
+	responses := parseResponsesByID(t, stdout.String())
+	var callResp struct {
+		Result *ToolCallResult `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.Unmarshal(responses[2], &callResp); err != nil {
+		t.Fatalf("Unmarshal tools/call response: %v\n%s", err, stdout.String())
+	}
+	if callResp.Error != nil {
+		t.Fatalf("tools/call failed: %v", callResp.Error)
+	}
+	if callResp.Result == nil || len(callResp.Result.Content) != 1 || !strings.Contains(string(callResp.Result.Content[0]), "hello") {
+		t.Errorf("unexpected tools/call result: %s", responses[2])
+	}
+
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	if upstream.rejected != 1 || upstream.refreshes != 1 {
+		t.Errorf("rejected=%d refreshes=%d, want one rejection followed by one refresh", upstream.rejected, upstream.refreshes)
+	}
+
+	stored, err := store.Get(serverURL)
+	if err != nil || stored == nil || stored.AccessToken != "fresh-token" {
+		t.Errorf("expected the refreshed token to be stored, got %+v (err %v)", stored, err)
+	}
+}
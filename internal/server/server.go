@@ -5,11 +5,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -27,32 +30,48 @@ var DebugLogging bool
 
 // Options configures the MCP server.
 type Options struct {
-	Config             *config.Config
-	ConfigPath         string        // Expanded path for hot-reload watching (empty = no watching)
-	PIDTrackerDir      string        // Directory for PID tracking file (empty = derive from ConfigPath or default)
-	Namespace          string        // Namespace to expose (empty = auto-select)
-	EagerStart         bool          // Pre-start all servers
-	ExposeManagerTools bool          // Include mcpmu.* tools in tools/list
-	ExposeResources    bool          // Passthrough resources/* from upstream servers
-	ExposePrompts      bool          // Passthrough prompts/* from upstream servers
-	DebounceDelay      time.Duration // Delay before applying config changes (default: 150ms)
-	LogLevel           string
-	Stdin              io.Reader
-	Stdout             io.Writer
-	Stderr             io.Writer
-	ServerName         string
-	ServerVersion      string
-	ProtocolVersion    string
+	Config               *config.Config
+	ConfigPath           string            // Expanded path for hot-reload watching (empty = no watching)
+	PIDTrackerDir        string            // Directory for PID tracking file (empty = derive from ConfigPath or default)
+	PIDFilePrefix        string            // Scopes the PID tracking file to a mode, e.g. "try" (empty = pids.json)
+	Namespace            string            // Namespace to expose (empty = auto-select)
+	AllNamespaces        bool              // Expose every enabled server without namespace permissions (excludes Namespace)
+	EagerStart           bool              // Pre-start all servers
+	ExposeManagerTools   bool              // Include mcpmu.* tools in tools/list
+	ExposeResources      bool              // Passthrough resources/* from upstream servers
+	ExposePrompts        bool              // Passthrough prompts/* from upstream servers
+	DebounceDelay        time.Duration     // Delay before applying config changes (default: 150ms)
+	ReloadDrainTimeout   time.Duration     // Max wait for in-flight calls before stopping servers on reload (default: 10s)
+	MaxResultBytes       int               // Cap on tools/call result content size (0 = unlimited; per-server maxResultBytes overrides)
+	ReadOnly             bool              // Deny tools whose names contain a write verb (config readOnlyDenyVerbs)
+	ToolsRefreshInterval time.Duration     // List an upstream's tools again on tools/list once its list is this old (0 = only after a restart or tools/list_changed)
+	ShowDeniedTools      bool              // List denied tools marked "[denied]" instead of hiding them (namespace showDeniedTools also enables it)
+	EventsOutput         io.Writer         // Receives lifecycle events as NDJSON events.Record lines (nil = disabled)
+	IdleTimeout          time.Duration     // Stop stdio servers idle this long; restarted lazily (0 = never; per-server idleTimeoutSec overrides)
+	DuplicateIDs         DuplicateIDPolicy // Handling of requests reusing an in-flight id (default: queue)
+	DiscoveryConcurrency int               // Max upstreams queried at once by tools/list, resources/list and prompts/list (0 = MaxConcurrentDiscovery)
+	ValidateArgs         bool              // Reject tools/call arguments that don't match the tool's input schema before forwarding
+	MaxTools             int               // Cap on the permission-filtered tools/list size (0 = unlimited)
+	MaxToolsMode         MaxToolsPolicy    // What tools/list does over MaxTools: error (default) or truncate
+	TraceOutput          io.Writer         // Receives every JSON-RPC frame exchanged with upstream servers, pretty-printed with secrets masked (nil = disabled)
+	LogLevel             string
+	Stdin                io.Reader
+	Stdout               io.Writer
+	Stderr               io.Writer
+	ServerName           string
+	ServerVersion        string
+	ProtocolVersion      string
 }
 
 // SelectionMethod indicates how the active namespace was selected.
 type SelectionMethod string
 
 const (
-	SelectionFlag    SelectionMethod = "flag"    // --namespace flag
-	SelectionDefault SelectionMethod = "default" // config.defaultNamespaceId
-	SelectionOnly    SelectionMethod = "only"    // only one namespace exists
-	SelectionAll     SelectionMethod = "all"     // no namespaces, all servers exposed
+	SelectionFlag          SelectionMethod = "flag"           // --namespace flag
+	SelectionDefault       SelectionMethod = "default"        // config.defaultNamespaceId
+	SelectionOnly          SelectionMethod = "only"           // only one namespace exists
+	SelectionAll           SelectionMethod = "all"            // no namespaces, all servers exposed
+	SelectionAllNamespaces SelectionMethod = "all-namespaces" // --all-namespaces flag
 )
 
 // Server is an MCP server that aggregates tools from managed upstream servers.
@@ -70,8 +89,10 @@ type Server struct {
 	selectionMethod     SelectionMethod // How the namespace was selected
 
 	// Protocol state
-	initialized bool
-	mu          sync.RWMutex
+	initialized    bool
+	clientCaps     map[string]any // Capabilities the downstream client declared at initialize
+	clientLogLevel string         // Minimum level of upstream log messages relayed, from logging/setLevel ("" = all)
+	mu             sync.RWMutex
 
 	// IO
 	reader  *bufio.Reader
@@ -83,12 +104,32 @@ type Server struct {
 	// caller reading the buffer after Run exits).
 	handlersWG sync.WaitGroup
 
+	// Downstream request ids currently in flight
+	requests *requestTable
+
+	// Requests relayed from upstreams to the client awaiting its response
+	clientRequests *clientRequestTable
+
+	// Progress tokens of tools/call requests in flight
+	progress *progressTable
+
 	// Background discovery
 	bgDiscovering        atomic.Bool
 	listToolsGracePeriod time.Duration // 0 means use ListToolsGracePeriod constant
 
+	// Logs the startup summary after the first tools/list
+	summaryOnce sync.Once
+
+	// Qualified tool names this session narrowed itself to with
+	// mcpmu.tools_filter (nil = no filter). Guarded by mu.
+	toolFilter map[string]bool
+
 	// Hot-reload
 	reloadCh chan *config.Config // Serializes reload with request handling
+	inflight *inflightTracker    // In-flight upstream calls, drained before a reload stops a server
+
+	// Per-namespace tools/call rate limiting (maxCallsPerMinute)
+	limiter *callLimiter
 
 	// Resource routing: maps original URI → server name (populated by resources/list)
 	resourceMap sync.Map
@@ -98,24 +139,47 @@ type Server struct {
 	// config reload. Guarded by subMu.
 	subMu sync.Mutex
 	subs  map[string]string
+
+	// Serializes frames written to opts.TraceOutput
+	traceMu sync.Mutex
 }
 
 // New creates a new MCP server.
 func New(opts Options) (*Server, error) {
 	// Create event bus
 	bus := events.NewBus()
+	if opts.EventsOutput != nil {
+		enc := json.NewEncoder(opts.EventsOutput)
+		bus.Subscribe(func(e events.Event) {
+			if err := enc.Encode(events.NewRecord(e)); err != nil {
+				log.Printf("Warning: failed to write event: %v", err)
+			}
+		})
+	}
 
 	// Derive PID tracker directory from config path to isolate instances
 	pidTrackerDir := opts.PIDTrackerDir
 	if pidTrackerDir == "" && opts.ConfigPath != "" {
-		pidTrackerDir = filepath.Dir(opts.ConfigPath)
+		if dir, err := config.StateDir(opts.ConfigPath); err == nil {
+			pidTrackerDir = dir
+		}
+	}
+	var configDir string
+	if opts.ConfigPath != "" {
+		configDir = filepath.Dir(opts.ConfigPath)
 	}
 
 	// Create process supervisor with config-specified credential store
 	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
 		CredentialStoreMode:     opts.Config.MCPOAuthCredentialStore,
+		CredentialEncryption:    opts.Config.MCPOAuthCredentialEncryption,
 		PIDTrackerDir:           pidTrackerDir,
+		PIDFilePrefix:           opts.PIDFilePrefix,
+		ConfigDir:               configDir,
 		GlobalOAuthCallbackPort: opts.Config.MCPOAuthCallbackPort,
+		MaxLogLines:             opts.Config.MaxLogLines,
+		InitRetries:             opts.Config.InitRetries,
+		InitRetryBackoff:        time.Duration(opts.Config.InitRetryBackoffMs) * time.Millisecond,
 	})
 
 	if opts.ConfigPath != "" {
@@ -128,28 +192,58 @@ func New(opts Options) (*Server, error) {
 	}
 
 	s := &Server{
-		opts:       opts,
-		cfg:        opts.Config,
-		bus:        bus,
-		supervisor: supervisor,
-		reader:     bufio.NewReader(opts.Stdin),
-		writer:     opts.Stdout,
-		reloadCh:   make(chan *config.Config, 1), // Buffered to avoid blocking watcher
-		subs:       make(map[string]string),
+		opts:           opts,
+		cfg:            opts.Config,
+		bus:            bus,
+		supervisor:     supervisor,
+		reader:         bufio.NewReader(opts.Stdin),
+		writer:         opts.Stdout,
+		reloadCh:       make(chan *config.Config, 1), // Buffered to avoid blocking watcher
+		inflight:       newInflightTracker(),
+		requests:       newRequestTable(),
+		clientRequests: newClientRequestTable(),
+		progress:       newProgressTable(),
+		limiter:        newCallLimiter(),
+		subs:           make(map[string]string),
 	}
 
 	// Wire the server as the supervisor's notification sink before any
 	// upstream client is constructed so every handler is installed the
 	// moment Initialize completes.
 	supervisor.SetNotificationSink(s)
+	s.traceUpstreams()
 
 	// Create aggregator and router (will be initialized after namespace selection)
-	s.aggregator = NewAggregator(s.cfg, supervisor, opts.ExposeManagerTools)
+	s.aggregator = NewAggregator(s.cfg, supervisor, opts.ExposeManagerTools, opts.DiscoveryConcurrency, opts.ToolsRefreshInterval)
 	s.router = NewRouter(s.cfg, supervisor, s.aggregator)
 
 	return s, nil
 }
 
+// traceUpstreams enables tracing to opts.TraceOutput for every configured
+// server not already traced. A no-op when TraceOutput is nil.
+func (s *Server) traceUpstreams() {
+	if s.opts.TraceOutput == nil {
+		return
+	}
+	for name := range s.cfg.Servers {
+		if s.supervisor.Trace(name) != nil {
+			continue
+		}
+		trace := mcp.NewTrace(0)
+		trace.SetTap(func(e mcp.TraceEntry) { s.writeTrace(name, e) })
+		s.supervisor.SetTrace(name, trace)
+	}
+}
+
+// writeTrace writes one traced frame to opts.TraceOutput, headed by its
+// direction and server.
+func (s *Server) writeTrace(server string, e mcp.TraceEntry) {
+	s.traceMu.Lock()
+	defer s.traceMu.Unlock()
+	_, _ = io.WriteString(s.opts.TraceOutput, mcp.FormatTraceEntry(server, e))
+}
+
 // readResult holds a line read from stdin and any error.
 type readResult struct {
 	line []byte
@@ -163,12 +257,19 @@ func (s *Server) Run(ctx context.Context) error {
 	// Callers (and tests) typically read the stdout buffer after Run exits;
 	// if handlers were still writing, that would be a data race.
 	defer s.handlersWG.Wait()
+	// Cancel background work (eager starts, reload drains) and in-flight
+	// upstream calls first, so the wait above is short and nothing restarts
+	// a server after shutdown stops it.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	// Start config file watcher if ConfigPath is set
 	if s.opts.ConfigPath != "" {
 		go s.watchConfig(ctx, s.opts.ConfigPath)
 	}
 
+	go s.watchIdle(ctx)
+
 	// Start a goroutine to read lines from stdin
 	lines := make(chan readResult)
 	go func() {
@@ -216,7 +317,10 @@ func (s *Server) Run(ctx context.Context) error {
 			// Handle the read error
 			if r.err != nil {
 				if r.err == io.EOF {
-					log.Println("Client closed connection (EOF)")
+					// Shut down as for a signal, but let requests already
+					// in flight be answered first
+					log.Println("Client closed connection (EOF), shutting down")
+					s.drainHandlers(cancel)
 					return nil
 				}
 				return fmt.Errorf("read request: %w", r.err)
@@ -251,36 +355,92 @@ func (s *Server) handleMessage(ctx context.Context, data []byte) error {
 		return nil
 	}
 
+	// A response from the client to a request relayed from an upstream
+	if msg.ID != nil && msg.Method == "" && (msg.Result != nil || msg.Error != nil) {
+		if !s.clientRequests.resolve(msg.ID, clientResponse{Result: msg.Result, Error: msg.Error}) {
+			log.Printf("Dropping response to unknown request id %s", msg.ID)
+		}
+		return nil
+	}
+
 	// Check if it's a notification (no ID)
 	if msg.ID == nil {
 		return s.handleNotification(ctx, msg.Method, msg.Params)
 	}
 
+	// A client reusing the id of a request that hasn't been answered yet
+	// would otherwise get two responses with that id in arbitrary order.
+	if s.opts.DuplicateIDs == DuplicateIDsReject && s.requests.busy(msg.ID) {
+		s.sendError(msg.ID, ErrInvalidRequest(fmt.Sprintf("request id %s is already in flight", msg.ID)))
+		return nil
+	}
+	// Every request gets a correlation ID on receipt. Requests that reach an
+	// upstream log it when they arrive, when they are dispatched and when
+	// they are answered, so one call can be followed through the logs.
+	ctx = withCorrelationID(ctx, newCorrelationID())
+	logged := isUpstreamMethod(msg.Method)
+	if logged {
+		logf(ctx, "Received %s (id %s)", msg.Method, idKey(msg.ID))
+	}
+
+	prev, release := s.requests.claim(msg.ID)
+	respond := func() {
+		defer release()
+		reqCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		run := s.requests.start(msg.ID, cancel)
+		defer s.requests.finish(msg.ID, run)
+
+		start := time.Now()
+		result, rpcErr := s.handleRequest(reqCtx, msg.Method, msg.Params)
+		if run.cancelled.Load() {
+			// The client has given up on it and expects no response
+			if logged {
+				logf(ctx, "%s cancelled by the client after %s", msg.Method, time.Since(start).Round(time.Millisecond))
+			}
+			return
+		}
+		if rpcErr != nil {
+			if logged {
+				logf(ctx, "%s failed after %s: %s", msg.Method, time.Since(start).Round(time.Millisecond), rpcErr.Message)
+			}
+			s.sendError(msg.ID, rpcErr)
+		} else {
+			if logged {
+				logf(ctx, "%s answered in %s", msg.Method, time.Since(start).Round(time.Millisecond))
+			}
+			s.sendResult(msg.ID, result)
+		}
+	}
+
+	// Queue a duplicate behind the earlier request with its id, off the
+	// main loop so other requests keep flowing meanwhile.
+	if prev != nil {
+		s.handlersWG.Go(func() {
+			select {
+			case <-prev:
+				respond()
+			case <-ctx.Done():
+				release()
+			}
+		})
+		return nil
+	}
+
 	// Requests that dispatch to an upstream MCP server can block for a long
 	// time (up to the per-server tool timeout). Run them in a goroutine so
 	// the main loop stays free to handle other requests — otherwise one
 	// wedged upstream would freeze every other tool call, list, or ping.
-	// JSON-RPC correlates responses by id and send() serializes stdout
-	// writes via writeMu, so concurrent handlers are safe.
+	// The request table keeps responses correlated with their ids and
+	// send() serializes stdout writes via writeMu, so concurrent handlers
+	// are safe.
 	if isUpstreamMethod(msg.Method) {
-		s.handlersWG.Go(func() {
-			result, rpcErr := s.handleRequest(ctx, msg.Method, msg.Params)
-			if rpcErr != nil {
-				s.sendError(msg.ID, rpcErr)
-			} else {
-				s.sendResult(msg.ID, result)
-			}
-		})
+		s.handlersWG.Go(respond)
 		return nil
 	}
 
 	// It's a request - handle and respond
-	result, rpcErr := s.handleRequest(ctx, msg.Method, msg.Params)
-	if rpcErr != nil {
-		s.sendError(msg.ID, rpcErr)
-	} else {
-		s.sendResult(msg.ID, result)
-	}
+	respond()
 	return nil
 }
 
@@ -291,6 +451,8 @@ func (s *Server) handleRequest(ctx context.Context, method string, params json.R
 		return s.handleInitialize(ctx, params)
 	case "ping":
 		return s.handlePing(ctx)
+	case "logging/setLevel":
+		return s.handleLoggingSetLevel(ctx, params)
 	case "tools/list":
 		return s.handleToolsList(ctx)
 	case "tools/call":
@@ -346,9 +508,24 @@ func (s *Server) handleNotification(ctx context.Context, method string, params j
 		if s.opts.EagerStart {
 			go s.startEagerServers(ctx)
 		}
+	case "notifications/roots/list_changed":
+		s.notifyUpstreams(ctx, method, params)
 	case "notifications/cancelled":
-		// Handle cancellation - for now just log it
-		log.Printf("Received cancellation notification: %s", string(params))
+		// Aborting the handler cancels its upstream call, which tells the
+		// upstream in turn
+		var p struct {
+			RequestID json.RawMessage `json:"requestId"`
+			Reason    string          `json:"reason,omitempty"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil || p.RequestID == nil {
+			log.Printf("Ignoring malformed cancellation: %s", string(params))
+			return nil
+		}
+		if s.requests.cancel(p.RequestID) {
+			log.Printf("Client cancelled request %s: %s", p.RequestID, p.Reason)
+		} else if DebugLogging {
+			log.Printf("Cancellation for request %s that is not in flight", p.RequestID)
+		}
 	default:
 		log.Printf("Unknown notification: %s", method)
 	}
@@ -384,9 +561,15 @@ func (s *Server) handleInitialize(ctx context.Context, params json.RawMessage) (
 
 	s.initialized = true
 
+	// Upstreams start after initialize, so each declares the client's
+	// capabilities that mcpmu can relay.
+	s.clientCaps = req.Capabilities
+	s.supervisor.SetClientCapabilities(forwardedClientCapabilities(req.Capabilities))
+
 	// Build capabilities
 	caps := capabilities{
-		Tools: &toolsCapability{ListChanged: true},
+		Tools:   &toolsCapability{ListChanged: true},
+		Logging: &struct{}{}, // Upstream log messages are relayed
 	}
 	if s.opts.ExposeResources {
 		// Advertise subscribe optimistically — capabilities are returned at
@@ -410,11 +593,37 @@ func (s *Server) handleInitialize(ctx context.Context, params json.RawMessage) (
 	}, nil
 }
 
+// relayedClientCapabilities are the client capabilities forwarded to
+// upstreams: the ones whose server-to-client requests mcpmu can route back
+// to the downstream client.
+var relayedClientCapabilities = []string{"roots", "sampling"}
+
+// forwardedClientCapabilities returns the subset of a downstream client's
+// capabilities that upstreams are told about.
+func forwardedClientCapabilities(caps map[string]any) map[string]any {
+	forwarded := make(map[string]any)
+	for _, name := range relayedClientCapabilities {
+		if v, ok := caps[name]; ok && v != nil {
+			forwarded[name] = v
+		}
+	}
+	return forwarded
+}
+
 // handlePing handles the ping request.
 func (s *Server) handlePing(ctx context.Context) (any, *RPCError) {
 	return struct{}{}, nil
 }
 
+// discoveryConcurrency is the max number of upstreams a list request
+// queries at once.
+func (s *Server) discoveryConcurrency() int {
+	if s.opts.DiscoveryConcurrency > 0 {
+		return s.opts.DiscoveryConcurrency
+	}
+	return MaxConcurrentDiscovery
+}
+
 // handleToolsList handles the tools/list request.
 func (s *Server) handleToolsList(ctx context.Context) (any, *RPCError) {
 	s.mu.RLock()
@@ -425,6 +634,9 @@ func (s *Server) handleToolsList(ctx context.Context) (any, *RPCError) {
 	activeNamespaceName := s.activeNamespaceName
 	activeServerNames := s.activeServerNames
 	aggregator := s.aggregator
+	strippedPrefix := s.strippedToolPrefix()
+	showDenied := s.showDeniedTools()
+	toolFilter := s.toolFilter
 	s.mu.RUnlock()
 
 	// Discover tools with a grace period. ListTools starts servers
@@ -448,26 +660,80 @@ func (s *Server) handleToolsList(ctx context.Context) (any, *RPCError) {
 
 	// Filter tools based on permissions (always runs — IsToolAllowed handles
 	// global deny even without a namespace, and returns true for everything
-	// else when namespace is empty)
+	// else when namespace is empty). With showDenied, denied tools stay
+	// listed but marked; tools/call rejects them either way.
 	filtered := make([]AggregatedTool, 0, len(tools))
 	for _, tool := range tools {
-		serverName, toolName, isManager := ParseToolName(tool.Name)
+		serverName, toolName, isManager := ResolveToolName(s.cfg, tool.Name)
 		// Manager tools are always shown
 		if isManager {
 			filtered = append(filtered, tool)
 			continue
 		}
-		// Check permission for regular tools
-		allowed, _ := IsToolAllowed(s.cfg, activeNamespaceName, serverName, toolName)
-		if allowed {
+		if toolFilter != nil && !toolFilter[tool.Name] {
+			continue
+		}
+		switch {
+		case s.toolAllowed(activeNamespaceName, serverName, toolName):
 			filtered = append(filtered, tool)
+		case showDenied:
+			filtered = append(filtered, markDenied(tool))
 		}
 	}
 	tools = filtered
 
+	tools, rpcErr := s.limitTools(tools, activeNamespaceName)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	s.summaryOnce.Do(func() {
+		s.logStartupSummary(len(tools), len(stillPending))
+	})
+
+	// Single-server namespaces may expose unqualified tool names
+	if strippedPrefix != "" {
+		for i, tool := range tools {
+			if tool.serverName != "" {
+				tools[i].Name = strings.TrimPrefix(tool.Name, strippedPrefix+".")
+			}
+		}
+	}
+
 	return toolsListResult{Tools: tools}, nil
 }
 
+// logStartupSummary logs one line describing what this serve instance
+// exposes, so users can confirm they launched what they meant to. It runs
+// once tool discovery has first completed, since that's when the tool count
+// is known.
+func (s *Server) logStartupSummary(toolCount, pending int) {
+	s.mu.RLock()
+	namespaceName := s.activeNamespaceName
+	selection := s.selectionMethod
+	serverCount := len(s.activeServerNames)
+	s.mu.RUnlock()
+
+	configPath := s.opts.ConfigPath
+	if configPath == "" {
+		configPath = "(none)"
+	}
+	namespace := "none"
+	if namespaceName != "" {
+		namespace = strconv.Quote(namespaceName)
+	}
+	start := "lazy"
+	if s.opts.EagerStart {
+		start = "eager"
+	}
+	tools := fmt.Sprintf("%d tools exposed", toolCount)
+	if pending > 0 {
+		tools += fmt.Sprintf(" (%d server(s) still starting)", pending)
+	}
+
+	log.Printf("Startup summary: config %s, namespace %s (selection: %s), %d server(s), %s start, %s",
+		configPath, namespace, selection, serverCount, start, tools)
+}
+
 // sendNotification sends a JSON-RPC notification (no ID, no response expected).
 func (s *Server) sendNotification(method string) {
 	s.sendNotificationWithParams(method, nil)
@@ -490,7 +756,7 @@ func (s *Server) sendNotificationWithParams(method string, params any) {
 
 // OnUpstreamNotification implements mcp.NotificationSink. It runs on the
 // upstream client's reader goroutine — must not block on stdout writes, so
-// any downstream emission happens in a goroutine.
+// downstream emission happens in a goroutine, progress aside.
 func (s *Server) OnUpstreamNotification(serverName, method string, params json.RawMessage) {
 	switch method {
 	case "notifications/resources/updated":
@@ -521,6 +787,24 @@ func (s *Server) OnUpstreamNotification(serverName, method string, params json.R
 		s.handlersWG.Go(func() {
 			s.sendNotificationWithParams("notifications/resources/updated", map[string]string{"uri": p.URI})
 		})
+	case "notifications/tools/list_changed":
+		// The server's tools are listed again on the next tools/list, which
+		// the client is told to make
+		if handle := s.supervisor.Get(serverName); handle != nil {
+			handle.InvalidateTools()
+		}
+		s.handlersWG.Go(func() {
+			s.sendNotification("notifications/tools/list_changed")
+		})
+	case "notifications/message":
+		s.relayLogMessage(serverName, params)
+	case "notifications/progress":
+		// Written inline, unlike other notifications: the upstream's
+		// response to the call is read by this same goroutine afterwards,
+		// so progress always reaches the client before the result.
+		s.progress.relay(serverName, params, func(p map[string]json.RawMessage) {
+			s.sendNotificationWithParams(method, p)
+		})
 	default:
 		if DebugLogging {
 			log.Printf("OnUpstreamNotification: dropping %s from %s (relay not implemented)", method, serverName)
@@ -597,7 +881,10 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (a
 		return nil, ErrInvalidRequest("not initialized")
 	}
 	activeServerNames := s.activeServerNames
+	activeNamespaceName := s.activeNamespaceName
 	router := s.router
+	aggregator := s.aggregator
+	strippedPrefix := s.strippedToolPrefix()
 	s.mu.RUnlock()
 
 	var req toolsCallRequest
@@ -605,8 +892,20 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (a
 		return nil, ErrInvalidParams(err.Error())
 	}
 
+	// Unqualified names from a prefix-stripped namespace belong to its only
+	// server; manager tools keep their mcpmu. prefix.
+	if strippedPrefix != "" && !strings.HasPrefix(req.Name, config.ManagerToolPrefix+".") {
+		req.Name = strippedPrefix + "." + req.Name
+	}
+
+	if req.Name == toolsFilterTool {
+		return s.handleToolsFilter(ctx, req.Arguments)
+	}
+
 	// Parse tool name to check namespace enforcement
-	serverName, _, isManager := ParseToolName(req.Name)
+	serverName, toolName, isManager := ResolveToolName(s.cfg, req.Name)
+
+	maxResultBytes := s.opts.MaxResultBytes
 
 	// Manager tools are always allowed
 	if !isManager && serverName != "" {
@@ -624,6 +923,51 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (a
 		if !srv.IsEnabled() {
 			return nil, NewRPCError(ErrCodeServerNotRunning, "server is disabled: "+serverName, nil)
 		}
+		s.mu.RLock()
+		hidden := s.filteredOut(req.Name)
+		s.mu.RUnlock()
+		if hidden {
+			return nil, ErrToolDenied(req.Name, "excluded by this session's mcpmu.tools_filter")
+		}
+		if srv.MaxResultBytes > 0 {
+			maxResultBytes = srv.MaxResultBytes
+		}
+
+		// Read-only mode overrides namespace permissions
+		if s.opts.ReadOnly && MatchesDenyVerb(s.cfg.ReadOnlyDenyVerbList(), toolName) {
+			return nil, ErrToolDenied(req.Name, "server is in read-only mode and the tool name contains a write verb")
+		}
+
+		// Denied calls are refused before argument validation, so schema
+		// errors don't reveal anything about them, and before the rate
+		// limiter, so they don't use up the namespace's budget. The router
+		// checks again.
+		if allowed, reason := IsToolAllowed(s.cfg, activeNamespaceName, serverName, toolName); !allowed {
+			return nil, ErrToolDenied(req.Name, reason)
+		}
+
+		if s.opts.ValidateArgs {
+			if rpcErr := aggregator.ValidateArguments(req.Name, req.Arguments); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+
+		if ns, ok := s.cfg.GetNamespace(activeNamespaceName); ok {
+			if ok, retryAfter := s.limiter.allow(activeNamespaceName, ns.MaxCallsPerMinute); !ok {
+				return nil, ErrRateLimited(activeNamespaceName, retryAfter)
+			}
+		}
+
+		if !s.inflight.acquire(serverName) {
+			return nil, ErrServerDraining(serverName)
+		}
+		defer s.inflight.release(serverName)
+
+		if req.Meta != nil && len(req.Meta.ProgressToken) > 0 {
+			token := s.progress.add(serverName, req.Meta.ProgressToken)
+			defer s.progress.remove(token)
+			ctx = mcp.WithProgressToken(ctx, token)
+		}
 	}
 
 	// Route the call through the router
@@ -632,9 +976,57 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (a
 		return nil, rpcErr
 	}
 
+	if !isManager && truncateToolResult(result, maxResultBytes) {
+		logf(ctx, "Truncated tools/call result for %s to %d bytes", req.Name, maxResultBytes)
+	}
+
 	return result, nil
 }
 
+// toolAllowed reports whether tools/list exposes a tool under the given
+// namespace: --read-only and the permission rules must both allow it.
+func (s *Server) toolAllowed(namespaceName, serverName, toolName string) bool {
+	if s.opts.ReadOnly && MatchesDenyVerb(s.cfg.ReadOnlyDenyVerbList(), toolName) {
+		return false
+	}
+	allowed, _ := IsToolAllowed(s.cfg, namespaceName, serverName, toolName)
+	return allowed
+}
+
+// showDeniedTools reports whether tools/list should include denied tools,
+// from the serve option or the active namespace. Caller must hold s.mu.
+func (s *Server) showDeniedTools() bool {
+	if s.opts.ShowDeniedTools {
+		return true
+	}
+	ns, ok := s.cfg.GetNamespace(s.activeNamespaceName)
+	return ok && ns.ShowDeniedTools
+}
+
+// deniedMarker prefixes the description of denied tools listed under
+// showDeniedTools.
+const deniedMarker = "[denied]"
+
+// markDenied returns a copy of tool with its description marked as denied.
+func markDenied(tool AggregatedTool) AggregatedTool {
+	tool.Description = deniedMarker + " " + tool.Description
+	return tool
+}
+
+// strippedToolPrefix returns the tool prefix to strip from tool names when
+// the active namespace has StripPrefixWhenSingle set and contains exactly one
+// server. Returns "" when names stay qualified. Caller must hold s.mu.
+func (s *Server) strippedToolPrefix() string {
+	if s.activeNamespaceName == "" || len(s.activeServerNames) != 1 {
+		return ""
+	}
+	ns, ok := s.cfg.GetNamespace(s.activeNamespaceName)
+	if !ok || !ns.StripPrefixWhenSingle {
+		return ""
+	}
+	return s.cfg.ToolPrefix(s.activeServerNames[0])
+}
+
 // handleResourcesList handles the resources/list request.
 func (s *Server) handleResourcesList(ctx context.Context) (any, *RPCError) {
 	s.mu.RLock()
@@ -657,7 +1049,7 @@ func (s *Server) handleResourcesList(ctx context.Context) (any, *RPCError) {
 
 	var allResources []listedResource
 	var mu sync.Mutex
-	sem := make(chan struct{}, MaxConcurrentDiscovery)
+	sem := make(chan struct{}, s.discoveryConcurrency())
 	var wg sync.WaitGroup
 
 	for _, name := range activeServerNames {
@@ -740,6 +1132,11 @@ func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage
 		return nil, ErrServerNotFound(serverName)
 	}
 
+	if !s.inflight.acquire(serverName) {
+		return nil, ErrServerDraining(serverName)
+	}
+	defer s.inflight.release(serverName)
+
 	sc, rpcErr := s.ensureServerClient(ctx, serverName)
 	if rpcErr != nil {
 		return nil, rpcErr
@@ -748,6 +1145,7 @@ func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage
 	callCtx, cancel := context.WithTimeout(ctx, sc.timeout)
 	defer cancel()
 
+	logf(ctx, "Dispatching resources/read %s to upstream %s", req.URI, serverName)
 	contents, err := sc.client.ReadResource(callCtx, req.URI)
 	if err != nil {
 		return nil, ErrInternalError(fmt.Sprintf("resources/read from %s: %v", serverName, err))
@@ -895,7 +1293,7 @@ func (s *Server) handlePromptsList(ctx context.Context) (any, *RPCError) {
 
 	var allPrompts []qualifiedPrompt
 	var mu sync.Mutex
-	sem := make(chan struct{}, MaxConcurrentDiscovery)
+	sem := make(chan struct{}, s.discoveryConcurrency())
 	var wg sync.WaitGroup
 
 	for _, name := range activeServerNames {
@@ -920,16 +1318,18 @@ func (s *Server) handlePromptsList(ctx context.Context) (any, *RPCError) {
 				return
 			}
 
+			prefix := s.cfg.ToolPrefix(serverName)
+
 			mu.Lock()
 			for _, p := range prompts {
 				desc := p.Description
 				if desc != "" {
-					desc = fmt.Sprintf("[%s] %s", serverName, desc)
+					desc = fmt.Sprintf("[%s] %s", prefix, desc)
 				} else {
-					desc = fmt.Sprintf("[%s]", serverName)
+					desc = fmt.Sprintf("[%s]", prefix)
 				}
 				allPrompts = append(allPrompts, qualifiedPrompt{
-					Name:        serverName + "." + p.Name,
+					Name:        prefix + "." + p.Name,
 					Description: desc,
 					Arguments:   p.Arguments,
 				})
@@ -966,16 +1366,24 @@ func (s *Server) handlePromptsGet(ctx context.Context, params json.RawMessage) (
 		return nil, ErrInvalidParams(err.Error())
 	}
 
-	// Split on first '.' to extract server name and original prompt name
+	// Split on first '.' to extract the prefix and original prompt name
 	serverName, originalName, ok := strings.Cut(req.Name, ".")
 	if !ok || serverName == "" || originalName == "" {
 		return nil, ErrInvalidParams("invalid prompt name: " + req.Name)
 	}
+	if name, ok := s.cfg.ServerForToolPrefix(serverName); ok {
+		serverName = name
+	}
 
 	if !slices.Contains(activeServerNames, serverName) {
 		return nil, ErrServerNotFound(serverName)
 	}
 
+	if !s.inflight.acquire(serverName) {
+		return nil, ErrServerDraining(serverName)
+	}
+	defer s.inflight.release(serverName)
+
 	sc, rpcErr := s.ensureServerClient(ctx, serverName)
 	if rpcErr != nil {
 		return nil, rpcErr
@@ -984,6 +1392,7 @@ func (s *Server) handlePromptsGet(ctx context.Context, params json.RawMessage) (
 	callCtx, cancel := context.WithTimeout(ctx, sc.timeout)
 	defer cancel()
 
+	logf(ctx, "Dispatching prompts/get %s to upstream %s", originalName, serverName)
 	messages, err := sc.client.GetPrompt(callCtx, originalName, req.Arguments)
 	if err != nil {
 		return nil, ErrInternalError(fmt.Sprintf("prompts/get from %s: %v", serverName, err))
@@ -1025,6 +1434,7 @@ func (s *Server) ensureServerClient(ctx context.Context, serverName string) (ser
 	if err := handle.WaitForTools(ctx); err != nil {
 		return serverClient{}, ErrServerFailedToStart(serverName, err.Error())
 	}
+	handle.Touch()
 
 	return serverClient{
 		client:       handle.Client(),
@@ -1038,6 +1448,16 @@ func (s *Server) resolveNamespace() *RPCError {
 	cfg := s.cfg
 	namespaceArg := s.opts.Namespace
 
+	// Rule 0: --all-namespaces deliberately exposes every enabled server
+	// with no namespace permissions (server deny lists still apply)
+	if s.opts.AllNamespaces {
+		s.activeNamespaceName = ""
+		s.activeServerNames = enabledServerNames(cfg)
+		s.selectionMethod = SelectionAllNamespaces
+		log.Printf("Exposing all %d enabled servers across %d namespaces (selection: all-namespaces)", len(s.activeServerNames), len(cfg.Namespaces))
+		return nil
+	}
+
 	// Rule 1: If --namespace provided, use it (lookup by name)
 	if namespaceArg != "" {
 		if ns, exists := cfg.Namespaces[namespaceArg]; exists {
@@ -1076,12 +1496,7 @@ func (s *Server) resolveNamespace() *RPCError {
 	// Rule 4: If 0 namespaces, expose all enabled servers
 	if len(cfg.Namespaces) == 0 {
 		s.activeNamespaceName = ""
-		s.activeServerNames = make([]string, 0, len(cfg.Servers))
-		for name, srv := range cfg.Servers {
-			if srv.IsEnabled() {
-				s.activeServerNames = append(s.activeServerNames, name)
-			}
-		}
+		s.activeServerNames = enabledServerNames(cfg)
 		s.selectionMethod = SelectionAll
 		log.Printf("No namespaces configured, exposing all %d enabled servers (selection: all)", len(s.activeServerNames))
 		return nil
@@ -1089,18 +1504,62 @@ func (s *Server) resolveNamespace() *RPCError {
 
 	// Rule 5: 2+ namespaces, none selected - fail
 	return NewRPCError(ErrCodeInvalidRequest,
-		fmt.Sprintf("Multiple namespaces configured (%d), but none selected. Use --namespace to specify which namespace to expose.", len(cfg.Namespaces)),
+		fmt.Sprintf("Multiple namespaces configured (%d), but none selected. Use --namespace to specify which namespace to expose, or --all-namespaces to expose every enabled server.", len(cfg.Namespaces)),
 		nil)
 }
 
+// enabledServerNames returns the names of all enabled servers in cfg,
+// sorted, logging the ones left out.
+func enabledServerNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Servers))
+	for _, entry := range cfg.ServerEntries() {
+		if !entry.Config.IsEnabled() {
+			log.Printf("%s, skipping", disabledMessage(entry.Name, entry.Config))
+			continue
+		}
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+// disabledMessage says that a server is disabled, with the recorded reason.
+func disabledMessage(name string, srv config.ServerConfig) string {
+	if srv.DisabledReason == "" {
+		return fmt.Sprintf("Server %s is disabled", name)
+	}
+	return fmt.Sprintf("Server %s is disabled (%s)", name, srv.DisabledReason)
+}
+
 // startEagerServers starts all servers in the active namespace.
 func (s *Server) startEagerServers(ctx context.Context) {
-	log.Printf("Starting %d servers eagerly", len(s.activeServerNames))
-	for _, name := range s.activeServerNames {
-		srv, ok := s.cfg.GetServer(name)
+	s.mu.RLock()
+	activeServerNames := s.activeServerNames
+	s.mu.RUnlock()
+
+	log.Printf("Starting %d servers eagerly", len(activeServerNames))
+	s.startServers(ctx, activeServerNames)
+}
+
+// startServers starts the named servers that are enabled and not already
+// running.
+func (s *Server) startServers(ctx context.Context, names []string) {
+	s.mu.RLock()
+	cfg := s.cfg
+	s.mu.RUnlock()
+
+	for _, name := range names {
+		srv, ok := cfg.GetServer(name)
 		if !ok {
 			continue
 		}
+		if !srv.IsEnabled() {
+			log.Printf("%s, not starting", disabledMessage(name, srv))
+			continue
+		}
+		// Servers kept across a reload are already running
+		if handle := s.supervisor.Get(name); handle != nil && handle.IsRunning() {
+			continue
+		}
 		if _, err := s.supervisor.Start(ctx, name, srv); err != nil {
 			log.Printf("Failed to start server %s: %v", name, err)
 		}
@@ -1110,12 +1569,23 @@ func (s *Server) startEagerServers(ctx context.Context) {
 // shutdown cleans up resources.
 func (s *Server) shutdown() {
 	log.Println("Shutting down server")
+	s.clientRequests.close()
 	s.supervisor.StopAll()
 	s.bus.Close()
 }
 
+// DefaultDebounceDelay is how long the config watcher waits after the last
+// write before reloading, so an editor's multi-step save reloads once.
+const DefaultDebounceDelay = 150 * time.Millisecond
+
+// configRewatchInterval is how often watchConfig retries watching the config
+// directory after it has been removed.
+const configRewatchInterval = 250 * time.Millisecond
+
 // watchConfig watches the config file for changes and sends new config to reloadCh.
 // It watches the parent directory (not the file) to handle atomic renames.
+// While the file is missing the last good config stays in place; if the
+// directory itself is removed, the watch is re-established once it's back.
 func (s *Server) watchConfig(ctx context.Context, configPath string) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -1138,7 +1608,7 @@ func (s *Server) watchConfig(ctx context.Context, configPath string) {
 	// Debounce timer
 	debounceDelay := s.opts.DebounceDelay
 	if debounceDelay == 0 {
-		debounceDelay = 150 * time.Millisecond
+		debounceDelay = DefaultDebounceDelay
 	}
 	var debounceTimer *time.Timer
 	var debounceMu sync.Mutex
@@ -1151,8 +1621,13 @@ func (s *Server) watchConfig(ctx context.Context, configPath string) {
 		debounceTimer = time.AfterFunc(debounceDelay, func() {
 			log.Printf("Config file changed, loading new config")
 
-			// Load and parse before sending
-			newCfg, err := config.LoadFrom(configPath)
+			// Load and parse before sending. A missing file is most likely
+			// mid-rewrite by another tool; its recreation triggers a reload.
+			newCfg, err := config.LoadExisting(configPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Config file %s is missing (keeping current config until it is recreated)", configPath)
+				return
+			}
 			if err != nil {
 				log.Printf("Failed to load config after change: %v (keeping current config)", err)
 				return
@@ -1169,6 +1644,15 @@ func (s *Server) watchConfig(ctx context.Context, configPath string) {
 		debounceMu.Unlock()
 	}
 
+	// rewatch ticks while the directory watch is lost
+	var rewatchTicker *time.Ticker
+	var rewatch <-chan time.Time
+	defer func() {
+		if rewatchTicker != nil {
+			rewatchTicker.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -1184,6 +1668,17 @@ func (s *Server) watchConfig(ctx context.Context, configPath string) {
 				return
 			}
 
+			// The directory itself was removed or moved away, taking its
+			// watch with it
+			if event.Name == dir && event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				if rewatchTicker == nil {
+					log.Printf("Config directory %s removed, waiting for it to be recreated", dir)
+					rewatchTicker = time.NewTicker(configRewatchInterval)
+					rewatch = rewatchTicker.C
+				}
+				continue
+			}
+
 			// Filter for our target file
 			if filepath.Base(event.Name) != filename {
 				continue
@@ -1196,6 +1691,16 @@ func (s *Server) watchConfig(ctx context.Context, configPath string) {
 				triggerReload()
 			}
 
+		case <-rewatch:
+			if err := watcher.Add(dir); err != nil {
+				continue
+			}
+			rewatchTicker.Stop()
+			rewatchTicker, rewatch = nil, nil
+			log.Printf("Watching config file again: %s", configPath)
+			// The file may have been written before the watch was back
+			triggerReload()
+
 		case err, ok := <-watcher.Errors:
 			if !ok {
 				return
@@ -1211,24 +1716,18 @@ func (s *Server) applyReload(ctx context.Context, newCfg *config.Config) {
 	log.Printf("Applying config reload: %d servers, %d namespaces",
 		len(newCfg.Servers), len(newCfg.Namespaces))
 
-	// Clear subscription tracking before StopAll: closing the upstream
-	// transport ends the upstream-side subscription cleanly, so we only
-	// need to drop our local bookkeeping. No per-URI unsubscribe RPC is
-	// attempted — it would race with shutdown.
-	s.subMu.Lock()
-	clear(s.subs)
-	s.subMu.Unlock()
-
-	// Stop all running servers
-	s.supervisor.StopAll()
-
 	// Swap config
 	s.mu.Lock()
+	oldCfg := s.cfg
 	oldNamespaceName := s.activeNamespaceName
 	oldSelectionMethod := s.selectionMethod
 	s.cfg = newCfg
 	s.mu.Unlock()
 
+	if removed := oldCfg.RemovedServers(newCfg); len(removed) > 0 {
+		log.Printf("WARN: %s", s.removedServersSummary(removed))
+	}
+
 	// Re-resolve namespace
 	// If namespace was selected by flag and still exists, keep it
 	// If namespace was auto-selected and still valid, keep it
@@ -1277,24 +1776,53 @@ func (s *Server) applyReload(ctx context.Context, newCfg *config.Config) {
 		s.mu.Unlock()
 	}
 
+	s.traceUpstreams()
+
 	// Rebuild aggregator and router with new config. Swap under the write
 	// lock so concurrently-running handlers see either the whole old pair or
 	// the whole new pair, never a torn read.
-	newAgg := NewAggregator(s.cfg, s.supervisor, s.opts.ExposeManagerTools)
+	newAgg := NewAggregator(s.cfg, s.supervisor, s.opts.ExposeManagerTools, s.opts.DiscoveryConcurrency, s.opts.ToolsRefreshInterval)
 	newRouter := NewRouter(s.cfg, s.supervisor, newAgg)
 
 	s.mu.Lock()
 	s.aggregator = newAgg
 	s.router = newRouter
 	activeNsName := s.activeNamespaceName
+	activeServerNames := s.activeServerNames
 	selMethod := s.selectionMethod
 	s.mu.Unlock()
 
 	newRouter.SetActiveNamespace(activeNsName, selMethod)
 
-	// Restart servers if eager start is configured
+	// Servers that are unchanged and still active keep their connections.
+	// The rest stop accepting new calls now and are stopped once their
+	// in-flight calls drain.
+	stale := s.staleServers(oldCfg, newCfg, activeServerNames)
+	if len(stale) > 0 {
+		s.inflight.startDrain(stale)
+
+		// Drop subscription bookkeeping for stopped servers: closing the
+		// upstream transport ends the upstream-side subscription cleanly.
+		// No per-URI unsubscribe RPC is attempted — it would race with
+		// shutdown.
+		s.subMu.Lock()
+		for uri, owner := range s.subs {
+			if slices.Contains(stale, owner) {
+				delete(s.subs, uri)
+			}
+		}
+		s.subMu.Unlock()
+
+		go s.drainAndStop(ctx, stale)
+	}
 	if s.opts.EagerStart {
-		go s.startEagerServers(ctx)
+		// Start servers newly added to the active set straight away; changed
+		// servers come back up once drained
+		fresh := slices.DeleteFunc(slices.Clone(activeServerNames), func(name string) bool {
+			return slices.Contains(stale, name)
+		})
+		log.Printf("Starting %d servers eagerly", len(fresh))
+		go s.startServers(ctx, fresh)
 	}
 
 	// Notify client that lists may have changed
@@ -1309,6 +1837,27 @@ func (s *Server) applyReload(ctx context.Context, newCfg *config.Config) {
 	log.Printf("Config reload complete")
 }
 
+// removedServersSummary describes servers a reload removes from the config,
+// with the tools each running one was exposing, so an accidental deletion
+// stands out in the log.
+func (s *Server) removedServersSummary(removed []string) string {
+	parts := make([]string, 0, len(removed))
+	for _, name := range removed {
+		handle := s.supervisor.Get(name)
+		if handle == nil {
+			parts = append(parts, name+" (not running)")
+			continue
+		}
+		tools := handle.Tools()
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d tools: %s)", name, len(tools), strings.Join(names, ", ")))
+	}
+	return fmt.Sprintf("Config reload removes %d server(s): %s", len(removed), strings.Join(parts, "; "))
+}
+
 // sendResult sends a successful JSON-RPC response.
 func (s *Server) sendResult(id json.RawMessage, result any) {
 	resultJSON, _ := json.Marshal(result)
@@ -1361,6 +1910,8 @@ type rpcMessage struct {
 	ID      json.RawMessage `json:"id,omitempty"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"` // Set on the client's responses to relayed requests
+	Error   *RPCError       `json:"error,omitempty"`
 }
 
 type rpcResponse struct {
@@ -1371,9 +1922,9 @@ type rpcResponse struct {
 }
 
 type initializeRequest struct {
-	ProtocolVersion string     `json:"protocolVersion"`
-	Capabilities    any        `json:"capabilities"`
-	ClientInfo      clientInfo `json:"clientInfo"`
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      clientInfo     `json:"clientInfo"`
 }
 
 type clientInfo struct {
@@ -1396,6 +1947,7 @@ type capabilities struct {
 	Tools     *toolsCapability     `json:"tools,omitempty"`
 	Resources *resourcesCapability `json:"resources,omitempty"`
 	Prompts   *promptsCapability   `json:"prompts,omitempty"`
+	Logging   *struct{}            `json:"logging,omitempty"`
 }
 
 type toolsCapability struct {
@@ -1418,4 +1970,7 @@ type toolsListResult struct {
 type toolsCallRequest struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      *struct {
+		ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+	} `json:"_meta,omitempty"`
 }
@@ -389,6 +389,7 @@ func TestServer_ResourcesSubscribe_MixedCapability(t *testing.T) {
 				"resources":                []any{map[string]any{"uri": "file:///good.txt", "name": "good"}},
 				"resourcesSubscribe":       true,
 				"emitUpdateAfterSubscribe": true,
+				"postSubscribeEmitDelayMs": 50,
 			}),
 			"bad": fakeServerConfig(t, map[string]any{
 				"tools":              []any{},
@@ -528,8 +529,8 @@ func TestServer_ResourcesSubscribe_PostUnsubscribeUpdateDropped(t *testing.T) {
 	}
 }
 
-// TestServer_ResourcesSubscribe_ReloadClearsSubs: after a config reload the
-// subscription map is cleared, downstream gets list_changed for resources,
+// TestServer_ResourcesSubscribe_ReloadClearsSubs: after a config reload that
+// restarts a server, its subscriptions are cleared, downstream gets list_changed for resources,
 // and mcpmu does NOT emit a best-effort upstream resources/unsubscribe.
 func TestServer_ResourcesSubscribe_ReloadClearsSubs(t *testing.T) {
 	t.Parallel()
@@ -539,21 +540,22 @@ func TestServer_ResourcesSubscribe_ReloadClearsSubs(t *testing.T) {
 
 	logPath := filepath.Join(t.TempDir(), "requests.log")
 
-	makeCfg := func() *config.Config {
+	makeCfg := func(generation string) *config.Config {
+		srv1 := fakeServerConfig(t, map[string]any{
+			"tools":              []any{},
+			"resources":          []any{map[string]any{"uri": "file:///a.txt", "name": "a"}},
+			"resourcesSubscribe": true,
+			"requestLogPath":     logPath,
+		})
+		// Changing the env forces srv1 to be restarted by the reload
+		srv1.Env["RELOAD_GENERATION"] = generation
 		return &config.Config{
 			SchemaVersion: 1,
-			Servers: map[string]config.ServerConfig{
-				"srv1": fakeServerConfig(t, map[string]any{
-					"tools":              []any{},
-					"resources":          []any{map[string]any{"uri": "file:///a.txt", "name": "a"}},
-					"resourcesSubscribe": true,
-					"requestLogPath":     logPath,
-				}),
-			},
+			Servers:       map[string]config.ServerConfig{"srv1": srv1},
 		}
 	}
 
-	h := startSubscribeTestServer(t, Options{Config: makeCfg(), ExposeResources: true})
+	h := startSubscribeTestServer(t, Options{Config: makeCfg("1"), ExposeResources: true})
 	h.write(
 		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
 		`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`,
@@ -561,9 +563,9 @@ func TestServer_ResourcesSubscribe_ReloadClearsSubs(t *testing.T) {
 	)
 	h.settle(300 * time.Millisecond)
 
-	// Reload rebuilds the supervisor set — upstream transports close, local
-	// subs clear, list_changed notifications are emitted.
-	h.srv.applyReload(context.Background(), makeCfg())
+	// Reload restarts the changed server — its upstream transport closes,
+	// local subs clear, list_changed notifications are emitted.
+	h.srv.applyReload(context.Background(), makeCfg("2"))
 	h.settle(300 * time.Millisecond)
 	h.close(t)
 
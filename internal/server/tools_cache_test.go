@@ -0,0 +1,163 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// upstreamRequests counts the requests for method in a fake server's request log.
+func upstreamRequests(t *testing.T, logPath, method string) int {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read request log: %v", err)
+	}
+	n := 0
+	for line := range strings.Lines(string(data)) {
+		if strings.TrimSpace(line) == method {
+			n++
+		}
+	}
+	return n
+}
+
+func TestServer_ToolsList_RequeriesAfterListChanged(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":              []map[string]any{{"name": "work"}, {"name": "reconfigure"}},
+				"toolsListChangedOn": "reconfigure",
+				"requestLogPath":     logPath,
+			}),
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+	)
+	h.settle(500 * time.Millisecond)
+	if n := upstreamRequests(t, logPath, "tools/list"); n != 1 {
+		t.Fatalf("expected 1 upstream tools/list before list_changed, got %d", n)
+	}
+
+	h.write(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"alpha.reconfigure","arguments":{}}}`)
+	h.settle(300 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":5,"method":"tools/list"}`)
+	h.settle(500 * time.Millisecond)
+	h.close(t)
+
+	if !strings.Contains(h.stdout.String(), `"method":"notifications/tools/list_changed"`) {
+		t.Errorf("expected the upstream's tools/list_changed to be relayed, got:\n%s", h.stdout.String())
+	}
+	responses := parseResponsesByID(t, h.stdout.String())
+	if !strings.Contains(string(responses[5]), "alpha.work") {
+		t.Errorf("expected alpha.work after the refresh, got %s", responses[5])
+	}
+	if n := upstreamRequests(t, logPath, "tools/list"); n != 2 {
+		t.Errorf("expected the upstream to be listed again after list_changed, got %d tools/list", n)
+	}
+}
+
+func TestServer_ToolsList_RequeriesAfterRefreshInterval(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":          []map[string]any{{"name": "work"}},
+				"requestLogPath": logPath,
+			}),
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg, ToolsRefreshInterval: time.Second})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+	)
+	h.settle(500 * time.Millisecond)
+	if n := upstreamRequests(t, logPath, "tools/list"); n != 1 {
+		t.Fatalf("expected 1 upstream tools/list within the refresh interval, got %d", n)
+	}
+
+	time.Sleep(time.Second)
+	h.write(`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+	h.settle(500 * time.Millisecond)
+	h.close(t)
+
+	responses := parseResponsesByID(t, h.stdout.String())
+	if !strings.Contains(string(responses[4]), "alpha.work") {
+		t.Errorf("expected alpha.work after the refresh, got %s", responses[4])
+	}
+	if n := upstreamRequests(t, logPath, "tools/list"); n != 2 {
+		t.Errorf("expected the upstream to be listed again after the refresh interval, got %d tools/list", n)
+	}
+}
+
+// TestServer_ToolsList_FailedRefreshBacksOff: when listing a server's tools
+// again fails, the previous tools are served and later tools/list calls
+// don't ask the server again straight away.
+func TestServer_ToolsList_FailedRefreshBacksOff(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "requests.log")
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools":              []map[string]any{{"name": "work"}, {"name": "reconfigure"}},
+				"toolsListChangedOn": "reconfigure",
+				"failOnAttempt":      map[string]int{"tools/list": 2},
+				"requestLogPath":     logPath,
+			}),
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+	h.settle(500 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"alpha.reconfigure","arguments":{}}}`)
+	h.settle(300 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`)
+	h.settle(300 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":5,"method":"tools/list"}`)
+	h.settle(300 * time.Millisecond)
+	h.close(t)
+
+	responses := parseResponsesByID(t, h.stdout.String())
+	for _, id := range []int{4, 5} {
+		if !strings.Contains(string(responses[id]), "alpha.work") {
+			t.Errorf("expected the previous tools in tools/list id %d, got %s", id, responses[id])
+		}
+	}
+	if n := upstreamRequests(t, logPath, "tools/list"); n != 2 {
+		t.Errorf("expected one failed refresh and no retry, got %d upstream tools/list", n)
+	}
+}
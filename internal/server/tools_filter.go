@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+// toolsFilterTool is the manager tool that narrows this session's tools.
+const toolsFilterTool = config.ManagerToolPrefix + ".tools_filter"
+
+// ToolsFilterResult is the result of mcpmu.tools_filter.
+type ToolsFilterResult struct {
+	// Filtered is false once the filter has been removed.
+	Filtered bool `json:"filtered"`
+	// Tools is what tools/list now returns, manager tools aside.
+	Tools []string `json:"tools"`
+	// Unavailable lists requested names that aren't in the tool list,
+	// because no such tool exists or the namespace denies it.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// handleToolsFilter restricts the tools exposed to this session to the given
+// names, or lifts the restriction when "tools" is omitted. The filter only
+// ever narrows the namespace's own policy, is not persisted, and leaves
+// manager tools alone so a client can always undo it. Clients are told to
+// re-fetch tools/list.
+func (s *Server) handleToolsFilter(ctx context.Context, arguments json.RawMessage) (*ToolCallResult, *RPCError) {
+	var args struct {
+		Tools *[]string `json:"tools"`
+	}
+	if len(arguments) > 0 && string(arguments) != "null" {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, ErrInvalidParams(err.Error())
+		}
+	}
+
+	s.mu.Lock()
+	if args.Tools == nil {
+		s.toolFilter = nil
+		log.Printf("Session tool filter removed")
+	} else {
+		strippedPrefix := s.strippedToolPrefix()
+		s.toolFilter = make(map[string]bool, len(*args.Tools))
+		for _, name := range *args.Tools {
+			if strippedPrefix != "" && !strings.HasPrefix(name, config.ManagerToolPrefix+".") {
+				name = strippedPrefix + "." + name
+			}
+			s.toolFilter[name] = true
+		}
+		log.Printf("Session tool filter set to %d tool(s)", len(*args.Tools))
+	}
+	s.mu.Unlock()
+
+	listed, rpcErr := s.handleToolsList(ctx)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	result := ToolsFilterResult{Filtered: args.Tools != nil, Tools: []string{}}
+	for _, tool := range listed.(toolsListResult).Tools {
+		if !strings.HasPrefix(tool.Name, config.ManagerToolPrefix+".") {
+			result.Tools = append(result.Tools, tool.Name)
+		}
+	}
+	if args.Tools != nil {
+		for _, name := range *args.Tools {
+			if !slices.Contains(result.Tools, name) {
+				result.Unavailable = append(result.Unavailable, name)
+			}
+		}
+	}
+
+	s.sendNotification("notifications/tools/list_changed")
+	return textResult(mustJSON(result)), nil
+}
+
+// filteredOut reports whether the session's tool filter hides a qualified
+// tool name. Caller must hold s.mu.
+func (s *Server) filteredOut(qualifiedName string) bool {
+	return s.toolFilter != nil && !s.toolFilter[qualifiedName]
+}
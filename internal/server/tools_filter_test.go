@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+)
+
+func TestServer_ToolsFilter(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("Skipping end-to-end test in short mode")
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: 1,
+		Servers: map[string]config.ServerConfig{
+			"alpha": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "a1"}, {"name": "a2"}},
+			}),
+			"beta": fakeServerConfig(t, map[string]any{
+				"tools": []map[string]any{{"name": "b1"}},
+			}),
+		},
+		Namespaces: map[string]config.NamespaceConfig{
+			"work": {ServerIDs: []string{"alpha", "beta"}},
+		},
+		ToolPermissions: []config.ToolPermission{
+			{Namespace: "work", Server: "beta", ToolName: "b1", Enabled: false},
+		},
+	}
+
+	h := startSubscribeTestServer(t, Options{Config: cfg, Namespace: "work"})
+	h.write(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test","version":"1.0"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"mcpmu.tools_filter","arguments":{"tools":["alpha.a2","beta.b1","nope.x"]}}}`,
+	)
+	h.settle(500 * time.Millisecond)
+	h.write(
+		`{"jsonrpc":"2.0","id":4,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"alpha.a1","arguments":{}}}`,
+	)
+	// Tool calls run concurrently; let id 5 finish before clearing the filter
+	h.settle(300 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"mcpmu.tools_filter","arguments":{}}}`)
+	h.settle(500 * time.Millisecond)
+	h.write(`{"jsonrpc":"2.0","id":7,"method":"tools/list"}`)
+	h.settle(300 * time.Millisecond)
+	h.close(t)
+
+	out := h.stdout.String()
+	responses := parseResponsesByID(t, out)
+
+	toolNames := func(id int) []string {
+		t.Helper()
+		var resp struct {
+			Result struct {
+				Tools []struct {
+					Name string `json:"name"`
+				} `json:"tools"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(responses[id], &resp); err != nil {
+			t.Fatalf("Unmarshal tools/list %d: %v", id, err)
+		}
+		var names []string
+		for _, tool := range resp.Result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	filterResult := func(id int) ToolsFilterResult {
+		t.Helper()
+		var resp struct {
+			Result struct {
+				Content []struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(responses[id], &resp); err != nil || len(resp.Result.Content) == 0 {
+			t.Fatalf("unexpected tools_filter response: %s", responses[id])
+		}
+		var result ToolsFilterResult
+		if err := json.Unmarshal([]byte(resp.Result.Content[0].Text), &result); err != nil {
+			t.Fatalf("tools_filter text is not JSON: %v", err)
+		}
+		return result
+	}
+
+	if got := toolNames(2); !reflect.DeepEqual(got, []string{"alpha.a1", "alpha.a2"}) {
+		t.Fatalf("unfiltered tools = %v", got)
+	}
+
+	// The filter intersects with the namespace policy: beta.b1 stays denied
+	want := ToolsFilterResult{Filtered: true, Tools: []string{"alpha.a2"}, Unavailable: []string{"beta.b1", "nope.x"}}
+	if got := filterResult(3); !reflect.DeepEqual(got, want) {
+		t.Errorf("tools_filter = %+v, want %+v", got, want)
+	}
+	if got := toolNames(4); !reflect.DeepEqual(got, []string{"alpha.a2"}) {
+		t.Errorf("filtered tools = %v, want [alpha.a2]", got)
+	}
+	if !strings.Contains(string(responses[5]), "tools_filter") {
+		t.Errorf("expected a call to a filtered-out tool to be denied, got %s", responses[5])
+	}
+	if !strings.Contains(out, "notifications/tools/list_changed") {
+		t.Error("expected a tools/list_changed notification after filtering")
+	}
+
+	// Calling without tools removes the filter
+	if got := filterResult(6); got.Filtered || !reflect.DeepEqual(got.Tools, []string{"alpha.a1", "alpha.a2"}) {
+		t.Errorf("cleared tools_filter = %+v", got)
+	}
+	if got := toolNames(7); !reflect.DeepEqual(got, []string{"alpha.a1", "alpha.a2"}) {
+		t.Errorf("tools after clearing the filter = %v", got)
+	}
+}
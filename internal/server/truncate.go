@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Keys set in a tools/call result's _meta when mcpmu truncated the content.
+const (
+	metaTruncated     = "mcpmu/truncated"
+	metaOriginalBytes = "mcpmu/originalBytes"
+)
+
+// truncateToolResult caps the total size of result's content blocks at
+// maxBytes. Blocks that fit are kept as-is; the first block that overflows is
+// cut (text blocks) or dropped (anything else) and replaced by a marker, and
+// later blocks are discarded. Returns true if the result was truncated.
+func truncateToolResult(result *ToolCallResult, maxBytes int) bool {
+	if result == nil || maxBytes <= 0 {
+		return false
+	}
+
+	total := 0
+	for _, block := range result.Content {
+		total += len(block)
+	}
+	if total <= maxBytes {
+		return false
+	}
+
+	marker := fmt.Sprintf("[truncated by mcpmu: result was %d bytes, limit is %d bytes]", total, maxBytes)
+	remaining := maxBytes
+	content := make([]json.RawMessage, 0, len(result.Content))
+	for _, block := range result.Content {
+		if len(block) <= remaining {
+			content = append(content, block)
+			remaining -= len(block)
+			continue
+		}
+
+		var text struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(block, &text); err == nil && text.Type == "text" && remaining > 0 {
+			// Budget the text itself; JSON escaping overhead is not worth
+			// being exact about.
+			content = append(content, textBlock(truncateUTF8(text.Text, remaining)+"\n\n"+marker))
+		} else {
+			content = append(content, textBlock(marker))
+		}
+		break
+	}
+
+	result.Content = content
+	if result.Meta == nil {
+		result.Meta = make(map[string]any)
+	}
+	result.Meta[metaTruncated] = true
+	result.Meta[metaOriginalBytes] = total
+	return true
+}
+
+// truncateUTF8 returns at most n bytes of s without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// textBlock builds a text content block.
+func textBlock(text string) json.RawMessage {
+	block, _ := json.Marshal(map[string]string{"type": "text", "text": text})
+	return block
+}
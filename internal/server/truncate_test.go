@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateToolResult(t *testing.T) {
+	t.Run("under limit is untouched", func(t *testing.T) {
+		result := textResult("hello")
+		if truncateToolResult(result, 1000) {
+			t.Error("Expected no truncation")
+		}
+		if result.Meta != nil {
+			t.Errorf("Expected no _meta, got %v", result.Meta)
+		}
+	})
+
+	t.Run("zero limit disables truncation", func(t *testing.T) {
+		result := textResult(strings.Repeat("a", 5000))
+		if truncateToolResult(result, 0) {
+			t.Error("Expected no truncation with limit 0")
+		}
+	})
+
+	t.Run("later blocks are dropped", func(t *testing.T) {
+		result := &ToolCallResult{Content: []json.RawMessage{
+			textBlock("first"),
+			textBlock(strings.Repeat("b", 500)),
+			textBlock("third"),
+		}}
+		if !truncateToolResult(result, 100) {
+			t.Fatal("Expected truncation")
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("Expected 2 content blocks, got %d", len(result.Content))
+		}
+		if string(result.Content[0]) != string(textBlock("first")) {
+			t.Errorf("Expected first block to be kept verbatim, got %s", result.Content[0])
+		}
+		if result.Meta[metaTruncated] != true {
+			t.Errorf("Expected truncated flag, got %v", result.Meta)
+		}
+	})
+
+	t.Run("non-text block is replaced by marker", func(t *testing.T) {
+		image, _ := json.Marshal(map[string]string{"type": "image", "data": strings.Repeat("A", 500), "mimeType": "image/png"})
+		result := &ToolCallResult{Content: []json.RawMessage{image}}
+		if !truncateToolResult(result, 100) {
+			t.Fatal("Expected truncation")
+		}
+		var block struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(result.Content[0], &block); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if block.Type != "text" || !strings.HasPrefix(block.Text, "[truncated by mcpmu") {
+			t.Errorf("Expected marker text block, got %+v", block)
+		}
+	})
+
+	t.Run("does not split runes", func(t *testing.T) {
+		result := textResult(strings.Repeat("é", 200))
+		if !truncateToolResult(result, 101) {
+			t.Fatal("Expected truncation")
+		}
+		var block struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(result.Content[0], &block); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if !utf8.ValidString(block.Text) {
+			t.Error("Expected valid UTF-8 after truncation")
+		}
+	})
+}
@@ -23,6 +23,8 @@ func SetupTestHome(t *testing.T) string {
 	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpHome, ".config"))
 	// TMPDIR for macOS
 	t.Setenv("TMPDIR", tmpHome)
+	// Don't let a relocated mcpmu directory escape the isolated $HOME
+	t.Setenv("MCPMU_HOME", "")
 
 	// Create the config directory
 	configDir := filepath.Join(tmpHome, ".config", "mcpmu")
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configEditedMsg is sent when the external editor opened on the config
+// file exits.
+type configEditedMsg struct{ err error }
+
+// configFilePath returns the config file the TUI reads and writes.
+func (m *Model) configFilePath() (string, error) {
+	if m.configPath != "" {
+		return m.configPath, nil
+	}
+	return config.ConfigPath()
+}
+
+// editorCommand builds the command that opens path in $VISUAL or $EDITOR,
+// falling back to vi. The variables may carry arguments, e.g. "code -w".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// editConfig suspends the TUI and opens the config file in the user's
+// editor. The config is reloaded when the editor exits.
+func (m *Model) editConfig() tea.Cmd {
+	path, err := m.configFilePath()
+	if err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Can't find config file: %v", err))
+	}
+	m.editingConfig = true
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		return configEditedMsg{err: err}
+	})
+}
+
+// handleConfigEdited reloads the config after the editor exits. A config
+// that fails to parse or validate is reported and the current one is kept.
+func (m *Model) handleConfigEdited(msg configEditedMsg) tea.Cmd {
+	m.editingConfig = false
+	if msg.err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Editor failed: %v", msg.err))
+	}
+
+	path, err := m.configFilePath()
+	if err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Can't find config file: %v", err))
+	}
+	newCfg, err := config.LoadFrom(path)
+	if err != nil {
+		log.Printf("Config reload after edit failed: %v", err)
+		return m.toast.ShowError(fmt.Sprintf("Config not reloaded: %v", err))
+	}
+	return m.reloadConfig(newCfg)
+}
+
+// reloadConfig applies a config just read from disk and reports what
+// changed.
+func (m *Model) reloadConfig(newCfg *config.Config) tea.Cmd {
+	m.recordConfigFingerprint()
+	removed := m.cfg.RemovedServers(newCfg)
+	restarted := m.applyConfig(newCfg)
+	if len(removed) > 0 {
+		log.Printf("Config reload removed server(s): %s", strings.Join(removed, ", "))
+		return m.toast.ShowWarn(fmt.Sprintf("Config reloaded, removed %d server(s): %s", len(removed), strings.Join(removed, ", ")))
+	}
+	if restarted > 0 {
+		return m.toast.ShowSuccess(fmt.Sprintf("Config reloaded, restarting %d server(s)", restarted))
+	}
+	return m.toast.ShowSuccess("Config reloaded")
+}
+
+// applyConfig swaps in a freshly loaded config. Running servers whose
+// connection settings are unchanged keep running; servers that were removed
+// or disabled are stopped, and those whose connection changed are restarted
+// with the new settings. Returns the number of servers restarted.
+func (m *Model) applyConfig(newCfg *config.Config) int {
+	oldCfg := m.cfg
+	m.cfg = newCfg
+	m.logPanel.SetLevelPatterns(logLevelPatterns(newCfg))
+
+	restarted := 0
+	for _, name := range m.supervisor.RunningServers() {
+		oldSrv, _ := oldCfg.GetServer(name)
+		newSrv, ok := newCfg.GetServer(name)
+		switch {
+		case !ok || !newSrv.IsEnabled():
+			log.Printf("Stopping %s after config reload", name)
+			go func() { _ = m.supervisor.Stop(name) }()
+		case !oldSrv.SameConnection(newSrv):
+			log.Printf("Restarting %s after config reload", name)
+			restarted++
+			go func() {
+				_ = m.supervisor.Stop(name)
+				m.startServer(name, newSrv)
+			}()
+		}
+	}
+
+	for name := range m.serverStatuses {
+		if _, ok := newCfg.GetServer(name); !ok {
+			delete(m.serverStatuses, name)
+			delete(m.serverTools, name)
+		}
+	}
+
+	m.refreshServerList()
+	m.refreshNamespaceList()
+	if m.currentView == ViewDetail {
+		_, serverOK := newCfg.GetServer(m.detailServerID)
+		_, nsOK := newCfg.GetNamespace(m.detailNamespaceID)
+		if (m.detailServerID != "" && !serverOK) || (m.detailNamespaceID != "" && !nsOK) {
+			m.currentView = ViewList
+			m.detailServerID = ""
+			m.detailNamespaceID = ""
+		} else {
+			m.refreshDetailViewIfShowing(m.detailServerID)
+			m.refreshNamespaceDetailIfShowing()
+		}
+	}
+	return restarted
+}
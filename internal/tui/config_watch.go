@@ -0,0 +1,245 @@
+package tui
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/server"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// configChangedMsg is sent when the config file changes on disk.
+type configChangedMsg struct{}
+
+// errConfigChangedOnDisk is returned by saveConfig when another process
+// changed the config file since the TUI last loaded or saved it. Saving
+// would silently discard that change.
+var errConfigChangedOnDisk = errors.New("config file changed on disk; press ctrl+r to reload it or answer the reload prompt with n to keep the TUI's version")
+
+// fileFingerprint returns a hash of the file's contents, or "" if it can't
+// be read (e.g. it doesn't exist yet).
+func fileFingerprint(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// resolvedConfigPath expands ~ and symlinks in the config path, matching
+// where SaveTo writes, so the watcher sees the file's real directory.
+func (m *Model) resolvedConfigPath() (string, error) {
+	path, err := m.configFilePath()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path, nil
+}
+
+// recordConfigFingerprint notes the file contents the in-memory config
+// matches, after loading or saving it.
+func (m *Model) recordConfigFingerprint() {
+	path, err := m.resolvedConfigPath()
+	if err != nil {
+		return
+	}
+	m.configFingerprint = fileFingerprint(path)
+	m.configStale = false
+}
+
+// configChangedOnDisk reports whether the config file no longer holds what
+// the TUI last loaded or saved.
+func (m *Model) configChangedOnDisk() bool {
+	path, err := m.resolvedConfigPath()
+	if err != nil {
+		return false
+	}
+	return fileFingerprint(path) != m.configFingerprint
+}
+
+// startConfigWatcher watches the config file for changes made by other
+// processes and reports them on m.configChanges.
+func (m Model) startConfigWatcher() tea.Cmd {
+	return func() tea.Msg {
+		go m.watchConfigFile(m.ctx)
+		return nil
+	}
+}
+
+// waitForConfigChange returns a command that waits for the next change to
+// the config file.
+func (m Model) waitForConfigChange() tea.Cmd {
+	return func() tea.Msg {
+		<-m.configChanges
+		return configChangedMsg{}
+	}
+}
+
+// watchConfigFile watches the config file's directory (to catch atomic
+// renames) and sends on m.configChanges once writes settle. Whether the
+// content actually changed is decided by handleConfigChanged.
+func (m Model) watchConfigFile(ctx context.Context) {
+	path, err := m.resolvedConfigPath()
+	if err != nil {
+		log.Printf("Not watching config: %v", err)
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("Failed to create config watcher: %v", err)
+		return
+	}
+	defer func() { _ = watcher.Close() }()
+
+	dir, filename := filepath.Dir(path), filepath.Base(path)
+	if err := watcher.Add(dir); err != nil {
+		log.Printf("Failed to watch config directory %s: %v", dir, err)
+		return
+	}
+
+	var debounceTimer *time.Timer
+	var debounceMu sync.Mutex
+	defer func() {
+		debounceMu.Lock()
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+		debounceMu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != filename {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			debounceMu.Lock()
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+			debounceTimer = time.AfterFunc(server.DefaultDebounceDelay, func() {
+				select {
+				case m.configChanges <- struct{}{}:
+				default:
+					// A change is already pending
+				}
+			})
+			debounceMu.Unlock()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Config watcher error: %v", err)
+		}
+	}
+}
+
+// handleConfigChanged decides what to do about a change to the config
+// file. The TUI's own saves and changes that leave the config structurally
+// the same are absorbed; otherwise the config is marked stale and the user
+// is asked whether to reload it, discarding the TUI's version, or keep it.
+// Until they answer, saves are refused rather than clobbering the change.
+func (m *Model) handleConfigChanged() tea.Cmd {
+	// The editor opened with ctrl+e is ours; its exit reloads the config
+	if m.editingConfig || !m.configChangedOnDisk() {
+		return nil
+	}
+
+	path, err := m.configFilePath()
+	if err != nil {
+		return nil
+	}
+	newCfg, err := config.LoadExisting(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Config file %s was removed; keeping the current config", path)
+		return nil
+	}
+	if err != nil {
+		log.Printf("Config changed on disk but failed to load: %v", err)
+		m.configStale = true
+		return m.toast.ShowWarn(fmt.Sprintf("Config changed on disk but is invalid: %v", err))
+	}
+	if m.cfg.Diff(newCfg).Empty() {
+		m.recordConfigFingerprint()
+		return nil
+	}
+
+	log.Printf("Config changed on disk by another process")
+	m.configStale = true
+	if m.modalVisible() {
+		return m.toast.ShowWarn("Config changed on disk — press ctrl+r to reload")
+	}
+	m.confirmDlg.Show(
+		"Config changed on disk",
+		"Another program changed the config file. Reload it? Changes made in the TUI since the last save are discarded; n keeps the TUI's version and overwrites the file on the next save.",
+		"reload-config",
+	)
+	return nil
+}
+
+// reloadConfigFromDisk replaces the in-memory config with the file's
+// current contents.
+func (m *Model) reloadConfigFromDisk() tea.Cmd {
+	path, err := m.configFilePath()
+	if err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Can't find config file: %v", err))
+	}
+	newCfg, err := config.LoadFrom(path)
+	if err != nil {
+		log.Printf("Config reload failed: %v", err)
+		return m.toast.ShowError(fmt.Sprintf("Config not reloaded: %v", err))
+	}
+	return m.reloadConfig(newCfg)
+}
+
+// keepInMemoryConfig resolves a conflict in favour of the TUI's config: the
+// external change is acknowledged and the next save overwrites it.
+func (m *Model) keepInMemoryConfig() tea.Cmd {
+	m.recordConfigFingerprint()
+	return m.toast.ShowInfo("Keeping the TUI's config; the next save overwrites the file")
+}
+
+// modalVisible reports whether a dialog or form has the keyboard.
+func (m *Model) modalVisible() bool {
+	return m.serverForm.IsVisible() || m.namespaceForm.IsVisible() ||
+		m.serverPicker.IsVisible() || m.toolPerms.IsVisible() ||
+		m.toolDenyEditor.IsVisible() || m.addMethod.IsVisible() ||
+		m.registryBrowser.IsVisible() || m.nsPicker.IsVisible() ||
+		m.groupPicker.IsVisible() || m.traceViewer.IsVisible() ||
+		m.disableReason.IsVisible() || m.envPrompt.IsVisible() ||
+		m.confirmDlg.IsVisible() || m.showConfirm
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Bigsy/mcpmu/internal/tui/views"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showGroupPicker opens the server group picker, or explains how to define
+// groups when the config has none.
+func (m *Model) showGroupPicker() tea.Cmd {
+	names := m.cfg.GroupNames()
+	if len(names) == 0 {
+		return m.toast.ShowInfo("No server groups — add them under \"groups\" in the config")
+	}
+	groups := make([]views.GroupItem, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, views.GroupItem{Name: name, Members: m.cfg.Groups[name]})
+	}
+	m.groupPicker.Show(groups)
+	return nil
+}
+
+// runGroupAction starts, stops or restarts every server in a group. Members
+// that no longer exist are skipped, as are disabled members when starting.
+// Servers already in the requested state are left alone.
+func (m *Model) runGroupAction(name string, action views.GroupAction) tea.Cmd {
+	members, ok := m.cfg.Groups[name]
+	if !ok {
+		return m.toast.ShowError(fmt.Sprintf("Group \"%s\" not found", name))
+	}
+
+	count := 0
+	for _, id := range members {
+		srv, ok := m.cfg.GetServer(id)
+		if !ok {
+			log.Printf("Group %s: skipping unknown server %s", name, id)
+			continue
+		}
+		active := m.serverStatuses[id].State.IsActive()
+
+		switch action {
+		case views.GroupStart:
+			if active || !srv.IsEnabled() {
+				continue
+			}
+			log.Printf("Starting server %s (group %s)", id, name)
+			go m.startServer(id, srv)
+		case views.GroupStop:
+			if !active {
+				continue
+			}
+			log.Printf("Stopping server %s (group %s)", id, name)
+			go func() { _ = m.supervisor.Stop(id) }()
+		case views.GroupRestart:
+			if !srv.IsEnabled() {
+				continue
+			}
+			log.Printf("Restarting server %s (group %s)", id, name)
+			go func() {
+				if active {
+					_ = m.supervisor.Stop(id)
+				}
+				m.startServer(id, srv)
+			}()
+		}
+		count++
+	}
+
+	if count == 0 {
+		return m.toast.ShowInfo(fmt.Sprintf("Nothing to %s in group \"%s\"", action, name))
+	}
+	verb := map[views.GroupAction]string{
+		views.GroupStart:   "Starting",
+		views.GroupStop:    "Stopping",
+		views.GroupRestart: "Restarting",
+	}[action]
+	return m.toast.ShowInfo(fmt.Sprintf("%s %d server(s) in group \"%s\"", verb, count, name))
+}
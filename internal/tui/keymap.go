@@ -28,6 +28,9 @@ type KeyBindings struct {
 	Escape  key.Binding
 	CtrlC   key.Binding
 
+	EditConfig   key.Binding // Open the config file in $EDITOR
+	ReloadConfig key.Binding // Reload the config file, discarding in-memory changes
+
 	// List navigation
 	Up     key.Binding
 	Down   key.Binding
@@ -36,17 +39,30 @@ type KeyBindings struct {
 	Enter  key.Binding
 
 	// Server actions
-	Test          key.Binding // Toggle start/stop for testing
-	Add           key.Binding
-	Edit          key.Binding
-	Delete        key.Binding
-	Duplicate     key.Binding
-	ToggleLogs    key.Binding
-	FollowLogs    key.Binding
-	WrapLogs      key.Binding
-	ToggleEnabled key.Binding
-	Login         key.Binding // OAuth login for HTTP servers
-	Logout        key.Binding // OAuth logout for HTTP servers
+	Test           key.Binding // Toggle start/stop for testing
+	Add            key.Binding
+	Edit           key.Binding
+	Delete         key.Binding
+	Duplicate      key.Binding
+	ToggleLogs     key.Binding
+	FollowLogs     key.Binding
+	WrapLogs       key.Binding
+	RawLogs        key.Binding // Toggle ANSI escapes in log lines
+	LogLevel       key.Binding // Cycle the minimum log level shown
+	ToggleEnabled  key.Binding
+	Login          key.Binding // OAuth login for HTTP servers
+	Logout         key.Binding // OAuth logout for HTTP servers
+	Reachability   key.Binding // Toggle background reachability checks for HTTP servers
+	StartAutostart key.Binding // Start all autostart servers on demand
+	Groups         key.Binding // Start/stop/restart a server group
+	NextNeedsAuth  key.Binding // Select the next server waiting for OAuth login
+	CopyLaunch     key.Binding // Show and copy a stdio server's launch command
+	ToolSchema     key.Binding // Expand the selected tool's input schema
+	Namespaces     key.Binding // Jump to a namespace containing the server
+	Trace          key.Binding // Toggle JSON-RPC tracing for a server
+	ViewTrace      key.Binding // Show a server's captured JSON-RPC trace
+	NextTool       key.Binding
+	PrevTool       key.Binding
 
 	// Confirm dialog
 	Yes key.Binding
@@ -112,6 +128,15 @@ func NewKeyBindings() KeyBindings {
 			key.WithHelp("enter", "select"),
 		),
 
+		EditConfig: key.NewBinding(
+			key.WithKeys("ctrl+e"),
+			key.WithHelp("ctrl+e", "edit config in $EDITOR"),
+		),
+		ReloadConfig: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reload config from disk"),
+		),
+
 		// Server actions
 		Test: key.NewBinding(
 			key.WithKeys("t"),
@@ -145,6 +170,14 @@ func NewKeyBindings() KeyBindings {
 			key.WithKeys("w"),
 			key.WithHelp("w", "wrap"),
 		),
+		RawLogs: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "raw log colors"),
+		),
+		LogLevel: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "min log level"),
+		),
 		ToggleEnabled: key.NewBinding(
 			key.WithKeys("E"),
 			key.WithHelp("E", "enable/disable"),
@@ -157,6 +190,50 @@ func NewKeyBindings() KeyBindings {
 			key.WithKeys("O"),
 			key.WithHelp("O", "OAuth logout"),
 		),
+		Reachability: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "ping HTTP servers"),
+		),
+		StartAutostart: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "start autostart servers"),
+		),
+		Groups: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "server groups"),
+		),
+		NextNeedsAuth: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "next server needing login"),
+		),
+		CopyLaunch: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy launch command"),
+		),
+		ToolSchema: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "tool schema"),
+		),
+		Namespaces: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "jump to namespace"),
+		),
+		Trace: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "toggle JSON-RPC trace"),
+		),
+		ViewTrace: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "view trace"),
+		),
+		NextTool: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next tool"),
+		),
+		PrevTool: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "prev tool"),
+		),
 
 		// Confirm dialog
 		Yes: key.NewBinding(
@@ -179,8 +256,9 @@ func (k KeyBindings) ShortHelp() []key.Binding {
 func (k KeyBindings) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.Escape},
-		{k.Test, k.ToggleEnabled, k.Add, k.Edit, k.Delete, k.Duplicate, k.Login, k.Logout},
-		{k.ToggleLogs, k.FollowLogs, k.WrapLogs, k.TabPrev, k.TabNext, k.Tab1, k.Tab2},
-		{k.Help, k.Quit, k.CtrlC},
+		{k.Test, k.ToggleEnabled, k.Add, k.Edit, k.Delete, k.Duplicate, k.Login, k.Logout, k.NextNeedsAuth, k.Reachability, k.Groups, k.CopyLaunch},
+		{k.PrevTool, k.NextTool, k.ToolSchema, k.Namespaces},
+		{k.ToggleLogs, k.FollowLogs, k.WrapLogs, k.RawLogs, k.LogLevel, k.TabPrev, k.TabNext, k.Tab1, k.Tab2},
+		{k.EditConfig, k.ReloadConfig, k.Help, k.Quit, k.CtrlC},
 	}
 }
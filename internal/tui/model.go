@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
@@ -17,8 +20,10 @@ import (
 	"github.com/Bigsy/mcpmu/internal/server"
 	"github.com/Bigsy/mcpmu/internal/tui/theme"
 	"github.com/Bigsy/mcpmu/internal/tui/views"
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -73,16 +78,33 @@ type Model struct {
 	registryBrowser views.RegistryBrowserModel
 
 	// Shared Components
-	logPanel    views.LogPanelModel
-	helpOverlay views.HelpOverlayModel
-	confirmDlg  views.ConfirmModel
-	addMethod   views.AddMethodModel
-	toast       views.ToastModel
+	logPanel      views.LogPanelModel
+	helpOverlay   views.HelpOverlayModel
+	confirmDlg    views.ConfirmModel
+	addMethod     views.AddMethodModel
+	nsPicker      views.NamespacePickerModel
+	groupPicker   views.GroupPickerModel
+	traceViewer   views.TraceViewerModel
+	disableReason views.DisableReasonModel
+	envPrompt     views.EnvPromptModel
+	toast         views.ToastModel
 
 	// Server status tracking
 	serverStatuses map[string]events.ServerStatus
 	serverTools    map[string][]events.McpTool
 
+	// HTTP reachability checks (toggled, off by default)
+	reachEnabled bool
+	reachGen     int
+	reachability map[string]views.Reachability
+
+	// Skip starting autostart servers on Init (--no-autostart)
+	noAutostart bool
+
+	// JSON-RPC traces by server, kept after tracing stops so they can still
+	// be viewed and exported
+	traces map[string]*mcp.Trace
+
 	// Detail view tracking
 	detailServerID    string
 	detailNamespaceID string
@@ -105,6 +127,15 @@ type Model struct {
 
 	// Event channel for Bubble Tea integration
 	eventCh chan events.Event
+
+	// External config changes: the watcher signals configChanges, and
+	// configFingerprint is the file content the in-memory cfg matches.
+	// configStale is set while an external change hasn't been reloaded or
+	// dismissed; editingConfig while ctrl+e's editor is open.
+	configChanges     chan struct{}
+	configFingerprint string
+	configStale       bool
+	editingConfig     bool
 }
 
 // newServerFormPtr creates a pointer to a ServerFormModel.
@@ -152,11 +183,21 @@ func NewModel(cfg *config.Config, supervisor *process.Supervisor, bus *events.Bu
 		helpOverlay:     views.NewHelpOverlay(th),
 		confirmDlg:      views.NewConfirm(th),
 		addMethod:       views.NewAddMethod(th),
+		nsPicker:        views.NewNamespacePicker(th),
+		groupPicker:     views.NewGroupPicker(th),
+		traceViewer:     views.NewTraceViewer(th),
+		disableReason:   views.NewDisableReason(th),
+		envPrompt:       views.NewEnvPrompt(th),
 		toast:           views.NewToast(th),
 		serverStatuses:  make(map[string]events.ServerStatus),
 		serverTools:     make(map[string][]events.McpTool),
+		reachability:    make(map[string]views.Reachability),
+		traces:          make(map[string]*mcp.Trace),
 		eventCh:         make(chan events.Event, 100),
+		configChanges:   make(chan struct{}, 1),
 	}
+	m.recordConfigFingerprint()
+	m.logPanel.SetLevelPatterns(logLevelPatterns(cfg))
 
 	// Subscribe to events
 	bus.Subscribe(func(e events.Event) {
@@ -171,12 +212,60 @@ func NewModel(cfg *config.Config, supervisor *process.Supervisor, bus *events.Bu
 	m.refreshServerList()
 	m.refreshNamespaceList()
 
+	// Ask for promptOnStart env values before anything starts
+	if items := promptOnStartItems(cfg); len(items) > 0 {
+		m.envPrompt.Show(items)
+	}
+
 	return m
 }
 
-// saveConfig saves the config to the resolved config path.
+// logLevelPatterns compiles each server's logLevelPattern for the log
+// panel. Patterns are checked when the config loads, so one that fails to
+// compile here is skipped and the server gets the built-in pattern.
+func logLevelPatterns(cfg *config.Config) map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, entry := range cfg.ServerEntries() {
+		if entry.Config.LogLevelPattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(entry.Config.LogLevelPattern)
+		if err != nil {
+			log.Printf("Ignoring logLevelPattern for %s: %v", entry.Name, err)
+			continue
+		}
+		patterns[entry.Name] = re
+	}
+	return patterns
+}
+
+// promptOnStartItems lists the promptOnStart env keys of enabled servers.
+func promptOnStartItems(cfg *config.Config) []views.EnvPromptItem {
+	var items []views.EnvPromptItem
+	for _, entry := range cfg.ServerEntries() {
+		if !entry.Config.IsEnabled() {
+			continue
+		}
+		for _, k := range entry.Config.PromptOnStart {
+			items = append(items, views.EnvPromptItem{Server: entry.Name, Key: k})
+		}
+	}
+	return items
+}
+
+// saveConfig saves the config to the resolved config path. It refuses to
+// overwrite a file another process has changed since the TUI last loaded or
+// saved it.
 func (m *Model) saveConfig() error {
-	return config.SaveTo(m.cfg, m.configPath)
+	if m.configChangedOnDisk() {
+		m.configStale = true
+		return errConfigChangedOnDisk
+	}
+	if err := config.SaveTo(m.cfg, m.configPath); err != nil {
+		return err
+	}
+	m.recordConfigFingerprint()
+	return nil
 }
 
 func (m *Model) switchToTab(tab Tab) {
@@ -194,6 +283,23 @@ func (m *Model) switchToTab(tab Tab) {
 	}
 }
 
+// FocusNamespace opens the Namespaces tab with the given namespace selected.
+// It is used to start the TUI on the last-used namespace; an unknown name
+// leaves the model on the Servers tab.
+func (m *Model) FocusNamespace(name string) {
+	if _, ok := m.cfg.GetNamespace(name); !ok {
+		return
+	}
+	m.switchToTab(TabNamespaces)
+	m.namespaceList.SelectName(name)
+}
+
+// DisableAutostart stops Init from starting autostart servers. They can
+// still be started on demand from the server list.
+func (m *Model) DisableAutostart() {
+	m.noAutostart = true
+}
+
 func (m *Model) applyFocus() {
 	// Reset everything to unfocused, then mark the active pane focused so it
 	// picks up the orange accent border.
@@ -220,27 +326,35 @@ func (m *Model) applyFocus() {
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
+	watch := tea.Batch(m.startConfigWatcher(), m.waitForConfigChange())
+	if m.noAutostart {
+		return tea.Batch(m.waitForEvent(), watch)
+	}
+	// Autostart waits until the promptOnStart values are in
+	if m.envPrompt.IsVisible() {
+		return tea.Batch(textinput.Blink, m.waitForEvent(), watch)
+	}
 	// Start autostart servers and wait for events
 	return tea.Batch(
-		m.startAutostartServers(),
+		m.startAutostartServers(m.autostartEntries()),
 		m.waitForEvent(),
+		watch,
 	)
 }
 
-// startAutostartServers starts all servers with autostart=true.
-func (m Model) startAutostartServers() tea.Cmd {
+// autostartEntries returns the enabled servers with autostart=true, in
+// start order.
+func (m Model) autostartEntries() []config.ServerEntry {
+	return m.cfg.AutostartEntries()
+}
+
+// startAutostartServers starts the given autostart servers in order, with at
+// most cfg.AutostartConcurrency starting at once. It returns immediately;
+// the starts run in the background.
+func (m Model) startAutostartServers(entries []config.ServerEntry) tea.Cmd {
+	limit := m.cfg.AutostartConcurrency
 	return func() tea.Msg {
-		for _, entry := range m.cfg.ServerEntries() {
-			if entry.Config.Autostart && entry.Config.IsEnabled() {
-				log.Printf("Autostarting server: %s", entry.Name)
-				go func(name string, s config.ServerConfig) {
-					_, err := m.supervisor.Start(m.ctx, name, s)
-					if err != nil {
-						log.Printf("Failed to autostart server %s: %v", name, err)
-					}
-				}(entry.Name, entry.Config)
-			}
-		}
+		go m.supervisor.Autostart(m.ctx, entries, limit)
 		return nil
 	}
 }
@@ -256,6 +370,11 @@ func (m Model) waitForEvent() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// External config changes are noticed whatever is on screen
+	if _, ok := msg.(configChangedMsg); ok {
+		return m, tea.Batch(m.handleConfigChanged(), m.waitForConfigChange())
+	}
+
 	// Handle modal forms first - they need ALL messages
 	// Server form
 	if m.serverForm.IsVisible() {
@@ -292,6 +411,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateWithRegistryBrowser(msg)
 	}
 
+	// Namespace picker modal
+	if m.nsPicker.IsVisible() {
+		return m.updateWithNamespacePicker(msg)
+	}
+
+	// Server group picker modal
+	if m.groupPicker.IsVisible() {
+		return m.updateWithGroupPicker(msg)
+	}
+
+	// Trace viewer modal
+	if m.traceViewer.IsVisible() {
+		return m.updateWithTraceViewer(msg)
+	}
+
+	// Disable reason prompt
+	if m.disableReason.IsVisible() {
+		return m.updateWithDisableReason(msg)
+	}
+
+	// promptOnStart env prompt
+	if m.envPrompt.IsVisible() {
+		return m.updateWithEnvPrompt(msg)
+	}
+
 	// Handle pending registry install (deferred form opening after browser closes)
 	if m.pendingRegistryInstall != nil {
 		spec := m.pendingRegistryInstall
@@ -368,6 +512,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case views.ToolDenyResult:
 		return m.handleToolDenyResult(msg)
 
+	case views.NamespacePickerResult:
+		if msg.Submitted {
+			m.jumpToNamespace(msg.Name)
+		}
+		return m, nil
+
+	case views.GroupPickerResult:
+		if msg.Submitted {
+			return m, m.runGroupAction(msg.Name, msg.Action)
+		}
+		return m, nil
+
+	case views.TraceViewerResult:
+		if msg.Export {
+			return m, m.exportTrace(msg.ServerName)
+		}
+		return m, nil
+
+	case views.DisableReasonResult:
+		if msg.Submitted {
+			m.setServerEnabled(msg.ServerName, false, msg.Reason)
+		}
+		return m, nil
+
+	case views.EnvPromptResult:
+		return m, m.handleEnvPromptResult(msg)
+
 	case views.AddMethodResult:
 		m.addMethod.Hide()
 		if msg.Submitted {
@@ -390,6 +561,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case views.ConfirmResult:
 		return m.handleConfirmResult(msg)
 
+	case reachabilityTickMsg:
+		if m.reachEnabled && msg.gen == m.reachGen {
+			return m, m.checkReachability()
+		}
+		return m, nil
+
+	case reachabilityResultMsg:
+		return m, m.handleReachabilityResult(msg)
+
+	case configEditedMsg:
+		return m, m.handleConfigEdited(msg)
+
 	case permDiscoveryTimeoutMsg:
 		// Handle permission discovery timeout
 		if m.toolPerms.IsDiscovering() {
@@ -520,6 +703,9 @@ func (m *Model) handleEvent(e events.Event) tea.Cmd {
 		m.logPanel.AppendLog(evt.ServerID(), evt.Line)
 
 	case events.ErrorEvent:
+		if evt.Warning {
+			return m.toast.ShowWarn(evt.Message)
+		}
 		return m.toast.ShowError(evt.Message)
 	}
 	return nil
@@ -597,6 +783,27 @@ func (m *Model) handleKey(msg tea.KeyMsg) (handled bool, model tea.Model, cmd te
 			m.logPanel.ToggleWrap()
 		}
 		return true, m, nil
+
+	case key.Matches(msg, m.keys.RawLogs):
+		if m.logPanel.IsVisible() {
+			m.logPanel.ToggleRaw()
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.LogLevel):
+		if m.logPanel.IsVisible() {
+			m.logPanel.CycleMinLevel()
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.NextNeedsAuth):
+		return true, m, m.selectNextNeedsAuth()
+
+	case key.Matches(msg, m.keys.EditConfig):
+		return true, m, m.editConfig()
+
+	case key.Matches(msg, m.keys.ReloadConfig):
+		return true, m, m.reloadConfigFromDisk()
 	}
 
 	// Tab and view-specific keys
@@ -632,6 +839,21 @@ func (m *Model) handleServerListKey(msg tea.KeyMsg) (handled bool, model tea.Mod
 		}
 		return true, m, nil
 
+	case key.Matches(msg, m.keys.StartAutostart):
+		var pending []config.ServerEntry
+		for _, entry := range m.autostartEntries() {
+			if !m.serverStatuses[entry.Name].State.IsActive() {
+				pending = append(pending, entry)
+			}
+		}
+		if len(pending) == 0 {
+			return true, m, m.toast.ShowInfo("No stopped autostart servers")
+		}
+		return true, m, tea.Batch(m.startAutostartServers(pending), m.toast.ShowInfo(fmt.Sprintf("Starting %d autostart server(s)", len(pending))))
+
+	case key.Matches(msg, m.keys.Groups):
+		return true, m, m.showGroupPicker()
+
 	case key.Matches(msg, m.keys.Test):
 		log.Printf("Test key pressed, selected item: %v", m.serverList.SelectedItem())
 		if item := m.serverList.SelectedItem(); item != nil {
@@ -648,7 +870,7 @@ func (m *Model) handleServerListKey(msg tea.KeyMsg) (handled bool, model tea.Mod
 
 	case key.Matches(msg, m.keys.ToggleEnabled):
 		if item := m.serverList.SelectedItem(); item != nil {
-			m.toggleServerEnabled(item.Name)
+			return true, m, m.toggleServerEnabled(item.Name)
 		}
 		return true, m, nil
 
@@ -656,6 +878,9 @@ func (m *Model) handleServerListKey(msg tea.KeyMsg) (handled bool, model tea.Mod
 		m.addMethod.Show()
 		return true, m, nil
 
+	case key.Matches(msg, m.keys.Reachability):
+		return true, m, m.toggleReachability()
+
 	case key.Matches(msg, m.keys.Edit):
 		if item := m.serverList.SelectedItem(); item != nil {
 			cmd := m.serverForm.ShowEdit(item.Name, item.Config)
@@ -715,7 +940,7 @@ func (m *Model) handleServerDetailKey(msg tea.KeyMsg) (handled bool, model tea.M
 
 	case key.Matches(msg, m.keys.ToggleEnabled):
 		if m.detailServerID != "" {
-			m.toggleServerEnabled(m.detailServerID)
+			return true, m, m.toggleServerEnabled(m.detailServerID)
 		}
 		return true, m, nil
 
@@ -747,6 +972,60 @@ func (m *Model) handleServerDetailKey(msg tea.KeyMsg) (handled bool, model tea.M
 		}
 		return true, m, nil
 
+	case key.Matches(msg, m.keys.ToolSchema):
+		if !m.serverDetail.ToggleToolSchema() {
+			return true, m, m.toast.ShowError("No tools to show a schema for")
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.NextTool):
+		m.serverDetail.SelectNextTool()
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.PrevTool):
+		m.serverDetail.SelectPrevTool()
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.CopyLaunch):
+		if m.detailServerID != "" {
+			launch := m.serverDetail.LaunchCommand()
+			if launch == "" {
+				return true, m, m.toast.ShowError("Launch command only applies to stdio servers")
+			}
+			m.serverDetail.ShowLaunchCommand()
+			if err := clipboard.WriteAll(launch); err != nil {
+				return true, m, m.toast.ShowError(fmt.Sprintf("Copy failed: %v", err))
+			}
+			return true, m, m.toast.ShowSuccess("Launch command copied")
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.Namespaces):
+		if m.detailServerID != "" {
+			namespaces := m.serverNamespaces(m.detailServerID)
+			if len(namespaces) == 0 {
+				return true, m, m.toast.ShowInfo(fmt.Sprintf("Server \"%s\" is not in any namespace", m.detailServerID))
+			}
+			m.nsPicker.Show(m.detailServerID, namespaces)
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.Trace):
+		if m.detailServerID != "" {
+			return true, m, m.toggleTrace(m.detailServerID)
+		}
+		return true, m, nil
+
+	case key.Matches(msg, m.keys.ViewTrace):
+		if m.detailServerID != "" {
+			trace := m.traces[m.detailServerID]
+			if trace == nil {
+				return true, m, m.toast.ShowError("No trace yet — press R to start tracing")
+			}
+			m.traceViewer.Show(m.detailServerID, trace.Entries())
+		}
+		return true, m, nil
+
 	case msg.String() == "p": // Edit denied tools
 		if m.detailServerID != "" {
 			tools, _, _ := m.getServerToolsForDetail(m.detailServerID)
@@ -850,6 +1129,13 @@ func (m Model) handleServerFormResult(result views.ServerFormResult) (tea.Model,
 }
 
 func (m Model) handleConfirmResult(result views.ConfirmResult) (tea.Model, tea.Cmd) {
+	if result.Tag == "reload-config" {
+		if result.Confirmed {
+			return m, m.reloadConfigFromDisk()
+		}
+		return m, m.keepInMemoryConfig()
+	}
+
 	if result.Tag == "delete-server" && result.Confirmed {
 		// Server name is the ID now
 		serverName := m.pendingDeleteID
@@ -974,24 +1260,38 @@ func oauthLoginHint(state events.RuntimeState) string {
 	}
 }
 
-func (m *Model) toggleServerEnabled(id string) {
+// toggleServerEnabled enables a disabled server, or prompts for a reason
+// before disabling an enabled one.
+func (m *Model) toggleServerEnabled(id string) tea.Cmd {
 	srv, ok := m.cfg.GetServer(id)
 	if !ok {
-		return
+		return nil
+	}
+	if srv.IsEnabled() {
+		return m.disableReason.Show(id)
 	}
+	m.setServerEnabled(id, true, "")
+	return nil
+}
 
-	// Toggle enabled state
-	currentlyEnabled := srv.IsEnabled()
-	newEnabled := !currentlyEnabled
+// setServerEnabled enables or disables a server (recording reason when
+// disabling) and saves the config.
+func (m *Model) setServerEnabled(id string, enabled bool, reason string) {
+	srv, ok := m.cfg.GetServer(id)
+	if !ok {
+		return
+	}
 
 	// Avoid a contradictory "running + disabled" state by stopping the server
 	// when disabling.
-	if currentlyEnabled && !newEnabled {
+	if !enabled {
 		if status, ok := m.serverStatuses[id]; ok && status.State == events.StateRunning {
 			go func() { _ = m.supervisor.Stop(id) }()
 		}
+		srv.Disable(reason)
+	} else {
+		srv.SetEnabled(true)
 	}
-	srv.SetEnabled(newEnabled)
 	m.cfg.Servers[id] = srv
 
 	// Save config synchronously (fast operation, avoids race conditions)
@@ -1000,6 +1300,7 @@ func (m *Model) toggleServerEnabled(id string) {
 	}
 
 	m.refreshServerList()
+	m.refreshDetailViewIfShowing(id)
 }
 
 func (m *Model) refreshServerList() {
@@ -1008,25 +1309,57 @@ func (m *Model) refreshServerList() {
 	for i, entry := range entries {
 		status := m.serverStatuses[entry.Name]
 
-		// Find namespaces this server belongs to
-		var namespaceNames []string
-		for nsName, ns := range m.cfg.Namespaces {
-			if slices.Contains(ns.ServerIDs, entry.Name) {
-				namespaceNames = append(namespaceNames, nsName)
-			}
-		}
-		sort.Strings(namespaceNames)
-
 		items[i] = views.ServerItem{
 			Name:       entry.Name,
 			Config:     entry.Config,
 			Status:     status,
-			Namespaces: namespaceNames,
+			Namespaces: m.serverNamespaces(entry.Name),
+			Reach:      m.reachability[entry.Name],
 		}
 	}
 	m.serverList.SetItems(items)
 }
 
+// needsAuthCount returns how many configured servers are waiting for an
+// OAuth login.
+func (m *Model) needsAuthCount() int {
+	n := 0
+	for name, status := range m.serverStatuses {
+		if _, ok := m.cfg.GetServer(name); ok && status.State == events.StateNeedsAuth {
+			n++
+		}
+	}
+	return n
+}
+
+// selectNextNeedsAuth moves to the next server in the list waiting for an
+// OAuth login, switching to the server list if needed, so logins can be
+// worked through one after another with N then L.
+func (m *Model) selectNextNeedsAuth() tea.Cmd {
+	if m.needsAuthCount() == 0 {
+		return m.toast.ShowInfo("No servers need login")
+	}
+	if m.activeTab != TabServers || m.currentView != ViewList {
+		m.switchToTab(TabServers)
+	}
+	m.serverList.SelectNext(func(item views.ServerItem) bool {
+		return item.Status.State == events.StateNeedsAuth
+	})
+	return nil
+}
+
+// serverNamespaces returns the sorted names of the namespaces containing a server.
+func (m *Model) serverNamespaces(serverName string) []string {
+	var names []string
+	for nsName, ns := range m.cfg.Namespaces {
+		if slices.Contains(ns.ServerIDs, serverName) {
+			names = append(names, nsName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // refreshDetailViewIfShowing updates the detail view if currently showing the specified server.
 func (m *Model) refreshDetailViewIfShowing(serverID string) {
 	if m.currentView != ViewDetail || m.detailServerID != serverID {
@@ -1048,6 +1381,9 @@ func (m *Model) convertTools(mcpTools []events.McpTool) []mcp.Tool {
 			Name:        t.Name,
 			Description: t.Description,
 		}
+		if len(t.InputSchema) > 0 {
+			result[i].InputSchema = t.InputSchema
+		}
 	}
 	return result
 }
@@ -1082,6 +1418,11 @@ func (m *Model) updateLayout() {
 	m.toolPerms.SetSize(m.width, m.height)
 	m.toolDenyEditor.SetSize(m.width, m.height)
 	m.addMethod.SetSize(m.width, m.height)
+	m.nsPicker.SetSize(m.width, m.height)
+	m.groupPicker.SetSize(m.width, m.height)
+	m.traceViewer.SetSize(m.width, m.height)
+	m.disableReason.SetSize(m.width, m.height)
+	m.envPrompt.SetSize(m.width, m.height)
 	m.registryBrowser.SetSize(m.width, m.height)
 
 	if m.logPanel.IsVisible() {
@@ -1171,6 +1512,23 @@ func (m Model) View() string {
 		content = m.registryBrowser.RenderOverlay(content, m.width, m.height)
 	}
 
+	// Namespace picker overlay
+	if m.nsPicker.IsVisible() {
+		content = m.nsPicker.RenderOverlay(content, m.width, m.height)
+	}
+	if m.groupPicker.IsVisible() {
+		content = m.groupPicker.RenderOverlay(content, m.width, m.height)
+	}
+	if m.traceViewer.IsVisible() {
+		content = m.traceViewer.RenderOverlay(content, m.width, m.height)
+	}
+	if m.disableReason.IsVisible() {
+		content = m.disableReason.RenderOverlay(content, m.width, m.height)
+	}
+	if m.envPrompt.IsVisible() {
+		content = m.envPrompt.RenderOverlay(content, m.width, m.height)
+	}
+
 	// Confirm dialog overlay (delete, etc.)
 	if m.confirmDlg.IsVisible() {
 		content = m.confirmDlg.RenderOverlay(content, m.width, m.height)
@@ -1225,6 +1583,12 @@ func (m Model) renderStatusBar() string {
 	totalCount := len(m.cfg.Servers)
 
 	left := fmt.Sprintf("%d/%d servers running", runningCount, totalCount)
+	if n := m.needsAuthCount(); n > 0 {
+		left += "  " + m.theme.Warn.Render(fmt.Sprintf("%d need login (N:next)", n))
+	}
+	if m.configStale {
+		left += "  " + m.theme.Warn.Render("⚠ config changed on disk (ctrl+r reload)")
+	}
 
 	// Show context-sensitive key hints based on tab and view
 	var keys string
@@ -1350,6 +1714,210 @@ func (m Model) updateModal(msg tea.Msg, cfg modalUpdateConfig) (tea.Model, tea.C
 	return m, tea.Batch(cmds...)
 }
 
+func (m Model) updateWithNamespacePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.CtrlC) {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateLayout()
+	}
+
+	var cmd tea.Cmd
+	m.nsPicker, cmd = m.nsPicker.Update(msg)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle events while modal is open
+	if evt, ok := msg.(events.Event); ok {
+		if eCmd := m.handleEvent(evt); eCmd != nil {
+			cmds = append(cmds, eCmd)
+		}
+		cmds = append(cmds, m.waitForEvent())
+	}
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+	if toastCmd != nil {
+		cmds = append(cmds, toastCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) updateWithGroupPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.CtrlC) {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateLayout()
+	}
+
+	var cmd tea.Cmd
+	m.groupPicker, cmd = m.groupPicker.Update(msg)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle events while modal is open
+	if evt, ok := msg.(events.Event); ok {
+		if eCmd := m.handleEvent(evt); eCmd != nil {
+			cmds = append(cmds, eCmd)
+		}
+		cmds = append(cmds, m.waitForEvent())
+	}
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+	if toastCmd != nil {
+		cmds = append(cmds, toastCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) updateWithTraceViewer(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.CtrlC) {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateLayout()
+	}
+
+	var cmd tea.Cmd
+	m.traceViewer, cmd = m.traceViewer.Update(msg)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle events while modal is open
+	if evt, ok := msg.(events.Event); ok {
+		if eCmd := m.handleEvent(evt); eCmd != nil {
+			cmds = append(cmds, eCmd)
+		}
+		cmds = append(cmds, m.waitForEvent())
+	}
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+	if toastCmd != nil {
+		cmds = append(cmds, toastCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) updateWithDisableReason(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.CtrlC) {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateLayout()
+	}
+
+	var cmd tea.Cmd
+	m.disableReason, cmd = m.disableReason.Update(msg)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle events while modal is open
+	if evt, ok := msg.(events.Event); ok {
+		if eCmd := m.handleEvent(evt); eCmd != nil {
+			cmds = append(cmds, eCmd)
+		}
+		cmds = append(cmds, m.waitForEvent())
+	}
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+	if toastCmd != nil {
+		cmds = append(cmds, toastCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) updateWithEnvPrompt(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.CtrlC) {
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.updateLayout()
+	}
+
+	var cmd tea.Cmd
+	m.envPrompt, cmd = m.envPrompt.Update(msg)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle events while modal is open
+	if evt, ok := msg.(events.Event); ok {
+		if eCmd := m.handleEvent(evt); eCmd != nil {
+			cmds = append(cmds, eCmd)
+		}
+		cmds = append(cmds, m.waitForEvent())
+	}
+
+	var toastCmd tea.Cmd
+	m.toast, toastCmd = m.toast.Update(msg)
+	if toastCmd != nil {
+		cmds = append(cmds, toastCmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleEnvPromptResult hands the entered promptOnStart values to the
+// supervisor for this session, then runs the autostart that Init deferred.
+func (m *Model) handleEnvPromptResult(result views.EnvPromptResult) tea.Cmd {
+	count := 0
+	for name, env := range result.Values {
+		m.supervisor.SetSessionEnv(name, env)
+		count += len(env)
+	}
+
+	var cmds []tea.Cmd
+	if count > 0 {
+		cmds = append(cmds, m.toast.ShowInfo(fmt.Sprintf("Using %d prompted value(s) for this session", count)))
+	}
+	if !m.noAutostart {
+		cmds = append(cmds, m.startAutostartServers(m.autostartEntries()))
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m Model) updateWithAddMethod(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -1577,16 +2145,68 @@ func formatEnvMap(env map[string]string) string {
 // Namespace key handlers
 // ============================================================================
 
+// openSelectedNamespace shows the detail view for the selected namespace.
+func (m *Model) openSelectedNamespace() {
+	item := m.namespaceList.SelectedItem()
+	if item == nil {
+		return
+	}
+	m.currentView = ViewDetail
+	m.detailNamespaceID = item.Name
+	permissions := m.cfg.GetToolPermissionsForNamespace(item.Name)
+	serverTokens := m.getServerTokensForNamespace(item.Name)
+	m.namespaceDetail.SetNamespace(item.Name, &item.Config, item.IsDefault, m.cfg.ServerEntries(), permissions, serverTokens, m.getToolCollisionsForNamespace(item.Name))
+}
+
+// jumpToNamespace switches to the Namespaces tab and opens a namespace's detail view.
+func (m *Model) jumpToNamespace(name string) {
+	if _, ok := m.cfg.GetNamespace(name); !ok {
+		return
+	}
+	m.switchToTab(TabNamespaces)
+	if m.namespaceList.SelectName(name) {
+		m.openSelectedNamespace()
+	}
+}
+
+// toggleTrace starts or stops capturing a server's JSON-RPC frames. A new
+// trace replaces the previous one; stopping keeps it viewable.
+func (m *Model) toggleTrace(name string) tea.Cmd {
+	if m.supervisor.Trace(name) != nil {
+		m.supervisor.SetTrace(name, nil)
+		return m.toast.ShowInfo(fmt.Sprintf("Stopped tracing \"%s\"", name))
+	}
+	trace := mcp.NewTrace(mcp.DefaultTraceCapacity)
+	m.traces[name] = trace
+	m.supervisor.SetTrace(name, trace)
+	return m.toast.ShowSuccess(fmt.Sprintf("Tracing \"%s\" (v to view)", name))
+}
+
+// exportTrace writes a server's trace to a JSON Lines file in the temp dir.
+func (m *Model) exportTrace(name string) tea.Cmd {
+	trace := m.traces[name]
+	if trace == nil {
+		return m.toast.ShowError("No trace to export")
+	}
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("mcpmu-trace-%s-%s.jsonl", name, time.Now().Format("20060102-150405")))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Export failed: %v", err))
+	}
+	if err := trace.Export(f); err != nil {
+		_ = f.Close()
+		return m.toast.ShowError(fmt.Sprintf("Export failed: %v", err))
+	}
+	if err := f.Close(); err != nil {
+		return m.toast.ShowError(fmt.Sprintf("Export failed: %v", err))
+	}
+	return m.toast.ShowSuccess("Trace exported to " + path)
+}
+
 func (m *Model) handleNamespaceListKey(msg tea.KeyMsg) (handled bool, model tea.Model, cmd tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keys.Enter):
-		if item := m.namespaceList.SelectedItem(); item != nil {
-			m.currentView = ViewDetail
-			m.detailNamespaceID = item.Name
-			permissions := m.cfg.GetToolPermissionsForNamespace(item.Name)
-			serverTokens := m.getServerTokensForNamespace(item.Name)
-			m.namespaceDetail.SetNamespace(item.Name, &item.Config, item.IsDefault, m.cfg.ServerEntries(), permissions, serverTokens)
-		}
+		m.openSelectedNamespace()
 		return true, m, nil
 
 	case key.Matches(msg, m.keys.Add):
@@ -1675,7 +2295,7 @@ func (m *Model) handleNamespaceDetailKey(msg tea.KeyMsg) (handled bool, model te
 		}
 		permissions := m.cfg.GetToolPermissionsForNamespace(m.detailNamespaceID)
 		serverTokens := m.getServerTokensForNamespace(m.detailNamespaceID)
-		m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, true, m.cfg.ServerEntries(), permissions, serverTokens)
+		m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, true, m.cfg.ServerEntries(), permissions, serverTokens, m.getToolCollisionsForNamespace(m.detailNamespaceID))
 		m.refreshNamespaceList()
 		return true, m, m.toast.ShowSuccess(fmt.Sprintf("Namespace \"%s\" set as default", m.detailNamespaceID))
 
@@ -1744,6 +2364,7 @@ func (m *Model) startToolPermissionEditor(nsName string, ns *config.NamespaceCon
 	// If all running servers already have tools, show editor immediately
 	if len(serversToStart) == 0 && len(serverTools) > 0 {
 		m.toolPerms.Show(nsName, serverTools, m.cfg.ServerEntries(), m.cfg.ToolPermissions, ns.DenyByDefault, ns.ServerDefaults, buildGlobalDenied(m.cfg))
+		m.toolPerms.SetServerNamespaces(m.namespacesByServer(ns.ServerIDs))
 		return true, m, nil
 	}
 
@@ -1844,10 +2465,21 @@ func (m *Model) finishPermissionDiscovery() {
 		ns.ServerDefaults,
 		buildGlobalDenied(m.cfg),
 	)
+	m.toolPerms.SetServerNamespaces(m.namespacesByServer(ns.ServerIDs))
 	m.permDiscoveryServers = nil
 	m.permDiscoveryExpected = 0
 }
 
+// namespacesByServer maps each of the given servers to the namespaces
+// containing it, for applying a tool permission across namespaces.
+func (m *Model) namespacesByServer(serverNames []string) map[string][]string {
+	result := make(map[string][]string, len(serverNames))
+	for _, name := range serverNames {
+		result[name] = m.serverNamespaces(name)
+	}
+	return result
+}
+
 // buildGlobalDenied builds a map of serverName -> denied tool names from the config.
 func buildGlobalDenied(cfg *config.Config) map[string][]string {
 	result := make(map[string][]string)
@@ -1910,7 +2542,7 @@ func (m Model) handleNamespaceFormResult(result views.NamespaceFormResult) (tea.
 		if ns, ok := m.cfg.GetNamespace(result.Name); ok {
 			permissions := m.cfg.GetToolPermissionsForNamespace(result.Name)
 			serverTokens := m.getServerTokensForNamespace(result.Name)
-			m.namespaceDetail.SetNamespace(result.Name, &ns, result.Name == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens)
+			m.namespaceDetail.SetNamespace(result.Name, &ns, result.Name == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens, m.getToolCollisionsForNamespace(result.Name))
 		}
 	}
 
@@ -1942,7 +2574,7 @@ func (m Model) handleServerPickerResult(result views.ServerPickerResult) (tea.Mo
 	// Refresh detail view
 	permissions := m.cfg.GetToolPermissionsForNamespace(m.detailNamespaceID)
 	serverTokens := m.getServerTokensForNamespace(m.detailNamespaceID)
-	m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, m.detailNamespaceID == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens)
+	m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, m.detailNamespaceID == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens, m.getToolCollisionsForNamespace(m.detailNamespaceID))
 	m.refreshNamespaceList()
 	m.refreshServerList() // Update server list badges
 
@@ -1984,6 +2616,19 @@ func (m Model) handleToolPermissionsResult(result views.ToolPermissionsResult) (
 		}
 	}
 
+	// Apply bulk changes made to other namespaces
+	for nsName, changes := range result.OtherChanges {
+		for key, enabled := range changes {
+			serverName, toolName, ok := strings.Cut(key, ":")
+			if !ok {
+				continue
+			}
+			if err := m.cfg.SetToolPermission(nsName, serverName, toolName, enabled); err != nil {
+				log.Printf("Failed to set permission: %v", err)
+			}
+		}
+	}
+
 	if err := m.saveConfig(); err != nil {
 		log.Printf("Failed to save config: %v", err)
 		return m, m.toast.ShowError(fmt.Sprintf("Failed to save: %v", err))
@@ -1993,7 +2638,7 @@ func (m Model) handleToolPermissionsResult(result views.ToolPermissionsResult) (
 	if ns, ok := m.cfg.GetNamespace(m.detailNamespaceID); ok {
 		permissions := m.cfg.GetToolPermissionsForNamespace(m.detailNamespaceID)
 		serverTokens := m.getServerTokensForNamespace(m.detailNamespaceID)
-		m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, m.detailNamespaceID == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens)
+		m.namespaceDetail.SetNamespace(m.detailNamespaceID, &ns, m.detailNamespaceID == m.cfg.DefaultNamespace, m.cfg.ServerEntries(), permissions, serverTokens, m.getToolCollisionsForNamespace(m.detailNamespaceID))
 	}
 	m.refreshNamespaceList()
 
@@ -2142,6 +2787,43 @@ func (m *Model) getServerTokensForNamespace(nsName string) map[string]int {
 	return result
 }
 
+// getToolCollisionsForNamespace finds cached tool names that more than one
+// of a namespace's enabled servers exposes. Tools the namespace denies are
+// not exposed and so can't collide.
+func (m *Model) getToolCollisionsForNamespace(nsName string) []views.ToolCollision {
+	if m.toolCache == nil {
+		return nil
+	}
+	ns, ok := m.cfg.GetNamespace(nsName)
+	if !ok {
+		return nil
+	}
+	owners := make(map[string][]string)
+	for _, serverID := range ns.ServerIDs {
+		srv, ok := m.cfg.GetServer(serverID)
+		if !ok || !srv.IsEnabled() {
+			continue
+		}
+		cachedTools, ok := m.toolCache.Get(serverID)
+		if !ok {
+			continue
+		}
+		for _, tool := range cachedTools {
+			if allowed, _ := server.IsToolAllowed(m.cfg, nsName, serverID, tool.Name); allowed {
+				owners[tool.Name] = append(owners[tool.Name], serverID)
+			}
+		}
+	}
+	var collisions []views.ToolCollision
+	for tool, servers := range owners {
+		if len(servers) > 1 {
+			collisions = append(collisions, views.ToolCollision{Tool: tool, Servers: servers})
+		}
+	}
+	slices.SortFunc(collisions, func(a, b views.ToolCollision) int { return strings.Compare(a.Tool, b.Tool) })
+	return collisions
+}
+
 // refreshNamespaceDetailIfShowing refreshes the namespace detail view if currently showing.
 func (m *Model) refreshNamespaceDetailIfShowing() {
 	if m.activeTab != TabNamespaces || m.currentView != ViewDetail || m.detailNamespaceID == "" {
@@ -2160,6 +2842,7 @@ func (m *Model) refreshNamespaceDetailIfShowing() {
 		m.cfg.ServerEntries(),
 		permissions,
 		serverTokens,
+		m.getToolCollisionsForNamespace(m.detailNamespaceID),
 	)
 }
 
@@ -2192,6 +2875,9 @@ func (m *Model) getServerToolsForDetail(serverID string) (tools []mcp.Tool, tool
 					Name:        ct.Name,
 					Description: ct.Description,
 				}
+				if len(ct.InputSchema) > 0 {
+					tools[i].InputSchema = ct.InputSchema
+				}
 				toolTokens[ct.Name] = ct.TokenCount
 			}
 			return tools, toolTokens, true
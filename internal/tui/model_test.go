@@ -1,14 +1,23 @@
 package tui
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/mcp"
 	"github.com/Bigsy/mcpmu/internal/mcptest"
 	"github.com/Bigsy/mcpmu/internal/process"
 	"github.com/Bigsy/mcpmu/internal/registry"
@@ -28,7 +37,7 @@ func newTestModel(t *testing.T) Model {
 		CredentialStoreMode: "file",
 	})
 
-	return NewModel(cfg, supervisor, bus, "", nil)
+	return NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
 }
 
 // updateModel is a helper that calls Update and returns the Model (with type assertion).
@@ -288,6 +297,722 @@ func TestModel_TestKeyInDetailStartsServer(t *testing.T) {
 	}
 }
 
+func TestModel_NoAutostart(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 80
+	m.height = 24
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	srv := fakeServerConfig(t, mcptest.DefaultConfig())
+	srv.Autostart = true
+	m.cfg.Servers["auto"] = srv
+	m.refreshServerList()
+	m.DisableAutostart()
+
+	// Init only waits for events, which blocks; run it in the background
+	go func() {
+		if cmd := m.Init(); cmd != nil {
+			if batch, ok := cmd().(tea.BatchMsg); ok {
+				for _, c := range batch {
+					go c()
+				}
+			}
+		}
+	}()
+
+	if collector.WaitForState("auto", events.StateRunning, 500*time.Millisecond) {
+		t.Fatal("expected autostart server not to start with autostart disabled")
+	}
+
+	// Autostart servers can still be started on demand
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	if cmd == nil {
+		t.Fatal("expected a start command")
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c != nil {
+			go c()
+		}
+	}
+	if !collector.WaitForState("auto", events.StateRunning, 5*time.Second) {
+		t.Fatal("expected 'A' to start the autostart server")
+	}
+}
+
+func TestModel_AutostartPriorityAndConcurrency(t *testing.T) {
+	m := newTestModel(t)
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	for name, priority := range map[string]int{"a": 0, "b": 10, "c": 5} {
+		srv := fakeServerConfig(t, mcptest.DefaultConfig())
+		srv.Autostart = true
+		srv.StartPriority = priority
+		m.cfg.Servers[name] = srv
+	}
+	m.cfg.AutostartConcurrency = 1
+
+	// The command must not block on the starts themselves
+	done := make(chan struct{})
+	go func() {
+		m.startAutostartServers(m.autostartEntries())()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("startAutostartServers blocked")
+	}
+
+	for _, name := range []string{"a", "b", "c"} {
+		if !collector.WaitForState(name, events.StateRunning, 10*time.Second) {
+			t.Fatalf("server %s did not start", name)
+		}
+	}
+
+	startedAt := make(map[string]time.Time)
+	runningAt := make(map[string]time.Time)
+	for _, e := range collector.Events() {
+		sc, ok := e.(events.StatusChangedEvent)
+		if !ok {
+			continue
+		}
+		switch sc.NewState {
+		case events.StateStarting:
+			startedAt[e.ServerID()] = e.Timestamp()
+		case events.StateRunning:
+			runningAt[e.ServerID()] = e.Timestamp()
+		}
+	}
+
+	order := []string{"b", "c", "a"}
+	for i := 1; i < len(order); i++ {
+		prev, next := order[i-1], order[i]
+		if startedAt[next].Before(runningAt[prev]) {
+			t.Errorf("%s started at %v before %s was running at %v", next, startedAt[next], prev, runningAt[prev])
+		}
+	}
+}
+
+func TestModel_GroupStartStartsAllMembers(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 80
+	m.height = 24
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	for _, name := range []string{"api", "db", "other"} {
+		m.cfg.Servers[name] = fakeServerConfig(t, mcptest.DefaultConfig())
+	}
+	m.cfg.Groups = map[string][]string{"backend": {"api", "db", "missing"}}
+	m.refreshServerList()
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if !m.groupPicker.IsVisible() {
+		t.Fatal("expected the group picker to open")
+	}
+
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a group picker result")
+	}
+	result, ok := cmd().(views.GroupPickerResult)
+	if !ok || !result.Submitted || result.Name != "backend" || result.Action != views.GroupStart {
+		t.Fatalf("unexpected picker result %+v", result)
+	}
+	_, _ = updateModel(m, result)
+
+	for _, name := range []string{"api", "db"} {
+		if !collector.WaitForState(name, events.StateRunning, 10*time.Second) {
+			t.Fatalf("group member %s did not start", name)
+		}
+	}
+	if collector.WaitForState("other", events.StateRunning, 200*time.Millisecond) {
+		t.Error("expected a server outside the group not to start")
+	}
+}
+
+func TestModel_EditConfig_ReloadsOnReturn(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 80
+	m.height = 24
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	dir := t.TempDir()
+	m.configPath = filepath.Join(dir, "config.json")
+	srv := fakeServerConfig(t, mcptest.DefaultConfig())
+	m.cfg.Servers["fs"] = srv
+	m.cfg.Servers["old"] = srv
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m.startServer("fs", srv)
+	if !collector.WaitForState("fs", events.StateRunning, 5*time.Second) {
+		t.Fatal("fs did not start")
+	}
+
+	// The stub editor replaces the config: "old" removed, "new" added, fs unchanged
+	edited := config.NewConfig()
+	edited.Servers["fs"] = srv
+	edited.Servers["new"] = srv
+	editedPath := filepath.Join(dir, "edited.json")
+	if err := config.SaveTo(edited, editedPath); err != nil {
+		t.Fatalf("save edited config: %v", err)
+	}
+	script := filepath.Join(dir, "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncp \""+editedPath+"\" \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sh "+script)
+
+	path, err := m.configFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := editorCommand(path).Run(); err != nil {
+		t.Fatalf("stub editor: %v", err)
+	}
+	collector.Clear()
+	m, _ = updateModel(m, configEditedMsg{})
+
+	if _, ok := m.cfg.GetServer("new"); !ok {
+		t.Error("expected added server after reload")
+	}
+	if _, ok := m.cfg.GetServer("old"); ok {
+		t.Error("expected removed server to be gone after reload")
+	}
+	if toast := testutil.StripANSI(m.toast.View()); !strings.Contains(toast, "Config reloaded") || !strings.Contains(toast, "removed 1 server(s): old") {
+		t.Errorf("expected reload toast naming the removed server, got %q", toast)
+	}
+	if state, ok := collector.WaitForAnyState("fs", []events.RuntimeState{events.StateStopping, events.StateStopped}, 300*time.Millisecond); ok {
+		t.Errorf("unchanged running server was disrupted: %s", state)
+	}
+
+	// A broken edit keeps the current config
+	if err := os.WriteFile(editedPath, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := editorCommand(path).Run(); err != nil {
+		t.Fatalf("stub editor: %v", err)
+	}
+	m, _ = updateModel(m, configEditedMsg{})
+	if _, ok := m.cfg.GetServer("new"); !ok {
+		t.Error("expected config to be kept after a failed reload")
+	}
+	if toast := testutil.StripANSI(m.toast.View()); !strings.Contains(toast, "Config not reloaded") {
+		t.Errorf("expected parse error toast, got %q", toast)
+	}
+}
+
+func TestModel_ExternalConfigChange_PromptsReload(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 80
+	m.height = 24
+	m.configPath = filepath.Join(t.TempDir(), "config.json")
+	m.cfg.Servers["local"] = config.ServerConfig{Kind: config.ServerKindStdio, Command: "echo"}
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	// The TUI's own save is not an external change
+	m, _ = updateModel(m, configChangedMsg{})
+	if m.confirmDlg.IsVisible() || m.configStale {
+		t.Fatal("own save should not prompt for a reload")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.watchConfigFile(ctx)
+
+	// Another process adds a server. Keep writing until the watcher, which
+	// starts asynchronously, reports it.
+	external, err := config.LoadFrom(m.configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	external.Servers["external"] = config.ServerConfig{Kind: config.ServerKindStdio, Command: "cat"}
+	deadline := time.After(5 * time.Second)
+	for seen := false; !seen; {
+		if err := config.SaveTo(external, m.configPath); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-m.configChanges:
+			seen = true
+		case <-time.After(300 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("watcher did not report the external write")
+		}
+	}
+
+	m, _ = updateModel(m, configChangedMsg{})
+	if !m.confirmDlg.IsVisible() || !strings.Contains(testutil.StripANSI(m.confirmDlg.View()), "Config changed on disk") {
+		t.Fatalf("expected a reload prompt, got %q", testutil.StripANSI(m.confirmDlg.View()))
+	}
+	if !m.configStale || !strings.Contains(testutil.StripANSI(m.renderStatusBar()), "config changed on disk") {
+		t.Error("expected the status bar to flag the stale config")
+	}
+	if err := m.saveConfig(); !errors.Is(err, errConfigChangedOnDisk) {
+		t.Errorf("saving over an external change: err = %v, want errConfigChangedOnDisk", err)
+	}
+
+	// y reloads the file
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m, _ = updateModel(m, cmd())
+	if _, ok := m.cfg.GetServer("external"); !ok {
+		t.Error("expected the external server after reloading")
+	}
+	if m.configStale {
+		t.Error("expected the stale flag to clear after reloading")
+	}
+	if err := m.saveConfig(); err != nil {
+		t.Errorf("save after reload: %v", err)
+	}
+
+	// n keeps the TUI's config, which then wins on the next save
+	delete(external.Servers, "local")
+	if err := config.SaveTo(external, m.configPath); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = updateModel(m, configChangedMsg{})
+	if !m.confirmDlg.IsVisible() {
+		t.Fatal("expected a reload prompt for the second change")
+	}
+	m, cmd = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m, _ = updateModel(m, cmd())
+	if _, ok := m.cfg.GetServer("local"); !ok {
+		t.Error("expected the TUI's config to be kept")
+	}
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("save after keeping: %v", err)
+	}
+	onDisk, err := config.LoadFrom(m.configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := onDisk.GetServer("local"); !ok {
+		t.Error("expected the kept config to be saved")
+	}
+}
+
+func TestModel_PromptOnStartEnv(t *testing.T) {
+	testutil.SetupTestHome(t)
+
+	fake := mcptest.DefaultConfig()
+	fake.StderrEnv = []string{"API_TOKEN"}
+	srv := fakeServerConfig(t, fake)
+	srv.Autostart = true
+	srv.PromptOnStart = []string{"API_TOKEN"}
+
+	cfg := config.NewConfig()
+	cfg.Servers["secret"] = srv
+	bus := events.NewBus()
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
+		CredentialStoreMode: "file",
+	})
+	m := NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
+	m.width = 80
+	m.height = 24
+
+	collector := testutil.NewEventCollector()
+	bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		supervisor.StopAll()
+	})
+
+	if !m.envPrompt.IsVisible() {
+		t.Fatal("expected the env prompt to be shown at start")
+	}
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s3cret")})
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a result from the prompt")
+	}
+	m, cmd = updateModel(m, cmd())
+	if cmd == nil {
+		t.Fatal("expected autostart after the prompt")
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c != nil {
+			go c()
+		}
+	}
+
+	if !collector.WaitForState("secret", events.StateRunning, 5*time.Second) {
+		t.Fatal("server did not start")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !slices.Contains(supervisor.Get("secret").Logs(), "API_TOKEN=s3cret") {
+		if time.Now().After(deadline) {
+			t.Fatalf("child env did not receive the prompted value; logs: %v", supervisor.Get("secret").Logs())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// The value never reaches the config, in memory or on disk
+	if _, ok := m.cfg.Servers["secret"].Env["API_TOKEN"]; ok {
+		t.Error("prompted value was stored in the config")
+	}
+	if err := m.saveConfig(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Error("prompted value was written to the config file")
+	}
+}
+
+func TestModel_DisableWithReason(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.cfg.Servers["paused"] = config.ServerConfig{Command: "echo"}
+	m.refreshServerList()
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if !m.disableReason.IsVisible() {
+		t.Fatal("expected a reason prompt when disabling")
+	}
+	if !m.cfg.Servers["paused"].IsEnabled() {
+		t.Fatal("server should stay enabled until the prompt is submitted")
+	}
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("quota exceeded")})
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a result command")
+	}
+	m, _ = updateModel(m, cmd())
+
+	srv := m.cfg.Servers["paused"]
+	if srv.IsEnabled() || srv.DisabledReason != "quota exceeded" {
+		t.Fatalf("after disable: enabled=%v reason=%q", srv.IsEnabled(), srv.DisabledReason)
+	}
+	if view := testutil.StripANSI(m.serverList.View()); !strings.Contains(view, "quota exceeded") {
+		t.Errorf("expected reason in server list, got:\n%s", view)
+	}
+
+	// Enabling needs no prompt and clears the reason
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if m.disableReason.IsVisible() {
+		t.Fatal("expected no prompt when enabling")
+	}
+	srv = m.cfg.Servers["paused"]
+	if !srv.IsEnabled() || srv.DisabledReason != "" {
+		t.Errorf("after enable: enabled=%v reason=%q, want enabled with no reason", srv.IsEnabled(), srv.DisabledReason)
+	}
+}
+
+func TestModel_DetailShowsToolSchema(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 120, Height: 200})
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+
+	serverName := "schema-server"
+	srv := fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{
+			{Name: "ping", Description: "Ping"},
+			{
+				Name:        "search_issues",
+				Description: "Search issues",
+				InputSchema: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"query": map[string]any{"type": "string", "description": "JQL query"},
+						"limit": map[string]any{"type": "integer", "minimum": 1},
+					},
+					"required": []string{"query"},
+				},
+			},
+		},
+	})
+	m.cfg.Servers[serverName] = srv
+	m.refreshServerList()
+
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if ok := collector.WaitForState(serverName, events.StateRunning, 2*time.Second); !ok {
+		t.Fatal("expected server to reach running state")
+	}
+
+	var tools []events.McpTool
+	deadline := time.Now().Add(2 * time.Second)
+	for len(tools) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		tools = collector.ToolsFor(serverName)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %v", tools)
+	}
+	m, _ = updateModel(m, events.NewToolsUpdatedEvent(serverName, tools))
+
+	// Select the second tool and expand its schema
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	view := testutil.StripANSI(m.serverDetail.View())
+	for _, want := range []string{"Input Schema: search_issues", `"query": {`, `"description": "JQL query"`, `"required": [`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected detail view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	// Toggling again collapses the schema
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if view := testutil.StripANSI(m.serverDetail.View()); strings.Contains(view, "Input Schema:") {
+		t.Errorf("expected schema to be collapsed, got:\n%s", view)
+	}
+}
+
+func TestModel_DetailShowsCachedToolSchema(t *testing.T) {
+	testutil.SetupTestHome(t)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	toolCache, err := config.NewToolCache(configPath)
+	if err != nil {
+		t.Fatalf("NewToolCache: %v", err)
+	}
+	if err := toolCache.Update("offline", []config.CachedToolInput{{
+		Name:        "lookup",
+		Description: "Look something up",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"term":{"type":"string","description":"Search term"}}}`),
+	}}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Servers["offline"] = config.ServerConfig{Command: "does-not-run"}
+	bus := events.NewBus()
+	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{CredentialStoreMode: "file"})
+	m := NewModel(cfg, supervisor, bus, configPath, toolCache)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 120, Height: 200})
+
+	// The server never starts, so its tools come from the cache
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	view := testutil.StripANSI(m.serverDetail.View())
+	for _, want := range []string{"Input Schema: lookup", `"term": {`, `"description": "Search term"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected detail view to contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_ServerDetail_Trace(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 120, Height: 60})
+	t.Setenv("TMPDIR", t.TempDir())
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+
+	serverName := "trace-server"
+	m.cfg.Servers[serverName] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools:         []mcptest.Tool{{Name: "login", Description: "Log in"}},
+		EchoToolCalls: true,
+	})
+	m.refreshServerList()
+
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Nothing to view before tracing starts
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if m.traceViewer.IsVisible() {
+		t.Fatal("expected no trace viewer before tracing starts")
+	}
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if m.supervisor.Trace(serverName) == nil {
+		t.Fatal("expected tracing to be enabled")
+	}
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if ok := collector.WaitForState(serverName, events.StateRunning, 2*time.Second); !ok {
+		t.Fatal("expected server to reach running state")
+	}
+	handle := m.supervisor.Get(serverName)
+	if handle == nil {
+		t.Fatal("expected a running handle")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := handle.WaitForTools(ctx); err != nil {
+		t.Fatalf("WaitForTools: %v", err)
+	}
+	if _, err := handle.Client().CallTool(ctx, "login", json.RawMessage(`{"password":"hunter2"}`)); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	var request, response *mcp.TraceEntry
+	entries := m.traces[serverName].Entries()
+	for i, e := range entries {
+		if e.Direction == mcp.TraceSend && strings.Contains(string(e.Frame), `"method":"tools/call"`) {
+			request = &entries[i]
+			var req struct {
+				ID int64 `json:"id"`
+			}
+			_ = json.Unmarshal(e.Frame, &req)
+			for j := i + 1; j < len(entries); j++ {
+				if entries[j].Direction == mcp.TraceRecv && strings.Contains(string(entries[j].Frame), fmt.Sprintf(`"id":%d`, req.ID)) {
+					response = &entries[j]
+					break
+				}
+			}
+		}
+	}
+	if request == nil || response == nil {
+		t.Fatalf("expected a traced tools/call request/response pair, got %d entries", len(entries))
+	}
+	if strings.Contains(string(request.Frame), "hunter2") {
+		t.Errorf("trace leaked secret argument: %s", request.Frame)
+	}
+	if !strings.Contains(string(response.Frame), "login") {
+		t.Errorf("expected echoed tool call in response, got %s", response.Frame)
+	}
+
+	// The viewer lists the captured frames
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if !m.traceViewer.IsVisible() {
+		t.Fatal("expected trace viewer to open")
+	}
+	if view := testutil.StripANSI(m.View()); !strings.Contains(view, "tools/call") {
+		t.Errorf("expected trace viewer to list tools/call, got:\n%s", view)
+	}
+
+	// Exporting writes the trace as JSON Lines
+	var cmd tea.Cmd
+	m, cmd = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd == nil {
+		t.Fatal("expected export command")
+	}
+	m, _ = updateModel(m, cmd())
+	matches, _ := filepath.Glob(filepath.Join(os.TempDir(), "mcpmu-trace-"+serverName+"-*.jsonl"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one exported trace file, got %v", matches)
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), `"arguments":{"password":"REDACTED"}`) {
+		t.Errorf("unexpected export contents:\n%s", data)
+	}
+
+	// Toggling again stops capturing but keeps the trace viewable
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if m.supervisor.Trace(serverName) != nil {
+		t.Error("expected tracing to stop")
+	}
+	if m.traces[serverName] == nil {
+		t.Error("expected stopped trace to remain viewable")
+	}
+}
+
+func TestModel_ServerDetail_ProtocolVersion(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 120, Height: 60})
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+
+	serverName := "versioned"
+	m.cfg.Servers[serverName] = fakeServerConfig(t, mcptest.FakeServerConfig{
+		Tools: []mcptest.Tool{{Name: "noop"}},
+	})
+	m.refreshServerList()
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if ok := collector.WaitForState(serverName, events.StateRunning, 2*time.Second); !ok {
+		t.Fatal("expected server to reach running state")
+	}
+
+	var running events.StatusChangedEvent
+	for _, e := range collector.Events() {
+		if sc, ok := e.(events.StatusChangedEvent); ok && sc.ServerID() == serverName && sc.NewState == events.StateRunning {
+			running = sc
+		}
+	}
+	m, _ = updateModel(m, running)
+
+	// The fake server answers initialize with 2024-11-05 whatever we ask for.
+	view := testutil.StripANSI(m.serverDetail.View())
+	if !strings.Contains(view, "Protocol: 2024-11-05") {
+		t.Errorf("expected negotiated protocol version in detail view, got:\n%s", view)
+	}
+}
+
+func TestModel_ZeroToolsServerShowsWarning(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 120
+	m.height = 24
+
+	collector := testutil.NewEventCollector()
+	m.bus.Subscribe(collector.Handler)
+	t.Cleanup(func() {
+		m.supervisor.StopAll()
+	})
+
+	if _, err := m.supervisor.Start(context.Background(), "empty", fakeServerConfig(t, mcptest.FakeServerConfig{})); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	var warning events.ErrorEvent
+	deadline := time.Now().Add(5 * time.Second)
+	for warning.Message == "" && time.Now().Before(deadline) {
+		for _, e := range collector.Events() {
+			if ee, ok := e.(events.ErrorEvent); ok && ee.ServerID() == "empty" {
+				warning = ee
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !warning.Warning {
+		t.Fatalf("expected a zero-tools warning event, got %+v", warning)
+	}
+
+	m, _ = updateModel(m, warning)
+	toast := testutil.StripANSI(m.toast.View())
+	if !strings.Contains(toast, "⚠") || !strings.Contains(toast, "reported no tools") {
+		t.Errorf("expected a warning toast, got %q", toast)
+	}
+}
+
 func fakeServerConfig(t *testing.T, fakeCfg mcptest.FakeServerConfig) config.ServerConfig {
 	t.Helper()
 
@@ -445,6 +1170,90 @@ func TestModel_NamespaceDetail_ServerPicker(t *testing.T) {
 	}
 }
 
+func TestModel_FocusNamespace(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.cfg.AddNamespace("alpha", config.NamespaceConfig{})
+	_ = m.cfg.AddNamespace("beta", config.NamespaceConfig{})
+	m.refreshNamespaceList()
+
+	m.FocusNamespace("missing")
+	if m.activeTab != TabServers {
+		t.Errorf("expected unknown namespace to leave the Servers tab active, got %v", m.activeTab)
+	}
+
+	m.FocusNamespace("beta")
+	if m.activeTab != TabNamespaces {
+		t.Fatalf("expected Namespaces tab, got %v", m.activeTab)
+	}
+	if item := m.namespaceList.SelectedItem(); item == nil || item.Name != "beta" {
+		t.Errorf("expected beta selected, got %+v", item)
+	}
+}
+
+func TestModel_ServerDetail_JumpToNamespace(t *testing.T) {
+	m := newTestModel(t)
+	m.width = 80
+	m.height = 24
+
+	_ = m.cfg.AddServer("alpha", config.ServerConfig{Kind: config.ServerKindStdio, Command: "alpha"})
+	_ = m.cfg.AddServer("beta", config.ServerConfig{Kind: config.ServerKindStdio, Command: "beta"})
+	_ = m.cfg.AddNamespace("dev", config.NamespaceConfig{ServerIDs: []string{"alpha", "beta"}})
+	_ = m.cfg.AddNamespace("other", config.NamespaceConfig{ServerIDs: []string{"beta"}})
+	_ = m.cfg.AddNamespace("prod", config.NamespaceConfig{ServerIDs: []string{"alpha"}})
+	m.refreshServerList()
+	m.refreshNamespaceList()
+
+	// Open alpha's detail view
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != ViewDetail || m.detailServerID != "alpha" {
+		t.Fatalf("expected alpha detail view, got view=%v server=%q", m.currentView, m.detailServerID)
+	}
+
+	// 'n' lists alpha's namespaces; pick the second (prod)
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if !m.nsPicker.IsVisible() {
+		t.Fatal("expected namespace picker to be visible")
+	}
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.nsPicker.IsVisible() {
+		t.Error("expected namespace picker to close after Enter")
+	}
+	if cmd == nil {
+		t.Fatal("expected a picker result command")
+	}
+	m, _ = updateModel(m, cmd())
+
+	if m.activeTab != TabNamespaces || m.currentView != ViewDetail {
+		t.Fatalf("expected namespace detail view, got tab=%v view=%v", m.activeTab, m.currentView)
+	}
+	if m.detailNamespaceID != "prod" {
+		t.Errorf("detailNamespaceID = %q, want prod", m.detailNamespaceID)
+	}
+	if item := m.namespaceList.SelectedItem(); item == nil || item.Name != "prod" {
+		t.Errorf("expected prod selected in namespace list, got %+v", item)
+	}
+}
+
+func TestModel_ServerDetail_JumpToNamespace_NoNamespaces(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.cfg.AddServer("lonely", config.ServerConfig{Kind: config.ServerKindStdio, Command: "lonely"})
+	m.refreshServerList()
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+	if m.nsPicker.IsVisible() {
+		t.Error("expected no picker for a server in zero namespaces")
+	}
+	if !m.toast.IsVisible() {
+		t.Error("expected an info toast")
+	}
+	if m.activeTab != TabServers || m.currentView != ViewDetail {
+		t.Errorf("expected to stay on server detail, got tab=%v view=%v", m.activeTab, m.currentView)
+	}
+}
+
 func TestModel_RefreshServerList_IncludesNamespaces(t *testing.T) {
 	m := newTestModel(t)
 
@@ -705,7 +1514,7 @@ func newTestModelWithCredStore(t *testing.T) Model {
 		CredentialStoreMode: "file",
 	})
 
-	m := NewModel(cfg, supervisor, bus, "", nil)
+	m := NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
 	m.width = 80
 	m.height = 24
 	return m
@@ -741,6 +1550,83 @@ func TestModel_LoginKey_OAuthHTTPServer_NeedsAuth(t *testing.T) {
 	}
 }
 
+func TestModel_ReachabilityAnnotatesHTTPServers(t *testing.T) {
+	m := newTestModel(t)
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer up.Close()
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer auth.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	_ = m.cfg.AddServer("up", config.ServerConfig{Kind: config.ServerKindStreamableHTTP, URL: up.URL})
+	_ = m.cfg.AddServer("auth", config.ServerConfig{Kind: config.ServerKindStreamableHTTP, URL: auth.URL})
+	_ = m.cfg.AddServer("down", config.ServerConfig{Kind: config.ServerKindStreamableHTTP, URL: down.URL})
+	m.refreshServerList()
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 160, Height: 50})
+
+	// Off by default: nothing is probed or shown
+	if strings.Contains(testutil.StripANSI(m.View()), "[reachable]") {
+		t.Fatal("expected no reachability badges before toggling checks on")
+	}
+
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if !m.reachEnabled {
+		t.Fatal("expected P to enable reachability checks")
+	}
+	m, cmd := updateModel(m, m.checkReachability()())
+	if cmd == nil {
+		t.Error("expected the next round of checks to be scheduled")
+	}
+
+	want := map[string]views.Reachability{
+		"up":   views.ReachOK,
+		"auth": views.ReachNeedsAuth,
+		"down": views.ReachUnreachable,
+	}
+	for name, reach := range want {
+		if got := m.reachability[name]; got != reach {
+			t.Errorf("%s: reachability = %q, want %q", name, got, reach)
+		}
+	}
+	view := testutil.StripANSI(m.View())
+	for _, badge := range []string{"[reachable]", "[needs-auth]", "[unreachable]"} {
+		if !strings.Contains(view, badge) {
+			t.Errorf("expected %s badge in view", badge)
+		}
+	}
+
+	// Toggling off clears the annotations and ignores in-flight results
+	stale := m.checkReachability()()
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m, _ = updateModel(m, stale)
+	if len(m.reachability) != 0 {
+		t.Errorf("expected reachability cleared after toggling off, got %v", m.reachability)
+	}
+}
+
+func TestProbeReachability_UsesServerTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// The self-signed certificate is rejected unless the server's tls
+	// settings allow it, as they would be for a real connection
+	plain := config.ServerConfig{Kind: config.ServerKindStreamableHTTP, URL: srv.URL}
+	if got := probeReachability(context.Background(), plain); got != views.ReachUnreachable {
+		t.Errorf("without tls settings: reachability = %q, want %q", got, views.ReachUnreachable)
+	}
+	insecure := plain
+	insecure.TLS = &config.TLSConfig{InsecureSkipVerify: true}
+	if got := probeReachability(context.Background(), insecure); got != views.ReachOK {
+		t.Errorf("with tls.insecure_skip_verify: reachability = %q, want %q", got, views.ReachOK)
+	}
+}
+
 func TestModel_LoginKey_OAuthHTTPServer_NotNeedsAuth(t *testing.T) {
 	m := newTestModelWithCredStore(t)
 
@@ -1398,6 +2284,60 @@ func TestModel_PermissionEditor_WithServerDefaults(t *testing.T) {
 	}
 }
 
+func TestModel_PermissionEditor_BulkAcrossNamespaces(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = updateModel(m, tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	srv := config.ServerConfig{Kind: config.ServerKindStdio, Command: "test"}
+	_ = m.cfg.AddServer("srv1", srv)
+	for _, name := range []string{"ns-a", "ns-b", "ns-c"} {
+		_ = m.cfg.AddNamespace(name, config.NamespaceConfig{ServerIDs: []string{"srv1"}})
+	}
+
+	m.detailNamespaceID = "ns-a"
+	m.serverStatuses = map[string]events.ServerStatus{"srv1": {State: events.StateRunning}}
+	m.serverTools = map[string][]events.McpTool{
+		"srv1": {{Name: "delete_file", Description: "Delete a file"}},
+	}
+
+	ns, _ := m.cfg.GetNamespace("ns-a")
+	handled, _, _ := m.startToolPermissionEditor("ns-a", &ns)
+	if !handled || !m.toolPerms.IsVisible() {
+		t.Fatal("expected tool permissions editor to be visible")
+	}
+
+	// Select delete_file (below the server header) and open bulk mode
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	overlay := testutil.StripANSI(m.toolPerms.RenderOverlay("base", 100, 40))
+	for _, want := range []string{"ns-a (editing)", "ns-b", "ns-c"} {
+		if !strings.Contains(overlay, want) {
+			t.Fatalf("expected bulk overlay to list %q, got:\n%s", want, overlay)
+		}
+	}
+
+	// ns-a is preselected; add ns-b and deny the tool in both
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+
+	m, cmd := updateModel(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected submit command")
+	}
+	m, _ = updateModel(m, cmd())
+
+	for _, nsName := range []string{"ns-a", "ns-b"} {
+		enabled, ok := m.cfg.GetToolPermission(nsName, "srv1", "delete_file")
+		if !ok || enabled {
+			t.Errorf("expected delete_file denied in %s, got enabled=%v explicit=%v", nsName, enabled, ok)
+		}
+	}
+	if _, ok := m.cfg.GetToolPermission("ns-c", "srv1", "delete_file"); ok {
+		t.Error("expected ns-c to be left unchanged")
+	}
+}
+
 func TestFormatEnvMap(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1420,3 +2360,84 @@ func TestFormatEnvMap(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_NamespaceDetail_ToolNameCollisions(t *testing.T) {
+	m := newTestModelWithToolCache(t)
+
+	disabled := false
+	_ = m.cfg.AddServer("alpha", config.ServerConfig{Kind: config.ServerKindStdio, Command: "echo"})
+	_ = m.cfg.AddServer("beta", config.ServerConfig{Kind: config.ServerKindStdio, Command: "echo"})
+	_ = m.cfg.AddServer("gamma", config.ServerConfig{Kind: config.ServerKindStdio, Command: "echo", Enabled: &disabled})
+	_ = m.cfg.AddNamespace("work", config.NamespaceConfig{ServerIDs: []string{"alpha", "beta", "gamma"}})
+	// A denied tool isn't exposed, so it can't collide
+	_ = m.cfg.SetToolPermission("work", "beta", "fetch", false)
+
+	_ = m.toolCache.Update("alpha", []config.CachedToolInput{{Name: "search"}, {Name: "fetch"}, {Name: "only_alpha"}})
+	_ = m.toolCache.Update("beta", []config.CachedToolInput{{Name: "search"}, {Name: "fetch"}})
+	_ = m.toolCache.Update("gamma", []config.CachedToolInput{{Name: "only_alpha"}})
+
+	collisions := m.getToolCollisionsForNamespace("work")
+	want := []views.ToolCollision{{Tool: "search", Servers: []string{"alpha", "beta"}}}
+	if !reflect.DeepEqual(collisions, want) {
+		t.Fatalf("collisions = %+v, want %+v", collisions, want)
+	}
+
+	ns, _ := m.cfg.GetNamespace("work")
+	m.namespaceDetail.SetSize(100, 40)
+	m.namespaceDetail.SetNamespace("work", &ns, false, m.cfg.ServerEntries(), nil, nil, collisions)
+	view := m.namespaceDetail.View()
+	for _, s := range []string{"1 tool name collision", "Tool Name Collisions (1)", "search in alpha, beta"} {
+		if !strings.Contains(view, s) {
+			t.Errorf("expected %q in namespace detail, got:\n%s", s, view)
+		}
+	}
+}
+
+func TestModel_NeedsAuthCountAndJump(t *testing.T) {
+	m := newTestModelWithCredStore(t)
+	m.width = 120
+
+	for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+		_ = m.cfg.AddServer(name, config.ServerConfig{Kind: config.ServerKindStreamableHTTP, URL: "https://" + name + ".example.com/mcp"})
+	}
+	m.serverStatuses["beta"] = events.ServerStatus{State: events.StateNeedsAuth}
+	m.serverStatuses["delta"] = events.ServerStatus{State: events.StateNeedsAuth}
+	m.serverStatuses["gamma"] = events.ServerStatus{State: events.StateRunning}
+	m.refreshServerList()
+
+	if bar := testutil.StripANSI(m.renderStatusBar()); !strings.Contains(bar, "2 need login") {
+		t.Errorf("expected the status bar to count 2 servers needing login, got: %s", bar)
+	}
+
+	// Start elsewhere: N switches back to the server list
+	m.switchToTab(TabNamespaces)
+	press := func() string {
+		t.Helper()
+		m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+		if m.activeTab != TabServers || m.currentView != ViewList {
+			t.Fatalf("expected N to show the server list, got tab %v view %v", m.activeTab, m.currentView)
+		}
+		return m.serverList.SelectedItem().Name
+	}
+	if got := press(); got != "beta" {
+		t.Errorf("first N selected %q, want beta", got)
+	}
+	if got := press(); got != "delta" {
+		t.Errorf("second N selected %q, want delta", got)
+	}
+	if got := press(); got != "beta" {
+		t.Errorf("third N selected %q, want beta (wrapping around)", got)
+	}
+
+	// Once logged in, the count drops and N has nowhere to go
+	m.serverStatuses["beta"] = events.ServerStatus{State: events.StateRunning}
+	m.serverStatuses["delta"] = events.ServerStatus{State: events.StateRunning}
+	m.refreshServerList()
+	if bar := testutil.StripANSI(m.renderStatusBar()); strings.Contains(bar, "need login") {
+		t.Errorf("expected no login count, got: %s", bar)
+	}
+	m, _ = updateModel(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	if !strings.Contains(testutil.StripANSI(m.View()), "No servers need login") {
+		t.Error("expected a toast when no server needs login")
+	}
+}
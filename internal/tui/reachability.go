@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/tui/views"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	reachabilityInterval = 30 * time.Second
+	reachabilityTimeout  = 3 * time.Second
+)
+
+// reachabilityTickMsg triggers the next round of HTTP reachability probes.
+// gen ties it to the toggle that scheduled it, so a stale loop from an
+// earlier toggle dies out instead of running alongside the new one.
+type reachabilityTickMsg struct{ gen int }
+
+// reachabilityResultMsg carries the results of one round of probes.
+type reachabilityResultMsg struct {
+	gen     int
+	results map[string]views.Reachability
+}
+
+// toggleReachability turns background reachability checks for HTTP servers
+// on or off. They're off by default so the TUI makes no network requests for
+// servers the user hasn't started.
+func (m *Model) toggleReachability() tea.Cmd {
+	m.reachEnabled = !m.reachEnabled
+	m.reachGen++
+	if !m.reachEnabled {
+		m.reachability = make(map[string]views.Reachability)
+		m.refreshServerList()
+		return m.toast.ShowInfo("HTTP reachability checks off")
+	}
+	return tea.Batch(
+		m.checkReachability(),
+		m.toast.ShowInfo("HTTP reachability checks on"),
+	)
+}
+
+// checkReachability probes every enabled HTTP server that isn't running.
+// Running servers already report their real status.
+func (m *Model) checkReachability() tea.Cmd {
+	gen := m.reachGen
+	targets := make(map[string]config.ServerConfig)
+	for _, entry := range m.cfg.ServerEntries() {
+		if !entry.Config.IsHTTP() || !entry.Config.IsEnabled() {
+			continue
+		}
+		if m.serverStatuses[entry.Name].State == events.StateRunning {
+			continue
+		}
+		targets[entry.Name] = entry.Config
+	}
+
+	return func() tea.Msg {
+		results := make(map[string]views.Reachability, len(targets))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for name, srv := range targets {
+			wg.Go(func() {
+				reach := probeReachability(context.Background(), srv)
+				mu.Lock()
+				results[name] = reach
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+		return reachabilityResultMsg{gen: gen, results: results}
+	}
+}
+
+// handleReachabilityResult records a round of probe results and schedules the
+// next round, unless checks were toggled since the round started.
+func (m *Model) handleReachabilityResult(msg reachabilityResultMsg) tea.Cmd {
+	if !m.reachEnabled || msg.gen != m.reachGen {
+		return nil
+	}
+	for name, reach := range msg.results {
+		m.reachability[name] = reach
+	}
+	m.refreshServerList()
+
+	gen := m.reachGen
+	return tea.Tick(reachabilityInterval, func(time.Time) tea.Msg {
+		return reachabilityTickMsg{gen: gen}
+	})
+}
+
+// probeReachability sends a HEAD request to an HTTP server's URL with its
+// configured headers, TLS and proxy settings. Any response means the server
+// is reachable; 401 and 403 mean it wants credentials we don't have.
+func probeReachability(ctx context.Context, srv config.ServerConfig) views.Reachability {
+	transport, err := process.HTTPTransport(srv)
+	if err != nil {
+		return views.ReachUnreachable
+	}
+	client := &http.Client{Transport: transport, Timeout: reachabilityTimeout}
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, srv.URL, nil)
+	if err != nil {
+		return views.ReachUnreachable
+	}
+	for name, value := range srv.HTTPHeaders {
+		req.Header.Set(name, value)
+	}
+	for name, envVar := range srv.EnvHTTPHeaders {
+		if value := os.Getenv(envVar); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+	if srv.BearerTokenEnvVar != "" {
+		if token, err := mcp.ValidateBearerTokenEnvVar(srv.BearerTokenEnvVar); err == nil {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return views.ReachUnreachable
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return views.ReachNeedsAuth
+	default:
+		return views.ReachOK
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -22,7 +23,7 @@ func newTestModelWithSize(t *testing.T, width, height int) Model {
 		CredentialStoreMode: "file",
 	})
 
-	m := NewModel(cfg, supervisor, bus, "", nil)
+	m := NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
 	return newModel.(Model)
 }
@@ -35,7 +36,7 @@ func TestView_Loading(t *testing.T) {
 	supervisor := process.NewSupervisorWithOptions(bus, process.SupervisorOptions{
 		CredentialStoreMode: "file",
 	})
-	m := NewModel(cfg, supervisor, bus, "", nil)
+	m := NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
 
 	// Before WindowSizeMsg, width/height are 0
 	view := m.View()
@@ -154,7 +155,7 @@ func TestView_WithServers(t *testing.T) {
 		CredentialStoreMode: "file",
 	})
 
-	m := NewModel(cfg, supervisor, bus, "", nil)
+	m := NewModel(cfg, supervisor, bus, filepath.Join(t.TempDir(), "config.json"), nil)
 	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
 	m = newModel.(Model)
 
@@ -0,0 +1,134 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DisableReasonResult is sent when the disable prompt closes. Submitted is
+// false if the user cancelled and the server should stay enabled.
+type DisableReasonResult struct {
+	ServerName string
+	Reason     string
+	Submitted  bool
+}
+
+// DisableReasonModel asks for an optional note on why a server is being
+// disabled.
+type DisableReasonModel struct {
+	theme      theme.Theme
+	visible    bool
+	serverName string
+	input      textinput.Model
+	width      int
+	height     int
+
+	enterKey key.Binding
+	escKey   key.Binding
+}
+
+// NewDisableReason creates a new disable reason prompt.
+func NewDisableReason(th theme.Theme) DisableReasonModel {
+	ti := textinput.New()
+	ti.Placeholder = "optional"
+	ti.CharLimit = 200
+
+	return DisableReasonModel{
+		theme: th,
+		input: ti,
+		enterKey: key.NewBinding(
+			key.WithKeys("enter"),
+		),
+		escKey: key.NewBinding(
+			key.WithKeys("esc"),
+		),
+	}
+}
+
+// Show displays the prompt for a server.
+func (m *DisableReasonModel) Show(serverName string) tea.Cmd {
+	m.visible = true
+	m.serverName = serverName
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+// Hide hides the prompt.
+func (m *DisableReasonModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the prompt is visible.
+func (m DisableReasonModel) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the available dimensions for centering.
+func (m *DisableReasonModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles key events for the prompt.
+func (m DisableReasonModel) Update(msg tea.Msg) (DisableReasonModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.enterKey):
+			m.Hide()
+			result := DisableReasonResult{ServerName: m.serverName, Reason: m.input.Value(), Submitted: true}
+			return m, func() tea.Msg { return result }
+		case key.Matches(msg, m.escKey):
+			m.Hide()
+			name := m.serverName
+			return m, func() tea.Msg { return DisableReasonResult{ServerName: name} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// RenderOverlay renders the prompt as a centered overlay on top of the base content.
+func (m DisableReasonModel) RenderOverlay(base string, width, height int) string {
+	if !m.visible {
+		return base
+	}
+
+	dialogWidth := 50
+	if width > 0 && width < 60 {
+		dialogWidth = width - 10
+	}
+	m.input.Width = dialogWidth - 8
+
+	content := m.theme.Warn.Bold(true).Render(fmt.Sprintf("Disable %q", m.serverName)) + "\n\n" +
+		"Reason:\n" + m.input.View() + "\n\n" +
+		m.theme.Muted.Render("enter disable  esc cancel")
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Warn.GetForeground()).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+	)
+}
@@ -0,0 +1,160 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EnvPromptItem is one env key the prompt asks a value for.
+type EnvPromptItem struct {
+	Server string
+	Key    string
+}
+
+// EnvPromptResult is sent when every key has been answered or skipped.
+// Values maps server name to the env values entered for it; skipped keys
+// are absent.
+type EnvPromptResult struct {
+	Values map[string]map[string]string
+}
+
+// EnvPromptModel asks, one at a time, for the values of env keys marked
+// promptOnStart. Input is masked.
+type EnvPromptModel struct {
+	theme   theme.Theme
+	visible bool
+	items   []EnvPromptItem
+	current int
+	values  map[string]map[string]string
+	input   textinput.Model
+	width   int
+	height  int
+
+	enterKey key.Binding
+	escKey   key.Binding
+}
+
+// NewEnvPrompt creates a new env prompt.
+func NewEnvPrompt(th theme.Theme) EnvPromptModel {
+	ti := textinput.New()
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+
+	return EnvPromptModel{
+		theme: th,
+		input: ti,
+		enterKey: key.NewBinding(
+			key.WithKeys("enter"),
+		),
+		escKey: key.NewBinding(
+			key.WithKeys("esc"),
+		),
+	}
+}
+
+// Show displays the prompt for the given keys.
+func (m *EnvPromptModel) Show(items []EnvPromptItem) tea.Cmd {
+	m.visible = true
+	m.items = items
+	m.current = 0
+	m.values = make(map[string]map[string]string)
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+// Hide hides the prompt.
+func (m *EnvPromptModel) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns whether the prompt is visible.
+func (m EnvPromptModel) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the available dimensions for centering.
+func (m *EnvPromptModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles key events for the prompt.
+func (m EnvPromptModel) Update(msg tea.Msg) (EnvPromptModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.enterKey):
+			if value := m.input.Value(); value != "" {
+				item := m.items[m.current]
+				if m.values[item.Server] == nil {
+					m.values[item.Server] = make(map[string]string)
+				}
+				m.values[item.Server][item.Key] = value
+			}
+			return m.next()
+		case key.Matches(msg, m.escKey):
+			return m.next()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// next moves to the following key, or closes the prompt after the last one.
+func (m EnvPromptModel) next() (EnvPromptModel, tea.Cmd) {
+	m.input.SetValue("")
+	m.current++
+	if m.current < len(m.items) {
+		return m, nil
+	}
+	m.Hide()
+	result := EnvPromptResult{Values: m.values}
+	return m, func() tea.Msg { return result }
+}
+
+// RenderOverlay renders the prompt as a centered overlay on top of the base content.
+func (m EnvPromptModel) RenderOverlay(base string, width, height int) string {
+	if !m.visible || m.current >= len(m.items) {
+		return base
+	}
+
+	dialogWidth := 50
+	if width > 0 && width < 60 {
+		dialogWidth = width - 10
+	}
+	m.input.Width = dialogWidth - 8
+
+	item := m.items[m.current]
+	content := m.theme.Title.Render("Session secrets") + "\n\n" +
+		m.theme.Muted.Render(fmt.Sprintf("%d of %d · kept in memory only", m.current+1, len(m.items))) + "\n\n" +
+		fmt.Sprintf("%s for %q:\n", m.theme.Primary.Render(item.Key), item.Server) + m.input.View() + "\n\n" +
+		m.theme.Muted.Render("enter save  esc skip")
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary.GetForeground()).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+	)
+}
@@ -0,0 +1,188 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GroupAction is what to do with every server in a group.
+type GroupAction string
+
+const (
+	GroupStart   GroupAction = "start"
+	GroupStop    GroupAction = "stop"
+	GroupRestart GroupAction = "restart"
+)
+
+// GroupItem is one server group shown in the picker.
+type GroupItem struct {
+	Name    string
+	Members []string
+}
+
+// GroupPickerResult is sent when the user runs an action on a group or
+// cancels.
+type GroupPickerResult struct {
+	Name      string
+	Action    GroupAction
+	Submitted bool
+}
+
+// GroupPickerModel is an overlay listing the configured server groups. The
+// selected group can be started, stopped or restarted as a unit.
+type GroupPickerModel struct {
+	theme    theme.Theme
+	visible  bool
+	groups   []GroupItem
+	selected int
+	width    int
+	height   int
+
+	upKey      key.Binding
+	downKey    key.Binding
+	startKey   key.Binding
+	stopKey    key.Binding
+	restartKey key.Binding
+	escKey     key.Binding
+}
+
+// NewGroupPicker creates a new group picker.
+func NewGroupPicker(th theme.Theme) GroupPickerModel {
+	return GroupPickerModel{
+		theme: th,
+		upKey: key.NewBinding(
+			key.WithKeys("up", "k"),
+		),
+		downKey: key.NewBinding(
+			key.WithKeys("down", "j"),
+		),
+		startKey: key.NewBinding(
+			key.WithKeys("enter", "s"),
+		),
+		stopKey: key.NewBinding(
+			key.WithKeys("x"),
+		),
+		restartKey: key.NewBinding(
+			key.WithKeys("r"),
+		),
+		escKey: key.NewBinding(
+			key.WithKeys("esc"),
+		),
+	}
+}
+
+// Show displays the picker with the given groups.
+func (m *GroupPickerModel) Show(groups []GroupItem) {
+	m.visible = true
+	m.groups = groups
+	m.selected = 0
+}
+
+// Hide hides the group picker.
+func (m *GroupPickerModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the picker is visible.
+func (m GroupPickerModel) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the available dimensions for centering.
+func (m *GroupPickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles key events for the group picker.
+func (m GroupPickerModel) Update(msg tea.Msg) (GroupPickerModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.upKey):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(keyMsg, m.downKey):
+		if m.selected < len(m.groups)-1 {
+			m.selected++
+		}
+	case key.Matches(keyMsg, m.startKey):
+		return m.submit(GroupStart)
+	case key.Matches(keyMsg, m.stopKey):
+		return m.submit(GroupStop)
+	case key.Matches(keyMsg, m.restartKey):
+		return m.submit(GroupRestart)
+	case key.Matches(keyMsg, m.escKey):
+		m.visible = false
+		return m, func() tea.Msg {
+			return GroupPickerResult{Submitted: false}
+		}
+	}
+
+	return m, nil
+}
+
+// submit closes the picker and reports action on the selected group.
+func (m GroupPickerModel) submit(action GroupAction) (GroupPickerModel, tea.Cmd) {
+	m.visible = false
+	if len(m.groups) == 0 {
+		return m, func() tea.Msg { return GroupPickerResult{} }
+	}
+	name := m.groups[m.selected].Name
+	return m, func() tea.Msg {
+		return GroupPickerResult{Name: name, Action: action, Submitted: true}
+	}
+}
+
+// RenderOverlay renders the picker as a centered overlay on top of the base content.
+func (m GroupPickerModel) RenderOverlay(base string, width, height int) string {
+	if !m.visible {
+		return base
+	}
+
+	title := m.theme.Title.Render("Server groups")
+
+	var options strings.Builder
+	for i, group := range m.groups {
+		members := m.theme.Faint.Render(fmt.Sprintf(" (%s)", strings.Join(group.Members, ", ")))
+		if i == m.selected {
+			options.WriteString("  " + m.theme.Primary.Render("▸") + " " + m.theme.Primary.Bold(true).Render(group.Name) + members + "\n")
+		} else {
+			options.WriteString("    " + m.theme.Base.Render(group.Name) + members + "\n")
+		}
+	}
+
+	footer := m.theme.Faint.Render("↑↓ select  enter/s start  x stop  r restart  esc ×")
+
+	content := title + "\n\n" + options.String() + "\n" + footer
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary.GetForeground()).
+		Padding(1, 2).
+		Width(60).
+		Render(content)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+	)
+}
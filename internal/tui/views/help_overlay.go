@@ -103,6 +103,12 @@ func (m HelpOverlayModel) helpContent() string {
 			{"d", "Delete server"},
 			{"L", "OAuth login (HTTP servers)"},
 			{"O", "OAuth logout (HTTP servers)"},
+			{"P", "Toggle reachability checks (HTTP servers)"},
+			{"A", "Start all autostart servers"},
+			{"o", "Start/stop/restart a server group"},
+			{"n", "Jump to a namespace with this server (detail)"},
+			{"R", "Toggle JSON-RPC tracing (detail)"},
+			{"v", "View/export JSON-RPC trace (detail)"},
 		}),
 		m.renderSection("Logs", [][]string{
 			{"l", "Toggle log panel"},
@@ -116,6 +122,7 @@ func (m HelpOverlayModel) helpContent() string {
 			{"3", "Proxies tab (planned)"},
 		}),
 		m.renderSection("General", [][]string{
+			{"Ctrl+E", "Edit config file in $EDITOR"},
 			{"?", "Toggle this help"},
 			{"q", "Quit"},
 			{"Ctrl+C", "Force quit"},
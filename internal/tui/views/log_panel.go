@@ -2,19 +2,99 @@ package views
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/Bigsy/mcpmu/internal/tui/theme"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// ansiEscape matches ANSI escape sequences: CSI sequences such as colors and
+// cursor movement, and OSC sequences such as terminal titles and hyperlinks.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// stripANSI removes ANSI escape sequences from s.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// LogLevel is the severity detected in a log line.
+type LogLevel int
+
+const (
+	LogLevelUnknown LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level's name as shown in the panel title.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return "ALL"
+	}
+}
+
+// defaultLogLevelPattern finds a level word at the start of a line, after up
+// to two timestamp-like fields and an optional bracket ("[WARN]",
+// "2024-05-01 12:00:00 INFO", "ERROR:"), or in a logfmt level= field.
+var defaultLogLevelPattern = regexp.MustCompile(`(?i)(?:^\W{0,2}(?:[\d\-:T.,Z+/]+\s+){0,2}[\[(<]?|\blevel=["']?)(trace|debug|info|notice|warn(?:ing)?|error|err|fatal|critical|panic)\b`)
+
+// logLevelWords maps level words, lowercased, to levels.
+var logLevelWords = map[string]LogLevel{
+	"trace":    LogLevelDebug,
+	"debug":    LogLevelDebug,
+	"info":     LogLevelInfo,
+	"notice":   LogLevelInfo,
+	"warn":     LogLevelWarn,
+	"warning":  LogLevelWarn,
+	"error":    LogLevelError,
+	"err":      LogLevelError,
+	"fatal":    LogLevelError,
+	"critical": LogLevelError,
+	"panic":    LogLevelError,
+}
+
+// detectLogLevel finds the level in line using pattern, reading the group
+// named "level" or else the first capture group. Lines with no match, or
+// whose level word isn't recognized, are LogLevelUnknown.
+func detectLogLevel(pattern *regexp.Regexp, line string) LogLevel {
+	match := pattern.FindStringSubmatch(line)
+	if match == nil {
+		return LogLevelUnknown
+	}
+	group := 1
+	if i := pattern.SubexpIndex("level"); i > 0 {
+		group = i
+	}
+	if group >= len(match) {
+		return LogLevelUnknown
+	}
+	return logLevelWords[strings.ToLower(strings.TrimSpace(match[group]))]
+}
+
 // LogEntry represents a single log line.
 type LogEntry struct {
 	ServerID  string
 	Line      string
 	Timestamp time.Time
+	Level     LogLevel
 }
 
 // LogPanelModel displays server logs.
@@ -24,11 +104,16 @@ type LogPanelModel struct {
 	entries  []LogEntry
 	follow   bool
 	wrap     bool
+	raw      bool // render lines with their ANSI escapes instead of stripped
+	minLevel LogLevel
 	visible  bool
 	width    int
 	height   int
 	topPad   int
 	focused  bool
+
+	// levelPatterns overrides defaultLogLevelPattern per server.
+	levelPatterns map[string]*regexp.Regexp
 }
 
 // NewLogPanel creates a new log panel.
@@ -104,12 +189,69 @@ func (m LogPanelModel) IsWrapping() bool {
 	return m.wrap
 }
 
+// ToggleRaw switches between plain lines, with ANSI escapes stripped (the
+// default), and raw lines that keep a server's colors. Raw lines can throw
+// off wrapping and styling.
+func (m *LogPanelModel) ToggleRaw() {
+	m.raw = !m.raw
+	m.updateContent()
+}
+
+// IsRaw returns whether raw mode is active.
+func (m LogPanelModel) IsRaw() bool {
+	return m.raw
+}
+
+// CycleMinLevel steps the minimum level shown through all, INFO, WARN and
+// ERROR. Lines with no detected level are always shown, so stack traces and
+// other continuation lines aren't lost.
+func (m *LogPanelModel) CycleMinLevel() {
+	switch m.minLevel {
+	case LogLevelUnknown:
+		m.minLevel = LogLevelInfo
+	case LogLevelError:
+		m.minLevel = LogLevelUnknown
+	default:
+		m.minLevel++
+	}
+	m.updateContent()
+	if m.follow {
+		m.viewport.GotoBottom()
+	}
+}
+
+// MinLevel returns the minimum level shown; LogLevelUnknown shows all.
+func (m LogPanelModel) MinLevel() LogLevel {
+	return m.minLevel
+}
+
+// SetLevelPatterns sets the per-server patterns used to detect levels,
+// keyed by server name. Servers without one use the built-in pattern.
+// Lines already in the panel are re-read with the new patterns.
+func (m *LogPanelModel) SetLevelPatterns(patterns map[string]*regexp.Regexp) {
+	m.levelPatterns = patterns
+	for i := range m.entries {
+		m.entries[i].Level = m.detectLevel(m.entries[i].ServerID, m.entries[i].Line)
+	}
+	m.updateContent()
+}
+
+// detectLevel finds the level of a line from serverID.
+func (m *LogPanelModel) detectLevel(serverID, line string) LogLevel {
+	pattern := defaultLogLevelPattern
+	if p, ok := m.levelPatterns[serverID]; ok {
+		pattern = p
+	}
+	return detectLogLevel(pattern, stripANSI(line))
+}
+
 // AppendLog adds a log entry.
 func (m *LogPanelModel) AppendLog(serverID, line string) {
 	entry := LogEntry{
 		ServerID:  serverID,
 		Line:      line,
 		Timestamp: time.Now(),
+		Level:     m.detectLevel(serverID, line),
 	}
 	m.entries = append(m.entries, entry)
 
@@ -205,20 +347,27 @@ func (m *LogPanelModel) updateContent() {
 	}
 
 	var content strings.Builder
-	for i, entry := range m.entries {
-		if i > 0 {
+	shown := 0
+	for _, entry := range m.entries {
+		if entry.Level != LogLevelUnknown && entry.Level < m.minLevel {
+			continue
+		}
+		if shown > 0 {
 			content.WriteString("\n")
 		}
+		shown++
 
 		// Build the prefix: "HH:MM:SS [serverID] "
 		ts := entry.Timestamp.Format("15:04:05")
 		serverTag := fmt.Sprintf("[%s]", entry.ServerID)
 
-		// Determine style based on log content
+		// Determine style based on log content. Lines are stored as
+		// received so raw mode can be toggled back on.
 		line := entry.Line
-		isError := strings.Contains(strings.ToLower(line), "error") ||
-			strings.Contains(strings.ToLower(line), "err:")
-		isWarn := strings.Contains(strings.ToLower(line), "warn")
+		if !m.raw {
+			line = stripANSI(line)
+		}
+		style := m.levelStyle(entry.Level, line)
 
 		if m.wrap {
 			// Calculate prefix width (timestamp + space + serverTag + space)
@@ -228,7 +377,7 @@ func (m *LogPanelModel) updateContent() {
 			contentWidth := max(m.viewport.Width-prefixWidth, 10)
 
 			// Wrap the log line
-			wrappedLines := wrapText(entry.Line, contentWidth)
+			wrappedLines := wrapText(line, contentWidth)
 			indent := strings.Repeat(" ", prefixWidth)
 
 			for j, wrappedLine := range wrappedLines {
@@ -247,14 +396,7 @@ func (m *LogPanelModel) updateContent() {
 					content.WriteString(indent)
 				}
 
-				// Apply appropriate style to the log content
-				if isError {
-					content.WriteString(m.theme.Danger.Render(wrappedLine))
-				} else if isWarn {
-					content.WriteString(m.theme.Warn.Render(wrappedLine))
-				} else {
-					content.WriteString(m.theme.Base.Render(wrappedLine))
-				}
+				content.WriteString(style.Render(wrappedLine))
 			}
 		} else {
 			// No wrapping - render single line
@@ -263,19 +405,41 @@ func (m *LogPanelModel) updateContent() {
 			content.WriteString(m.theme.Primary.Render(serverTag))
 			content.WriteString(" ")
 
-			if isError {
-				content.WriteString(m.theme.Danger.Render(line))
-			} else if isWarn {
-				content.WriteString(m.theme.Warn.Render(line))
-			} else {
-				content.WriteString(m.theme.Base.Render(line))
-			}
+			content.WriteString(style.Render(line))
 		}
 	}
 
+	if shown == 0 {
+		m.viewport.SetContent(m.theme.Faint.Render(fmt.Sprintf("No logs at %s or above...", m.minLevel)))
+		return
+	}
 	m.viewport.SetContent(content.String())
 }
 
+// levelStyle picks the style for a line's content. Lines with no detected
+// level are colored by whether they mention errors or warnings.
+func (m *LogPanelModel) levelStyle(level LogLevel, line string) lipgloss.Style {
+	if level == LogLevelUnknown {
+		lower := strings.ToLower(line)
+		switch {
+		case strings.Contains(lower, "error") || strings.Contains(lower, "err:"):
+			level = LogLevelError
+		case strings.Contains(lower, "warn"):
+			level = LogLevelWarn
+		}
+	}
+	switch level {
+	case LogLevelError:
+		return m.theme.Danger
+	case LogLevelWarn:
+		return m.theme.Warn
+	case LogLevelDebug:
+		return m.theme.Faint
+	default:
+		return m.theme.Base
+	}
+}
+
 // Init implements tea.Model.
 func (m LogPanelModel) Init() tea.Cmd {
 	return nil
@@ -308,9 +472,15 @@ func (m LogPanelModel) View() string {
 	if m.wrap {
 		title += " [WRAP]"
 	}
+	if m.raw {
+		title += " [RAW]"
+	}
+	if m.minLevel != LogLevelUnknown {
+		title += " [" + m.minLevel.String() + "+]"
+	}
 
 	// Show keybinding hints
-	title += "  f:follow w:wrap"
+	title += "  f:follow w:wrap C:raw F:level"
 
 	content := strings.TrimSuffix(m.viewport.View(), "\n")
 	if m.topPad > 0 {
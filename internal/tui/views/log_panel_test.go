@@ -0,0 +1,99 @@
+package views
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+)
+
+func TestLogPanel_StripsANSI(t *testing.T) {
+	panel := NewLogPanel(theme.New())
+	panel.SetVisible(true)
+	panel.SetSize(120, 10)
+
+	panel.AppendLog("colors", "\x1b[32mready\x1b[0m on \x1b[1;34mport 8080\x1b[0m \x1b]0;title\x07done")
+
+	content := panel.viewport.View()
+	if !strings.Contains(content, "ready on port 8080 done") {
+		t.Errorf("expected the stripped line, got:\n%q", content)
+	}
+	if strings.Contains(content, "[32m") || strings.Contains(content, "title") {
+		t.Errorf("expected ANSI escapes to be stripped, got:\n%q", content)
+	}
+
+	panel.ToggleRaw()
+	if !panel.IsRaw() {
+		t.Fatal("expected raw mode after ToggleRaw")
+	}
+	if content := panel.viewport.View(); !strings.Contains(content, "\x1b[32mready") {
+		t.Errorf("expected raw mode to keep the escapes, got:\n%q", content)
+	}
+	if !strings.Contains(panel.View(), "[RAW]") {
+		t.Error("expected the title to show [RAW]")
+	}
+
+	panel.ToggleRaw()
+	if content := panel.viewport.View(); strings.Contains(content, "[32m") {
+		t.Errorf("expected escapes stripped again after leaving raw mode, got:\n%q", content)
+	}
+}
+
+func TestLogPanel_LevelDetectionAndFilter(t *testing.T) {
+	panel := NewLogPanel(theme.New())
+	panel.SetVisible(true)
+	panel.SetSize(120, 20)
+	panel.SetLevelPatterns(map[string]*regexp.Regexp{
+		"custom": regexp.MustCompile(`^<(?P<level>\w+)>`),
+	})
+
+	lines := []struct {
+		server string
+		line   string
+		want   LogLevel
+	}{
+		{"std", "DEBUG connecting to cache", LogLevelDebug},
+		{"std", "[INFO] listening on stdio", LogLevelInfo},
+		{"std", "2024-05-01 12:00:00,123 WARNING slow response", LogLevelWarn},
+		{"std", "time=2024-05-01T12:00:00Z level=error msg=\"request failed\"", LogLevelError},
+		{"std", "\x1b[31mERROR:\x1b[0m disk full", LogLevelError},
+		{"std", "    at handler (index.js:10)", LogLevelUnknown},
+		{"custom", "<warn> retrying", LogLevelWarn},
+		{"custom", "INFO not matched by the custom pattern", LogLevelUnknown},
+	}
+	for _, l := range lines {
+		panel.AppendLog(l.server, l.line)
+	}
+	for i, l := range lines {
+		if got := panel.entries[i].Level; got != l.want {
+			t.Errorf("level of %q = %s, want %s", l.line, got, l.want)
+		}
+	}
+
+	panel.CycleMinLevel() // INFO
+	panel.CycleMinLevel() // WARN
+	if panel.MinLevel() != LogLevelWarn {
+		t.Fatalf("MinLevel() = %s, want WARN", panel.MinLevel())
+	}
+	content := panel.viewport.View()
+	for _, hidden := range []string{"connecting to cache", "listening on stdio"} {
+		if strings.Contains(content, hidden) {
+			t.Errorf("expected %q hidden at WARN, got:\n%s", hidden, content)
+		}
+	}
+	for _, shown := range []string{"slow response", "request failed", "disk full", "at handler", "retrying", "not matched"} {
+		if !strings.Contains(content, shown) {
+			t.Errorf("expected %q shown at WARN, got:\n%s", shown, content)
+		}
+	}
+	if !strings.Contains(panel.View(), "[WARN+]") {
+		t.Error("expected the title to show [WARN+]")
+	}
+
+	panel.CycleMinLevel() // ERROR
+	panel.CycleMinLevel() // back to all
+	if content := panel.viewport.View(); !strings.Contains(content, "connecting to cache") {
+		t.Errorf("expected all lines after cycling back, got:\n%s", content)
+	}
+}
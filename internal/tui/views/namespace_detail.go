@@ -11,6 +11,14 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// ToolCollision is a tool name exposed by more than one of a namespace's
+// servers. Tool prefixes keep the qualified names apart, but agents can
+// still mix the tools up.
+type ToolCollision struct {
+	Tool    string
+	Servers []string
+}
+
 // NamespaceDetailModel displays detailed information about a namespace.
 type NamespaceDetailModel struct {
 	theme         theme.Theme
@@ -24,6 +32,7 @@ type NamespaceDetailModel struct {
 	serverTokens map[string]int // serverID -> enabled token count
 	totalTokens  int
 	hasCache     bool
+	collisions   []ToolCollision
 	viewport     viewport.Model
 	width        int
 	height       int
@@ -41,7 +50,7 @@ func NewNamespaceDetail(th theme.Theme) NamespaceDetailModel {
 }
 
 // SetNamespace sets the namespace to display.
-func (m *NamespaceDetailModel) SetNamespace(name string, ns *config.NamespaceConfig, isDefault bool, allServers []config.ServerEntry, permissions []config.ToolPermission, serverTokens map[string]int) {
+func (m *NamespaceDetailModel) SetNamespace(name string, ns *config.NamespaceConfig, isDefault bool, allServers []config.ServerEntry, permissions []config.ToolPermission, serverTokens map[string]int, collisions []ToolCollision) {
 	m.namespaceName = name
 	m.namespace = ns
 	m.isDefault = isDefault
@@ -53,6 +62,7 @@ func (m *NamespaceDetailModel) SetNamespace(name string, ns *config.NamespaceCon
 		m.totalTokens += n
 	}
 	m.hasCache = len(serverTokens) > 0
+	m.collisions = collisions
 	m.updateContent()
 }
 
@@ -91,6 +101,10 @@ func (m *NamespaceDetailModel) updateContent() {
 		content.WriteString("  ")
 		content.WriteString(m.theme.Primary.Render("[default]"))
 	}
+	if len(m.collisions) > 0 {
+		content.WriteString("  ")
+		content.WriteString(m.theme.Warn.Render(fmt.Sprintf("[⚠ %d tool name collision(s)]", len(m.collisions))))
+	}
 	content.WriteString("\n\n")
 
 	infoStyle := m.theme.Muted
@@ -163,6 +177,20 @@ func (m *NamespaceDetailModel) updateContent() {
 		content.WriteString(serverBox.Render(serversContent.String()))
 	}
 
+	// Tool names exposed by more than one server
+	if len(m.collisions) > 0 {
+		content.WriteString("\n\n")
+		content.WriteString(m.theme.Title.Render(fmt.Sprintf("Tool Name Collisions (%d)", len(m.collisions))))
+		content.WriteString("\n")
+		for _, c := range m.collisions {
+			content.WriteString("  ")
+			content.WriteString(m.theme.Warn.Render(c.Tool))
+			content.WriteString(m.theme.Muted.Render(" in " + strings.Join(c.Servers, ", ")))
+			content.WriteString("\n")
+		}
+		content.WriteString(m.theme.Faint.Render("  Set a toolPrefix or deny one of each to tell them apart."))
+	}
+
 	// Tool permissions section
 	content.WriteString("\n\n")
 	content.WriteString(m.theme.Title.Render(fmt.Sprintf("Tool Permissions (%d configured)", len(m.permissions))))
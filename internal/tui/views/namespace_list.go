@@ -96,6 +96,18 @@ func (m *NamespaceListModel) SelectedItem() *NamespaceItem {
 	return &ni
 }
 
+// SelectName moves the cursor to the namespace with the given name. It
+// returns false if there is no such namespace.
+func (m *NamespaceListModel) SelectName(name string) bool {
+	for i, item := range m.list.Items() {
+		if ni, ok := item.(NamespaceItem); ok && ni.Name == name {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // SelectedIndex returns the index of the selected item.
 func (m NamespaceListModel) SelectedIndex() int {
 	return m.list.Index()
@@ -0,0 +1,153 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NamespacePickerResult is sent when the user picks a namespace or cancels.
+type NamespacePickerResult struct {
+	Name      string
+	Submitted bool
+}
+
+// NamespacePickerModel is a single-select overlay listing the namespaces a
+// server belongs to.
+type NamespacePickerModel struct {
+	theme      theme.Theme
+	visible    bool
+	serverName string
+	namespaces []string
+	selected   int
+	width      int
+	height     int
+
+	upKey    key.Binding
+	downKey  key.Binding
+	enterKey key.Binding
+	escKey   key.Binding
+}
+
+// NewNamespacePicker creates a new namespace picker.
+func NewNamespacePicker(th theme.Theme) NamespacePickerModel {
+	return NamespacePickerModel{
+		theme: th,
+		upKey: key.NewBinding(
+			key.WithKeys("up", "k"),
+		),
+		downKey: key.NewBinding(
+			key.WithKeys("down", "j"),
+		),
+		enterKey: key.NewBinding(
+			key.WithKeys("enter"),
+		),
+		escKey: key.NewBinding(
+			key.WithKeys("esc"),
+		),
+	}
+}
+
+// Show displays the picker with the namespaces containing serverName.
+func (m *NamespacePickerModel) Show(serverName string, namespaces []string) {
+	m.visible = true
+	m.serverName = serverName
+	m.namespaces = namespaces
+	m.selected = 0
+}
+
+// Hide hides the namespace picker.
+func (m *NamespacePickerModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the picker is visible.
+func (m NamespacePickerModel) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the available dimensions for centering.
+func (m *NamespacePickerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles key events for the namespace picker.
+func (m NamespacePickerModel) Update(msg tea.Msg) (NamespacePickerModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.upKey):
+			if m.selected > 0 {
+				m.selected--
+			}
+		case key.Matches(msg, m.downKey):
+			if m.selected < len(m.namespaces)-1 {
+				m.selected++
+			}
+		case key.Matches(msg, m.enterKey):
+			m.visible = false
+			if len(m.namespaces) == 0 {
+				return m, func() tea.Msg { return NamespacePickerResult{} }
+			}
+			name := m.namespaces[m.selected]
+			return m, func() tea.Msg {
+				return NamespacePickerResult{Name: name, Submitted: true}
+			}
+		case key.Matches(msg, m.escKey):
+			m.visible = false
+			return m, func() tea.Msg {
+				return NamespacePickerResult{Submitted: false}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// RenderOverlay renders the picker as a centered overlay on top of the base content.
+func (m NamespacePickerModel) RenderOverlay(base string, width, height int) string {
+	if !m.visible {
+		return base
+	}
+
+	title := m.theme.Title.Render(fmt.Sprintf("Namespaces with %q", m.serverName))
+
+	var options strings.Builder
+	for i, name := range m.namespaces {
+		if i == m.selected {
+			options.WriteString("  " + m.theme.Primary.Render("▸") + " " + m.theme.Primary.Bold(true).Render(name) + "\n")
+		} else {
+			options.WriteString("    " + m.theme.Base.Render(name) + "\n")
+		}
+	}
+
+	footer := m.theme.Faint.Render("↑↓ select  enter open  esc ×")
+
+	content := title + "\n\n" + options.String() + "\n" + footer
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary.GetForeground()).
+		Padding(1, 2).
+		Width(50).
+		Render(content)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+	)
+}
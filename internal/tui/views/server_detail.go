@@ -1,14 +1,20 @@
 package views
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/Bigsy/mcpmu/internal/config"
 	"github.com/Bigsy/mcpmu/internal/events"
 	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/process"
+	"github.com/Bigsy/mcpmu/internal/redact"
 	"github.com/Bigsy/mcpmu/internal/tui/theme"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -24,6 +30,9 @@ type ServerDetailModel struct {
 	tools          []mcp.Tool
 	toolTokens     map[string]int // toolName -> token count
 	toolsFromCache bool           // true when tools were loaded from cache
+	showLaunch     bool           // true when the launch command section is expanded
+	toolCursor     int            // index of the selected tool in tools
+	showSchema     bool           // true when the selected tool's input schema is expanded
 	viewport       viewport.Model
 	width          int
 	height         int
@@ -42,6 +51,14 @@ func NewServerDetail(theme theme.Theme) ServerDetailModel {
 
 // SetServer sets the server to display.
 func (m *ServerDetailModel) SetServer(name string, srv *config.ServerConfig, status *events.ServerStatus, tools []mcp.Tool, toolTokens map[string]int, toolsFromCache bool) {
+	if name != m.serverName {
+		m.showLaunch = false
+		m.toolCursor = 0
+		m.showSchema = false
+	}
+	if m.toolCursor >= len(tools) {
+		m.toolCursor = max(len(tools)-1, 0)
+	}
 	m.serverName = name
 	m.server = srv
 	m.status = status
@@ -69,6 +86,56 @@ func (m *ServerDetailModel) SetSize(width, height int) {
 	m.updateContent()
 }
 
+// ShowLaunchCommand expands the launch command section for a stdio server.
+func (m *ServerDetailModel) ShowLaunchCommand() {
+	m.showLaunch = true
+	m.updateContent()
+}
+
+// LaunchCommand returns the shell command mcpmu effectively runs for the
+// current stdio server, with secret env values and args masked. Placeholders
+// in cwd and args are left as written, under a comment saying so. It returns
+// "" for HTTP servers or when no server is selected.
+func (m ServerDetailModel) LaunchCommand() string {
+	if m.server == nil || m.server.IsHTTP() {
+		return ""
+	}
+	cmd := strings.Join(launchCommandLines(m.server), " \\\n")
+	if hasLaunchPlaceholders(m.server) {
+		cmd = "# " + launchTemplateNote + "\n" + cmd
+	}
+	return cmd
+}
+
+// SelectNextTool moves the tool selection down, wrapping at the end.
+func (m *ServerDetailModel) SelectNextTool() {
+	if len(m.tools) == 0 {
+		return
+	}
+	m.toolCursor = (m.toolCursor + 1) % len(m.tools)
+	m.updateContent()
+}
+
+// SelectPrevTool moves the tool selection up, wrapping at the start.
+func (m *ServerDetailModel) SelectPrevTool() {
+	if len(m.tools) == 0 {
+		return
+	}
+	m.toolCursor = (m.toolCursor - 1 + len(m.tools)) % len(m.tools)
+	m.updateContent()
+}
+
+// ToggleToolSchema expands or collapses the input schema of the selected
+// tool. It returns false when there is no tool to show.
+func (m *ServerDetailModel) ToggleToolSchema() bool {
+	if len(m.tools) == 0 {
+		return false
+	}
+	m.showSchema = !m.showSchema
+	m.updateContent()
+	return true
+}
+
 // SetFocused sets whether the view is focused.
 func (m *ServerDetailModel) SetFocused(focused bool) {
 	m.focused = focused
@@ -94,6 +161,15 @@ func (m *ServerDetailModel) updateContent() {
 	content.WriteString(statusPill)
 	content.WriteString("\n\n")
 
+	if !m.server.IsEnabled() {
+		note := "Disabled"
+		if m.server.DisabledReason != "" {
+			note += ": " + m.server.DisabledReason
+		}
+		content.WriteString(m.theme.Faint.Render(note))
+		content.WriteString("\n\n")
+	}
+
 	// Server info
 	infoStyle := m.theme.Muted
 	labelStyle := m.theme.Base.Bold(true)
@@ -108,6 +184,12 @@ func (m *ServerDetailModel) updateContent() {
 		uptime := time.Since(*m.status.StartedAt).Round(time.Second)
 		content.WriteString(labelStyle.Render("Uptime: "))
 		content.WriteString(infoStyle.Render(formatDuration(uptime)))
+		content.WriteString("   ")
+	}
+
+	if m.status != nil && m.status.State == events.StateRunning && m.status.ProtocolVersion != "" {
+		content.WriteString(labelStyle.Render("Protocol: "))
+		content.WriteString(infoStyle.Render(m.status.ProtocolVersion))
 	}
 	content.WriteString("\n\n")
 
@@ -153,9 +235,37 @@ func (m *ServerDetailModel) updateContent() {
 	if len(m.server.Env) > 0 {
 		content.WriteString("\n")
 		content.WriteString(labelStyle.Render("Environment:\n"))
-		for k, v := range m.server.Env {
+		env := redact.Env(m.server.Env)
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
 			content.WriteString("  ")
-			content.WriteString(infoStyle.Render(k + "=" + v))
+			content.WriteString(infoStyle.Render(k + "=" + env[k]))
+			content.WriteString("\n")
+		}
+	}
+
+	// Launch command
+	if m.showLaunch && !m.server.IsHTTP() {
+		content.WriteString("\n")
+		if hasLaunchPlaceholders(m.server) {
+			content.WriteString(labelStyle.Render("Launch Command (template):"))
+			content.WriteString("\n  ")
+			content.WriteString(m.theme.Faint.Render(launchTemplateNote))
+		} else {
+			content.WriteString(labelStyle.Render("Launch Command:"))
+		}
+		content.WriteString("\n")
+		lines := launchCommandLines(m.server)
+		for i, line := range lines {
+			content.WriteString("  ")
+			if i < len(lines)-1 {
+				line += " \\"
+			}
+			content.WriteString(infoStyle.Render(line))
 			content.WriteString("\n")
 		}
 	}
@@ -185,6 +295,11 @@ func (m *ServerDetailModel) updateContent() {
 			if i > 0 {
 				toolsContent.WriteString("\n")
 			}
+			if i == m.toolCursor {
+				toolsContent.WriteString(m.theme.Primary.Render("▸ "))
+			} else {
+				toolsContent.WriteString("  ")
+			}
 			if m.server.IsToolDenied(tool.Name) {
 				toolsContent.WriteString(m.theme.Faint.Render(tool.Name))
 				toolsContent.WriteString("  ")
@@ -197,7 +312,7 @@ func (m *ServerDetailModel) updateContent() {
 				toolsContent.WriteString(m.theme.Faint.Render(fmt.Sprintf("~%d tokens", tokens)))
 			}
 			if tool.Description != "" {
-				toolsContent.WriteString("\n  ")
+				toolsContent.WriteString("\n    ")
 				desc := tool.Description
 				if len(desc) > 60 {
 					desc = desc[:57] + "..."
@@ -206,6 +321,18 @@ func (m *ServerDetailModel) updateContent() {
 			}
 		}
 		content.WriteString(toolBox.Render(toolsContent.String()))
+
+		if m.showSchema && m.toolCursor < len(m.tools) {
+			tool := m.tools[m.toolCursor]
+			content.WriteString("\n\n")
+			content.WriteString(labelStyle.Render("Input Schema: " + tool.Name))
+			content.WriteString("\n")
+			for line := range strings.SplitSeq(formatToolSchema(tool.InputSchema), "\n") {
+				content.WriteString("  ")
+				content.WriteString(infoStyle.Render(line))
+				content.WriteString("\n")
+			}
+		}
 	}
 
 	// Error info
@@ -259,7 +386,7 @@ func (m *ServerDetailModel) renderStdioInfo(content *strings.Builder, labelStyle
 	content.WriteString(labelStyle.Render("Command: "))
 	cmd := m.server.Command
 	if len(m.server.Args) > 0 {
-		cmd += " " + strings.Join(m.server.Args, " ")
+		cmd += " " + strings.Join(redact.Args(m.server.Args), " ")
 	}
 	content.WriteString(infoStyle.Render(cmd))
 	content.WriteString("\n")
@@ -335,6 +462,99 @@ func (m *ServerDetailModel) renderHTTPInfo(content *strings.Builder, labelStyle,
 	}
 }
 
+// launchCommandLines renders a stdio server's launch as shell lines: the
+// working directory, the environment overrides (including the PATH prefix
+// added by the supervisor) and the command with its args. Secret env values
+// and args are masked.
+func launchCommandLines(srv *config.ServerConfig) []string {
+	var lines []string
+	if srv.Cwd != "" {
+		lines = append(lines, "cd "+shellQuote(srv.Cwd)+" &&")
+	}
+
+	env := redact.Env(srv.Env)
+	if srv.CleanEnv {
+		// Only PATH, passEnv and env reach the server.
+		lines = append(lines, "env -i")
+	}
+	if _, ok := env["PATH"]; !ok {
+		// The supervisor prepends common binary locations to the inherited PATH.
+		lines = append(lines, "PATH="+process.AugmentPath("$PATH"))
+	}
+	if srv.CleanEnv {
+		for _, k := range srv.PassEnv {
+			if _, ok := env[k]; !ok && k != "PATH" {
+				lines = append(lines, k+`="$`+k+`"`)
+			}
+		}
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, k+"="+shellQuote(env[k]))
+	}
+
+	cmd := []string{shellQuote(srv.Command)}
+	for _, arg := range redact.Args(srv.Args) {
+		cmd = append(cmd, shellQuote(arg))
+	}
+	return append(lines, strings.Join(cmd, " "))
+}
+
+// launchTemplateNote explains a launch command shown with its placeholders.
+const launchTemplateNote = "{{...}} and ${...} placeholders are expanded when mcpmu starts the server"
+
+// hasLaunchPlaceholders reports whether srv's cwd or args contain
+// placeholders the supervisor expands at start, so launchCommandLines shows
+// a template rather than the exact command.
+func hasLaunchPlaceholders(srv *config.ServerConfig) bool {
+	isTemplate := func(s string) bool {
+		return strings.Contains(s, "{{") || strings.Contains(s, "${")
+	}
+	return isTemplate(srv.Cwd) || slices.ContainsFunc(srv.Args, isTemplate)
+}
+
+// shellQuote single-quotes s when it contains characters a POSIX shell
+// would interpret.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./:=@%+,", r)
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return !safe(r) }) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// formatToolSchema pretty-prints a tool's JSON input schema.
+func formatToolSchema(schema any) string {
+	if schema == nil {
+		return "(no input schema)"
+	}
+	raw, ok := schema.(json.RawMessage)
+	if !ok {
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return fmt.Sprintf("(invalid schema: %v)", err)
+		}
+		raw = b
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return "(no input schema)"
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return out.String()
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/process"
 	"github.com/Bigsy/mcpmu/internal/testutil"
 	"github.com/Bigsy/mcpmu/internal/tui/theme"
 )
@@ -251,3 +252,89 @@ func TestServerDetail_HTTPHeadersSorted(t *testing.T) {
 		t.Errorf("expected A-Header (pos %d) to appear before Z-Header (pos %d)", aIdx, zIdx)
 	}
 }
+
+func TestServerDetail_LaunchCommand(t *testing.T) {
+	detail := newTestDetailModel(t)
+	srv := &config.ServerConfig{
+		Command: "npx",
+		Args:    []string{"-y", "@upstash/context7-mcp", "--api-key=sk-live-123"},
+		Cwd:     "/srv/mcp",
+		Env: map[string]string{
+			"LOG_LEVEL":    "debug",
+			"GITHUB_TOKEN": "ghp_secret",
+			"GREETING":     "hello world",
+		},
+	}
+	detail.SetServer("context7", srv, nil, nil, nil, false)
+
+	assertNotContains(t, detailContent(t, detail), "Launch Command:")
+
+	detail.ShowLaunchCommand()
+	content := detailContent(t, detail)
+
+	assertContains(t, content, "Launch Command:")
+	assertContains(t, content, "cd /srv/mcp && \\")
+	assertContains(t, content, "PATH="+process.AugmentPath("$PATH")+" \\")
+	assertContains(t, content, "/opt/homebrew/bin:/usr/local/bin:/usr/bin:/bin:$PATH")
+	assertContains(t, content, "LOG_LEVEL=debug \\")
+	assertContains(t, content, "GREETING='hello world' \\")
+	assertContains(t, content, "GITHUB_TOKEN=REDACTED \\")
+	assertContains(t, content, "npx -y @upstash/context7-mcp --api-key=REDACTED")
+	assertNotContains(t, content, "ghp_secret")
+	assertNotContains(t, content, "sk-live-123")
+
+	want := "cd /srv/mcp && \\\n" +
+		"PATH=/opt/homebrew/bin:/usr/local/bin:/usr/bin:/bin:$PATH \\\n" +
+		"GITHUB_TOKEN=REDACTED \\\n" +
+		"GREETING='hello world' \\\n" +
+		"LOG_LEVEL=debug \\\n" +
+		"npx -y @upstash/context7-mcp --api-key=REDACTED"
+	if got := detail.LaunchCommand(); got != want {
+		t.Errorf("LaunchCommand() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestServerDetail_LaunchCommandExplicitPath(t *testing.T) {
+	detail := newTestDetailModel(t)
+	srv := &config.ServerConfig{
+		Command: "./server",
+		Env:     map[string]string{"PATH": "/custom/bin"},
+	}
+	detail.SetServer("custom", srv, nil, nil, nil, false)
+
+	// A configured PATH replaces the augmented one entirely.
+	want := "PATH=/custom/bin \\\n./server"
+	if got := detail.LaunchCommand(); got != want {
+		t.Errorf("LaunchCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestServerDetail_LaunchCommandTemplate(t *testing.T) {
+	detail := newTestDetailModel(t)
+	srv := &config.ServerConfig{
+		Command: "npx",
+		Args:    []string{"-y", "server-fs", "{{config_dir}}/data", "${PROJECTS_DIR}"},
+		Env:     map[string]string{"PATH": "/usr/bin"},
+	}
+	detail.SetServer("fs", srv, nil, nil, nil, false)
+	detail.ShowLaunchCommand()
+	content := detailContent(t, detail)
+
+	assertContains(t, content, "Launch Command (template):")
+	assertContains(t, content, "placeholders are expanded when mcpmu starts the server")
+
+	want := "# {{...}} and ${...} placeholders are expanded when mcpmu starts the server\n" +
+		"PATH=/usr/bin \\\n" +
+		"npx -y server-fs '{{config_dir}}/data' '${PROJECTS_DIR}'"
+	if got := detail.LaunchCommand(); got != want {
+		t.Errorf("LaunchCommand() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestServerDetail_LaunchCommandHTTP(t *testing.T) {
+	detail := newTestDetailModel(t)
+	detail.SetServer("remote", &config.ServerConfig{URL: "https://example.com/mcp"}, nil, nil, nil, false)
+	if got := detail.LaunchCommand(); got != "" {
+		t.Errorf("LaunchCommand() = %q, want empty for HTTP server", got)
+	}
+}
@@ -23,8 +23,20 @@ type ServerItem struct {
 	Status     events.ServerStatus
 	Namespaces []string       // Names of namespaces this server belongs to
 	AuthStatus mcp.AuthStatus // Authentication status for HTTP servers
+	Reach      Reachability   // Background reachability of a stopped HTTP server
 }
 
+// Reachability is the result of a lightweight probe of an HTTP server's URL,
+// made without opening an MCP session.
+type Reachability string
+
+const (
+	ReachUnknown     Reachability = ""
+	ReachOK          Reachability = "reachable"
+	ReachUnreachable Reachability = "unreachable"
+	ReachNeedsAuth   Reachability = "needs-auth"
+)
+
 func (i ServerItem) Title() string { return i.Name }
 func (i ServerItem) Description() string {
 	if i.Config.IsHTTP() {
@@ -44,6 +56,10 @@ type ServerListModel struct {
 	height   int
 	topPad   int
 	focused  bool
+
+	// Servers starting or stopping, counted in SetItems so spinner ticks
+	// don't rescan the whole list
+	transitional int
 }
 
 // NewServerList creates a new server list view.
@@ -81,8 +97,12 @@ func NewServerList(th theme.Theme) ServerListModel {
 // SetItems updates the server list items.
 func (m *ServerListModel) SetItems(items []ServerItem) {
 	listItems := make([]list.Item, len(items))
+	m.transitional = 0
 	for i, item := range items {
 		listItems[i] = item
+		if item.Status.State == events.StateStarting || item.Status.State == events.StateStopping {
+			m.transitional++
+		}
 	}
 	m.list.SetItems(listItems)
 	// Update delegate with current spinner frame
@@ -91,13 +111,7 @@ func (m *ServerListModel) SetItems(items []ServerItem) {
 
 // HasTransitionalServers returns true if any server is in a transitional state.
 func (m ServerListModel) HasTransitionalServers() bool {
-	for _, item := range m.list.Items() {
-		si, ok := item.(ServerItem)
-		if ok && (si.Status.State == events.StateStarting || si.Status.State == events.StateStopping) {
-			return true
-		}
-	}
-	return false
+	return m.transitional > 0
 }
 
 // SpinnerTick returns a command to tick the spinner.
@@ -143,6 +157,21 @@ func (m *ServerListModel) SelectedItem() *ServerItem {
 	return &si
 }
 
+// SelectNext moves the selection to the next shown server after the
+// current one that matches, wrapping around. It returns false, leaving the
+// selection alone, if none other does.
+func (m *ServerListModel) SelectNext(match func(ServerItem) bool) bool {
+	items := m.list.VisibleItems()
+	for offset := 1; offset <= len(items); offset++ {
+		i := (m.list.Index() + offset) % len(items)
+		if si, ok := items[i].(ServerItem); ok && match(si) {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 // SelectedIndex returns the index of the selected item.
 func (m ServerListModel) SelectedIndex() int {
 	return m.list.Index()
@@ -157,15 +186,14 @@ func (m ServerListModel) Init() tea.Cmd {
 func (m ServerListModel) Update(msg tea.Msg) (ServerListModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	// Handle spinner tick messages
+	// Handle spinner tick messages. Only the delegate's frame changes; the
+	// list itself has nothing to do with a tick.
 	if _, ok := msg.(spinner.TickMsg); ok {
 		var spinnerCmd tea.Cmd
 		m.spinner, spinnerCmd = m.spinner.Update(msg)
-		if spinnerCmd != nil {
-			cmds = append(cmds, spinnerCmd)
-		}
 		// Update the delegate with new spinner frame
 		m.list.SetDelegate(newServerDelegate(m.theme, m.spinner.View()))
+		return m, spinnerCmd
 	}
 
 	var listCmd tea.Cmd
@@ -289,6 +317,10 @@ func (d serverDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		line1.WriteString(d.theme.Faint.Render("[http]"))
 		line1.WriteString(" ")
 		line1.WriteString(formatAuthBadge(si, d.theme))
+		if badge := formatReachBadge(si, d.theme); badge != "" {
+			line1.WriteString(" ")
+			line1.WriteString(badge)
+		}
 	}
 
 	// Second line: command/URL + namespace badges
@@ -310,6 +342,12 @@ func (d serverDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 	}
 	line2.WriteString(d.theme.Muted.Render(cmdOrURL))
 
+	// Why the server was disabled, if recorded
+	if !enabled && si.Config.DisabledReason != "" {
+		line2.WriteString("  ")
+		line2.WriteString(d.theme.Faint.Render("— " + truncateString(si.Config.DisabledReason, 40)))
+	}
+
 	// Namespace badges on second line
 	if len(si.Namespaces) > 0 {
 		line2.WriteString("  ")
@@ -390,3 +428,22 @@ func formatAuthBadge(si ServerItem, t theme.Theme) string {
 		return t.Faint.Render("[oauth]")
 	}
 }
+
+// formatReachBadge returns a styled reachability badge for a stopped HTTP
+// server, or "" when it hasn't been probed or is running (and so reports its
+// real status).
+func formatReachBadge(si ServerItem, t theme.Theme) string {
+	if si.Status.State == events.StateRunning {
+		return ""
+	}
+	switch si.Reach {
+	case ReachOK:
+		return t.Success.Render("[reachable]")
+	case ReachUnreachable:
+		return t.Danger.Render("[unreachable]")
+	case ReachNeedsAuth:
+		return t.Warn.Render("[needs-auth]")
+	default:
+		return ""
+	}
+}
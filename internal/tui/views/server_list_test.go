@@ -0,0 +1,103 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Bigsy/mcpmu/internal/config"
+	"github.com/Bigsy/mcpmu/internal/events"
+	"github.com/Bigsy/mcpmu/internal/testutil"
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func manyServerItems(n int) []ServerItem {
+	items := make([]ServerItem, n)
+	for i := range items {
+		items[i] = ServerItem{
+			Name:   fmt.Sprintf("srv-%03d", i),
+			Config: config.ServerConfig{Command: "server"},
+			Status: events.ServerStatus{State: events.StateStopped},
+		}
+	}
+	return items
+}
+
+// renderedServers returns the server names shown in a rendered list.
+func renderedServers(view string) []string {
+	var names []string
+	for _, field := range strings.Fields(testutil.StripANSI(view)) {
+		if strings.HasPrefix(field, "srv-") {
+			names = append(names, field)
+		}
+	}
+	return names
+}
+
+func TestServerList_ManyServersRenderBoundedWindow(t *testing.T) {
+	m := NewServerList(theme.New())
+	m.SetSize(80, 30)
+	m.SetItems(manyServerItems(300))
+
+	shown := renderedServers(m.View())
+	if len(shown) == 0 || len(shown) > 30 {
+		t.Fatalf("rendered %d servers, want a window bounded by the pane height", len(shown))
+	}
+	if shown[0] != "srv-000" {
+		t.Errorf("first rendered server = %q, want srv-000", shown[0])
+	}
+
+	// Moving the cursor past the window scrolls the selection into view
+	for range 150 {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if got := m.SelectedItem().Name; got != "srv-150" {
+		t.Fatalf("selected %q, want srv-150", got)
+	}
+	shown = renderedServers(m.View())
+	if len(shown) > 30 {
+		t.Fatalf("rendered %d servers after scrolling, want a bounded window", len(shown))
+	}
+	found := false
+	for _, name := range shown {
+		found = found || name == "srv-150"
+		if name == "srv-000" {
+			t.Error("expected the first page to scroll out of view")
+		}
+	}
+	if !found {
+		t.Errorf("selected server not rendered; window shows %v", shown)
+	}
+}
+
+func TestServerList_TransitionalServers(t *testing.T) {
+	m := NewServerList(theme.New())
+	items := manyServerItems(300)
+	m.SetItems(items)
+	if m.HasTransitionalServers() {
+		t.Fatal("expected no transitional servers")
+	}
+
+	items[250].Status.State = events.StateStarting
+	m.SetItems(items)
+	if !m.HasTransitionalServers() {
+		t.Fatal("expected a transitional server")
+	}
+
+	items[250].Status.State = events.StateRunning
+	m.SetItems(items)
+	if m.HasTransitionalServers() {
+		t.Fatal("expected transitional state to clear")
+	}
+}
+
+func BenchmarkServerList_View(b *testing.B) {
+	m := NewServerList(theme.New())
+	m.SetSize(120, 40)
+	m.SetItems(manyServerItems(500))
+
+	for b.Loop() {
+		_ = m.View()
+	}
+}
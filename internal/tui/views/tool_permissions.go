@@ -29,6 +29,9 @@ type ToolPermissionsResult struct {
 	// AutoStartedServers contains IDs of servers that were auto-started for this session
 	// The caller should stop these when the modal closes
 	AutoStartedServers []string
+	// OtherChanges contains bulk changes for namespaces other than the one
+	// being edited: namespace -> "serverID:toolName" -> enabled
+	OtherChanges map[string]map[string]bool
 }
 
 // toolPermItem represents a tool in the permission editor.
@@ -80,12 +83,22 @@ type ToolPermissionsModel struct {
 	discovering        bool     // True while waiting for tools
 	discoveryTimeout   bool     // True if discovery timed out
 
+	// Bulk mode applies one tool's decision across several namespaces
+	serverNamespaces map[string][]string // serverName -> namespaces containing it
+	bulkActive       bool
+	bulkItem         toolPermItem
+	bulkCursor       int
+	bulkSelected     map[string]bool
+	bulkNote         string
+	otherChanges     map[string]map[string]bool
+
 	// Key bindings
 	escKey        key.Binding
 	enterKey      key.Binding
 	spaceKey      key.Binding
 	enableSafeKey key.Binding
 	denyAllKey    key.Binding
+	bulkKey       key.Binding
 }
 
 // isGloballyDenied returns whether a tool is in the server's global deny list.
@@ -144,6 +157,10 @@ func NewToolPermissions(th theme.Theme) ToolPermissionsModel {
 			key.WithKeys("d"),
 			key.WithHelp("d", "deny-all"),
 		),
+		bulkKey: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "bulk"),
+		),
 	}
 }
 
@@ -167,6 +184,7 @@ func (m *ToolPermissionsModel) Show(
 	m.globalDenied = globalDenied
 	m.originalPerms = make(map[string]bool)
 	m.currentPerms = make(map[string]bool)
+	m.resetBulk()
 
 	// Build permission lookup
 	for _, perm := range permissions {
@@ -240,6 +258,7 @@ func (m *ToolPermissionsModel) ShowDiscovering(namespaceID string, autoStartedSe
 	m.autoStartedServers = autoStartedServers
 	m.originalPerms = make(map[string]bool)
 	m.currentPerms = make(map[string]bool)
+	m.resetBulk()
 	m.list.SetItems([]list.Item{})
 }
 
@@ -328,6 +347,108 @@ func (m *ToolPermissionsModel) FinishDiscovery(
 	m.list.SetDelegate(newToolPermDelegate(m.theme, m.currentPerms, m.denyByDefault, m.serverDefaults, m.globalDenied))
 }
 
+// SetServerNamespaces sets the namespaces each server belongs to, offered as
+// targets when applying a tool's permission in bulk.
+func (m *ToolPermissionsModel) SetServerNamespaces(serverNamespaces map[string][]string) {
+	m.serverNamespaces = serverNamespaces
+}
+
+// resetBulk clears bulk mode and any pending changes to other namespaces.
+func (m *ToolPermissionsModel) resetBulk() {
+	m.bulkActive = false
+	m.bulkNote = ""
+	m.bulkSelected = nil
+	m.otherChanges = make(map[string]map[string]bool)
+}
+
+// bulkNamespaces returns the namespaces offered for the bulk tool, always
+// including the namespace being edited.
+func (m *ToolPermissionsModel) bulkNamespaces() []string {
+	namespaces := m.serverNamespaces[m.bulkItem.serverID]
+	if !slices.Contains(namespaces, m.namespaceID) {
+		namespaces = append([]string{m.namespaceID}, namespaces...)
+	}
+	return namespaces
+}
+
+// startBulk enters bulk mode for the selected tool, with the namespace
+// being edited preselected.
+func (m *ToolPermissionsModel) startBulk() {
+	item := m.list.SelectedItem()
+	if item == nil {
+		return
+	}
+	ti := item.(toolPermItem)
+	if ti.isHeader || m.isGloballyDenied(ti.serverID, ti.toolName) {
+		return
+	}
+	m.bulkActive = true
+	m.bulkItem = ti
+	m.bulkCursor = 0
+	m.bulkSelected = map[string]bool{m.namespaceID: true}
+}
+
+// applyBulk sets the bulk tool's permission in every selected namespace.
+// The namespace being edited is updated in place like a toggle; the others
+// are returned in the result when the editor is saved.
+func (m *ToolPermissionsModel) applyBulk(enabled bool) {
+	key := m.bulkItem.serverID + ":" + m.bulkItem.toolName
+	count := 0
+	for _, ns := range m.bulkNamespaces() {
+		if !m.bulkSelected[ns] {
+			continue
+		}
+		count++
+		if ns == m.namespaceID {
+			if enabled == m.defaultAllowed(m.bulkItem.serverID) {
+				delete(m.currentPerms, key)
+			} else {
+				m.currentPerms[key] = enabled
+			}
+			continue
+		}
+		if m.otherChanges[ns] == nil {
+			m.otherChanges[ns] = make(map[string]bool)
+		}
+		m.otherChanges[ns][key] = enabled
+	}
+	if count == 0 {
+		return
+	}
+
+	verb := "denied"
+	if enabled {
+		verb = "allowed"
+	}
+	m.bulkNote = fmt.Sprintf("%s %s in %d namespace(s) — enter to save", m.bulkItem.toolName, verb, count)
+	m.bulkActive = false
+	m.list.SetDelegate(newToolPermDelegate(m.theme, m.currentPerms, m.denyByDefault, m.serverDefaults, m.globalDenied))
+}
+
+// updateBulk handles keys while choosing namespaces in bulk mode.
+func (m *ToolPermissionsModel) updateBulk(msg tea.KeyMsg) {
+	namespaces := m.bulkNamespaces()
+	switch {
+	case key.Matches(msg, m.escKey):
+		m.bulkActive = false
+	case msg.String() == "up" || msg.String() == "k":
+		if m.bulkCursor > 0 {
+			m.bulkCursor--
+		}
+	case msg.String() == "down" || msg.String() == "j":
+		if m.bulkCursor < len(namespaces)-1 {
+			m.bulkCursor++
+		}
+	case key.Matches(msg, m.spaceKey):
+		ns := namespaces[m.bulkCursor]
+		m.bulkSelected[ns] = !m.bulkSelected[ns]
+	case key.Matches(msg, m.enableSafeKey):
+		m.applyBulk(true)
+	case key.Matches(msg, m.denyAllKey):
+		m.applyBulk(false)
+	}
+}
+
 // SetSize sets the available size.
 func (m *ToolPermissionsModel) SetSize(width, height int) {
 	m.width = width
@@ -401,6 +522,7 @@ func (m *ToolPermissionsModel) toggleSelected() {
 // submitResult builds and returns the submit command.
 func (m *ToolPermissionsModel) submitResult() tea.Cmd {
 	autoStarted := m.autoStartedServers
+	otherChanges := m.otherChanges
 	m.visible = false
 	m.autoStartedServers = nil
 
@@ -425,6 +547,7 @@ func (m *ToolPermissionsModel) submitResult() tea.Cmd {
 			Deletions:          deletions,
 			Submitted:          true,
 			AutoStartedServers: autoStarted,
+			OtherChanges:       otherChanges,
 		}
 	}
 }
@@ -459,6 +582,11 @@ func (m *ToolPermissionsModel) Update(msg tea.Msg) tea.Cmd {
 		return cmd
 	}
 
+	if m.bulkActive {
+		m.updateBulk(kmsg)
+		return nil
+	}
+
 	if m.filterFocused {
 		switch {
 		case key.Matches(kmsg, m.escKey):
@@ -516,6 +644,9 @@ func (m *ToolPermissionsModel) Update(msg tea.Msg) tea.Cmd {
 		m.applyBulkDenyAll()
 		m.list.SetDelegate(newToolPermDelegate(m.theme, m.currentPerms, m.denyByDefault, m.serverDefaults, m.globalDenied))
 		return nil
+	case key.Matches(kmsg, m.bulkKey):
+		m.startBulk()
+		return nil
 	}
 
 	var cmd tea.Cmd
@@ -633,6 +764,13 @@ func (m ToolPermissionsModel) RenderOverlay(base string, width, height int) stri
 			Padding(2, 4).
 			Width(editorWidth).
 			Render(m.theme.Title.Render("Tool Permissions") + "\n\n" + msg)
+	} else if m.bulkActive {
+		contentStr = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(m.theme.Primary.GetForeground()).
+			Padding(1, 2).
+			Width(editorWidth).
+			Render(m.bulkView())
 	} else {
 		// Normal editing state
 		var footer strings.Builder
@@ -659,7 +797,12 @@ func (m ToolPermissionsModel) RenderOverlay(base string, width, height int) stri
 			}
 		}
 
-		footer.WriteString(m.theme.Faint.Render("space=toggle  /=filter  a=enable-safe  d=deny-all  enter=save  esc=cancel"))
+		if m.bulkNote != "" {
+			footer.WriteString(m.theme.Success.Render(m.bulkNote))
+			footer.WriteString("\n")
+		}
+
+		footer.WriteString(m.theme.Faint.Render("space=toggle  /=filter  a=enable-safe  d=deny-all  b=bulk  enter=save  esc=cancel"))
 
 		// Show per-server policy for selected tool if applicable
 		if item := m.list.SelectedItem(); item != nil {
@@ -702,6 +845,34 @@ func (m ToolPermissionsModel) RenderOverlay(base string, width, height int) stri
 	)
 }
 
+// bulkView renders the namespace selection for bulk mode.
+func (m ToolPermissionsModel) bulkView() string {
+	var b strings.Builder
+	b.WriteString(m.theme.Title.Render(fmt.Sprintf("Apply %q (%s) to namespaces", m.bulkItem.toolName, m.bulkItem.serverName)))
+	b.WriteString("\n\n")
+	for i, ns := range m.bulkNamespaces() {
+		cursor := "  "
+		if i == m.bulkCursor {
+			cursor = "> "
+		}
+		checkbox := "[ ]"
+		if m.bulkSelected[ns] {
+			checkbox = m.theme.Primary.Render("[x]")
+		}
+		name := ns
+		if i == m.bulkCursor {
+			name = m.theme.Primary.Bold(true).Render(name)
+		}
+		if ns == m.namespaceID {
+			name += m.theme.Faint.Render(" (editing)")
+		}
+		b.WriteString(cursor + checkbox + " " + name + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.theme.Faint.Render("space=select  a=allow  d=deny  esc=back"))
+	return b.String()
+}
+
 // toolPermDelegate renders items in the tool permissions editor.
 type toolPermDelegate struct {
 	theme          theme.Theme
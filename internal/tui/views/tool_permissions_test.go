@@ -771,3 +771,43 @@ func TestToolPermissions_BulkDenyAll_WithServerDefaults(t *testing.T) {
 		t.Error("srv2:get_time should be explicitly denied")
 	}
 }
+
+func TestToolPermissions_BulkAppliesAcrossNamespaces(t *testing.T) {
+	perms := newPermEditorWithTools(t)
+	perms.SetServerNamespaces(map[string][]string{"srv1": {"ns1", "ns2", "ns3"}})
+
+	// Select the first srv1 tool (items are grouped per server in map order)
+	for i, item := range perms.list.Items() {
+		if ti := item.(toolPermItem); !ti.isHeader && ti.serverID == "srv1" {
+			perms.list.Select(i)
+			break
+		}
+	}
+	tool := perms.list.SelectedItem().(toolPermItem).toolName
+
+	sendPermRune(&perms, 'b')
+	if !perms.bulkActive {
+		t.Fatal("expected bulk mode")
+	}
+	perms.Update(tea.KeyMsg{Type: tea.KeyDown})
+	sendPermRune(&perms, ' ')
+	sendPermRune(&perms, 'd')
+
+	if perms.bulkActive {
+		t.Error("expected bulk mode to close after applying")
+	}
+	if enabled, ok := perms.currentPerms["srv1:"+tool]; !ok || enabled {
+		t.Errorf("expected %s denied in the edited namespace", tool)
+	}
+	if enabled, ok := perms.otherChanges["ns2"]["srv1:"+tool]; !ok || enabled {
+		t.Errorf("expected %s denied in ns2, got %v", tool, perms.otherChanges)
+	}
+	if _, ok := perms.otherChanges["ns3"]; ok {
+		t.Error("expected ns3 to be unchanged")
+	}
+
+	result := perms.submitResult()().(ToolPermissionsResult)
+	if enabled, ok := result.OtherChanges["ns2"]["srv1:"+tool]; !ok || enabled {
+		t.Errorf("expected ns2 change in result, got %v", result.OtherChanges)
+	}
+}
@@ -0,0 +1,216 @@
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Bigsy/mcpmu/internal/mcp"
+	"github.com/Bigsy/mcpmu/internal/tui/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TraceViewerResult is sent when the trace viewer closes. Export is set when
+// the user asked to write the trace to a file.
+type TraceViewerResult struct {
+	ServerName string
+	Export     bool
+}
+
+// TraceViewerModel is an overlay listing the JSON-RPC frames captured for a
+// server, with the selected frame shown in full below the list.
+type TraceViewerModel struct {
+	theme      theme.Theme
+	visible    bool
+	serverName string
+	entries    []mcp.TraceEntry
+	selected   int
+	width      int
+	height     int
+
+	upKey     key.Binding
+	downKey   key.Binding
+	exportKey key.Binding
+	escKey    key.Binding
+}
+
+// NewTraceViewer creates a new trace viewer.
+func NewTraceViewer(th theme.Theme) TraceViewerModel {
+	return TraceViewerModel{
+		theme: th,
+		upKey: key.NewBinding(
+			key.WithKeys("up", "k"),
+		),
+		downKey: key.NewBinding(
+			key.WithKeys("down", "j"),
+		),
+		exportKey: key.NewBinding(
+			key.WithKeys("x"),
+		),
+		escKey: key.NewBinding(
+			key.WithKeys("esc", "v"),
+		),
+	}
+}
+
+// Show displays the viewer with a server's captured frames, selecting the
+// most recent one.
+func (m *TraceViewerModel) Show(serverName string, entries []mcp.TraceEntry) {
+	m.visible = true
+	m.serverName = serverName
+	m.entries = entries
+	m.selected = max(len(entries)-1, 0)
+}
+
+// Hide hides the trace viewer.
+func (m *TraceViewerModel) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the viewer is visible.
+func (m TraceViewerModel) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the available dimensions for centering.
+func (m *TraceViewerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Update handles key events for the trace viewer.
+func (m TraceViewerModel) Update(msg tea.Msg) (TraceViewerModel, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.upKey):
+			if m.selected > 0 {
+				m.selected--
+			}
+		case key.Matches(msg, m.downKey):
+			if m.selected < len(m.entries)-1 {
+				m.selected++
+			}
+		case key.Matches(msg, m.exportKey):
+			m.visible = false
+			name := m.serverName
+			return m, func() tea.Msg {
+				return TraceViewerResult{ServerName: name, Export: true}
+			}
+		case key.Matches(msg, m.escKey):
+			m.visible = false
+			name := m.serverName
+			return m, func() tea.Msg {
+				return TraceViewerResult{ServerName: name}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// RenderOverlay renders the viewer as a centered overlay on top of the base content.
+func (m TraceViewerModel) RenderOverlay(base string, width, height int) string {
+	if !m.visible {
+		return base
+	}
+
+	dialogWidth := max(min(width-4, 100), 40)
+	innerWidth := dialogWidth - 6
+	listRows := max(min(len(m.entries), (height-16)/2), 3)
+	frameRows := max(height-listRows-16, 3)
+
+	title := m.theme.Title.Render(fmt.Sprintf("Trace for %q (%d frames)", m.serverName, len(m.entries)))
+
+	var body strings.Builder
+	if len(m.entries) == 0 {
+		body.WriteString(m.theme.Faint.Render("  No frames captured yet") + "\n")
+	} else {
+		start := max(min(m.selected-listRows/2, len(m.entries)-listRows), 0)
+		end := min(start+listRows, len(m.entries))
+		for i := start; i < end; i++ {
+			line := truncateString(traceSummary(m.entries[i]), innerWidth-4)
+			if i == m.selected {
+				body.WriteString("  " + m.theme.Primary.Render("▸") + " " + m.theme.Primary.Bold(true).Render(line) + "\n")
+			} else {
+				body.WriteString("    " + m.theme.Base.Render(line) + "\n")
+			}
+		}
+
+		body.WriteString("\n")
+		lines := strings.Split(prettyFrame(m.entries[m.selected].Frame), "\n")
+		for i, line := range lines {
+			if i == frameRows {
+				body.WriteString(m.theme.Faint.Render(fmt.Sprintf("  … %d more lines (x to export)", len(lines)-frameRows)) + "\n")
+				break
+			}
+			body.WriteString(m.theme.Muted.Render("  "+truncateString(line, innerWidth-2)) + "\n")
+		}
+	}
+
+	footer := m.theme.Faint.Render("↑↓ select  x export  esc ×")
+
+	content := title + "\n\n" + body.String() + "\n" + footer
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary.GetForeground()).
+		Padding(1, 2).
+		Width(dialogWidth).
+		Render(content)
+
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.AdaptiveColor{Light: "#E5E7EB", Dark: "#1F2937"}),
+	)
+}
+
+// traceSummary renders one line describing a frame: time, direction, and
+// its method or the id it answers.
+func traceSummary(e mcp.TraceEntry) string {
+	arrow := "→"
+	if e.Direction == mcp.TraceRecv {
+		arrow = "←"
+	}
+
+	var env struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Error  json.RawMessage `json:"error"`
+	}
+	what := "frame"
+	if err := json.Unmarshal(e.Frame, &env); err == nil {
+		switch {
+		case env.Method != "" && len(env.ID) > 0:
+			what = fmt.Sprintf("%s (id %s)", env.Method, env.ID)
+		case env.Method != "":
+			what = env.Method
+		case len(env.Error) > 0:
+			what = fmt.Sprintf("error (id %s)", env.ID)
+		case len(env.ID) > 0:
+			what = fmt.Sprintf("result (id %s)", env.ID)
+		}
+	}
+	return fmt.Sprintf("%s %s %s", e.Time.Format("15:04:05.000"), arrow, what)
+}
+
+// prettyFrame indents a frame for display, falling back to the raw bytes.
+func prettyFrame(frame json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, frame, "", "  "); err != nil {
+		return string(frame)
+	}
+	return buf.String()
+}
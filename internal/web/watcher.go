@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
@@ -105,7 +107,11 @@ func (s *Server) WatchConfig(ctx context.Context) {
 			debounceTimer.Stop()
 		}
 		debounceTimer = time.AfterFunc(debounceDelay, func() {
-			newCfg, err := config.LoadFrom(s.configPath)
+			newCfg, err := config.LoadExisting(s.configPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				log.Printf("Config file %s is missing (keeping current config until it is recreated)", s.configPath)
+				return
+			}
 			if err != nil {
 				log.Printf("Failed to load config after change: %v (keeping current config)", err)
 				return